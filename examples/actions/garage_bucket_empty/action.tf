@@ -0,0 +1,26 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    s3 = "http://localhost:3900" # S3 API
+  }
+  access_key = "GK123..."     # S3 access key
+  secret_key = "secret123..." # S3 secret key
+}
+
+# Delete the objects under tmp/ of the staging-assets bucket, without
+# destroying the bucket. Requires Terraform 1.14 or later. Invoke it with:
+#
+#   terraform apply -invoke=action.garage_bucket_empty.staging_tmp
+action "garage_bucket_empty" "staging_tmp" {
+  config {
+    bucket = "staging-assets"
+    prefix = "tmp/"
+  }
+}
@@ -0,0 +1,28 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+# Look up a token by name, the name must be unique
+data "garage_admin_token" "ci" {
+  name = "ci"
+}
+
+# Look up a token by ID
+data "garage_admin_token" "by_id" {
+  id = "b7f9c7a1e2d34f5a6b7c8d9e"
+}
+
+output "ci_token_expiration" {
+  value = data.garage_admin_token.ci.expiration
+}
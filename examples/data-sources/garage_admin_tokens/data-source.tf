@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+data "garage_admin_tokens" "all" {}
+
+# Audit the tokens that can call every admin endpoint
+output "full_access_tokens" {
+  value = [for t in data.garage_admin_tokens.all.tokens : t.name if contains(t.scope, "*")]
+}
@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+    s3    = "http://localhost:3900" # S3 API
+  }
+  token      = "admin-token"
+  access_key = "GK123..."     # S3 access key
+  secret_key = "secret123..." # S3 secret key
+}
+
+# How much data lives under each top-level prefix
+data "garage_bucket_usage" "data" {
+  bucket   = "data"
+  prefixes = ["logs/", "artifacts/", "backups/"]
+
+  max_objects_scanned = 500000
+}
+
+output "usage_gib" {
+  value = {
+    for prefix, usage in data.garage_bucket_usage.data.usage :
+    prefix => usage.bytes / 1073741824
+  }
+}
@@ -0,0 +1,26 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+# No admin token: the website configuration is read through the S3 API
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+    s3    = "http://localhost:3900" # S3 API
+  }
+  access_key = "GK123..."     # S3 access key
+  secret_key = "secret123..." # S3 secret key
+}
+
+# Website set up by a deployment script
+data "garage_bucket_website" "site" {
+  bucket = "my-website"
+}
+
+output "index_document" {
+  value = data.garage_bucket_website.site.enabled ? data.garage_bucket_website.site.index_document : null
+}
@@ -0,0 +1,25 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+# Wait for the cluster to be back to healthy, e.g. after a node restart,
+# before checking it
+data "garage_cluster_health" "ready" {
+  wait_for_status = "healthy"
+  timeout         = "10m"
+}
+
+output "cluster_status" {
+  value = data.garage_cluster_health.ready.status
+}
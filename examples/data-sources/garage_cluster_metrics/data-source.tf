@@ -0,0 +1,28 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin   = "http://localhost:3903" # Admin API
+    metrics = "http://localhost:3903" # Serves /metrics, defaults to the admin API
+  }
+  token         = "admin-token"
+  metrics_token = "metrics-token"
+}
+
+data "garage_cluster_metrics" "health" {
+  metrics = ["cluster_healthy", "block_resync_queue_length"]
+}
+
+output "cluster_healthy" {
+  value = data.garage_cluster_metrics.health.values["cluster_healthy"] == 1
+}
+
+output "resync_queue_length" {
+  value = data.garage_cluster_metrics.health.values["block_resync_queue_length"]
+}
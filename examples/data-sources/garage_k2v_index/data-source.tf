@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+    k2v   = "http://localhost:3904" # K2V API
+  }
+  token      = "admin-token"
+  access_key = "GK123..."
+  secret_key = "secret123..."
+}
+
+data "garage_k2v_index" "nodes" {
+  bucket = "cluster-metadata"
+  prefix = "node/"
+  limit  = 5000
+}
+
+output "node_partitions" {
+  value = data.garage_k2v_index.nodes.partition_keys[*].partition_key
+}
+
+# Partitions holding items with concurrent values
+output "conflicting_partitions" {
+  value = [for p in data.garage_k2v_index.nodes.partition_keys : p.partition_key if p.conflicts > 0]
+}
@@ -0,0 +1,24 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+# Find the CI keys that have expired, to clean them up
+data "garage_keys" "expired_ci" {
+  name_prefix  = "ci-"
+  expired_only = true
+}
+
+output "expired_ci_keys" {
+  value = data.garage_keys.expired_ci.keys[*].id
+}
@@ -0,0 +1,22 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+data "garage_node" "storage" {
+  id = "563e1ac825ee3323aa441e72c26d1030d6d4414aeb3dd25287c531e7fc2bc95d"
+}
+
+output "storage_data_available_bytes" {
+  value = data.garage_node.storage.data_partition.available
+}
@@ -0,0 +1,30 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+data "garage_nodes" "storage" {
+  role = "storage"
+  up   = true
+}
+
+# One report per storage node
+data "garage_node_statistics" "node" {
+  for_each = { for n in data.garage_nodes.storage.nodes : n.hostname => n.id }
+
+  node_id = each.value
+}
+
+output "resync_queues" {
+  value = { for name, s in data.garage_node_statistics.node : name => s.resync_queue_length }
+}
@@ -0,0 +1,30 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+data "garage_nodes" "all" {}
+
+# Nodes whose data partition is more than 80% full. Nodes that are down do
+# not report their partitions.
+output "nodes_over_80_percent" {
+  value = [for n in data.garage_nodes.all.nodes : n.hostname if coalesce(n.data_used_percent, 0) > 80]
+}
+
+# Storage nodes of zone dc2 with the ssd tag that are currently up
+data "garage_nodes" "dc2_ssd" {
+  zone = "dc2"
+  tags = ["ssd"]
+  role = "storage"
+  up   = true
+}
@@ -49,8 +49,51 @@ data "garage_object" "image" {
   key    = "logo.png"
 }
 
-# Write binary file (requires local_file resource with content_base64)
+# Write binary file, body is null for content that is not valid UTF-8
 resource "local_file" "image" {
-  content_base64 = base64encode(data.garage_object.image.body)
+  content_base64 = data.garage_object.image.body_base64
   filename       = "${path.module}/logo.png"
 }
+
+# Create a default configuration only when none exists yet
+data "garage_object" "settings" {
+  bucket        = "my-bucket"
+  key           = "settings.json"
+  allow_missing = true
+}
+
+resource "garage_object" "default_settings" {
+  count = data.garage_object.settings.exists ? 0 : 1
+
+  bucket  = "my-bucket"
+  key     = "settings.json"
+  content = jsonencode({ theme = "light" })
+}
+
+# Stream a bootstrap binary to disk without keeping it in state
+data "garage_object" "agent" {
+  bucket          = "artifacts"
+  key             = "bootstrap/agent"
+  output_path     = "${path.module}/.build/agent"
+  file_permission = "0755"
+  overwrite       = true
+}
+
+resource "terraform_data" "install_agent" {
+  triggers_replace = [data.garage_object.agent.output_sha256]
+
+  provisioner "local-exec" {
+    command = data.garage_object.agent.output_path
+  }
+}
+
+# Read a JSON document uploaded with Content-Encoding: gzip
+data "garage_object" "manifest" {
+  bucket     = "artifacts"
+  key        = "manifest.json"
+  decompress = true
+}
+
+output "manifest_version" {
+  value = jsondecode(data.garage_object.manifest.body).version
+}
@@ -0,0 +1,49 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+    s3    = "http://localhost:3900" # S3 API
+  }
+  token      = "admin-token"
+  access_key = "GK123..."     # S3 access key
+  secret_key = "secret123..." # S3 secret key
+}
+
+# Read the metadata of a large artifact without downloading it
+data "garage_object_head" "release" {
+  bucket = "artifacts"
+  key    = "releases/app.tar.gz"
+}
+
+output "release_info" {
+  value = {
+    size     = data.garage_object_head.release.content_length
+    etag     = data.garage_object_head.release.etag
+    modified = data.garage_object_head.release.last_modified
+    metadata = data.garage_object_head.release.metadata
+  }
+}
+
+# Check for an optional object instead of failing when it is missing
+data "garage_object_head" "maintenance" {
+  bucket        = "website"
+  key           = "maintenance.html"
+  allow_missing = true
+}
+
+output "maintenance_mode" {
+  value = data.garage_object_head.maintenance.exists
+}
+
+# Compare the remote artifact with a local build without downloading it.
+# Checksums are only known when Garage reports them (checksum_source = "server").
+output "release_up_to_date" {
+  value = data.garage_object_head.release.checksum_sha256 == filesha256("${path.module}/app.tar.gz")
+}
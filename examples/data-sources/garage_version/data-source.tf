@@ -0,0 +1,28 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+data "garage_version" "server" {}
+
+output "garage_version" {
+  value = data.garage_version.server.version
+}
+
+# Fail the run early when the server was built without K2V support
+check "k2v_enabled" {
+  assert {
+    condition     = contains(data.garage_version.server.features, "k2v")
+    error_message = "Garage ${data.garage_version.server.version} was built without the k2v feature."
+  }
+}
@@ -0,0 +1,37 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+# Mint a token that may only read bucket information, for the duration of the
+# run. It is deleted at the end of the run and expires after ttl in case it
+# cannot be. Requires Terraform 1.10 and Garage v2.0 or later.
+ephemeral "garage_admin_token" "read_only" {
+  name  = "terraform-read-only"
+  scope = ["ListBuckets", "GetBucketInfo"]
+  ttl   = "30m"
+}
+
+# Provider configurations accept ephemeral values
+provider "garage" {
+  alias = "read_only"
+  endpoints = {
+    admin = "http://localhost:3903"
+  }
+  token = ephemeral.garage_admin_token.read_only.secret
+}
+
+data "garage_bucket" "assets" {
+  provider     = garage.read_only
+  global_alias = "assets"
+}
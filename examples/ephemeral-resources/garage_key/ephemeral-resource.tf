@@ -0,0 +1,39 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+    s3    = "http://localhost:3900" # S3 API
+  }
+  token = "admin-token"
+}
+
+data "garage_bucket" "assets" {
+  global_alias = "assets"
+}
+
+# Mint a key that may only read the assets bucket, for the duration of the
+# run. It is deleted at the end of the run and expires after ttl in case it
+# cannot be; runs that last longer extend it. Requires Terraform 1.10 or later.
+ephemeral "garage_key" "reader" {
+  name      = "terraform-reader"
+  ttl       = "30m"
+  bucket_id = data.garage_bucket.assets.id
+  read      = true
+}
+
+# Provider configurations accept ephemeral values
+provider "garage" {
+  alias = "reader"
+  endpoints = {
+    s3 = "http://localhost:3900"
+  }
+  access_key = ephemeral.garage_key.reader.access_key_id
+  secret_key = ephemeral.garage_key.reader.secret_access_key
+}
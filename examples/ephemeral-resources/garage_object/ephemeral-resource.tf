@@ -0,0 +1,42 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+    s3    = "http://localhost:3900" # S3 API
+  }
+  token      = "admin-token"
+  access_key = "GK123..."     # S3 access key
+  secret_key = "secret123..." # S3 secret key
+}
+
+# Read database credentials stored in Garage without writing them to state.
+# Requires Terraform 1.10 or later.
+ephemeral "garage_object" "db_credentials" {
+  bucket = "secrets"
+  key    = "db/credentials.json"
+}
+
+provider "postgresql" {
+  host     = "db.example.com"
+  username = jsondecode(ephemeral.garage_object.db_credentials.body).username
+  password = jsondecode(ephemeral.garage_object.db_credentials.body).password
+}
+
+# Objects encrypted with SSE-C need the same key to be read
+variable "sse_key" {
+  type      = string
+  sensitive = true
+}
+
+ephemeral "garage_object" "tls_key" {
+  bucket           = "secrets"
+  key              = "tls/server.key"
+  sse_customer_key = var.sse_key
+}
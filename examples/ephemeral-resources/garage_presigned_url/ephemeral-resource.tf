@@ -0,0 +1,38 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+    s3    = "http://localhost:3900" # S3 API
+  }
+  token      = "admin-token"
+  access_key = "GK123..."     # S3 access key
+  secret_key = "secret123..." # S3 secret key
+}
+
+# Short-lived download URL for a private installer, e.g. for a cloud-init
+# script. Requires Terraform 1.10 or later.
+ephemeral "garage_presigned_url" "installer" {
+  bucket     = "artifacts"
+  key        = "releases/agent.tar.gz"
+  expires_in = "30m"
+}
+
+# One-shot upload URL for a build agent, which never holds the S3 secret.
+# The upload must be sent with the returned headers.
+ephemeral "garage_presigned_url" "upload" {
+  bucket         = "artifacts"
+  key            = "builds/latest.tar.gz"
+  method         = "PUT"
+  expires_in     = "1h"
+  content_type   = "application/gzip"
+  content_length = 1048576
+}
+
+# e.g. curl -X PUT --upload-file latest.tar.gz -H "Content-Type: application/gzip" "<url>"
@@ -0,0 +1,40 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+    s3    = "http://localhost:3900" # S3 API
+  }
+  token      = "admin-token"
+  access_key = "GK123..."     # S3 access key
+  secret_key = "secret123..." # S3 secret key
+
+  content_type_overrides = local.content_types
+}
+
+locals {
+  site_dir = "${path.module}/site"
+
+  # Extensions missing from the built-in table
+  content_types = {
+    ".glb"  = "model/gltf-binary"
+    ".gltf" = "model/gltf+json"
+  }
+}
+
+# Set content_type explicitly, e.g. to add a cache policy per type
+resource "garage_object" "site" {
+  for_each = fileset(local.site_dir, "**")
+
+  bucket        = "my-website"
+  key           = each.value
+  source        = "${local.site_dir}/${each.value}"
+  content_type  = provider::garage::content_type(each.value, local.content_types)
+  cache_control = startswith(provider::garage::content_type(each.value, local.content_types), "font/") ? "public, max-age=31536000, immutable" : "no-cache"
+}
@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+    time = {
+      source = "hashicorp/time"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+# time_static keeps the start of the validity in state, so the expiration
+# does not move on every plan. Replace it to renew the token.
+resource "time_static" "ci_token" {}
+
+resource "garage_admin_token" "ci" {
+  name       = "ci"
+  scope      = ["ListBuckets", "GetBucketInfo"]
+  expiration = provider::garage::expires_at("90d", time_static.ci_token.rfc3339)
+}
+
+# plantimestamp() is stable within a run, but changes on every plan
+output "expires_if_created_now" {
+  value = provider::garage::expires_at("1d12h", plantimestamp())
+}
@@ -0,0 +1,37 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+    s3    = "http://localhost:3900" # S3 API
+  }
+  token      = "admin-token"
+  access_key = "GK123..."     # S3 access key
+  secret_key = "secret123..." # S3 secret key
+}
+
+data "garage_object_head" "release" {
+  bucket = "releases"
+  key    = "app-1.2.0.tar.gz"
+}
+
+# The release was uploaded by CI in parts of 8 MiB
+check "release_up_to_date" {
+  assert {
+    condition = data.garage_object_head.release.etag == provider::garage::file_etag(
+      "${path.module}/dist/app-1.2.0.tar.gz",
+      provider::garage::parse_size("8MiB"),
+    )
+    error_message = "The uploaded release differs from the local build."
+  }
+}
+
+output "index_etag" {
+  value = provider::garage::file_etag("${path.module}/site/index.html")
+}
@@ -0,0 +1,26 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+data "garage_bucket" "backups" {
+  global_alias = "backups"
+}
+
+output "backups_quota" {
+  value = "backups: ${provider::garage::format_size(data.garage_bucket.backups.max_size)} max" # "backups: 50.0 GiB max"
+}
+
+output "rounded" {
+  value = provider::garage::format_size(1610612736, 0) # "2 GiB"
+}
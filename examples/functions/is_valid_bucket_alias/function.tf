@@ -0,0 +1,27 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+variable "team" {
+  type = string
+
+  validation {
+    condition     = provider::garage::is_valid_bucket_alias("${var.team}-assets")
+    error_message = "The team name must make a valid bucket alias: lowercase letters, digits and dashes."
+  }
+}
+
+resource "garage_bucket" "assets" {
+  global_alias = "${var.team}-assets"
+}
@@ -0,0 +1,31 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+    s3    = "http://localhost:3900" # S3 API
+  }
+  token      = "admin-token"
+  access_key = "GK123..."     # S3 access key
+  secret_key = "secret123..." # S3 secret key
+}
+
+locals {
+  site_dir = "${path.module}/../shared/site"
+  files    = fileset(local.site_dir, "**")
+}
+
+# Keys such as "v2/css/style.css", with forward slashes on every OS
+resource "garage_object" "site" {
+  for_each = { for file in local.files : provider::garage::key_from_path("${local.site_dir}/${file}", local.site_dir, "v2/") => file }
+
+  bucket = "my-website"
+  key    = each.key
+  source = "${local.site_dir}/${each.value}"
+}
@@ -0,0 +1,33 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+locals {
+  assets = toset(["index.html", "css/site.css", "js/app.js"])
+}
+
+# Import existing objects with the IDs the provider expects
+import {
+  for_each = local.assets
+  to       = garage_object.assets[each.key]
+  id       = provider::garage::object_id("assets", each.key)
+}
+
+resource "garage_object" "assets" {
+  for_each = local.assets
+
+  bucket = "assets"
+  key    = each.key
+  source = "${path.module}/site/${each.key}"
+}
@@ -0,0 +1,24 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+variable "token_lifetime" {
+  description = "How long CI tokens stay valid, e.g. 90d or 1d12h"
+  type        = string
+  default     = "90d"
+}
+
+output "token_lifetime_seconds" {
+  value = provider::garage::parse_duration(var.token_lifetime) # 7776000
+}
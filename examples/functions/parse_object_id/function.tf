@@ -0,0 +1,24 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+variable "object_ids" {
+  type    = list(string)
+  default = ["assets/index.html", "assets/css/site.css"]
+}
+
+# Keys keep their slashes: {bucket = "assets", key = "css/site.css"}
+output "objects" {
+  value = [for id in var.object_ids : provider::garage::parse_object_id(id)]
+}
@@ -0,0 +1,23 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+resource "garage_bucket" "backups" {
+  global_alias = "backups"
+  max_size     = provider::garage::parse_size("50GiB")
+}
+
+output "half_a_terabyte" {
+  value = provider::garage::parse_size("0.5TB") # 500000000000
+}
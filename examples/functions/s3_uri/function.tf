@@ -0,0 +1,27 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+resource "garage_bucket" "backups" {
+  global_alias = "backups"
+}
+
+# Feed external tools, e.g. `rclone copy dump.sql.gz garage:$(terraform output -raw backup_prefix)`
+output "backup_prefix" {
+  value = provider::garage::s3_uri(garage_bucket.backups.global_alias, "db/")
+}
+
+output "latest_dump" {
+  value = provider::garage::s3_uri("backups", "db/latest.sql.gz") # s3://backups/db/latest.sql.gz
+}
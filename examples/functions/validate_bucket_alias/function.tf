@@ -0,0 +1,31 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+variable "environments" {
+  type    = list(string)
+  default = ["staging", "production"]
+}
+
+# Fails at plan time with the rule an alias breaks, e.g.
+# invalid bucket alias "Staging-backups": alias contains 'S', ...
+locals {
+  backup_aliases = [for env in var.environments : provider::garage::validate_bucket_alias("${env}-backups")]
+}
+
+resource "garage_bucket" "backups" {
+  for_each = toset(local.backup_aliases)
+
+  global_alias = each.key
+}
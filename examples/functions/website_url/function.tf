@@ -0,0 +1,35 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+variable "web_root_domain" {
+  description = "root_domain of the [s3_web] section of the Garage configuration"
+  type        = string
+  default     = ".web.example.com"
+}
+
+resource "garage_bucket" "docs" {
+  global_alias           = "docs"
+  website_enabled        = true
+  website_index_document = "index.html"
+}
+
+output "docs_url" {
+  value = provider::garage::website_url(garage_bucket.docs.global_alias, var.web_root_domain)
+}
+
+# https://docs.web.example.com/guides/getting%20started.html
+output "getting_started_url" {
+  value = provider::garage::website_url("docs", var.web_root_domain, "guides/getting started.html")
+}
@@ -2,6 +2,7 @@ provider "garage" {
   endpoints = {
     admin = "http://localhost:3903" # Admin API
     s3    = "http://localhost:3900" # S3 API
+    k2v   = "http://localhost:3904" # K2V API, optional
   }
   token      = "admin-token"
   access_key = "GK123..."     # S3 access key
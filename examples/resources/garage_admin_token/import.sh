@@ -0,0 +1,5 @@
+#!/bin/bash
+
+# Admin tokens can be imported using their ID. The secret is not returned by
+# the admin API after creation and stays empty.
+terraform import garage_admin_token.monitoring 2f6d9fa1d3a4f0c4e8d6a3b3
@@ -0,0 +1,27 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+# A token for a monitoring system, widened or narrowed in place without
+# rotating its secret
+resource "garage_admin_token" "monitoring" {
+  name       = "monitoring"
+  scope      = ["Metrics", "GetClusterHealth", "GetClusterStatus"]
+  expiration = "2030-01-01T00:00:00Z"
+}
+
+output "monitoring_token" {
+  value     = garage_admin_token.monitoring.secret
+  sensitive = true
+}
@@ -1,2 +1,3 @@
-# Garage buckets can be imported using the bucket ID
+# Garage buckets can be imported using the bucket ID or its global alias
 terraform import garage_bucket.example bucket-id-here
+terraform import garage_bucket.example my-bucket
@@ -0,0 +1,4 @@
+#!/bin/bash
+
+# Bucket CORS configurations can be imported using the bucket name
+terraform import garage_bucket_cors.assets assets
@@ -0,0 +1,40 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+    s3    = "http://localhost:3900" # S3 API
+  }
+  token      = "admin-token"
+  access_key = "GK123..."     # S3 access key
+  secret_key = "secret123..." # S3 secret key
+}
+
+resource "garage_bucket" "assets" {
+  global_alias = "assets"
+}
+
+# Let the web app upload directly from the browser and anyone read
+resource "garage_bucket_cors" "assets" {
+  bucket = garage_bucket.assets.global_alias
+
+  rule = [
+    {
+      allowed_origins = ["https://app.example.com"]
+      allowed_methods = ["PUT", "POST"]
+      allowed_headers = ["*"]
+      expose_headers  = ["ETag"]
+      max_age_seconds = 3600
+    },
+    {
+      allowed_origins = ["*"]
+      allowed_methods = ["GET", "HEAD"]
+    },
+  ]
+}
@@ -0,0 +1,4 @@
+#!/bin/bash
+
+# Bucket lifecycle configurations can be imported using the bucket name
+terraform import garage_bucket_lifecycle.scratch scratch
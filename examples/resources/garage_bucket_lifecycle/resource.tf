@@ -0,0 +1,35 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+    s3    = "http://localhost:3900" # S3 API
+  }
+  token      = "admin-token"
+  access_key = "GK123..."     # S3 access key
+  secret_key = "secret123..." # S3 secret key
+}
+
+# Expire temporary objects after a week and abort uploads left behind by
+# crashed jobs after a day
+resource "garage_bucket_lifecycle" "scratch" {
+  bucket = "scratch"
+
+  rule = [
+    {
+      id              = "expire-tmp"
+      prefix          = "tmp/"
+      expiration_days = 7
+    },
+    {
+      id                                     = "abort-uploads"
+      abort_incomplete_multipart_upload_days = 1
+    },
+  ]
+}
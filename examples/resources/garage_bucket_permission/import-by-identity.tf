@@ -0,0 +1,7 @@
+import {
+  to = garage_bucket_permission.example
+  identity = {
+    bucket_id     = "bucket-id"
+    access_key_id = "GK31c2f218a2e44f485b94239e"
+  }
+}
@@ -1,4 +1,4 @@
 #!/bin/bash
 
-# Garage bucket permissions can be imported using the format: bucket_id/access_key_id
+# Garage bucket permissions can be imported using their ID, which has the format: <bucket_id>/<access_key_id>
 terraform import garage_bucket_permission.example bucket-id/access-key-id
@@ -30,18 +30,24 @@ resource "garage_key" "example" {
 resource "garage_bucket_permission" "example" {
   bucket_id     = garage_bucket.example.id
   access_key_id = garage_key.example.id
-  read          = true
-  write         = true
-  owner         = false
+
+  permissions = {
+    read  = true
+    write = true
+    owner = false
+  }
 }
 
 # Example: Read-only access
 resource "garage_bucket_permission" "readonly" {
   bucket_id     = garage_bucket.example.id
   access_key_id = garage_key.readonly.id
-  read          = true
-  write         = false
-  owner         = false
+
+  permissions = {
+    read  = true
+    write = false
+    owner = false
+  }
 }
 
 resource "garage_key" "readonly" {
@@ -56,7 +62,10 @@ resource "garage_key" "admin" {
 resource "garage_bucket_permission" "admin" {
   bucket_id     = garage_bucket.example.id
   access_key_id = garage_key.admin.id
-  read          = true
-  write         = true
-  owner         = true
+
+  permissions = {
+    read  = true
+    write = true
+    owner = true
+  }
 }
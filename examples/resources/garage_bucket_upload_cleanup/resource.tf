@@ -0,0 +1,24 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+    s3    = "http://localhost:3900" # S3 API
+  }
+  token      = "admin-token"
+  access_key = "GK123..."     # S3 access key
+  secret_key = "secret123..." # S3 secret key
+}
+
+# Abort the multipart uploads of crashed jobs on every apply, leaving the
+# ones started in the last three days alone
+resource "garage_bucket_upload_cleanup" "artifacts" {
+  bucket     = "artifacts"
+  older_than = "72h"
+}
@@ -0,0 +1,4 @@
+#!/bin/bash
+
+# Bucket website configurations can be imported using the bucket name
+terraform import garage_bucket_website.docs docs
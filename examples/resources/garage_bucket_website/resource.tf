@@ -0,0 +1,25 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+    s3    = "http://localhost:3900" # S3 API
+  }
+  token      = "admin-token"
+  access_key = "GK123..."     # S3 access key
+  secret_key = "secret123..." # S3 secret key
+}
+
+# Serve a bucket created by other tooling as a website. The provider S3
+# access key needs owner permission on the bucket.
+resource "garage_bucket_website" "docs" {
+  bucket         = "docs"
+  index_document = "index.html"
+  error_document = "404.html"
+}
@@ -0,0 +1,5 @@
+#!/bin/bash
+
+# The layout of a cluster can be imported using the fixed ID "cluster", to
+# adopt an existing cluster without staging its roles again.
+terraform import garage_cluster_layout.main cluster
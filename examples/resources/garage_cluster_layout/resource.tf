@@ -0,0 +1,52 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+# The complete layout of the cluster: nodes not listed are removed from it
+resource "garage_cluster_layout" "main" {
+  node = [
+    {
+      id       = "563e1ac825ee3323aa441e72c26d1030d6d4414aeb3dd25287c531e7fc2bc95d"
+      zone     = "dc1"
+      capacity = 1000000000000 # 1 TB
+    },
+    {
+      id       = "86f0f26ae4afbd59aaf9cfb059eefac844951efd5b8caeec0d53f4ed6c85f332"
+      zone     = "dc2"
+      capacity = 1000000000000
+      tags     = ["ssd"]
+    },
+    # Gateways serve S3 requests without storing data
+    {
+      id      = "9a4d25c1f47f07e0bb3b7c8c1e7c3e3ab2d5de5a4a1f6ff03b6e4a2bb5c7d120"
+      zone    = "dc1"
+      gateway = true
+    },
+  ]
+
+  # Keep a copy of each partition in at least 2 zones, or "maximum" to use
+  # as many zones as possible
+  parameters = {
+    zone_redundancy = "2"
+  }
+
+  # Set to false to only stage the changes and review them with
+  # `garage layout show` before applying them with `garage layout apply`
+  auto_apply = true
+
+  # Required for any change removing nodes from the layout; the apply then
+  # waits for every partition to be back to quorum
+  allow_decommission = false
+  wait_for_rebalance = "30m"
+}
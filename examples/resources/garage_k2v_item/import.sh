@@ -0,0 +1,4 @@
+#!/bin/bash
+
+# K2V items can be imported using bucket/partition_key/sort_key
+terraform import garage_k2v_item.environment cluster-metadata/cluster/environment
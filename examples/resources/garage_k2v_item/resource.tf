@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+    k2v   = "http://localhost:3904" # K2V API
+  }
+  token      = "admin-token"
+  access_key = "GK123..."
+  secret_key = "secret123..."
+}
+
+resource "garage_k2v_item" "environment" {
+  bucket        = "cluster-metadata"
+  partition_key = "cluster"
+  sort_key      = "environment"
+  value         = "production"
+}
+
+# Binary values are base64-encoded
+resource "garage_k2v_item" "ca" {
+  bucket        = "cluster-metadata"
+  partition_key = "cluster"
+  sort_key      = "ca.der"
+  value_base64  = filebase64("${path.module}/ca.der")
+}
@@ -0,0 +1,4 @@
+#!/bin/bash
+
+# The grants of a key can be imported using the access key ID
+terraform import garage_key_grants.example GK31c2f218a2e44f485b94239e
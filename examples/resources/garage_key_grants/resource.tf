@@ -0,0 +1,46 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+    s3    = "http://localhost:3900" # S3 API
+  }
+  token = "admin-token"
+}
+
+resource "garage_bucket" "assets" {
+  global_alias = "assets"
+}
+
+resource "garage_bucket" "uploads" {
+  global_alias = "uploads"
+}
+
+# Each application key declares exactly the buckets it may access.
+# Grants on any other bucket are revoked on the next apply.
+resource "garage_key" "app" {
+  name = "my-app-key"
+}
+
+resource "garage_key_grants" "app" {
+  access_key_id = garage_key.app.id
+
+  grant = [
+    {
+      bucket_id = garage_bucket.assets.id
+      read      = true
+    },
+    {
+      # Buckets can also be referenced by global alias
+      bucket_alias = garage_bucket.uploads.global_alias
+      read         = true
+      write        = true
+    },
+  ]
+}
@@ -0,0 +1,27 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+# Connect the first node to the rest of the cluster.
+# Node IDs are printed by `garage node id` on each node.
+resource "garage_node_connect" "cluster" {
+  peers = [
+    "563e1ac825ee3323aa441e72c26d1030d6d4414aeb3dd25287c531e7fc2bc95d@10.0.0.2:3901",
+    "86f0f26ae4afbd59aaf9cfb059eefac844951efd5b8caeec0d53f4ed6c85f332@10.0.0.3:3901",
+  ]
+
+  # Retry the peers while they are still booting
+  wait_for_connection = true
+  connection_timeout  = "10m"
+}
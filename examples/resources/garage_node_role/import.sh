@@ -0,0 +1,2 @@
+# Node roles can be imported using the full node ID
+terraform import garage_node_role.storage 563e1ac825ee3323aa441e72c26d1030d6d4414aeb3dd25287c531e7fc2bc95d
@@ -0,0 +1,29 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+# Each node can be added by the configuration that provisions it. Do not
+# manage the same cluster with garage_cluster_layout.
+resource "garage_node_role" "storage" {
+  node_id  = "563e1ac825ee3323aa441e72c26d1030d6d4414aeb3dd25287c531e7fc2bc95d"
+  zone     = "dc1"
+  capacity = 1000000000000 # 1 TB
+  tags     = ["ssd"]
+}
+
+resource "garage_node_role" "gateway" {
+  node_id = "9a4d25c1f47f07e0bb3b7c8c1e7c3e3ab2d5de5a4a1f6ff03b6e4a2bb5c7d120"
+  zone    = "dc1"
+  gateway = true
+}
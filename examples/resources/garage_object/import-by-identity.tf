@@ -0,0 +1,7 @@
+import {
+  to = garage_object.example
+  identity = {
+    bucket = "my-bucket"
+    key    = "path/to/file.txt"
+  }
+}
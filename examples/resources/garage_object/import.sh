@@ -0,0 +1,7 @@
+#!/bin/bash
+
+# Garage objects can be imported using their ID, which has the format: <bucket>/<key>
+terraform import garage_object.example my-bucket/path/to/file.txt
+
+# The key is given as is, not URL-encoded, spaces and unicode included
+terraform import garage_object.report 'my-bucket/reports/2024 Q3/résumé.pdf'
@@ -30,3 +30,43 @@ resource "garage_object" "file_example" {
   key    = "data.json"
   source = "${path.module}/data.json"
 }
+
+# Pre-compressed asset served as a download
+resource "garage_object" "download_example" {
+  bucket              = garage_bucket.example.id
+  key                 = "report.csv.gz"
+  source              = "${path.module}/report.csv.gz"
+  content_type        = "text/csv"
+  content_encoding    = "gzip"
+  content_disposition = "attachment; filename=\"report.csv\""
+  content_language    = "en-US"
+  cache_control       = "max-age=86400"
+
+  metadata = {
+    generated-by = "nightly-export"
+  }
+}
+
+# Zero-byte placeholder object
+resource "garage_object" "folder_example" {
+  bucket  = garage_bucket.example.id
+  key     = "uploads/"
+  content = ""
+}
+
+# Content downloaded from a remote URL, verified before upload
+resource "garage_object" "url_example" {
+  bucket              = garage_bucket.example.id
+  key                 = "releases/app-1.2.3.tar.gz"
+  source_url          = "https://artifacts.example.com/app-1.2.3.tar.gz"
+  source_url_checksum = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+}
+
+# Large generated content kept out of the state: only its SHA-256 is stored,
+# and it is uploaded again whenever the hash changes
+resource "garage_object" "bundle_example" {
+  bucket          = garage_bucket.example.id
+  key             = "assets/bundle.js"
+  content_wo      = file("${path.module}/dist/bundle.js")
+  content_storage = "hash"
+}
@@ -0,0 +1,38 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+    s3    = "http://localhost:3900" # S3 API
+  }
+  token      = "admin-token"
+  access_key = "GK123..."     # S3 access key
+  secret_key = "secret123..." # S3 secret key
+}
+
+resource "garage_bucket" "website" {
+  global_alias           = "website"
+  website_enabled        = true
+  website_index_document = "index.html"
+}
+
+# Deploy a static site build, deleting the files that are no longer part of it
+resource "garage_object_directory" "website" {
+  bucket     = garage_bucket.website.global_alias
+  source_dir = "${path.module}/dist"
+  exclude    = ["*.map", ".DS_Store"]
+}
+
+# Upload release artifacts under a prefix without ever deleting old ones
+resource "garage_object_directory" "releases" {
+  bucket         = "artifacts"
+  source_dir     = "${path.module}/build/releases"
+  key_prefix     = "releases/"
+  delete_removed = false
+}
@@ -0,0 +1,35 @@
+terraform {
+  required_providers {
+    garage = {
+      source = "darkmukke/garage"
+    }
+  }
+}
+
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903" # Admin API
+  }
+  token = "admin-token"
+}
+
+data "garage_nodes" "all" {}
+
+# Check every block after replacing a disk, from the same run that changed the
+# layout. This launches work on the cluster, it does not manage any state.
+resource "garage_repair" "blocks" {
+  operation = "blocks"
+  wait      = true
+  timeout   = "2h"
+
+  # Run again whenever the layout changes
+  triggers = {
+    layout_version = data.garage_nodes.all.layout_version
+  }
+}
+
+# Scrub a single node without waiting for the scrub to complete
+resource "garage_repair" "scrub" {
+  operation = "scrub"
+  node      = "563e1ac825ee3323aa441e72c26d1030d6d4414aeb3dd25287c531e7fc2bc95d"
+}
@@ -7,17 +7,76 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 )
 
+// ErrUnsupportedEndpoint is returned when the server does not implement an
+// admin API endpoint, typically because it predates it.
+var ErrUnsupportedEndpoint = errors.New("endpoint not supported by this Garage server")
+
+// APIError is returned when the Garage API answers a request with an
+// unexpected status.
+type APIError struct {
+	Method string
+	// Path is the admin API path of the request, e.g.
+	// /v2/GetBucketInfo?id=abc, without the path of the endpoint.
+	Path       string
+	StatusCode int
+	// Code and Message are those of the JSON error body of Garage, empty
+	// when the body is not one.
+	Code    string
+	Message string
+	// Body is the raw response body.
+	Body string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// AuthError is returned when the admin API rejects the token of the client
+// with 401 Unauthorized or 403 Forbidden.
+type AuthError struct {
+	*APIError
+	// Repeated is true when the request was not sent because the same
+	// endpoint already rejected the token earlier in the lifetime of the
+	// client.
+	Repeated bool
+}
+
+func (e *AuthError) Error() string {
+	return "admin token rejected: " + e.APIError.Error()
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.APIError
+}
+
 // Client is a Garage API client.
 type Client struct {
 	endpoint   string
 	token      string
 	httpClient *http.Client
+
+	// nodeInfo caches the server reported by GetNodeInfo, which does not
+	// change during the lifetime of a client.
+	nodeInfoMu sync.Mutex
+	nodeInfo   *NodeInfo
+
+	// rejected remembers the AuthError of each endpoint that rejected the
+	// token, so that resources sharing the client do not all send requests
+	// bound to fail.
+	rejectedMu sync.Mutex
+	rejected   map[string]*AuthError
 }
 
 // NewClient creates a new Garage API client.
@@ -43,9 +102,10 @@ type Bucket struct {
 }
 
 // WebsiteConfig represents website configuration for a bucket.
+// The error document is null when none is configured.
 type WebsiteConfig struct {
-	IndexDocument string `json:"indexDocument"`
-	ErrorDocument string `json:"errorDocument"`
+	IndexDocument string  `json:"indexDocument"`
+	ErrorDocument *string `json:"errorDocument"`
 }
 
 // BucketKeyInfo represents key permissions on a bucket.
@@ -117,6 +177,15 @@ type AccessKey struct {
 	Buckets         []KeyBucketInfo `json:"buckets"`
 }
 
+// KeyListItem represents an access key in the list of keys.
+type KeyListItem struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Created    *string `json:"created,omitempty"`
+	Expiration *string `json:"expiration,omitempty"`
+	Expired    bool    `json:"expired"`
+}
+
 // KeyPermissions represents the permissions a key has.
 type KeyPermissions struct {
 	CreateBucket bool `json:"createBucket"`
@@ -148,11 +217,302 @@ type DeleteKeyRequest struct {
 	ID string `json:"id"`
 }
 
+// UpdateKeyRequest represents the request to update an access key. Fields
+// left empty are unchanged; NeverExpires removes the expiration.
+type UpdateKeyRequest struct {
+	Name         *string `json:"name,omitempty"`
+	Expiration   *string `json:"expiration,omitempty"`
+	NeverExpires bool    `json:"neverExpires,omitempty"`
+}
+
 // GetKeyInfoRequest represents the request to get key info.
 type GetKeyInfoRequest struct {
 	ID string `json:"id"`
 }
 
+// ConnectNodeResult is the outcome of connecting to one node.
+type ConnectNodeResult struct {
+	Success bool    `json:"success"`
+	Error   *string `json:"error"`
+}
+
+// ClusterHealth represents the health of the cluster: whether its storage
+// nodes are up and its partitions have a quorum of copies available.
+type ClusterHealth struct {
+	Status           string `json:"status"`
+	KnownNodes       int64  `json:"knownNodes"`
+	ConnectedNodes   int64  `json:"connectedNodes"`
+	StorageNodes     int64  `json:"storageNodes"`
+	StorageNodesUp   int64  `json:"storageNodesUp"`
+	Partitions       int64  `json:"partitions"`
+	PartitionsQuorum int64  `json:"partitionsQuorum"`
+	PartitionsAllOk  int64  `json:"partitionsAllOk"`
+}
+
+// NodeInfo represents the Garage server answering the admin API.
+type NodeInfo struct {
+	NodeID         string   `json:"nodeId"`
+	GarageVersion  string   `json:"garageVersion"`
+	GarageFeatures []string `json:"garageFeatures"`
+	RustVersion    string   `json:"rustVersion"`
+	DBEngine       string   `json:"dbEngine"`
+}
+
+// NodeStatistics represents the statistics of a node, as the free-form text
+// printed by `garage stats`.
+type NodeStatistics struct {
+	Freeform string `json:"freeform"`
+}
+
+// ClusterStatus represents the status of the cluster as seen by the node
+// serving the admin API.
+type ClusterStatus struct {
+	LayoutVersion int64        `json:"layoutVersion"`
+	Nodes         []NodeStatus `json:"nodes"`
+}
+
+// NodeStatus represents a node known to the cluster. Nodes that are down or
+// draining may not report their version nor their partitions.
+type NodeStatus struct {
+	ID                string         `json:"id"`
+	GarageVersion     *string        `json:"garageVersion"`
+	Addr              *string        `json:"addr"`
+	Hostname          *string        `json:"hostname"`
+	IsUp              bool           `json:"isUp"`
+	LastSeenSecsAgo   *int64         `json:"lastSeenSecsAgo"`
+	Role              *NodeRole      `json:"role"`
+	Draining          bool           `json:"draining"`
+	DataPartition     *FreeSpaceInfo `json:"dataPartition"`
+	MetadataPartition *FreeSpaceInfo `json:"metadataPartition"`
+}
+
+// FreeSpaceInfo represents the space of the partition of a node, in bytes.
+type FreeSpaceInfo struct {
+	Available int64 `json:"available"`
+	Total     int64 `json:"total"`
+}
+
+// AdminToken represents an admin API token. The secret is only returned on
+// creation and is never part of it. The token set in the configuration file
+// of the server has no ID nor creation date.
+type AdminToken struct {
+	ID         *string  `json:"id"`
+	Name       string   `json:"name"`
+	Created    *string  `json:"created"`
+	Expiration *string  `json:"expiration"`
+	Expired    bool     `json:"expired"`
+	Scope      []string `json:"scope"`
+}
+
+// Repair operations supported by LaunchRepairOperation.
+const (
+	RepairBlocks    = "blocks"
+	RepairVersions  = "versions"
+	RepairMultipart = "multipartUploads"
+	RepairScrub     = "scrub"
+)
+
+// WorkerInfo represents a background worker of a node.
+type WorkerInfo struct {
+	ID                int64        `json:"id"`
+	Name              string       `json:"name"`
+	State             WorkerState  `json:"state"`
+	Errors            int64        `json:"errors"`
+	ConsecutiveErrors int64        `json:"consecutiveErrors"`
+	LastError         *WorkerError `json:"lastError"`
+}
+
+// WorkerError represents the last error of a worker.
+type WorkerError struct {
+	Message string `json:"message"`
+	SecsAgo int64  `json:"secsAgo"`
+}
+
+// WorkerState is the state of a worker: busy, throttled, idle or done.
+type WorkerState string
+
+// UnmarshalJSON decodes a state, which is either a string or, when the worker
+// is throttled, an object holding the duration of the throttling.
+func (s *WorkerState) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*s = WorkerState(name)
+		return nil
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return fmt.Errorf("invalid worker state %s", string(data))
+	}
+	for name := range object {
+		*s = WorkerState(name)
+	}
+
+	return nil
+}
+
+// Running reports whether the worker still has work to do.
+func (s WorkerState) Running() bool {
+	return s == "busy" || s == "throttled"
+}
+
+// CreateAdminTokenRequest represents the request to create an admin API
+// token. Expiration is in RFC 3339 format.
+type CreateAdminTokenRequest struct {
+	Name         string   `json:"name"`
+	Expiration   *string  `json:"expiration,omitempty"`
+	NeverExpires bool     `json:"neverExpires"`
+	Scope        []string `json:"scope"`
+}
+
+// UpdateAdminTokenRequest represents the request to update an admin API
+// token. Fields left empty are unchanged; NeverExpires removes the expiration.
+type UpdateAdminTokenRequest struct {
+	Name         *string  `json:"name,omitempty"`
+	Expiration   *string  `json:"expiration,omitempty"`
+	NeverExpires bool     `json:"neverExpires,omitempty"`
+	Scope        []string `json:"scope,omitempty"`
+}
+
+// CreatedAdminToken represents a newly created admin API token, the only time
+// its secret is returned.
+type CreatedAdminToken struct {
+	AdminToken
+	SecretToken string `json:"secretToken"`
+}
+
+// ClusterLayout represents the cluster layout, with the role changes staged
+// for its next version.
+type ClusterLayout struct {
+	Version           int64             `json:"version"`
+	Roles             []NodeRole        `json:"roles"`
+	Parameters        *LayoutParameters `json:"parameters"`
+	StagedRoleChanges []NodeRoleChange  `json:"stagedRoleChanges"`
+	StagedParameters  *LayoutParameters `json:"stagedParameters"`
+}
+
+// LayoutPreview represents the layout that applying the staged changes would
+// produce, with the messages of the computation of its partition assignment.
+type LayoutPreview struct {
+	Message   []string      `json:"message"`
+	NewLayout ClusterLayout `json:"newLayout"`
+}
+
+// partitionTransfersPattern matches the summary of the partition copies to
+// move in the messages of a layout computation.
+var partitionTransfersPattern = regexp.MustCompile(`A total of (\d+) new copies of partitions need to be transferred`)
+
+// PartitionTransfers returns the number of partition copies that applying the
+// layout moves between nodes, or false when the messages do not report it.
+func (p LayoutPreview) PartitionTransfers() (int64, bool) {
+	for _, line := range p.Message {
+		if match := partitionTransfersPattern.FindStringSubmatch(line); match != nil {
+			n, err := strconv.ParseInt(match[1], 10, 64)
+			return n, err == nil
+		}
+	}
+	return 0, false
+}
+
+// LayoutParameters represents the cluster-wide parameters of the layout.
+type LayoutParameters struct {
+	ZoneRedundancy ZoneRedundancy `json:"zoneRedundancy"`
+}
+
+// ZoneRedundancy is the number of zones holding a copy of each partition:
+// "maximum", or a positive integer in decimal.
+type ZoneRedundancy string
+
+// ZoneRedundancyMaximum spreads the copies across as many zones as possible.
+const ZoneRedundancyMaximum ZoneRedundancy = "maximum"
+
+// MarshalJSON encodes an integer redundancy as {"atLeast": n}.
+func (z ZoneRedundancy) MarshalJSON() ([]byte, error) {
+	if z == ZoneRedundancyMaximum {
+		return json.Marshal(string(z))
+	}
+
+	n, err := strconv.ParseInt(string(z), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zone redundancy %q", string(z))
+	}
+	return json.Marshal(map[string]int64{"atLeast": n})
+}
+
+// UnmarshalJSON decodes either "maximum" or {"atLeast": n}.
+func (z *ZoneRedundancy) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*z = ZoneRedundancy(name)
+		return nil
+	}
+
+	var atLeast struct {
+		AtLeast *int64 `json:"atLeast"`
+	}
+	if err := json.Unmarshal(data, &atLeast); err != nil || atLeast.AtLeast == nil {
+		return fmt.Errorf("invalid zone redundancy %s", string(data))
+	}
+	*z = ZoneRedundancy(strconv.FormatInt(*atLeast.AtLeast, 10))
+
+	return nil
+}
+
+// NodeRole represents the role of a node in the layout. Gateway nodes have no
+// capacity.
+type NodeRole struct {
+	ID       string   `json:"id"`
+	Zone     string   `json:"zone"`
+	Capacity *int64   `json:"capacity"`
+	Tags     []string `json:"tags"`
+}
+
+// NodeRoleChange represents a staged change of the role of a node: either its
+// removal from the layout, or its new role.
+type NodeRoleChange struct {
+	NodeRole
+	Remove bool
+}
+
+// MarshalJSON encodes a removal as {"id", "remove"} and any other change as
+// the new role, as expected by the API.
+func (c NodeRoleChange) MarshalJSON() ([]byte, error) {
+	if c.Remove {
+		return json.Marshal(struct {
+			ID     string `json:"id"`
+			Remove bool   `json:"remove"`
+		}{ID: c.ID, Remove: true})
+	}
+
+	role := c.NodeRole
+	if role.Tags == nil {
+		role.Tags = []string{}
+	}
+	return json.Marshal(role)
+}
+
+// UnmarshalJSON decodes both forms of a change.
+func (c *NodeRoleChange) UnmarshalJSON(data []byte) error {
+	var change struct {
+		NodeRole
+		Remove bool `json:"remove"`
+	}
+	if err := json.Unmarshal(data, &change); err != nil {
+		return err
+	}
+
+	c.NodeRole = change.NodeRole
+	c.Remove = change.Remove
+	return nil
+}
+
+// UpdateClusterLayoutRequest represents the request to stage layout changes.
+// Parameters are left unchanged when nil.
+type UpdateClusterLayoutRequest struct {
+	Roles      []NodeRoleChange  `json:"roles"`
+	Parameters *LayoutParameters `json:"parameters,omitempty"`
+}
+
 // doRequest makes an HTTP request to the Garage API.
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
 	var reqBody io.Reader
@@ -164,6 +524,13 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 		reqBody = bytes.NewBuffer(jsonData)
 	}
 
+	if authErr := c.rejectedEndpoint(path); authErr != nil {
+		return nil, authErr
+	}
+
+	// Errors report and cache rejections by path, not by the request URI,
+	// which includes the path of an endpoint behind a reverse proxy
+	ctx = context.WithValue(ctx, requestPathKey{}, path)
 	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -192,8 +559,7 @@ func (c *Client) ListBuckets(ctx context.Context) ([]Bucket, error) {
 	defer closeBody(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, c.apiError(resp)
 	}
 
 	var buckets []Bucket
@@ -225,8 +591,7 @@ func (c *Client) GetBucketInfo(ctx context.Context, req GetBucketInfoRequest) (*
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, c.apiError(resp)
 	}
 
 	var bucket Bucket
@@ -246,8 +611,7 @@ func (c *Client) CreateBucket(ctx context.Context, req CreateBucketRequest) (*Bu
 	defer closeBody(resp.Body)
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, c.apiError(resp)
 	}
 
 	var bucket Bucket
@@ -270,8 +634,7 @@ func (c *Client) UpdateBucket(ctx context.Context, bucketID string, req UpdateBu
 	defer closeBody(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, c.apiError(resp)
 	}
 
 	var bucket Bucket
@@ -294,8 +657,7 @@ func (c *Client) DeleteBucket(ctx context.Context, req DeleteBucketRequest) erro
 	defer closeBody(resp.Body)
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return c.apiError(resp)
 	}
 
 	return nil
@@ -315,8 +677,7 @@ func (c *Client) AddBucketAlias(ctx context.Context, bucketID, alias string) err
 	defer closeBody(resp.Body)
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return c.apiError(resp)
 	}
 
 	return nil
@@ -336,8 +697,7 @@ func (c *Client) RemoveBucketAlias(ctx context.Context, bucketID, alias string)
 	defer closeBody(resp.Body)
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return c.apiError(resp)
 	}
 
 	return nil
@@ -352,8 +712,7 @@ func (c *Client) AllowBucketKey(ctx context.Context, req BucketKeyPermRequest) (
 	defer closeBody(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, c.apiError(resp)
 	}
 
 	var bucket Bucket
@@ -373,8 +732,7 @@ func (c *Client) DenyBucketKey(ctx context.Context, req BucketKeyPermRequest) (*
 	defer closeBody(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, c.apiError(resp)
 	}
 
 	var bucket Bucket
@@ -394,8 +752,7 @@ func (c *Client) CreateKey(ctx context.Context, req CreateKeyRequest) (*AccessKe
 	defer closeBody(resp.Body)
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, c.apiError(resp)
 	}
 
 	var key AccessKey
@@ -415,8 +772,7 @@ func (c *Client) ImportKey(ctx context.Context, req ImportKeyRequest) (*AccessKe
 	defer closeBody(resp.Body)
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, c.apiError(resp)
 	}
 
 	var key AccessKey
@@ -427,6 +783,26 @@ func (c *Client) ImportKey(ctx context.Context, req ImportKeyRequest) (*AccessKe
 	return &key, nil
 }
 
+// ListKeys lists all access keys, without their permissions.
+func (c *Client) ListKeys(ctx context.Context) ([]KeyListItem, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/v2/ListKeys", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.apiError(resp)
+	}
+
+	var keys []KeyListItem
+	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return keys, nil
+}
+
 // GetKeyInfo gets information about a specific access key.
 func (c *Client) GetKeyInfo(ctx context.Context, req GetKeyInfoRequest) (*AccessKey, error) {
 	path := fmt.Sprintf("/v2/GetKeyInfo?id=%s", req.ID)
@@ -442,8 +818,28 @@ func (c *Client) GetKeyInfo(ctx context.Context, req GetKeyInfoRequest) (*Access
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, c.apiError(resp)
+	}
+
+	var key AccessKey
+	if err := json.NewDecoder(resp.Body).Decode(&key); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &key, nil
+}
+
+// UpdateKey updates the name or expiration of an access key. Its secret is
+// unchanged.
+func (c *Client) UpdateKey(ctx context.Context, id string, req UpdateKeyRequest) (*AccessKey, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/v2/UpdateKey?id="+url.QueryEscape(id), req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.apiError(resp)
 	}
 
 	var key AccessKey
@@ -465,13 +861,526 @@ func (c *Client) DeleteKey(ctx context.Context, req DeleteKeyRequest) error {
 	defer closeBody(resp.Body)
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return c.apiError(resp)
 	}
 
 	return nil
 }
 
+// ConnectClusterNodes instructs the node to connect to other nodes, given as
+// <node_id>@<addr>:<port>. The results are in the order of nodes.
+func (c *Client) ConnectClusterNodes(ctx context.Context, nodes []string) ([]ConnectNodeResult, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/v2/ConnectClusterNodes", nodes)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.apiError(resp)
+	}
+
+	var results []ConnectNodeResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(results) != len(nodes) {
+		return nil, fmt.Errorf("expected %d results, got %d", len(nodes), len(results))
+	}
+
+	return results, nil
+}
+
+// GetClusterStatus gets the nodes known to the cluster and their status.
+func (c *Client) GetClusterStatus(ctx context.Context) (*ClusterStatus, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/v2/GetClusterStatus", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.apiError(resp)
+	}
+
+	var status ClusterStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &status, nil
+}
+
+// GetClusterHealth gets the health of the cluster.
+func (c *Client) GetClusterHealth(ctx context.Context) (*ClusterHealth, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/v2/GetClusterHealth", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.apiError(resp)
+	}
+
+	var health ClusterHealth
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &health, nil
+}
+
+// GetNodeInfo gets the version and build features of the node answering the
+// admin API. Servers predating the v2 admin API are queried through the v1
+// status endpoint so that their version can still be reported. The result is
+// cached for the lifetime of the client.
+func (c *Client) GetNodeInfo(ctx context.Context) (*NodeInfo, error) {
+	c.nodeInfoMu.Lock()
+	defer c.nodeInfoMu.Unlock()
+
+	if c.nodeInfo != nil {
+		return c.nodeInfo, nil
+	}
+
+	info, err := c.getNodeInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.nodeInfo = info
+	return info, nil
+}
+
+func (c *Client) getNodeInfo(ctx context.Context) (*NodeInfo, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/v2/GetNodeInfo?node=self", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest {
+		return c.getLegacyNodeInfo(ctx)
+	}
+
+	nodes, err := decodeMultiNodeResponse[NodeInfo](c, resp)
+	if err != nil {
+		return nil, err
+	}
+	for id, info := range nodes {
+		if info.NodeID == "" {
+			info.NodeID = id
+		}
+		return &info, nil
+	}
+
+	return nil, errors.New("no node reported its information")
+}
+
+// getLegacyNodeInfo gets the node information from the v1 status endpoint.
+func (c *Client) getLegacyNodeInfo(ctx context.Context) (*NodeInfo, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/v1/status", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.apiError(resp)
+	}
+
+	var status struct {
+		NodeInfo
+		Node string `json:"node"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	info := status.NodeInfo
+	info.NodeID = status.Node
+	return &info, nil
+}
+
+// GetNodeStatistics gets the statistics of a node: the sizes of its metadata
+// tables and the state of its block manager.
+func (c *Client) GetNodeStatistics(ctx context.Context, node string) (*NodeStatistics, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/v2/GetNodeStatistics?node="+url.QueryEscape(node), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	nodes, err := decodeMultiNodeResponse[NodeStatistics](c, resp)
+	if err != nil {
+		return nil, err
+	}
+	stats, ok := nodes[node]
+	if !ok {
+		return nil, fmt.Errorf("node %s did not report its statistics", node)
+	}
+
+	return &stats, nil
+}
+
+// ListAdminTokens lists all admin API tokens. It returns
+// ErrUnsupportedEndpoint on servers without admin tokens, which were added
+// with the v2 admin API.
+func (c *Client) ListAdminTokens(ctx context.Context) ([]AdminToken, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/v2/ListAdminTokens", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	// Unknown endpoints are rejected as bad requests by older servers, the
+	// endpoint itself takes no parameters
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedEndpoint, c.apiError(resp))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.apiError(resp)
+	}
+
+	var tokens []AdminToken
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// CreateAdminToken creates an admin API token. It returns
+// ErrUnsupportedEndpoint on servers without admin tokens.
+func (c *Client) CreateAdminToken(ctx context.Context, req CreateAdminTokenRequest) (*CreatedAdminToken, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/v2/CreateAdminToken", req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedEndpoint, c.apiError(resp))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.apiError(resp)
+	}
+
+	var token CreatedAdminToken
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &token, nil
+}
+
+// GetAdminTokenInfo gets an admin API token by ID. It returns nil if the
+// token does not exist.
+func (c *Client) GetAdminTokenInfo(ctx context.Context, id string) (*AdminToken, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/v2/GetAdminTokenInfo?id="+url.QueryEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.apiError(resp)
+	}
+
+	var token AdminToken
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &token, nil
+}
+
+// UpdateAdminToken updates the name, expiration or scope of an admin API
+// token. Its secret is unchanged.
+func (c *Client) UpdateAdminToken(ctx context.Context, id string, req UpdateAdminTokenRequest) (*AdminToken, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/v2/UpdateAdminToken?id="+url.QueryEscape(id), req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.apiError(resp)
+	}
+
+	var token AdminToken
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &token, nil
+}
+
+// DeleteAdminToken deletes an admin API token. Deleting a token that no
+// longer exists is not an error.
+func (c *Client) DeleteAdminToken(ctx context.Context, id string) error {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/v2/DeleteAdminToken?id="+url.QueryEscape(id), nil)
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return c.apiError(resp)
+	}
+
+	return nil
+}
+
+// GetClusterLayout gets the current cluster layout and its staged changes.
+func (c *Client) GetClusterLayout(ctx context.Context) (*ClusterLayout, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/v2/GetClusterLayout", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	return c.decodeClusterLayout(resp)
+}
+
+// UpdateClusterLayout stages role and parameter changes, to be applied with
+// ApplyClusterLayout.
+func (c *Client) UpdateClusterLayout(ctx context.Context, req UpdateClusterLayoutRequest) (*ClusterLayout, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/v2/UpdateClusterLayout", req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	return c.decodeClusterLayout(resp)
+}
+
+// ApplyClusterLayout applies the staged changes as the given layout version,
+// which must be the current version plus one.
+func (c *Client) ApplyClusterLayout(ctx context.Context, version int64) (*ClusterLayout, error) {
+	req := map[string]int64{
+		"version": version,
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/v2/ApplyClusterLayout", req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.apiError(resp)
+	}
+
+	var applied struct {
+		Message []string      `json:"message"`
+		Layout  ClusterLayout `json:"layout"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&applied); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &applied.Layout, nil
+}
+
+// PreviewClusterLayoutChanges computes the layout that applying the staged
+// changes would produce, without applying them. It returns
+// ErrUnsupportedEndpoint on servers without layout previews.
+func (c *Client) PreviewClusterLayoutChanges(ctx context.Context) (*LayoutPreview, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/v2/PreviewClusterLayoutChanges", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedEndpoint, c.apiError(resp))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.apiError(resp)
+	}
+
+	// The staged layout may be impossible to compute, e.g. with too few
+	// zones for the redundancy, which is reported with a success status
+	var preview struct {
+		LayoutPreview
+		Error *string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&preview); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if preview.Error != nil {
+		return nil, fmt.Errorf("unable to compute the staged layout: %s", *preview.Error)
+	}
+
+	return &preview.LayoutPreview, nil
+}
+
+// RevertClusterLayout discards all the staged changes.
+func (c *Client) RevertClusterLayout(ctx context.Context) (*ClusterLayout, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/v2/RevertClusterLayout", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	return c.decodeClusterLayout(resp)
+}
+
+func (c *Client) decodeClusterLayout(resp *http.Response) (*ClusterLayout, error) {
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.apiError(resp)
+	}
+
+	var layout ClusterLayout
+	if err := json.NewDecoder(resp.Body).Decode(&layout); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &layout, nil
+}
+
+// LaunchRepairOperation launches a repair on a node, or on every node when
+// node is "*". Scrubs are started rather than launched, as each node runs a
+// single scrub worker.
+func (c *Client) LaunchRepairOperation(ctx context.Context, node, operation string) error {
+	var repairType interface{} = operation
+	if operation == RepairScrub {
+		repairType = map[string]string{"scrub": "start"}
+	}
+	req := map[string]interface{}{
+		"repairType": repairType,
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/v2/LaunchRepairOperation?node="+url.QueryEscape(node), req)
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp.Body)
+
+	_, err = decodeMultiNodeResponse[json.RawMessage](c, resp)
+	return err
+}
+
+// ListWorkers lists the background workers of a node, or of every node when
+// node is "*", by node ID.
+func (c *Client) ListWorkers(ctx context.Context, node string) (map[string][]WorkerInfo, error) {
+	req := map[string]bool{
+		"busyOnly":  false,
+		"errorOnly": false,
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/v2/ListWorkers?node="+url.QueryEscape(node), req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	return decodeMultiNodeResponse[[]WorkerInfo](c, resp)
+}
+
+// decodeMultiNodeResponse decodes the response of an endpoint called on
+// several nodes, failing if any of them reported an error.
+func decodeMultiNodeResponse[T any](c *Client, resp *http.Response) (map[string]T, error) {
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.apiError(resp)
+	}
+
+	var multi struct {
+		Success map[string]T      `json:"success"`
+		Error   map[string]string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&multi); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(multi.Error) > 0 {
+		nodes := make([]string, 0, len(multi.Error))
+		for node := range multi.Error {
+			nodes = append(nodes, node)
+		}
+		sort.Strings(nodes)
+
+		errs := make([]string, 0, len(nodes))
+		for _, node := range nodes {
+			errs = append(errs, fmt.Sprintf("node %s: %s", node, multi.Error[node]))
+		}
+		return nil, fmt.Errorf("request failed on %d node(s): %s", len(errs), strings.Join(errs, "; "))
+	}
+
+	return multi.Success, nil
+}
+
+// apiError returns the error of resp, an *AuthError remembered for its
+// endpoint when the admin API rejected the token.
+func (c *Client) apiError(resp *http.Response) error {
+	apiErr := newAPIError(resp)
+	if apiErr.StatusCode != http.StatusUnauthorized && apiErr.StatusCode != http.StatusForbidden {
+		return apiErr
+	}
+
+	authErr := &AuthError{APIError: apiErr}
+	c.rejectedMu.Lock()
+	defer c.rejectedMu.Unlock()
+	if c.rejected == nil {
+		c.rejected = map[string]*AuthError{}
+	}
+	c.rejected[endpointName(apiErr.Path)] = authErr
+
+	return authErr
+}
+
+// rejectedEndpoint returns a repeated AuthError when the endpoint of path
+// already rejected the token, nil otherwise.
+func (c *Client) rejectedEndpoint(path string) *AuthError {
+	c.rejectedMu.Lock()
+	defer c.rejectedMu.Unlock()
+
+	authErr, ok := c.rejected[endpointName(path)]
+	if !ok {
+		return nil
+	}
+	return &AuthError{APIError: authErr.APIError, Repeated: true}
+}
+
+// endpointName returns path without its query, e.g. /v2/GetBucketInfo.
+func endpointName(path string) string {
+	name, _, _ := strings.Cut(path, "?")
+	return name
+}
+
+// requestPathKey is the context key of the path doRequest was called with.
+type requestPathKey struct{}
+
+// newAPIError returns the APIError of resp, reading its body.
+func newAPIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(resp.Body)
+	apiErr := &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.Path = resp.Request.URL.RequestURI()
+		if path, ok := resp.Request.Context().Value(requestPathKey{}).(string); ok {
+			apiErr.Path = path
+		}
+	}
+
+	var garageErr struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &garageErr) == nil {
+		apiErr.Code, apiErr.Message = garageErr.Code, garageErr.Message
+	}
+
+	return apiErr
+}
+
 func closeBody(body io.ReadCloser) {
 	_ = body.Close()
 }
@@ -6,8 +6,11 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -95,6 +98,143 @@ func TestListBuckets(t *testing.T) {
 	}
 }
 
+func TestListKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v2/ListKeys" {
+			t.Errorf("Expected GET /v2/ListKeys, got %s %s", r.Method, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"id": "GK1", "name": "ci", "created": "2025-01-01T00:00:00Z", "expiration": null, "expired": false},
+			{"id": "GK2", "name": "old", "created": "2024-01-01T00:00:00Z", "expiration": "2024-06-01T00:00:00Z", "expired": true}
+		]`))
+	}))
+	defer server.Close()
+
+	keys, err := NewClient(server.URL, "test-token").ListKeys(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(keys) != 2 || keys[0].ID != "GK1" || keys[0].Name != "ci" || keys[0].Expiration != nil {
+		t.Errorf("Unexpected first key: %+v", keys)
+	}
+	if keys[1].Expiration == nil || *keys[1].Expiration != "2024-06-01T00:00:00Z" || !keys[1].Expired {
+		t.Errorf("Unexpected second key: %+v", keys[1])
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"code": "AccessDenied", "message": "Forbidden: missing scope", "region": "garage", "path": "/v2/ListKeys"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "test-token").ListKeys(context.Background())
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an *APIError, got %T: %v", err, err)
+	}
+	if apiErr.Method != http.MethodGet || apiErr.Path != "/v2/ListKeys" || apiErr.StatusCode != http.StatusForbidden {
+		t.Errorf("Unexpected request of the error: %+v", apiErr)
+	}
+	if apiErr.Code != "AccessDenied" || apiErr.Message != "Forbidden: missing scope" {
+		t.Errorf("Unexpected Garage error: %+v", apiErr)
+	}
+	if !strings.Contains(err.Error(), "status 403") {
+		t.Errorf("Expected the status in %q", err)
+	}
+}
+
+func TestAPIError_notJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	bucketID := "abc"
+	_, err := NewClient(server.URL, "test-token").GetBucketInfo(context.Background(), GetBucketInfoRequest{ID: &bucketID})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an *APIError, got %T: %v", err, err)
+	}
+	if apiErr.Path != "/v2/GetBucketInfo?id=abc" || apiErr.Code != "" || apiErr.Body != "bad gateway\n" {
+		t.Errorf("Unexpected error: %+v", apiErr)
+	}
+}
+
+func TestAuthError(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"code": "InvalidToken", "message": "Invalid bearer token"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "wrong-token")
+	for i, id := range []string{"abc", "def"} {
+		bucketID := id
+		_, err := c.GetBucketInfo(context.Background(), GetBucketInfoRequest{ID: &bucketID})
+
+		var authErr *AuthError
+		if !errors.As(err, &authErr) {
+			t.Fatalf("Expected an *AuthError, got %T: %v", err, err)
+		}
+		if authErr.Repeated != (i > 0) || authErr.Code != "InvalidToken" || authErr.Path != "/v2/GetBucketInfo?id=abc" {
+			t.Errorf("Unexpected error of request %d: %+v", i, authErr)
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected the *APIError to be wrapped, got %v", err)
+		}
+	}
+	if requests["/v2/GetBucketInfo"] != 1 {
+		t.Errorf("Expected a single request to the rejecting endpoint, got %d", requests["/v2/GetBucketInfo"])
+	}
+
+	// Other endpoints may be in the scope of the token
+	if _, err := c.ListBuckets(context.Background()); err == nil || requests["/v2/ListBuckets"] != 1 {
+		t.Errorf("Expected ListBuckets to be sent and rejected, got %v and %d requests", err, requests["/v2/ListBuckets"])
+	}
+}
+
+func TestAuthError_endpointPath(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"code": "InvalidToken", "message": "Invalid bearer token"}`))
+	}))
+	defer server.Close()
+
+	// Behind a reverse proxy, the admin API is under the path of the endpoint
+	c := NewClient(server.URL+"/garage", "wrong-token")
+	for i, id := range []string{"abc", "def"} {
+		bucketID := id
+		_, err := c.GetBucketInfo(context.Background(), GetBucketInfoRequest{ID: &bucketID})
+
+		var authErr *AuthError
+		if !errors.As(err, &authErr) {
+			t.Fatalf("Expected an *AuthError, got %T: %v", err, err)
+		}
+		if authErr.Repeated != (i > 0) || authErr.Path != "/v2/GetBucketInfo?id=abc" {
+			t.Errorf("Unexpected error of request %d: %+v", i, authErr)
+		}
+	}
+	if requests["/garage/v2/GetBucketInfo"] != 1 {
+		t.Errorf("Expected a single request to the rejecting endpoint, got %v", requests)
+	}
+}
+
 func TestGetBucketInfo_byID(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
@@ -411,3 +551,564 @@ func TestClient_errorHandling(t *testing.T) {
 		t.Error("Expected error for 500 response")
 	}
 }
+
+func TestConnectClusterNodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST request, got %s", r.Method)
+		}
+		if r.URL.Path != "/v2/ConnectClusterNodes" {
+			t.Errorf("Expected path /v2/ConnectClusterNodes, got %s", r.URL.Path)
+		}
+
+		var nodes []string
+		if err := json.NewDecoder(r.Body).Decode(&nodes); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if len(nodes) != 2 || nodes[0] != "ec79@10.0.0.1:3901" {
+			t.Errorf("Unexpected nodes %v", nodes)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"success": true, "error": null}, {"success": false, "error": "connection refused"}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	results, err := client.ConnectClusterNodes(context.Background(), []string{"ec79@10.0.0.1:3901", "4a6a@10.0.0.2:3901"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !results[0].Success || results[0].Error != nil {
+		t.Errorf("Expected the first node to succeed, got %+v", results[0])
+	}
+	if results[1].Success || results[1].Error == nil || *results[1].Error != "connection refused" {
+		t.Errorf("Expected the second node to fail, got %+v", results[1])
+	}
+}
+
+func TestGetClusterStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET request, got %s", r.Method)
+		}
+		if r.URL.Path != "/v2/GetClusterStatus" {
+			t.Errorf("Expected path /v2/GetClusterStatus, got %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"layoutVersion": 3, "nodes": [
+			{"id": "ec79", "addr": "10.0.0.1:3901", "hostname": "node1", "isUp": true, "lastSeenSecsAgo": null,
+			 "role": {"zone": "dc1", "capacity": 1000, "tags": []}, "draining": false,
+			 "dataPartition": {"available": 600, "total": 1000}, "metadataPartition": null}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	status, err := client.GetClusterStatus(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if status.LayoutVersion != 3 || len(status.Nodes) != 1 {
+		t.Fatalf("Unexpected status %+v", status)
+	}
+	node := status.Nodes[0]
+	if node.ID != "ec79" || !node.IsUp || *node.Addr != "10.0.0.1:3901" || node.Role.Zone != "dc1" {
+		t.Errorf("Unexpected node %+v", node)
+	}
+	if node.DataPartition == nil || node.DataPartition.Available != 600 || node.MetadataPartition != nil {
+		t.Errorf("Unexpected partitions %+v and %+v", node.DataPartition, node.MetadataPartition)
+	}
+}
+
+func TestGetClusterHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/GetClusterHealth" {
+			t.Errorf("Expected path /v2/GetClusterHealth, got %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status": "degraded", "knownNodes": 3, "connectedNodes": 2, "storageNodes": 3, "storageNodesUp": 2,
+			"partitions": 256, "partitionsQuorum": 256, "partitionsAllOk": 0}`))
+	}))
+	defer server.Close()
+
+	health, err := NewClient(server.URL, "test-token").GetClusterHealth(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if health.Status != "degraded" || health.StorageNodesUp != 2 || health.PartitionsQuorum != 256 {
+		t.Errorf("Unexpected health %+v", health)
+	}
+}
+
+func TestGetNodeInfo(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/v2/GetNodeInfo" || r.URL.Query().Get("node") != "self" {
+			t.Errorf("Expected path /v2/GetNodeInfo?node=self, got %s", r.URL)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success": {"abc": {"nodeId": "abc", "garageVersion": "v2.1.0", "garageFeatures": ["k2v", "lmdb"],
+			"rustVersion": "1.86.0", "dbEngine": "LMDB"}}, "error": {}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "test-token")
+	info, err := c.GetNodeInfo(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if info.NodeID != "abc" || info.GarageVersion != "v2.1.0" || info.RustVersion != "1.86.0" || len(info.GarageFeatures) != 2 {
+		t.Errorf("Unexpected node info %+v", info)
+	}
+
+	// The server does not change during the lifetime of the client
+	if _, err := c.GetNodeInfo(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected the node info to be cached, got %d calls", calls)
+	}
+}
+
+func TestGetNodeInfo_legacy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/status" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"node": "abc", "garageVersion": "v1.1.0", "garageFeatures": ["k2v"], "rustVersion": "1.81.0",
+			"dbEngine": "LMDB", "layoutVersion": 2, "nodes": []}`))
+	}))
+	defer server.Close()
+
+	info, err := NewClient(server.URL, "test-token").GetNodeInfo(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if info.NodeID != "abc" || info.GarageVersion != "v1.1.0" || info.RustVersion != "1.81.0" {
+		t.Errorf("Unexpected node info %+v", info)
+	}
+}
+
+func TestGetNodeStatistics_nodeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/GetNodeStatistics" || r.URL.Query().Get("node") != "abc" {
+			t.Errorf("Expected path /v2/GetNodeStatistics?node=abc, got %s", r.URL)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success": {}, "error": {"abc": "node is down"}}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "test-token").GetNodeStatistics(context.Background(), "abc")
+	if err == nil || !strings.Contains(err.Error(), "node abc: node is down") {
+		t.Errorf("Expected the node error, got %v", err)
+	}
+}
+
+func TestListAdminTokens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET request, got %s", r.Method)
+		}
+		if r.URL.Path != "/v2/ListAdminTokens" {
+			t.Errorf("Expected path /v2/ListAdminTokens, got %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"id": null, "name": "admin_token (from daemon configuration)", "created": null, "expiration": null, "expired": false, "scope": ["*"]},
+			{"id": "b7f9c7a1", "name": "monitoring", "created": "2025-06-01T10:00:00Z", "expiration": "2026-06-01T10:00:00Z", "expired": false, "scope": ["GetClusterStatus", "Metrics"]}
+		]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	tokens, err := client.ListAdminTokens(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(tokens) != 2 {
+		t.Fatalf("Expected 2 tokens, got %d", len(tokens))
+	}
+	if tokens[0].ID != nil || tokens[0].Created != nil {
+		t.Errorf("Expected the configuration token to have no ID nor creation date, got %+v", tokens[0])
+	}
+	if *tokens[1].ID != "b7f9c7a1" || tokens[1].Name != "monitoring" || len(tokens[1].Scope) != 2 {
+		t.Errorf("Unexpected token %+v", tokens[1])
+	}
+}
+
+func TestListAdminTokens_unsupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Bad request: Unknown API endpoint"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	_, err := client.ListAdminTokens(context.Background())
+	if !errors.Is(err, ErrUnsupportedEndpoint) {
+		t.Errorf("Expected ErrUnsupportedEndpoint, got %v", err)
+	}
+}
+
+func TestCreateAdminToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v2/CreateAdminToken" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		expected := `{"name":"ci","expiration":"2025-01-01T01:00:00Z","neverExpires":false,"scope":["ListBuckets"]}`
+		if string(body) != expected {
+			t.Errorf("Expected body %s, got %s", expected, body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "a1", "name": "ci", "created": "2025-01-01T00:00:00Z", "expiration": "2025-01-01T01:00:00Z",
+			"expired": false, "scope": ["ListBuckets"], "secretToken": "a1.secret"}`))
+	}))
+	defer server.Close()
+
+	expiration := "2025-01-01T01:00:00Z"
+	token, err := NewClient(server.URL, "test-token").CreateAdminToken(context.Background(), CreateAdminTokenRequest{
+		Name:       "ci",
+		Expiration: &expiration,
+		Scope:      []string{"ListBuckets"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if *token.ID != "a1" || token.SecretToken != "a1.secret" {
+		t.Errorf("Unexpected token %+v", token)
+	}
+}
+
+func TestDeleteAdminToken(t *testing.T) {
+	for _, status := range []int{http.StatusOK, http.StatusNotFound} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v2/DeleteAdminToken" || r.URL.Query().Get("id") != "a1" {
+				t.Errorf("Unexpected request to %s", r.URL)
+			}
+			w.WriteHeader(status)
+		}))
+
+		if err := NewClient(server.URL, "test-token").DeleteAdminToken(context.Background(), "a1"); err != nil {
+			t.Errorf("Expected no error for status %d, got %v", status, err)
+		}
+		server.Close()
+	}
+}
+
+func TestGetAdminTokenInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/GetAdminTokenInfo" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("id") != "a1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "a1", "name": "ci", "created": "2025-01-01T00:00:00Z", "expiration": null,
+			"expired": false, "scope": ["ListBuckets", "GetBucketInfo"]}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "test-token")
+	token, err := c.GetAdminTokenInfo(context.Background(), "a1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if *token.ID != "a1" || token.Expiration != nil || len(token.Scope) != 2 {
+		t.Errorf("Unexpected token %+v", token)
+	}
+
+	token, err = c.GetAdminTokenInfo(context.Background(), "missing")
+	if err != nil || token != nil {
+		t.Errorf("Expected no token nor error for a missing token, got %+v and %v", token, err)
+	}
+}
+
+func TestUpdateAdminToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v2/UpdateAdminToken" || r.URL.Query().Get("id") != "a1" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+		body, _ := io.ReadAll(r.Body)
+		expected := `{"name":"ci","neverExpires":true,"scope":["ListBuckets"]}`
+		if string(body) != expected {
+			t.Errorf("Expected body %s, got %s", expected, body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "a1", "name": "ci", "created": "2025-01-01T00:00:00Z", "expiration": null,
+			"expired": false, "scope": ["ListBuckets"]}`))
+	}))
+	defer server.Close()
+
+	name := "ci"
+	token, err := NewClient(server.URL, "test-token").UpdateAdminToken(context.Background(), "a1", UpdateAdminTokenRequest{
+		Name:         &name,
+		NeverExpires: true,
+		Scope:        []string{"ListBuckets"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if *token.ID != "a1" || token.Scope[0] != "ListBuckets" {
+		t.Errorf("Unexpected token %+v", token)
+	}
+}
+
+func TestUpdateKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v2/UpdateKey" || r.URL.Query().Get("id") != "GK1" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+		body, _ := io.ReadAll(r.Body)
+		expected := `{"expiration":"2025-01-01T13:00:00Z"}`
+		if string(body) != expected {
+			t.Errorf("Expected body %s, got %s", expected, body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"accessKeyId": "GK1", "name": "ci", "expired": false, "expiration": "2025-01-01T13:00:00Z",
+			"permissions": {"createBucket": false}, "buckets": []}`))
+	}))
+	defer server.Close()
+
+	expiration := "2025-01-01T13:00:00Z"
+	key, err := NewClient(server.URL, "test-token").UpdateKey(context.Background(), "GK1", UpdateKeyRequest{Expiration: &expiration})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if key.AccessKeyID != "GK1" || key.Expiration == nil || *key.Expiration != expiration {
+		t.Errorf("Unexpected key %+v", key)
+	}
+}
+
+func TestNodeRoleChange_json(t *testing.T) {
+	capacity := int64(1000)
+	changes := []NodeRoleChange{
+		{NodeRole: NodeRole{ID: "ec79", Zone: "dc1", Capacity: &capacity}},
+		{NodeRole: NodeRole{ID: "4a6a"}, Remove: true},
+		{NodeRole: NodeRole{ID: "b3c2", Zone: "dc1", Tags: []string{"gw"}}},
+	}
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// Gateways are sent with an explicit null capacity
+	expected := `[{"id":"ec79","zone":"dc1","capacity":1000,"tags":[]},{"id":"4a6a","remove":true},{"id":"b3c2","zone":"dc1","capacity":null,"tags":["gw"]}]`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	var decoded []NodeRoleChange
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if decoded[0].Remove || *decoded[0].Capacity != 1000 || !decoded[1].Remove || decoded[1].ID != "4a6a" || decoded[2].Capacity != nil {
+		t.Errorf("Unexpected changes %+v", decoded)
+	}
+}
+
+func TestLayoutParameters_json(t *testing.T) {
+	tests := []struct {
+		redundancy ZoneRedundancy
+		expected   string
+	}{
+		{redundancy: ZoneRedundancyMaximum, expected: `{"zoneRedundancy":"maximum"}`},
+		{redundancy: "2", expected: `{"zoneRedundancy":{"atLeast":2}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.redundancy), func(t *testing.T) {
+			data, err := json.Marshal(LayoutParameters{ZoneRedundancy: tt.redundancy})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, data)
+			}
+
+			var decoded LayoutParameters
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if decoded.ZoneRedundancy != tt.redundancy {
+				t.Errorf("Expected %s, got %s", tt.redundancy, decoded.ZoneRedundancy)
+			}
+		})
+	}
+
+	if _, err := json.Marshal(LayoutParameters{ZoneRedundancy: "all"}); err == nil {
+		t.Errorf("Expected an error for an invalid zone redundancy")
+	}
+}
+
+func TestPreviewClusterLayoutChanges(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v2/PreviewClusterLayoutChanges" {
+			t.Errorf("Expected POST /v2/PreviewClusterLayoutChanges, got %s %s", r.Method, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"message": ["==== COMPUTATION OF A NEW PARTITION ASSIGNATION ====", "",
+			"A total of 384 new copies of partitions need to be transferred.", ""],
+			"newLayout": {"version": 3, "roles": [], "stagedRoleChanges": []}}`))
+	}))
+	defer server.Close()
+
+	preview, err := NewClient(server.URL, "test-token").PreviewClusterLayoutChanges(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if preview.NewLayout.Version != 3 {
+		t.Errorf("Expected new layout version 3, got %d", preview.NewLayout.Version)
+	}
+	if transfers, ok := preview.PartitionTransfers(); !ok || transfers != 384 {
+		t.Errorf("Expected 384 partition transfers, got %d, %t", transfers, ok)
+	}
+}
+
+func TestPreviewClusterLayoutChanges_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"error": "not enough zones"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "test-token").PreviewClusterLayoutChanges(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "not enough zones") {
+		t.Errorf("Expected the computation error, got %v", err)
+	}
+}
+
+func TestApplyClusterLayout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST request, got %s", r.Method)
+		}
+		if r.URL.Path != "/v2/ApplyClusterLayout" {
+			t.Errorf("Expected path /v2/ApplyClusterLayout, got %s", r.URL.Path)
+		}
+
+		var req map[string]int64
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if req["version"] != 4 {
+			t.Errorf("Expected version 4, got %d", req["version"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"message": ["Layout applied"], "layout": {"version": 4, "roles": [{"id": "ec79", "zone": "dc1", "capacity": null, "tags": []}], "stagedRoleChanges": []}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	layout, err := client.ApplyClusterLayout(context.Background(), 4)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if layout.Version != 4 || len(layout.Roles) != 1 || layout.Roles[0].Capacity != nil {
+		t.Errorf("Unexpected layout %+v", layout)
+	}
+}
+
+func TestLaunchRepairOperation(t *testing.T) {
+	tests := []struct {
+		operation string
+		expected  string
+	}{
+		{operation: RepairBlocks, expected: `{"repairType":"blocks"}`},
+		{operation: RepairScrub, expected: `{"repairType":{"scrub":"start"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operation, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v2/LaunchRepairOperation" || r.URL.Query().Get("node") != "*" {
+					t.Errorf("Unexpected request to %s", r.URL)
+				}
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != tt.expected {
+					t.Errorf("Expected body %s, got %s", tt.expected, body)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"success": {"a1": null}, "error": {}}`))
+			}))
+			defer server.Close()
+
+			if err := NewClient(server.URL, "test-token").LaunchRepairOperation(context.Background(), "*", tt.operation); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestListWorkers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"success": {
+				"a1": [
+					{"id": 1, "name": "Block scrub worker", "state": {"throttled": {"durationSecs": 0.5}}, "errors": 0, "consecutiveErrors": 0, "lastError": null},
+					{"id": 7, "name": "version repair worker", "state": "done", "errors": 1, "consecutiveErrors": 1, "lastError": {"message": "timeout", "secsAgo": 3}}
+				]
+			},
+			"error": {}
+		}`))
+	}))
+	defer server.Close()
+
+	workers, err := NewClient(server.URL, "test-token").ListWorkers(context.Background(), "a1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(workers["a1"]) != 2 {
+		t.Fatalf("Expected 2 workers, got %+v", workers)
+	}
+	if scrub := workers["a1"][0]; scrub.State != "throttled" || !scrub.State.Running() {
+		t.Errorf("Expected a throttled scrub worker, got %+v", scrub)
+	}
+	if repair := workers["a1"][1]; repair.State.Running() || repair.LastError == nil || repair.LastError.Message != "timeout" {
+		t.Errorf("Expected a finished repair worker with an error, got %+v", repair)
+	}
+}
+
+func TestListWorkers_nodeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success": {"a1": []}, "error": {"b2": "node is down"}}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "test-token").ListWorkers(context.Background(), "*")
+	if err == nil || !strings.Contains(err.Error(), "node b2: node is down") {
+		t.Errorf("Expected the node error, got %v", err)
+	}
+}
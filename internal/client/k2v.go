@@ -0,0 +1,229 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// k2vCausalityTokenHeader carries the causality token of an item, returned
+// by ReadItem and sent back on writes.
+const k2vCausalityTokenHeader = "X-Garage-Causality-Token"
+
+// K2VClient is a client for the K2V API of Garage. Requests are signed with
+// S3 credentials, for the "k2v" service.
+type K2VClient struct {
+	endpoint    string
+	credentials aws.Credentials
+	signer      *v4.Signer
+	httpClient  *http.Client
+}
+
+// NewK2VClient creates a new K2V API client.
+func NewK2VClient(endpoint, accessKey, secretKey string) *K2VClient {
+	return &K2VClient{
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		credentials: aws.Credentials{AccessKeyID: accessKey, SecretAccessKey: secretKey},
+		signer:      v4.NewSigner(),
+		httpClient:  http.DefaultClient,
+	}
+}
+
+// K2VItem represents the values of a K2V item. An item written concurrently
+// without causality token holds several values until one write supersedes
+// them all. Deleted values are kept as nil tombstones.
+type K2VItem struct {
+	Values         [][]byte
+	CausalityToken string
+}
+
+// LiveValues returns the values of the item that are not tombstones.
+func (i *K2VItem) LiveValues() [][]byte {
+	var values [][]byte
+	for _, value := range i.Values {
+		if value != nil {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// doRequest sends a signed request to path, relative to the endpoint.
+func (c *K2VClient) doRequest(ctx context.Context, method, path string, query url.Values, body []byte, header http.Header) (*http.Response, error) {
+	requestURL := c.endpoint + path
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if err := c.signer.SignHTTP(ctx, c.credentials, req, payloadHash, "k2v", "garage", time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// doItemRequest sends a signed request for the item of bucket identified by
+// partitionKey and sortKey.
+func (c *K2VClient) doItemRequest(ctx context.Context, method, bucket, partitionKey, sortKey string, body []byte, header http.Header) (*http.Response, error) {
+	return c.doRequest(ctx, method, "/"+url.PathEscape(bucket)+"/"+url.PathEscape(partitionKey), url.Values{"sort_key": {sortKey}}, body, header)
+}
+
+// ReadItem reads an item with all its concurrent values. It returns nil if
+// the item does not exist.
+func (c *K2VClient) ReadItem(ctx context.Context, bucket, partitionKey, sortKey string) (*K2VItem, error) {
+	resp, err := c.doItemRequest(ctx, http.MethodGet, bucket, partitionKey, sortKey, nil, http.Header{"Accept": {"application/json"}})
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	// Values are base64 encoded, tombstones are null
+	var values []*[]byte
+	if err := json.NewDecoder(resp.Body).Decode(&values); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	item := &K2VItem{CausalityToken: resp.Header.Get(k2vCausalityTokenHeader)}
+	for _, value := range values {
+		if value == nil {
+			item.Values = append(item.Values, nil)
+			continue
+		}
+		item.Values = append(item.Values, *value)
+	}
+
+	return item, nil
+}
+
+// InsertItem writes the value of an item. With the causality token of the
+// last read, the value supersedes the values read; without it, the value is
+// added next to any existing one.
+func (c *K2VClient) InsertItem(ctx context.Context, bucket, partitionKey, sortKey string, value []byte, causalityToken string) error {
+	header := http.Header{"Content-Type": {"application/octet-stream"}}
+	if causalityToken != "" {
+		header.Set(k2vCausalityTokenHeader, causalityToken)
+	}
+
+	resp, err := c.doItemRequest(ctx, http.MethodPut, bucket, partitionKey, sortKey, value, header)
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
+
+// DeleteItem deletes the values of an item superseded by causalityToken.
+// Deleting an item that no longer exists is not an error.
+func (c *K2VClient) DeleteItem(ctx context.Context, bucket, partitionKey, sortKey, causalityToken string) error {
+	resp, err := c.doItemRequest(ctx, http.MethodDelete, bucket, partitionKey, sortKey, nil, http.Header{k2vCausalityTokenHeader: {causalityToken}})
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
+
+// K2VIndexQuery selects the partition keys returned by ReadIndex. Empty
+// fields are not sent.
+type K2VIndexQuery struct {
+	Prefix string
+	Start  string
+	Limit  int
+}
+
+// K2VIndex represents a page of the index of a bucket: its partition keys,
+// in the order of the server, and where the next page starts when there
+// are more keys.
+type K2VIndex struct {
+	PartitionKeys []K2VPartitionKey `json:"partitionKeys"`
+	More          bool              `json:"more"`
+	NextStart     *string           `json:"nextStart"`
+}
+
+// K2VPartitionKey represents the counters of a partition key in the index.
+type K2VPartitionKey struct {
+	PK        string `json:"pk"`
+	Entries   int64  `json:"entries"`
+	Conflicts int64  `json:"conflicts"`
+	Values    int64  `json:"values"`
+	Bytes     int64  `json:"bytes"`
+}
+
+// ReadIndex reads a page of the index of the partition keys of bucket.
+func (c *K2VClient) ReadIndex(ctx context.Context, bucket string, query K2VIndexQuery) (*K2VIndex, error) {
+	values := url.Values{}
+	if query.Prefix != "" {
+		values.Set("prefix", query.Prefix)
+	}
+	if query.Start != "" {
+		values.Set("start", query.Start)
+	}
+	if query.Limit > 0 {
+		values.Set("limit", strconv.Itoa(query.Limit))
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, "/"+url.PathEscape(bucket), values, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var index K2VIndex
+	if err := json.NewDecoder(resp.Body).Decode(&index); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &index, nil
+}
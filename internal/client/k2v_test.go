@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestK2VReadItem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Authorization"), "Credential=GKtest/") || !strings.Contains(r.Header.Get("Authorization"), "/garage/k2v/aws4_request") {
+			t.Errorf("Expected a request signed for k2v, got %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.EscapedPath() != "/meta/cluster%2Fa" {
+			t.Errorf("Unexpected request to %s", r.URL.EscapedPath())
+		}
+
+		switch r.URL.Query().Get("sort_key") {
+		case "missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "conflict":
+			w.Header().Set("X-Garage-Causality-Token", "ct2")
+			_, _ = w.Write([]byte(`["b25l", null, "dHdv"]`))
+		default:
+			w.Header().Set("X-Garage-Causality-Token", "ct1")
+			_, _ = w.Write([]byte(`["aGVsbG8="]`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewK2VClient(server.URL, "GKtest", "secret")
+
+	item, err := c.ReadItem(context.Background(), "meta", "cluster/a", "name")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if item.CausalityToken != "ct1" || len(item.Values) != 1 || string(item.Values[0]) != "hello" {
+		t.Errorf("Unexpected item %+v", item)
+	}
+
+	item, err = c.ReadItem(context.Background(), "meta", "cluster/a", "conflict")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	live := item.LiveValues()
+	if len(item.Values) != 3 || len(live) != 2 || string(live[0]) != "one" || string(live[1]) != "two" {
+		t.Errorf("Unexpected item %+v", item)
+	}
+
+	item, err = c.ReadItem(context.Background(), "meta", "cluster/a", "missing")
+	if err != nil || item != nil {
+		t.Errorf("Expected no item nor error for a missing item, got %+v and %v", item, err)
+	}
+}
+
+func TestK2VInsertAndDeleteItem(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Query().Get("sort_key")+" "+r.Header.Get("X-Garage-Causality-Token")+" "+string(body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := NewK2VClient(server.URL, "GKtest", "secret")
+	if err := c.InsertItem(context.Background(), "meta", "cluster", "name", []byte("hello"), ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := c.InsertItem(context.Background(), "meta", "cluster", "name", []byte("world"), "ct1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := c.DeleteItem(context.Background(), "meta", "cluster", "name", "ct2"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{"PUT name  hello", "PUT name ct1 world", "DELETE name ct2 "}
+	if strings.Join(requests, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected requests %q, got %q", expected, requests)
+	}
+}
+
+func TestK2VReadIndex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/meta" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+		if r.URL.RawQuery != "limit=2&prefix=node%2F&start=node%2Fb" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+
+		_, _ = w.Write([]byte(`{"prefix": "node/", "start": "node/b", "limit": 2, "partitionKeys": [
+			{"pk": "node/b", "entries": 3, "conflicts": 1, "values": 4, "bytes": 120},
+			{"pk": "node/c", "entries": 1, "conflicts": 0, "values": 1, "bytes": 10}
+		], "more": true, "nextStart": "node/d"}`))
+	}))
+	defer server.Close()
+
+	index, err := NewK2VClient(server.URL, "GKtest", "secret").ReadIndex(context.Background(), "meta", K2VIndexQuery{Prefix: "node/", Start: "node/b", Limit: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(index.PartitionKeys) != 2 || index.PartitionKeys[0].PK != "node/b" || index.PartitionKeys[0].Conflicts != 1 ||
+		!index.More || *index.NextStart != "node/d" {
+		t.Errorf("Unexpected index %+v", index)
+	}
+}
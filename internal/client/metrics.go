@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// MetricsClient is a client for the Prometheus metrics endpoint of Garage,
+// protected by the metrics token rather than the admin token.
+type MetricsClient struct {
+	endpoint   string
+	token      string
+	httpClient *http.Client
+}
+
+// NewMetricsClient creates a new metrics client. The token is not sent when
+// empty, for servers without metrics token.
+func NewMetricsClient(endpoint, token string) *MetricsClient {
+	return &MetricsClient{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		token:      token,
+		httpClient: http.DefaultClient,
+	}
+}
+
+// MetricSample is a sample of the Prometheus text exposition format.
+type MetricSample struct {
+	Name string
+	// Labels is the label set of the sample as written, without braces,
+	// empty when the sample has no labels.
+	Labels string
+	Value  float64
+	// Line is the line of the sample, e.g. for samples that are passed on
+	// as text.
+	Line string
+}
+
+// GetMetrics fetches /metrics and returns the exposition text.
+func (c *MetricsClient) GetMetrics(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+"/metrics", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newAPIError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return string(body), nil
+}
+
+// ParseMetrics returns the samples of text, in the Prometheus text
+// exposition format. Only the samples of the metrics named in names are
+// parsed, all of them when names is empty; the _bucket, _sum and _count
+// samples of a histogram or summary belong to its metric. Comments and lines
+// that cannot be parsed are skipped, so that new kinds of metrics do not
+// break parsing.
+func ParseMetrics(text string, names []string) []MetricSample {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	var samples []MetricSample
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		sample, ok := parseMetricSample(line)
+		if !ok || (len(wanted) > 0 && !wanted[sample.Name] && !wanted[metricFamilyName(sample.Name)]) {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+
+	return samples
+}
+
+// parseMetricSample parses a sample line: a metric name, an optional label
+// set in braces, a value and an optional timestamp.
+func parseMetricSample(line string) (MetricSample, bool) {
+	sample := MetricSample{Line: line}
+
+	end := strings.IndexAny(line, "{ \t")
+	if end <= 0 {
+		return sample, false
+	}
+	sample.Name = line[:end]
+	rest := line[end:]
+
+	if strings.HasPrefix(rest, "{") {
+		closing := labelSetEnd(rest)
+		if closing < 0 {
+			return sample, false
+		}
+		sample.Labels = rest[1:closing]
+		rest = rest[closing+1:]
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) < 1 || len(fields) > 2 {
+		return sample, false
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return sample, false
+	}
+	sample.Value = value
+
+	return sample, true
+}
+
+// labelSetEnd returns the index of the brace closing the label set starting
+// s, skipping the braces in quoted label values, or -1.
+func labelSetEnd(s string) int {
+	quoted := false
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quoted && s[i] == '\\':
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && s[i] == '}':
+			return i
+		}
+	}
+	return -1
+}
+
+// metricFamilyName returns the name of the histogram or summary a sample
+// name such as foo_bucket belongs to, or name itself.
+func metricFamilyName(name string) string {
+	for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+		if family, ok := strings.CutSuffix(name, suffix); ok {
+			return family
+		}
+	}
+	return name
+}
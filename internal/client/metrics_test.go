@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testMetrics = `# HELP cluster_healthy Whether all storage nodes are connected
+# TYPE cluster_healthy gauge
+cluster_healthy 1
+# TYPE block_resync_queue_length gauge
+block_resync_queue_length 42
+# TYPE rpc_netapp_error_counter counter
+rpc_netapp_error_counter{rpc_endpoint="garage_block/manager.rs/Rpc",to="6b3a"} 3
+# TYPE api_s3_request_duration histogram
+api_s3_request_duration_bucket{api_endpoint="GetObject",le="0.5"} 10
+api_s3_request_duration_bucket{api_endpoint="GetObject",le="+Inf"} 12
+api_s3_request_duration_sum{api_endpoint="GetObject"} 1.5
+api_s3_request_duration_count{api_endpoint="GetObject"} 12
+odd_labels{path="a}b \"c\" d"} 7 1700000000000
+table_gc_todo_queue_length NaN
+some_future_metric +Inf
+not a sample line
+`
+
+func TestParseMetrics(t *testing.T) {
+	samples := ParseMetrics(testMetrics, nil)
+
+	byLine := map[string]MetricSample{}
+	for _, sample := range samples {
+		byLine[sample.Line] = sample
+	}
+	if len(samples) != 10 {
+		t.Fatalf("Expected 10 samples, got %d: %+v", len(samples), samples)
+	}
+
+	if s := byLine["cluster_healthy 1"]; s.Name != "cluster_healthy" || s.Labels != "" || s.Value != 1 {
+		t.Errorf("Unexpected sample %+v", s)
+	}
+	if s := byLine[`rpc_netapp_error_counter{rpc_endpoint="garage_block/manager.rs/Rpc",to="6b3a"} 3`]; s.Name != "rpc_netapp_error_counter" ||
+		s.Labels != `rpc_endpoint="garage_block/manager.rs/Rpc",to="6b3a"` || s.Value != 3 {
+		t.Errorf("Unexpected labelled sample %+v", s)
+	}
+	if s := byLine[`odd_labels{path="a}b \"c\" d"} 7 1700000000000`]; s.Name != "odd_labels" || s.Labels != `path="a}b \"c\" d"` || s.Value != 7 {
+		t.Errorf("Expected braces and quotes in label values to be skipped, got %+v", s)
+	}
+	if s := byLine["table_gc_todo_queue_length NaN"]; !math.IsNaN(s.Value) {
+		t.Errorf("Expected NaN, got %+v", s)
+	}
+	if s := byLine["some_future_metric +Inf"]; !math.IsInf(s.Value, 1) {
+		t.Errorf("Expected +Inf, got %+v", s)
+	}
+}
+
+func TestParseMetrics_names(t *testing.T) {
+	samples := ParseMetrics(testMetrics, []string{"block_resync_queue_length", "api_s3_request_duration"})
+
+	var names []string
+	for _, sample := range samples {
+		names = append(names, sample.Name)
+	}
+	expected := []string{
+		"block_resync_queue_length",
+		"api_s3_request_duration_bucket", "api_s3_request_duration_bucket",
+		"api_s3_request_duration_sum", "api_s3_request_duration_count",
+	}
+	if len(names) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, names)
+			break
+		}
+	}
+}
+
+func TestGetMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" {
+			t.Errorf("Expected /metrics, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer metrics-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(testMetrics))
+	}))
+	defer server.Close()
+
+	text, err := NewMetricsClient(server.URL+"/", "metrics-token").GetMetrics(context.Background())
+	if err != nil || text != testMetrics {
+		t.Errorf("Expected the metrics, got %q and %v", text, err)
+	}
+
+	_, err = NewMetricsClient(server.URL, "").GetMetrics(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden || apiErr.Path != "/metrics" {
+		t.Errorf("Expected a 403 API error, got %v", err)
+	}
+}
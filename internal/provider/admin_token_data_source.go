@@ -0,0 +1,239 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AdminTokenDataSource{}
+
+func NewAdminTokenDataSource() datasource.DataSource {
+	return &AdminTokenDataSource{}
+}
+
+// AdminTokenDataSource defines the data source implementation.
+type AdminTokenDataSource struct {
+	client *client.Client
+}
+
+// AdminTokenDataSourceModel describes the data source data model. It is also
+// the model of the tokens of garage_admin_tokens.
+type AdminTokenDataSourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	Name       types.String `tfsdk:"name"`
+	Scope      types.List   `tfsdk:"scope"`
+	Created    types.String `tfsdk:"created"`
+	Expiration types.String `tfsdk:"expiration"`
+	Expired    types.Bool   `tfsdk:"expired"`
+}
+
+// adminTokenAttrTypes are the attribute types of a token object.
+var adminTokenAttrTypes = map[string]attr.Type{
+	"id":         types.StringType,
+	"name":       types.StringType,
+	"scope":      types.ListType{ElemType: types.StringType},
+	"created":    types.StringType,
+	"expiration": types.StringType,
+	"expired":    types.BoolType,
+}
+
+func (d *AdminTokenDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_admin_token"
+}
+
+func (d *AdminTokenDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Retrieves information about a Garage admin API token. The secret of the token is never returned. Requires Garage v2.0 or later.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The identifier of the token. Exactly one of `id` or `name` must be specified.",
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("name")),
+				},
+			},
+			"name": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The name of the token. Exactly one of `id` or `name` must be specified. Looking up a name shared by several tokens fails.",
+			},
+			"scope": schema.ListAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The admin API endpoints the token may call, `*` for all of them.",
+			},
+			"created": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The creation date of the token, in RFC 3339 format.",
+			},
+			"expiration": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The expiration date of the token, in RFC 3339 format. Null when the token never expires.",
+			},
+			"expired": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the token has expired.",
+			},
+		},
+	}
+}
+
+func (d *AdminTokenDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	var diags diag.Diagnostics
+	d.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
+}
+
+func (d *AdminTokenDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AdminTokenDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Reading admin token data source", map[string]interface{}{
+		"id":   data.ID.ValueString(),
+		"name": data.Name.ValueString(),
+	})
+
+	tokens, diags := listAdminTokens(ctx, d.client)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	token, err := findAdminToken(tokens, data.ID, data.Name)
+	if err != nil {
+		resp.Diagnostics.AddError("Admin Token Lookup Failed", fmt.Sprintf("Unable to find the admin token: %s.", err))
+		return
+	}
+
+	data = adminTokenModel(*token)
+
+	tflog.Trace(ctx, "Read admin token data source")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// listAdminTokens lists the admin tokens, turning a server without admin
+// tokens into a diagnostic explaining the version requirement.
+func listAdminTokens(ctx context.Context, c *client.Client) ([]client.AdminToken, diag.Diagnostics) {
+	diags := requireGarageCapability(ctx, c, capabilityAdminTokens)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	tokens, err := c.ListAdminTokens(ctx)
+	if errors.Is(err, client.ErrUnsupportedEndpoint) {
+		diags.AddError(
+			"Admin Tokens Not Supported",
+			fmt.Sprintf("Admin tokens require the v2 admin API of Garage v2.0 or later, which this server does not provide: %s", err),
+		)
+		return nil, diags
+	}
+	if err != nil {
+		diags.Append(diagFromClientError("Unable to list admin tokens", err))
+		return nil, diags
+	}
+
+	return tokens, diags
+}
+
+// findAdminToken returns the token with the given ID, or else the only token
+// with the given name.
+func findAdminToken(tokens []client.AdminToken, id, name types.String) (*client.AdminToken, error) {
+	if !id.IsNull() {
+		for i, token := range tokens {
+			if token.ID != nil && *token.ID == id.ValueString() {
+				return &tokens[i], nil
+			}
+		}
+		return nil, fmt.Errorf("no admin token with ID %q exists on this cluster", id.ValueString())
+	}
+
+	var matches []client.AdminToken
+	for _, token := range tokens {
+		if token.Name == name.ValueString() {
+			matches = append(matches, token)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no admin token named %q exists on this cluster", name.ValueString())
+	case 1:
+		return &matches[0], nil
+	}
+
+	candidates := make([]string, 0, len(matches))
+	for _, token := range matches {
+		candidates = append(candidates, adminTokenLabel(token))
+	}
+	sort.Strings(candidates)
+	return nil, fmt.Errorf("%d admin tokens are named %q, look one up by id instead: %s",
+		len(matches), name.ValueString(), strings.Join(candidates, ", "))
+}
+
+// adminTokenLabel describes token in error messages.
+func adminTokenLabel(token client.AdminToken) string {
+	if token.ID == nil {
+		return "the token of the server configuration file"
+	}
+	if token.Created == nil {
+		return *token.ID
+	}
+	return fmt.Sprintf("%s (created %s)", *token.ID, *token.Created)
+}
+
+// adminTokenModel converts a token reported by the API into its model.
+func adminTokenModel(token client.AdminToken) AdminTokenDataSourceModel {
+	scope := make([]attr.Value, 0, len(token.Scope))
+	for _, endpoint := range token.Scope {
+		scope = append(scope, types.StringValue(endpoint))
+	}
+
+	return AdminTokenDataSourceModel{
+		ID:         types.StringPointerValue(token.ID),
+		Name:       types.StringValue(token.Name),
+		Scope:      types.ListValueMust(types.StringType, scope),
+		Created:    types.StringPointerValue(token.Created),
+		Expiration: types.StringPointerValue(token.Expiration),
+		Expired:    types.BoolValue(token.Expired),
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+func TestAccAdminTokenDataSource_byName(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The token of the configuration file always exists
+			{
+				Config: testAccProviderConfig() + `
+data "garage_admin_tokens" "all" {}
+
+data "garage_admin_token" "test" {
+  name = data.garage_admin_tokens.all.tokens[0].name
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.garage_admin_token.test", "scope.#", "data.garage_admin_tokens.all", "tokens.0.scope.#"),
+					resource.TestCheckResourceAttrSet("data.garage_admin_token.test", "expired"),
+				),
+			},
+		},
+	})
+}
+
+func TestFindAdminToken(t *testing.T) {
+	id := func(s string) *string { return &s }
+	tokens := []client.AdminToken{
+		{Name: "admin_token (from daemon configuration)"},
+		{ID: id("a1"), Name: "ci", Created: id("2025-01-01T00:00:00Z")},
+		{ID: id("b2"), Name: "ci", Created: id("2025-02-01T00:00:00Z")},
+		{ID: id("c3"), Name: "monitoring"},
+	}
+
+	tests := []struct {
+		name    string
+		id      types.String
+		byName  types.String
+		wantID  string
+		wantErr string
+	}{
+		{name: "by id", id: types.StringValue("b2"), byName: types.StringNull(), wantID: "b2"},
+		{name: "by name", id: types.StringNull(), byName: types.StringValue("monitoring"), wantID: "c3"},
+		{name: "unknown id", id: types.StringValue("z9"), byName: types.StringNull(), wantErr: `no admin token with ID "z9"`},
+		{name: "unknown name", id: types.StringNull(), byName: types.StringValue("backup"), wantErr: `no admin token named "backup"`},
+		{
+			name:    "ambiguous name",
+			id:      types.StringNull(),
+			byName:  types.StringValue("ci"),
+			wantErr: `2 admin tokens are named "ci", look one up by id instead: a1 (created 2025-01-01T00:00:00Z), b2 (created 2025-02-01T00:00:00Z)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := findAdminToken(tokens, tt.id, tt.byName)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if *token.ID != tt.wantID {
+				t.Errorf("Expected token %s, got %s", tt.wantID, *token.ID)
+			}
+		})
+	}
+}
+
+func TestAdminTokenDataSource_unsupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Bad request: Unknown API endpoint"))
+	}))
+	defer server.Close()
+
+	d := &AdminTokenDataSource{client: client.NewClient(server.URL, "test-token")}
+	resp := testDataSourceRead(t, d, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "ci"),
+	})
+
+	if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != "Admin Tokens Not Supported" {
+		t.Errorf("Expected the version requirement error, got %v", resp.Diagnostics)
+	}
+}
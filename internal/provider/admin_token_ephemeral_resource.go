@@ -0,0 +1,237 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ ephemeral.EphemeralResourceWithConfigure = &AdminTokenEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithClose = &AdminTokenEphemeralResource{}
+
+const (
+	// defaultAdminTokenName is the name of the tokens created without one.
+	defaultAdminTokenName = "terraform-ephemeral"
+	// defaultAdminTokenTTL is the lifetime of the tokens created without a
+	// ttl, in case they are never deleted.
+	defaultAdminTokenTTL = "1h"
+	// adminTokenPrivateKey is the private data key holding the token ID
+	// between Open and Close.
+	adminTokenPrivateKey = "token_id"
+)
+
+func NewAdminTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &AdminTokenEphemeralResource{}
+}
+
+// AdminTokenEphemeralResource creates an admin API token when opened and
+// deletes it when closed. Ephemeral resources are never stored in the plan
+// nor the state, neither is the secret of the token.
+type AdminTokenEphemeralResource struct {
+	client *client.Client
+}
+
+// AdminTokenEphemeralResourceModel describes the ephemeral resource data
+// model.
+type AdminTokenEphemeralResourceModel struct {
+	Name       types.String `tfsdk:"name"`
+	Scope      types.List   `tfsdk:"scope"`
+	TTL        types.String `tfsdk:"ttl"`
+	ID         types.String `tfsdk:"id"`
+	Secret     types.String `tfsdk:"secret"`
+	Expiration types.String `tfsdk:"expiration"`
+}
+
+func (e *AdminTokenEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_admin_token"
+}
+
+func (e *AdminTokenEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Creates a Garage admin API token for the duration of a Terraform run and deletes it at the end of the run. " +
+			"The token and its secret are never stored in the plan nor the state. " +
+			"The token expires after `ttl` in case it cannot be deleted. Requires Garage v2.0 or later.",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The name of the token. Defaults to `" + defaultAdminTokenName + "`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"scope": schema.ListAttribute{
+				Required:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The admin API endpoints the token may call, e.g. `ListBuckets`, or `*` for all of them.",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(validators.AdminTokenScope()),
+				},
+			},
+			"ttl": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "How long the token stays valid if it is not deleted at the end of the run, as a Go duration (e.g. `30m`). Defaults to `" + defaultAdminTokenTTL + "`.",
+				Validators: []validator.String{
+					validators.DurationBetween(time.Minute, 7*24*time.Hour),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The identifier of the token.",
+			},
+			"secret": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The secret of the token, to send as a bearer token to the admin API.",
+			},
+			"expiration": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The expiration date of the token, in RFC 3339 format.",
+			},
+		},
+	}
+}
+
+func (e *AdminTokenEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	var diags diag.Diagnostics
+	e.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
+}
+
+func (e *AdminTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data AdminTokenEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Name.IsNull() {
+		data.Name = types.StringValue(defaultAdminTokenName)
+	}
+	if data.TTL.IsNull() {
+		data.TTL = types.StringValue(defaultAdminTokenTTL)
+	}
+
+	ttl, err := time.ParseDuration(data.TTL.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Duration", fmt.Sprintf("Unable to parse ttl %q: %s", data.TTL.ValueString(), err))
+		return
+	}
+
+	var scope []string
+	resp.Diagnostics.Append(data.Scope.ElementsAs(ctx, &scope, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(requireGarageCapability(ctx, e.client, capabilityAdminTokens)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	expiration := time.Now().Add(ttl).UTC().Format(time.RFC3339)
+
+	tflog.Debug(ctx, "Creating ephemeral admin token", map[string]interface{}{
+		"name":       data.Name.ValueString(),
+		"scope":      scope,
+		"expiration": expiration,
+	})
+
+	token, err := e.client.CreateAdminToken(ctx, client.CreateAdminTokenRequest{
+		Name:       data.Name.ValueString(),
+		Expiration: &expiration,
+		Scope:      scope,
+	})
+	if errors.Is(err, client.ErrUnsupportedEndpoint) {
+		resp.Diagnostics.AddError(
+			"Admin Tokens Not Supported",
+			fmt.Sprintf("Admin tokens require the v2 admin API of Garage v2.0 or later, which this server does not provide: %s", err),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError("Unable to create admin token", err))
+		return
+	}
+	if token.ID == nil {
+		resp.Diagnostics.AddError("Client Error", "The created admin token has no ID and could not be deleted.")
+		return
+	}
+
+	// Close only receives the private data, which is never persisted either
+	id, err := json.Marshal(*token.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to encode the admin token ID: %s", err))
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, adminTokenPrivateKey, id)...)
+
+	data.ID = types.StringPointerValue(token.ID)
+	data.Secret = types.StringValue(token.SecretToken)
+	data.Expiration = types.StringPointerValue(token.Expiration)
+
+	tflog.Trace(ctx, "Opened admin token ephemeral resource")
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+func (e *AdminTokenEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	data, diags := req.Private.GetKey(ctx, adminTokenPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || data == nil {
+		return
+	}
+
+	var id string
+	if err := json.Unmarshal(data, &id); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to decode the admin token ID: %s", err))
+		return
+	}
+
+	tflog.Debug(ctx, "Deleting ephemeral admin token", map[string]interface{}{
+		"id": id,
+	})
+
+	if err := e.client.DeleteAdminToken(ctx, id); err != nil {
+		resp.Diagnostics.Append(diagFromClientError(fmt.Sprintf("Unable to delete admin token %s, it stays valid until its expiration", id), err))
+		return
+	}
+
+	tflog.Trace(ctx, "Closed admin token ephemeral resource")
+}
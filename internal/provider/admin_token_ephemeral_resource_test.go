@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+func TestAccAdminTokenEphemeralResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesWithEcho,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig() + `
+ephemeral "garage_admin_token" "test" {
+  name  = "acc-ephemeral"
+  scope = ["ListBuckets"]
+  ttl   = "5m"
+}
+
+provider "echo" {
+  data = {
+    name  = ephemeral.garage_admin_token.test.name
+    scope = ephemeral.garage_admin_token.test.scope[0]
+  }
+}
+
+resource "echo" "test" {}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("name"), knownvalue.StringExact("acc-ephemeral")),
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("scope"), knownvalue.StringExact("ListBuckets")),
+				},
+			},
+		},
+	})
+}
+
+func TestAdminTokenEphemeralResource(t *testing.T) {
+	var created client.CreateAdminTokenRequest
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v2/GetNodeInfo":
+			_, _ = w.Write([]byte(`{"success": {"n1": {"nodeId": "n1", "garageVersion": "v2.1.0"}}, "error": {}}`))
+		case "/v2/CreateAdminToken":
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Errorf("Unexpected request body: %s", err)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"id": "a1", "name": created.Name, "created": "2025-01-01T00:00:00Z", "expiration": created.Expiration,
+				"expired": false, "scope": created.Scope, "secretToken": "a1.secret",
+			})
+		case "/v2/DeleteAdminToken":
+			deleted = append(deleted, r.URL.Query().Get("id"))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	e := &AdminTokenEphemeralResource{client: client.NewClient(server.URL, "test-token")}
+	before := time.Now()
+	resp := testEphemeralResourceOpen(t, e, map[string]tftypes.Value{
+		"scope": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "ListBuckets")}),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var result AdminTokenEphemeralResourceModel
+	resp.Diagnostics.Append(resp.Result.Get(context.Background(), &result)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if result.ID.ValueString() != "a1" || result.Secret.ValueString() != "a1.secret" || result.Name.ValueString() != defaultAdminTokenName {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	// The token expires after the default ttl in case it is never deleted
+	expiration, err := time.Parse(time.RFC3339, *created.Expiration)
+	if err != nil || expiration.Before(before.Add(59*time.Minute)) || expiration.After(time.Now().Add(time.Hour)) {
+		t.Errorf("Expected an expiration in 1h, got %s", *created.Expiration)
+	}
+	if created.NeverExpires || len(created.Scope) != 1 || created.Scope[0] != "ListBuckets" {
+		t.Errorf("Unexpected request %+v", created)
+	}
+
+	closeResp := &ephemeral.CloseResponse{}
+	e.Close(context.Background(), ephemeral.CloseRequest{Private: resp.Private}, closeResp)
+	if closeResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", closeResp.Diagnostics)
+	}
+	if len(deleted) != 1 || deleted[0] != "a1" {
+		t.Errorf("Expected token a1 to be deleted, got %v", deleted)
+	}
+}
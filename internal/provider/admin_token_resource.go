@@ -0,0 +1,318 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AdminTokenResource{}
+var _ resource.ResourceWithValidateConfig = &AdminTokenResource{}
+var _ resource.ResourceWithModifyPlan = &AdminTokenResource{}
+var _ resource.ResourceWithImportState = &AdminTokenResource{}
+
+func NewAdminTokenResource() resource.Resource {
+	return &AdminTokenResource{}
+}
+
+// AdminTokenResource defines the resource implementation.
+type AdminTokenResource struct {
+	client *client.Client
+}
+
+// AdminTokenResourceModel describes the resource data model.
+type AdminTokenResourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	Name       types.String `tfsdk:"name"`
+	Scope      types.List   `tfsdk:"scope"`
+	Expiration types.String `tfsdk:"expiration"`
+	Secret     types.String `tfsdk:"secret"`
+	Created    types.String `tfsdk:"created"`
+	Expired    types.Bool   `tfsdk:"expired"`
+}
+
+func (r *AdminTokenResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_admin_token"
+}
+
+func (r *AdminTokenResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a Garage admin API token. The name, scope and expiration are updated in place, keeping the secret used by running systems. " +
+			"The secret is only returned on creation and is stored in the state. Requires Garage v2.0 or later.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The identifier of the token.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The name of the token.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"scope": schema.ListAttribute{
+				Required:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The admin API endpoints the token may call, e.g. `ListBuckets`, or `*` for all of them. Endpoint names unknown to the provider produce a warning, as newer servers may have more endpoints.",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(validators.AdminTokenScope()),
+				},
+			},
+			"expiration": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The expiration date of the token, in RFC 3339 format (e.g. `2030-01-01T00:00:00Z`). The token never expires when not set.",
+			},
+			"secret": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The secret of the token, to send as a bearer token to the admin API.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"created": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The creation date of the token.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"expired": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the token has expired.",
+			},
+		},
+	}
+}
+
+func (r *AdminTokenResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	var diags diag.Diagnostics
+	r.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *AdminTokenResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var expiration types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("expiration"), &expiration)...)
+	if resp.Diagnostics.HasError() || expiration.IsNull() || expiration.IsUnknown() {
+		return
+	}
+
+	if _, err := time.Parse(time.RFC3339, expiration.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("expiration"),
+			"Invalid Expiration",
+			fmt.Sprintf("The expiration must be a date in RFC 3339 format, e.g. 2030-01-01T00:00:00Z: %s", err),
+		)
+	}
+}
+
+func (r *AdminTokenResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Report old servers at plan time rather than with the missing endpoint
+	// on apply. Existing tokens are on a server that supports them.
+	if req.Plan.Raw.IsNull() || !req.State.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(requireGarageCapability(ctx, r.client, capabilityAdminTokens)...)
+}
+
+func (r *AdminTokenResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data AdminTokenResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(requireGarageCapability(ctx, r.client, capabilityAdminTokens)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var scope []string
+	resp.Diagnostics.Append(data.Scope.ElementsAs(ctx, &scope, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Creating admin token", map[string]interface{}{
+		"name":  data.Name.ValueString(),
+		"scope": scope,
+	})
+
+	token, err := r.client.CreateAdminToken(ctx, client.CreateAdminTokenRequest{
+		Name:         data.Name.ValueString(),
+		Expiration:   data.Expiration.ValueStringPointer(),
+		NeverExpires: data.Expiration.IsNull(),
+		Scope:        scope,
+	})
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError("Unable to create admin token", err))
+		return
+	}
+	if token.ID == nil {
+		resp.Diagnostics.AddError("Client Error", "The created admin token has no ID.")
+		return
+	}
+
+	data.Secret = types.StringValue(token.SecretToken)
+	resp.Diagnostics.Append(adminTokenResourceState(ctx, &data, token.AdminToken)...)
+
+	tflog.Trace(ctx, "Created admin token resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AdminTokenResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AdminTokenResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	token, err := r.client.GetAdminTokenInfo(ctx, data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError("Unable to read admin token", err))
+		return
+	}
+
+	if token == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Changes made on the server, such as a narrowed scope, show as drift
+	resp.Diagnostics.Append(adminTokenResourceState(ctx, &data, *token)...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AdminTokenResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data AdminTokenResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var scope []string
+	resp.Diagnostics.Append(data.Scope.ElementsAs(ctx, &scope, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Updating admin token", map[string]interface{}{
+		"id":    data.ID.ValueString(),
+		"scope": scope,
+	})
+
+	// Updated in place: replacing the token would rotate its secret
+	token, err := r.client.UpdateAdminToken(ctx, data.ID.ValueString(), client.UpdateAdminTokenRequest{
+		Name:         data.Name.ValueStringPointer(),
+		Expiration:   data.Expiration.ValueStringPointer(),
+		NeverExpires: data.Expiration.IsNull(),
+		Scope:        scope,
+	})
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError("Unable to update admin token", err))
+		return
+	}
+
+	resp.Diagnostics.Append(adminTokenResourceState(ctx, &data, *token)...)
+
+	tflog.Trace(ctx, "Updated admin token resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AdminTokenResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data AdminTokenResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteAdminToken(ctx, data.ID.ValueString()); err != nil {
+		resp.Diagnostics.Append(diagFromClientError("Unable to delete admin token", err))
+		return
+	}
+}
+
+func (r *AdminTokenResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// adminTokenResourceState sets the attributes of data from token, except the
+// secret. An expiration equal to the one of data in another format is kept
+// as is.
+func adminTokenResourceState(ctx context.Context, data *AdminTokenResourceModel, token client.AdminToken) diag.Diagnostics {
+	scope, diags := types.ListValueFrom(ctx, types.StringType, token.Scope)
+
+	data.ID = types.StringPointerValue(token.ID)
+	data.Name = types.StringValue(token.Name)
+	data.Scope = scope
+	data.Created = types.StringPointerValue(token.Created)
+	data.Expired = types.BoolValue(token.Expired)
+	if !sameInstant(data.Expiration, token.Expiration) {
+		data.Expiration = types.StringPointerValue(token.Expiration)
+	}
+
+	return diags
+}
+
+// sameInstant reports whether value and other are the same RFC 3339 date,
+// possibly formatted differently.
+func sameInstant(value types.String, other *string) bool {
+	if value.IsNull() || value.IsUnknown() || other == nil {
+		return value.IsNull() && other == nil
+	}
+
+	a, errA := time.Parse(time.RFC3339, value.ValueString())
+	b, errB := time.Parse(time.RFC3339, *other)
+	return errA == nil && errB == nil && a.Equal(b)
+}
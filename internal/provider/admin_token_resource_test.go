@@ -0,0 +1,157 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+func TestAccAdminTokenResource(t *testing.T) {
+	var mu sync.Mutex
+	var token *client.AdminToken
+	var updates int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v2/GetNodeInfo":
+			_, _ = w.Write([]byte(`{"success": {"n1": {"nodeId": "n1", "garageVersion": "v2.1.0"}}, "error": {}}`))
+		case "/v2/CreateAdminToken":
+			var req client.CreateAdminTokenRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("Unexpected request body: %s", err)
+			}
+			id, created := "a1", "2025-01-01T00:00:00Z"
+			token = &client.AdminToken{ID: &id, Name: req.Name, Created: &created, Expiration: req.Expiration, Scope: req.Scope}
+			_ = json.NewEncoder(w).Encode(client.CreatedAdminToken{AdminToken: *token, SecretToken: "a1.secret"})
+		case "/v2/GetAdminTokenInfo":
+			if token == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(token)
+		case "/v2/UpdateAdminToken":
+			var req client.UpdateAdminTokenRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("Unexpected request body: %s", err)
+			}
+			updates++
+			token.Name = *req.Name
+			token.Scope = req.Scope
+			token.Expiration = req.Expiration
+			_ = json.NewEncoder(w).Encode(token)
+		case "/v2/DeleteAdminToken":
+			token = nil
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccAdminTokenResourceConfig(server.URL, `""`, ""),
+				ExpectError: regexp.MustCompile(`Invalid Admin Token Scope`),
+			},
+			{
+				Config:      testAccAdminTokenResourceConfig(server.URL, `"ListBuckets"`, `expiration = "tomorrow"`),
+				ExpectError: regexp.MustCompile(`Invalid Expiration`),
+			},
+			{
+				Config: testAccAdminTokenResourceConfig(server.URL, `"ListBuckets"`, `expiration = "2030-01-01T00:00:00Z"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_admin_token.test", "id", "a1"),
+					resource.TestCheckResourceAttr("garage_admin_token.test", "secret", "a1.secret"),
+					resource.TestCheckResourceAttr("garage_admin_token.test", "scope.#", "1"),
+					resource.TestCheckResourceAttr("garage_admin_token.test", "expiration", "2030-01-01T00:00:00Z"),
+				),
+			},
+			// The scope is widened in place, keeping the secret
+			{
+				Config: testAccAdminTokenResourceConfig(server.URL, `"ListBuckets", "GetBucketInfo"`, ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_admin_token.test", "id", "a1"),
+					resource.TestCheckResourceAttr("garage_admin_token.test", "secret", "a1.secret"),
+					resource.TestCheckResourceAttr("garage_admin_token.test", "scope.1", "GetBucketInfo"),
+					resource.TestCheckNoResourceAttr("garage_admin_token.test", "expiration"),
+				),
+			},
+			// A scope changed outside of Terraform is restored
+			{
+				PreConfig: func() {
+					mu.Lock()
+					defer mu.Unlock()
+					token.Scope = []string{"*"}
+				},
+				Config: testAccAdminTokenResourceConfig(server.URL, `"ListBuckets", "GetBucketInfo"`, ""),
+				Check: func(*terraform.State) error {
+					mu.Lock()
+					defer mu.Unlock()
+					if updates != 2 || len(token.Scope) != 2 {
+						return fmt.Errorf("expected the scope to be restored by a second update, got %d updates and %v", updates, token.Scope)
+					}
+					return nil
+				},
+			},
+			{
+				ResourceName:            "garage_admin_token.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"secret"},
+			},
+		},
+	})
+}
+
+func TestSameInstant(t *testing.T) {
+	utc := "2030-01-01T00:00:00Z"
+	offset := "2030-01-01T01:00:00+01:00"
+	other := "2030-01-02T00:00:00Z"
+
+	tests := []struct {
+		value    types.String
+		other    *string
+		expected bool
+	}{
+		{types.StringNull(), nil, true},
+		{types.StringNull(), &utc, false},
+		{types.StringValue(utc), nil, false},
+		{types.StringValue(utc), &utc, true},
+		{types.StringValue(utc), &offset, true},
+		{types.StringValue(utc), &other, false},
+		{types.StringValue("tomorrow"), &utc, false},
+	}
+
+	for _, test := range tests {
+		if got := sameInstant(test.value, test.other); got != test.expected {
+			t.Errorf("sameInstant(%s, %v): expected %t, got %t", test.value, test.other, test.expected, got)
+		}
+	}
+}
+
+func testAccAdminTokenResourceConfig(adminEndpoint, scope, extra string) string {
+	return testAccMockProviderConfig(adminEndpoint) + fmt.Sprintf(`
+resource "garage_admin_token" "test" {
+  name  = "ci"
+  scope = [%s]
+  %s
+}
+`, scope, extra)
+}
@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AdminTokensDataSource{}
+
+func NewAdminTokensDataSource() datasource.DataSource {
+	return &AdminTokensDataSource{}
+}
+
+// AdminTokensDataSource defines the data source implementation.
+type AdminTokensDataSource struct {
+	client *client.Client
+}
+
+// AdminTokensDataSourceModel describes the data source data model.
+type AdminTokensDataSourceModel struct {
+	Tokens types.List `tfsdk:"tokens"`
+}
+
+func (d *AdminTokensDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_admin_tokens"
+}
+
+func (d *AdminTokensDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists every Garage admin API token, for auditing. The secrets of the tokens are never returned. Requires Garage v2.0 or later.",
+
+		Attributes: map[string]schema.Attribute{
+			"tokens": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "The admin tokens, sorted by name then ID.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The identifier of the token. Null for the token of the server configuration file.",
+						},
+						"name": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The name of the token.",
+						},
+						"scope": schema.ListAttribute{
+							Computed:            true,
+							ElementType:         types.StringType,
+							MarkdownDescription: "The admin API endpoints the token may call, `*` for all of them.",
+						},
+						"created": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The creation date of the token, in RFC 3339 format.",
+						},
+						"expiration": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The expiration date of the token, in RFC 3339 format. Null when the token never expires.",
+						},
+						"expired": schema.BoolAttribute{
+							Computed:            true,
+							MarkdownDescription: "Whether the token has expired.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AdminTokensDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	var diags diag.Diagnostics
+	d.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
+}
+
+func (d *AdminTokensDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AdminTokensDataSourceModel
+
+	tflog.Debug(ctx, "Reading admin tokens data source")
+
+	tokens, diags := listAdminTokens(ctx, d.client)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sort.SliceStable(tokens, func(i, j int) bool {
+		if tokens[i].Name != tokens[j].Name {
+			return tokens[i].Name < tokens[j].Name
+		}
+		// The token of the configuration file, without ID, comes first
+		return tokens[j].ID != nil && (tokens[i].ID == nil || *tokens[i].ID < *tokens[j].ID)
+	})
+
+	models := make([]AdminTokenDataSourceModel, 0, len(tokens))
+	for _, token := range tokens {
+		models = append(models, adminTokenModel(token))
+	}
+
+	list, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: adminTokenAttrTypes}, models)
+	resp.Diagnostics.Append(diags...)
+	data.Tokens = list
+
+	tflog.Trace(ctx, "Read admin tokens data source")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
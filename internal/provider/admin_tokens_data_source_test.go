@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+func TestAdminTokensDataSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"id": "b2", "name": "ci", "created": "2025-02-01T00:00:00Z", "expiration": null, "expired": false, "scope": ["*"]},
+			{"id": "c3", "name": "monitoring", "created": "2025-03-01T00:00:00Z", "expiration": "2025-04-01T00:00:00Z", "expired": true, "scope": ["Metrics"]},
+			{"id": "a1", "name": "ci", "created": "2025-01-01T00:00:00Z", "expiration": null, "expired": false, "scope": ["*"]},
+			{"id": null, "name": "admin_token (from daemon configuration)", "created": null, "expiration": null, "expired": false, "scope": ["*"]}
+		]`))
+	}))
+	defer server.Close()
+
+	d := &AdminTokensDataSource{client: client.NewClient(server.URL, "test-token")}
+	resp := testDataSourceRead(t, d, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var tokens []AdminTokenDataSourceModel
+	resp.Diagnostics.Append(resp.State.GetAttribute(context.Background(), path.Root("tokens"), &tokens)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var ids []types.String
+	for _, token := range tokens {
+		ids = append(ids, token.ID)
+	}
+	expected := []types.String{types.StringNull(), types.StringValue("a1"), types.StringValue("b2"), types.StringValue("c3")}
+	if len(ids) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %v", len(expected), ids)
+	}
+	for i := range expected {
+		if !ids[i].Equal(expected[i]) {
+			t.Errorf("Expected tokens sorted as %v, got %v", expected, ids)
+			break
+		}
+	}
+	if last := tokens[3]; !last.Expired.ValueBool() || last.Expiration.ValueString() != "2025-04-01T00:00:00Z" {
+		t.Errorf("Unexpected token %+v", last)
+	}
+}
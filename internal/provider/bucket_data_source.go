@@ -7,8 +7,10 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
@@ -40,6 +42,22 @@ type BucketDataSourceModel struct {
 	Objects           types.Int64  `tfsdk:"objects"`
 	Bytes             types.Int64  `tfsdk:"bytes"`
 	UnfinishedUploads types.Int64  `tfsdk:"unfinished_uploads"`
+	Website           types.Object `tfsdk:"website"`
+	Quotas            types.Object `tfsdk:"quotas"`
+}
+
+// bucketWebsiteAttrTypes are the attribute types of the website object of
+// the bucket data source.
+var bucketWebsiteAttrTypes = map[string]attr.Type{
+	"index_document": types.StringType,
+	"error_document": types.StringType,
+}
+
+// bucketQuotasAttrTypes are the attribute types of the quotas object of the
+// bucket data source.
+var bucketQuotasAttrTypes = map[string]attr.Type{
+	"max_size":    types.Int64Type,
+	"max_objects": types.Int64Type,
 }
 
 func (d *BucketDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -68,23 +86,51 @@ func (d *BucketDataSource) Schema(ctx context.Context, req datasource.SchemaRequ
 			},
 			"website_enabled": schema.BoolAttribute{
 				Computed:            true,
-				MarkdownDescription: "Whether website hosting is enabled for this bucket.",
+				MarkdownDescription: "Whether website hosting is enabled for this bucket. Deprecated: use `website`, null when website hosting is disabled.",
 			},
 			"website_index_document": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "The index document for website hosting.",
+				MarkdownDescription: "The index document for website hosting. Deprecated: use `website.index_document`.",
 			},
 			"website_error_document": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "The error document for website hosting.",
+				MarkdownDescription: "The error document for website hosting. Deprecated: use `website.error_document`.",
 			},
 			"max_size": schema.Int64Attribute{
 				Computed:            true,
-				MarkdownDescription: "Maximum size of the bucket in bytes.",
+				MarkdownDescription: "Maximum size of the bucket in bytes. Deprecated: use `quotas.max_size`.",
 			},
 			"max_objects": schema.Int64Attribute{
 				Computed:            true,
-				MarkdownDescription: "Maximum number of objects in the bucket.",
+				MarkdownDescription: "Maximum number of objects in the bucket. Deprecated: use `quotas.max_objects`.",
+			},
+			"website": schema.SingleNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "The website hosting configuration of the bucket. Null when website hosting is disabled.",
+				Attributes: map[string]schema.Attribute{
+					"index_document": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "The index document for website hosting.",
+					},
+					"error_document": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "The error document for website hosting. Null when not set.",
+					},
+				},
+			},
+			"quotas": schema.SingleNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "The quotas of the bucket. Null when the bucket has no quota.",
+				Attributes: map[string]schema.Attribute{
+					"max_size": schema.Int64Attribute{
+						Computed:            true,
+						MarkdownDescription: "Maximum size of the bucket in bytes. Null when not limited.",
+					},
+					"max_objects": schema.Int64Attribute{
+						Computed:            true,
+						MarkdownDescription: "Maximum number of objects in the bucket. Null when not limited.",
+					},
+				},
 			},
 			"objects": schema.Int64Attribute{
 				Computed:            true,
@@ -116,24 +162,9 @@ func (d *BucketDataSource) Configure(ctx context.Context, req datasource.Configu
 		return
 	}
 
-	// Get admin endpoint with backwards compatibility
-	adminEndpoint := ""
-	if providerData.Endpoints != nil && !providerData.Endpoints.Admin.IsNull() {
-		adminEndpoint = providerData.Endpoints.Admin.ValueString()
-	} else if !providerData.Endpoint.IsNull() {
-		// Fallback to deprecated endpoint
-		adminEndpoint = providerData.Endpoint.ValueString()
-	}
-
-	if adminEndpoint == "" {
-		resp.Diagnostics.AddError(
-			"Missing Admin Endpoint",
-			"Admin endpoint must be configured via 'endpoints.admin' or deprecated 'endpoint' attribute",
-		)
-		return
-	}
-
-	d.client = client.NewClient(adminEndpoint, providerData.Token.ValueString())
+	var diags diag.Diagnostics
+	d.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
 }
 
 func (d *BucketDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -175,7 +206,7 @@ func (d *BucketDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	// Fetch bucket info
 	bucket, err := d.client.GetBucketInfo(ctx, getBucketReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read bucket, got error: %s", err))
+		resp.Diagnostics.Append(diagFromClientError("Unable to read bucket", err))
 		return
 	}
 
@@ -207,32 +238,7 @@ func (d *BucketDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		data.GlobalAliases = aliasList
 	}
 
-	data.WebsiteEnabled = types.BoolValue(bucket.WebsiteAccess)
-
-	if bucket.WebsiteConfig != nil {
-		data.WebsiteIndex = types.StringValue(bucket.WebsiteConfig.IndexDocument)
-		data.WebsiteError = types.StringValue(bucket.WebsiteConfig.ErrorDocument)
-	} else {
-		data.WebsiteIndex = types.StringNull()
-		data.WebsiteError = types.StringNull()
-	}
-
-	if bucket.Quotas != nil {
-		if bucket.Quotas.MaxSize != nil {
-			data.MaxSize = types.Int64Value(*bucket.Quotas.MaxSize)
-		} else {
-			data.MaxSize = types.Int64Null()
-		}
-
-		if bucket.Quotas.MaxObjects != nil {
-			data.MaxObjects = types.Int64Value(*bucket.Quotas.MaxObjects)
-		} else {
-			data.MaxObjects = types.Int64Null()
-		}
-	} else {
-		data.MaxSize = types.Int64Null()
-		data.MaxObjects = types.Int64Null()
-	}
+	setBucketDataSourceSettings(&data, bucket)
 
 	data.Objects = types.Int64Value(bucket.Objects)
 	data.Bytes = types.Int64Value(bucket.Bytes)
@@ -242,3 +248,40 @@ func (d *BucketDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+// setBucketDataSourceSettings sets the website and quota attributes of data
+// from bucket, both as nested objects and as the deprecated flat attributes.
+// The objects are null when website hosting is disabled and when the bucket
+// has no quota, rather than objects of nulls.
+func setBucketDataSourceSettings(data *BucketDataSourceModel, bucket *client.Bucket) {
+	data.WebsiteEnabled = types.BoolValue(bucket.WebsiteAccess)
+	data.WebsiteIndex = types.StringNull()
+	data.WebsiteError = types.StringNull()
+	if bucket.WebsiteConfig != nil {
+		data.WebsiteIndex = types.StringValue(bucket.WebsiteConfig.IndexDocument)
+		data.WebsiteError = types.StringPointerValue(bucket.WebsiteConfig.ErrorDocument)
+	}
+
+	data.Website = types.ObjectNull(bucketWebsiteAttrTypes)
+	if bucket.WebsiteAccess {
+		data.Website = types.ObjectValueMust(bucketWebsiteAttrTypes, map[string]attr.Value{
+			"index_document": data.WebsiteIndex,
+			"error_document": data.WebsiteError,
+		})
+	}
+
+	data.MaxSize = types.Int64Null()
+	data.MaxObjects = types.Int64Null()
+	if bucket.Quotas != nil {
+		data.MaxSize = types.Int64PointerValue(bucket.Quotas.MaxSize)
+		data.MaxObjects = types.Int64PointerValue(bucket.Quotas.MaxObjects)
+	}
+
+	data.Quotas = types.ObjectNull(bucketQuotasAttrTypes)
+	if !data.MaxSize.IsNull() || !data.MaxObjects.IsNull() {
+		data.Quotas = types.ObjectValueMust(bucketQuotasAttrTypes, map[string]attr.Value{
+			"max_size":    data.MaxSize,
+			"max_objects": data.MaxObjects,
+		})
+	}
+}
@@ -7,7 +7,11 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
 )
 
 func TestAccBucketDataSource_byAlias(t *testing.T) {
@@ -64,6 +68,9 @@ func TestAccBucketDataSource_withWebsite(t *testing.T) {
 					resource.TestCheckResourceAttr("data.garage_bucket.test", "website_enabled", "true"),
 					resource.TestCheckResourceAttr("data.garage_bucket.test", "website_index_document", "index.html"),
 					resource.TestCheckResourceAttr("data.garage_bucket.test", "website_error_document", "error.html"),
+					resource.TestCheckResourceAttrPair("data.garage_bucket.test", "website.index_document", "data.garage_bucket.test", "website_index_document"),
+					resource.TestCheckResourceAttrPair("data.garage_bucket.test", "website.error_document", "data.garage_bucket.test", "website_error_document"),
+					resource.TestCheckNoResourceAttr("data.garage_bucket.test", "quotas"),
 				),
 			},
 		},
@@ -81,6 +88,9 @@ func TestAccBucketDataSource_withQuotas(t *testing.T) {
 					resource.TestCheckResourceAttr("data.garage_bucket.test", "global_alias", "test-bucket-datasource-quotas"),
 					resource.TestCheckResourceAttr("data.garage_bucket.test", "max_size", "1073741824"),
 					resource.TestCheckResourceAttr("data.garage_bucket.test", "max_objects", "10000"),
+					resource.TestCheckResourceAttrPair("data.garage_bucket.test", "quotas.max_size", "data.garage_bucket.test", "max_size"),
+					resource.TestCheckResourceAttrPair("data.garage_bucket.test", "quotas.max_objects", "data.garage_bucket.test", "max_objects"),
+					resource.TestCheckNoResourceAttr("data.garage_bucket.test", "website"),
 				),
 			},
 		},
@@ -200,3 +210,76 @@ data "garage_bucket" "test" {
 }
 `, name)
 }
+
+func TestSetBucketDataSourceSettings(t *testing.T) {
+	errorDocument := "error.html"
+	maxSize, maxObjects := int64(1073741824), int64(10000)
+
+	tests := []struct {
+		name        string
+		bucket      client.Bucket
+		wantWebsite bool
+		wantQuotas  bool
+	}{
+		{name: "no website and no quota", bucket: client.Bucket{}},
+		{
+			name:   "disabled website with a leftover configuration",
+			bucket: client.Bucket{WebsiteConfig: &client.WebsiteConfig{IndexDocument: "index.html"}},
+		},
+		{
+			name:        "website without error document",
+			bucket:      client.Bucket{WebsiteAccess: true, WebsiteConfig: &client.WebsiteConfig{IndexDocument: "index.html"}},
+			wantWebsite: true,
+		},
+		{
+			name: "website with error document",
+			bucket: client.Bucket{
+				WebsiteAccess: true,
+				WebsiteConfig: &client.WebsiteConfig{IndexDocument: "index.html", ErrorDocument: &errorDocument},
+			},
+			wantWebsite: true,
+		},
+		{name: "empty quotas", bucket: client.Bucket{Quotas: &client.BucketQuotas{}}},
+		{name: "size quota", bucket: client.Bucket{Quotas: &client.BucketQuotas{MaxSize: &maxSize}}, wantQuotas: true},
+		{
+			name:       "both quotas",
+			bucket:     client.Bucket{Quotas: &client.BucketQuotas{MaxSize: &maxSize, MaxObjects: &maxObjects}},
+			wantQuotas: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data BucketDataSourceModel
+			setBucketDataSourceSettings(&data, &tt.bucket)
+
+			if data.Website.IsNull() == tt.wantWebsite || data.WebsiteEnabled.ValueBool() != tt.wantWebsite {
+				t.Errorf("Expected website %t, got %s and website_enabled %s", tt.wantWebsite, data.Website, data.WebsiteEnabled)
+			}
+			if tt.wantWebsite {
+				expected := types.ObjectValueMust(bucketWebsiteAttrTypes, map[string]attr.Value{
+					"index_document": data.WebsiteIndex,
+					"error_document": data.WebsiteError,
+				})
+				if !data.Website.Equal(expected) {
+					t.Errorf("Expected website to agree with the flat attributes %s, got %s", expected, data.Website)
+				}
+			}
+
+			if data.Quotas.IsNull() == tt.wantQuotas {
+				t.Errorf("Expected quotas %t, got %s", tt.wantQuotas, data.Quotas)
+			}
+			if tt.wantQuotas {
+				expected := types.ObjectValueMust(bucketQuotasAttrTypes, map[string]attr.Value{
+					"max_size":    data.MaxSize,
+					"max_objects": data.MaxObjects,
+				})
+				if !data.Quotas.Equal(expected) || data.MaxSize.ValueInt64() != maxSize {
+					t.Errorf("Expected quotas to agree with the flat attributes %s, got %s", expected, data.Quotas)
+				}
+			} else if !data.MaxSize.IsNull() || !data.MaxObjects.IsNull() {
+				t.Errorf("Expected null flat quotas, got %s and %s", data.MaxSize, data.MaxObjects)
+			}
+		})
+	}
+}
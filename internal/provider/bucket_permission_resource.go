@@ -6,30 +6,59 @@ package provider
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &BucketPermissionResource{}
 var _ resource.ResourceWithImportState = &BucketPermissionResource{}
+var _ resource.ResourceWithConfigValidators = &BucketPermissionResource{}
+var _ resource.ResourceWithIdentity = &BucketPermissionResource{}
+var _ resource.ResourceWithModifyPlan = &BucketPermissionResource{}
+var _ resource.ResourceWithUpgradeState = &BucketPermissionResource{}
 
 func NewBucketPermissionResource() resource.Resource {
 	return &BucketPermissionResource{}
 }
 
+// propagationPollInterval is the delay between two propagation checks.
+const propagationPollInterval = 500 * time.Millisecond
+
+// bucketPermissionFlagsDeprecation is the deprecation message of the flat
+// read, write and owner attributes.
+const bucketPermissionFlagsDeprecation = "Use the permissions attribute instead. " +
+	"The flat read, write and owner attributes will be removed in the next release."
+
 // BucketPermissionResource defines the resource implementation.
 type BucketPermissionResource struct {
 	client *client.Client
+
+	// Shared S3 client, only used to verify propagation with a signed
+	// HeadBucket when the provider credentials belong to the granted key.
+	s3Client  *s3.Client
+	accessKey string
 }
 
 // BucketPermissionResourceModel describes the resource data model.
@@ -40,6 +69,30 @@ type BucketPermissionResourceModel struct {
 	Read        types.Bool   `tfsdk:"read"`
 	Write       types.Bool   `tfsdk:"write"`
 	Owner       types.Bool   `tfsdk:"owner"`
+	Permissions types.Object `tfsdk:"permissions"`
+
+	WaitForPropagation types.String `tfsdk:"wait_for_propagation"`
+}
+
+// BucketPermissionFlagsModel describes the permissions object.
+type BucketPermissionFlagsModel struct {
+	Read  types.Bool `tfsdk:"read"`
+	Write types.Bool `tfsdk:"write"`
+	Owner types.Bool `tfsdk:"owner"`
+}
+
+// bucketPermissionFlagsAttrTypes are the attribute types of the permissions
+// object.
+var bucketPermissionFlagsAttrTypes = map[string]attr.Type{
+	"read":  types.BoolType,
+	"write": types.BoolType,
+	"owner": types.BoolType,
+}
+
+// BucketPermissionIdentityModel describes the resource identity.
+type BucketPermissionIdentityModel struct {
+	BucketID    types.String `tfsdk:"bucket_id"`
+	AccessKeyID types.String `tfsdk:"access_key_id"`
 }
 
 func (r *BucketPermissionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -48,12 +101,14 @@ func (r *BucketPermissionResource) Metadata(ctx context.Context, req resource.Me
 
 func (r *BucketPermissionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Manages permissions for an access key on a Garage S3 bucket.",
+		MarkdownDescription: "Manages permissions for an access key on a Garage S3 bucket. At least one of `permissions.read`, `permissions.write` or `permissions.owner` must be `true`; remove the resource to revoke all access.",
+		// Version 1 added the permissions object
+		Version: 1,
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "The unique identifier of the permission (format: bucket_id/access_key_id).",
+				MarkdownDescription: "The unique identifier of the permission, in the form `<bucket_id>/<access_key_id>`. It is stable across updates of the permission flags and is also the import ID.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -67,33 +122,201 @@ func (r *BucketPermissionResource) Schema(ctx context.Context, req resource.Sche
 			},
 			"access_key_id": schema.StringAttribute{
 				Required:            true,
-				MarkdownDescription: "The ID of the access key.",
+				MarkdownDescription: "The ID of the access key (`GK` followed by 24 hexadecimal characters).",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					validators.AccessKeyID(),
+				},
 			},
 			"read": schema.BoolAttribute{
 				Optional:            true,
 				Computed:            true,
 				Default:             booldefault.StaticBool(false),
-				MarkdownDescription: "Grant read permission to the access key.",
+				MarkdownDescription: "Grant read permission to the access key. Deprecated: use `permissions.read`. Computed from `permissions` when it is set.",
+				DeprecationMessage:  bucketPermissionFlagsDeprecation,
 			},
 			"write": schema.BoolAttribute{
 				Optional:            true,
 				Computed:            true,
 				Default:             booldefault.StaticBool(false),
-				MarkdownDescription: "Grant write permission to the access key.",
+				MarkdownDescription: "Grant write permission to the access key. Deprecated: use `permissions.write`. Computed from `permissions` when it is set.",
+				DeprecationMessage:  bucketPermissionFlagsDeprecation,
 			},
 			"owner": schema.BoolAttribute{
 				Optional:            true,
 				Computed:            true,
 				Default:             booldefault.StaticBool(false),
-				MarkdownDescription: "Grant owner permission to the access key.",
+				MarkdownDescription: "Grant owner permission to the access key. Deprecated: use `permissions.owner`. Computed from `permissions` when it is set.",
+				DeprecationMessage:  bucketPermissionFlagsDeprecation,
+			},
+			"permissions": schema.SingleNestedAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The permissions granted to the access key, like the `permissions` object of the admin API. Cannot be combined with the deprecated `read`, `write` and `owner` attributes, and is computed from them when they are used.",
+				Attributes: map[string]schema.Attribute{
+					"read": schema.BoolAttribute{
+						Optional:            true,
+						Computed:            true,
+						Default:             booldefault.StaticBool(false),
+						MarkdownDescription: "Grant read permission to the access key.",
+					},
+					"write": schema.BoolAttribute{
+						Optional:            true,
+						Computed:            true,
+						Default:             booldefault.StaticBool(false),
+						MarkdownDescription: "Grant write permission to the access key.",
+					},
+					"owner": schema.BoolAttribute{
+						Optional:            true,
+						Computed:            true,
+						Default:             booldefault.StaticBool(false),
+						MarkdownDescription: "Grant owner permission to the access key.",
+					},
+				},
+				Validators: []validator.Object{
+					objectvalidator.ConflictsWith(path.MatchRoot("read"), path.MatchRoot("write"), path.MatchRoot("owner")),
+				},
+			},
+			"wait_for_propagation": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("0s"),
+				MarkdownDescription: "Maximum time to wait after granting for the permission to become visible, as a Go duration (e.g. `30s`) of at most `1h`. When the provider S3 credentials belong to the granted key, a signed HeadBucket is also performed. Defaults to `0s` (no wait).",
+				Validators: []validator.String{
+					validators.DurationBetween(0, time.Hour),
+				},
+			},
+		},
+	}
+}
+
+func (r *BucketPermissionResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"bucket_id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The ID of the bucket.",
+			},
+			"access_key_id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The ID of the access key.",
+			},
+		},
+	}
+}
+
+func (r *BucketPermissionResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		validators.AtLeastOneTrue(
+			path.Root("permissions").AtName("read"), path.Root("permissions").AtName("write"), path.Root("permissions").AtName("owner"),
+			path.Root("read"), path.Root("write"), path.Root("owner"),
+		),
+	}
+}
+
+// ModifyPlan keeps the permissions object and the deprecated flat attributes
+// in sync: the shape that is not configured is planned from the other one.
+func (r *BucketPermissionResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var config, plan BucketPermissionResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch {
+	case config.Permissions.IsNull():
+		plan.Permissions = bucketPermissionFlagsObject(plan.Read, plan.Write, plan.Owner)
+	case plan.Permissions.IsUnknown():
+		plan.Read, plan.Write, plan.Owner = types.BoolUnknown(), types.BoolUnknown(), types.BoolUnknown()
+	default:
+		var flags BucketPermissionFlagsModel
+		resp.Diagnostics.Append(plan.Permissions.As(ctx, &flags, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		plan.Read, plan.Write, plan.Owner = flags.Read, flags.Write, flags.Owner
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *BucketPermissionResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 only had the flat read, write and owner attributes
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id":                   schema.StringAttribute{Computed: true},
+					"bucket_id":            schema.StringAttribute{Required: true},
+					"access_key_id":        schema.StringAttribute{Required: true},
+					"read":                 schema.BoolAttribute{Optional: true, Computed: true},
+					"write":                schema.BoolAttribute{Optional: true, Computed: true},
+					"owner":                schema.BoolAttribute{Optional: true, Computed: true},
+					"wait_for_propagation": schema.StringAttribute{Optional: true, Computed: true},
+				},
 			},
+			StateUpgrader: upgradeBucketPermissionStateV0,
 		},
 	}
 }
 
+// bucketPermissionResourceModelV0 is the model of version 0 of the schema.
+type bucketPermissionResourceModelV0 struct {
+	ID                 types.String `tfsdk:"id"`
+	BucketID           types.String `tfsdk:"bucket_id"`
+	AccessKeyID        types.String `tfsdk:"access_key_id"`
+	Read               types.Bool   `tfsdk:"read"`
+	Write              types.Bool   `tfsdk:"write"`
+	Owner              types.Bool   `tfsdk:"owner"`
+	WaitForPropagation types.String `tfsdk:"wait_for_propagation"`
+}
+
+// upgradeBucketPermissionStateV0 builds the permissions object from the flat
+// attributes of a version 0 state.
+func upgradeBucketPermissionStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior bucketPermissionResourceModelV0
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// States written before wait_for_propagation existed hold null
+	waitForPropagation := prior.WaitForPropagation
+	if waitForPropagation.IsNull() {
+		waitForPropagation = types.StringValue("0s")
+	}
+
+	read := types.BoolValue(prior.Read.ValueBool())
+	write := types.BoolValue(prior.Write.ValueBool())
+	owner := types.BoolValue(prior.Owner.ValueBool())
+	resp.Diagnostics.Append(resp.State.Set(ctx, BucketPermissionResourceModel{
+		ID:                 prior.ID,
+		BucketID:           prior.BucketID,
+		AccessKeyID:        prior.AccessKeyID,
+		Read:               read,
+		Write:              write,
+		Owner:              owner,
+		Permissions:        bucketPermissionFlagsObject(read, write, owner),
+		WaitForPropagation: waitForPropagation,
+	})...)
+}
+
+// bucketPermissionFlagsObject builds the permissions object from its flags.
+func bucketPermissionFlagsObject(read, write, owner types.Bool) types.Object {
+	return types.ObjectValueMust(bucketPermissionFlagsAttrTypes, map[string]attr.Value{
+		"read":  read,
+		"write": write,
+		"owner": owner,
+	})
+}
+
 func (r *BucketPermissionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -108,24 +331,12 @@ func (r *BucketPermissionResource) Configure(ctx context.Context, req resource.C
 		return
 	}
 
-	// Get admin endpoint with backwards compatibility
-	adminEndpoint := ""
-	if providerData.Endpoints != nil && !providerData.Endpoints.Admin.IsNull() {
-		adminEndpoint = providerData.Endpoints.Admin.ValueString()
-	} else if !providerData.Endpoint.IsNull() {
-		// Fallback to deprecated endpoint
-		adminEndpoint = providerData.Endpoint.ValueString()
-	}
+	var diags diag.Diagnostics
+	r.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
 
-	if adminEndpoint == "" {
-		resp.Diagnostics.AddError(
-			"Missing Admin Endpoint",
-			"Admin endpoint must be configured via 'endpoints.admin' or deprecated 'endpoint' attribute",
-		)
-		return
-	}
-
-	r.client = client.NewClient(adminEndpoint, providerData.Token.ValueString())
+	r.s3Client = providerData.S3Client
+	r.accessKey = providerData.AccessKey.ValueString()
 }
 
 func (r *BucketPermissionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -142,6 +353,18 @@ func (r *BucketPermissionResource) Create(ctx context.Context, req resource.Crea
 		"access_key_id": data.AccessKeyID.ValueString(),
 	})
 
+	// Already validated, unless the value was unknown at validation. Parsed
+	// before granting so that an invalid value leaves nothing untracked.
+	timeout, err := time.ParseDuration(data.WaitForPropagation.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("wait_for_propagation"),
+			"Invalid Duration",
+			fmt.Sprintf("Unable to parse wait_for_propagation %q: %s", data.WaitForPropagation.ValueString(), err),
+		)
+		return
+	}
+
 	// Grant permissions using AllowBucketKey
 	allowReq := client.BucketKeyPermRequest{
 		BucketID:    data.BucketID.ValueString(),
@@ -155,25 +378,41 @@ func (r *BucketPermissionResource) Create(ctx context.Context, req resource.Crea
 
 	bucket, err := r.client.AllowBucketKey(ctx, allowReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create bucket permission, got error: %s", err))
+		resp.Diagnostics.AddError(r.grantFailureDiagnostic(ctx, allowReq.BucketID, allowReq.AccessKeyID, err))
 		return
 	}
 
-	// Set the ID
-	data.ID = types.StringValue(fmt.Sprintf("%s/%s", data.BucketID.ValueString(), data.AccessKeyID.ValueString()))
+	data.ID = types.StringValue(bucketPermissionID(data.BucketID.ValueString(), data.AccessKeyID.ValueString()))
 
 	// Update state from bucket info to ensure consistency
 	r.updateStateFromBucket(&data, bucket)
 
+	if timeout > 0 {
+		if err := r.waitForPropagation(ctx, &data, timeout); err != nil {
+			// Keep the grant in state so Terraform knows about it (the
+			// resource is tainted by the error and replaced on next apply).
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			resp.Diagnostics.Append(setBucketPermissionIdentity(ctx, resp.Identity, data)...)
+			resp.Diagnostics.AddError(
+				"Permission Propagation Timeout",
+				fmt.Sprintf("The permission for access key %s on bucket %s did not propagate within %s: %s",
+					data.AccessKeyID.ValueString(), data.BucketID.ValueString(), timeout, err),
+			)
+			return
+		}
+	}
+
 	tflog.Trace(ctx, "Created bucket permission resource")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(setBucketPermissionIdentity(ctx, resp.Identity, data)...)
 }
 
 func (r *BucketPermissionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var data BucketPermissionResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	resp.Diagnostics.Append(setBucketPermissionIdentity(ctx, resp.Identity, data)...)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -185,7 +424,7 @@ func (r *BucketPermissionResource) Read(ctx context.Context, req resource.ReadRe
 	})
 
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read bucket, got error: %s", err))
+		resp.Diagnostics.Append(diagFromClientError("Unable to read bucket", err))
 		return
 	}
 
@@ -195,6 +434,7 @@ func (r *BucketPermissionResource) Read(ctx context.Context, req resource.ReadRe
 	}
 
 	// Update state from bucket info
+	data.ID = types.StringValue(bucketPermissionID(data.BucketID.ValueString(), data.AccessKeyID.ValueString()))
 	r.updateStateFromBucket(&data, bucket)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -216,10 +456,12 @@ func (r *BucketPermissionResource) Update(ctx context.Context, req resource.Upda
 		"access_key_id": data.AccessKeyID.ValueString(),
 	})
 
-	// Determine which permissions need to be granted or revoked
-	readChanged := data.Read.ValueBool() != state.Read.ValueBool()
-	writeChanged := data.Write.ValueBool() != state.Write.ValueBool()
-	ownerChanged := data.Owner.ValueBool() != state.Owner.ValueBool()
+	// Compute the delta between state and plan. Grants are applied before
+	// revocations so the key never loses access that is still planned.
+	grant, revoke := permissionDelta(
+		client.Permissions{Read: state.Read.ValueBool(), Write: state.Write.ValueBool(), Owner: state.Owner.ValueBool()},
+		client.Permissions{Read: data.Read.ValueBool(), Write: data.Write.ValueBool(), Owner: data.Owner.ValueBool()},
+	)
 
 	bucketID := data.BucketID.ValueString()
 	accessKeyID := data.AccessKeyID.ValueString()
@@ -227,50 +469,34 @@ func (r *BucketPermissionResource) Update(ctx context.Context, req resource.Upda
 	var bucket *client.Bucket
 	var err error
 
-	// If any permission is being enabled, use AllowBucketKey
-	if (readChanged && data.Read.ValueBool()) ||
-		(writeChanged && data.Write.ValueBool()) ||
-		(ownerChanged && data.Owner.ValueBool()) {
-
-		allowReq := client.BucketKeyPermRequest{
+	if hasAnyPermission(grant) {
+		bucket, err = r.client.AllowBucketKey(ctx, client.BucketKeyPermRequest{
 			BucketID:    bucketID,
 			AccessKeyID: accessKeyID,
-			Permissions: client.Permissions{
-				Read:  readChanged && data.Read.ValueBool(),
-				Write: writeChanged && data.Write.ValueBool(),
-				Owner: ownerChanged && data.Owner.ValueBool(),
-			},
-		}
-
-		bucket, err = r.client.AllowBucketKey(ctx, allowReq)
+			Permissions: grant,
+		})
 		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to grant bucket permissions, got error: %s", err))
+			resp.Diagnostics.Append(diagFromClientError("Unable to grant bucket permissions", err))
 			return
 		}
 	}
 
-	// If any permission is being disabled, use DenyBucketKey
-	if (readChanged && !data.Read.ValueBool()) ||
-		(writeChanged && !data.Write.ValueBool()) ||
-		(ownerChanged && !data.Owner.ValueBool()) {
-
-		denyReq := client.BucketKeyPermRequest{
+	if hasAnyPermission(revoke) {
+		bucket, err = r.client.DenyBucketKey(ctx, client.BucketKeyPermRequest{
 			BucketID:    bucketID,
 			AccessKeyID: accessKeyID,
-			Permissions: client.Permissions{
-				Read:  readChanged && !data.Read.ValueBool(),
-				Write: writeChanged && !data.Write.ValueBool(),
-				Owner: ownerChanged && !data.Owner.ValueBool(),
-			},
-		}
-
-		bucket, err = r.client.DenyBucketKey(ctx, denyReq)
+			Permissions: revoke,
+		})
 		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to revoke bucket permissions, got error: %s", err))
+			resp.Diagnostics.Append(diagFromClientError("Unable to revoke bucket permissions", err))
 			return
 		}
 	}
 
+	// The ID only depends on bucket_id and access_key_id, both of which
+	// force replacement, so it never changes during an update.
+	data.ID = state.ID
+
 	// Update state from bucket info to ensure consistency
 	if bucket != nil {
 		r.updateStateFromBucket(&data, bucket)
@@ -279,6 +505,7 @@ func (r *BucketPermissionResource) Update(ctx context.Context, req resource.Upda
 	tflog.Trace(ctx, "Updated bucket permission resource")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(setBucketPermissionIdentity(ctx, resp.Identity, data)...)
 }
 
 func (r *BucketPermissionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -308,7 +535,7 @@ func (r *BucketPermissionResource) Delete(ctx context.Context, req resource.Dele
 
 	_, err := r.client.DenyBucketKey(ctx, denyReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete bucket permission, got error: %s", err))
+		resp.Diagnostics.Append(diagFromClientError("Unable to delete bucket permission", err))
 		return
 	}
 
@@ -316,6 +543,22 @@ func (r *BucketPermissionResource) Delete(ctx context.Context, req resource.Dele
 }
 
 func (r *BucketPermissionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import by identity (import block with an identity attribute)
+	if req.ID == "" {
+		var identity BucketPermissionIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if identity.BucketID.ValueString() == "" || identity.AccessKeyID.ValueString() == "" {
+			resp.Diagnostics.AddError("Invalid Import Identity", "Both bucket_id and access_key_id must be set in the import identity")
+			return
+		}
+
+		resp.Diagnostics.Append(importBucketPermission(ctx, resp, identity.BucketID.ValueString(), identity.AccessKeyID.ValueString())...)
+		return
+	}
+
 	// Import ID format: bucket_id/access_key_id
 	// Parse the import ID
 	bucketID, accessKeyID, found := parseImportID(req.ID)
@@ -327,9 +570,33 @@ func (r *BucketPermissionResource) ImportState(ctx context.Context, req resource
 		return
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket_id"), bucketID)...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("access_key_id"), accessKeyID)...)
+	resp.Diagnostics.Append(importBucketPermission(ctx, resp, bucketID, accessKeyID)...)
+}
+
+// importBucketPermission sets the state of an imported permission, Read
+// fills in the flags.
+func importBucketPermission(ctx context.Context, resp *resource.ImportStateResponse, bucketID, accessKeyID string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	diags.Append(resp.State.SetAttribute(ctx, path.Root("id"), bucketPermissionID(bucketID, accessKeyID))...)
+	diags.Append(resp.State.SetAttribute(ctx, path.Root("bucket_id"), bucketID)...)
+	diags.Append(resp.State.SetAttribute(ctx, path.Root("access_key_id"), accessKeyID)...)
+	diags.Append(resp.State.SetAttribute(ctx, path.Root("wait_for_propagation"), "0s")...)
+
+	return diags
+}
+
+// setBucketPermissionIdentity sets the resource identity from data. identity
+// is nil when Terraform does not support resource identities.
+func setBucketPermissionIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, data BucketPermissionResourceModel) diag.Diagnostics {
+	if identity == nil {
+		return nil
+	}
+
+	return identity.Set(ctx, BucketPermissionIdentityModel{
+		BucketID:    data.BucketID,
+		AccessKeyID: data.AccessKeyID,
+	})
 }
 
 // updateStateFromBucket updates the resource state from bucket info.
@@ -354,13 +621,144 @@ func (r *BucketPermissionResource) updateStateFromBucket(data *BucketPermissionR
 		data.Write = types.BoolValue(false)
 		data.Owner = types.BoolValue(false)
 	}
+
+	data.Permissions = bucketPermissionFlagsObject(data.Read, data.Write, data.Owner)
+}
+
+// grantFailureDiagnostic builds the diagnostic for a failed grant. Garage does
+// not say which of the bucket or the key is unknown, so both are looked up to
+// name the missing one. These lookups only happen on the error path.
+func (r *BucketPermissionResource) grantFailureDiagnostic(ctx context.Context, bucketID, accessKeyID string, err error) (string, string) {
+	bucket, bucketErr := r.client.GetBucketInfo(ctx, client.GetBucketInfoRequest{ID: &bucketID})
+	if bucketErr == nil && bucket == nil {
+		return "Bucket Not Found",
+			fmt.Sprintf("Unable to create bucket permission: bucket %s does not exist on this cluster. Check the bucket_id attribute.", bucketID)
+	}
+
+	key, keyErr := r.client.GetKeyInfo(ctx, client.GetKeyInfoRequest{ID: accessKeyID})
+	if keyErr == nil && key == nil {
+		return "Access Key Not Found",
+			fmt.Sprintf("Unable to create bucket permission: access key %s does not exist on this cluster. Check the access_key_id attribute.", accessKeyID)
+	}
+
+	return "Client Error", clientErrorDetail("Unable to create bucket permission", err)
+}
+
+// waitForPropagation polls the admin API until the planned grant is visible
+// and, when the provider S3 credentials belong to the granted key, until a
+// signed HeadBucket succeeds. It returns the last failure once timeout expires.
+func (r *BucketPermissionResource) waitForPropagation(ctx context.Context, data *BucketPermissionResourceModel, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	want := client.Permissions{
+		Read:  data.Read.ValueBool(),
+		Write: data.Write.ValueBool(),
+		Owner: data.Owner.ValueBool(),
+	}
+
+	var s3Client *s3.Client
+	if r.accessKey == data.AccessKeyID.ValueString() {
+		s3Client = r.s3Client
+	}
+
+	bucketID := data.BucketID.ValueString()
+	ticker := time.NewTicker(propagationPollInterval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		lastErr = r.checkPropagation(ctx, s3Client, bucketID, data.AccessKeyID.ValueString(), want)
+		if lastErr == nil {
+			return nil
+		}
+
+		tflog.Debug(ctx, "Waiting for bucket permission to propagate", map[string]interface{}{
+			"bucket_id":     bucketID,
+			"access_key_id": data.AccessKeyID.ValueString(),
+			"reason":        lastErr.Error(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return lastErr
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkPropagation performs a single propagation check.
+func (r *BucketPermissionResource) checkPropagation(ctx context.Context, s3Client *s3.Client, bucketID, accessKeyID string, want client.Permissions) error {
+	bucket, err := r.client.GetBucketInfo(ctx, client.GetBucketInfoRequest{ID: &bucketID})
+	if err != nil {
+		return err
+	}
+	if bucket == nil {
+		return fmt.Errorf("bucket %s not found", bucketID)
+	}
+
+	var got client.Permissions
+	for _, keyInfo := range bucket.Keys {
+		if keyInfo.AccessKeyID == accessKeyID {
+			got = keyInfo.Permissions
+			break
+		}
+	}
+	if grant, _ := permissionDelta(got, want); hasAnyPermission(grant) {
+		return fmt.Errorf("grant not yet visible in bucket info (have %+v, want %+v)", got, want)
+	}
+
+	if s3Client == nil || len(bucket.GlobalAliases) == 0 {
+		return nil
+	}
+
+	_, err = s3Client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(bucket.GlobalAliases[0]),
+	})
+	if err != nil {
+		return fmt.Errorf("signed HeadBucket failed: %w", err)
+	}
+
+	return nil
+}
+
+// permissionDelta returns the flags that must be granted and revoked to move
+// from the current permissions to the planned ones. Flags that are set in both
+// are left out of both results so they are never touched.
+func permissionDelta(current, planned client.Permissions) (grant, revoke client.Permissions) {
+	grant = client.Permissions{
+		Read:  planned.Read && !current.Read,
+		Write: planned.Write && !current.Write,
+		Owner: planned.Owner && !current.Owner,
+	}
+	revoke = client.Permissions{
+		Read:  current.Read && !planned.Read,
+		Write: current.Write && !planned.Write,
+		Owner: current.Owner && !planned.Owner,
+	}
+	return grant, revoke
+}
+
+// hasAnyPermission reports whether at least one flag is set.
+func hasAnyPermission(p client.Permissions) bool {
+	return p.Read || p.Write || p.Owner
+}
+
+// bucketPermissionID builds the composite ID of a permission: "bucket_id/access_key_id".
+func bucketPermissionID(bucketID, accessKeyID string) string {
+	return bucketID + "/" + accessKeyID
 }
 
 // parseImportID parses an import ID in the format "bucket_id/access_key_id".
+// Both parts must be non-empty.
 func parseImportID(id string) (bucketID, accessKeyID string, ok bool) {
 	for i := 0; i < len(id); i++ {
 		if id[i] == '/' {
-			return id[:i], id[i+1:], true
+			bucketID, accessKeyID = id[:i], id[i+1:]
+			if bucketID == "" || accessKeyID == "" {
+				return "", "", false
+			}
+			return bucketID, accessKeyID, true
 		}
 	}
 	return "", "", false
@@ -4,13 +4,35 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/compare"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
 )
 
 func TestAccBucketPermissionResource_basic(t *testing.T) {
+	sameID := statecheck.CompareValue(compare.ValuesSame())
+
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
@@ -18,8 +40,11 @@ func TestAccBucketPermissionResource_basic(t *testing.T) {
 			// Create bucket and grant read permission
 			{
 				Config: testAccBucketPermissionResourceConfig_basic("test-perm-bucket", "test-perm-key", true, false, false),
+				ConfigStateChecks: []statecheck.StateCheck{
+					sameID.AddStateValue("garage_bucket_permission.test", tfjsonpath.New("id")),
+				},
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttrSet("garage_bucket_permission.test", "id"),
+					testAccCheckBucketPermissionID("garage_bucket_permission.test"),
 					resource.TestCheckResourceAttrSet("garage_bucket_permission.test", "bucket_id"),
 					resource.TestCheckResourceAttrSet("garage_bucket_permission.test", "access_key_id"),
 					resource.TestCheckResourceAttr("garage_bucket_permission.test", "read", "true"),
@@ -33,10 +58,14 @@ func TestAccBucketPermissionResource_basic(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
-			// Update to grant write permission as well
+			// Update to grant write permission as well; the ID must not change
 			{
 				Config: testAccBucketPermissionResourceConfig_basic("test-perm-bucket", "test-perm-key", true, true, false),
+				ConfigStateChecks: []statecheck.StateCheck{
+					sameID.AddStateValue("garage_bucket_permission.test", tfjsonpath.New("id")),
+				},
 				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckBucketPermissionID("garage_bucket_permission.test"),
 					resource.TestCheckResourceAttr("garage_bucket_permission.test", "read", "true"),
 					resource.TestCheckResourceAttr("garage_bucket_permission.test", "write", "true"),
 					resource.TestCheckResourceAttr("garage_bucket_permission.test", "owner", "false"),
@@ -47,6 +76,30 @@ func TestAccBucketPermissionResource_basic(t *testing.T) {
 	})
 }
 
+func TestAccBucketPermissionResource_identity(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBucketPermissionResourceConfig_basic("test-perm-identity-bucket", "test-perm-identity-key", true, true, false),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentityValueMatchesState("garage_bucket_permission.test", tfjsonpath.New("bucket_id")),
+					statecheck.ExpectIdentityValueMatchesState("garage_bucket_permission.test", tfjsonpath.New("access_key_id")),
+				},
+			},
+			{
+				ResourceName:    "garage_bucket_permission.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+		},
+	})
+}
+
 func TestAccBucketPermissionResource_allPermissions(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
@@ -70,14 +123,10 @@ func TestAccBucketPermissionResource_allPermissions(t *testing.T) {
 					resource.TestCheckResourceAttr("garage_bucket_permission.test", "owner", "true"),
 				),
 			},
-			// Remove all permissions
+			// Removing all permissions is rejected at plan time
 			{
-				Config: testAccBucketPermissionResourceConfig_basic("test-all-perm-bucket", "test-all-perm-key", false, false, false),
-				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("garage_bucket_permission.test", "read", "false"),
-					resource.TestCheckResourceAttr("garage_bucket_permission.test", "write", "false"),
-					resource.TestCheckResourceAttr("garage_bucket_permission.test", "owner", "false"),
-				),
+				Config:      testAccBucketPermissionResourceConfig_basic("test-all-perm-bucket", "test-all-perm-key", false, false, false),
+				ExpectError: regexp.MustCompile(`at least one of read, write, owner must be true`),
 			},
 		},
 	})
@@ -124,6 +173,585 @@ func TestAccBucketPermissionResource_multipleKeys(t *testing.T) {
 	})
 }
 
+func TestAccBucketPermissionResource_inPlaceUpdate(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBucketPermissionResourceConfig_basic("test-inplace-bucket", "test-inplace-key", true, false, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_bucket_permission.test", "read", "true"),
+					resource.TestCheckResourceAttr("garage_bucket_permission.test", "write", "false"),
+				),
+			},
+			// Flip write on: must be an in-place update, not a replacement
+			{
+				Config: testAccBucketPermissionResourceConfig_basic("test-inplace-bucket", "test-inplace-key", true, true, false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("garage_bucket_permission.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_bucket_permission.test", "read", "true"),
+					resource.TestCheckResourceAttr("garage_bucket_permission.test", "write", "true"),
+				),
+			},
+			// Flip write off again: read must be kept and the resource updated in place
+			{
+				Config: testAccBucketPermissionResourceConfig_basic("test-inplace-bucket", "test-inplace-key", true, false, false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("garage_bucket_permission.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_bucket_permission.test", "read", "true"),
+					resource.TestCheckResourceAttr("garage_bucket_permission.test", "write", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBucketPermissionResource_permissions(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The permissions object, with the flat attributes computed from it
+			{
+				Config: testAccBucketPermissionResourceConfig_permissions("test-perm-nested-bucket", "test-perm-nested-key", true, false, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_bucket_permission.test", "permissions.read", "true"),
+					resource.TestCheckResourceAttr("garage_bucket_permission.test", "permissions.write", "false"),
+					resource.TestCheckResourceAttr("garage_bucket_permission.test", "permissions.owner", "false"),
+					resource.TestCheckResourceAttr("garage_bucket_permission.test", "read", "true"),
+					resource.TestCheckResourceAttr("garage_bucket_permission.test", "write", "false"),
+				),
+			},
+			{
+				ResourceName:      "garage_bucket_permission.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccBucketPermissionResourceConfig_permissions("test-perm-nested-bucket", "test-perm-nested-key", true, true, false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("garage_bucket_permission.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_bucket_permission.test", "permissions.write", "true"),
+					resource.TestCheckResourceAttr("garage_bucket_permission.test", "write", "true"),
+				),
+			},
+			// Moving to the deprecated flat attributes with the same values
+			// changes nothing, and back again
+			{
+				Config: testAccBucketPermissionResourceConfig_basic("test-perm-nested-bucket", "test-perm-nested-key", true, true, false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("garage_bucket_permission.test", "permissions.write", "true"),
+			},
+			{
+				Config: testAccBucketPermissionResourceConfig_permissions("test-perm-nested-bucket", "test-perm-nested-key", true, true, false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccBucketPermissionResource_mixedShapes(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig() + `
+resource "garage_bucket_permission" "test" {
+  bucket_id     = "bucket"
+  access_key_id = "GK0123456789abcdef01234567"
+  read          = true
+
+  permissions = {
+    write = true
+  }
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+			{
+				Config: testAccProviderConfig() + `
+resource "garage_bucket_permission" "test" {
+  bucket_id     = "bucket"
+  access_key_id = "GK0123456789abcdef01234567"
+
+  permissions = {}
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`at least one of`),
+			},
+		},
+	})
+}
+
+func TestAccBucketPermissionResource_invalidAccessKeyID(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig() + `
+resource "garage_bucket_permission" "test" {
+  bucket_id     = "bucket-id"
+  access_key_id = "31c2f218a2e44f485b94239e"
+  read          = true
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Access Key ID`),
+			},
+		},
+	})
+}
+
+func TestParseImportID(t *testing.T) {
+	tests := []struct {
+		id              string
+		wantBucketID    string
+		wantAccessKeyID string
+		wantOK          bool
+	}{
+		{id: "bucket-123/GK123", wantBucketID: "bucket-123", wantAccessKeyID: "GK123", wantOK: true},
+		{id: "bucket-123"},
+		{id: "/GK123"},
+		{id: "bucket-123/"},
+		{id: ""},
+	}
+
+	for _, tt := range tests {
+		bucketID, accessKeyID, ok := parseImportID(tt.id)
+		if ok != tt.wantOK || bucketID != tt.wantBucketID || accessKeyID != tt.wantAccessKeyID {
+			t.Errorf("parseImportID(%q) = (%q, %q, %t), want (%q, %q, %t)",
+				tt.id, bucketID, accessKeyID, ok, tt.wantBucketID, tt.wantAccessKeyID, tt.wantOK)
+		}
+	}
+
+	if id := bucketPermissionID("bucket-123", "GK123"); id != "bucket-123/GK123" {
+		t.Errorf("Expected ID bucket-123/GK123, got %s", id)
+	}
+}
+
+func TestPermissionDelta(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    client.Permissions
+		planned    client.Permissions
+		wantGrant  client.Permissions
+		wantRevoke client.Permissions
+	}{
+		{
+			name:    "no change",
+			current: client.Permissions{Read: true, Write: true},
+			planned: client.Permissions{Read: true, Write: true},
+		},
+		{
+			name:      "grant single flag",
+			current:   client.Permissions{Read: true},
+			planned:   client.Permissions{Read: true, Write: true},
+			wantGrant: client.Permissions{Write: true},
+		},
+		{
+			name:       "revoke single flag keeps others",
+			current:    client.Permissions{Read: true, Write: true, Owner: true},
+			planned:    client.Permissions{Read: true, Owner: true},
+			wantRevoke: client.Permissions{Write: true},
+		},
+		{
+			name:       "grant and revoke",
+			current:    client.Permissions{Read: true},
+			planned:    client.Permissions{Owner: true},
+			wantGrant:  client.Permissions{Owner: true},
+			wantRevoke: client.Permissions{Read: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grant, revoke := permissionDelta(tt.current, tt.planned)
+			if grant != tt.wantGrant {
+				t.Errorf("Expected grant %+v, got %+v", tt.wantGrant, grant)
+			}
+			if revoke != tt.wantRevoke {
+				t.Errorf("Expected revoke %+v, got %+v", tt.wantRevoke, revoke)
+			}
+		})
+	}
+}
+
+func TestBucketPermissionModifyPlan(t *testing.T) {
+	ctx := context.Background()
+	r := &BucketPermissionResource{}
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	flagsType := objectType.AttributeTypes["permissions"]
+
+	flags := func(read, write, owner interface{}) tftypes.Value {
+		return tftypes.NewValue(flagsType, map[string]tftypes.Value{
+			"read":  tftypes.NewValue(tftypes.Bool, read),
+			"write": tftypes.NewValue(tftypes.Bool, write),
+			"owner": tftypes.NewValue(tftypes.Bool, owner),
+		})
+	}
+	value := func(read, write, owner interface{}, permissions tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"id":                   tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			"bucket_id":            tftypes.NewValue(tftypes.String, "bucket"),
+			"access_key_id":        tftypes.NewValue(tftypes.String, "GK0123456789abcdef01234567"),
+			"read":                 tftypes.NewValue(tftypes.Bool, read),
+			"write":                tftypes.NewValue(tftypes.Bool, write),
+			"owner":                tftypes.NewValue(tftypes.Bool, owner),
+			"permissions":          permissions,
+			"wait_for_propagation": tftypes.NewValue(tftypes.String, "0s"),
+		})
+	}
+
+	tests := []struct {
+		name   string
+		config tftypes.Value
+		plan   tftypes.Value
+		want   [3]types.Bool
+	}{
+		{
+			name:   "flat attributes",
+			config: value(true, nil, nil, tftypes.NewValue(flagsType, nil)),
+			plan:   value(true, false, false, tftypes.NewValue(flagsType, tftypes.UnknownValue)),
+			want:   [3]types.Bool{types.BoolValue(true), types.BoolValue(false), types.BoolValue(false)},
+		},
+		{
+			name:   "permissions object",
+			config: value(nil, nil, nil, flags(nil, true, nil)),
+			plan:   value(false, false, false, flags(false, true, false)),
+			want:   [3]types.Bool{types.BoolValue(false), types.BoolValue(true), types.BoolValue(false)},
+		},
+		{
+			name:   "unknown permissions object",
+			config: value(nil, nil, nil, tftypes.NewValue(flagsType, tftypes.UnknownValue)),
+			plan:   value(false, false, false, tftypes.NewValue(flagsType, tftypes.UnknownValue)),
+			want:   [3]types.Bool{types.BoolUnknown(), types.BoolUnknown(), types.BoolUnknown()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: tt.plan}
+			resp := &fwresource.ModifyPlanResponse{Plan: plan}
+			r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{
+				Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tt.config},
+				Plan:   plan,
+			}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var data BucketPermissionResourceModel
+			resp.Diagnostics.Append(resp.Plan.Get(ctx, &data)...)
+			if got := [3]types.Bool{data.Read, data.Write, data.Owner}; got != tt.want {
+				t.Errorf("Expected flat attributes %v, got %v", tt.want, got)
+			}
+			if tt.want[0].IsUnknown() {
+				return
+			}
+			expected := bucketPermissionFlagsObject(tt.want[0], tt.want[1], tt.want[2])
+			if !data.Permissions.Equal(expected) {
+				t.Errorf("Expected permissions %s, got %s", expected, data.Permissions)
+			}
+		})
+	}
+}
+
+func TestBucketPermissionConfigValidators(t *testing.T) {
+	ctx := context.Background()
+	r := &BucketPermissionResource{}
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	flagsType := objectType.AttributeTypes["permissions"]
+
+	tests := []struct {
+		name        string
+		read        interface{}
+		permissions tftypes.Value
+		wantErr     bool
+	}{
+		{name: "flat attribute", read: true, permissions: tftypes.NewValue(flagsType, nil)},
+		{name: "permissions object", permissions: tftypes.NewValue(flagsType, map[string]tftypes.Value{
+			"read":  tftypes.NewValue(tftypes.Bool, nil),
+			"write": tftypes.NewValue(tftypes.Bool, true),
+			"owner": tftypes.NewValue(tftypes.Bool, nil),
+		})},
+		{name: "nothing granted", permissions: tftypes.NewValue(flagsType, nil), wantErr: true},
+		{name: "empty permissions object", permissions: tftypes.NewValue(flagsType, map[string]tftypes.Value{
+			"read":  tftypes.NewValue(tftypes.Bool, nil),
+			"write": tftypes.NewValue(tftypes.Bool, false),
+			"owner": tftypes.NewValue(tftypes.Bool, nil),
+		}), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+				"id":                   tftypes.NewValue(tftypes.String, nil),
+				"bucket_id":            tftypes.NewValue(tftypes.String, "bucket"),
+				"access_key_id":        tftypes.NewValue(tftypes.String, "GK0123456789abcdef01234567"),
+				"read":                 tftypes.NewValue(tftypes.Bool, tt.read),
+				"write":                tftypes.NewValue(tftypes.Bool, nil),
+				"owner":                tftypes.NewValue(tftypes.Bool, nil),
+				"permissions":          tt.permissions,
+				"wait_for_propagation": tftypes.NewValue(tftypes.String, nil),
+			})}
+
+			resp := &fwresource.ValidateConfigResponse{}
+			for _, v := range r.ConfigValidators(ctx) {
+				v.ValidateResource(ctx, fwresource.ValidateConfigRequest{Config: config}, resp)
+			}
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Errorf("Expected error %t, got %v", tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestBucketPermissionUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	r := &BucketPermissionResource{}
+	upgrader := r.UpgradeState(ctx)[0]
+
+	priorType := upgrader.PriorSchema.Type().TerraformType(ctx)
+	prior := tftypes.NewValue(priorType, map[string]tftypes.Value{
+		"id":                   tftypes.NewValue(tftypes.String, "bucket/GK0123456789abcdef01234567"),
+		"bucket_id":            tftypes.NewValue(tftypes.String, "bucket"),
+		"access_key_id":        tftypes.NewValue(tftypes.String, "GK0123456789abcdef01234567"),
+		"read":                 tftypes.NewValue(tftypes.Bool, true),
+		"write":                tftypes.NewValue(tftypes.Bool, false),
+		"owner":                tftypes.NewValue(tftypes.Bool, true),
+		"wait_for_propagation": tftypes.NewValue(tftypes.String, nil),
+	})
+
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	resp := &fwresource.UpgradeStateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	upgrader.StateUpgrader(ctx, fwresource.UpgradeStateRequest{
+		State: &tfsdk.State{Schema: *upgrader.PriorSchema, Raw: prior},
+	}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data BucketPermissionResourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	expected := bucketPermissionFlagsObject(types.BoolValue(true), types.BoolValue(false), types.BoolValue(true))
+	if !data.Permissions.Equal(expected) {
+		t.Errorf("Expected permissions %s, got %s", expected, data.Permissions)
+	}
+	if !data.Read.ValueBool() || data.Write.ValueBool() || !data.Owner.ValueBool() {
+		t.Errorf("Expected the flat attributes to be kept, got %+v", data)
+	}
+	if data.ID.ValueString() != "bucket/GK0123456789abcdef01234567" || data.WaitForPropagation.ValueString() != "0s" {
+		t.Errorf("Unexpected upgraded state %+v", data)
+	}
+}
+
+func TestBucketPermissionCreate_invalidWaitForPropagation(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		t.Errorf("Unexpected request to %s", r.URL.Path)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	r := &BucketPermissionResource{client: client.NewClient(server.URL, "test-token")}
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	values := map[string]tftypes.Value{}
+	for name, typ := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(typ, nil)
+	}
+	values["bucket_id"] = tftypes.NewValue(tftypes.String, "bucket-123")
+	values["access_key_id"] = tftypes.NewValue(tftypes.String, "GK123")
+	values["read"] = tftypes.NewValue(tftypes.Bool, true)
+	values["wait_for_propagation"] = tftypes.NewValue(tftypes.String, "soon")
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)}
+
+	resp := &fwresource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)}}
+	r.Create(ctx, fwresource.CreateRequest{Plan: plan}, resp)
+
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Invalid Duration" {
+		t.Errorf("Expected an Invalid Duration error, got %v", resp.Diagnostics)
+	}
+	// Nothing is granted that the state would not track
+	if got := atomic.LoadInt32(&calls); got != 0 {
+		t.Errorf("Expected no grant, got %d requests", got)
+	}
+}
+
+func TestBucketPermissionWaitForPropagation(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bucket := client.Bucket{ID: "bucket-123"}
+		// The grant only becomes visible on the third poll
+		if atomic.AddInt32(&calls, 1) >= 3 {
+			bucket.Keys = []client.BucketKeyInfo{
+				{AccessKeyID: "GK123", Permissions: client.Permissions{Read: true}},
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(bucket)
+	}))
+	defer server.Close()
+
+	r := &BucketPermissionResource{client: client.NewClient(server.URL, "test-token")}
+	data := &BucketPermissionResourceModel{
+		BucketID:    types.StringValue("bucket-123"),
+		AccessKeyID: types.StringValue("GK123"),
+		Read:        types.BoolValue(true),
+		Write:       types.BoolValue(false),
+		Owner:       types.BoolValue(false),
+	}
+
+	if err := r.waitForPropagation(context.Background(), data, 10*time.Second); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("Expected 3 polls, got %d", got)
+	}
+}
+
+func TestBucketPermissionWaitForPropagation_timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(client.Bucket{ID: "bucket-123"})
+	}))
+	defer server.Close()
+
+	r := &BucketPermissionResource{client: client.NewClient(server.URL, "test-token")}
+	data := &BucketPermissionResourceModel{
+		BucketID:    types.StringValue("bucket-123"),
+		AccessKeyID: types.StringValue("GK123"),
+		Read:        types.BoolValue(true),
+		Write:       types.BoolValue(true),
+		Owner:       types.BoolValue(false),
+	}
+
+	if err := r.waitForPropagation(context.Background(), data, time.Second); err == nil {
+		t.Fatal("Expected timeout error, got nil")
+	}
+}
+
+func TestBucketPermissionGrantFailureDiagnostic(t *testing.T) {
+	tests := []struct {
+		name         string
+		bucketExists bool
+		keyExists    bool
+		wantSummary  string
+		wantInDetail string
+	}{
+		{
+			name:         "bucket missing",
+			bucketExists: false,
+			keyExists:    true,
+			wantSummary:  "Bucket Not Found",
+			wantInDetail: "bucket bucket-123 does not exist",
+		},
+		{
+			name:         "key missing",
+			bucketExists: true,
+			keyExists:    false,
+			wantSummary:  "Access Key Not Found",
+			wantInDetail: "access key GK123 does not exist",
+		},
+		{
+			name:         "both exist",
+			bucketExists: true,
+			keyExists:    true,
+			wantSummary:  "Client Error",
+			wantInDetail: "got error: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/v2/GetBucketInfo":
+					if !tt.bucketExists {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					_ = json.NewEncoder(w).Encode(client.Bucket{ID: "bucket-123"})
+				case "/v2/GetKeyInfo":
+					if !tt.keyExists {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					_ = json.NewEncoder(w).Encode(client.AccessKey{AccessKeyID: "GK123"})
+				default:
+					t.Errorf("Unexpected request to %s", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			r := &BucketPermissionResource{client: client.NewClient(server.URL, "test-token")}
+			summary, detail := r.grantFailureDiagnostic(context.Background(), "bucket-123", "GK123", fmt.Errorf("boom"))
+
+			if summary != tt.wantSummary {
+				t.Errorf("Expected summary %q, got %q", tt.wantSummary, summary)
+			}
+			if !strings.Contains(detail, tt.wantInDetail) {
+				t.Errorf("Expected detail to contain %q, got %q", tt.wantInDetail, detail)
+			}
+		})
+	}
+}
+
+// testAccCheckBucketPermissionID checks that the permission ID is <bucket_id>/<access_key_id>.
+func testAccCheckBucketPermissionID(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", name)
+		}
+
+		attrs := rs.Primary.Attributes
+		expected := attrs["bucket_id"] + "/" + attrs["access_key_id"]
+		if attrs["id"] != expected {
+			return fmt.Errorf("expected id %q, got %q", expected, attrs["id"])
+		}
+
+		return nil
+	}
+}
+
 // Test configuration functions
 
 func testAccBucketPermissionResourceConfig_basic(bucketName, keyName string, read, write, owner bool) string {
@@ -146,6 +774,29 @@ resource "garage_bucket_permission" "test" {
 `, bucketName, keyName, read, write, owner)
 }
 
+func testAccBucketPermissionResourceConfig_permissions(bucketName, keyName string, read, write, owner bool) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = %[1]q
+}
+
+resource "garage_key" "test" {
+  name = %[2]q
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = garage_key.test.id
+
+  permissions = {
+    read  = %[3]t
+    write = %[4]t
+    owner = %[5]t
+  }
+}
+`, bucketName, keyName, read, write, owner)
+}
+
 func testAccBucketPermissionResourceConfig_multiple(bucketName, key1Name, key2Name string) string {
 	return testAccProviderConfig() + fmt.Sprintf(`
 resource "garage_bucket" "test" {
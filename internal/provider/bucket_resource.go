@@ -6,17 +6,23 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -27,9 +33,21 @@ func NewBucketResource() resource.Resource {
 	return &BucketResource{}
 }
 
+// bucketDeleteAttempts bounds the attempts to delete a purged bucket, as
+// Garage may take a moment to account for the removed objects.
+const bucketDeleteAttempts = 3
+
+// bucketDeleteRetryDelay is the wait before the second attempt to delete a
+// purged bucket, doubled for each further attempt.
+var bucketDeleteRetryDelay = 2 * time.Second
+
 // BucketResource defines the resource implementation.
 type BucketResource struct {
 	client *client.Client
+
+	// S3 access is only needed to purge the bucket with force_destroy
+	s3Client    *s3.Client
+	s3AccessKey string
 }
 
 // BucketResourceModel describes the resource data model.
@@ -41,6 +59,7 @@ type BucketResourceModel struct {
 	WebsiteError   types.String `tfsdk:"website_error_document"`
 	MaxSize        types.Int64  `tfsdk:"max_size"`
 	MaxObjects     types.Int64  `tfsdk:"max_objects"`
+	ForceDestroy   types.Bool   `tfsdk:"force_destroy"`
 }
 
 func (r *BucketResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -61,16 +80,19 @@ func (r *BucketResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			},
 			"global_alias": schema.StringAttribute{
 				Required:            true,
-				MarkdownDescription: "The global alias (name) for the bucket.",
+				MarkdownDescription: "The global alias (name) for the bucket. It must be 3 to 63 lowercase letters, digits, dashes and dots, starting and ending with a letter or digit.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					validators.BucketAlias(),
+				},
 			},
 			"website_enabled": schema.BoolAttribute{
 				Optional:            true,
 				Computed:            true,
 				Default:             booldefault.StaticBool(false),
-				MarkdownDescription: "Enable website hosting for this bucket.",
+				MarkdownDescription: "Enable website hosting for this bucket. Do not set the website attributes when the website is managed by a `garage_bucket_website` resource, and ignore their changes instead.",
 			},
 			"website_index_document": schema.StringAttribute{
 				Optional:            true,
@@ -88,6 +110,12 @@ func (r *BucketResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Optional:            true,
 				MarkdownDescription: "Maximum number of objects in the bucket. Leave unset for unlimited.",
 			},
+			"force_destroy": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Delete every object and abort every unfinished multipart upload of the bucket when destroying it, instead of failing on a non-empty bucket. Requires `endpoints.s3` and S3 credentials: the provider access key is granted read and write on the bucket for the purge. Defaults to `false`.",
+			},
 		},
 	}
 }
@@ -106,24 +134,11 @@ func (r *BucketResource) Configure(ctx context.Context, req resource.ConfigureRe
 		return
 	}
 
-	// Get admin endpoint with backwards compatibility
-	adminEndpoint := ""
-	if providerData.Endpoints != nil && !providerData.Endpoints.Admin.IsNull() {
-		adminEndpoint = providerData.Endpoints.Admin.ValueString()
-	} else if !providerData.Endpoint.IsNull() {
-		// Fallback to deprecated endpoint
-		adminEndpoint = providerData.Endpoint.ValueString()
-	}
-
-	if adminEndpoint == "" {
-		resp.Diagnostics.AddError(
-			"Missing Admin Endpoint",
-			"Admin endpoint must be configured via 'endpoints.admin' or deprecated 'endpoint' attribute",
-		)
-		return
-	}
-
-	r.client = client.NewClient(adminEndpoint, providerData.Token.ValueString())
+	var diags diag.Diagnostics
+	r.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
+	r.s3Client = providerData.S3Client
+	r.s3AccessKey = providerData.AccessKey.ValueString()
 }
 
 func (r *BucketResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -147,7 +162,7 @@ func (r *BucketResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	bucket, err := r.client.CreateBucket(ctx, createReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create bucket, got error: %s", err))
+		resp.Diagnostics.Append(diagFromClientError("Unable to create bucket", err))
 		return
 	}
 
@@ -201,7 +216,7 @@ func (r *BucketResource) Create(ctx context.Context, req resource.CreateRequest,
 	if needsUpdate {
 		_, err = r.client.UpdateBucket(ctx, bucket.ID, updateReq)
 		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update bucket, got error: %s", err))
+			resp.Diagnostics.Append(diagFromClientError("Unable to update bucket", err))
 			return
 		}
 	}
@@ -226,7 +241,7 @@ func (r *BucketResource) Read(ctx context.Context, req resource.ReadRequest, res
 	})
 
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read bucket, got error: %s", err))
+		resp.Diagnostics.Append(diagFromClientError("Unable to read bucket", err))
 		return
 	}
 
@@ -246,7 +261,7 @@ func (r *BucketResource) Read(ctx context.Context, req resource.ReadRequest, res
 
 	if bucket.WebsiteConfig != nil {
 		data.WebsiteIndex = types.StringValue(bucket.WebsiteConfig.IndexDocument)
-		data.WebsiteError = types.StringValue(bucket.WebsiteConfig.ErrorDocument)
+		data.WebsiteError = types.StringPointerValue(bucket.WebsiteConfig.ErrorDocument)
 	} else {
 		data.WebsiteIndex = types.StringNull()
 		data.WebsiteError = types.StringNull()
@@ -269,6 +284,11 @@ func (r *BucketResource) Read(ctx context.Context, req resource.ReadRequest, res
 		data.MaxObjects = types.Int64Null()
 	}
 
+	// Not stored by Garage, unset after an import
+	if data.ForceDestroy.IsNull() {
+		data.ForceDestroy = types.BoolValue(false)
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -320,7 +340,7 @@ func (r *BucketResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	_, err := r.client.UpdateBucket(ctx, bucketID, updateReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update bucket, got error: %s", err))
+		resp.Diagnostics.Append(diagFromClientError("Unable to update bucket", err))
 		return
 	}
 
@@ -340,18 +360,104 @@ func (r *BucketResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 	bucketID := data.ID.ValueString()
 
-	err := r.client.DeleteBucket(ctx, client.DeleteBucketRequest{
-		ID: bucketID,
+	attempts := 1
+	if data.ForceDestroy.ValueBool() {
+		resp.Diagnostics.Append(r.purge(ctx, data)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		attempts = bucketDeleteAttempts
+	}
+
+	delay := bucketDeleteRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := r.client.DeleteBucket(ctx, client.DeleteBucketRequest{
+			ID: bucketID,
+		})
+		if err == nil {
+			break
+		}
+		if attempt >= attempts {
+			resp.Diagnostics.Append(diagFromClientError("Unable to delete bucket", err))
+			return
+		}
+
+		tflog.Debug(ctx, "Purged bucket not deleted yet, retrying", map[string]interface{}{
+			"bucket_id": bucketID,
+			"attempt":   attempt,
+			"error":     err.Error(),
+		})
+		select {
+		case <-ctx.Done():
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete bucket, got error: %s", ctx.Err()))
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+
+	tflog.Trace(ctx, "Deleted bucket resource")
+}
+
+// purge empties the bucket through the S3 API for force_destroy, after
+// granting the provider access key read and write on it.
+func (r *BucketResource) purge(ctx context.Context, data BucketResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if r.s3Client == nil {
+		diags.AddError(
+			"Missing S3 Endpoint",
+			"force_destroy deletes the objects of the bucket through the S3 API: endpoints.s3 and the S3 credentials must be configured",
+		)
+		return diags
+	}
+
+	_, err := r.client.AllowBucketKey(ctx, client.BucketKeyPermRequest{
+		BucketID:    data.ID.ValueString(),
+		AccessKeyID: r.s3AccessKey,
+		Permissions: client.Permissions{Read: true, Write: true},
 	})
+	if err != nil {
+		diags.Append(diagFromClientError(fmt.Sprintf("Unable to grant access key %s access to the bucket to purge it", r.s3AccessKey), err))
+		return diags
+	}
 
+	bucket := data.GlobalAlias.ValueString()
+	objects, uploads, err := purgeBucket(ctx, r.s3Client, bucket)
+	tflog.Info(ctx, "Purged bucket before deletion", map[string]interface{}{
+		"bucket":  bucket,
+		"objects": objects,
+		"uploads": uploads,
+	})
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete bucket, got error: %s", err))
-		return
+		diags.AddError(
+			"Failed to Purge Bucket",
+			fmt.Sprintf("Could not empty bucket %s before deleting it: %s", bucket, err),
+		)
 	}
 
-	tflog.Trace(ctx, "Deleted bucket resource")
+	return diags
 }
 
 func (r *BucketResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	if bucketIDPattern.MatchString(req.ID) {
+		resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+		return
+	}
+
+	// Import blocks are easier to write with the bucket name, resolve it
+	bucket, err := r.client.GetBucketInfo(ctx, client.GetBucketInfoRequest{GlobalAlias: &req.ID})
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError(fmt.Sprintf("Unable to read bucket %q", req.ID), err))
+		return
+	}
+	if bucket == nil {
+		resp.Diagnostics.AddError("Bucket Not Found", fmt.Sprintf("No bucket with ID or global alias %q exists on this cluster.", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), bucket.ID)...)
 }
+
+// bucketIDPattern matches Garage bucket IDs: 64 hexadecimal characters.
+var bucketIDPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
@@ -4,10 +4,18 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"strings"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
 )
 
 func TestAccBucketResource_basic(t *testing.T) {
@@ -30,6 +38,13 @@ func TestAccBucketResource_basic(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			// Import by global alias
+			{
+				ResourceName:      "garage_bucket.test",
+				ImportState:       true,
+				ImportStateId:     "test-bucket-basic",
+				ImportStateVerify: true,
+			},
 			// Update and Read testing
 			{
 				Config: testAccBucketResourceConfig_basic("test-bucket-basic"),
@@ -170,6 +185,60 @@ func TestAccBucketResource_nameChange(t *testing.T) {
 	})
 }
 
+func TestAccBucketResource_forceDestroy(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Fill the bucket outside of Terraform, the destroy at the end of
+			// the test must purge it
+			{
+				Config: testAccBucketResourceConfig_forceDestroy("test-bucket-force-destroy"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_bucket.test", "force_destroy", "true"),
+					testAccFillBucket("garage_bucket.test", "test-bucket-force-destroy"),
+				),
+			},
+		},
+	})
+}
+
+// testAccFillBucket grants the acceptance test access key write access to the
+// bucket, then uploads an object and starts a multipart upload in it.
+func testAccFillBucket(name, bucket string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", name)
+		}
+
+		ctx := context.Background()
+		c := client.NewClient(os.Getenv("GARAGE_ADMIN_ENDPOINT"), os.Getenv("GARAGE_TOKEN"))
+		_, err := c.AllowBucketKey(ctx, client.BucketKeyPermRequest{
+			BucketID:    rs.Primary.ID,
+			AccessKeyID: os.Getenv("GARAGE_ACCESS_KEY"),
+			Permissions: client.Permissions{Read: true, Write: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		s3Client := testAccS3Client()
+		if _, err := s3Client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String("leftover.txt"),
+			Body:   strings.NewReader("leftover"),
+		}); err != nil {
+			return err
+		}
+		_, err = s3Client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String("unfinished.bin"),
+		})
+		return err
+	}
+}
+
 // Test configuration functions
 
 func testAccBucketResourceConfig_basic(name string) string {
@@ -223,3 +292,12 @@ resource "garage_bucket" "test" {
 }
 `, name, websiteEnabled, indexDoc, errorDoc, maxSize, maxObjects)
 }
+
+func testAccBucketResourceConfig_forceDestroy(name string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias  = %[1]q
+  force_destroy = true
+}
+`, name)
+}
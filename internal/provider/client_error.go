@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+// diagFromClientError returns the "Client Error" diagnostic of a failed call
+// of the admin or K2V API, action saying what could not be done, such as
+// "Unable to read bucket". A rejected admin token gets a dedicated
+// diagnostic saying how to fix it.
+func diagFromClientError(action string, err error) diag.Diagnostic {
+	var authErr *client.AuthError
+	if errors.As(err, &authErr) {
+		return diag.NewErrorDiagnostic("Admin Token Rejected", adminTokenRejectedDetail(action, authErr))
+	}
+	return diag.NewErrorDiagnostic("Client Error", clientErrorDetail(action, err))
+}
+
+// adminTokenRejectedDetail explains that the admin API rejected the token
+// of the provider. Only the first rejection of an endpoint is explained in
+// full, the following ones refer to it.
+func adminTokenRejectedDetail(action string, authErr *client.AuthError) string {
+	endpoint := strings.TrimPrefix(strings.SplitN(authErr.Path, "?", 2)[0], "/v2/")
+	if authErr.Repeated {
+		return fmt.Sprintf("%s: the admin API already rejected the token of the provider for %s during this operation, "+
+			"so the request was not sent again. See the first Admin Token Rejected error.", action, endpoint)
+	}
+
+	return clientErrorDetail(action, authErr) + "\n\n" +
+		fmt.Sprintf("The admin API rejected the token of the provider for %s. ", endpoint) +
+		"Check the token attribute of the provider, and the variable or environment variable such as GARAGE_TOKEN it is set from. " +
+		fmt.Sprintf("The token may be mistyped or expired, or its scope may not include %s.", endpoint)
+}
+
+// clientErrorDetail returns action followed by err. The method, path, status
+// and Garage error code of an API error are given in a consistent format, e.g.
+// "Unable to read bucket, got error: GET /v2/GetBucketInfo?id=abc returned
+// status 403 (AccessDenied): Forbidden".
+func clientErrorDetail(action string, err error) string {
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		return fmt.Sprintf("%s, got error: %s", action, err)
+	}
+
+	detail := fmt.Sprintf("%s, got error: %s %s returned status %d", action, apiErr.Method, apiErr.Path, apiErr.StatusCode)
+	if apiErr.Code != "" {
+		detail += fmt.Sprintf(" (%s)", apiErr.Code)
+	}
+
+	message := apiErr.Message
+	if message == "" && apiErr.Code == "" {
+		message = strings.TrimSpace(apiErr.Body)
+	}
+	if message != "" {
+		detail += ": " + message
+	}
+
+	return detail
+}
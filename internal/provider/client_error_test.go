@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+func TestClientErrorDetail(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name: "garage error",
+			err: &client.APIError{
+				Method: http.MethodGet, Path: "/v2/GetBucketInfo?id=abc", StatusCode: http.StatusForbidden,
+				Code: "AccessDenied", Message: "Forbidden: missing scope", Body: `{"code":"AccessDenied"}`,
+			},
+			expected: "Unable to read bucket, got error: GET /v2/GetBucketInfo?id=abc returned status 403 (AccessDenied): Forbidden: missing scope",
+		},
+		{
+			name:     "garage error without message",
+			err:      &client.APIError{Method: http.MethodPost, Path: "/v2/CreateBucket", StatusCode: http.StatusConflict, Code: "BucketAlreadyExists"},
+			expected: "Unable to read bucket, got error: POST /v2/CreateBucket returned status 409 (BucketAlreadyExists)",
+		},
+		{
+			name:     "plain body",
+			err:      &client.APIError{Method: http.MethodGet, Path: "/v2/ListBuckets", StatusCode: http.StatusBadGateway, Body: "bad gateway\n"},
+			expected: "Unable to read bucket, got error: GET /v2/ListBuckets returned status 502: bad gateway",
+		},
+		{
+			name:     "empty body",
+			err:      &client.APIError{Method: http.MethodGet, Path: "/v2/ListBuckets", StatusCode: http.StatusInternalServerError},
+			expected: "Unable to read bucket, got error: GET /v2/ListBuckets returned status 500",
+		},
+		{
+			name:     "wrapped",
+			err:      fmt.Errorf("%w: %w", client.ErrUnsupportedEndpoint, &client.APIError{Method: http.MethodGet, Path: "/v2/ListAdminTokens", StatusCode: http.StatusNotFound}),
+			expected: "Unable to read bucket, got error: GET /v2/ListAdminTokens returned status 404",
+		},
+		{
+			name:     "other error",
+			err:      errors.New("failed to execute request: connection refused"),
+			expected: "Unable to read bucket, got error: failed to execute request: connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clientErrorDetail("Unable to read bucket", tt.err); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDiagFromClientError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code": "NoSuchAccessKey", "message": "Access key not found: GKmissing", "region": "garage", "path": "/v2/DeleteKey"}`))
+	}))
+	defer server.Close()
+
+	err := client.NewClient(server.URL, "test-token").DeleteKey(context.Background(), client.DeleteKeyRequest{ID: "GKmissing"})
+	diagnostic := diagFromClientError("Unable to delete access key", err)
+
+	if diagnostic.Summary() != "Client Error" {
+		t.Errorf("Expected Client Error, got %q", diagnostic.Summary())
+	}
+	expected := "Unable to delete access key, got error: POST /v2/DeleteKey?id=GKmissing returned status 404 (NoSuchAccessKey): Access key not found: GKmissing"
+	if diagnostic.Detail() != expected {
+		t.Errorf("Expected %q, got %q", expected, diagnostic.Detail())
+	}
+}
+
+func TestDiagFromClientError_adminTokenRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"code": "AccessDenied", "message": "Forbidden: Invalid authorization token"}`))
+	}))
+	defer server.Close()
+
+	c := client.NewClient(server.URL, "expired-token")
+	var diagnostics []string
+	for _, id := range []string{"abc", "def"} {
+		bucketID := id
+		_, err := c.GetBucketInfo(context.Background(), client.GetBucketInfoRequest{ID: &bucketID})
+		diagnostic := diagFromClientError("Unable to read bucket", err)
+		if diagnostic.Summary() != "Admin Token Rejected" {
+			t.Errorf("Expected Admin Token Rejected, got %q", diagnostic.Summary())
+		}
+		diagnostics = append(diagnostics, diagnostic.Detail())
+	}
+
+	expected := "Unable to read bucket, got error: GET /v2/GetBucketInfo?id=abc returned status 403 (AccessDenied): Forbidden: Invalid authorization token\n\n" +
+		"The admin API rejected the token of the provider for GetBucketInfo. " +
+		"Check the token attribute of the provider, and the variable or environment variable such as GARAGE_TOKEN it is set from. " +
+		"The token may be mistyped or expired, or its scope may not include GetBucketInfo."
+	if diagnostics[0] != expected {
+		t.Errorf("Expected %q, got %q", expected, diagnostics[0])
+	}
+
+	expected = "Unable to read bucket: the admin API already rejected the token of the provider for GetBucketInfo during this operation, " +
+		"so the request was not sent again. See the first Admin Token Rejected error."
+	if diagnostics[1] != expected {
+		t.Errorf("Expected %q, got %q", expected, diagnostics[1])
+	}
+}
+
+func TestDiagFromClientError_adminTokenRejectedBehindProxy(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/garage/v2/GetBucketInfo" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"code": "AccessDenied", "message": "Forbidden: Invalid authorization token"}`))
+	}))
+	defer server.Close()
+
+	// The endpoint path of the reverse proxy is not part of the endpoint name
+	c := client.NewClient(server.URL+"/garage", "expired-token")
+	var diagnostics []string
+	for _, id := range []string{"abc", "def"} {
+		bucketID := id
+		_, err := c.GetBucketInfo(context.Background(), client.GetBucketInfoRequest{ID: &bucketID})
+		diagnostics = append(diagnostics, diagFromClientError("Unable to read bucket", err).Detail())
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected a single request to the rejecting endpoint, got %d", requests)
+	}
+	expected := "Unable to read bucket, got error: GET /v2/GetBucketInfo?id=abc returned status 403 (AccessDenied): Forbidden: Invalid authorization token\n\n" +
+		"The admin API rejected the token of the provider for GetBucketInfo. "
+	if !strings.HasPrefix(diagnostics[0], expected) {
+		t.Errorf("Expected %q to start with %q", diagnostics[0], expected)
+	}
+	expected = "Unable to read bucket: the admin API already rejected the token of the provider for GetBucketInfo during this operation, " +
+		"so the request was not sent again. See the first Admin Token Rejected error."
+	if diagnostics[1] != expected {
+		t.Errorf("Expected %q, got %q", expected, diagnostics[1])
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+const (
+	clusterStatusHealthy  = "healthy"
+	clusterStatusDegraded = "degraded"
+
+	// defaultClusterHealthTimeout bounds the wait for wait_for_status when
+	// timeout is not set.
+	defaultClusterHealthTimeout = 5 * time.Minute
+)
+
+// clusterHealthPollInterval is the delay before the second check of the
+// cluster health. It doubles after each check, up to
+// clusterHealthMaxPollInterval. They are variables so tests can shorten them.
+var (
+	clusterHealthPollInterval    = time.Second
+	clusterHealthMaxPollInterval = 15 * time.Second
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ClusterHealthDataSource{}
+
+func NewClusterHealthDataSource() datasource.DataSource {
+	return &ClusterHealthDataSource{}
+}
+
+// ClusterHealthDataSource defines the data source implementation.
+type ClusterHealthDataSource struct {
+	client *client.Client
+}
+
+// ClusterHealthDataSourceModel describes the data source data model.
+type ClusterHealthDataSourceModel struct {
+	WaitForStatus    types.String `tfsdk:"wait_for_status"`
+	Timeout          types.String `tfsdk:"timeout"`
+	Status           types.String `tfsdk:"status"`
+	KnownNodes       types.Int64  `tfsdk:"known_nodes"`
+	ConnectedNodes   types.Int64  `tfsdk:"connected_nodes"`
+	StorageNodes     types.Int64  `tfsdk:"storage_nodes"`
+	StorageNodesUp   types.Int64  `tfsdk:"storage_nodes_up"`
+	Partitions       types.Int64  `tfsdk:"partitions"`
+	PartitionsQuorum types.Int64  `tfsdk:"partitions_quorum"`
+	PartitionsAllOk  types.Int64  `tfsdk:"partitions_all_ok"`
+}
+
+func (d *ClusterHealthDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_health"
+}
+
+func (d *ClusterHealthDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads the health of the Garage cluster, e.g. to check it in a precondition. " +
+			"With `wait_for_status`, waits for the cluster to reach a status first, e.g. right after a layout change or a node restart.",
+
+		Attributes: map[string]schema.Attribute{
+			"wait_for_status": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Poll the cluster health until the status is at least this one: `healthy`, or `degraded`, which is also reached by a healthy cluster. " +
+					"The read fails when the status is not reached within `timeout`. The health is read once when not set.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(clusterStatusHealthy, clusterStatusDegraded),
+				},
+			},
+			"timeout": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "How long to wait for `wait_for_status`, as a Go duration. Defaults to `5m`.",
+				Validators: []validator.String{
+					validators.DurationBetween(time.Second, 24*time.Hour),
+					stringvalidator.AlsoRequires(path.MatchRoot("wait_for_status")),
+				},
+			},
+			"status": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The status of the cluster: `healthy`, `degraded` or `unavailable`.",
+			},
+			"known_nodes": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The number of nodes the answering node knows of.",
+			},
+			"connected_nodes": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The number of nodes the answering node is connected to.",
+			},
+			"storage_nodes": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The number of storage nodes in the current layout.",
+			},
+			"storage_nodes_up": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The number of storage nodes the answering node is connected to.",
+			},
+			"partitions": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The number of partitions of the layout.",
+			},
+			"partitions_quorum": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The number of partitions with a quorum of their nodes up.",
+			},
+			"partitions_all_ok": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The number of partitions with all their nodes up.",
+			},
+		},
+	}
+}
+
+func (d *ClusterHealthDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	var diags diag.Diagnostics
+	d.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
+}
+
+func (d *ClusterHealthDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ClusterHealthDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Reading cluster health data source", map[string]interface{}{
+		"wait_for_status": data.WaitForStatus.ValueString(),
+	})
+
+	if data.WaitForStatus.IsNull() {
+		health, err := d.client.GetClusterHealth(ctx)
+		if err != nil {
+			resp.Diagnostics.Append(diagFromClientError("Unable to read cluster health", err))
+			return
+		}
+		setClusterHealthModel(&data, health)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	timeout := defaultClusterHealthTimeout
+	if !data.Timeout.IsNull() {
+		var err error
+		timeout, err = time.ParseDuration(data.Timeout.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("timeout"),
+				"Invalid Duration",
+				fmt.Sprintf("Unable to parse timeout %q: %s", data.Timeout.ValueString(), err),
+			)
+			return
+		}
+	}
+
+	want := data.WaitForStatus.ValueString()
+	health, reason := d.waitForStatus(ctx, want, timeout)
+	if health != nil {
+		// The last health read is kept even when the status was not reached
+		setClusterHealthModel(&data, health)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	}
+	if reason != "" {
+		resp.Diagnostics.AddError(
+			"Cluster Health Timeout",
+			fmt.Sprintf("The cluster did not become %s within %s: %s.", want, timeout, reason),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "Read cluster health data source", map[string]interface{}{
+		"status": health.Status,
+	})
+}
+
+// waitForStatus polls the cluster health, backing off between checks, until
+// its status is at least want or timeout expires. It returns the last health
+// read, nil if none could be, and, when want was not reached, the reason why.
+func (d *ClusterHealthDataSource) waitForStatus(ctx context.Context, want string, timeout time.Duration) (*client.ClusterHealth, string) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var last *client.ClusterHealth
+	var reason string
+	delay := clusterHealthPollInterval
+	for {
+		health, err := d.client.GetClusterHealth(ctx)
+		switch {
+		case err == nil && clusterStatusRank(health.Status) >= clusterStatusRank(want):
+			return health, ""
+		case err == nil:
+			last = health
+			reason = fmt.Sprintf("last status %s", health.Status)
+			if short := clusterHealthShortfall(health, want); len(short) > 0 {
+				reason += " with " + strings.Join(short, ", ")
+			}
+		case ctx.Err() == nil || reason == "":
+			// A request interrupted by the timeout keeps the last reason
+			reason = err.Error()
+		}
+
+		tflog.Debug(ctx, "Waiting for the cluster health", map[string]interface{}{
+			"wait_for_status": want,
+			"reason":          reason,
+			"delay":           delay.String(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return last, reason
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, clusterHealthMaxPollInterval)
+	}
+}
+
+// clusterStatusRank orders the cluster statuses from unavailable to healthy.
+func clusterStatusRank(status string) int {
+	switch status {
+	case clusterStatusHealthy:
+		return 2
+	case clusterStatusDegraded:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// clusterHealthShortfall describes the counters of health that keep the
+// cluster from reaching want.
+func clusterHealthShortfall(health *client.ClusterHealth, want string) []string {
+	var short []string
+	if health.PartitionsQuorum < health.Partitions {
+		short = append(short, fmt.Sprintf("partitions_quorum %d of %d", health.PartitionsQuorum, health.Partitions))
+	}
+	if want != clusterStatusHealthy {
+		return short
+	}
+	if health.StorageNodesUp < health.StorageNodes {
+		short = append(short, fmt.Sprintf("storage_nodes_up %d of %d", health.StorageNodesUp, health.StorageNodes))
+	}
+	if health.PartitionsAllOk < health.Partitions {
+		short = append(short, fmt.Sprintf("partitions_all_ok %d of %d", health.PartitionsAllOk, health.Partitions))
+	}
+	return short
+}
+
+// setClusterHealthModel sets the computed attributes of data from health.
+func setClusterHealthModel(data *ClusterHealthDataSourceModel, health *client.ClusterHealth) {
+	data.Status = types.StringValue(health.Status)
+	data.KnownNodes = types.Int64Value(health.KnownNodes)
+	data.ConnectedNodes = types.Int64Value(health.ConnectedNodes)
+	data.StorageNodes = types.Int64Value(health.StorageNodes)
+	data.StorageNodesUp = types.Int64Value(health.StorageNodesUp)
+	data.Partitions = types.Int64Value(health.Partitions)
+	data.PartitionsQuorum = types.Int64Value(health.PartitionsQuorum)
+	data.PartitionsAllOk = types.Int64Value(health.PartitionsAllOk)
+}
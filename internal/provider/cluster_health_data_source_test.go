@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+const (
+	testClusterUnavailable = `{"status": "unavailable", "knownNodes": 3, "connectedNodes": 1, "storageNodes": 3, "storageNodesUp": 1, "partitions": 256, "partitionsQuorum": 0, "partitionsAllOk": 0}`
+	testClusterDegraded    = `{"status": "degraded", "knownNodes": 3, "connectedNodes": 2, "storageNodes": 3, "storageNodesUp": 2, "partitions": 256, "partitionsQuorum": 256, "partitionsAllOk": 0}`
+	testClusterHealthy     = `{"status": "healthy", "knownNodes": 3, "connectedNodes": 3, "storageNodes": 3, "storageNodesUp": 3, "partitions": 256, "partitionsQuorum": 256, "partitionsAllOk": 256}`
+)
+
+func TestClusterHealthDataSource(t *testing.T) {
+	oldInterval := clusterHealthPollInterval
+	clusterHealthPollInterval = time.Millisecond
+	defer func() { clusterHealthPollInterval = oldInterval }()
+
+	tests := []struct {
+		name     string
+		attrs    map[string]tftypes.Value
+		healths  []string
+		status   string
+		calls    int64
+		errorMsg string
+	}{
+		{
+			name:    "no wait",
+			healths: []string{testClusterDegraded, testClusterHealthy},
+			status:  "degraded",
+			calls:   1,
+		},
+		{
+			name:    "wait for healthy",
+			attrs:   map[string]tftypes.Value{"wait_for_status": tftypes.NewValue(tftypes.String, "healthy")},
+			healths: []string{testClusterUnavailable, testClusterDegraded, testClusterHealthy},
+			status:  "healthy",
+			calls:   3,
+		},
+		{
+			name:    "wait for degraded",
+			attrs:   map[string]tftypes.Value{"wait_for_status": tftypes.NewValue(tftypes.String, "degraded")},
+			healths: []string{testClusterUnavailable, testClusterHealthy},
+			status:  "healthy",
+			calls:   2,
+		},
+		{
+			name: "timeout",
+			attrs: map[string]tftypes.Value{
+				"wait_for_status": tftypes.NewValue(tftypes.String, "healthy"),
+				"timeout":         tftypes.NewValue(tftypes.String, "50ms"),
+			},
+			healths:  []string{testClusterDegraded},
+			status:   "degraded",
+			errorMsg: "The cluster did not become healthy within 50ms: last status degraded with storage_nodes_up 2 of 3, partitions_all_ok 0 of 256.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v2/GetClusterHealth" {
+					t.Errorf("Unexpected request to %s", r.URL.Path)
+				}
+				i := int(calls.Add(1)) - 1
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.healths[min(i, len(tt.healths)-1)]))
+			}))
+			defer server.Close()
+
+			d := &ClusterHealthDataSource{client: client.NewClient(server.URL, "test-token")}
+			resp := testDataSourceRead(t, d, tt.attrs)
+
+			if tt.errorMsg != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Detail() != tt.errorMsg {
+					t.Errorf("Expected the error %q, got %v", tt.errorMsg, resp.Diagnostics)
+				}
+			} else if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if tt.calls != 0 && calls.Load() != tt.calls {
+				t.Errorf("Expected %d health checks, got %d", tt.calls, calls.Load())
+			}
+
+			var data ClusterHealthDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+			if data.Status.ValueString() != tt.status {
+				t.Errorf("Expected status %s, got %s", tt.status, data.Status)
+			}
+		})
+	}
+}
+
+func TestClusterHealthShortfall(t *testing.T) {
+	health := &client.ClusterHealth{StorageNodes: 3, StorageNodesUp: 1, Partitions: 256, PartitionsQuorum: 128, PartitionsAllOk: 0}
+
+	if short := strings.Join(clusterHealthShortfall(health, "degraded"), ", "); short != "partitions_quorum 128 of 256" {
+		t.Errorf("Unexpected degraded shortfall %q", short)
+	}
+	expected := "partitions_quorum 128 of 256, storage_nodes_up 1 of 3, partitions_all_ok 0 of 256"
+	if short := strings.Join(clusterHealthShortfall(health, "healthy"), ", "); short != expected {
+		t.Errorf("Expected healthy shortfall %q, got %q", expected, short)
+	}
+}
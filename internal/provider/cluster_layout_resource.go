@@ -0,0 +1,931 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ClusterLayoutResource{}
+var _ resource.ResourceWithValidateConfig = &ClusterLayoutResource{}
+var _ resource.ResourceWithModifyPlan = &ClusterLayoutResource{}
+var _ resource.ResourceWithImportState = &ClusterLayoutResource{}
+
+// clusterLayoutID is the ID of the only layout of a cluster.
+const clusterLayoutID = "cluster"
+
+// stagedParametersID stands for the layout parameters in staged_changes.
+const stagedParametersID = "parameters"
+
+// rebalancePollInterval is the delay between two checks of the cluster health
+// after removing nodes. It is a variable so tests can shorten it.
+var rebalancePollInterval = 5 * time.Second
+
+func NewClusterLayoutResource() resource.Resource {
+	return &ClusterLayoutResource{}
+}
+
+// ClusterLayoutResource defines the resource implementation.
+type ClusterLayoutResource struct {
+	client *client.Client
+}
+
+// ClusterLayoutResourceModel describes the resource data model.
+type ClusterLayoutResourceModel struct {
+	ID                types.String `tfsdk:"id"`
+	Nodes             types.Set    `tfsdk:"node"`
+	AutoApply         types.Bool   `tfsdk:"auto_apply"`
+	AllowDecommission types.Bool   `tfsdk:"allow_decommission"`
+	WaitForRebalance  types.String `tfsdk:"wait_for_rebalance"`
+	Parameters        types.Object `tfsdk:"parameters"`
+	Version           types.Int64  `tfsdk:"version"`
+	StagedChanges     types.List   `tfsdk:"staged_changes"`
+	PendingTransfers  types.Int64  `tfsdk:"pending_transfer_partitions"`
+}
+
+// ClusterLayoutNodeModel describes the role of a node in the layout.
+type ClusterLayoutNodeModel struct {
+	ID       types.String `tfsdk:"id"`
+	Zone     types.String `tfsdk:"zone"`
+	Capacity types.Int64  `tfsdk:"capacity"`
+	Gateway  types.Bool   `tfsdk:"gateway"`
+	Tags     types.Set    `tfsdk:"tags"`
+}
+
+// ClusterLayoutParametersModel describes the cluster-wide parameters of the
+// layout.
+type ClusterLayoutParametersModel struct {
+	ZoneRedundancy types.String `tfsdk:"zone_redundancy"`
+}
+
+// clusterLayoutParametersAttrTypes are the attribute types of the parameters
+// object.
+var clusterLayoutParametersAttrTypes = map[string]attr.Type{
+	"zone_redundancy": types.StringType,
+}
+
+// clusterLayoutNodeAttrTypes are the attribute types of a node object.
+var clusterLayoutNodeAttrTypes = map[string]attr.Type{
+	"id":       types.StringType,
+	"zone":     types.StringType,
+	"capacity": types.Int64Type,
+	"gateway":  types.BoolType,
+	"tags":     types.SetType{ElemType: types.StringType},
+}
+
+func (r *ClusterLayoutResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_layout"
+}
+
+func (r *ClusterLayoutResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Authoritatively manages the roles of the nodes in the Garage cluster layout. " +
+			"Changes are staged, then applied as a new layout version unless `auto_apply` is `false`. " +
+			"If applying fails, the changes staged by this resource are reverted. " +
+			"The resource refuses to run while changes staged by someone else are pending. " +
+			"Removing nodes from the layout requires `allow_decommission`. " +
+			"Nodes with a `capacity` store data, nodes with `gateway = true` only serve requests. " +
+			"Only one instance may exist per cluster and it must not be mixed with `garage_node_role`. Destroying the resource leaves the layout as is.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The identifier of the resource, always `cluster`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"node": schema.SetNestedAttribute{
+				Required:            true,
+				MarkdownDescription: "The complete set of nodes in the layout. Nodes of the layout that are not listed are removed from it.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "The full ID of the node, as printed by `garage node id`.",
+							Validators: []validator.String{
+								validators.NodeID(),
+							},
+						},
+						"zone": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "The zone of the node. Garage spreads the copies of the data across zones.",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"capacity": schema.Int64Attribute{
+							Optional:            true,
+							MarkdownDescription: "The storage capacity of the node in bytes. Exactly one of `capacity` or `gateway` must be set.",
+							Validators: []validator.Int64{
+								int64validator.AtLeast(1),
+							},
+						},
+						"gateway": schema.BoolAttribute{
+							Optional:            true,
+							Computed:            true,
+							MarkdownDescription: "Whether the node is a gateway, which serves requests without storing data. Exactly one of `capacity` or `gateway = true` must be set. Defaults to `false`.",
+							Default:             booldefault.StaticBool(false),
+						},
+						"tags": schema.SetAttribute{
+							Optional:            true,
+							Computed:            true,
+							ElementType:         types.StringType,
+							MarkdownDescription: "Free-form tags of the node.",
+							Default:             setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+						},
+					},
+				},
+			},
+			"auto_apply": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Apply the staged changes as a new layout version. When `false`, the changes are only staged, to be reviewed and applied with `garage layout apply`. Defaults to `true`.",
+				Default:             booldefault.StaticBool(true),
+			},
+			"allow_decommission": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Allow removing nodes from the layout. Their data is moved to the remaining nodes, which is unsafe while the cluster is not fully rebalanced. Plans removing nodes fail without it. Defaults to `false`.",
+				Default:             booldefault.StaticBool(false),
+			},
+			"wait_for_rebalance": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Maximum time to wait after applying the removal of nodes for every partition to be back to a quorum of copies, as a Go duration (e.g. `30m`). Defaults to `0s` (no wait).",
+				Default:             stringdefault.StaticString("0s"),
+				Validators: []validator.String{
+					validators.DurationBetween(0, 7*24*time.Hour),
+				},
+			},
+			"parameters": schema.SingleNestedAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The cluster-wide parameters of the layout, staged and applied along with the roles. Read from the cluster when not set.",
+				Attributes: map[string]schema.Attribute{
+					"zone_redundancy": schema.StringAttribute{
+						Required:            true,
+						MarkdownDescription: "The number of zones holding a copy of each partition: `maximum` to spread them across as many zones as possible, or a positive integer such as `2`.",
+						Validators: []validator.String{
+							validators.ZoneRedundancy(),
+						},
+					},
+				},
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"version": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The current version of the layout.",
+			},
+			"staged_changes": schema.ListAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The IDs of the nodes whose changes were staged by this resource but not applied yet, and `parameters` when the layout parameters were changed. Always empty when `auto_apply` is `true`.",
+			},
+			"pending_transfer_partitions": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The number of partition copies that applying the changes in `staged_changes` moves between nodes, as previewed by the cluster. Null when no changes are staged or the cluster cannot preview them.",
+			},
+		},
+	}
+}
+
+func (r *ClusterLayoutResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	var diags diag.Diagnostics
+	r.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterLayoutResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var nodes types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("node"), &nodes)...)
+	if resp.Diagnostics.HasError() || nodes.IsNull() || nodes.IsUnknown() {
+		return
+	}
+
+	var models []ClusterLayoutNodeModel
+	resp.Diagnostics.Append(nodes.ElementsAs(ctx, &models, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, node := range models {
+		if detail := nodeRoleError(node); detail != "" {
+			resp.Diagnostics.AddAttributeError(path.Root("node"), "Invalid Node Role", detail)
+		}
+	}
+}
+
+func (r *ClusterLayoutResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy, the layout is left as is
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan ClusterLayoutResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state ClusterLayoutResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(warnPendingTransfers(ctx, plan, state)...)
+	}
+
+	// Parameters read from the cluster are computed, only configured ones
+	// need a server supporting them
+	var parameters types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("parameters"), &parameters)...)
+	if !parameters.IsNull() && !parameters.IsUnknown() && r.client != nil {
+		resp.Diagnostics.Append(requireGarageCapability(ctx, r.client, capabilityLayoutParameters)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Nothing stays staged when the changes are applied
+	if plan.AutoApply.ValueBool() && plan.PendingTransfers.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("pending_transfer_partitions"), types.Int64Null())...)
+	}
+
+	if plan.Nodes.IsUnknown() || plan.AllowDecommission.IsUnknown() || plan.AllowDecommission.ValueBool() {
+		return
+	}
+
+	var nodes []ClusterLayoutNodeModel
+	resp.Diagnostics.Append(plan.Nodes.ElementsAs(ctx, &nodes, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	planned := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		if node.ID.IsUnknown() {
+			// Removals are checked again on apply
+			return
+		}
+		planned[node.ID.ValueString()] = true
+	}
+
+	// The refreshed state holds the current layout; without state, the
+	// resource takes over the layout of the cluster
+	var current []string
+	if req.State.Raw.IsNull() {
+		if r.client == nil {
+			return
+		}
+		layout, err := r.client.GetClusterLayout(ctx)
+		if err != nil {
+			resp.Diagnostics.Append(diagFromClientError("Unable to read cluster layout", err))
+			return
+		}
+		for _, role := range layout.Roles {
+			current = append(current, role.ID)
+		}
+	} else {
+		var state ClusterLayoutResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		roles, diags := clusterLayoutRolesFromModel(ctx, state.Nodes)
+		resp.Diagnostics.Append(diags...)
+		current = sortedKeys(roles)
+	}
+
+	var removed []string
+	for _, id := range current {
+		if !planned[id] {
+			removed = append(removed, id)
+		}
+	}
+	if len(removed) > 0 {
+		sort.Strings(removed)
+		resp.Diagnostics.AddAttributeError(path.Root("allow_decommission"), "Node Removal Not Allowed", decommissionDetail(removed))
+	}
+}
+
+func (r *ClusterLayoutResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ClusterLayoutResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	removed, diags := r.stage(ctx, &data, nil)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The layout is applied: it is kept in state even if the cluster does
+	// not rebalance in time
+	resp.Diagnostics.Append(r.waitForRebalance(ctx, &data, removed)...)
+
+	tflog.Trace(ctx, "Created cluster layout resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ClusterLayoutResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ClusterLayoutResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	layout, err := r.client.GetClusterLayout(ctx)
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError("Unable to read cluster layout", err))
+		return
+	}
+
+	ours, diags := stagedChangeIDs(ctx, data.StagedChanges)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Report the nodes as staged by this resource, so that not applying them
+	// yet does not show as a difference with the configuration
+	var staged []client.NodeRoleChange
+	for _, change := range layout.StagedRoleChanges {
+		if ours[change.ID] {
+			staged = append(staged, change)
+		}
+	}
+
+	parameters := layout.Parameters
+	stagedParameters := ours[stagedParametersID] && layout.StagedParameters != nil
+	if stagedParameters {
+		parameters = layout.StagedParameters
+	}
+
+	nodes, diags := clusterLayoutNodesValue(ctx, applyRoleChanges(layout.Roles, staged))
+	resp.Diagnostics.Append(diags...)
+	data.Nodes = nodes
+	data.Parameters = clusterLayoutParametersValue(parameters)
+	data.ID = types.StringValue(clusterLayoutID)
+	data.Version = types.Int64Value(layout.Version)
+	data.StagedChanges = stagedChangesValue(staged, stagedParameters)
+	data.PendingTransfers = types.Int64Null()
+
+	// The preview covers every staged change, it only describes this
+	// resource's changes when nobody else staged any
+	onlyOurs := len(staged) == len(layout.StagedRoleChanges) && (layout.StagedParameters == nil || stagedParameters)
+	if (len(staged) > 0 || stagedParameters) && onlyOurs {
+		data.PendingTransfers = r.previewTransfers(ctx)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ClusterLayoutResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state ClusterLayoutResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ours, diags := stagedChangeIDs(ctx, state.StagedChanges)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	removed, diags := r.stage(ctx, &data, ours)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The layout is applied: it is kept in state even if the cluster does
+	// not rebalance in time
+	resp.Diagnostics.Append(r.waitForRebalance(ctx, &data, removed)...)
+
+	tflog.Trace(ctx, "Updated cluster layout resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ClusterLayoutResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Removing every role would take all the data of the cluster offline,
+	// the layout is left as is
+	tflog.Debug(ctx, "Removing cluster layout resource from state, the layout is unchanged")
+}
+
+func (r *ClusterLayoutResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID != clusterLayoutID {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("The cluster has a single layout, imported with the ID %q, got: %s", clusterLayoutID, req.ID),
+		)
+		return
+	}
+
+	// Defaults are not applied on import, set them so that a configuration
+	// leaving them out plans no change. Read fills in the nodes, parameters
+	// and version from the current layout; nothing is staged by this
+	// resource yet.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &ClusterLayoutResourceModel{
+		ID:                types.StringValue(clusterLayoutID),
+		Nodes:             types.SetNull(types.ObjectType{AttrTypes: clusterLayoutNodeAttrTypes}),
+		AutoApply:         types.BoolValue(true),
+		AllowDecommission: types.BoolValue(false),
+		WaitForRebalance:  types.StringValue("0s"),
+		Parameters:        types.ObjectNull(clusterLayoutParametersAttrTypes),
+		Version:           types.Int64Null(),
+		StagedChanges:     types.ListValueMust(types.StringType, []attr.Value{}),
+		PendingTransfers:  types.Int64Null(),
+	})...)
+}
+
+// stage stages the changes needed for the layout to match the nodes of data,
+// then applies them unless auto_apply is false. ours holds the IDs of the
+// nodes with changes staged by a previous run of this resource; any other
+// staged change makes it fail without touching the layout, as does removing
+// nodes without allow_decommission. It sets the computed attributes of data
+// and returns the IDs of the nodes whose removal was applied.
+func (r *ClusterLayoutResource) stage(ctx context.Context, data *ClusterLayoutResourceModel, ours map[string]bool) ([]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	desired, d := clusterLayoutRolesFromModel(ctx, data.Nodes)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	layout, err := r.client.GetClusterLayout(ctx)
+	if err != nil {
+		diags.Append(diagFromClientError("Unable to read cluster layout", err))
+		return nil, diags
+	}
+
+	var unrelated []string
+	for _, change := range layout.StagedRoleChanges {
+		if !ours[change.ID] {
+			unrelated = append(unrelated, change.ID)
+		}
+	}
+	unrelatedParameters := layout.StagedParameters != nil && !ours[stagedParametersID]
+	if len(unrelated) > 0 || unrelatedParameters {
+		var targets []string
+		if len(unrelated) > 0 {
+			sort.Strings(unrelated)
+			targets = append(targets, "nodes "+strings.Join(unrelated, ", "))
+		}
+		if unrelatedParameters {
+			targets = append(targets, "the layout parameters")
+		}
+		diags.AddError(
+			"Unrelated Staged Layout Changes",
+			fmt.Sprintf("The cluster layout has staged changes that were not made by this resource, for %s. "+
+				"Apply them with `garage layout apply` or discard them with `garage layout revert` before running Terraform again.",
+				strings.Join(targets, " and ")),
+		)
+		return nil, diags
+	}
+
+	// Start over from the applied layout rather than amending a previous run
+	if len(layout.StagedRoleChanges) > 0 || layout.StagedParameters != nil {
+		tflog.Debug(ctx, "Reverting the layout changes previously staged by this resource")
+		layout, err = r.client.RevertClusterLayout(ctx)
+		if err != nil {
+			diags.Append(diagFromClientError("Unable to revert previously staged layout changes", err))
+			return nil, diags
+		}
+	}
+
+	changes := clusterLayoutRoleChanges(layout.Roles, desired)
+
+	var removed []string
+	for _, change := range changes {
+		if change.Remove {
+			removed = append(removed, change.ID)
+		}
+	}
+	if len(removed) > 0 && !data.AllowDecommission.ValueBool() {
+		diags.AddError("Node Removal Not Allowed", decommissionDetail(removed))
+		return nil, diags
+	}
+
+	// Parameters are only managed when configured
+	var parameters *client.LayoutParameters
+	if !data.Parameters.IsNull() && !data.Parameters.IsUnknown() {
+		var model ClusterLayoutParametersModel
+		diags.Append(data.Parameters.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		zoneRedundancy := client.ZoneRedundancy(model.ZoneRedundancy.ValueString())
+		if layout.Parameters == nil || layout.Parameters.ZoneRedundancy != zoneRedundancy {
+			parameters = &client.LayoutParameters{ZoneRedundancy: zoneRedundancy}
+		}
+	}
+
+	data.ID = types.StringValue(clusterLayoutID)
+	data.Version = types.Int64Value(layout.Version)
+	data.StagedChanges = stagedChangesValue(nil, false)
+	data.PendingTransfers = types.Int64Null()
+	if parameters == nil {
+		data.Parameters = clusterLayoutParametersValue(layout.Parameters)
+	}
+
+	if len(changes) == 0 && parameters == nil {
+		return nil, diags
+	}
+
+	tflog.Debug(ctx, "Staging cluster layout changes", map[string]interface{}{
+		"changes":    len(changes),
+		"parameters": parameters != nil,
+		"version":    layout.Version,
+	})
+
+	update := client.UpdateClusterLayoutRequest{Roles: changes, Parameters: parameters}
+	if update.Roles == nil {
+		update.Roles = []client.NodeRoleChange{}
+	}
+	if _, err := r.client.UpdateClusterLayout(ctx, update); err != nil {
+		diags.Append(diagFromClientError("Unable to stage cluster layout changes", err))
+		return nil, diags
+	}
+
+	if !data.AutoApply.ValueBool() {
+		data.StagedChanges = stagedChangesValue(changes, parameters != nil)
+		data.PendingTransfers = r.previewTransfers(ctx)
+		return nil, diags
+	}
+
+	applied, err := r.client.ApplyClusterLayout(ctx, layout.Version+1)
+	if err != nil {
+		// Only this resource's changes are staged, reverting cannot lose
+		// anyone else's work
+		detail := clientErrorDetail(fmt.Sprintf("Unable to apply cluster layout version %d", layout.Version+1), err)
+		if _, revertErr := r.client.RevertClusterLayout(ctx); revertErr != nil {
+			detail += fmt.Sprintf("\n\nReverting the staged changes also failed, run `garage layout revert` before retrying: %s", revertErr)
+		} else {
+			detail += "\n\nThe staged changes were reverted."
+		}
+		diags.AddError("Failed to Apply Cluster Layout", detail)
+		return nil, diags
+	}
+
+	tflog.Info(ctx, "Applied cluster layout", map[string]interface{}{
+		"version": applied.Version,
+	})
+
+	data.Version = types.Int64Value(applied.Version)
+	data.Parameters = clusterLayoutParametersValue(applied.Parameters)
+	return removed, diags
+}
+
+// previewTransfers returns the number of partition copies that applying the
+// staged changes moves between nodes, or null when the cluster cannot tell.
+func (r *ClusterLayoutResource) previewTransfers(ctx context.Context) types.Int64 {
+	preview, err := r.client.PreviewClusterLayoutChanges(ctx)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to preview the staged layout changes", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return types.Int64Null()
+	}
+
+	transfers, ok := preview.PartitionTransfers()
+	if !ok {
+		return types.Int64Null()
+	}
+	return types.Int64Value(transfers)
+}
+
+// warnPendingTransfers warns when the plan applies the changes previously
+// staged by the resource and their preview moves partitions, so that the
+// rebalance shows up in the plan rather than after the apply. Plans changing
+// the layout again are not previewed: the preview only covers staged changes.
+func warnPendingTransfers(ctx context.Context, plan, state ClusterLayoutResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if state.PendingTransfers.IsNull() || state.PendingTransfers.ValueInt64() == 0 {
+		return diags
+	}
+	if plan.AutoApply.IsUnknown() || !plan.AutoApply.ValueBool() || !plan.Nodes.Equal(state.Nodes) || !plan.Parameters.Equal(state.Parameters) {
+		return diags
+	}
+
+	var staged []string
+	diags.Append(state.StagedChanges.ElementsAs(ctx, &staged, false)...)
+
+	diags.AddWarning(
+		"Layout Rebalance Pending",
+		fmt.Sprintf("Applying the layout changes staged for %s moves %d partition copies between nodes. "+
+			"The cluster rebalances in the background after the apply, which can take a long time on large clusters.",
+			strings.Join(staged, ", "), state.PendingTransfers.ValueInt64()),
+	)
+	return diags
+}
+
+// waitForRebalance waits up to wait_for_rebalance for every partition to be
+// back to a quorum of copies once the removal of nodes was applied.
+func (r *ClusterLayoutResource) waitForRebalance(ctx context.Context, data *ClusterLayoutResourceModel, removed []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	timeout, err := time.ParseDuration(data.WaitForRebalance.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("wait_for_rebalance"),
+			"Invalid Duration",
+			fmt.Sprintf("Unable to parse wait_for_rebalance %q: %s", data.WaitForRebalance.ValueString(), err),
+		)
+		return diags
+	}
+	if len(removed) == 0 || timeout == 0 {
+		return diags
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(rebalancePollInterval)
+	defer ticker.Stop()
+
+	var reason string
+	for {
+		health, err := r.client.GetClusterHealth(ctx)
+		switch {
+		case err == nil && health.PartitionsQuorum == health.Partitions:
+			return diags
+		case err == nil:
+			reason = fmt.Sprintf("%d of %d partitions have a quorum", health.PartitionsQuorum, health.Partitions)
+		case ctx.Err() == nil || reason == "":
+			// A request interrupted by the timeout keeps the last reason
+			reason = err.Error()
+		}
+
+		tflog.Debug(ctx, "Waiting for the cluster to rebalance", map[string]interface{}{
+			"removed": removed,
+			"reason":  reason,
+		})
+
+		select {
+		case <-ctx.Done():
+			diags.AddError(
+				"Rebalance Timeout",
+				fmt.Sprintf("The layout removing nodes %s was applied, but the cluster did not rebalance within %s: %s. "+
+					"Check `garage status` before shutting the removed nodes down.", strings.Join(removed, ", "), timeout, reason),
+			)
+			return diags
+		case <-ticker.C:
+		}
+	}
+}
+
+// decommissionDetail describes the removal of nodes without
+// allow_decommission.
+func decommissionDetail(removed []string) string {
+	return fmt.Sprintf("The plan removes nodes %s from the cluster layout. Their data is moved to the remaining nodes, "+
+		"which loses data if the cluster is not fully rebalanced. Set allow_decommission = true to remove them.", strings.Join(removed, ", "))
+}
+
+// clusterLayoutRolesFromModel converts the node set into roles by node ID.
+func clusterLayoutRolesFromModel(ctx context.Context, set types.Set) (map[string]client.NodeRole, diag.Diagnostics) {
+	var nodes []ClusterLayoutNodeModel
+	diags := set.ElementsAs(ctx, &nodes, false)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	roles := make(map[string]client.NodeRole, len(nodes))
+	for _, node := range nodes {
+		id := node.ID.ValueString()
+		if _, ok := roles[id]; ok {
+			diags.AddError("Duplicate Node", fmt.Sprintf("Node %s is listed more than once in the layout.", id))
+			continue
+		}
+
+		role, d := clusterLayoutNodeRole(ctx, node)
+		diags.Append(d...)
+		roles[id] = role
+	}
+
+	return roles, diags
+}
+
+// clusterLayoutNodeRole converts a node into its role.
+func clusterLayoutNodeRole(ctx context.Context, node ClusterLayoutNodeModel) (client.NodeRole, diag.Diagnostics) {
+	var tags []string
+	diags := node.Tags.ElementsAs(ctx, &tags, false)
+	sort.Strings(tags)
+
+	role := client.NodeRole{
+		ID:   node.ID.ValueString(),
+		Zone: node.Zone.ValueString(),
+		Tags: tags,
+	}
+	// Gateways are sent with a null capacity
+	if !node.Gateway.ValueBool() {
+		role.Capacity = node.Capacity.ValueInt64Pointer()
+	}
+	return role, diags
+}
+
+// nodeRoleError describes why a node does not set exactly one of capacity or
+// gateway = true, or returns "" when its role is valid or not known yet.
+func nodeRoleError(node ClusterLayoutNodeModel) string {
+	if node.Capacity.IsUnknown() || node.Gateway.IsUnknown() {
+		return ""
+	}
+
+	// Storage nodes have a capacity, gateways have none
+	if node.Gateway.ValueBool() == node.Capacity.IsNull() {
+		return ""
+	}
+
+	if node.Gateway.ValueBool() {
+		return fmt.Sprintf("Node %s is a gateway and cannot have a capacity.", node.ID.ValueString())
+	}
+	return fmt.Sprintf("Node %s must set exactly one of capacity or gateway = true.", node.ID.ValueString())
+}
+
+// clusterLayoutRoleChanges returns the changes turning the current roles into
+// the desired ones, sorted by node ID.
+func clusterLayoutRoleChanges(current []client.NodeRole, desired map[string]client.NodeRole) []client.NodeRoleChange {
+	var changes []client.NodeRoleChange
+
+	currentByID := make(map[string]client.NodeRole, len(current))
+	for _, role := range current {
+		currentByID[role.ID] = role
+		if _, ok := desired[role.ID]; !ok {
+			changes = append(changes, client.NodeRoleChange{NodeRole: client.NodeRole{ID: role.ID}, Remove: true})
+		}
+	}
+
+	for _, id := range sortedKeys(desired) {
+		role, ok := currentByID[id]
+		if !ok || !sameNodeRole(role, desired[id]) {
+			changes = append(changes, client.NodeRoleChange{NodeRole: desired[id]})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+	return changes
+}
+
+// sameNodeRole reports whether a and b assign the same role.
+func sameNodeRole(a, b client.NodeRole) bool {
+	if a.Zone != b.Zone {
+		return false
+	}
+	if (a.Capacity == nil) != (b.Capacity == nil) || (a.Capacity != nil && *a.Capacity != *b.Capacity) {
+		return false
+	}
+
+	tagsA := slices.Sorted(slices.Values(a.Tags))
+	tagsB := slices.Sorted(slices.Values(b.Tags))
+	return slices.Equal(tagsA, tagsB)
+}
+
+// applyRoleChanges returns roles with changes applied.
+func applyRoleChanges(roles []client.NodeRole, changes []client.NodeRoleChange) []client.NodeRole {
+	byID := make(map[string]client.NodeRole, len(roles)+len(changes))
+	for _, role := range roles {
+		byID[role.ID] = role
+	}
+	for _, change := range changes {
+		if change.Remove {
+			delete(byID, change.ID)
+		} else {
+			byID[change.ID] = change.NodeRole
+		}
+	}
+
+	result := make([]client.NodeRole, 0, len(byID))
+	for _, id := range sortedKeys(byID) {
+		result = append(result, byID[id])
+	}
+	return result
+}
+
+// clusterLayoutNodesValue converts roles into the node set.
+func clusterLayoutNodesValue(ctx context.Context, roles []client.NodeRole) (types.Set, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	nodes := make([]ClusterLayoutNodeModel, 0, len(roles))
+	for _, role := range roles {
+		tags := make([]attr.Value, 0, len(role.Tags))
+		for _, tag := range role.Tags {
+			tags = append(tags, types.StringValue(tag))
+		}
+
+		nodes = append(nodes, ClusterLayoutNodeModel{
+			ID:       types.StringValue(role.ID),
+			Zone:     types.StringValue(role.Zone),
+			Capacity: types.Int64PointerValue(role.Capacity),
+			Gateway:  types.BoolValue(role.Capacity == nil),
+			Tags:     types.SetValueMust(types.StringType, tags),
+		})
+	}
+
+	set, d := types.SetValueFrom(ctx, types.ObjectType{AttrTypes: clusterLayoutNodeAttrTypes}, nodes)
+	diags.Append(d...)
+	return set, diags
+}
+
+// clusterLayoutParametersValue converts the layout parameters into the
+// parameters object, null when the server does not report them.
+func clusterLayoutParametersValue(parameters *client.LayoutParameters) types.Object {
+	if parameters == nil {
+		return types.ObjectNull(clusterLayoutParametersAttrTypes)
+	}
+
+	return types.ObjectValueMust(clusterLayoutParametersAttrTypes, map[string]attr.Value{
+		"zone_redundancy": types.StringValue(string(parameters.ZoneRedundancy)),
+	})
+}
+
+// stagedChangeIDs returns the node IDs of the staged_changes attribute.
+func stagedChangeIDs(ctx context.Context, list types.List) (map[string]bool, diag.Diagnostics) {
+	ids := make(map[string]bool)
+	if list.IsNull() || list.IsUnknown() {
+		return ids, nil
+	}
+
+	var values []string
+	diags := list.ElementsAs(ctx, &values, false)
+	for _, id := range values {
+		ids[id] = true
+	}
+	return ids, diags
+}
+
+// stagedChangesValue converts staged changes into the staged_changes
+// attribute, sorted by node ID, followed by stagedParametersID when the
+// parameters were changed.
+func stagedChangesValue(changes []client.NodeRoleChange, parameters bool) types.List {
+	ids := make([]string, 0, len(changes)+1)
+	for _, change := range changes {
+		ids = append(ids, change.ID)
+	}
+	sort.Strings(ids)
+	if parameters {
+		ids = append(ids, stagedParametersID)
+	}
+
+	values := make([]attr.Value, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, types.StringValue(id))
+	}
+	return types.ListValueMust(types.StringType, values)
+}
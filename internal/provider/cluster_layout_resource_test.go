@@ -0,0 +1,938 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+func TestAccClusterLayoutResource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccClusterLayoutVariables(t)
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccClusterLayoutResourceConfig(true, `["acc"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_cluster_layout.test", "id", "cluster"),
+					resource.TestCheckResourceAttr("garage_cluster_layout.test", "node.#", "1"),
+					resource.TestCheckResourceAttr("garage_cluster_layout.test", "staged_changes.#", "0"),
+					resource.TestCheckResourceAttrSet("garage_cluster_layout.test", "version"),
+				),
+			},
+			// Changes are only staged without auto_apply
+			{
+				Config: testAccClusterLayoutResourceConfig(false, `["acc", "staged"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_cluster_layout.test", "staged_changes.#", "1"),
+					resource.TestCheckResourceAttr("garage_cluster_layout.test", "node.0.tags.#", "2"),
+				),
+			},
+			// The changes staged by the previous step are applied
+			{
+				Config: testAccClusterLayoutResourceConfig(true, `["acc", "staged"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_cluster_layout.test", "staged_changes.#", "0"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccClusterLayoutResource_gateway(t *testing.T) {
+	storage := strings.Repeat("a", 64)
+	gateway := strings.Repeat("b", 64)
+	server := newTestLayoutServer(t, client.ClusterLayout{
+		Version: 1,
+		Roles:   []client.NodeRole{testNodeRole(storage, "dc1", 1000)},
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Invalid roles are rejected before reaching the API
+			{
+				Config:      testAccClusterLayoutResourceConfig_gateway(server.URL, storage, gateway, "capacity = 1000\n      gateway = true"),
+				ExpectError: regexp.MustCompile(`is a gateway and cannot have a capacity`),
+			},
+			{
+				Config:      testAccClusterLayoutResourceConfig_gateway(server.URL, storage, gateway, ""),
+				ExpectError: regexp.MustCompile(`must set exactly one of capacity or gateway`),
+			},
+			// A mixed layout is applied, then read back without difference
+			{
+				Config: testAccClusterLayoutResourceConfig_gateway(server.URL, storage, gateway, "gateway = true"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_cluster_layout.test", "version", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("garage_cluster_layout.test", "node.*", map[string]string{
+						"id":      gateway,
+						"gateway": "true",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("garage_cluster_layout.test", "node.*", map[string]string{
+						"id":       storage,
+						"capacity": "1000",
+						"gateway":  "false",
+					}),
+					func(*terraform.State) error {
+						server.mu.Lock()
+						defer server.mu.Unlock()
+						for _, role := range server.layout.Roles {
+							if role.ID == gateway && role.Capacity != nil {
+								return fmt.Errorf("expected the gateway to be staged without capacity, got %d", *role.Capacity)
+							}
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccClusterLayoutResource_decommission(t *testing.T) {
+	nodeA := strings.Repeat("a", 64)
+	nodeB := strings.Repeat("b", 64)
+	server := newTestLayoutServer(t, client.ClusterLayout{
+		Version: 1,
+		Roles:   []client.NodeRole{testNodeRole(nodeA, "dc1", 1000), testNodeRole(nodeB, "dc1", 1000)},
+	})
+	server.unbalancedChecks = 2
+
+	oldInterval := rebalancePollInterval
+	rebalancePollInterval = time.Millisecond
+	defer func() { rebalancePollInterval = oldInterval }()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Removing node B fails at plan time without allow_decommission
+			{
+				Config:      testAccClusterLayoutResourceConfig_decommission(server.URL, nodeA, false),
+				ExpectError: regexp.MustCompile(`(?s)Node Removal Not Allowed.*removes nodes\s+` + nodeB),
+			},
+			{
+				Config: testAccClusterLayoutResourceConfig_decommission(server.URL, nodeA, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_cluster_layout.test", "version", "2"),
+					resource.TestCheckResourceAttr("garage_cluster_layout.test", "node.#", "1"),
+					func(*terraform.State) error {
+						server.mu.Lock()
+						defer server.mu.Unlock()
+						if server.unbalancedChecks != 0 {
+							return fmt.Errorf("expected the cluster health to be polled until rebalanced, %d checks left", server.unbalancedChecks)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccClusterLayoutResource_preview(t *testing.T) {
+	nodeA := strings.Repeat("a", 64)
+	server := newTestLayoutServer(t, client.ClusterLayout{Version: 1})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Staged changes are previewed
+			{
+				Config: testAccClusterLayoutResourceConfig_preview(server.URL, nodeA, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_cluster_layout.test", "version", "1"),
+					resource.TestCheckResourceAttr("garage_cluster_layout.test", "pending_transfer_partitions", "64"),
+				),
+			},
+			// Applying them clears the preview
+			{
+				Config: testAccClusterLayoutResourceConfig_preview(server.URL, nodeA, true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_cluster_layout.test", "version", "2"),
+					resource.TestCheckNoResourceAttr("garage_cluster_layout.test", "pending_transfer_partitions"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccClusterLayoutResource_import(t *testing.T) {
+	storage := strings.Repeat("a", 64)
+	gateway := strings.Repeat("b", 64)
+	role := testNodeRole(storage, "dc1", 1000)
+	role.Tags = []string{"ssd"}
+	server := newTestLayoutServer(t, client.ClusterLayout{
+		Version:    3,
+		Roles:      []client.NodeRole{role, {ID: gateway, Zone: "dc2", Tags: []string{}}},
+		Parameters: &client.LayoutParameters{ZoneRedundancy: client.ZoneRedundancyMaximum},
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:        testAccClusterLayoutResourceConfig_import(server.URL, storage, gateway),
+				ResourceName:  "garage_cluster_layout.test",
+				ImportState:   true,
+				ImportStateId: storage,
+				ExpectError:   regexp.MustCompile(`Invalid Import ID`),
+			},
+			// The current layout is imported as is
+			{
+				Config:             testAccClusterLayoutResourceConfig_import(server.URL, storage, gateway),
+				ResourceName:       "garage_cluster_layout.test",
+				ImportState:        true,
+				ImportStateId:      "cluster",
+				ImportStatePersist: true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("expected 1 imported resource, got %d", len(states))
+					}
+					attributes := states[0].Attributes
+					expected := map[string]string{
+						"id":                         "cluster",
+						"version":                    "3",
+						"node.#":                     "2",
+						"parameters.zone_redundancy": "maximum",
+						"auto_apply":                 "true",
+						"allow_decommission":         "false",
+						"wait_for_rebalance":         "0s",
+						"staged_changes.#":           "0",
+					}
+					for key, value := range expected {
+						if attributes[key] != value {
+							return fmt.Errorf("expected %s to be %q, got %q", key, value, attributes[key])
+						}
+					}
+					return nil
+				},
+			},
+			// A configuration matching the cluster plans no change
+			{
+				Config:   testAccClusterLayoutResourceConfig_import(server.URL, storage, gateway),
+				PlanOnly: true,
+			},
+		},
+		CheckDestroy: func(*terraform.State) error {
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			for _, call := range server.calls {
+				if call != "GetClusterLayout" {
+					return fmt.Errorf("expected the layout to only be read, got a call to %s", call)
+				}
+			}
+			if server.layout.Version != 3 {
+				return fmt.Errorf("expected the layout version to stay 3, got %d", server.layout.Version)
+			}
+			return nil
+		},
+	})
+}
+
+func TestClusterLayoutResourceValidateConfig(t *testing.T) {
+	nodeID := strings.Repeat("a", 64)
+
+	tests := []struct {
+		name     string
+		capacity types.Int64
+		gateway  types.Bool
+		wantErr  string
+	}{
+		{name: "storage", capacity: types.Int64Value(100), gateway: types.BoolNull()},
+		{name: "explicit storage", capacity: types.Int64Value(100), gateway: types.BoolValue(false)},
+		{name: "gateway", capacity: types.Int64Null(), gateway: types.BoolValue(true)},
+		{name: "unknown capacity", capacity: types.Int64Unknown(), gateway: types.BoolValue(true)},
+		{name: "both", capacity: types.Int64Value(100), gateway: types.BoolValue(true), wantErr: "is a gateway and cannot have a capacity"},
+		{name: "neither", capacity: types.Int64Null(), gateway: types.BoolNull(), wantErr: "must set exactly one of capacity or gateway"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := &ClusterLayoutResource{}
+			schemaResp := &fwresource.SchemaResponse{}
+			r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+			nodes, diags := types.SetValueFrom(ctx, types.ObjectType{AttrTypes: clusterLayoutNodeAttrTypes}, []ClusterLayoutNodeModel{{
+				ID:       types.StringValue(nodeID),
+				Zone:     types.StringValue("dc1"),
+				Capacity: tt.capacity,
+				Gateway:  tt.gateway,
+				Tags:     types.SetNull(types.StringType),
+			}})
+			if diags.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", diags)
+			}
+
+			state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+			diags = state.Set(ctx, &ClusterLayoutResourceModel{
+				ID:                types.StringNull(),
+				Nodes:             nodes,
+				AutoApply:         types.BoolNull(),
+				AllowDecommission: types.BoolNull(),
+				WaitForRebalance:  types.StringNull(),
+				Parameters:        types.ObjectNull(clusterLayoutParametersAttrTypes),
+				Version:           types.Int64Null(),
+				StagedChanges:     types.ListNull(types.StringType),
+			})
+			if diags.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", diags)
+			}
+
+			resp := &fwresource.ValidateConfigResponse{}
+			r.ValidateConfig(ctx, fwresource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, resp)
+
+			if tt.wantErr == "" {
+				if resp.Diagnostics.HasError() {
+					t.Errorf("Unexpected diagnostics: %v", resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.ErrorsCount() != 1 || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got %v", tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestClusterLayoutResourceImportState(t *testing.T) {
+	ctx := context.Background()
+	nodeA := strings.Repeat("a", 64)
+	server := newTestLayoutServer(t, client.ClusterLayout{
+		Version:    3,
+		Roles:      []client.NodeRole{testNodeRole(nodeA, "dc1", 1000)},
+		Parameters: &client.LayoutParameters{ZoneRedundancy: "2"},
+	})
+	r := &ClusterLayoutResource{client: client.NewClient(server.URL, "test-token")}
+
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	empty := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+
+	resp := &fwresource.ImportStateResponse{State: empty}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: nodeA}, resp)
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Invalid Import ID" {
+		t.Fatalf("Expected an Invalid Import ID error, got %v", resp.Diagnostics)
+	}
+
+	resp = &fwresource.ImportStateResponse{State: empty}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: clusterLayoutID}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	readResp := &fwresource.ReadResponse{State: resp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: resp.State}, readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", readResp.Diagnostics)
+	}
+
+	var data ClusterLayoutResourceModel
+	readResp.Diagnostics.Append(readResp.State.Get(ctx, &data)...)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", readResp.Diagnostics)
+	}
+	expected := testClusterLayoutModel(t, true, testNodeRole(nodeA, "dc1", 1000))
+	if !data.Nodes.Equal(expected.Nodes) {
+		t.Errorf("Expected nodes %v, got %v", expected.Nodes, data.Nodes)
+	}
+	if data.ID.ValueString() != clusterLayoutID || data.Version.ValueInt64() != 3 || !data.AutoApply.ValueBool() || data.AllowDecommission.ValueBool() ||
+		data.WaitForRebalance.ValueString() != "0s" || len(data.StagedChanges.Elements()) != 0 {
+		t.Errorf("Unexpected imported state %+v", data)
+	}
+	if !data.Parameters.Equal(clusterLayoutParametersValue(&client.LayoutParameters{ZoneRedundancy: "2"})) {
+		t.Errorf("Expected zone redundancy 2, got %v", data.Parameters)
+	}
+	server.expectCalls(t, "GetClusterLayout")
+}
+
+func TestClusterLayoutStage(t *testing.T) {
+	nodeA := strings.Repeat("a", 64)
+	nodeB := strings.Repeat("b", 64)
+	nodeC := strings.Repeat("c", 64)
+	initial := client.ClusterLayout{
+		Version: 1,
+		Roles: []client.NodeRole{
+			testNodeRole(nodeA, "dc1", 100),
+			testNodeRole(nodeB, "dc1", 100),
+		},
+	}
+	desired := []client.NodeRole{
+		testNodeRole(nodeA, "dc1", 200),
+		testNodeRole(nodeC, "dc2", 100),
+	}
+
+	t.Run("apply", func(t *testing.T) {
+		server := newTestLayoutServer(t, initial)
+		r := &ClusterLayoutResource{client: client.NewClient(server.URL, "test-token")}
+		data := testClusterLayoutModel(t, true, desired...)
+
+		removed, diags := r.stage(context.Background(), &data, nil)
+		if diags.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", diags)
+		}
+
+		server.expectCalls(t, "GetClusterLayout", "UpdateClusterLayout", "ApplyClusterLayout")
+		if !slices.Equal(removed, []string{nodeB}) {
+			t.Errorf("Expected the removal of %s, got %v", nodeB, removed)
+		}
+		if data.Version.ValueInt64() != 2 || len(data.StagedChanges.Elements()) != 0 {
+			t.Errorf("Expected version 2 without staged changes, got %s and %s", data.Version, data.StagedChanges)
+		}
+		if testJSON(t, server.layout.Roles) != testJSON(t, desired) {
+			t.Errorf("Expected roles %v, got %v", desired, server.layout.Roles)
+		}
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		server := newTestLayoutServer(t, initial)
+		r := &ClusterLayoutResource{client: client.NewClient(server.URL, "test-token")}
+		data := testClusterLayoutModel(t, true, initial.Roles...)
+
+		if _, diags := r.stage(context.Background(), &data, nil); diags.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", diags)
+		}
+
+		server.expectCalls(t, "GetClusterLayout")
+		if data.Version.ValueInt64() != 1 {
+			t.Errorf("Expected version 1, got %s", data.Version)
+		}
+	})
+
+	t.Run("stage only", func(t *testing.T) {
+		server := newTestLayoutServer(t, initial)
+		r := &ClusterLayoutResource{client: client.NewClient(server.URL, "test-token")}
+		data := testClusterLayoutModel(t, false, desired...)
+
+		if _, diags := r.stage(context.Background(), &data, nil); diags.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", diags)
+		}
+
+		server.expectCalls(t, "GetClusterLayout", "UpdateClusterLayout", "PreviewClusterLayoutChanges")
+		expected := stagedChangesValue([]client.NodeRoleChange{{NodeRole: client.NodeRole{ID: nodeA}}, {NodeRole: client.NodeRole{ID: nodeB}}, {NodeRole: client.NodeRole{ID: nodeC}}}, false)
+		if data.Version.ValueInt64() != 1 || !data.StagedChanges.Equal(expected) {
+			t.Errorf("Expected version 1 with staged changes %s, got %s and %s", expected, data.Version, data.StagedChanges)
+		}
+		if data.PendingTransfers.ValueInt64() != 192 {
+			t.Errorf("Expected 192 pending partition transfers, got %s", data.PendingTransfers)
+		}
+
+		// The next run starts over from the applied layout
+		ours, _ := stagedChangeIDs(context.Background(), data.StagedChanges)
+		data = testClusterLayoutModel(t, true, desired...)
+		if _, diags := r.stage(context.Background(), &data, ours); diags.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", diags)
+		}
+
+		server.expectCalls(t, "GetClusterLayout", "UpdateClusterLayout", "PreviewClusterLayoutChanges",
+			"GetClusterLayout", "RevertClusterLayout", "UpdateClusterLayout", "ApplyClusterLayout")
+		if !data.PendingTransfers.IsNull() {
+			t.Errorf("Expected no pending partition transfers once applied, got %s", data.PendingTransfers)
+		}
+		if data.Version.ValueInt64() != 2 {
+			t.Errorf("Expected version 2, got %s", data.Version)
+		}
+	})
+
+	t.Run("apply failure", func(t *testing.T) {
+		server := newTestLayoutServer(t, initial)
+		server.failApply = true
+		r := &ClusterLayoutResource{client: client.NewClient(server.URL, "test-token")}
+		data := testClusterLayoutModel(t, true, desired...)
+
+		_, diags := r.stage(context.Background(), &data, nil)
+		if diags.ErrorsCount() != 1 || !strings.Contains(diags.Errors()[0].Detail(), "The staged changes were reverted") {
+			t.Fatalf("Expected the apply error, got %v", diags)
+		}
+
+		server.expectCalls(t, "GetClusterLayout", "UpdateClusterLayout", "ApplyClusterLayout", "RevertClusterLayout")
+		if len(server.layout.StagedRoleChanges) != 0 || server.layout.Version != 1 {
+			t.Errorf("Expected the staged changes to be reverted, got %+v", server.layout)
+		}
+	})
+
+	t.Run("removal not allowed", func(t *testing.T) {
+		server := newTestLayoutServer(t, initial)
+		r := &ClusterLayoutResource{client: client.NewClient(server.URL, "test-token")}
+		data := testClusterLayoutModel(t, true, desired...)
+		data.AllowDecommission = types.BoolValue(false)
+
+		_, diags := r.stage(context.Background(), &data, nil)
+		if diags.ErrorsCount() != 1 || !strings.Contains(diags.Errors()[0].Detail(), "removes nodes "+nodeB) {
+			t.Fatalf("Expected the removal error, got %v", diags)
+		}
+
+		server.expectCalls(t, "GetClusterLayout")
+	})
+
+	t.Run("unrelated changes", func(t *testing.T) {
+		staged := initial
+		staged.StagedRoleChanges = []client.NodeRoleChange{{NodeRole: client.NodeRole{ID: nodeB}, Remove: true}}
+		server := newTestLayoutServer(t, staged)
+		r := &ClusterLayoutResource{client: client.NewClient(server.URL, "test-token")}
+		data := testClusterLayoutModel(t, true, desired...)
+
+		_, diags := r.stage(context.Background(), &data, map[string]bool{nodeC: true})
+		if diags.ErrorsCount() != 1 || !strings.Contains(diags.Errors()[0].Detail(), "for nodes "+nodeB) {
+			t.Fatalf("Expected the unrelated changes error, got %v", diags)
+		}
+
+		server.expectCalls(t, "GetClusterLayout")
+	})
+}
+
+func TestClusterLayoutStageParameters(t *testing.T) {
+	nodeA := strings.Repeat("a", 64)
+	initial := client.ClusterLayout{
+		Version:    1,
+		Roles:      []client.NodeRole{testNodeRole(nodeA, "dc1", 100)},
+		Parameters: &client.LayoutParameters{ZoneRedundancy: client.ZoneRedundancyMaximum},
+	}
+	withParameters := func(data ClusterLayoutResourceModel, zoneRedundancy string) ClusterLayoutResourceModel {
+		data.Parameters = types.ObjectValueMust(clusterLayoutParametersAttrTypes, map[string]attr.Value{
+			"zone_redundancy": types.StringValue(zoneRedundancy),
+		})
+		return data
+	}
+
+	t.Run("not configured", func(t *testing.T) {
+		server := newTestLayoutServer(t, initial)
+		r := &ClusterLayoutResource{client: client.NewClient(server.URL, "test-token")}
+		data := testClusterLayoutModel(t, true, initial.Roles...)
+		data.Parameters = types.ObjectUnknown(clusterLayoutParametersAttrTypes)
+
+		if _, diags := r.stage(context.Background(), &data, nil); diags.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", diags)
+		}
+
+		server.expectCalls(t, "GetClusterLayout")
+		if expected := withParameters(data, "maximum").Parameters; !data.Parameters.Equal(expected) {
+			t.Errorf("Expected parameters %s, got %s", expected, data.Parameters)
+		}
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		server := newTestLayoutServer(t, initial)
+		r := &ClusterLayoutResource{client: client.NewClient(server.URL, "test-token")}
+		data := withParameters(testClusterLayoutModel(t, true, initial.Roles...), "2")
+
+		if _, diags := r.stage(context.Background(), &data, nil); diags.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", diags)
+		}
+
+		server.expectCalls(t, "GetClusterLayout", "UpdateClusterLayout", "ApplyClusterLayout")
+		if server.layout.Parameters.ZoneRedundancy != "2" || server.layout.Version != 2 {
+			t.Errorf("Expected zone redundancy 2 in version 2, got %+v", server.layout)
+		}
+	})
+
+	t.Run("stage only", func(t *testing.T) {
+		server := newTestLayoutServer(t, initial)
+		r := &ClusterLayoutResource{client: client.NewClient(server.URL, "test-token")}
+		data := withParameters(testClusterLayoutModel(t, false, initial.Roles...), "2")
+
+		if _, diags := r.stage(context.Background(), &data, nil); diags.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", diags)
+		}
+
+		if expected := stagedChangesValue(nil, true); !data.StagedChanges.Equal(expected) {
+			t.Errorf("Expected staged changes %s, got %s", expected, data.StagedChanges)
+		}
+
+		// Parameters staged by someone else block the resource
+		ours := map[string]bool{}
+		data = testClusterLayoutModel(t, true, initial.Roles...)
+		_, diags := r.stage(context.Background(), &data, ours)
+		if diags.ErrorsCount() != 1 || !strings.Contains(diags.Errors()[0].Detail(), "for the layout parameters") {
+			t.Fatalf("Expected the unrelated changes error, got %v", diags)
+		}
+	})
+}
+
+func TestClusterLayoutWaitForRebalance(t *testing.T) {
+	oldInterval := rebalancePollInterval
+	rebalancePollInterval = time.Millisecond
+	defer func() { rebalancePollInterval = oldInterval }()
+
+	nodeB := strings.Repeat("b", 64)
+
+	t.Run("rebalanced", func(t *testing.T) {
+		server := newTestLayoutServer(t, client.ClusterLayout{Version: 2})
+		server.unbalancedChecks = 2
+		r := &ClusterLayoutResource{client: client.NewClient(server.URL, "test-token")}
+		data := testClusterLayoutModel(t, true)
+		data.WaitForRebalance = types.StringValue("1m")
+
+		if diags := r.waitForRebalance(context.Background(), &data, []string{nodeB}); diags.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", diags)
+		}
+		server.expectCalls(t, "GetClusterHealth", "GetClusterHealth", "GetClusterHealth")
+	})
+
+	t.Run("no removal", func(t *testing.T) {
+		server := newTestLayoutServer(t, client.ClusterLayout{Version: 2})
+		r := &ClusterLayoutResource{client: client.NewClient(server.URL, "test-token")}
+		data := testClusterLayoutModel(t, true)
+		data.WaitForRebalance = types.StringValue("1m")
+
+		if diags := r.waitForRebalance(context.Background(), &data, nil); diags.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", diags)
+		}
+		server.expectCalls(t)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		server := newTestLayoutServer(t, client.ClusterLayout{Version: 2})
+		server.unbalancedChecks = 1 << 30
+		r := &ClusterLayoutResource{client: client.NewClient(server.URL, "test-token")}
+		data := testClusterLayoutModel(t, true)
+		data.WaitForRebalance = types.StringValue("50ms")
+
+		diags := r.waitForRebalance(context.Background(), &data, []string{nodeB})
+		if diags.ErrorsCount() != 1 || !strings.Contains(diags.Errors()[0].Detail(), "200 of 256 partitions have a quorum") {
+			t.Fatalf("Expected the rebalance timeout, got %v", diags)
+		}
+	})
+}
+
+func TestClusterLayoutWarnPendingTransfers(t *testing.T) {
+	nodeA := strings.Repeat("a", 64)
+	state := testClusterLayoutModel(t, false, testNodeRole(nodeA, "dc1", 100))
+	state.StagedChanges = stagedChangesValue([]client.NodeRoleChange{{NodeRole: client.NodeRole{ID: nodeA}}}, false)
+	state.PendingTransfers = types.Int64Value(64)
+
+	tests := []struct {
+		name     string
+		plan     ClusterLayoutResourceModel
+		expected bool
+	}{
+		{"applying the staged changes", testClusterLayoutModel(t, true, testNodeRole(nodeA, "dc1", 100)), true},
+		{"staging again", testClusterLayoutModel(t, false, testNodeRole(nodeA, "dc1", 100)), false},
+		{"changing the layout again", testClusterLayoutModel(t, true, testNodeRole(nodeA, "dc1", 200)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := warnPendingTransfers(context.Background(), tt.plan, state)
+			if diags.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", diags)
+			}
+			if got := len(diags.Warnings()) == 1; got != tt.expected {
+				t.Fatalf("Expected warning %t, got %v", tt.expected, diags)
+			}
+			if tt.expected && !strings.Contains(diags.Warnings()[0].Detail(), "staged for "+nodeA+" moves 64 partition copies") {
+				t.Errorf("Unexpected warning %q", diags.Warnings()[0].Detail())
+			}
+		})
+	}
+}
+
+func TestClusterLayoutRoleChanges(t *testing.T) {
+	current := []client.NodeRole{
+		testNodeRole("a", "dc1", 100),
+		testNodeRole("b", "dc1", 100),
+		{ID: "c", Zone: "dc2", Capacity: testNodeRole("c", "", 100).Capacity, Tags: []string{"y", "x"}},
+	}
+	desired := map[string]client.NodeRole{
+		"a": testNodeRole("a", "dc1", 100),
+		"c": {ID: "c", Zone: "dc2", Capacity: testNodeRole("c", "", 100).Capacity, Tags: []string{"x", "y"}},
+		"d": testNodeRole("d", "dc2", 50),
+	}
+
+	changes := clusterLayoutRoleChanges(current, desired)
+
+	// Tags are compared regardless of their order
+	expected := []client.NodeRoleChange{
+		{NodeRole: client.NodeRole{ID: "b"}, Remove: true},
+		{NodeRole: desired["d"]},
+	}
+	if testJSON(t, changes) != testJSON(t, expected) {
+		t.Errorf("Expected changes %v, got %v", expected, changes)
+	}
+}
+
+// testLayoutServer is an in-memory admin API serving the cluster layout
+// endpoints.
+type testLayoutServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	layout    client.ClusterLayout
+	failApply bool
+	calls     []string
+	// unbalancedChecks is the number of health checks reporting partitions
+	// without quorum
+	unbalancedChecks int
+}
+
+func newTestLayoutServer(t *testing.T, layout client.ClusterLayout) *testLayoutServer {
+	s := &testLayoutServer{layout: layout}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		endpoint := strings.TrimPrefix(r.URL.Path, "/v2/")
+		s.calls = append(s.calls, endpoint)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch endpoint {
+		case "GetClusterLayout":
+		case "UpdateClusterLayout":
+			var req client.UpdateClusterLayoutRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Roles == nil {
+				t.Errorf("Unexpected request body: %v", err)
+				return
+			}
+			if req.Parameters != nil {
+				s.layout.StagedParameters = req.Parameters
+			}
+			for _, change := range req.Roles {
+				s.layout.StagedRoleChanges = slices.DeleteFunc(s.layout.StagedRoleChanges, func(c client.NodeRoleChange) bool {
+					return c.ID == change.ID
+				})
+				s.layout.StagedRoleChanges = append(s.layout.StagedRoleChanges, change)
+			}
+		case "ApplyClusterLayout":
+			var req map[string]int64
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if s.failApply || req["version"] != s.layout.Version+1 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"code": "InvalidRequest", "message": "could not apply layout"}`))
+				return
+			}
+			s.layout.Roles = applyRoleChanges(s.layout.Roles, s.layout.StagedRoleChanges)
+			s.layout.StagedRoleChanges = nil
+			if s.layout.StagedParameters != nil {
+				s.layout.Parameters, s.layout.StagedParameters = s.layout.StagedParameters, nil
+			}
+			s.layout.Version++
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"message": []string{"applied"}, "layout": s.layout})
+			return
+		case "PreviewClusterLayoutChanges":
+			// Each staged role moves a quarter of the 256 partitions
+			preview := client.LayoutPreview{
+				Message:   []string{fmt.Sprintf("A total of %d new copies of partitions need to be transferred.", 64*len(s.layout.StagedRoleChanges))},
+				NewLayout: s.layout,
+			}
+			_ = json.NewEncoder(w).Encode(preview)
+			return
+		case "RevertClusterLayout":
+			s.layout.StagedRoleChanges = nil
+			s.layout.StagedParameters = nil
+		case "GetClusterHealth":
+			health := client.ClusterHealth{Status: "healthy", Partitions: 256, PartitionsQuorum: 256, PartitionsAllOk: 256}
+			if s.unbalancedChecks > 0 {
+				s.unbalancedChecks--
+				health.Status, health.PartitionsQuorum, health.PartitionsAllOk = "unavailable", 200, 200
+			}
+			_ = json.NewEncoder(w).Encode(health)
+			return
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(s.layout)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// expectCalls checks the endpoints called so far.
+func (s *testLayoutServer) expectCalls(t *testing.T, expected ...string) {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !slices.Equal(s.calls, expected) {
+		t.Errorf("Expected calls %v, got %v", expected, s.calls)
+	}
+}
+
+// testJSON encodes v, to compare values holding pointers.
+func testJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func testNodeRole(id, zone string, capacity int64) client.NodeRole {
+	return client.NodeRole{ID: id, Zone: zone, Capacity: &capacity, Tags: []string{}}
+}
+
+func testClusterLayoutModel(t *testing.T, autoApply bool, roles ...client.NodeRole) ClusterLayoutResourceModel {
+	t.Helper()
+
+	nodes, diags := clusterLayoutNodesValue(context.Background(), roles)
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	return ClusterLayoutResourceModel{
+		Nodes:             nodes,
+		AutoApply:         types.BoolValue(autoApply),
+		AllowDecommission: types.BoolValue(true),
+		WaitForRebalance:  types.StringValue("0s"),
+		Parameters:        types.ObjectNull(clusterLayoutParametersAttrTypes),
+		StagedChanges:     types.ListNull(types.StringType),
+	}
+}
+
+// testAccClusterLayoutVariables passes the ID, zone and capacity of the first
+// node of the test cluster to the configuration, so that the tests do not
+// trigger a rebalance.
+func testAccClusterLayoutVariables(t *testing.T) {
+	layout, err := client.NewClient(os.Getenv("GARAGE_ADMIN_ENDPOINT"), os.Getenv("GARAGE_TOKEN")).GetClusterLayout(context.Background())
+	if err != nil {
+		t.Fatalf("Unable to read the cluster layout: %s", err)
+	}
+	// The resource is authoritative, other nodes would be removed
+	if len(layout.Roles) != 1 || layout.Roles[0].Capacity == nil {
+		t.Skip("The test cluster must have a single storage node in its layout")
+	}
+
+	role := layout.Roles[0]
+	t.Setenv("TF_VAR_node_id", role.ID)
+	t.Setenv("TF_VAR_zone", role.Zone)
+	t.Setenv("TF_VAR_capacity", fmt.Sprint(*role.Capacity))
+}
+
+// testAccMockProviderConfig returns a provider configuration for an admin API
+// served by the tests.
+func testAccMockProviderConfig(adminEndpoint string) string {
+	return fmt.Sprintf(`
+provider "garage" {
+  endpoints = {
+    admin = %q
+  }
+  token = "test-token"
+}
+`, adminEndpoint)
+}
+
+func testAccClusterLayoutResourceConfig_gateway(adminEndpoint, storage, gateway, gatewayRole string) string {
+	return testAccMockProviderConfig(adminEndpoint) + fmt.Sprintf(`
+resource "garage_cluster_layout" "test" {
+  node = [
+    {
+      id       = %[1]q
+      zone     = "dc1"
+      capacity = 1000
+    },
+    {
+      id   = %[2]q
+      zone = "dc1"
+      %[3]s
+    },
+  ]
+}
+`, storage, gateway, gatewayRole)
+}
+
+func testAccClusterLayoutResourceConfig_decommission(adminEndpoint, node string, allowDecommission bool) string {
+	return testAccMockProviderConfig(adminEndpoint) + fmt.Sprintf(`
+resource "garage_cluster_layout" "test" {
+  allow_decommission = %[2]t
+  wait_for_rebalance = "1m"
+
+  node = [
+    {
+      id       = %[1]q
+      zone     = "dc1"
+      capacity = 1000
+    },
+  ]
+}
+`, node, allowDecommission)
+}
+
+func testAccClusterLayoutResourceConfig_preview(adminEndpoint, node string, autoApply bool) string {
+	return testAccMockProviderConfig(adminEndpoint) + fmt.Sprintf(`
+resource "garage_cluster_layout" "test" {
+  auto_apply = %[2]t
+
+  node = [
+    {
+      id       = %[1]q
+      zone     = "dc1"
+      capacity = 1000
+    },
+  ]
+}
+`, node, autoApply)
+}
+
+func testAccClusterLayoutResourceConfig_import(adminEndpoint, storage, gateway string) string {
+	return testAccMockProviderConfig(adminEndpoint) + fmt.Sprintf(`
+resource "garage_cluster_layout" "test" {
+  parameters = {
+    zone_redundancy = "maximum"
+  }
+
+  node = [
+    {
+      id       = %[1]q
+      zone     = "dc1"
+      capacity = 1000
+      tags     = ["ssd"]
+    },
+    {
+      id      = %[2]q
+      zone    = "dc2"
+      gateway = true
+    },
+  ]
+}
+`, storage, gateway)
+}
+
+func testAccClusterLayoutResourceConfig(autoApply bool, tags string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+variable "node_id" {}
+variable "zone" {}
+variable "capacity" {}
+
+resource "garage_cluster_layout" "test" {
+  auto_apply = %[1]t
+
+  node = [
+    {
+      id       = var.node_id
+      zone     = var.zone
+      capacity = var.capacity
+      tags     = %[2]s
+    },
+  ]
+}
+`, autoApply, tags)
+}
@@ -0,0 +1,157 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ClusterMetricsDataSource{}
+
+func NewClusterMetricsDataSource() datasource.DataSource {
+	return &ClusterMetricsDataSource{}
+}
+
+// ClusterMetricsDataSource defines the data source implementation.
+type ClusterMetricsDataSource struct {
+	client *client.MetricsClient
+}
+
+// ClusterMetricsDataSourceModel describes the data source data model.
+type ClusterMetricsDataSourceModel struct {
+	Metrics types.List   `tfsdk:"metrics"`
+	Values  types.Map    `tfsdk:"values"`
+	Raw     types.String `tfsdk:"raw"`
+}
+
+func (d *ClusterMetricsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_metrics"
+}
+
+func (d *ClusterMetricsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads the Prometheus metrics of the Garage node behind `endpoints.metrics`, the admin endpoint unless set, " +
+			"e.g. to check the resync queue in a precondition. Uses `metrics_token` when the metrics endpoint is protected.",
+
+		Attributes: map[string]schema.Attribute{
+			"metrics": schema.ListAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Only read these metrics, e.g. `block_resync_queue_length`. A histogram or summary includes its `_bucket`, `_sum` and `_count` samples. All metrics are read when not set.",
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"values": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.Float64Type,
+				MarkdownDescription: "The value of each metric with a single sample without labels, such as `cluster_healthy` or `block_resync_queue_length`, by name.",
+			},
+			"raw": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The other samples in the text exposition format, one per line: samples with labels, such as those of histograms, and samples that are not a finite number.",
+			},
+		},
+	}
+}
+
+func (d *ClusterMetricsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	if providerData.MetricsClient == nil {
+		resp.Diagnostics.AddError(
+			"Missing Metrics Endpoint",
+			"Metrics endpoint must be configured in endpoints.metrics or endpoints.admin for the cluster metrics",
+		)
+		return
+	}
+
+	d.client = providerData.MetricsClient
+}
+
+func (d *ClusterMetricsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ClusterMetricsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var names []string
+	if !data.Metrics.IsNull() {
+		resp.Diagnostics.Append(data.Metrics.ElementsAs(ctx, &names, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	tflog.Debug(ctx, "Reading cluster metrics data source", map[string]interface{}{
+		"metrics": names,
+	})
+
+	text, err := d.client.GetMetrics(ctx)
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError("Unable to read metrics", err))
+		return
+	}
+
+	values, raw := clusterMetricsValues(client.ParseMetrics(text, names))
+	data.Values = types.MapValueMust(types.Float64Type, values)
+	data.Raw = types.StringValue(raw)
+
+	tflog.Trace(ctx, "Read cluster metrics data source", map[string]interface{}{
+		"values": len(values),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// clusterMetricsValues splits samples into the values of the metrics with a
+// single finite sample without labels, by name, and the lines of the other
+// samples.
+func clusterMetricsValues(samples []client.MetricSample) (map[string]attr.Value, string) {
+	counts := map[string]int{}
+	for _, sample := range samples {
+		counts[sample.Name]++
+	}
+
+	values := map[string]attr.Value{}
+	var raw []string
+	for _, sample := range samples {
+		if counts[sample.Name] == 1 && sample.Labels == "" && !math.IsNaN(sample.Value) && !math.IsInf(sample.Value, 0) {
+			values[sample.Name] = types.Float64Value(sample.Value)
+			continue
+		}
+		raw = append(raw, sample.Line)
+	}
+
+	return values, strings.Join(raw, "\n")
+}
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+const testClusterMetrics = `# TYPE cluster_healthy gauge
+cluster_healthy 1
+# TYPE block_resync_queue_length gauge
+block_resync_queue_length 42
+rpc_netapp_error_counter{rpc_endpoint="garage_block/manager.rs/Rpc",to="6b3a"} 3
+api_s3_request_duration_bucket{api_endpoint="GetObject",le="+Inf"} 12
+api_s3_request_duration_sum{api_endpoint="GetObject"} 1.5
+table_gc_todo_queue_length NaN
+`
+
+func TestClusterMetricsDataSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer metrics-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(testClusterMetrics))
+	}))
+	defer server.Close()
+
+	d := &ClusterMetricsDataSource{client: client.NewMetricsClient(server.URL, "metrics-token")}
+
+	resp := testDataSourceRead(t, d, nil)
+	data := testClusterMetricsData(t, resp)
+	values := map[string]float64{}
+	for name, value := range data.Values.Elements() {
+		values[name] = value.(types.Float64).ValueFloat64()
+	}
+	if len(values) != 2 || values["cluster_healthy"] != 1 || values["block_resync_queue_length"] != 42 {
+		t.Errorf("Unexpected values %v", values)
+	}
+	expectedRaw := `rpc_netapp_error_counter{rpc_endpoint="garage_block/manager.rs/Rpc",to="6b3a"} 3
+api_s3_request_duration_bucket{api_endpoint="GetObject",le="+Inf"} 12
+api_s3_request_duration_sum{api_endpoint="GetObject"} 1.5
+table_gc_todo_queue_length NaN`
+	if data.Raw.ValueString() != expectedRaw {
+		t.Errorf("Expected raw %q, got %q", expectedRaw, data.Raw.ValueString())
+	}
+
+	resp = testDataSourceRead(t, d, map[string]tftypes.Value{
+		"metrics": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "block_resync_queue_length"),
+		}),
+	})
+	data = testClusterMetricsData(t, resp)
+	if len(data.Values.Elements()) != 1 || data.Raw.ValueString() != "" {
+		t.Errorf("Expected only block_resync_queue_length, got %v and %q", data.Values, data.Raw.ValueString())
+	}
+
+	d = &ClusterMetricsDataSource{client: client.NewMetricsClient(server.URL, "")}
+	resp = testDataSourceRead(t, d, nil)
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("Expected an error without the metrics token")
+	}
+	expected := "Unable to read metrics, got error: GET /metrics returned status 403"
+	if detail := resp.Diagnostics[0].Detail(); detail != expected {
+		t.Errorf("Expected %q, got %q", expected, detail)
+	}
+}
+
+func testClusterMetricsData(t *testing.T, resp *datasource.ReadResponse) ClusterMetricsDataSourceModel {
+	t.Helper()
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data ClusterMetricsDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	return data
+}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &ContentTypeFunction{}
+
+func NewContentTypeFunction() function.Function {
+	return &ContentTypeFunction{}
+}
+
+// ContentTypeFunction defines the function implementation.
+type ContentTypeFunction struct{}
+
+func (f *ContentTypeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "content_type"
+}
+
+func (f *ContentTypeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Returns the MIME type of a file from its extension",
+		MarkdownDescription: "Returns the MIME type of a file from its extension, e.g. `text/css; charset=utf-8` for `site/style.css`, " +
+			"with the mapping `garage_object` and `garage_object_directory` use when `content_type` is not set, web assets such as `.woff2`, `.mjs` and `.wasm` included. " +
+			"Extensions are case-insensitive. Unknown extensions and files without extension are `application/octet-stream`. " +
+			"Provider functions cannot read the provider configuration, so pass the `content_type_overrides` of the provider as last argument to get the same result as the resources.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "filename",
+				MarkdownDescription: "The name or path of the file, e.g. a key or an element of `fileset()`.",
+			},
+		},
+		VariadicParameter: function.MapParameter{
+			Name:                "overrides",
+			ElementType:         types.StringType,
+			MarkdownDescription: "Content types by extension such as `.glb`, used before the built-in table like `content_type_overrides` of the provider. Extensions start with a dot and are case-insensitive.",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ContentTypeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var filename string
+	var overrideArgs []map[string]string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &filename, &overrideArgs))
+	if resp.Error != nil {
+		return
+	}
+
+	var overrides map[string]string
+	switch {
+	case len(overrideArgs) > 1:
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("invalid overrides: expected at most one map, got %d", len(overrideArgs)))
+		return
+	case len(overrideArgs) == 1:
+		var err error
+		if overrides, err = lowerContentTypeOverrides(overrideArgs[0]); err != nil {
+			resp.Error = function.NewArgumentFuncError(1, err.Error())
+			return
+		}
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, fileContentType(filename, overrides)))
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccContentTypeFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "font" {
+  value = provider::garage::content_type("fonts/inter.woff2")
+}
+
+output "unknown" {
+  value = provider::garage::content_type("LICENSE")
+}
+
+output "override" {
+  value = provider::garage::content_type("models/ship.glb", { ".glb" = "model/gltf-binary" })
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("font", knownvalue.StringExact("font/woff2")),
+					statecheck.ExpectKnownOutputValue("unknown", knownvalue.StringExact("application/octet-stream")),
+					statecheck.ExpectKnownOutputValue("override", knownvalue.StringExact("model/gltf-binary")),
+				},
+			},
+		},
+	})
+}
+
+func TestContentTypeFunction_run(t *testing.T) {
+	f := NewContentTypeFunction()
+
+	tests := map[string]string{
+		"index.html":            "text/html; charset=utf-8",
+		"site/css/style.CSS":    "text/css; charset=utf-8",
+		"js/app.mjs":            "text/javascript; charset=utf-8",
+		"fonts/inter.woff2":     "font/woff2",
+		"module.wasm":           "application/wasm",
+		"report.pdf":            "application/pdf",
+		"archive.unknownext":    "application/octet-stream",
+		"LICENSE":               "application/octet-stream",
+		"":                      "application/octet-stream",
+		"dir.with.dots/LICENSE": "application/octet-stream",
+	}
+
+	for filename, expected := range tests {
+		resp := testFunctionRun(t, f, types.StringValue(filename), types.TupleValueMust(nil, nil))
+		if resp.Error != nil || !resp.Result.Value().Equal(types.StringValue(expected)) {
+			t.Errorf("content_type(%q): expected %q, got %s and %v", filename, expected, resp.Result.Value(), resp.Error)
+		}
+
+		// The function and the resource default agree
+		if got := fileContentType(filename, nil); got != expected {
+			t.Errorf("fileContentType(%q): expected %q, got %q", filename, expected, got)
+		}
+	}
+}
+
+func TestContentTypeFunction_overrides(t *testing.T) {
+	f := NewContentTypeFunction()
+	overrides := types.MapValueMust(types.StringType, map[string]attr.Value{
+		".GLB": types.StringValue("model/gltf-binary"),
+		".map": types.StringValue("application/source-map+json"),
+	})
+	overridesType := []attr.Type{types.MapType{ElemType: types.StringType}}
+
+	tests := map[string]string{
+		"models/ship.glb": "model/gltf-binary",
+		// The override wins over the built-in table
+		"js/app.js.map": "application/source-map+json",
+		"index.html":    "text/html; charset=utf-8",
+	}
+
+	for filename, expected := range tests {
+		resp := testFunctionRun(t, f, types.StringValue(filename), types.TupleValueMust(overridesType, []attr.Value{overrides}))
+		if resp.Error != nil || !resp.Result.Value().Equal(types.StringValue(expected)) {
+			t.Errorf("content_type(%q): expected %q, got %s and %v", filename, expected, resp.Result.Value(), resp.Error)
+		}
+	}
+
+	invalid := types.MapValueMust(types.StringType, map[string]attr.Value{"glb": types.StringValue("model/gltf-binary")})
+	resp := testFunctionRun(t, f, types.StringValue("ship.glb"), types.TupleValueMust(overridesType, []attr.Value{invalid}))
+	if resp.Error == nil {
+		t.Errorf("Expected an error for an extension without dot")
+	}
+
+	resp = testFunctionRun(t, f, types.StringValue("ship.glb"), types.TupleValueMust(
+		append(overridesType, overridesType...), []attr.Value{overrides, overrides}))
+	if resp.Error == nil {
+		t.Errorf("Expected an error for more than one overrides map")
+	}
+}
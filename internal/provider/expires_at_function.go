@@ -0,0 +1,100 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &ExpiresAtFunction{}
+
+func NewExpiresAtFunction() function.Function {
+	return &ExpiresAtFunction{}
+}
+
+// ExpiresAtFunction defines the function implementation.
+type ExpiresAtFunction struct{}
+
+func (f *ExpiresAtFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "expires_at"
+}
+
+func (f *ExpiresAtFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Returns the RFC 3339 timestamp a duration after now, or after a given time",
+		MarkdownDescription: "Returns the time a duration after `from`, in RFC 3339 format, for `expiration` attributes such as the one of `garage_admin_token`. " +
+			"The duration has the format of `parse_duration`, e.g. `90d`.\n\n" +
+			"**This function is impure without `from`:** it then uses the current time, so its result changes on every call, " +
+			"between plan and apply included, which Terraform reports as an inconsistent result. " +
+			"Pass a time that is stable for the whole run, such as `plantimestamp()`, or one stored in state, such as `time_static`, " +
+			"to keep the expiration from changing on every plan.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "duration",
+				MarkdownDescription: "The duration until the expiration, e.g. `90d` or `1d12h`.",
+			},
+		},
+		VariadicParameter: function.StringParameter{
+			Name:                "from",
+			MarkdownDescription: "The time the duration starts from, in RFC 3339 format. Default: the current time. At most one time can be given.",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ExpiresAtFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var duration string
+	var froms []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &duration, &froms))
+	if resp.Error != nil {
+		return
+	}
+
+	seconds, err := parseDuration(duration)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	from := time.Now()
+	switch {
+	case len(froms) > 1:
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("invalid from: expected at most one time, got %d", len(froms)))
+		return
+	case len(froms) == 1:
+		from, err = time.Parse(time.RFC3339, froms[0])
+		if err != nil {
+			resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("invalid from %q: expected a time in RFC 3339 format, such as 2030-01-01T00:00:00Z", froms[0]))
+			return
+		}
+	}
+
+	expiresAt, err := expiresAt(from, seconds)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid duration %q: %s", duration, err))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, expiresAt))
+}
+
+// maxExpiration is the last time of expires_at, the last one of RFC 3339.
+var maxExpiration = time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC)
+
+// expiresAt returns the time seconds after from, in UTC and RFC 3339 format.
+// Seconds are added to the Unix time of from, as time.Duration is limited to
+// about 292 years.
+func expiresAt(from time.Time, seconds int64) (string, error) {
+	if seconds > maxExpiration.Unix()-from.Unix() {
+		return "", fmt.Errorf("the expiration is after %s", maxExpiration.Format(time.RFC3339))
+	}
+
+	return time.Unix(from.Unix()+seconds, 0).UTC().Format(time.RFC3339), nil
+}
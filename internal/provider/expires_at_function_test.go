@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccExpiresAtFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::garage::expires_at("90d", "2030-01-01T00:00:00Z")
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.StringExact("2030-04-01T00:00:00Z")),
+				},
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::garage::expires_at("90d", "2030-01-01")
+}
+`,
+				ExpectError: regexp.MustCompile(`invalid from "2030-01-01"`),
+			},
+		},
+	})
+}
+
+func TestExpiresAt(t *testing.T) {
+	tests := []struct {
+		from     string
+		seconds  int64
+		expected string
+	}{
+		{"2030-01-01T00:00:00Z", 0, "2030-01-01T00:00:00Z"},
+		{"2030-01-01T00:00:00Z", 129600, "2030-01-02T12:00:00Z"},
+		{"2030-01-01T00:00:00Z", 7776000, "2030-04-01T00:00:00Z"},
+		// The result is in UTC whatever the offset of from
+		{"2030-01-01T02:00:00+02:00", 3600, "2030-01-01T01:00:00Z"},
+		// Fractions of seconds are dropped
+		{"2030-01-01T00:00:00.75Z", 1, "2030-01-01T00:00:01Z"},
+		{"2030-01-01T00:00:00Z", 250000 * 86400, "2714-06-25T00:00:00Z"},
+	}
+
+	for _, test := range tests {
+		from, _ := time.Parse(time.RFC3339, test.from)
+		got, err := expiresAt(from, test.seconds)
+		if err != nil || got != test.expected {
+			t.Errorf("expiresAt(%s, %d): expected %s, got %s and %v", test.from, test.seconds, test.expected, got, err)
+		}
+	}
+
+	from, _ := time.Parse(time.RFC3339, "2030-01-01T00:00:00Z")
+	for _, seconds := range []int64{3_000_000 * 86400, 9223372036854720000} {
+		if got, err := expiresAt(from, seconds); err == nil || !strings.Contains(err.Error(), "after 9999-12-31T23:59:59Z") {
+			t.Errorf("expiresAt(%d): expected an error, got %s and %v", seconds, got, err)
+		}
+	}
+}
+
+func TestExpiresAtFunction_run(t *testing.T) {
+	f := NewExpiresAtFunction()
+
+	run := func(duration string, froms ...string) (string, *int64, string) {
+		fromTypes := make([]attr.Type, 0, len(froms))
+		fromValues := make([]attr.Value, 0, len(froms))
+		for _, from := range froms {
+			fromTypes = append(fromTypes, types.StringType)
+			fromValues = append(fromValues, types.StringValue(from))
+		}
+		resp := testFunctionRun(t, f, types.StringValue(duration), types.TupleValueMust(fromTypes, fromValues))
+		if resp.Error != nil {
+			return "", resp.Error.FunctionArgument, resp.Error.Text
+		}
+		return resp.Result.Value().(types.String).ValueString(), nil, ""
+	}
+
+	if got, _, text := run("1d12h", "2030-01-01T00:00:00Z"); got != "2030-01-02T12:00:00Z" {
+		t.Errorf("Expected 2030-01-02T12:00:00Z, got %q and %q", got, text)
+	}
+
+	// Without from, the duration starts now
+	before := time.Now().Truncate(time.Second)
+	got, _, text := run("6h")
+	after := time.Now()
+	expiration, err := time.Parse(time.RFC3339, got)
+	if err != nil || expiration.Before(before.Add(6*time.Hour)) || expiration.After(after.Add(6*time.Hour)) {
+		t.Errorf("Expected a time 6h from now, got %q and %q", got, text)
+	}
+
+	tests := []struct {
+		duration string
+		froms    []string
+		argument int64
+		error    string
+	}{
+		{"90 days", nil, 0, `invalid duration "90 days"`},
+		{"1000000000d", []string{"2030-01-01T00:00:00Z"}, 0, "after 9999-12-31T23:59:59Z"},
+		{"90d", []string{"2030-01-01"}, 1, `invalid from "2030-01-01"`},
+		{"90d", []string{"2030-01-01T00:00:00Z", "2030-01-01T00:00:00Z"}, 1, "at most one time"},
+	}
+
+	for _, test := range tests {
+		_, argument, text := run(test.duration, test.froms...)
+		if argument == nil || *argument != test.argument || !strings.Contains(text, test.error) {
+			t.Errorf("expires_at(%q, %q): expected an error on argument %d containing %q, got %v and %q", test.duration, test.froms, test.argument, test.error, argument, text)
+		}
+	}
+}
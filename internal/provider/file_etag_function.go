@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &FileETagFunction{}
+
+func NewFileETagFunction() function.Function {
+	return &FileETagFunction{}
+}
+
+// FileETagFunction defines the function implementation.
+type FileETagFunction struct{}
+
+func (f *FileETagFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "file_etag"
+}
+
+func (f *FileETagFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Computes the S3 ETag of a local file",
+		MarkdownDescription: "Computes the ETag S3 returns for a local file once uploaded, to compare it with the `etag` of an object. " +
+			"Without part size, this is the quoted MD5 hex digest of the file, as for single-part uploads such as those of `garage_object`. " +
+			"With a part size, this is the ETag of a multipart upload in parts of that size: the MD5 of the MD5 digests of the parts followed by the number of parts, " +
+			"e.g. `\"...-3\"`. The file is read in chunks, so large files are not loaded in memory.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "path",
+				MarkdownDescription: "The path of the file, e.g. `\"${path.module}/site/index.html\"`.",
+			},
+		},
+		VariadicParameter: function.Int64Parameter{
+			Name:                "part_size",
+			MarkdownDescription: "The size in bytes of the parts of a multipart upload, e.g. `provider::garage::parse_size(\"8MiB\")`. At most one part size can be given.",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *FileETagFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var path string
+	var partSizes []int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &path, &partSizes))
+	if resp.Error != nil {
+		return
+	}
+
+	var partSize int64
+	switch {
+	case len(partSizes) > 1:
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("invalid part size: expected at most one part size, got %d", len(partSizes)))
+		return
+	case len(partSizes) == 1:
+		partSize = partSizes[0]
+		if partSize < 1 {
+			resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("invalid part size %d: expected a number of bytes of at least 1", partSize))
+			return
+		}
+	}
+
+	etag, err := fileETag(path, partSize)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid path %q: %s", path, err))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, etag))
+}
+
+// fileETag returns the quoted ETag of the file at path: its MD5 when partSize
+// is 0, the composite ETag of a multipart upload in parts of partSize bytes
+// otherwise. An empty file is uploaded as a single empty part.
+func fileETag(path string, partSize int64) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", unwrapPathError(err)
+	}
+	defer file.Close()
+
+	if partSize == 0 {
+		hash := md5.New()
+		if _, err := io.Copy(hash, file); err != nil {
+			return "", unwrapPathError(err)
+		}
+		return `"` + hex.EncodeToString(hash.Sum(nil)) + `"`, nil
+	}
+
+	composite := md5.New()
+	parts := 0
+	for {
+		hash := md5.New()
+		n, err := io.CopyN(hash, file, partSize)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", unwrapPathError(err)
+		}
+		if n == 0 && parts > 0 {
+			break
+		}
+		composite.Write(hash.Sum(nil))
+		parts++
+		if n < partSize {
+			break
+		}
+	}
+
+	return fmt.Sprintf(`"%s-%d"`, hex.EncodeToString(composite.Sum(nil)), parts), nil
+}
+
+// unwrapPathError drops the operation and path of err, which callers already
+// report, keeping e.g. "no such file or directory".
+func unwrapPathError(err error) error {
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err
+	}
+	return err
+}
@@ -0,0 +1,150 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccFileETagFunction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.html")
+	if err := os.WriteFile(path, []byte("Hello, World!"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+output "test" {
+  value = provider::garage::file_etag(%q)
+}
+`, path),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.StringExact(`"65a8e27d8879283831b664bd8b7f0ad4"`)),
+				},
+			},
+			{
+				Config: fmt.Sprintf(`
+output "test" {
+  value = provider::garage::file_etag(%q)
+}
+`, path+".missing"),
+				ExpectError: regexp.MustCompile(`no such file`),
+			},
+		},
+	})
+}
+
+func TestFileETag(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, content []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, content, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// The ETag of single-part uploads is the one garage_object predicts
+	for _, content := range []string{"", "Hello, World!", strings.Repeat("garage", 100000)} {
+		etag, err := fileETag(write("single", []byte(content)), 0)
+		if err != nil || etag != contentETag(content) {
+			t.Errorf("Expected %s for %d bytes, got %s and %v", contentETag(content), len(content), etag, err)
+		}
+	}
+
+	content := []byte(strings.Repeat("0123456789", 25))
+	path := write("multipart", content)
+	tests := []struct {
+		partSize int64
+		parts    []string
+	}{
+		{100, []string{string(content[:100]), string(content[100:200]), string(content[200:])}},
+		{125, []string{string(content[:125]), string(content[125:])}},
+		{250, []string{string(content)}},
+		{1000, []string{string(content)}},
+	}
+
+	for _, test := range tests {
+		composite := md5.New()
+		for _, part := range test.parts {
+			sum := md5.Sum([]byte(part))
+			composite.Write(sum[:])
+		}
+		expected := fmt.Sprintf(`"%s-%d"`, hex.EncodeToString(composite.Sum(nil)), len(test.parts))
+
+		etag, err := fileETag(path, test.partSize)
+		if err != nil || etag != expected {
+			t.Errorf("Expected %s with parts of %d bytes, got %s and %v", expected, test.partSize, etag, err)
+		}
+	}
+
+	emptyPart := md5.Sum(nil)
+	composite := md5.Sum(emptyPart[:])
+	if etag, err := fileETag(write("empty", nil), 100); err != nil || etag != `"`+hex.EncodeToString(composite[:])+`-1"` {
+		t.Errorf("Expected an empty file to be one empty part, got %s and %v", etag, err)
+	}
+}
+
+func TestFileETagFunction_run(t *testing.T) {
+	f := NewFileETagFunction()
+	path := filepath.Join(t.TempDir(), "index.html")
+	if err := os.WriteFile(path, []byte("Hello, World!"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(path string, partSizes ...int64) (string, *int64, string) {
+		partSizeTypes := make([]attr.Type, 0, len(partSizes))
+		partSizeValues := make([]attr.Value, 0, len(partSizes))
+		for _, partSize := range partSizes {
+			partSizeTypes = append(partSizeTypes, types.Int64Type)
+			partSizeValues = append(partSizeValues, types.Int64Value(partSize))
+		}
+		resp := testFunctionRun(t, f, types.StringValue(path), types.TupleValueMust(partSizeTypes, partSizeValues))
+		if resp.Error != nil {
+			return "", resp.Error.FunctionArgument, resp.Error.Text
+		}
+		return resp.Result.Value().(types.String).ValueString(), nil, ""
+	}
+
+	if etag, _, text := run(path); etag != `"65a8e27d8879283831b664bd8b7f0ad4"` {
+		t.Errorf("Expected the MD5 of the file, got %q and %q", etag, text)
+	}
+	if etag, _, text := run(path, 5); !strings.HasSuffix(etag, `-3"`) {
+		t.Errorf("Expected a multipart ETag of 3 parts, got %q and %q", etag, text)
+	}
+
+	missing := filepath.Join(filepath.Dir(path), "missing.html")
+	if _, argument, text := run(missing); argument == nil || *argument != 0 || !strings.Contains(text, missing) || !strings.Contains(text, "no such file") {
+		t.Errorf("Expected an error on the path naming it, got %v and %q", argument, text)
+	}
+	if _, argument, text := run(filepath.Dir(path)); argument == nil || *argument != 0 {
+		t.Errorf("Expected an error on a directory, got %v and %q", argument, text)
+	}
+
+	for _, partSizes := range [][]int64{{0}, {-1}, {5, 5}} {
+		if _, argument, text := run(path, partSizes...); argument == nil || *argument != 1 || !strings.Contains(text, "part size") {
+			t.Errorf("Expected an error on the part size %v, got %v and %q", partSizes, argument, text)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+const (
+	// defaultSizePrecision is the number of decimals of format_size when
+	// precision is not given.
+	defaultSizePrecision = 1
+	// maxSizePrecision is the largest precision accepted by format_size.
+	maxSizePrecision = 10
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &FormatSizeFunction{}
+
+func NewFormatSizeFunction() function.Function {
+	return &FormatSizeFunction{}
+}
+
+// FormatSizeFunction defines the function implementation.
+type FormatSizeFunction struct{}
+
+func (f *FormatSizeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "format_size"
+}
+
+func (f *FormatSizeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Formats a number of bytes such as 2147483648 as 2.0 GiB",
+		MarkdownDescription: "Formats a number of bytes with the largest binary unit (`KiB`, `MiB`, `GiB`, `TiB`, `PiB`) it amounts to at least one of, " +
+			"e.g. `2.0 GiB` for `2147483648`. Sizes under 1 KiB are formatted in bytes, without decimals, e.g. `512 B`. " +
+			"The result can be converted back with `parse_size`, exactly when no decimal was rounded.",
+		Parameters: []function.Parameter{
+			function.NumberParameter{
+				Name:                "bytes",
+				MarkdownDescription: "The number of bytes, a whole number of at least 0.",
+			},
+		},
+		VariadicParameter: function.Int64Parameter{
+			Name:                "precision",
+			MarkdownDescription: fmt.Sprintf("The number of decimals, from 0 to %d. Default: `%d`. At most one precision can be given.", maxSizePrecision, defaultSizePrecision),
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *FormatSizeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var bytes *big.Float
+	var precisions []int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &bytes, &precisions))
+	if resp.Error != nil {
+		return
+	}
+
+	precision := int64(defaultSizePrecision)
+	switch {
+	case len(precisions) > 1:
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("expected at most one precision, got %d", len(precisions)))
+		return
+	case len(precisions) == 1:
+		precision = precisions[0]
+	}
+	if precision < 0 || precision > maxSizePrecision {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("invalid precision %d: expected a number of decimals from 0 to %d", precision, maxSizePrecision))
+		return
+	}
+
+	size, err := formatSize(bytes, int(precision))
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, size))
+}
+
+// formatSize formats bytes with the largest binary unit it amounts to at
+// least one of, and precision decimals. A value rounded up to 1024 of a unit
+// is formatted with the next unit instead.
+func formatSize(bytes *big.Float, precision int) (string, error) {
+	if bytes.Sign() < 0 {
+		return "", fmt.Errorf("invalid size %s: expected a number of bytes of at least 0", bytes.Text('f', -1))
+	}
+	if !bytes.IsInt() {
+		return "", fmt.Errorf("invalid size %s: not a whole number of bytes", bytes.Text('f', -1))
+	}
+
+	unit := -1
+	for i, name := range binarySizeUnits {
+		if bytes.Cmp(new(big.Float).SetInt64(sizeUnits[strings.ToLower(name)])) < 0 {
+			break
+		}
+		unit = i
+	}
+	if unit < 0 {
+		return bytes.Text('f', 0) + " B", nil
+	}
+
+	text := sizeInUnit(bytes, unit, precision)
+	if rounded, ok := new(big.Float).SetString(text); ok && rounded.Cmp(big.NewFloat(1024)) >= 0 && unit+1 < len(binarySizeUnits) {
+		unit++
+		text = sizeInUnit(bytes, unit, precision)
+	}
+
+	return text + " " + binarySizeUnits[unit], nil
+}
+
+// sizeInUnit returns bytes in the binary unit at index unit, with precision
+// decimals.
+func sizeInUnit(bytes *big.Float, unit, precision int) string {
+	divisor := new(big.Float).SetInt64(sizeUnits[strings.ToLower(binarySizeUnits[unit])])
+	return new(big.Float).SetPrec(bytes.Prec()+64).Quo(bytes, divisor).Text('f', precision)
+}
@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"math/big"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccFormatSizeFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "default" {
+  value = provider::garage::format_size(2147483648)
+}
+
+output "precision" {
+  value = provider::garage::format_size(provider::garage::parse_size("1.5GiB"), 2)
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("default", knownvalue.StringExact("2.0 GiB")),
+					statecheck.ExpectKnownOutputValue("precision", knownvalue.StringExact("1.50 GiB")),
+				},
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::garage::format_size(-1)
+}
+`,
+				ExpectError: regexp.MustCompile(`invalid size -1`),
+			},
+		},
+	})
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		bytes     string
+		precision int
+		expected  string
+	}{
+		{"0", 1, "0 B"},
+		{"1", 1, "1 B"},
+		{"1023", 1, "1023 B"},
+		{"1024", 1, "1.0 KiB"},
+		{"1536", 1, "1.5 KiB"},
+		{"1536", 0, "2 KiB"},
+		{"1536", 3, "1.500 KiB"},
+		{"614400", 0, "600 KiB"},
+		{"1048575", 1, "1.0 MiB"},
+		{"1048575", 3, "1023.999 KiB"},
+		{"2147483648", 1, "2.0 GiB"},
+		{"53687091200", 1, "50.0 GiB"},
+		{"1610612736", 2, "1.50 GiB"},
+		{"1099511627776", 1, "1.0 TiB"},
+		{"1125899906842624", 1, "1.0 PiB"},
+		// PiB is the largest unit
+		{"1152921504606846976", 1, "1024.0 PiB"},
+	}
+
+	for _, test := range tests {
+		bytes, _ := new(big.Float).SetString(test.bytes)
+		got, err := formatSize(bytes, test.precision)
+		if err != nil {
+			t.Errorf("formatSize(%s, %d): unexpected error %v", test.bytes, test.precision, err)
+			continue
+		}
+		if got != test.expected {
+			t.Errorf("formatSize(%s, %d): expected %q, got %q", test.bytes, test.precision, test.expected, got)
+		}
+	}
+}
+
+func TestFormatSize_roundTrip(t *testing.T) {
+	for _, size := range []string{"1 B", "1.0 KiB", "1.5 MiB", "50.0 GiB", "3.0 TiB"} {
+		bytes, err := parseSize(size)
+		if err != nil {
+			t.Fatalf("parseSize(%q): unexpected error %v", size, err)
+		}
+		got, err := formatSize(new(big.Float).SetInt64(bytes), 1)
+		if err != nil || got != size {
+			t.Errorf("Expected %q back from %d, got %q and %v", size, bytes, got, err)
+		}
+	}
+}
+
+func TestFormatSize_invalid(t *testing.T) {
+	tests := []struct {
+		bytes string
+		error string
+	}{
+		{"-1", "invalid size -1: expected a number of bytes of at least 0"},
+		{"-1024", "invalid size -1024: expected a number of bytes of at least 0"},
+		{"1.5", "invalid size 1.5: not a whole number of bytes"},
+		{"1024.25", "invalid size 1024.25: not a whole number of bytes"},
+	}
+
+	for _, test := range tests {
+		bytes, _ := new(big.Float).SetString(test.bytes)
+		_, err := formatSize(bytes, 1)
+		if err == nil || err.Error() != test.error {
+			t.Errorf("formatSize(%s): expected error %q, got %v", test.bytes, test.error, err)
+		}
+	}
+}
+
+func TestFormatSizeFunction_run(t *testing.T) {
+	ctx := context.Background()
+	f := NewFormatSizeFunction()
+
+	run := func(bytes int64, precisions ...int64) *function.RunResponse {
+		precisionTypes := make([]attr.Type, 0, len(precisions))
+		precisionValues := make([]attr.Value, 0, len(precisions))
+		for _, precision := range precisions {
+			precisionTypes = append(precisionTypes, types.Int64Type)
+			precisionValues = append(precisionValues, types.Int64Value(precision))
+		}
+		resp := &function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+		f.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData([]attr.Value{
+			types.NumberValue(big.NewFloat(float64(bytes))),
+			types.TupleValueMust(precisionTypes, precisionValues),
+		})}, resp)
+		return resp
+	}
+
+	resp := run(2147483648)
+	if resp.Error != nil || !resp.Result.Value().Equal(types.StringValue("2.0 GiB")) {
+		t.Errorf("Expected 2.0 GiB, got %s and %v", resp.Result.Value(), resp.Error)
+	}
+
+	resp = run(2147483648, 0)
+	if resp.Error != nil || !resp.Result.Value().Equal(types.StringValue("2 GiB")) {
+		t.Errorf("Expected 2 GiB, got %s and %v", resp.Result.Value(), resp.Error)
+	}
+
+	for _, precisions := range [][]int64{{-1}, {11}, {1, 2}} {
+		resp = run(1024, precisions...)
+		if resp.Error == nil || resp.Error.FunctionArgument == nil || *resp.Error.FunctionArgument != 1 || !strings.Contains(resp.Error.Text, "precision") {
+			t.Errorf("Expected an error on the precision %v, got %v", precisions, resp.Error)
+		}
+	}
+}
@@ -0,0 +1,436 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = &GarageBucketCorsResource{}
+var _ resource.ResourceWithImportState = &GarageBucketCorsResource{}
+
+// GarageBucketCorsResource manages the whole CORS configuration of a bucket
+// through the S3 API.
+type GarageBucketCorsResource struct {
+	s3Client *s3.Client
+}
+
+type GarageBucketCorsResourceModel struct {
+	Bucket types.String `tfsdk:"bucket"`
+	Rules  types.List   `tfsdk:"rule"`
+	ID     types.String `tfsdk:"id"`
+}
+
+// CorsRuleModel describes a single CORS rule.
+type CorsRuleModel struct {
+	AllowedOrigins types.Set   `tfsdk:"allowed_origins"`
+	AllowedMethods types.Set   `tfsdk:"allowed_methods"`
+	AllowedHeaders types.Set   `tfsdk:"allowed_headers"`
+	ExposeHeaders  types.Set   `tfsdk:"expose_headers"`
+	MaxAgeSeconds  types.Int64 `tfsdk:"max_age_seconds"`
+}
+
+// corsRuleAttrTypes are the attribute types of a rule object.
+var corsRuleAttrTypes = map[string]attr.Type{
+	"allowed_origins": types.SetType{ElemType: types.StringType},
+	"allowed_methods": types.SetType{ElemType: types.StringType},
+	"allowed_headers": types.SetType{ElemType: types.StringType},
+	"expose_headers":  types.SetType{ElemType: types.StringType},
+	"max_age_seconds": types.Int64Type,
+}
+
+func NewGarageBucketCorsResource() resource.Resource {
+	return &GarageBucketCorsResource{}
+}
+
+func (r *GarageBucketCorsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_cors"
+}
+
+func (r *GarageBucketCorsResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the CORS configuration of a Garage bucket. The rules replace any configuration set outside of Terraform",
+		Attributes: map[string]schema.Attribute{
+			"bucket": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the bucket",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"rule": schema.ListNestedAttribute{
+				Required:    true,
+				Description: "CORS rules of the bucket. A request is allowed by the first rule matching its origin, method and headers",
+				Validators: []validator.List{
+					listvalidator.SizeBetween(1, 100),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"allowed_origins": schema.SetAttribute{
+							Required:    true,
+							ElementType: types.StringType,
+							Description: "Origins allowed to make requests, e.g. https://example.com. * allows any origin",
+							Validators: []validator.Set{
+								setvalidator.SizeAtLeast(1),
+							},
+						},
+						"allowed_methods": schema.SetAttribute{
+							Required:    true,
+							ElementType: types.StringType,
+							Description: "HTTP methods allowed: GET, PUT, POST, DELETE or HEAD",
+							Validators: []validator.Set{
+								setvalidator.SizeAtLeast(1),
+								setvalidator.ValueStringsAre(stringvalidator.OneOf("GET", "PUT", "POST", "DELETE", "HEAD")),
+							},
+						},
+						"allowed_headers": schema.SetAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Headers allowed in preflight requests. * allows any header",
+						},
+						"expose_headers": schema.SetAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Response headers readable by the browser",
+						},
+						"max_age_seconds": schema.Int64Attribute{
+							Optional:    true,
+							Description: "Time in seconds the browser may cache a preflight response",
+							Validators: []validator.Int64{
+								int64validator.AtLeast(0),
+							},
+						},
+					},
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the bucket",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *GarageBucketCorsResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			"Expected *GarageProviderModel",
+		)
+		return
+	}
+
+	r.s3Client = providerData.S3Client
+}
+
+func (r *GarageBucketCorsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan GarageBucketCorsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.put(ctx, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = plan.Bucket
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *GarageBucketCorsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state GarageBucketCorsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.s3Client == nil {
+		resp.Diagnostics.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return
+	}
+
+	output, err := r.s3Client.GetBucketCors(ctx, &s3.GetBucketCorsInput{
+		Bucket: aws.String(state.Bucket.ValueString()),
+	})
+	if isCorsNotFound(err) {
+		tflog.Warn(ctx, "CORS configuration not found, removing from state", map[string]interface{}{
+			"bucket": state.Bucket.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Read CORS Configuration",
+			fmt.Sprintf("Could not read the CORS configuration of bucket %s: %s", state.Bucket.ValueString(), err),
+		)
+		return
+	}
+
+	rules, diags := corsRulesValue(ctx, output.CORSRules, state.Rules)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.Rules = rules
+	state.ID = state.Bucket
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *GarageBucketCorsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan GarageBucketCorsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// PutBucketCors replaces the whole configuration
+	resp.Diagnostics.Append(r.put(ctx, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = plan.Bucket
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *GarageBucketCorsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state GarageBucketCorsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.s3Client == nil {
+		resp.Diagnostics.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return
+	}
+
+	_, err := r.s3Client.DeleteBucketCors(ctx, &s3.DeleteBucketCorsInput{
+		Bucket: aws.String(state.Bucket.ValueString()),
+	})
+	if isCorsNotFound(err) {
+		// Removed out-of-band, possibly with its bucket
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete CORS Configuration",
+			fmt.Sprintf("Could not delete the CORS configuration of bucket %s: %s", state.Bucket.ValueString(), err),
+		)
+	}
+}
+
+func (r *GarageBucketCorsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("bucket"), req, resp)
+}
+
+// put replaces the CORS configuration of the bucket with the rules of plan.
+func (r *GarageBucketCorsResource) put(ctx context.Context, plan GarageBucketCorsResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if r.s3Client == nil {
+		diags.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return diags
+	}
+
+	var models []CorsRuleModel
+	diags.Append(plan.Rules.ElementsAs(ctx, &models, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	rules := make([]s3types.CORSRule, 0, len(models))
+	for _, model := range models {
+		rule, d := corsRuleFromModel(ctx, model)
+		diags.Append(d...)
+		rules = append(rules, rule)
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	_, err := r.s3Client.PutBucketCors(ctx, &s3.PutBucketCorsInput{
+		Bucket:            aws.String(plan.Bucket.ValueString()),
+		CORSConfiguration: &s3types.CORSConfiguration{CORSRules: rules},
+	})
+	if err != nil {
+		diags.AddError(
+			"Failed to Set CORS Configuration",
+			fmt.Sprintf("Could not set the CORS configuration of bucket %s: %s", plan.Bucket.ValueString(), err),
+		)
+		return diags
+	}
+
+	tflog.Debug(ctx, "Set bucket CORS configuration", map[string]interface{}{
+		"bucket": plan.Bucket.ValueString(),
+		"rules":  len(rules),
+	})
+
+	return diags
+}
+
+// corsRuleFromModel converts a configured rule to its S3 form.
+func corsRuleFromModel(ctx context.Context, model CorsRuleModel) (s3types.CORSRule, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	rule := s3types.CORSRule{}
+
+	diags.Append(model.AllowedOrigins.ElementsAs(ctx, &rule.AllowedOrigins, false)...)
+	diags.Append(model.AllowedMethods.ElementsAs(ctx, &rule.AllowedMethods, false)...)
+	diags.Append(model.AllowedHeaders.ElementsAs(ctx, &rule.AllowedHeaders, false)...)
+	diags.Append(model.ExposeHeaders.ElementsAs(ctx, &rule.ExposeHeaders, false)...)
+	if !model.MaxAgeSeconds.IsNull() {
+		rule.MaxAgeSeconds = aws.Int32(int32(model.MaxAgeSeconds.ValueInt64()))
+	}
+
+	return rule, diags
+}
+
+// corsRulesValue converts the rules read from the bucket. When they are the
+// same rules as prior, in any order, prior is returned as is; otherwise they
+// are sorted so that the order does not depend on the server.
+func corsRulesValue(ctx context.Context, rules []s3types.CORSRule, prior types.List) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	ruleType := types.ObjectType{AttrTypes: corsRuleAttrTypes}
+
+	keys := make([]string, 0, len(rules))
+	byKey := make(map[string]attr.Value, len(rules))
+	for _, rule := range rules {
+		maxAge := types.Int64Null()
+		if rule.MaxAgeSeconds != nil {
+			maxAge = types.Int64Value(int64(aws.ToInt32(rule.MaxAgeSeconds)))
+		}
+
+		value, d := types.ObjectValueFrom(ctx, corsRuleAttrTypes, CorsRuleModel{
+			AllowedOrigins: corsStringSet(rule.AllowedOrigins),
+			AllowedMethods: corsStringSet(rule.AllowedMethods),
+			AllowedHeaders: corsStringSet(rule.AllowedHeaders),
+			ExposeHeaders:  corsStringSet(rule.ExposeHeaders),
+			MaxAgeSeconds:  maxAge,
+		})
+		diags.Append(d...)
+
+		key := corsRuleKey(rule)
+		keys = append(keys, key)
+		byKey[key] = value
+	}
+	if diags.HasError() {
+		return types.ListNull(ruleType), diags
+	}
+	sort.Strings(keys)
+
+	if !prior.IsNull() && !prior.IsUnknown() {
+		var priorModels []CorsRuleModel
+		diags.Append(prior.ElementsAs(ctx, &priorModels, false)...)
+		if diags.HasError() {
+			return types.ListNull(ruleType), diags
+		}
+
+		priorKeys := make([]string, 0, len(priorModels))
+		for _, model := range priorModels {
+			rule, d := corsRuleFromModel(ctx, model)
+			diags.Append(d...)
+			priorKeys = append(priorKeys, corsRuleKey(rule))
+		}
+		sort.Strings(priorKeys)
+
+		if strings.Join(priorKeys, "\n") == strings.Join(keys, "\n") {
+			return prior, diags
+		}
+	}
+
+	values := make([]attr.Value, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, byKey[key])
+	}
+
+	list, d := types.ListValue(ruleType, values)
+	diags.Append(d...)
+	return list, diags
+}
+
+// corsRuleKey returns a representation of rule that does not depend on the
+// order of its values, used to compare and sort rules.
+func corsRuleKey(rule s3types.CORSRule) string {
+	fields := make([]string, 0, 5)
+	for _, values := range [][]string{rule.AllowedOrigins, rule.AllowedMethods, rule.AllowedHeaders, rule.ExposeHeaders} {
+		sorted := append([]string(nil), values...)
+		sort.Strings(sorted)
+		fields = append(fields, strings.Join(sorted, ","))
+	}
+	if rule.MaxAgeSeconds != nil {
+		fields = append(fields, fmt.Sprint(aws.ToInt32(rule.MaxAgeSeconds)))
+	}
+	return strings.Join(fields, "|")
+}
+
+// corsStringSet converts values read from the bucket, none is null.
+func corsStringSet(values []string) types.Set {
+	if len(values) == 0 {
+		return types.SetNull(types.StringType)
+	}
+
+	elements := make([]attr.Value, 0, len(values))
+	for _, value := range values {
+		elements = append(elements, types.StringValue(value))
+	}
+	return types.SetValueMust(types.StringType, elements)
+}
+
+// isCorsNotFound reports whether err means the bucket has no CORS
+// configuration, or no longer exists.
+func isCorsNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	if isObjectNotFound(err) {
+		return true
+	}
+
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchCORSConfiguration"
+}
@@ -0,0 +1,137 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGarageBucketCorsResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageBucketCorsResourceConfig(`["https://example.com"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_bucket_cors.test", "rule.#", "2"),
+					resource.TestCheckResourceAttr("garage_bucket_cors.test", "rule.0.allowed_origins.#", "1"),
+					resource.TestCheckResourceAttr("garage_bucket_cors.test", "rule.0.max_age_seconds", "3600"),
+					resource.TestCheckResourceAttr("garage_bucket_cors.test", "rule.1.allowed_methods.#", "1"),
+				),
+			},
+			{
+				ResourceName:      "garage_bucket_cors.test",
+				ImportState:       true,
+				ImportStateId:     "test-bucket-cors",
+				ImportStateVerify: true,
+				// Imported rules are in the sorted order
+				ImportStateVerifyIgnore: []string{"rule"},
+			},
+			{
+				Config: testAccGarageBucketCorsResourceConfig(`["https://example.com", "https://www.example.com"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_bucket_cors.test", "rule.0.allowed_origins.#", "2"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestCorsRulesValue(t *testing.T) {
+	ctx := context.Background()
+	upload := s3types.CORSRule{
+		AllowedOrigins: []string{"https://example.com"},
+		AllowedMethods: []string{"PUT", "POST"},
+		AllowedHeaders: []string{"*"},
+		MaxAgeSeconds:  aws.Int32(3600),
+	}
+	download := s3types.CORSRule{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET"},
+	}
+
+	// Unordered rules from the server are sorted
+	sorted, diags := corsRulesValue(ctx, []s3types.CORSRule{upload, download}, types.ListNull(types.ObjectType{AttrTypes: corsRuleAttrTypes}))
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	reversed, _ := corsRulesValue(ctx, []s3types.CORSRule{download, upload}, types.ListNull(types.ObjectType{AttrTypes: corsRuleAttrTypes}))
+	if !sorted.Equal(reversed) {
+		t.Errorf("Expected the same order whatever the server order, got %s and %s", sorted, reversed)
+	}
+
+	var models []CorsRuleModel
+	_ = sorted.ElementsAs(ctx, &models, false)
+	if len(models) != 2 || !models[0].AllowedHeaders.IsNull() || !models[0].MaxAgeSeconds.IsNull() {
+		t.Errorf("Expected unset values to be null, got %v", models)
+	}
+
+	// The prior order is kept when the rules did not change, whatever the
+	// order of their values
+	prior, _ := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: corsRuleAttrTypes}, []CorsRuleModel{models[1], models[0]})
+	reordered := upload
+	reordered.AllowedMethods = []string{"POST", "PUT"}
+	kept, _ := corsRulesValue(ctx, []s3types.CORSRule{download, reordered}, prior)
+	if !kept.Equal(prior) {
+		t.Errorf("Expected the prior rules %s, got %s", prior, kept)
+	}
+
+	// Changed rules are sorted
+	changed := download
+	changed.MaxAgeSeconds = aws.Int32(60)
+	result, _ := corsRulesValue(ctx, []s3types.CORSRule{changed, upload}, prior)
+	_ = result.ElementsAs(ctx, &models, false)
+	if len(models) != 2 || models[0].MaxAgeSeconds.ValueInt64() != 60 {
+		t.Errorf("Expected the changed rules in sorted order, got %s", result)
+	}
+}
+
+func testAccGarageBucketCorsResourceConfig(origins string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-cors"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+  owner = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_bucket_cors" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket = garage_bucket.test.global_alias
+
+  rule = [
+    {
+      allowed_origins = %[2]s
+      allowed_methods = ["PUT", "POST"]
+      allowed_headers = ["*"]
+      expose_headers  = ["ETag"]
+      max_age_seconds = 3600
+    },
+    {
+      allowed_origins = ["*"]
+      allowed_methods = ["GET"]
+    },
+  ]
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"), origins)
+}
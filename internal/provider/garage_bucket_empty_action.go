@@ -0,0 +1,189 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+var _ action.Action = &GarageBucketEmptyAction{}
+var _ action.ActionWithConfigure = &GarageBucketEmptyAction{}
+
+// GarageBucketEmptyAction deletes the objects of a bucket on demand, without
+// destroying the bucket.
+type GarageBucketEmptyAction struct {
+	s3Client    *s3.Client
+	s3AccessKey string
+	// adminClient resolves bucket IDs to their global alias. It is nil when
+	// no admin endpoint is configured.
+	adminClient *client.Client
+}
+
+type GarageBucketEmptyActionModel struct {
+	Bucket types.String `tfsdk:"bucket"`
+	Prefix types.String `tfsdk:"prefix"`
+}
+
+func NewGarageBucketEmptyAction() action.Action {
+	return &GarageBucketEmptyAction{}
+}
+
+func (a *GarageBucketEmptyAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_empty"
+}
+
+func (a *GarageBucketEmptyAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Deletes the objects of a Garage bucket, or of a prefix of it, and aborts its unfinished multipart uploads, without destroying the bucket. " +
+			"The numbers of deleted objects and aborted uploads are reported as progress",
+		Attributes: map[string]schema.Attribute{
+			"bucket": schema.StringAttribute{
+				Required:    true,
+				Description: "Global alias or ID of the bucket to empty. An ID is resolved to the first global alias of the bucket through the admin API",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only delete the objects and abort the uploads whose key starts with this prefix, e.g. tmp/. The whole bucket is emptied when not set",
+			},
+		},
+	}
+}
+
+func (a *GarageBucketEmptyAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			"Expected *GarageProviderModel",
+		)
+		return
+	}
+
+	a.s3Client = providerData.S3Client
+	a.s3AccessKey = providerData.AccessKey.ValueString()
+
+	// The admin API is only needed for buckets given by ID
+	if providerData.adminEndpoint() != "" {
+		a.adminClient = providerData.adminClient()
+	}
+}
+
+func (a *GarageBucketEmptyAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config GarageBucketEmptyActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if a.s3Client == nil {
+		resp.Diagnostics.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return
+	}
+
+	bucket, diags := a.bucketAlias(ctx, config.Bucket.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	prefix := config.Prefix.ValueString()
+
+	tflog.Info(ctx, "Emptying bucket", map[string]interface{}{
+		"bucket": bucket,
+		"prefix": prefix,
+	})
+
+	objects, uploads, err := emptyBucket(ctx, a.s3Client, bucket, prefix, func(objects int) {
+		tflog.Info(ctx, "Deleted a page of objects", map[string]interface{}{
+			"bucket":  bucket,
+			"prefix":  prefix,
+			"objects": objects,
+		})
+		sendProgress(resp, fmt.Sprintf("Deleted %d objects from bucket %s so far", objects, bucket))
+	})
+	if err != nil {
+		detail := fmt.Sprintf("Deleted %d objects and aborted %d multipart uploads of bucket %s before failing: %s",
+			objects, uploads, bucket, objectErrorDetail(a.s3AccessKey, bucket, true, err))
+		if ctx.Err() != nil {
+			detail = fmt.Sprintf("The action was cancelled after deleting %d objects and aborting %d multipart uploads of bucket %s. "+
+				"Invoke it again to delete the remaining objects.", objects, uploads, bucket)
+		}
+		resp.Diagnostics.AddError("Failed to Empty Bucket", detail)
+		return
+	}
+
+	tflog.Info(ctx, "Emptied bucket", map[string]interface{}{
+		"bucket":  bucket,
+		"prefix":  prefix,
+		"objects": objects,
+		"uploads": uploads,
+	})
+	sendProgress(resp, fmt.Sprintf("Emptied bucket %s: deleted %d objects and aborted %d multipart uploads", bucket, objects, uploads))
+}
+
+// bucketAlias returns the global alias to address bucket with through the S3
+// API, resolving bucket IDs through the admin API.
+func (a *GarageBucketEmptyAction) bucketAlias(ctx context.Context, bucket string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if !bucketIDPattern.MatchString(bucket) {
+		return bucket, diags
+	}
+
+	if a.adminClient == nil {
+		diags.AddError(
+			"Missing Admin Endpoint",
+			fmt.Sprintf("%s is a bucket ID, which is resolved to the global alias of the bucket through the admin API: "+
+				"configure endpoints.admin or pass the global alias as bucket", bucket),
+		)
+		return "", diags
+	}
+
+	info, err := a.adminClient.GetBucketInfo(ctx, client.GetBucketInfoRequest{ID: &bucket})
+	if err != nil {
+		diags.Append(diagFromClientError(fmt.Sprintf("Unable to read bucket %s", bucket), err))
+		return "", diags
+	}
+	if info == nil {
+		diags.AddError("Bucket Not Found", fmt.Sprintf("No bucket with ID %s exists on this cluster.", bucket))
+		return "", diags
+	}
+	if len(info.GlobalAliases) == 0 {
+		diags.AddError(
+			"Bucket Without Global Alias",
+			fmt.Sprintf("Bucket %s has no global alias, so it cannot be addressed through the S3 API. Add a global alias to empty it.", bucket),
+		)
+		return "", diags
+	}
+
+	return info.GlobalAliases[0], diags
+}
+
+// sendProgress reports message to Terraform, when it listens for progress.
+func sendProgress(resp *action.InvokeResponse, message string) {
+	if resp.SendProgress != nil {
+		resp.SendProgress(action.InvokeProgressEvent{Message: message})
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+func TestGarageBucketEmptyAction(t *testing.T) {
+	bucketID := strings.Repeat("c", 64)
+	server := newTestEmptyBucketServer(t, "staging",
+		[]string{"tmp/a", "tmp/b", "keep/c"}, []string{"tmp/x", "keep/y"})
+	admin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/GetBucketInfo" || r.URL.Query().Get("id") != bucketID {
+			t.Errorf("Unexpected request to %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"id": %q, "globalAliases": ["staging"], "keys": []}`, bucketID)
+	}))
+	defer admin.Close()
+
+	a := &GarageBucketEmptyAction{s3Client: testS3Client(server.URL), adminClient: client.NewClient(admin.URL, "test-token")}
+
+	// A prefix of a bucket given by ID
+	resp, progress := testActionInvoke(t, context.Background(), a, map[string]tftypes.Value{
+		"bucket": tftypes.NewValue(tftypes.String, bucketID),
+		"prefix": tftypes.NewValue(tftypes.String, "tmp/"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if objects, uploads := server.remaining(); fmt.Sprint(objects, uploads) != "[keep/c] [keep/y]" {
+		t.Errorf("Expected only the objects and uploads outside the prefix to be left, got %v and %v", objects, uploads)
+	}
+	// One page per object
+	expected := []string{
+		"Deleted 1 objects from bucket staging so far",
+		"Deleted 2 objects from bucket staging so far",
+		"Emptied bucket staging: deleted 2 objects and aborted 1 multipart uploads",
+	}
+	if fmt.Sprint(progress) != fmt.Sprint(expected) {
+		t.Errorf("Expected progress %q, got %q", expected, progress)
+	}
+
+	// The whole bucket, by alias
+	resp, progress = testActionInvoke(t, context.Background(), a, map[string]tftypes.Value{
+		"bucket": tftypes.NewValue(tftypes.String, "staging"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if objects, uploads := server.remaining(); len(objects) != 0 || len(uploads) != 0 {
+		t.Errorf("Expected an empty bucket, got objects %v and uploads %v", objects, uploads)
+	}
+	if last := progress[len(progress)-1]; last != "Emptied bucket staging: deleted 1 objects and aborted 1 multipart uploads" {
+		t.Errorf("Unexpected final progress %q", last)
+	}
+}
+
+func TestGarageBucketEmptyAction_errors(t *testing.T) {
+	server := newTestEmptyBucketServer(t, "staging", []string{"a"}, nil)
+
+	a := &GarageBucketEmptyAction{s3Client: testS3Client(server.URL)}
+	resp, _ := testActionInvoke(t, context.Background(), a, map[string]tftypes.Value{
+		"bucket": tftypes.NewValue(tftypes.String, strings.Repeat("c", 64)),
+	})
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Missing Admin Endpoint" {
+		t.Errorf("Expected a Missing Admin Endpoint error for a bucket ID, got %v", resp.Diagnostics)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resp, _ = testActionInvoke(t, ctx, a, map[string]tftypes.Value{
+		"bucket": tftypes.NewValue(tftypes.String, "staging"),
+	})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "cancelled after deleting 0 objects") {
+		t.Errorf("Expected the cancellation to be reported, got %v", resp.Diagnostics)
+	}
+	if objects, _ := server.remaining(); len(objects) != 1 {
+		t.Errorf("Expected nothing to be deleted once cancelled, got %v", objects)
+	}
+
+	a = &GarageBucketEmptyAction{}
+	resp, _ = testActionInvoke(t, context.Background(), a, map[string]tftypes.Value{
+		"bucket": tftypes.NewValue(tftypes.String, "staging"),
+	})
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Missing S3 Endpoint" {
+		t.Errorf("Expected a Missing S3 Endpoint error, got %v", resp.Diagnostics)
+	}
+}
+
+// testEmptyBucketServer is an S3 API serving the objects and multipart
+// uploads of a single bucket, one object per listing page.
+type testEmptyBucketServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	objects map[string]bool
+	uploads map[string]bool
+}
+
+func newTestEmptyBucketServer(t *testing.T, bucket string, objects, uploads []string) *testEmptyBucketServer {
+	s := &testEmptyBucketServer{objects: map[string]bool{}, uploads: map[string]bool{}}
+	for _, key := range objects {
+		s.objects[key] = true
+	}
+	for _, key := range uploads {
+		s.uploads[key] = true
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		query := r.URL.Query()
+		if !strings.HasPrefix(r.URL.Path, "/"+bucket) {
+			t.Errorf("Unexpected request to %s", r.URL)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && query.Has("uploads"):
+			var result strings.Builder
+			for _, key := range sortedKeys(s.uploads) {
+				fmt.Fprintf(&result, `<Upload><Key>%s</Key><UploadId>id-%s</UploadId></Upload>`, key, key)
+			}
+			_, _ = fmt.Fprintf(w, `<ListMultipartUploadsResult>%s</ListMultipartUploadsResult>`, result.String())
+		case r.Method == http.MethodGet:
+			var keys []string
+			for _, key := range sortedKeys(s.objects) {
+				if strings.HasPrefix(key, query.Get("prefix")) && key > query.Get("continuation-token") {
+					keys = append(keys, key)
+				}
+			}
+			var result strings.Builder
+			if len(keys) > 0 {
+				fmt.Fprintf(&result, `<Contents><Key>%s</Key></Contents>`, keys[0])
+			}
+			if len(keys) > 1 {
+				fmt.Fprintf(&result, `<IsTruncated>true</IsTruncated><NextContinuationToken>%s</NextContinuationToken>`, keys[0])
+			}
+			_, _ = fmt.Fprintf(w, `<ListBucketResult>%s</ListBucketResult>`, result.String())
+		case r.Method == http.MethodPost && query.Has("delete"):
+			var request struct {
+				Objects []struct{ Key string } `xml:"Object"`
+			}
+			if err := xml.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Errorf("Unexpected delete request body: %s", err)
+				return
+			}
+			for _, object := range request.Objects {
+				delete(s.objects, object.Key)
+			}
+			_, _ = w.Write([]byte(`<DeleteResult></DeleteResult>`))
+		case r.Method == http.MethodDelete && query.Has("uploadId"):
+			delete(s.uploads, strings.TrimPrefix(r.URL.Path, "/"+bucket+"/"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected %s request to %s", r.Method, r.URL)
+		}
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// remaining returns the keys of the objects and uploads left, sorted.
+func (s *testEmptyBucketServer) remaining() ([]string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedKeys(s.objects), sortedKeys(s.uploads)
+}
+
+// testActionInvoke invokes a with the configuration attrs and returns the
+// response and the progress messages sent.
+func testActionInvoke(t *testing.T, ctx context.Context, a action.Action, attrs map[string]tftypes.Value) (*action.InvokeResponse, []string) {
+	t.Helper()
+
+	schemaResp := &action.SchemaResponse{}
+	a.Schema(ctx, action.SchemaRequest{}, schemaResp)
+
+	objectType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatalf("Unexpected schema type")
+	}
+	values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, typ := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(typ, nil)
+	}
+	for name, v := range attrs {
+		values[name] = v
+	}
+
+	var progress []string
+	resp := &action.InvokeResponse{SendProgress: func(event action.InvokeProgressEvent) {
+		progress = append(progress, event.Message)
+	}}
+	a.Invoke(ctx, action.InvokeRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)},
+	}, resp)
+
+	return resp, progress
+}
@@ -0,0 +1,582 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = &GarageBucketLifecycleResource{}
+var _ resource.ResourceWithImportState = &GarageBucketLifecycleResource{}
+var _ resource.ResourceWithValidateConfig = &GarageBucketLifecycleResource{}
+
+// lifecycleDateLayout is the format of expiration_date.
+const lifecycleDateLayout = "2006-01-02"
+
+// GarageBucketLifecycleResource manages the lifecycle configuration of a
+// bucket through the S3 API. Garage only applies expirations and the abort
+// of incomplete multipart uploads.
+type GarageBucketLifecycleResource struct {
+	s3Client *s3.Client
+}
+
+type GarageBucketLifecycleResourceModel struct {
+	Bucket types.String `tfsdk:"bucket"`
+	Rules  types.List   `tfsdk:"rule"`
+	ID     types.String `tfsdk:"id"`
+}
+
+// LifecycleRuleModel describes a single lifecycle rule.
+type LifecycleRuleModel struct {
+	ID                                 types.String `tfsdk:"id"`
+	Enabled                            types.Bool   `tfsdk:"enabled"`
+	Prefix                             types.String `tfsdk:"prefix"`
+	ObjectSizeGreaterThan              types.Int64  `tfsdk:"object_size_greater_than"`
+	ObjectSizeLessThan                 types.Int64  `tfsdk:"object_size_less_than"`
+	ExpirationDays                     types.Int64  `tfsdk:"expiration_days"`
+	ExpirationDate                     types.String `tfsdk:"expiration_date"`
+	AbortIncompleteMultipartUploadDays types.Int64  `tfsdk:"abort_incomplete_multipart_upload_days"`
+	Transition                         types.List   `tfsdk:"transition"`
+}
+
+// lifecycleTransitionAttrTypes are the attribute types of a transition
+// object, which is only accepted to reject it with a clear message.
+var lifecycleTransitionAttrTypes = map[string]attr.Type{
+	"days":          types.Int64Type,
+	"storage_class": types.StringType,
+}
+
+// lifecycleRuleAttrTypes are the attribute types of a rule object.
+var lifecycleRuleAttrTypes = map[string]attr.Type{
+	"id":                                     types.StringType,
+	"enabled":                                types.BoolType,
+	"prefix":                                 types.StringType,
+	"object_size_greater_than":               types.Int64Type,
+	"object_size_less_than":                  types.Int64Type,
+	"expiration_days":                        types.Int64Type,
+	"expiration_date":                        types.StringType,
+	"abort_incomplete_multipart_upload_days": types.Int64Type,
+	"transition":                             types.ListType{ElemType: types.ObjectType{AttrTypes: lifecycleTransitionAttrTypes}},
+}
+
+func NewGarageBucketLifecycleResource() resource.Resource {
+	return &GarageBucketLifecycleResource{}
+}
+
+func (r *GarageBucketLifecycleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_lifecycle"
+}
+
+func (r *GarageBucketLifecycleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the lifecycle configuration of a Garage bucket. Garage supports object expiration and the abort of incomplete multipart uploads",
+		Attributes: map[string]schema.Attribute{
+			"bucket": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the bucket",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"rule": schema.ListNestedAttribute{
+				Required:    true,
+				Description: "Lifecycle rules of the bucket. Each rule needs at least one of expiration_days, expiration_date or abort_incomplete_multipart_upload_days",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Required:    true,
+							Description: "Unique identifier of the rule",
+							Validators: []validator.String{
+								stringvalidator.LengthBetween(1, 255),
+							},
+						},
+						"enabled": schema.BoolAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(true),
+							Description: "Whether the rule is applied. Defaults to true",
+						},
+						"prefix": schema.StringAttribute{
+							Optional:    true,
+							Description: "Key prefix of the objects the rule applies to, e.g. tmp/. Defaults to all objects",
+						},
+						"object_size_greater_than": schema.Int64Attribute{
+							Optional:    true,
+							Description: "Only apply the rule to objects larger than this size in bytes",
+							Validators: []validator.Int64{
+								int64validator.AtLeast(0),
+							},
+						},
+						"object_size_less_than": schema.Int64Attribute{
+							Optional:    true,
+							Description: "Only apply the rule to objects smaller than this size in bytes",
+							Validators: []validator.Int64{
+								int64validator.AtLeast(1),
+							},
+						},
+						"expiration_days": schema.Int64Attribute{
+							Optional:    true,
+							Description: "Delete objects this many days after their creation",
+							Validators: []validator.Int64{
+								int64validator.AtLeast(1),
+								int64validator.ConflictsWith(path.MatchRelative().AtParent().AtName("expiration_date")),
+							},
+						},
+						"expiration_date": schema.StringAttribute{
+							Optional:    true,
+							Description: "Delete objects from this date (YYYY-MM-DD, midnight UTC) on",
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`), "must be a date in the YYYY-MM-DD format"),
+							},
+						},
+						"abort_incomplete_multipart_upload_days": schema.Int64Attribute{
+							Optional:    true,
+							Description: "Abort multipart uploads this many days after they were initiated",
+							Validators: []validator.Int64{
+								int64validator.AtLeast(1),
+							},
+						},
+						"transition": schema.ListNestedAttribute{
+							Optional:    true,
+							Description: "Not supported by Garage, which has no storage classes. Setting it is an error",
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"days": schema.Int64Attribute{
+										Optional:    true,
+										Description: "Days after creation to transition objects",
+									},
+									"storage_class": schema.StringAttribute{
+										Optional:    true,
+										Description: "Storage class to transition objects to",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the bucket",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *GarageBucketLifecycleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var rulesList types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("rule"), &rulesList)...)
+	if resp.Diagnostics.HasError() || rulesList.IsNull() || rulesList.IsUnknown() {
+		return
+	}
+
+	var rules []LifecycleRuleModel
+	resp.Diagnostics.Append(rulesList.ElementsAs(ctx, &rules, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ids := map[string]bool{}
+	for i, rule := range rules {
+		rulePath := path.Root("rule").AtListIndex(i)
+
+		// Garage would reject the whole configuration server-side
+		if !rule.Transition.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				rulePath.AtName("transition"),
+				"Unsupported Lifecycle Transition",
+				"Garage does not support lifecycle transitions as it has a single storage class. "+
+					"Only expiration_days, expiration_date and abort_incomplete_multipart_upload_days are applied.",
+			)
+		}
+
+		if !rule.ID.IsUnknown() && !rule.ID.IsNull() {
+			if ids[rule.ID.ValueString()] {
+				resp.Diagnostics.AddAttributeError(
+					rulePath.AtName("id"),
+					"Duplicate Lifecycle Rule ID",
+					fmt.Sprintf("Rule ID %q is used by more than one rule", rule.ID.ValueString()),
+				)
+			}
+			ids[rule.ID.ValueString()] = true
+		}
+
+		if rule.ExpirationDays.IsNull() && rule.ExpirationDate.IsNull() && rule.AbortIncompleteMultipartUploadDays.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				rulePath,
+				"Missing Lifecycle Action",
+				"Each rule needs at least one of expiration_days, expiration_date or abort_incomplete_multipart_upload_days.",
+			)
+		}
+
+		if !rule.ExpirationDate.IsNull() && !rule.ExpirationDate.IsUnknown() {
+			if _, err := time.Parse(lifecycleDateLayout, rule.ExpirationDate.ValueString()); err != nil {
+				resp.Diagnostics.AddAttributeError(
+					rulePath.AtName("expiration_date"),
+					"Invalid Expiration Date",
+					fmt.Sprintf("Expected a date in the YYYY-MM-DD format, got %q: %s", rule.ExpirationDate.ValueString(), err),
+				)
+			}
+		}
+	}
+}
+
+func (r *GarageBucketLifecycleResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			"Expected *GarageProviderModel",
+		)
+		return
+	}
+
+	r.s3Client = providerData.S3Client
+}
+
+func (r *GarageBucketLifecycleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan GarageBucketLifecycleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.put(ctx, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = plan.Bucket
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *GarageBucketLifecycleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state GarageBucketLifecycleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.s3Client == nil {
+		resp.Diagnostics.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return
+	}
+
+	output, err := r.s3Client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(state.Bucket.ValueString()),
+	})
+	if isLifecycleNotFound(err) {
+		tflog.Warn(ctx, "Lifecycle configuration not found, removing from state", map[string]interface{}{
+			"bucket": state.Bucket.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Read Lifecycle Configuration",
+			fmt.Sprintf("Could not read the lifecycle configuration of bucket %s: %s", state.Bucket.ValueString(), err),
+		)
+		return
+	}
+
+	rules, diags := lifecycleRulesValue(ctx, output.Rules, state.Rules)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.Rules = rules
+	state.ID = state.Bucket
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *GarageBucketLifecycleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan GarageBucketLifecycleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// PutBucketLifecycleConfiguration replaces the whole configuration
+	resp.Diagnostics.Append(r.put(ctx, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = plan.Bucket
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *GarageBucketLifecycleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state GarageBucketLifecycleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.s3Client == nil {
+		resp.Diagnostics.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return
+	}
+
+	_, err := r.s3Client.DeleteBucketLifecycle(ctx, &s3.DeleteBucketLifecycleInput{
+		Bucket: aws.String(state.Bucket.ValueString()),
+	})
+	if isLifecycleNotFound(err) {
+		// Removed out-of-band, possibly with its bucket
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete Lifecycle Configuration",
+			fmt.Sprintf("Could not delete the lifecycle configuration of bucket %s: %s", state.Bucket.ValueString(), err),
+		)
+	}
+}
+
+func (r *GarageBucketLifecycleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("bucket"), req, resp)
+}
+
+// put replaces the lifecycle configuration of the bucket with the rules of
+// plan.
+func (r *GarageBucketLifecycleResource) put(ctx context.Context, plan GarageBucketLifecycleResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if r.s3Client == nil {
+		diags.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return diags
+	}
+
+	var models []LifecycleRuleModel
+	diags.Append(plan.Rules.ElementsAs(ctx, &models, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	rules := make([]s3types.LifecycleRule, 0, len(models))
+	for _, model := range models {
+		rules = append(rules, lifecycleRuleFromModel(model))
+	}
+
+	_, err := r.s3Client.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket:                 aws.String(plan.Bucket.ValueString()),
+		LifecycleConfiguration: &s3types.BucketLifecycleConfiguration{Rules: rules},
+	})
+	if err != nil {
+		diags.AddError(
+			"Failed to Set Lifecycle Configuration",
+			fmt.Sprintf("Could not set the lifecycle configuration of bucket %s: %s", plan.Bucket.ValueString(), err),
+		)
+		return diags
+	}
+
+	tflog.Debug(ctx, "Set bucket lifecycle configuration", map[string]interface{}{
+		"bucket": plan.Bucket.ValueString(),
+		"rules":  len(rules),
+	})
+
+	return diags
+}
+
+// lifecycleRuleFromModel converts a configured rule to its S3 form. The
+// filter conditions are combined with And when there is more than one.
+func lifecycleRuleFromModel(model LifecycleRuleModel) s3types.LifecycleRule {
+	rule := s3types.LifecycleRule{
+		ID:     aws.String(model.ID.ValueString()),
+		Status: s3types.ExpirationStatusEnabled,
+		Filter: &s3types.LifecycleRuleFilter{},
+	}
+	if !model.Enabled.ValueBool() {
+		rule.Status = s3types.ExpirationStatusDisabled
+	}
+
+	prefix := model.Prefix.ValueStringPointer()
+	greaterThan := model.ObjectSizeGreaterThan.ValueInt64Pointer()
+	lessThan := model.ObjectSizeLessThan.ValueInt64Pointer()
+	conditions := 0
+	for _, set := range []bool{prefix != nil, greaterThan != nil, lessThan != nil} {
+		if set {
+			conditions++
+		}
+	}
+	if conditions > 1 {
+		rule.Filter.And = &s3types.LifecycleRuleAndOperator{
+			Prefix:                prefix,
+			ObjectSizeGreaterThan: greaterThan,
+			ObjectSizeLessThan:    lessThan,
+		}
+	} else {
+		rule.Filter.Prefix = prefix
+		rule.Filter.ObjectSizeGreaterThan = greaterThan
+		rule.Filter.ObjectSizeLessThan = lessThan
+	}
+
+	if !model.ExpirationDays.IsNull() {
+		rule.Expiration = &s3types.LifecycleExpiration{Days: aws.Int32(int32(model.ExpirationDays.ValueInt64()))}
+	} else if !model.ExpirationDate.IsNull() {
+		// ValidateConfig guarantees a valid date
+		date, _ := time.Parse(lifecycleDateLayout, model.ExpirationDate.ValueString())
+		rule.Expiration = &s3types.LifecycleExpiration{Date: aws.Time(date)}
+	}
+
+	if !model.AbortIncompleteMultipartUploadDays.IsNull() {
+		rule.AbortIncompleteMultipartUpload = &s3types.AbortIncompleteMultipartUpload{
+			DaysAfterInitiation: aws.Int32(int32(model.AbortIncompleteMultipartUploadDays.ValueInt64())),
+		}
+	}
+
+	return rule
+}
+
+// lifecycleRuleModel converts a rule read from the bucket.
+func lifecycleRuleModel(rule s3types.LifecycleRule) LifecycleRuleModel {
+	model := LifecycleRuleModel{
+		ID:                                 types.StringPointerValue(rule.ID),
+		Enabled:                            types.BoolValue(rule.Status == s3types.ExpirationStatusEnabled),
+		Prefix:                             types.StringPointerValue(rule.Prefix),
+		ObjectSizeGreaterThan:              types.Int64Null(),
+		ObjectSizeLessThan:                 types.Int64Null(),
+		ExpirationDays:                     types.Int64Null(),
+		ExpirationDate:                     types.StringNull(),
+		AbortIncompleteMultipartUploadDays: types.Int64Null(),
+		Transition:                         types.ListNull(types.ObjectType{AttrTypes: lifecycleTransitionAttrTypes}),
+	}
+
+	if filter := rule.Filter; filter != nil {
+		prefix, greaterThan, lessThan := filter.Prefix, filter.ObjectSizeGreaterThan, filter.ObjectSizeLessThan
+		if filter.And != nil {
+			prefix, greaterThan, lessThan = filter.And.Prefix, filter.And.ObjectSizeGreaterThan, filter.And.ObjectSizeLessThan
+		}
+		if prefix != nil {
+			model.Prefix = types.StringPointerValue(prefix)
+		}
+		model.ObjectSizeGreaterThan = types.Int64PointerValue(greaterThan)
+		model.ObjectSizeLessThan = types.Int64PointerValue(lessThan)
+	}
+
+	// An empty prefix applies to all objects, like no prefix
+	if model.Prefix.ValueString() == "" {
+		model.Prefix = types.StringNull()
+	}
+
+	if expiration := rule.Expiration; expiration != nil {
+		if expiration.Days != nil {
+			model.ExpirationDays = types.Int64Value(int64(aws.ToInt32(expiration.Days)))
+		}
+		if expiration.Date != nil {
+			model.ExpirationDate = types.StringValue(expiration.Date.UTC().Format(lifecycleDateLayout))
+		}
+	}
+
+	if abort := rule.AbortIncompleteMultipartUpload; abort != nil && abort.DaysAfterInitiation != nil {
+		model.AbortIncompleteMultipartUploadDays = types.Int64Value(int64(aws.ToInt32(abort.DaysAfterInitiation)))
+	}
+
+	return model
+}
+
+// lifecycleRulesValue converts the rules read from the bucket. They are in
+// the order of prior when they have the same IDs, and sorted by ID
+// otherwise, so that the order does not depend on the server.
+func lifecycleRulesValue(ctx context.Context, rules []s3types.LifecycleRule, prior types.List) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	ruleType := types.ObjectType{AttrTypes: lifecycleRuleAttrTypes}
+
+	byID := make(map[string]LifecycleRuleModel, len(rules))
+	ids := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		model := lifecycleRuleModel(rule)
+		byID[model.ID.ValueString()] = model
+		ids = append(ids, model.ID.ValueString())
+	}
+	sort.Strings(ids)
+
+	if !prior.IsNull() && !prior.IsUnknown() {
+		var priorModels []LifecycleRuleModel
+		diags.Append(prior.ElementsAs(ctx, &priorModels, false)...)
+		if diags.HasError() {
+			return types.ListNull(ruleType), diags
+		}
+
+		priorIDs := make([]string, 0, len(priorModels))
+		for _, model := range priorModels {
+			priorIDs = append(priorIDs, model.ID.ValueString())
+		}
+		sortedPriorIDs := append([]string(nil), priorIDs...)
+		sort.Strings(sortedPriorIDs)
+
+		if slices.Equal(sortedPriorIDs, ids) {
+			ids = priorIDs
+		}
+	}
+
+	models := make([]LifecycleRuleModel, 0, len(ids))
+	for _, id := range ids {
+		models = append(models, byID[id])
+	}
+
+	list, d := types.ListValueFrom(ctx, ruleType, models)
+	diags.Append(d...)
+	return list, diags
+}
+
+// isLifecycleNotFound reports whether err means the bucket has no lifecycle
+// configuration, or no longer exists.
+func isLifecycleNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	if isObjectNotFound(err) {
+		return true
+	}
+
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchLifecycleConfiguration"
+}
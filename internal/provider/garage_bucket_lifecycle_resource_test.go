@@ -0,0 +1,215 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGarageBucketLifecycleResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageBucketLifecycleResourceConfig(7),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_bucket_lifecycle.test", "rule.#", "2"),
+					resource.TestCheckResourceAttr("garage_bucket_lifecycle.test", "rule.0.id", "expire-tmp"),
+					resource.TestCheckResourceAttr("garage_bucket_lifecycle.test", "rule.0.expiration_days", "7"),
+					resource.TestCheckResourceAttr("garage_bucket_lifecycle.test", "rule.0.enabled", "true"),
+					resource.TestCheckResourceAttr("garage_bucket_lifecycle.test", "rule.1.abort_incomplete_multipart_upload_days", "1"),
+				),
+			},
+			{
+				ResourceName:      "garage_bucket_lifecycle.test",
+				ImportState:       true,
+				ImportStateId:     "test-bucket-lifecycle",
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccGarageBucketLifecycleResourceConfig(30),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_bucket_lifecycle.test", "rule.0.expiration_days", "30"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestGarageBucketLifecycleResourceValidateConfig(t *testing.T) {
+	transition := types.ListValueMust(types.ObjectType{AttrTypes: lifecycleTransitionAttrTypes}, nil)
+
+	tests := []struct {
+		name    string
+		rules   []LifecycleRuleModel
+		wantErr string
+	}{
+		{name: "valid", rules: []LifecycleRuleModel{
+			testLifecycleRule("expire", func(m *LifecycleRuleModel) { m.ExpirationDays = types.Int64Value(7) }),
+			testLifecycleRule("abort", func(m *LifecycleRuleModel) { m.AbortIncompleteMultipartUploadDays = types.Int64Value(1) }),
+		}},
+		{name: "transition", wantErr: "Garage does not support lifecycle transitions", rules: []LifecycleRuleModel{
+			testLifecycleRule("archive", func(m *LifecycleRuleModel) {
+				m.ExpirationDays = types.Int64Value(7)
+				m.Transition = transition
+			}),
+		}},
+		{name: "no action", wantErr: "at least one of", rules: []LifecycleRuleModel{
+			testLifecycleRule("noop", func(m *LifecycleRuleModel) { m.Prefix = types.StringValue("tmp/") }),
+		}},
+		{name: "duplicate id", wantErr: "more than one rule", rules: []LifecycleRuleModel{
+			testLifecycleRule("expire", func(m *LifecycleRuleModel) { m.ExpirationDays = types.Int64Value(7) }),
+			testLifecycleRule("expire", func(m *LifecycleRuleModel) { m.ExpirationDays = types.Int64Value(30) }),
+		}},
+		{name: "invalid date", wantErr: "Invalid Expiration Date", rules: []LifecycleRuleModel{
+			testLifecycleRule("expire", func(m *LifecycleRuleModel) { m.ExpirationDate = types.StringValue("2025-02-30") }),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := &GarageBucketLifecycleResource{}
+			schemaResp := &fwresource.SchemaResponse{}
+			r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+			rules, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: lifecycleRuleAttrTypes}, tt.rules)
+			if diags.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", diags)
+			}
+			rulesValue, err := rules.ToTerraformValue(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			objectType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+			if !ok {
+				t.Fatalf("Unexpected schema type")
+			}
+			config := tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+				"bucket": tftypes.NewValue(tftypes.String, "bucket"),
+				"rule":   rulesValue,
+				"id":     tftypes.NewValue(tftypes.String, nil),
+			})}
+
+			resp := &fwresource.ValidateConfigResponse{}
+			r.ValidateConfig(ctx, fwresource.ValidateConfigRequest{Config: config}, resp)
+
+			if tt.wantErr == "" {
+				if resp.Diagnostics.HasError() {
+					t.Errorf("Unexpected diagnostics: %v", resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.ErrorsCount() != 1 {
+				t.Fatalf("Expected 1 error, got %v", resp.Diagnostics)
+			}
+			if diag := resp.Diagnostics.Errors()[0]; !strings.Contains(diag.Summary()+" "+diag.Detail(), tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got %s: %s", tt.wantErr, diag.Summary(), diag.Detail())
+			}
+		})
+	}
+}
+
+func TestLifecycleRuleConversion(t *testing.T) {
+	ctx := context.Background()
+	tmp := testLifecycleRule("expire-tmp", func(m *LifecycleRuleModel) {
+		m.Prefix = types.StringValue("tmp/")
+		m.ObjectSizeGreaterThan = types.Int64Value(1024)
+		m.ExpirationDays = types.Int64Value(7)
+	})
+	abort := testLifecycleRule("abort", func(m *LifecycleRuleModel) {
+		m.Enabled = types.BoolValue(false)
+		m.ExpirationDate = types.StringValue("2030-01-01")
+		m.AbortIncompleteMultipartUploadDays = types.Int64Value(1)
+	})
+
+	// Several filter conditions are combined with And
+	rule := lifecycleRuleFromModel(tmp)
+	if rule.Filter.And == nil || aws.ToString(rule.Filter.And.Prefix) != "tmp/" || rule.Filter.Prefix != nil {
+		t.Errorf("Expected the filter conditions in And, got %+v", rule.Filter)
+	}
+	dated := lifecycleRuleFromModel(abort)
+	if dated.Status != s3types.ExpirationStatusDisabled || !dated.Expiration.Date.Equal(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected rule %+v", dated)
+	}
+
+	// The prior order is kept, rules read from the bucket are converted back
+	ruleType := types.ObjectType{AttrTypes: lifecycleRuleAttrTypes}
+	prior, _ := types.ListValueFrom(ctx, ruleType, []LifecycleRuleModel{tmp, abort})
+	read, diags := lifecycleRulesValue(ctx, []s3types.LifecycleRule{dated, rule}, prior)
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	if !read.Equal(prior) {
+		t.Errorf("Expected the prior rules %s, got %s", prior, read)
+	}
+
+	// Other rules are sorted by ID
+	sorted, _ := lifecycleRulesValue(ctx, []s3types.LifecycleRule{rule, dated}, types.ListNull(ruleType))
+	var models []LifecycleRuleModel
+	_ = sorted.ElementsAs(ctx, &models, false)
+	if len(models) != 2 || models[0].ID.ValueString() != "abort" {
+		t.Errorf("Expected the rules sorted by ID, got %s", sorted)
+	}
+}
+
+// testLifecycleRule returns a rule with only id and enabled set, changed by
+// modify.
+func testLifecycleRule(id string, modify func(*LifecycleRuleModel)) LifecycleRuleModel {
+	model := lifecycleRuleModel(s3types.LifecycleRule{ID: aws.String(id), Status: s3types.ExpirationStatusEnabled})
+	modify(&model)
+	return model
+}
+
+func testAccGarageBucketLifecycleResourceConfig(days int) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-lifecycle"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+  owner = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_bucket_lifecycle" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket = garage_bucket.test.global_alias
+
+  rule = [
+    {
+      id              = "expire-tmp"
+      prefix          = "tmp/"
+      expiration_days = %[2]d
+    },
+    {
+      id                                     = "abort-uploads"
+      abort_incomplete_multipart_upload_days = 1
+    },
+  ]
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"), days)
+}
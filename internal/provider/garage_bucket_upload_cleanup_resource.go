@@ -0,0 +1,304 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+var _ resource.Resource = &GarageBucketUploadCleanupResource{}
+
+// minUploadCleanupAge keeps the cleanup away from uploads that are likely
+// still in progress.
+const minUploadCleanupAge = time.Minute
+
+// GarageBucketUploadCleanupResource aborts the multipart uploads of a bucket
+// initiated before a threshold. It has no remote counterpart: the cleanup
+// runs on every apply and destroying it only removes it from the state.
+type GarageBucketUploadCleanupResource struct {
+	s3Client    *s3.Client
+	s3AccessKey string
+}
+
+type GarageBucketUploadCleanupResourceModel struct {
+	Bucket       types.String `tfsdk:"bucket"`
+	OlderThan    types.String `tfsdk:"older_than"`
+	AbortedCount types.Int64  `tfsdk:"aborted_count"`
+	ID           types.String `tfsdk:"id"`
+}
+
+// pendingUpload identifies an unfinished multipart upload.
+type pendingUpload struct {
+	key       string
+	uploadID  string
+	initiated *time.Time
+}
+
+func NewGarageBucketUploadCleanupResource() resource.Resource {
+	return &GarageBucketUploadCleanupResource{}
+}
+
+func (r *GarageBucketUploadCleanupResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_upload_cleanup"
+}
+
+func (r *GarageBucketUploadCleanupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Aborts the unfinished multipart uploads of a Garage bucket that are older than a threshold, on every apply",
+		Attributes: map[string]schema.Attribute{
+			"bucket": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the bucket to clean up",
+			},
+			"older_than": schema.StringAttribute{
+				Required:    true,
+				Description: "Minimum age of the uploads to abort, as a Go duration (e.g. 24h). Uploads initiated more recently are never aborted. At least 1m",
+				Validators: []validator.String{
+					validators.DurationBetween(minUploadCleanupAge, 100*365*24*time.Hour),
+				},
+			},
+			"aborted_count": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of uploads aborted by the last apply. Unknown in every plan, as the cleanup runs on every apply",
+				PlanModifiers: []planmodifier.Int64{
+					uploadCleanupPlanModifier{},
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the bucket",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *GarageBucketUploadCleanupResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			"Expected *GarageProviderModel",
+		)
+		return
+	}
+
+	r.s3Client = providerData.S3Client
+	r.s3AccessKey = providerData.AccessKey.ValueString()
+}
+
+func (r *GarageBucketUploadCleanupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan GarageBucketUploadCleanupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.cleanup(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *GarageBucketUploadCleanupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Nothing to refresh, the state only records the last cleanup
+	var state GarageBucketUploadCleanupResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *GarageBucketUploadCleanupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan GarageBucketUploadCleanupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.cleanup(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *GarageBucketUploadCleanupResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+	// Aborted uploads cannot be restored, removing from state is enough
+}
+
+// cleanup aborts the stale uploads of the bucket of data and sets
+// AbortedCount and ID.
+func (r *GarageBucketUploadCleanupResource) cleanup(ctx context.Context, data *GarageBucketUploadCleanupResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if r.s3Client == nil {
+		diags.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return diags
+	}
+
+	// Already validated, unless the value was unknown at validation
+	olderThan, err := time.ParseDuration(data.OlderThan.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("older_than"),
+			"Invalid Duration",
+			fmt.Sprintf("Unable to parse older_than %q: %s", data.OlderThan.ValueString(), err),
+		)
+		return diags
+	}
+	bucket := data.Bucket.ValueString()
+	cutoff := time.Now().Add(-olderThan)
+
+	stale, err := listStaleUploads(ctx, r.s3Client, bucket, cutoff)
+	if err != nil {
+		diags.AddError(
+			"Failed to List Multipart Uploads",
+			fmt.Sprintf("Could not list the multipart uploads of bucket %s: %s", bucket,
+				objectErrorDetail(r.s3AccessKey, bucket, false, err)),
+		)
+		return diags
+	}
+
+	// Report every failed upload, not only the first one
+	var aborted int64
+	for _, upload := range stale {
+		if err := abortUpload(ctx, r.s3Client, bucket, upload); err != nil {
+			diags.AddError(
+				"Failed to Abort Multipart Upload",
+				fmt.Sprintf("Could not abort upload %s of %s: %s", upload.uploadID, upload.key,
+					objectErrorDetail(r.s3AccessKey, bucket, true, err)),
+			)
+			continue
+		}
+		aborted++
+	}
+
+	tflog.Debug(ctx, "Cleaned up multipart uploads", map[string]interface{}{
+		"bucket":  bucket,
+		"cutoff":  cutoff.Format(time.RFC3339),
+		"stale":   len(stale),
+		"aborted": aborted,
+	})
+
+	data.AbortedCount = types.Int64Value(aborted)
+	data.ID = data.Bucket
+	return diags
+}
+
+// listStaleUploads lists the multipart uploads of bucket initiated before
+// cutoff. Uploads without an initiation time are left alone.
+func listStaleUploads(ctx context.Context, client *s3.Client, bucket string, cutoff time.Time) ([]pendingUpload, error) {
+	uploads, err := listPendingUploads(ctx, client, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []pendingUpload
+	for _, upload := range uploads {
+		if upload.initiated != nil && upload.initiated.Before(cutoff) {
+			stale = append(stale, upload)
+		}
+	}
+	return stale, nil
+}
+
+// listPendingUploads lists all the multipart uploads of bucket.
+func listPendingUploads(ctx context.Context, client *s3.Client, bucket string) ([]pendingUpload, error) {
+	var uploads []pendingUpload
+	paginator := s3.NewListMultipartUploadsPaginator(client, &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(bucket),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, upload := range page.Uploads {
+			uploads = append(uploads, pendingUpload{
+				key:       aws.ToString(upload.Key),
+				uploadID:  aws.ToString(upload.UploadId),
+				initiated: upload.Initiated,
+			})
+		}
+	}
+
+	return uploads, nil
+}
+
+// abortUpload aborts upload, which counts as done when it was completed or
+// aborted in the meantime.
+func abortUpload(ctx context.Context, client *s3.Client, bucket string, upload pendingUpload) error {
+	_, err := client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(upload.key),
+		UploadId: aws.String(upload.uploadID),
+	})
+	if err == nil {
+		return nil
+	}
+
+	var noSuchUpload *s3types.NoSuchUpload
+	var apiErr smithy.APIError
+	if errors.As(err, &noSuchUpload) || (errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchUpload") {
+		return nil
+	}
+	return err
+}
+
+// uploadCleanupPlanModifier marks aborted_count unknown in every plan so that
+// Terraform calls Update, and the cleanup runs, on every apply.
+type uploadCleanupPlanModifier struct{}
+
+func (m uploadCleanupPlanModifier) Description(_ context.Context) string {
+	return "Runs the cleanup on every apply."
+}
+
+func (m uploadCleanupPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m uploadCleanupPlanModifier) PlanModifyInt64(_ context.Context, req planmodifier.Int64Request, resp *planmodifier.Int64Response) {
+	// Nothing to clean up on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	resp.PlanValue = types.Int64Unknown()
+}
@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGarageBucketUploadCleanupResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageBucketUploadCleanupResourceConfig("24h"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_bucket_upload_cleanup.test", "aborted_count", "0"),
+					resource.TestCheckResourceAttr("garage_bucket_upload_cleanup.test", "id", "test-bucket-upload-cleanup"),
+				),
+				// The cleanup runs on every apply
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccGarageBucketUploadCleanupResourceConfig("1h"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_bucket_upload_cleanup.test", "older_than", "1h"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestGarageBucketUploadCleanup(t *testing.T) {
+	now := time.Now().UTC()
+	uploads := []struct {
+		key       string
+		initiated time.Time
+	}{
+		{key: "old-a", initiated: now.Add(-48 * time.Hour)},
+		{key: "recent", initiated: now.Add(-time.Hour)},
+		{key: "gone", initiated: now.Add(-48 * time.Hour)},
+		{key: "old-b", initiated: now.Add(-25 * time.Hour)},
+		{key: "denied", initiated: now.Add(-48 * time.Hour)},
+		{key: "unknown-age"},
+	}
+
+	var mu sync.Mutex
+	var aborted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		query := r.URL.Query()
+		switch {
+		case r.Method == http.MethodGet && query.Has("uploads"):
+			// Three uploads per page
+			start := 0
+			if query.Get("key-marker") != "" {
+				start = 3
+			}
+			var page strings.Builder
+			for _, upload := range uploads[start : start+3] {
+				initiated := ""
+				if !upload.initiated.IsZero() {
+					initiated = "<Initiated>" + upload.initiated.Format(time.RFC3339) + "</Initiated>"
+				}
+				fmt.Fprintf(&page, `<Upload><Key>%s</Key><UploadId>id-%s</UploadId>%s</Upload>`, upload.key, upload.key, initiated)
+			}
+			if start == 0 {
+				page.WriteString(`<IsTruncated>true</IsTruncated><NextKeyMarker>gone</NextKeyMarker><NextUploadIdMarker>id-gone</NextUploadIdMarker>`)
+			}
+			_, _ = fmt.Fprintf(w, `<ListMultipartUploadsResult><Bucket>bucket</Bucket>%s</ListMultipartUploadsResult>`, page.String())
+		case r.Method == http.MethodDelete && query.Has("uploadId"):
+			key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+			if query.Get("uploadId") != "id-"+key {
+				t.Errorf("Unexpected upload ID %s for %s", query.Get("uploadId"), key)
+			}
+			switch key {
+			case "gone":
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`<Error><Code>NoSuchUpload</Code><Message>Not found</Message></Error>`))
+				return
+			case "denied":
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>Forbidden</Message></Error>`))
+				return
+			}
+			aborted = append(aborted, key)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected %s request to %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	data := GarageBucketUploadCleanupResourceModel{
+		Bucket:    types.StringValue("bucket"),
+		OlderThan: types.StringValue("24h"),
+	}
+	r := &GarageBucketUploadCleanupResource{s3Client: testS3Client(server.URL)}
+	diags := r.cleanup(context.Background(), &data)
+
+	if diags.ErrorsCount() != 1 || !strings.Contains(diags.Errors()[0].Detail(), "denied") {
+		t.Fatalf("Expected an error for the denied upload only, got %v", diags)
+	}
+
+	// Recent uploads and uploads of unknown age are never aborted
+	sort.Strings(aborted)
+	if fmt.Sprint(aborted) != "[old-a old-b]" {
+		t.Errorf("Unexpected aborted uploads %v", aborted)
+	}
+	// An upload already gone counts as aborted
+	if data.AbortedCount.ValueInt64() != 3 {
+		t.Errorf("Expected 3 aborted uploads, got %s", data.AbortedCount)
+	}
+}
+
+func TestGarageBucketUploadCleanup_invalidOlderThan(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected %s request to %s", r.Method, r.URL)
+	}))
+	defer server.Close()
+
+	// Skips the validator, like a value unknown at validation
+	data := GarageBucketUploadCleanupResourceModel{
+		Bucket:    types.StringValue("bucket"),
+		OlderThan: types.StringValue("yesterday"),
+	}
+	r := &GarageBucketUploadCleanupResource{s3Client: testS3Client(server.URL)}
+	diags := r.cleanup(context.Background(), &data)
+
+	if !diags.HasError() || diags.Errors()[0].Summary() != "Invalid Duration" {
+		t.Fatalf("Expected an Invalid Duration error, got %v", diags)
+	}
+}
+
+func testAccGarageBucketUploadCleanupResourceConfig(olderThan string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-upload-cleanup"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_bucket_upload_cleanup" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket     = garage_bucket.test.global_alias
+  older_than = %[2]q
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"), olderThan)
+}
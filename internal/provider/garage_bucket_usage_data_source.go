@@ -0,0 +1,289 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &GarageBucketUsageDataSource{}
+
+const (
+	// defaultMaxObjectsScanned bounds the listings of a read when
+	// max_objects_scanned is not set.
+	defaultMaxObjectsScanned = 100000
+	// bucketUsageParallelism is the number of prefixes listed at once.
+	bucketUsageParallelism = 4
+)
+
+var bucketUsageAttrTypes = map[string]attr.Type{
+	"objects": types.Int64Type,
+	"bytes":   types.Int64Type,
+}
+
+// GarageBucketUsageDataSource sums the size and count of the objects under
+// prefixes of a bucket by listing them, the admin API only reports whole
+// bucket numbers.
+type GarageBucketUsageDataSource struct {
+	s3Client    *s3.Client
+	s3AccessKey string
+}
+
+type GarageBucketUsageDataSourceModel struct {
+	Bucket            types.String `tfsdk:"bucket"`
+	Prefixes          types.List   `tfsdk:"prefixes"`
+	MaxObjectsScanned types.Int64  `tfsdk:"max_objects_scanned"`
+	Usage             types.Map    `tfsdk:"usage"`
+	TotalObjects      types.Int64  `tfsdk:"total_objects"`
+	TotalBytes        types.Int64  `tfsdk:"total_bytes"`
+	Truncated         types.Bool   `tfsdk:"truncated"`
+	ID                types.String `tfsdk:"id"`
+}
+
+// prefixUsage is the result of the listing of one prefix.
+type prefixUsage struct {
+	objects   int64
+	bytes     int64
+	truncated bool
+	err       error
+}
+
+func NewGarageBucketUsageDataSource() datasource.DataSource {
+	return &GarageBucketUsageDataSource{}
+}
+
+func (d *GarageBucketUsageDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_usage"
+}
+
+func (d *GarageBucketUsageDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Sums the number and size of the objects under prefixes of a Garage bucket by listing them",
+		Attributes: map[string]schema.Attribute{
+			"bucket": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the bucket",
+			},
+			"prefixes": schema.ListAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "Key prefixes to sum, e.g. logs/. An empty prefix covers the whole bucket. Objects under overlapping prefixes are counted for each of them",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.UniqueValues(),
+				},
+			},
+			"max_objects_scanned": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of objects listed across all prefixes. The sums are partial and a warning is reported when it is reached. Defaults to 100000",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"usage": schema.MapNestedAttribute{
+				Computed:    true,
+				Description: "Usage per prefix",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"objects": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Number of objects under the prefix",
+						},
+						"bytes": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Total size of the objects under the prefix in bytes",
+						},
+					},
+				},
+			},
+			"total_objects": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Sum of the objects of all prefixes",
+			},
+			"total_bytes": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Sum of the bytes of all prefixes",
+			},
+			"truncated": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether max_objects_scanned was reached, the sums are then partial",
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the bucket",
+			},
+		},
+	}
+}
+
+func (d *GarageBucketUsageDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			"Expected *GarageProviderModel",
+		)
+		return
+	}
+
+	d.s3Client = providerData.S3Client
+	d.s3AccessKey = providerData.AccessKey.ValueString()
+}
+
+func (d *GarageBucketUsageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config GarageBucketUsageDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if d.s3Client == nil {
+		resp.Diagnostics.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return
+	}
+
+	var prefixes []string
+	resp.Diagnostics.Append(config.Prefixes.ElementsAs(ctx, &prefixes, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	maxObjects := int64(defaultMaxObjectsScanned)
+	if !config.MaxObjectsScanned.IsNull() {
+		maxObjects = config.MaxObjectsScanned.ValueInt64()
+	}
+
+	results := d.listPrefixes(ctx, config.Bucket.ValueString(), prefixes, maxObjects)
+
+	// Report every failed prefix, not only the first one
+	usage := make(map[string]attr.Value, len(prefixes))
+	var totalObjects, totalBytes int64
+	truncated := false
+	for i, prefix := range prefixes {
+		result := results[i]
+		if result.err != nil {
+			resp.Diagnostics.AddError(
+				"Failed to List Objects",
+				fmt.Sprintf("Could not list objects under prefix %q: %s", prefix,
+					objectErrorDetail(d.s3AccessKey, config.Bucket.ValueString(), false, result.err)),
+			)
+			continue
+		}
+
+		usage[prefix] = types.ObjectValueMust(bucketUsageAttrTypes, map[string]attr.Value{
+			"objects": types.Int64Value(result.objects),
+			"bytes":   types.Int64Value(result.bytes),
+		})
+		totalObjects += result.objects
+		totalBytes += result.bytes
+		truncated = truncated || result.truncated
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if truncated {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("max_objects_scanned"),
+			"Bucket Usage Truncated",
+			fmt.Sprintf("Listing stopped after %d objects in bucket %s, the usage is partial. Raise max_objects_scanned to list more objects.",
+				maxObjects, config.Bucket.ValueString()),
+		)
+	}
+
+	usageValue, diags := types.MapValue(types.ObjectType{AttrTypes: bucketUsageAttrTypes}, usage)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config.Usage = usageValue
+	config.TotalObjects = types.Int64Value(totalObjects)
+	config.TotalBytes = types.Int64Value(totalBytes)
+	config.Truncated = types.BoolValue(truncated)
+	config.ID = config.Bucket
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+// listPrefixes lists the prefixes with bounded parallelism, stopping once
+// maxObjects objects were listed across all of them. The results are in the
+// order of prefixes.
+func (d *GarageBucketUsageDataSource) listPrefixes(ctx context.Context, bucket string, prefixes []string, maxObjects int64) []prefixUsage {
+	results := make([]prefixUsage, len(prefixes))
+	var scanned atomic.Int64
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, bucketUsageParallelism)
+
+	for i, prefix := range prefixes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			results[i] = d.listPrefix(ctx, bucket, prefix, &scanned, maxObjects)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// listPrefix sums the objects under prefix, counting them in scanned.
+func (d *GarageBucketUsageDataSource) listPrefix(ctx context.Context, bucket, prefix string, scanned *atomic.Int64, maxObjects int64) prefixUsage {
+	var usage prefixUsage
+	paginator := s3.NewListObjectsV2Paginator(d.s3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			usage.err = err
+			return usage
+		}
+
+		for _, object := range page.Contents {
+			if scanned.Add(1) > maxObjects {
+				usage.truncated = true
+				return usage
+			}
+			usage.objects++
+			usage.bytes += aws.ToInt64(object.Size)
+		}
+	}
+
+	tflog.Debug(ctx, "Listed bucket prefix", map[string]interface{}{
+		"bucket":  bucket,
+		"prefix":  prefix,
+		"objects": usage.objects,
+		"bytes":   usage.bytes,
+	})
+
+	return usage
+}
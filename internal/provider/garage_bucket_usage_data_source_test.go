@@ -0,0 +1,171 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGarageBucketUsageDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageBucketUsageDataSourceConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.garage_bucket_usage.test", "usage.logs/.objects", "2"),
+					resource.TestCheckResourceAttr("data.garage_bucket_usage.test", "usage.logs/.bytes", "9"),
+					resource.TestCheckResourceAttr("data.garage_bucket_usage.test", "usage.artifacts/.objects", "1"),
+					resource.TestCheckResourceAttr("data.garage_bucket_usage.test", "usage.artifacts/.bytes", "5"),
+					resource.TestCheckResourceAttr("data.garage_bucket_usage.test", "total_objects", "3"),
+					resource.TestCheckResourceAttr("data.garage_bucket_usage.test", "total_bytes", "14"),
+					resource.TestCheckResourceAttr("data.garage_bucket_usage.test", "truncated", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestGarageBucketUsageDataSourceRead(t *testing.T) {
+	// Two pages under logs/, one object under artifacts/
+	pages := map[string][]string{
+		"logs/":      {`<Contents><Key>logs/a</Key><Size>10</Size></Contents><Contents><Key>logs/b</Key><Size>20</Size></Contents>`, `<Contents><Key>logs/c</Key><Size>30</Size></Contents>`},
+		"artifacts/": {`<Contents><Key>artifacts/a</Key><Size>5</Size></Contents>`},
+	}
+
+	tests := []struct {
+		name          string
+		prefixes      []string
+		maxObjects    tftypes.Value
+		wantObjects   int64
+		wantBytes     int64
+		wantTruncated bool
+		wantErrors    int
+	}{
+		{name: "all prefixes", prefixes: []string{"logs/", "artifacts/"}, maxObjects: tftypes.NewValue(tftypes.Number, nil), wantObjects: 4, wantBytes: 65},
+		{name: "truncated", prefixes: []string{"logs/"}, maxObjects: tftypes.NewValue(tftypes.Number, 2), wantObjects: 2, wantBytes: 30, wantTruncated: true},
+		{name: "failing prefixes", prefixes: []string{"logs/", "denied/", "forbidden/"}, maxObjects: tftypes.NewValue(tftypes.Number, nil), wantErrors: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query := r.URL.Query()
+				prefixPages, ok := pages[query.Get("prefix")]
+				if !ok {
+					w.WriteHeader(http.StatusForbidden)
+					_, _ = w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>Forbidden</Message></Error>`))
+					return
+				}
+
+				page := 0
+				if query.Get("continuation-token") != "" {
+					_, _ = fmt.Sscanf(query.Get("continuation-token"), "page-%d", &page)
+				}
+				next := ""
+				if page+1 < len(prefixPages) {
+					next = fmt.Sprintf(`<IsTruncated>true</IsTruncated><NextContinuationToken>page-%d</NextContinuationToken>`, page+1)
+				}
+				_, _ = fmt.Fprintf(w, `<ListBucketResult><Name>bucket</Name>%s%s</ListBucketResult>`, prefixPages[page], next)
+			}))
+			defer server.Close()
+
+			prefixValues := make([]tftypes.Value, 0, len(tt.prefixes))
+			for _, prefix := range tt.prefixes {
+				prefixValues = append(prefixValues, tftypes.NewValue(tftypes.String, prefix))
+			}
+
+			d := &GarageBucketUsageDataSource{s3Client: testS3Client(server.URL)}
+			resp := testDataSourceRead(t, d, map[string]tftypes.Value{
+				"bucket":              tftypes.NewValue(tftypes.String, "bucket"),
+				"prefixes":            tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, prefixValues),
+				"max_objects_scanned": tt.maxObjects,
+			})
+
+			if tt.wantErrors > 0 {
+				if resp.Diagnostics.ErrorsCount() != tt.wantErrors {
+					t.Fatalf("Expected %d errors, got %v", tt.wantErrors, resp.Diagnostics)
+				}
+				for _, diag := range resp.Diagnostics.Errors() {
+					if !strings.Contains(diag.Detail(), "denied/") && !strings.Contains(diag.Detail(), "forbidden/") {
+						t.Errorf("Expected the error to name the failing prefix, got %s", diag.Detail())
+					}
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if (resp.Diagnostics.WarningsCount() == 1) != tt.wantTruncated {
+				t.Errorf("Expected a truncation warning %v, got %v", tt.wantTruncated, resp.Diagnostics)
+			}
+
+			var state GarageBucketUsageDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			if state.TotalObjects.ValueInt64() != tt.wantObjects || state.TotalBytes.ValueInt64() != tt.wantBytes {
+				t.Errorf("Expected %d objects and %d bytes, got %s and %s", tt.wantObjects, tt.wantBytes, state.TotalObjects, state.TotalBytes)
+			}
+			if state.Truncated.ValueBool() != tt.wantTruncated {
+				t.Errorf("Expected truncated %v, got %s", tt.wantTruncated, state.Truncated)
+			}
+			if len(state.Usage.Elements()) != len(tt.prefixes) {
+				t.Errorf("Expected usage for %d prefixes, got %s", len(tt.prefixes), state.Usage)
+			}
+			if logs, ok := state.Usage.Elements()["logs/"].(types.Object); ok && !tt.wantTruncated {
+				if logs.Attributes()["bytes"].String() != "60" {
+					t.Errorf("Expected 60 bytes under logs/, got %s", logs)
+				}
+			}
+		})
+	}
+}
+
+func testAccGarageBucketUsageDataSourceConfig() string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-usage"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_object" "test" {
+  for_each = {
+    "logs/a.log"      = "four"
+    "logs/b.log"      = "five!"
+    "artifacts/a.bin" = "hello"
+  }
+  depends_on = [garage_bucket_permission.test]
+
+  bucket  = garage_bucket.test.global_alias
+  key     = each.key
+  content = each.value
+}
+
+data "garage_bucket_usage" "test" {
+  depends_on = [garage_object.test]
+
+  bucket   = garage_bucket.test.global_alias
+  prefixes = ["logs/", "artifacts/"]
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"))
+}
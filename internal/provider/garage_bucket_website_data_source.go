@@ -0,0 +1,141 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &GarageBucketWebsiteDataSource{}
+
+// GarageBucketWebsiteDataSource reads the website configuration of a bucket
+// through the S3 API, for setups with S3 credentials but no admin token.
+type GarageBucketWebsiteDataSource struct {
+	s3Client    *s3.Client
+	s3AccessKey string
+}
+
+type GarageBucketWebsiteDataSourceModel struct {
+	Bucket        types.String `tfsdk:"bucket"`
+	Enabled       types.Bool   `tfsdk:"enabled"`
+	IndexDocument types.String `tfsdk:"index_document"`
+	ErrorDocument types.String `tfsdk:"error_document"`
+	ID            types.String `tfsdk:"id"`
+}
+
+func NewGarageBucketWebsiteDataSource() datasource.DataSource {
+	return &GarageBucketWebsiteDataSource{}
+}
+
+func (d *GarageBucketWebsiteDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_website"
+}
+
+func (d *GarageBucketWebsiteDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads the website configuration of a Garage bucket with GetBucketWebsite, using the S3 credentials of the provider instead of the admin API",
+		Attributes: map[string]schema.Attribute{
+			"bucket": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the bucket",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether website hosting is enabled on the bucket",
+			},
+			"index_document": schema.StringAttribute{
+				Computed:    true,
+				Description: "Suffix of the object served for directory requests, e.g. index.html. Null when website hosting is disabled",
+			},
+			"error_document": schema.StringAttribute{
+				Computed:    true,
+				Description: "Key of the object served on errors. Null when none is set or website hosting is disabled",
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the bucket",
+			},
+		},
+	}
+}
+
+func (d *GarageBucketWebsiteDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			"Expected *GarageProviderModel",
+		)
+		return
+	}
+
+	d.s3Client = providerData.S3Client
+	d.s3AccessKey = providerData.AccessKey.ValueString()
+}
+
+func (d *GarageBucketWebsiteDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config GarageBucketWebsiteDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if d.s3Client == nil {
+		resp.Diagnostics.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return
+	}
+
+	bucket := config.Bucket.ValueString()
+	tflog.Debug(ctx, "Reading bucket website configuration", map[string]interface{}{
+		"bucket": bucket,
+	})
+
+	output, err := d.s3Client.GetBucketWebsite(ctx, &s3.GetBucketWebsiteInput{
+		Bucket: aws.String(bucket),
+	})
+	config.Enabled = types.BoolValue(true)
+	config.IndexDocument = types.StringNull()
+	config.ErrorDocument = types.StringNull()
+	switch {
+	case isNoSuchWebsiteConfiguration(err):
+		// Website hosting is disabled, a missing bucket is still an error
+		config.Enabled = types.BoolValue(false)
+	case err != nil:
+		resp.Diagnostics.AddError(
+			"Failed to Read Website Configuration",
+			fmt.Sprintf("Could not read the website configuration of bucket %s: %s", bucket, objectErrorDetail(d.s3AccessKey, bucket, false, err)),
+		)
+		return
+	default:
+		if output.IndexDocument != nil {
+			config.IndexDocument = types.StringPointerValue(output.IndexDocument.Suffix)
+		}
+		if output.ErrorDocument != nil {
+			config.ErrorDocument = types.StringPointerValue(output.ErrorDocument.Key)
+		}
+	}
+	config.ID = config.Bucket
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGarageBucketWebsiteDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageBucketWebsiteDataSourceConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.garage_bucket_website.test", "enabled", "true"),
+					resource.TestCheckResourceAttr("data.garage_bucket_website.test", "index_document", "index.html"),
+					resource.TestCheckResourceAttr("data.garage_bucket_website.test", "error_document", "404.html"),
+				),
+			},
+		},
+	})
+}
+
+func TestGarageBucketWebsiteDataSourceRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["website"]; !ok {
+			t.Errorf("Expected a GetBucketWebsite request, got %s", r.URL)
+		}
+
+		switch strings.Trim(r.URL.Path, "/") {
+		case "site":
+			_, _ = w.Write([]byte(`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument>` +
+				`<ErrorDocument><Key>404.html</Key></ErrorDocument></WebsiteConfiguration>`))
+		case "index-only":
+			_, _ = w.Write([]byte(`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument></WebsiteConfiguration>`))
+		case "private":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<Error><Code>NoSuchWebsiteConfiguration</Code><Message>The specified bucket does not have a website configuration</Message></Error>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<Error><Code>NoSuchBucket</Code><Message>Bucket not found</Message></Error>`))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		bucket        string
+		enabled       bool
+		indexDocument string
+		errorDocument string
+		wantError     bool
+	}{
+		{bucket: "site", enabled: true, indexDocument: "index.html", errorDocument: "404.html"},
+		{bucket: "index-only", enabled: true, indexDocument: "index.html"},
+		{bucket: "private", enabled: false},
+		// A missing bucket is not a bucket without website
+		{bucket: "missing", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.bucket, func(t *testing.T) {
+			d := &GarageBucketWebsiteDataSource{s3Client: testS3Client(server.URL)}
+			resp := testDataSourceRead(t, d, map[string]tftypes.Value{
+				"bucket": tftypes.NewValue(tftypes.String, tt.bucket),
+			})
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Fatalf("Expected error %t, got %v", tt.wantError, resp.Diagnostics)
+			}
+			if tt.wantError {
+				return
+			}
+
+			var state GarageBucketWebsiteDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			if state.Enabled.ValueBool() != tt.enabled || state.ID.ValueString() != tt.bucket {
+				t.Errorf("Expected enabled %t for %s, got %s and id %s", tt.enabled, tt.bucket, state.Enabled, state.ID)
+			}
+			if state.IndexDocument.ValueString() != tt.indexDocument || state.ErrorDocument.ValueString() != tt.errorDocument {
+				t.Errorf("Expected documents %q and %q, got %s and %s", tt.indexDocument, tt.errorDocument, state.IndexDocument, state.ErrorDocument)
+			}
+			if !tt.enabled && (!state.IndexDocument.IsNull() || !state.ErrorDocument.IsNull()) {
+				t.Errorf("Expected null documents without website, got %s and %s", state.IndexDocument, state.ErrorDocument)
+			}
+		})
+	}
+}
+
+func testAccGarageBucketWebsiteDataSourceConfig() string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-website-ds"
+
+  # The website is managed by garage_bucket_website
+  lifecycle {
+    ignore_changes = [website_enabled, website_index_document, website_error_document]
+  }
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  owner = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_bucket_website" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket         = garage_bucket.test.global_alias
+  index_document = "index.html"
+  error_document = "404.html"
+}
+
+data "garage_bucket_website" "test" {
+  bucket = garage_bucket_website.test.bucket
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"))
+}
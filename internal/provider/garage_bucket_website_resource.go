@@ -0,0 +1,269 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = &GarageBucketWebsiteResource{}
+var _ resource.ResourceWithImportState = &GarageBucketWebsiteResource{}
+
+// GarageBucketWebsiteResource manages the website configuration of a bucket
+// through the S3 API, for buckets not managed by garage_bucket.
+type GarageBucketWebsiteResource struct {
+	s3Client *s3.Client
+}
+
+type GarageBucketWebsiteResourceModel struct {
+	Bucket        types.String `tfsdk:"bucket"`
+	IndexDocument types.String `tfsdk:"index_document"`
+	ErrorDocument types.String `tfsdk:"error_document"`
+	ID            types.String `tfsdk:"id"`
+}
+
+func NewGarageBucketWebsiteResource() resource.Resource {
+	return &GarageBucketWebsiteResource{}
+}
+
+func (r *GarageBucketWebsiteResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_website"
+}
+
+func (r *GarageBucketWebsiteResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the website configuration of a Garage bucket with S3 credentials only. Do not set the website attributes of garage_bucket for the same bucket",
+		Attributes: map[string]schema.Attribute{
+			"bucket": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the bucket",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"index_document": schema.StringAttribute{
+				Required:    true,
+				Description: "Document served for requests to a directory, e.g. index.html",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"error_document": schema.StringAttribute{
+				Optional:    true,
+				Description: "Document served when an error occurs, e.g. error.html",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the bucket",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *GarageBucketWebsiteResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			"Expected *GarageProviderModel",
+		)
+		return
+	}
+
+	r.s3Client = providerData.S3Client
+}
+
+func (r *GarageBucketWebsiteResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan GarageBucketWebsiteResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.put(ctx, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = plan.Bucket
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *GarageBucketWebsiteResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state GarageBucketWebsiteResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.s3Client == nil {
+		resp.Diagnostics.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return
+	}
+
+	output, err := r.s3Client.GetBucketWebsite(ctx, &s3.GetBucketWebsiteInput{
+		Bucket: aws.String(state.Bucket.ValueString()),
+	})
+	if isWebsiteNotFound(err) {
+		// Disabled out-of-band, e.g. with garage_bucket or the CLI
+		tflog.Warn(ctx, "Website configuration not found, removing from state", map[string]interface{}{
+			"bucket": state.Bucket.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Read Website Configuration",
+			fmt.Sprintf("Could not read the website configuration of bucket %s: %s", state.Bucket.ValueString(), err),
+		)
+		return
+	}
+
+	state.IndexDocument = types.StringNull()
+	if output.IndexDocument != nil {
+		state.IndexDocument = types.StringPointerValue(output.IndexDocument.Suffix)
+	}
+	state.ErrorDocument = types.StringNull()
+	if output.ErrorDocument != nil {
+		state.ErrorDocument = types.StringPointerValue(output.ErrorDocument.Key)
+	}
+	state.ID = state.Bucket
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *GarageBucketWebsiteResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan GarageBucketWebsiteResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.put(ctx, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = plan.Bucket
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *GarageBucketWebsiteResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state GarageBucketWebsiteResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.s3Client == nil {
+		resp.Diagnostics.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return
+	}
+
+	_, err := r.s3Client.DeleteBucketWebsite(ctx, &s3.DeleteBucketWebsiteInput{
+		Bucket: aws.String(state.Bucket.ValueString()),
+	})
+	if isWebsiteNotFound(err) {
+		// Removed out-of-band, possibly with its bucket
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete Website Configuration",
+			fmt.Sprintf("Could not delete the website configuration of bucket %s: %s", state.Bucket.ValueString(), err),
+		)
+	}
+}
+
+func (r *GarageBucketWebsiteResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("bucket"), req, resp)
+}
+
+// put enables website hosting on the bucket with the documents of plan.
+func (r *GarageBucketWebsiteResource) put(ctx context.Context, plan GarageBucketWebsiteResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if r.s3Client == nil {
+		diags.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return diags
+	}
+
+	config := &s3types.WebsiteConfiguration{
+		IndexDocument: &s3types.IndexDocument{Suffix: aws.String(plan.IndexDocument.ValueString())},
+	}
+	if !plan.ErrorDocument.IsNull() {
+		config.ErrorDocument = &s3types.ErrorDocument{Key: aws.String(plan.ErrorDocument.ValueString())}
+	}
+
+	_, err := r.s3Client.PutBucketWebsite(ctx, &s3.PutBucketWebsiteInput{
+		Bucket:               aws.String(plan.Bucket.ValueString()),
+		WebsiteConfiguration: config,
+	})
+	if err != nil {
+		diags.AddError(
+			"Failed to Set Website Configuration",
+			fmt.Sprintf("Could not set the website configuration of bucket %s: %s", plan.Bucket.ValueString(), err),
+		)
+		return diags
+	}
+
+	tflog.Debug(ctx, "Set bucket website configuration", map[string]interface{}{
+		"bucket": plan.Bucket.ValueString(),
+	})
+
+	return diags
+}
+
+// isWebsiteNotFound reports whether err means website hosting is disabled
+// on the bucket, or the bucket no longer exists.
+func isWebsiteNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	return isObjectNotFound(err) || isNoSuchWebsiteConfiguration(err)
+}
+
+// isNoSuchWebsiteConfiguration reports whether err means website hosting is
+// disabled on the bucket.
+func isNoSuchWebsiteConfiguration(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchWebsiteConfiguration"
+}
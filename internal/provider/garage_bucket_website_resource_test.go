@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGarageBucketWebsiteResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageBucketWebsiteResourceConfig(`error_document = "error.html"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_bucket_website.test", "index_document", "index.html"),
+					resource.TestCheckResourceAttr("garage_bucket_website.test", "error_document", "error.html"),
+					resource.TestCheckResourceAttr("garage_bucket_website.test", "id", "test-bucket-website"),
+				),
+			},
+			{
+				ResourceName:      "garage_bucket_website.test",
+				ImportState:       true,
+				ImportStateId:     "test-bucket-website",
+				ImportStateVerify: true,
+			},
+			// Changes made outside of Terraform are detected
+			{
+				PreConfig: func() {
+					_, err := testAccS3Client().PutBucketWebsite(context.Background(), &s3.PutBucketWebsiteInput{
+						Bucket: aws.String("test-bucket-website"),
+						WebsiteConfiguration: &s3types.WebsiteConfiguration{
+							IndexDocument: &s3types.IndexDocument{Suffix: aws.String("home.html")},
+						},
+					})
+					if err != nil {
+						t.Fatalf("Unable to change the website configuration: %s", err)
+					}
+				},
+				Config:             testAccGarageBucketWebsiteResourceConfig(`error_document = "error.html"`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccGarageBucketWebsiteResourceConfig(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_bucket_website.test", "index_document", "index.html"),
+					resource.TestCheckNoResourceAttr("garage_bucket_website.test", "error_document"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccGarageBucketWebsiteResourceConfig(extra string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-website"
+
+  # The website is managed by garage_bucket_website
+  lifecycle {
+    ignore_changes = [website_enabled, website_index_document, website_error_document]
+  }
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  owner = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_bucket_website" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket         = garage_bucket.test.global_alias
+  index_document = "index.html"
+  %[2]s
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"), extra)
+}
@@ -4,27 +4,64 @@
 package provider
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/boolvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ datasource.DataSource = &GarageObjectDataSource{}
 
+// Values of checksum_source.
+const (
+	checksumSourceServer   = "server"
+	checksumSourceComputed = "computed"
+)
+
+// filePermissionPattern matches the octal modes accepted by file_permission.
+var filePermissionPattern = regexp.MustCompile(`^0?[0-7]{3}$`)
+
+// defaultMaxBodySize is the largest object the data source downloads when
+// max_body_size is not set: the body is held in memory and in the state.
+const defaultMaxBodySize = 16 << 20
+
 type GarageObjectDataSource struct {
-	s3Client *s3.Client
+	s3Client    *s3.Client
+	s3Endpoint  string
+	s3AccessKey string
 }
 
 type GarageObjectDataSourceModel struct {
 	Bucket        types.String `tfsdk:"bucket"`
 	Key           types.String `tfsdk:"key"`
+	MaxBodySize   types.Int64  `tfsdk:"max_body_size"`
+	AllowMissing  types.Bool   `tfsdk:"allow_missing"`
+	Exists        types.Bool   `tfsdk:"exists"`
 	Body          types.String `tfsdk:"body"`
+	BodyBase64    types.String `tfsdk:"body_base64"`
 	ContentType   types.String `tfsdk:"content_type"`
 	ContentLength types.Int64  `tfsdk:"content_length"`
 	ETag          types.String `tfsdk:"etag"`
@@ -32,6 +69,27 @@ type GarageObjectDataSourceModel struct {
 	Metadata      types.Map    `tfsdk:"metadata"`
 	VersionId     types.String `tfsdk:"version_id"`
 	ID            types.String `tfsdk:"id"`
+
+	ChecksumSHA256 types.String `tfsdk:"checksum_sha256"`
+	ChecksumCRC32  types.String `tfsdk:"checksum_crc32"`
+	ChecksumSource types.String `tfsdk:"checksum_source"`
+
+	ContentEncoding    types.String `tfsdk:"content_encoding"`
+	ContentDisposition types.String `tfsdk:"content_disposition"`
+	ContentLanguage    types.String `tfsdk:"content_language"`
+
+	S3URI types.String `tfsdk:"s3_uri"`
+	URL   types.String `tfsdk:"url"`
+
+	OutputPath     types.String `tfsdk:"output_path"`
+	FilePermission types.String `tfsdk:"file_permission"`
+	Overwrite      types.Bool   `tfsdk:"overwrite"`
+	OutputSHA256   types.String `tfsdk:"output_sha256"`
+
+	Decompress           types.Bool  `tfsdk:"decompress"`
+	ContentLengthEncoded types.Int64 `tfsdk:"content_length_encoded"`
+
+	Override *S3OverrideModel `tfsdk:"override"`
 }
 
 func NewGarageObjectDataSource() datasource.DataSource {
@@ -54,26 +112,112 @@ func (d *GarageObjectDataSource) Schema(_ context.Context, _ datasource.SchemaRe
 				Required:    true,
 				Description: "Key (name) of the object in the bucket",
 			},
+			"max_body_size": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Largest object size in bytes that will be downloaded, larger objects fail the read. Defaults to 16 MiB, 0 disables the limit. Use the garage_object_head data source to read the metadata of large objects",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"output_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "Local file the object content is written to instead of body and body_base64, which are then null. Parent directories are created. The content is streamed, max_body_size does not apply",
+			},
+			"file_permission": schema.StringAttribute{
+				Optional:    true,
+				Description: "Permissions of the output_path file, in octal. Defaults to 0644",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(filePermissionPattern, "must be an octal file mode such as 0644"),
+					stringvalidator.AlsoRequires(path.MatchRoot("output_path")),
+				},
+			},
+			"overwrite": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Replace output_path when it exists with different content. Defaults to false, reads then fail unless the file already has the object content",
+				Validators: []validator.Bool{
+					boolvalidator.AlsoRequires(path.MatchRoot("output_path")),
+				},
+			},
+			"output_sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "Hex-encoded SHA-256 checksum of the output_path file",
+			},
+			"decompress": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Decompress the body of objects stored with Content-Encoding gzip. content_length is then the decompressed size and the checksums remain those of the stored object. Cannot be used with output_path. Defaults to false",
+				Validators: []validator.Bool{
+					boolvalidator.ConflictsWith(path.MatchRoot("output_path")),
+				},
+			},
+			"allow_missing": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Set exists to false instead of failing when the object does not exist. Defaults to false",
+			},
+			"override": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "S3 credentials and endpoint to use for this read instead of the provider-level ones",
+				Attributes: map[string]schema.Attribute{
+					"access_key": schema.StringAttribute{
+						Required:    true,
+						Sensitive:   true,
+						Description: "S3 access key",
+					},
+					"secret_key": schema.StringAttribute{
+						Required:    true,
+						Sensitive:   true,
+						Description: "S3 secret key",
+					},
+					"endpoint": schema.StringAttribute{
+						Optional:    true,
+						Description: "S3 API endpoint. Defaults to the provider endpoints.s3",
+					},
+				},
+			},
+			"exists": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the object exists. Only false when allow_missing is set, the other attributes are then null",
+			},
 			"body": schema.StringAttribute{
 				Computed:    true,
 				Sensitive:   true,
-				Description: "Object content as a string (use for text files)",
+				Description: "Object content as a string, null when the content is not valid UTF-8 (use body_base64 for binary objects)",
+			},
+			"body_base64": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Object content encoded in base64. The whole object is downloaded and kept in memory and in the state, a third larger than the object itself, so avoid large objects",
 			},
 			"content_type": schema.StringAttribute{
 				Computed:    true,
 				Description: "MIME type of the object",
 			},
+			"content_encoding": schema.StringAttribute{
+				Computed:    true,
+				Description: "Content-Encoding header of the object",
+			},
+			"content_disposition": schema.StringAttribute{
+				Computed:    true,
+				Description: "Content-Disposition header of the object",
+			},
+			"content_language": schema.StringAttribute{
+				Computed:    true,
+				Description: "Content-Language header of the object",
+			},
 			"content_length": schema.Int64Attribute{
 				Computed:    true,
 				Description: "Size of the object in bytes",
 			},
+			"content_length_encoded": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Size of the object as stored in bytes, when decompress decompressed it",
+			},
 			"etag": schema.StringAttribute{
 				Computed:    true,
 				Description: "ETag of the object",
 			},
 			"last_modified": schema.StringAttribute{
 				Computed:    true,
-				Description: "Last modification time of the object",
+				Description: "Last modification time of the object, in RFC 3339 format",
 			},
 			"metadata": schema.MapAttribute{
 				Computed:    true,
@@ -84,6 +228,26 @@ func (d *GarageObjectDataSource) Schema(_ context.Context, _ datasource.SchemaRe
 				Computed:    true,
 				Description: "Version ID of the object (if versioning is enabled)",
 			},
+			"checksum_sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "Hex-encoded SHA-256 checksum of the object content",
+			},
+			"checksum_crc32": schema.StringAttribute{
+				Computed:    true,
+				Description: "Hex-encoded CRC32 checksum of the object content",
+			},
+			"checksum_source": schema.StringAttribute{
+				Computed:    true,
+				Description: "Where the checksums come from: server when Garage reports them, computed when they were computed from the downloaded body",
+			},
+			"s3_uri": schema.StringAttribute{
+				Computed:    true,
+				Description: "S3 URI of the object (s3://bucket/key)",
+			},
+			"url": schema.StringAttribute{
+				Computed:    true,
+				Description: "HTTP URL of the object on the configured S3 endpoint, using path-style addressing, with the key percent-encoded",
+			},
 			"id": schema.StringAttribute{
 				Computed:    true,
 				Description: "Unique identifier (bucket/key)",
@@ -106,26 +270,10 @@ func (d *GarageObjectDataSource) Configure(_ context.Context, req datasource.Con
 		return
 	}
 
-	s3Endpoint := providerData.Endpoints.S3.ValueString()
-	if s3Endpoint == "" {
-		resp.Diagnostics.AddError(
-			"Missing S3 Endpoint",
-			"S3 endpoint must be configured in endpoints.s3 for object operations",
-		)
-		return
-	}
-
-	d.s3Client = s3.NewFromConfig(aws.Config{
-		Region: "garage",
-		Credentials: credentials.NewStaticCredentialsProvider(
-			providerData.AccessKey.ValueString(),
-			providerData.SecretKey.ValueString(),
-			"",
-		),
-	}, func(o *s3.Options) {
-		o.BaseEndpoint = aws.String(s3Endpoint)
-		o.UsePathStyle = true
-	})
+	// A missing client is only an error for reads without an override
+	d.s3Client = providerData.S3Client
+	d.s3Endpoint = providerData.Endpoints.S3.ValueString()
+	d.s3AccessKey = providerData.AccessKey.ValueString()
 }
 
 func (d *GarageObjectDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -136,15 +284,68 @@ func (d *GarageObjectDataSource) Read(ctx context.Context, req datasource.ReadRe
 		return
 	}
 
+	s3Client, s3Endpoint := overrideS3Client(config.Override, d.s3Client, d.s3Endpoint)
+	if s3Client == nil {
+		resp.Diagnostics.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 or override.endpoint for object operations",
+		)
+		return
+	}
+
+	maxBodySize := int64(defaultMaxBodySize)
+	if !config.MaxBodySize.IsNull() {
+		maxBodySize = config.MaxBodySize.ValueInt64()
+	}
+	// Streamed content is not held in memory
+	if !config.OutputPath.IsNull() {
+		maxBodySize = 0
+	}
+
+	// The body is read into memory, check its size before downloading it
+	if maxBodySize > 0 {
+		headOutput, err := s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(config.Bucket.ValueString()),
+			Key:    aws.String(config.Key.ValueString()),
+		})
+		if isObjectNotFound(err) {
+			d.missingObject(ctx, config, resp)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Failed to Retrieve Object",
+				"Could not read object metadata from Garage: "+objectErrorDetail(overrideAccessKey(config.Override, d.s3AccessKey), config.Bucket.ValueString(), false, err),
+			)
+			return
+		}
+		if size := aws.ToInt64(headOutput.ContentLength); size > maxBodySize {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_body_size"),
+				"Object Too Large",
+				fmt.Sprintf("Object %s in bucket %s is %d bytes, more than the max_body_size of %d bytes. "+
+					"Use the garage_object_head data source to read its metadata without downloading it, "+
+					"or raise max_body_size (0 disables the limit).",
+					config.Key.ValueString(), config.Bucket.ValueString(), size, maxBodySize),
+			)
+			return
+		}
+	}
+
 	// Download object from Garage
-	getOutput, err := d.s3Client.GetObject(ctx, &s3.GetObjectInput{
-		Bucket: aws.String(config.Bucket.ValueString()),
-		Key:    aws.String(config.Key.ValueString()),
+	getOutput, err := s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket:       aws.String(config.Bucket.ValueString()),
+		Key:          aws.String(config.Key.ValueString()),
+		ChecksumMode: s3types.ChecksumModeEnabled,
 	})
+	if isObjectNotFound(err) {
+		d.missingObject(ctx, config, resp)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to Retrieve Object",
-			"Could not download object from Garage: "+err.Error(),
+			"Could not download object from Garage: "+objectErrorDetail(overrideAccessKey(config.Override, d.s3AccessKey), config.Bucket.ValueString(), false, err),
 		)
 		return
 	}
@@ -152,56 +353,297 @@ func (d *GarageObjectDataSource) Read(ctx context.Context, req datasource.ReadRe
 		_ = Body.Close()
 	}(getOutput.Body)
 
-	// Read object body
-	bodyBytes, err := io.ReadAll(getOutput.Body)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to Read Object Body",
-			"Could not read object content: "+err.Error(),
-		)
-		return
-	}
+	var checksums objectChecksums
+	var size int64
+	if !config.OutputPath.IsNull() {
+		perm := os.FileMode(0o644)
+		if !config.FilePermission.IsNull() {
+			// The validator guarantees an octal mode
+			mode, _ := strconv.ParseUint(config.FilePermission.ValueString(), 8, 32)
+			perm = os.FileMode(mode)
+		}
 
-	// Set computed attributes
-	config.Body = types.StringValue(string(bodyBytes))
-	config.ID = types.StringValue(config.Bucket.ValueString() + "/" + config.Key.ValueString())
+		checksums, size, err = writeObjectFile(getOutput.Body, config.OutputPath.ValueString(), perm, config.Overwrite.ValueBool())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("output_path"),
+				"Failed to Write Object File",
+				fmt.Sprintf("Could not write object %s to %s: %s", config.Key.ValueString(), config.OutputPath.ValueString(), err),
+			)
+			return
+		}
 
-	if getOutput.ContentType != nil {
-		config.ContentType = types.StringValue(*getOutput.ContentType)
+		config.Body = types.StringNull()
+		config.BodyBase64 = types.StringNull()
+		config.OutputSHA256 = types.StringValue(hex.EncodeToString(checksums.sha256))
 	} else {
-		config.ContentType = types.StringValue("application/octet-stream")
+		// Read object body, the object may have been replaced since the
+		// size check
+		body := io.Reader(getOutput.Body)
+		if maxBodySize > 0 {
+			body = io.LimitReader(getOutput.Body, maxBodySize+1)
+		}
+		bodyBytes, err := io.ReadAll(body)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Failed to Read Object Body",
+				"Could not read object content: "+err.Error(),
+			)
+			return
+		}
+		if maxBodySize > 0 && int64(len(bodyBytes)) > maxBodySize {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_body_size"),
+				"Object Too Large",
+				fmt.Sprintf("Object %s in bucket %s grew past the max_body_size of %d bytes while it was downloaded.",
+					config.Key.ValueString(), config.Bucket.ValueString(), maxBodySize),
+			)
+			return
+		}
+
+		checksums, err = hashBody(bytes.NewReader(bodyBytes))
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Failed to Compute Checksums",
+				"Could not compute object checksums: "+err.Error(),
+			)
+			return
+		}
+		size = int64(len(bodyBytes))
+
+		// The checksums above are those of the stored object
+		if config.Decompress.ValueBool() && isGzipEncoding(getOutput.ContentEncoding) {
+			decompressed, err := gunzipBody(bodyBytes, maxBodySize)
+			if errors.Is(err, errBodyTooLarge) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("max_body_size"),
+					"Object Too Large",
+					fmt.Sprintf("Object %s in bucket %s is more than the max_body_size of %d bytes once decompressed.",
+						config.Key.ValueString(), config.Bucket.ValueString(), maxBodySize),
+				)
+				return
+			}
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Invalid Gzip Content",
+					fmt.Sprintf("Object %s in bucket %s is stored with Content-Encoding gzip but could not be decompressed: %s. "+
+						"Unset decompress to read the stored bytes.",
+						config.Key.ValueString(), config.Bucket.ValueString(), err),
+				)
+				return
+			}
+
+			config.ContentLengthEncoded = types.Int64Value(size)
+			bodyBytes = decompressed
+			size = int64(len(decompressed))
+		}
+
+		config.Body, config.BodyBase64 = objectBodyValues(bodyBytes)
 	}
 
-	if getOutput.ContentLength != nil {
+	config.Exists = types.BoolValue(true)
+
+	// Set computed attributes
+	config.ID = types.StringValue(objectID(config.Bucket.ValueString(), config.Key.ValueString()))
+	config.S3URI = types.StringValue(objectS3URI(config.Bucket.ValueString(), config.Key.ValueString()))
+	config.URL = objectURL(s3Endpoint, config.Bucket.ValueString(), config.Key.ValueString())
+
+	config.ContentType = objectContentTypeValue(getOutput.ContentType)
+
+	config.ContentEncoding = types.StringPointerValue(getOutput.ContentEncoding)
+	config.ContentDisposition = types.StringPointerValue(getOutput.ContentDisposition)
+	config.ContentLanguage = types.StringPointerValue(getOutput.ContentLanguage)
+
+	// Decompressed bodies are larger than the stored object
+	if getOutput.ContentLength != nil && config.ContentLengthEncoded.IsNull() {
 		config.ContentLength = types.Int64Value(*getOutput.ContentLength)
 	} else {
-		config.ContentLength = types.Int64Value(int64(len(bodyBytes)))
+		config.ContentLength = types.Int64Value(size)
 	}
 
 	if getOutput.ETag != nil {
 		config.ETag = types.StringValue(*getOutput.ETag)
 	}
 
-	if getOutput.LastModified != nil {
-		config.LastModified = types.StringValue(getOutput.LastModified.String())
-	}
+	config.LastModified = lastModifiedValue(getOutput.LastModified)
 
 	if getOutput.VersionId != nil {
 		config.VersionId = types.StringValue(*getOutput.VersionId)
 	}
 
-	// Convert metadata map
-	if len(getOutput.Metadata) > 0 {
-		metadataMap := make(map[string]types.String)
-		for k, v := range getOutput.Metadata {
-			metadataMap[k] = types.StringValue(v)
-		}
-		config.Metadata, diags = types.MapValueFrom(ctx, types.StringType, metadataMap)
-		resp.Diagnostics.Append(diags...)
+	config.Metadata = objectMetadataValue(getOutput.Metadata)
+
+	// Objects uploaded without checksums, or in multiple parts, have no
+	// server checksum of their whole body
+	var ok bool
+	config.ChecksumSHA256, config.ChecksumCRC32, ok = serverChecksumValues(getOutput.ChecksumSHA256, getOutput.ChecksumCRC32)
+	if ok {
+		config.ChecksumSource = types.StringValue(checksumSourceServer)
 	} else {
-		config.Metadata = types.MapNull(types.StringType)
+		config.ChecksumSHA256 = types.StringValue(hex.EncodeToString(checksums.sha256))
+		config.ChecksumCRC32 = types.StringValue(hex.EncodeToString(checksums.crc32))
+		config.ChecksumSource = types.StringValue(checksumSourceComputed)
 	}
 
 	diags = resp.State.Set(ctx, &config)
 	resp.Diagnostics.Append(diags...)
 }
+
+// missingObject sets the state of a read of a missing object when
+// allow_missing is set, and fails it otherwise.
+func (d *GarageObjectDataSource) missingObject(ctx context.Context, config GarageObjectDataSourceModel, resp *datasource.ReadResponse) {
+	if !config.AllowMissing.ValueBool() {
+		resp.Diagnostics.AddError(
+			"Object Not Found",
+			fmt.Sprintf("Object %s does not exist in bucket %s. Set allow_missing = true to read objects that may not exist.",
+				config.Key.ValueString(), config.Bucket.ValueString()),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "Object does not exist", map[string]interface{}{
+		"bucket": config.Bucket.ValueString(),
+		"key":    config.Key.ValueString(),
+	})
+
+	// The computed attributes are null in the configuration
+	config.Exists = types.BoolValue(false)
+	config.ID = types.StringValue(objectID(config.Bucket.ValueString(), config.Key.ValueString()))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+// objectBodyValues returns the body and body_base64 values of a downloaded
+// object. Binary content cannot be represented in a string attribute without
+// mangling it, body is null unless it is valid UTF-8.
+func objectBodyValues(content []byte) (types.String, types.String) {
+	bodyBase64 := types.StringValue(base64.StdEncoding.EncodeToString(content))
+	if !utf8.Valid(content) {
+		return types.StringNull(), bodyBase64
+	}
+	return types.StringValue(string(content)), bodyBase64
+}
+
+// writeObjectFile streams body to a temporary file next to outputPath and
+// renames it into place, so readers never see a partial file. An existing
+// file is only replaced when overwrite is set, unless it already has the
+// same content.
+func writeObjectFile(body io.Reader, outputPath string, perm os.FileMode, overwrite bool) (objectChecksums, int64, error) {
+	dir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return objectChecksums{}, 0, err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(outputPath)+".*")
+	if err != nil {
+		return objectChecksums{}, 0, err
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	checksums, err := hashBody(io.TeeReader(body, tmp))
+	if err != nil {
+		return objectChecksums{}, 0, err
+	}
+	info, err := tmp.Stat()
+	if err != nil {
+		return objectChecksums{}, 0, err
+	}
+
+	if !overwrite {
+		existing, err := os.Open(outputPath)
+		if err == nil {
+			existingChecksums, err := hashBody(existing)
+			_ = existing.Close()
+			if err != nil {
+				return objectChecksums{}, 0, err
+			}
+			if !bytes.Equal(existingChecksums.sha256, checksums.sha256) {
+				return objectChecksums{}, 0, errors.New("the file exists with different content, set overwrite = true to replace it")
+			}
+		} else if !os.IsNotExist(err) {
+			return objectChecksums{}, 0, err
+		}
+	}
+
+	if err := tmp.Chmod(perm); err != nil {
+		return objectChecksums{}, 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return objectChecksums{}, 0, err
+	}
+	if err := os.Rename(tmp.Name(), outputPath); err != nil {
+		return objectChecksums{}, 0, err
+	}
+
+	return checksums, info.Size(), nil
+}
+
+// errBodyTooLarge is returned by gunzipBody when the decompressed content
+// exceeds the limit.
+var errBodyTooLarge = errors.New("body too large")
+
+// isGzipEncoding reports whether a Content-Encoding is gzip.
+func isGzipEncoding(contentEncoding *string) bool {
+	encoding := strings.TrimSpace(aws.ToString(contentEncoding))
+	return strings.EqualFold(encoding, "gzip") || strings.EqualFold(encoding, "x-gzip")
+}
+
+// gunzipBody decompresses a gzip body of at most maxSize bytes once
+// decompressed, 0 disabling the limit.
+func gunzipBody(content []byte, maxSize int64) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	body := io.Reader(reader)
+	if maxSize > 0 {
+		body = io.LimitReader(reader, maxSize+1)
+	}
+	decompressed, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	if maxSize > 0 && int64(len(decompressed)) > maxSize {
+		return nil, errBodyTooLarge
+	}
+
+	return decompressed, nil
+}
+
+// serverChecksumValues returns the checksum_sha256 and checksum_crc32 values
+// of the checksums reported by Garage, and whether it reported any.
+func serverChecksumValues(sha256Checksum, crc32Checksum *string) (types.String, types.String, bool) {
+	shaValue, crcValue := types.StringNull(), types.StringNull()
+	if v, ok := checksumHex(sha256Checksum); ok {
+		shaValue = types.StringValue(v)
+	}
+	if v, ok := checksumHex(crc32Checksum); ok {
+		crcValue = types.StringValue(v)
+	}
+	return shaValue, crcValue, !shaValue.IsNull() || !crcValue.IsNull()
+}
+
+// objectContentTypeValue returns the Content-Type of a fetched object,
+// defaulting to the S3 one when Garage does not report it.
+func objectContentTypeValue(contentType *string) types.String {
+	if contentType == nil {
+		return types.StringValue("application/octet-stream")
+	}
+	return types.StringValue(*contentType)
+}
+
+// objectMetadataValue converts the user metadata of a fetched object, null
+// when there is none.
+func objectMetadataValue(metadata map[string]string) types.Map {
+	if len(metadata) == 0 {
+		return types.MapNull(types.StringType)
+	}
+	return metadataValue(metadata)
+}
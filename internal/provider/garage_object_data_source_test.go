@@ -4,10 +4,21 @@
 package provider
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -21,17 +32,411 @@ func TestAccGarageObjectDataSource(t *testing.T) {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("data.garage_object.test", "key", "test-data-object.txt"),
 					resource.TestCheckResourceAttr("data.garage_object.test", "body", "Hello from data source test!"),
+					resource.TestCheckResourceAttr("data.garage_object.test", "body_base64", "SGVsbG8gZnJvbSBkYXRhIHNvdXJjZSB0ZXN0IQ=="),
 					resource.TestCheckResourceAttr("data.garage_object.test", "content_type", "text/plain"),
 					resource.TestCheckResourceAttrSet("data.garage_object.test", "etag"),
 					resource.TestCheckResourceAttrSet("data.garage_object.test", "content_length"),
-					resource.TestCheckResourceAttrSet("data.garage_object.test", "last_modified"),
+					resource.TestCheckResourceAttrPair("data.garage_object.test", "last_modified", "garage_object.test", "last_modified"),
 					resource.TestCheckResourceAttrSet("data.garage_object.test", "id"),
+					resource.TestCheckResourceAttr("data.garage_object.test", "exists", "true"),
+					resource.TestCheckResourceAttrPair("data.garage_object.test", "checksum_sha256", "garage_object.test", "checksum_sha256"),
+					resource.TestCheckResourceAttrPair("data.garage_object.test", "checksum_crc32", "garage_object.test", "checksum_crc32"),
+					resource.TestCheckResourceAttrSet("data.garage_object.test", "checksum_source"),
+					resource.TestCheckResourceAttr("data.garage_object.missing", "exists", "false"),
+					resource.TestCheckNoResourceAttr("data.garage_object.missing", "body"),
+					resource.TestCheckNoResourceAttr("data.garage_object.missing", "etag"),
 				),
 			},
 		},
 	})
 }
 
+func TestAccGarageObjectDataSource_binary(t *testing.T) {
+	// PNG signature and the start of an IHDR chunk, not valid UTF-8
+	const blob = "iVBORw0KGgoAAAANSUhEUgAAAAE="
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageObjectDataSourceConfig_binary(blob),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.garage_object.test", "body_base64", blob),
+					resource.TestCheckNoResourceAttr("data.garage_object.test", "body"),
+					resource.TestCheckResourceAttr("data.garage_object.test", "content_length", "20"),
+					resource.TestCheckResourceAttrPair("data.garage_object.test", "etag", "garage_object.test", "etag"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccGarageObjectDataSource_contentHeaders(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageObjectDataSourceConfig_contentHeaders(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_object.test", "content_encoding", "identity"),
+					resource.TestCheckResourceAttr("garage_object.test", "content_disposition", `attachment; filename="report.txt"`),
+					resource.TestCheckResourceAttr("garage_object.test", "content_language", "en-US"),
+					resource.TestCheckResourceAttr("data.garage_object.test", "content_encoding", "identity"),
+					resource.TestCheckResourceAttr("data.garage_object.test", "content_disposition", `attachment; filename="report.txt"`),
+					resource.TestCheckResourceAttr("data.garage_object.test", "content_language", "en-US"),
+				),
+			},
+		},
+	})
+}
+
+func TestGarageObjectDataSourceRead_maxBodySize(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxBodySize tftypes.Value
+		wantHead    bool
+		wantGet     bool
+		wantError   string
+	}{
+		{name: "default limit", maxBodySize: tftypes.NewValue(tftypes.Number, nil), wantHead: true, wantGet: true},
+		{name: "object too large", maxBodySize: tftypes.NewValue(tftypes.Number, 4), wantHead: true, wantError: "Object Too Large"},
+		{name: "limit disabled", maxBodySize: tftypes.NewValue(tftypes.Number, 0), wantGet: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var heads, gets int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("ETag", `"5d41402abc4b2a76b9719d911017c592"`)
+				w.Header().Set("Content-Length", "5")
+				switch r.Method {
+				case http.MethodHead:
+					heads++
+				case http.MethodGet:
+					gets++
+					_, _ = w.Write([]byte("hello"))
+				}
+			}))
+			defer server.Close()
+
+			d := &GarageObjectDataSource{s3Client: testS3Client(server.URL)}
+			resp := testDataSourceRead(t, d, map[string]tftypes.Value{
+				"bucket":        tftypes.NewValue(tftypes.String, "bucket"),
+				"key":           tftypes.NewValue(tftypes.String, "key.txt"),
+				"max_body_size": tt.maxBodySize,
+			})
+
+			if tt.wantError != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantError {
+					t.Fatalf("Expected %q error, got %v", tt.wantError, resp.Diagnostics)
+				}
+				if !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "garage_object_head") {
+					t.Errorf("Expected the error to mention garage_object_head, got %s", resp.Diagnostics.Errors()[0].Detail())
+				}
+			} else if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if (heads == 1) != tt.wantHead || (gets == 1) != tt.wantGet {
+				t.Errorf("Expected head=%v get=%v, got %d HEAD and %d GET requests", tt.wantHead, tt.wantGet, heads, gets)
+			}
+		})
+	}
+}
+
+func TestGarageObjectDataSourceRead_checksums(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		value      string
+		wantSource string
+		wantCRC32  string
+	}{
+		{name: "server checksum", header: "X-Amz-Checksum-Sha256", value: "LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=", wantSource: "server"},
+		{name: "no server checksum", wantSource: "computed", wantCRC32: "3610a686"},
+		{name: "multipart checksum", header: "X-Amz-Checksum-Sha256", value: "LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=-2", wantSource: "computed", wantCRC32: "3610a686"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet && r.Header.Get("X-Amz-Checksum-Mode") != "ENABLED" {
+					t.Errorf("Expected checksum mode to be enabled")
+				}
+				w.Header().Set("Content-Length", "5")
+				if tt.header != "" {
+					w.Header().Set(tt.header, tt.value)
+				}
+				if r.Method == http.MethodGet {
+					_, _ = w.Write([]byte("hello"))
+				}
+			}))
+			defer server.Close()
+
+			d := &GarageObjectDataSource{s3Client: testS3Client(server.URL)}
+			resp := testDataSourceRead(t, d, map[string]tftypes.Value{
+				"bucket": tftypes.NewValue(tftypes.String, "bucket"),
+				"key":    tftypes.NewValue(tftypes.String, "key.txt"),
+			})
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var state GarageObjectDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			if state.ChecksumSource.ValueString() != tt.wantSource {
+				t.Errorf("Expected checksum_source %s, got %s", tt.wantSource, state.ChecksumSource)
+			}
+			if state.ChecksumSHA256.ValueString() != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+				t.Errorf("Unexpected checksum_sha256 %s", state.ChecksumSHA256)
+			}
+			if state.ChecksumCRC32.ValueString() != tt.wantCRC32 {
+				t.Errorf("Expected checksum_crc32 %q, got %s", tt.wantCRC32, state.ChecksumCRC32)
+			}
+		})
+	}
+}
+
+func TestGarageObjectDataSourceRead_outputPath(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  string
+		overwrite bool
+		wantError string
+	}{
+		{name: "new file"},
+		{name: "existing file with the same content", existing: "hello"},
+		{name: "existing file", existing: "stale", wantError: "Failed to Write Object File"},
+		{name: "existing file with overwrite", existing: "stale", overwrite: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					t.Errorf("Unexpected %s request, the body is streamed without a size check", r.Method)
+				}
+				w.Header().Set("Content-Length", "5")
+				_, _ = w.Write([]byte("hello"))
+			}))
+			defer server.Close()
+
+			outputPath := filepath.Join(t.TempDir(), "nested", "dir", "object.txt")
+			if tt.existing != "" {
+				if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(outputPath, []byte(tt.existing), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			d := &GarageObjectDataSource{s3Client: testS3Client(server.URL)}
+			resp := testDataSourceRead(t, d, map[string]tftypes.Value{
+				"bucket":          tftypes.NewValue(tftypes.String, "bucket"),
+				"key":             tftypes.NewValue(tftypes.String, "key.txt"),
+				"max_body_size":   tftypes.NewValue(tftypes.Number, 1),
+				"output_path":     tftypes.NewValue(tftypes.String, outputPath),
+				"file_permission": tftypes.NewValue(tftypes.String, "0600"),
+				"overwrite":       tftypes.NewValue(tftypes.Bool, tt.overwrite),
+			})
+
+			content, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			entries, err := os.ReadDir(filepath.Dir(outputPath))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Errorf("Expected the temporary file to be removed, got %v", entries)
+			}
+
+			if tt.wantError != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantError {
+					t.Fatalf("Expected %q error, got %v", tt.wantError, resp.Diagnostics)
+				}
+				if string(content) != tt.existing {
+					t.Errorf("Expected the existing file to be kept, got %q", content)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if string(content) != "hello" {
+				t.Errorf("Expected the object content in the file, got %q", content)
+			}
+			if info, err := os.Stat(outputPath); err != nil || info.Mode().Perm() != 0o600 {
+				t.Errorf("Expected file mode 0600, got %v (%v)", info, err)
+			}
+
+			var state GarageObjectDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			if !state.Body.IsNull() || !state.BodyBase64.IsNull() {
+				t.Errorf("Expected null body attributes, got %s and %s", state.Body, state.BodyBase64)
+			}
+			if state.OutputSHA256.ValueString() != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+				t.Errorf("Unexpected output_sha256 %s", state.OutputSHA256)
+			}
+			if state.ContentLength.ValueInt64() != 5 || state.ChecksumSource.ValueString() != "computed" {
+				t.Errorf("Unexpected content_length %s or checksum_source %s", state.ContentLength, state.ChecksumSource)
+			}
+		})
+	}
+}
+
+func TestGarageObjectDataSourceRead_decompress(t *testing.T) {
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, _ = writer.Write([]byte(`{"hello":"world"}`))
+	_ = writer.Close()
+
+	tests := []struct {
+		name        string
+		encoding    string
+		content     []byte
+		decompress  bool
+		wantBody    string
+		wantLength  int64
+		wantEncoded bool
+		wantError   string
+	}{
+		{name: "gzip object", encoding: "gzip", content: compressed.Bytes(), decompress: true, wantBody: `{"hello":"world"}`, wantLength: 17, wantEncoded: true},
+		{name: "gzip object without decompress", encoding: "gzip", content: compressed.Bytes(), wantLength: int64(compressed.Len())},
+		{name: "plain object", content: []byte("hello"), decompress: true, wantBody: "hello", wantLength: 5},
+		{name: "corrupted gzip object", encoding: "gzip", content: []byte("not gzip"), decompress: true, wantError: "Invalid Gzip Content"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.encoding != "" {
+					w.Header().Set("Content-Encoding", tt.encoding)
+				}
+				w.Header().Set("Content-Length", strconv.Itoa(len(tt.content)))
+				if r.Method == http.MethodGet {
+					_, _ = w.Write(tt.content)
+				}
+			}))
+			defer server.Close()
+
+			d := &GarageObjectDataSource{s3Client: testS3Client(server.URL)}
+			resp := testDataSourceRead(t, d, map[string]tftypes.Value{
+				"bucket":     tftypes.NewValue(tftypes.String, "bucket"),
+				"key":        tftypes.NewValue(tftypes.String, "key.json"),
+				"decompress": tftypes.NewValue(tftypes.Bool, tt.decompress),
+			})
+
+			if tt.wantError != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantError {
+					t.Fatalf("Expected %q error, got %v", tt.wantError, resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var state GarageObjectDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			if state.Body.ValueString() != tt.wantBody {
+				t.Errorf("Expected body %q, got %s", tt.wantBody, state.Body)
+			}
+			if state.ContentLength.ValueInt64() != tt.wantLength {
+				t.Errorf("Expected content_length %d, got %s", tt.wantLength, state.ContentLength)
+			}
+			if tt.wantEncoded && state.ContentLengthEncoded.ValueInt64() != int64(len(tt.content)) {
+				t.Errorf("Expected content_length_encoded %d, got %s", len(tt.content), state.ContentLengthEncoded)
+			}
+			if !tt.wantEncoded && !state.ContentLengthEncoded.IsNull() {
+				t.Errorf("Expected null content_length_encoded, got %s", state.ContentLengthEncoded)
+			}
+		})
+	}
+}
+
+func TestGarageObjectDataSourceRead_missing(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		allowMissing bool
+		wantError    string
+	}{
+		{name: "allowed", status: http.StatusNotFound, allowMissing: true},
+		{name: "not allowed", status: http.StatusNotFound, wantError: "Object Not Found"},
+		{name: "other error", status: http.StatusForbidden, allowMissing: true, wantError: "Failed to Retrieve Object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			d := &GarageObjectDataSource{s3Client: testS3Client(server.URL)}
+			resp := testDataSourceRead(t, d, map[string]tftypes.Value{
+				"bucket":        tftypes.NewValue(tftypes.String, "bucket"),
+				"key":           tftypes.NewValue(tftypes.String, "key.txt"),
+				"allow_missing": tftypes.NewValue(tftypes.Bool, tt.allowMissing),
+			})
+
+			if tt.wantError != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantError {
+					t.Fatalf("Expected %q error, got %v", tt.wantError, resp.Diagnostics)
+				}
+				if detail := resp.Diagnostics.Errors()[0].Detail(); tt.status == http.StatusNotFound &&
+					(!strings.Contains(detail, "key.txt") || !strings.Contains(detail, "bucket")) {
+					t.Errorf("Expected the error to name the bucket and key, got %s", detail)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var state GarageObjectDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			if state.Exists.ValueBool() || !state.Body.IsNull() || !state.ETag.IsNull() {
+				t.Errorf("Expected a missing object with null attributes, got %+v", state)
+			}
+		})
+	}
+}
+
+// testDataSourceRead runs Read with a configuration built from attrs,
+// the other attributes being null, and returns the response.
+func testDataSourceRead(t *testing.T, d datasource.DataSource, attrs map[string]tftypes.Value) *datasource.ReadResponse {
+	t.Helper()
+
+	ctx := context.Background()
+	schemaResp := &datasource.SchemaResponse{}
+	d.Schema(ctx, datasource.SchemaRequest{}, schemaResp)
+
+	objectType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatalf("Unexpected schema type")
+	}
+	values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, typ := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(typ, nil)
+	}
+	for name, v := range attrs {
+		values[name] = v
+	}
+
+	config := tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)}
+	resp := &datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	d.Read(ctx, datasource.ReadRequest{Config: config}, resp)
+
+	return resp
+}
+
 func testAccGarageObjectDataSourceConfig(content string) string {
 	return testAccProviderConfig() + fmt.Sprintf(`
        resource "garage_bucket" "test" {
@@ -45,6 +450,8 @@ func testAccGarageObjectDataSourceConfig(content string) string {
           read  = true
           write = true
           owner = false
+
+          wait_for_propagation = "30s"
        }
        
        resource "garage_object" "test" {
@@ -60,6 +467,80 @@ func testAccGarageObjectDataSourceConfig(content string) string {
           bucket = garage_bucket.test.id
           key    = garage_object.test.key
        }
+
+       data "garage_object" "missing" {
+          depends_on = [garage_bucket_permission.test]
+
+          bucket        = garage_bucket.test.id
+          key           = "missing.txt"
+          allow_missing = true
+       }
        `, content, os.Getenv("GARAGE_ACCESS_KEY"),
 	)
 }
+
+func testAccGarageObjectDataSourceConfig_contentHeaders() string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-object-headers"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_object" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket              = garage_bucket.test.id
+  key                 = "report.txt"
+  content             = "quarterly report"
+  content_type        = "text/plain"
+  content_encoding    = "identity"
+  content_disposition = "attachment; filename=\"report.txt\""
+  content_language    = "en-US"
+}
+
+data "garage_object" "test" {
+  bucket = garage_bucket.test.id
+  key    = garage_object.test.key
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"))
+}
+
+func testAccGarageObjectDataSourceConfig_binary(blob string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-object-binary"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[2]q
+
+  read  = true
+  write = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_object" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket         = garage_bucket.test.id
+  key            = "pixel.png"
+  content_base64 = %[1]q
+}
+
+data "garage_object" "test" {
+  bucket = garage_bucket.test.id
+  key    = garage_object.test.key
+}
+`, blob, os.Getenv("GARAGE_ACCESS_KEY"))
+}
@@ -0,0 +1,607 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = &GarageObjectDirectoryResource{}
+
+// objectDirectoryParallelism is the number of files uploaded at once.
+const objectDirectoryParallelism = 8
+
+// GarageObjectDirectoryResource syncs a local directory into a bucket. The
+// state holds a manifest of the uploaded files, relative path to MD5, which
+// is compared with the directory when planning and with the object ETags
+// when refreshing.
+type GarageObjectDirectoryResource struct {
+	s3Client    *s3.Client
+	s3AccessKey string
+
+	contentTypeOverrides map[string]string
+}
+
+type GarageObjectDirectoryResourceModel struct {
+	Bucket        types.String `tfsdk:"bucket"`
+	SourceDir     types.String `tfsdk:"source_dir"`
+	KeyPrefix     types.String `tfsdk:"key_prefix"`
+	Exclude       types.List   `tfsdk:"exclude"`
+	DeleteRemoved types.Bool   `tfsdk:"delete_removed"`
+	Files         types.Map    `tfsdk:"files"`
+	ID            types.String `tfsdk:"id"`
+}
+
+func NewGarageObjectDirectoryResource() resource.Resource {
+	return &GarageObjectDirectoryResource{}
+}
+
+func (r *GarageObjectDirectoryResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_object_directory"
+}
+
+func (r *GarageObjectDirectoryResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Syncs the files of a local directory into a Garage bucket",
+		Attributes: map[string]schema.Attribute{
+			"bucket": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the bucket to store the files",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_dir": schema.StringAttribute{
+				Required:    true,
+				Description: "Local directory whose files are uploaded, recursively",
+			},
+			"key_prefix": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+				Description: "Prefix prepended to the relative path of each file to build its key, e.g. site/. Defaults to none",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"exclude": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Glob patterns of files not to upload, matched against the relative path and the file name (e.g. *.map). A pattern ending with /** excludes a directory",
+			},
+			"delete_removed": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Delete the objects of files removed from source_dir. When false they are left in the bucket and no longer managed. Defaults to true",
+			},
+			"files": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Manifest of the synced files: hex-encoded MD5 of the content by relative path",
+				PlanModifiers: []planmodifier.Map{
+					directoryManifestPlanModifier{},
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier (bucket/key_prefix)",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *GarageObjectDirectoryResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			"Expected *GarageProviderModel",
+		)
+		return
+	}
+
+	r.s3Client = providerData.S3Client
+	r.s3AccessKey = providerData.AccessKey.ValueString()
+	r.contentTypeOverrides = providerData.contentTypeOverrides()
+}
+
+func (r *GarageObjectDirectoryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan GarageObjectDirectoryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = types.StringValue(plan.Bucket.ValueString() + "/" + plan.KeyPrefix.ValueString())
+	resp.Diagnostics.Append(r.sync(ctx, &plan, map[string]string{})...)
+
+	// Keep the files that were uploaded, failed ones are retried on the
+	// next apply
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *GarageObjectDirectoryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state GarageObjectDirectoryResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.s3Client == nil {
+		resp.Diagnostics.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return
+	}
+
+	manifest := map[string]string{}
+	resp.Diagnostics.Append(state.Files.ElementsAs(ctx, &manifest, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	etags := map[string]string{}
+	paginator := s3.NewListObjectsV2Paginator(r.s3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(state.Bucket.ValueString()),
+		Prefix: aws.String(state.KeyPrefix.ValueString()),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if isObjectNotFound(err) {
+			tflog.Warn(ctx, "Bucket not found, removing directory from state", map[string]interface{}{
+				"bucket": state.Bucket.ValueString(),
+			})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Failed to List Objects",
+				fmt.Sprintf("Could not list objects under %q: %s", state.KeyPrefix.ValueString(),
+					objectErrorDetail(r.s3AccessKey, state.Bucket.ValueString(), false, err)),
+			)
+			return
+		}
+		for _, object := range page.Contents {
+			etags[aws.ToString(object.Key)] = strings.Trim(aws.ToString(object.ETag), `"`)
+		}
+	}
+
+	// Files deleted or changed out-of-band are dropped from the manifest so
+	// the next apply uploads them again. Multipart ETags are not MD5s and
+	// cannot be compared.
+	for rel, sum := range manifest {
+		etag, ok := etags[state.KeyPrefix.ValueString()+rel]
+		if !ok || (!isMultipartETag(etag) && etag != sum) {
+			tflog.Debug(ctx, "Synced file drifted", map[string]interface{}{
+				"path":   rel,
+				"exists": ok,
+			})
+			delete(manifest, rel)
+		}
+	}
+
+	files, diags := types.MapValueFrom(ctx, types.StringType, manifest)
+	resp.Diagnostics.Append(diags...)
+	state.Files = files
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *GarageObjectDirectoryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state GarageObjectDirectoryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	prior := map[string]string{}
+	resp.Diagnostics.Append(state.Files.ElementsAs(ctx, &prior, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = state.ID
+	resp.Diagnostics.Append(r.sync(ctx, &plan, prior)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *GarageObjectDirectoryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state GarageObjectDirectoryResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.s3Client == nil {
+		resp.Diagnostics.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return
+	}
+
+	manifest := map[string]string{}
+	resp.Diagnostics.Append(state.Files.ElementsAs(ctx, &manifest, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rels := sortedKeys(manifest)
+	errs := deleteObjectsBatch(ctx, r.s3Client, state.Bucket.ValueString(), objectDirectoryKeys(state, rels))
+	for _, rel := range rels {
+		if err, failed := errs[state.KeyPrefix.ValueString()+rel]; failed {
+			resp.Diagnostics.AddError(
+				"Object Deletion Failed",
+				fmt.Sprintf("Could not delete the object of %s: %s", rel, err),
+			)
+		}
+	}
+}
+
+// sync uploads the files of the planned manifest that differ from prior and
+// deletes the removed ones. plan.Files is set to the manifest of what was
+// synced, the failures are all reported.
+func (r *GarageObjectDirectoryResource) sync(ctx context.Context, plan *GarageObjectDirectoryResourceModel, prior map[string]string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if r.s3Client == nil {
+		diags.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return diags
+	}
+
+	// source_dir may not have been known when planning
+	planned := map[string]string{}
+	if plan.Files.IsUnknown() {
+		var exclude []string
+		diags.Append(plan.Exclude.ElementsAs(ctx, &exclude, false)...)
+		if diags.HasError() {
+			return diags
+		}
+
+		manifest, err := directoryManifest(plan.SourceDir.ValueString(), exclude)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("source_dir"),
+				"Failed to Read Directory",
+				fmt.Sprintf("Could not read %s: %s", plan.SourceDir.ValueString(), err),
+			)
+			return diags
+		}
+		planned = manifest
+	} else {
+		diags.Append(plan.Files.ElementsAs(ctx, &planned, false)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	var toUpload, toDelete []string
+	synced := map[string]string{}
+	for rel, sum := range planned {
+		if prior[rel] == sum {
+			synced[rel] = sum
+		} else {
+			toUpload = append(toUpload, rel)
+		}
+	}
+	for rel := range prior {
+		if _, ok := planned[rel]; !ok && plan.DeleteRemoved.ValueBool() {
+			toDelete = append(toDelete, rel)
+		}
+	}
+	sort.Strings(toUpload)
+	sort.Strings(toDelete)
+
+	tflog.Debug(ctx, "Syncing directory", map[string]interface{}{
+		"source_dir": plan.SourceDir.ValueString(),
+		"upload":     len(toUpload),
+		"delete":     len(toDelete),
+	})
+
+	uploadErrs := forEachParallel(toUpload, func(rel string) error {
+		return r.uploadFile(ctx, *plan, rel, planned[rel])
+	})
+	for _, rel := range toUpload {
+		if err, failed := uploadErrs[rel]; failed {
+			diags.AddAttributeError(
+				path.Root("files").AtMapKey(rel),
+				"Failed to Upload File",
+				fmt.Sprintf("Could not upload %s: %s", rel, err),
+			)
+			continue
+		}
+		synced[rel] = planned[rel]
+	}
+
+	// Objects that failed to be deleted stay in the manifest to be retried
+	deleteErrs := deleteObjectsBatch(ctx, r.s3Client, plan.Bucket.ValueString(), objectDirectoryKeys(*plan, toDelete))
+	for _, rel := range toDelete {
+		if err, failed := deleteErrs[plan.KeyPrefix.ValueString()+rel]; failed {
+			diags.AddAttributeError(
+				path.Root("files").AtMapKey(rel),
+				"Object Deletion Failed",
+				fmt.Sprintf("Could not delete the object of removed file %s: %s", rel, err),
+			)
+			synced[rel] = prior[rel]
+		}
+	}
+
+	files, d := types.MapValueFrom(ctx, types.StringType, synced)
+	diags.Append(d...)
+	plan.Files = files
+
+	return diags
+}
+
+// uploadFile uploads the file at rel, checking that it still has the
+// planned content.
+func (r *GarageObjectDirectoryResource) uploadFile(ctx context.Context, plan GarageObjectDirectoryResourceModel, rel, sum string) error {
+	file, err := os.Open(filepath.Join(plan.SourceDir.ValueString(), filepath.FromSlash(rel)))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	hash := md5.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); got != sum {
+		return fmt.Errorf("the file changed since the plan (MD5 %s, planned %s)", got, sum)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	key := plan.KeyPrefix.ValueString() + rel
+	contentType := fileContentType(key, r.contentTypeOverrides)
+
+	_, err = r.s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(plan.Bucket.ValueString()),
+		Key:           aws.String(key),
+		Body:          file,
+		ContentLength: aws.Int64(size),
+		ContentMD5:    aws.String(base64.StdEncoding.EncodeToString(hash.Sum(nil))),
+		ContentType:   aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("%s", objectErrorDetail(r.s3AccessKey, plan.Bucket.ValueString(), true, err))
+	}
+
+	return nil
+}
+
+// objectDirectoryKeys returns the object keys of the files at rels.
+func objectDirectoryKeys(data GarageObjectDirectoryResourceModel, rels []string) []string {
+	keys := make([]string, 0, len(rels))
+	for _, rel := range rels {
+		keys = append(keys, data.KeyPrefix.ValueString()+rel)
+	}
+	return keys
+}
+
+// forEachParallel calls fn for each item with bounded parallelism and
+// returns the errors by item.
+func forEachParallel(items []string, fn func(item string) error) map[string]error {
+	errs := map[string]error{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, objectDirectoryParallelism)
+
+	for _, item := range items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			if err := fn(item); err != nil {
+				mu.Lock()
+				errs[item] = err
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// sortedKeys returns the keys of m in order, for stable diagnostics.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// directoryManifest walks dir and returns the hex-encoded MD5 of each
+// regular file not excluded, by slash-separated relative path. Symbolic
+// links to files are followed, not those to directories.
+func directoryManifest(dir string, exclude []string) (map[string]string, error) {
+	for _, pattern := range exclude {
+		if _, err := filepath.Match(strings.TrimSuffix(pattern, "/**"), ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+
+	manifest := map[string]string{}
+	err := filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if entry.IsDir() {
+			if rel != "." && isExcluded(rel+"/", exclude) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isExcluded(rel, exclude) {
+			return nil
+		}
+
+		info, err := os.Stat(p)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		sum, err := fileMD5(p)
+		if err != nil {
+			return err
+		}
+		manifest[rel] = sum
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// isExcluded reports whether the relative path matches one of the exclude
+// patterns. Directories are passed with a trailing slash.
+func isExcluded(rel string, exclude []string) bool {
+	name := rel[strings.LastIndex(rel, "/")+1:]
+	for _, pattern := range exclude {
+		if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+			if strings.HasPrefix(rel, dir+"/") {
+				return true
+			}
+			continue
+		}
+		if strings.HasSuffix(rel, "/") {
+			continue
+		}
+		if matched, _ := filepath.Match(pattern, rel); matched {
+			return true
+		}
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// fileMD5 returns the hex-encoded MD5 of the file at p.
+func fileMD5(p string) (string, error) {
+	file, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// directoryManifestPlanModifier plans the manifest of source_dir, so that
+// added, changed and removed files show as a diff of files.
+type directoryManifestPlanModifier struct{}
+
+func (m directoryManifestPlanModifier) Description(_ context.Context) string {
+	return "Sets the planned manifest to the MD5 of the files in source_dir."
+}
+
+func (m directoryManifestPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m directoryManifestPlanModifier) PlanModifyMap(ctx context.Context, req planmodifier.MapRequest, resp *planmodifier.MapResponse) {
+	// Nothing to sync on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var sourceDir types.String
+	var excludeList types.List
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("source_dir"), &sourceDir)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("exclude"), &excludeList)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Computed at apply time
+	if sourceDir.IsUnknown() || excludeList.IsUnknown() {
+		resp.PlanValue = types.MapUnknown(types.StringType)
+		return
+	}
+
+	var exclude []string
+	resp.Diagnostics.Append(excludeList.ElementsAs(ctx, &exclude, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	manifest, err := directoryManifest(sourceDir.ValueString(), exclude)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("source_dir"),
+			"Failed to Read Directory",
+			fmt.Sprintf("Could not read %s: %s", sourceDir.ValueString(), err),
+		)
+		return
+	}
+
+	planValue, diags := types.MapValueFrom(ctx, types.StringType, manifest)
+	resp.Diagnostics.Append(diags...)
+	resp.PlanValue = planValue
+}
@@ -0,0 +1,236 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGarageObjectDirectoryResource(t *testing.T) {
+	dir := t.TempDir()
+	testWriteFiles(t, dir, map[string]string{
+		"index.html":        "<h1>hello</h1>",
+		"assets/app.js":     "console.log(1)",
+		"assets/app.js.map": "{}",
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageObjectDirectoryResourceConfig(dir),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_object_directory.test", "files.%", "2"),
+					resource.TestCheckResourceAttr("garage_object_directory.test", "files.index.html", "a01618fc9b714c0e530f525e1bd6b123"),
+					resource.TestCheckResourceAttrSet("garage_object_directory.test", "files.assets/app.js"),
+					resource.TestCheckResourceAttr("data.garage_object_head.index", "content_type", "text/html; charset=utf-8"),
+				),
+			},
+			// Changed and removed files are synced
+			{
+				PreConfig: func() {
+					testWriteFiles(t, dir, map[string]string{"index.html": "<h1>hello again</h1>"})
+					if err := os.Remove(filepath.Join(dir, "assets", "app.js")); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccGarageObjectDirectoryResourceConfig(dir),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_object_directory.test", "files.%", "1"),
+					resource.TestCheckNoResourceAttr("garage_object_directory.test", "files.assets/app.js"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestDirectoryManifest(t *testing.T) {
+	dir := t.TempDir()
+	testWriteFiles(t, dir, map[string]string{
+		"index.html":             "hello",
+		"assets/app.js":          "",
+		"assets/app.js.map":      "{}",
+		"node_modules/lib/x.js":  "x",
+		"assets/node_modules.js": "y",
+	})
+
+	manifest, err := directoryManifest(dir, []string{"*.map", "node_modules/**"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := map[string]string{
+		"index.html":             "5d41402abc4b2a76b9719d911017c592",
+		"assets/app.js":          "d41d8cd98f00b204e9800998ecf8427e",
+		"assets/node_modules.js": "415290769594460e2e485922904f345d",
+	}
+	if fmt.Sprint(manifest) != fmt.Sprint(expected) {
+		t.Errorf("Expected manifest %v, got %v", expected, manifest)
+	}
+
+	if _, err := directoryManifest(dir, []string{"[invalid"}); err == nil {
+		t.Error("Expected an error for an invalid exclude pattern")
+	}
+	if _, err := directoryManifest(filepath.Join(dir, "missing"), nil); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
+
+func TestGarageObjectDirectorySync(t *testing.T) {
+	dir := t.TempDir()
+	testWriteFiles(t, dir, map[string]string{
+		"same.txt":   "hello",
+		"new.txt":    "hello",
+		"fail-a.txt": "hello",
+		"fail-b.txt": "hello",
+	})
+
+	var mu sync.Mutex
+	var puts, deletes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+		switch r.Method {
+		case http.MethodPut:
+			puts = append(puts, key)
+			if r.Header.Get("Content-Md5") != "XUFAKrxLKna5cZ2REBfFkg==" || r.Header.Get("Content-Type") != "text/plain; charset=utf-8" {
+				t.Errorf("Unexpected upload headers for %s: %v", key, r.Header)
+			}
+			if strings.Contains(key, "fail-") {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>Forbidden</Message></Error>`))
+				return
+			}
+			w.Header().Set("ETag", `"5d41402abc4b2a76b9719d911017c592"`)
+		case http.MethodPost:
+			var request struct {
+				Objects []struct{ Key string } `xml:"Object"`
+			}
+			if err := xml.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Errorf("Unexpected delete request body: %s", err)
+			}
+			for _, object := range request.Objects {
+				deletes = append(deletes, object.Key)
+			}
+			_, _ = w.Write([]byte(`<DeleteResult></DeleteResult>`))
+		default:
+			t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	const hello = "5d41402abc4b2a76b9719d911017c592"
+	files, _ := types.MapValueFrom(context.Background(), types.StringType, map[string]string{
+		"same.txt":   hello,
+		"new.txt":    hello,
+		"fail-a.txt": hello,
+		"fail-b.txt": hello,
+	})
+	plan := GarageObjectDirectoryResourceModel{
+		Bucket:        types.StringValue("bucket"),
+		SourceDir:     types.StringValue(dir),
+		KeyPrefix:     types.StringValue("site/"),
+		Exclude:       types.ListNull(types.StringType),
+		DeleteRemoved: types.BoolValue(true),
+		Files:         files,
+	}
+	prior := map[string]string{
+		"same.txt":    hello,
+		"removed.txt": hello,
+	}
+
+	r := &GarageObjectDirectoryResource{s3Client: testS3Client(server.URL)}
+	diags := r.sync(context.Background(), &plan, prior)
+
+	// Every failed file is reported
+	if diags.ErrorsCount() != 2 {
+		t.Fatalf("Expected 2 errors, got %v", diags)
+	}
+	for i, name := range []string{"fail-a.txt", "fail-b.txt"} {
+		if !strings.Contains(diags.Errors()[i].Detail(), name) {
+			t.Errorf("Expected error %d to name %s, got %s", i, name, diags.Errors()[i].Detail())
+		}
+	}
+
+	sort.Strings(puts)
+	if fmt.Sprint(puts) != "[site/fail-a.txt site/fail-b.txt site/new.txt]" {
+		t.Errorf("Unexpected uploads %v", puts)
+	}
+	if fmt.Sprint(deletes) != "[site/removed.txt]" {
+		t.Errorf("Unexpected deletions %v", deletes)
+	}
+
+	synced := map[string]string{}
+	_ = plan.Files.ElementsAs(context.Background(), &synced, false)
+	expected := map[string]string{"same.txt": hello, "new.txt": hello}
+	if fmt.Sprint(synced) != fmt.Sprint(expected) {
+		t.Errorf("Expected the failed files to be left out of the manifest, got %v", synced)
+	}
+}
+
+// testWriteFiles writes files, by slash-separated relative path, under dir.
+func testWriteFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for rel, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func testAccGarageObjectDirectoryResourceConfig(dir string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-object-directory"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_object_directory" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket     = garage_bucket.test.global_alias
+  source_dir = %[2]q
+  key_prefix = "site/"
+  exclude    = ["*.map"]
+}
+
+data "garage_object_head" "index" {
+  depends_on = [garage_object_directory.test]
+
+  bucket = garage_bucket.test.global_alias
+  key    = "site/index.html"
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"), dir)
+}
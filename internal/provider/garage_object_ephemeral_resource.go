@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+var _ ephemeral.EphemeralResourceWithConfigure = &GarageObjectEphemeralResource{}
+
+// GarageObjectEphemeralResource reads an object without storing its content
+// in the plan or state. Nothing is held open, so there is no Close.
+type GarageObjectEphemeralResource struct {
+	s3Client    *s3.Client
+	s3AccessKey string
+}
+
+type GarageObjectEphemeralResourceModel struct {
+	Bucket         types.String `tfsdk:"bucket"`
+	Key            types.String `tfsdk:"key"`
+	SSECustomerKey types.String `tfsdk:"sse_customer_key"`
+	Body           types.String `tfsdk:"body"`
+	BodyBase64     types.String `tfsdk:"body_base64"`
+	ContentType    types.String `tfsdk:"content_type"`
+	ETag           types.String `tfsdk:"etag"`
+}
+
+func NewGarageObjectEphemeralResource() ephemeral.EphemeralResource {
+	return &GarageObjectEphemeralResource{}
+}
+
+func (e *GarageObjectEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_object"
+}
+
+func (e *GarageObjectEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads an object from a Garage bucket without persisting its content in the plan or state, e.g. to pass credentials to another provider",
+		Attributes: map[string]schema.Attribute{
+			"bucket": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the bucket containing the object",
+			},
+			"key": schema.StringAttribute{
+				Required:    true,
+				Description: "Key (name) of the object in the bucket",
+			},
+			"sse_customer_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Base64-encoded 256-bit key the object was encrypted with using SSE-C",
+				Validators: []validator.String{
+					validators.Base64(),
+				},
+			},
+			"body": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Object content as a string, null when the content is not valid UTF-8",
+			},
+			"body_base64": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Object content encoded in base64",
+			},
+			"content_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "MIME type of the object",
+			},
+			"etag": schema.StringAttribute{
+				Computed:    true,
+				Description: "ETag of the object",
+			},
+		},
+	}
+}
+
+func (e *GarageObjectEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			"Expected *GarageProviderModel",
+		)
+		return
+	}
+
+	e.s3Client = providerData.S3Client
+	e.s3AccessKey = providerData.AccessKey.ValueString()
+}
+
+func (e *GarageObjectEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data GarageObjectEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if e.s3Client == nil {
+		resp.Diagnostics.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return
+	}
+
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+		Key:    aws.String(data.Key.ValueString()),
+	}
+	if !data.SSECustomerKey.IsNull() {
+		// The validator guarantees valid base64
+		raw, _ := base64.StdEncoding.DecodeString(data.SSECustomerKey.ValueString())
+		if len(raw) != 32 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("sse_customer_key"),
+				"Invalid SSE-C Key",
+				fmt.Sprintf("Expected a base64-encoded 256-bit key, got %d bytes", len(raw)),
+			)
+			return
+		}
+
+		sum := md5.Sum(raw)
+		input.SSECustomerAlgorithm = aws.String("AES256")
+		input.SSECustomerKey = aws.String(data.SSECustomerKey.ValueString())
+		input.SSECustomerKeyMD5 = aws.String(base64.StdEncoding.EncodeToString(sum[:]))
+	}
+
+	getOutput, err := e.s3Client.GetObject(ctx, input)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Retrieve Object",
+			fmt.Sprintf("Could not download object %s from Garage: %s", data.Key.ValueString(),
+				objectErrorDetail(e.s3AccessKey, data.Bucket.ValueString(), false, err)),
+		)
+		return
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(getOutput.Body)
+
+	bodyBytes, err := io.ReadAll(getOutput.Body)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Read Object Body",
+			"Could not read object content: "+err.Error(),
+		)
+		return
+	}
+
+	data.Body, data.BodyBase64 = objectBodyValues(bodyBytes)
+	data.ContentType = objectContentTypeValue(getOutput.ContentType)
+	data.ETag = types.StringPointerValue(getOutput.ETag)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccGarageObjectEphemeralResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesWithEcho,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageObjectEphemeralResourceConfig(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("body"), knownvalue.StringExact(`{"token":"bootstrap"}`)),
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("content_type"), knownvalue.StringExact("application/json")),
+				},
+			},
+		},
+	})
+}
+
+func TestGarageObjectEphemeralResourceOpen(t *testing.T) {
+	tests := []struct {
+		name      string
+		sseKey    tftypes.Value
+		wantSSE   bool
+		wantError string
+	}{
+		{name: "plain", sseKey: tftypes.NewValue(tftypes.String, nil)},
+		{name: "sse-c", sseKey: tftypes.NewValue(tftypes.String, "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="), wantSSE: true},
+		{name: "short sse-c key", sseKey: tftypes.NewValue(tftypes.String, "aGVsbG8="), wantError: "Invalid SSE-C Key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+				}
+				if sse := r.Header.Get("X-Amz-Server-Side-Encryption-Customer-Algorithm") == "AES256"; sse != tt.wantSSE {
+					t.Errorf("Expected SSE-C headers %v, got %v", tt.wantSSE, r.Header)
+				}
+				if tt.wantSSE && r.Header.Get("X-Amz-Server-Side-Encryption-Customer-Key-Md5") != "hRasmdxgYDKV3nvbahU1MA==" {
+					t.Errorf("Unexpected SSE-C key MD5 %q", r.Header.Get("X-Amz-Server-Side-Encryption-Customer-Key-Md5"))
+				}
+				w.Header().Set("Content-Type", "application/octet-stream")
+				_, _ = w.Write([]byte{0xff, 0x00})
+			}))
+			defer server.Close()
+
+			e := &GarageObjectEphemeralResource{s3Client: testS3Client(server.URL)}
+			resp := testEphemeralResourceOpen(t, e, map[string]tftypes.Value{
+				"bucket":           tftypes.NewValue(tftypes.String, "bucket"),
+				"key":              tftypes.NewValue(tftypes.String, "secret.bin"),
+				"sse_customer_key": tt.sseKey,
+			})
+
+			if tt.wantError != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantError {
+					t.Fatalf("Expected %q error, got %v", tt.wantError, resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var result GarageObjectEphemeralResourceModel
+			resp.Diagnostics.Append(resp.Result.Get(context.Background(), &result)...)
+			if !result.Body.IsNull() {
+				t.Errorf("Expected null body for binary content, got %s", result.Body)
+			}
+			if result.BodyBase64.ValueString() != "/wA=" {
+				t.Errorf("Expected body_base64 /wA=, got %s", result.BodyBase64)
+			}
+		})
+	}
+}
+
+// testEphemeralResourceOpen runs Open with a configuration built from attrs,
+// the other attributes being null, and returns the response.
+func testEphemeralResourceOpen(t *testing.T, e ephemeral.EphemeralResource, attrs map[string]tftypes.Value) *ephemeral.OpenResponse {
+	t.Helper()
+
+	ctx := context.Background()
+	schemaResp := &ephemeral.SchemaResponse{}
+	e.Schema(ctx, ephemeral.SchemaRequest{}, schemaResp)
+
+	objectType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatalf("Unexpected schema type")
+	}
+	values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, typ := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(typ, nil)
+	}
+	for name, v := range attrs {
+		values[name] = v
+	}
+
+	config := tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)}
+	resp := &ephemeral.OpenResponse{Result: tfsdk.EphemeralResultData{Schema: schemaResp.Schema, Raw: config.Raw}}
+	// The type of the private data is internal to the framework
+	private := reflect.ValueOf(resp).Elem().FieldByName("Private")
+	private.Set(reflect.New(private.Type().Elem()))
+	e.Open(ctx, ephemeral.OpenRequest{Config: config}, resp)
+
+	return resp
+}
+
+func testAccGarageObjectEphemeralResourceConfig() string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-object-ephemeral"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_object" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket       = garage_bucket.test.global_alias
+  key          = "bootstrap.json"
+  content      = jsonencode({ token = "bootstrap" })
+  content_type = "application/json"
+}
+
+ephemeral "garage_object" "test" {
+  bucket = garage_object.test.bucket
+  key    = garage_object.test.key
+}
+
+provider "echo" {
+  data = ephemeral.garage_object.test
+}
+
+resource "echo" "test" {}
+`, os.Getenv("GARAGE_ACCESS_KEY"))
+}
@@ -0,0 +1,232 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &GarageObjectHeadDataSource{}
+
+// GarageObjectHeadDataSource reads the headers of an object without
+// downloading its body.
+type GarageObjectHeadDataSource struct {
+	s3Client    *s3.Client
+	s3Endpoint  string
+	s3AccessKey string
+}
+
+type GarageObjectHeadDataSourceModel struct {
+	Bucket       types.String `tfsdk:"bucket"`
+	Key          types.String `tfsdk:"key"`
+	AllowMissing types.Bool   `tfsdk:"allow_missing"`
+	Exists       types.Bool   `tfsdk:"exists"`
+
+	ETag          types.String `tfsdk:"etag"`
+	ContentLength types.Int64  `tfsdk:"content_length"`
+	ContentType   types.String `tfsdk:"content_type"`
+	CacheControl  types.String `tfsdk:"cache_control"`
+	LastModified  types.String `tfsdk:"last_modified"`
+	Metadata      types.Map    `tfsdk:"metadata"`
+	ID            types.String `tfsdk:"id"`
+
+	ChecksumSHA256 types.String `tfsdk:"checksum_sha256"`
+	ChecksumCRC32  types.String `tfsdk:"checksum_crc32"`
+	ChecksumSource types.String `tfsdk:"checksum_source"`
+
+	Override *S3OverrideModel `tfsdk:"override"`
+}
+
+func NewGarageObjectHeadDataSource() datasource.DataSource {
+	return &GarageObjectHeadDataSource{}
+}
+
+func (d *GarageObjectHeadDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_object_head"
+}
+
+func (d *GarageObjectHeadDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Retrieves the metadata of an object in a Garage bucket without downloading its content",
+		Attributes: map[string]schema.Attribute{
+			"bucket": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the bucket containing the object",
+			},
+			"key": schema.StringAttribute{
+				Required:    true,
+				Description: "Key (name) of the object in the bucket",
+			},
+			"allow_missing": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Set exists to false instead of failing when the object does not exist. Defaults to false",
+			},
+			"override": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "S3 credentials and endpoint to use for this read instead of the provider-level ones",
+				Attributes: map[string]schema.Attribute{
+					"access_key": schema.StringAttribute{
+						Required:    true,
+						Sensitive:   true,
+						Description: "S3 access key",
+					},
+					"secret_key": schema.StringAttribute{
+						Required:    true,
+						Sensitive:   true,
+						Description: "S3 secret key",
+					},
+					"endpoint": schema.StringAttribute{
+						Optional:    true,
+						Description: "S3 API endpoint. Defaults to the provider endpoints.s3",
+					},
+				},
+			},
+			"exists": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the object exists. Only false when allow_missing is set, the other attributes are then null",
+			},
+			"etag": schema.StringAttribute{
+				Computed:    true,
+				Description: "ETag of the object",
+			},
+			"content_length": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Size of the object in bytes",
+			},
+			"content_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "MIME type of the object",
+			},
+			"cache_control": schema.StringAttribute{
+				Computed:    true,
+				Description: "Cache-Control header of the object",
+			},
+			"last_modified": schema.StringAttribute{
+				Computed:    true,
+				Description: "Last modification time of the object, in RFC 3339 format",
+			},
+			"metadata": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "User-defined metadata for the object",
+			},
+			"checksum_sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "Hex-encoded SHA-256 checksum of the object content, null unless Garage reports it",
+			},
+			"checksum_crc32": schema.StringAttribute{
+				Computed:    true,
+				Description: "Hex-encoded CRC32 checksum of the object content, null unless Garage reports it",
+			},
+			"checksum_source": schema.StringAttribute{
+				Computed:    true,
+				Description: "server when Garage reports checksums of the object, null otherwise. The body is never downloaded to compute them",
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier (bucket/key)",
+			},
+		},
+	}
+}
+
+func (d *GarageObjectHeadDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			"Expected *GarageProviderModel",
+		)
+		return
+	}
+
+	// A missing client is only an error for reads without an override
+	d.s3Client = providerData.S3Client
+	d.s3Endpoint = providerData.Endpoints.S3.ValueString()
+	d.s3AccessKey = providerData.AccessKey.ValueString()
+}
+
+func (d *GarageObjectHeadDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config GarageObjectHeadDataSourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	s3Client, _ := overrideS3Client(config.Override, d.s3Client, d.s3Endpoint)
+	if s3Client == nil {
+		resp.Diagnostics.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 or override.endpoint for object operations",
+		)
+		return
+	}
+
+	config.ID = types.StringValue(objectID(config.Bucket.ValueString(), config.Key.ValueString()))
+
+	headOutput, err := s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(config.Bucket.ValueString()),
+		Key:          aws.String(config.Key.ValueString()),
+		ChecksumMode: s3types.ChecksumModeEnabled,
+	})
+	if isObjectNotFound(err) && config.AllowMissing.ValueBool() {
+		tflog.Debug(ctx, "Object does not exist", map[string]interface{}{
+			"bucket": config.Bucket.ValueString(),
+			"key":    config.Key.ValueString(),
+		})
+
+		config.Exists = types.BoolValue(false)
+		config.ETag = types.StringNull()
+		config.ContentLength = types.Int64Null()
+		config.ContentType = types.StringNull()
+		config.CacheControl = types.StringNull()
+		config.LastModified = types.StringNull()
+		config.Metadata = types.MapNull(types.StringType)
+		config.ChecksumSHA256 = types.StringNull()
+		config.ChecksumCRC32 = types.StringNull()
+		config.ChecksumSource = types.StringNull()
+
+		diags = resp.State.Set(ctx, &config)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Retrieve Object",
+			"Could not read object metadata from Garage: "+objectErrorDetail(overrideAccessKey(config.Override, d.s3AccessKey), config.Bucket.ValueString(), false, err),
+		)
+		return
+	}
+
+	config.Exists = types.BoolValue(true)
+	config.ETag = types.StringPointerValue(headOutput.ETag)
+	config.ContentLength = types.Int64PointerValue(headOutput.ContentLength)
+	config.ContentType = objectContentTypeValue(headOutput.ContentType)
+	config.CacheControl = types.StringPointerValue(headOutput.CacheControl)
+	config.LastModified = lastModifiedValue(headOutput.LastModified)
+	config.Metadata = objectMetadataValue(headOutput.Metadata)
+
+	var ok bool
+	config.ChecksumSHA256, config.ChecksumCRC32, ok = serverChecksumValues(headOutput.ChecksumSHA256, headOutput.ChecksumCRC32)
+	config.ChecksumSource = types.StringNull()
+	if ok {
+		config.ChecksumSource = types.StringValue(checksumSourceServer)
+	}
+
+	diags = resp.State.Set(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGarageObjectHeadDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageObjectHeadDataSourceConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.garage_object_head.test", "exists", "true"),
+					resource.TestCheckResourceAttrPair("data.garage_object_head.test", "etag", "garage_object.test", "etag"),
+					resource.TestCheckResourceAttr("data.garage_object_head.test", "content_length", "5"),
+					resource.TestCheckResourceAttr("data.garage_object_head.test", "content_type", "text/plain"),
+					resource.TestCheckResourceAttr("data.garage_object_head.test", "cache_control", "no-cache"),
+					resource.TestCheckResourceAttr("data.garage_object_head.test", "metadata.owner", "ops"),
+					resource.TestCheckResourceAttr("data.garage_object_head.missing", "exists", "false"),
+					resource.TestCheckNoResourceAttr("data.garage_object_head.missing", "etag"),
+				),
+			},
+		},
+	})
+}
+
+func TestGarageObjectHeadDataSourceRead(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		allowMissing bool
+		wantExists   bool
+		wantError    string
+	}{
+		{name: "existing object", status: http.StatusOK, wantExists: true},
+		{name: "missing object", status: http.StatusNotFound, allowMissing: true},
+		{name: "missing object not allowed", status: http.StatusNotFound, wantError: "Failed to Retrieve Object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodHead {
+					t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+				}
+				w.Header().Set("ETag", `"5d41402abc4b2a76b9719d911017c592"`)
+				w.Header().Set("Content-Length", "5")
+				w.Header().Set("Content-Type", "text/plain")
+				w.Header().Set("X-Amz-Meta-Owner", "ops")
+				w.Header().Set("X-Amz-Checksum-Crc32", "NhCmhg==")
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			d := &GarageObjectHeadDataSource{s3Client: testS3Client(server.URL)}
+			resp := testDataSourceRead(t, d, map[string]tftypes.Value{
+				"bucket":        tftypes.NewValue(tftypes.String, "bucket"),
+				"key":           tftypes.NewValue(tftypes.String, "key.txt"),
+				"allow_missing": tftypes.NewValue(tftypes.Bool, tt.allowMissing),
+			})
+
+			if tt.wantError != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantError {
+					t.Fatalf("Expected %q error, got %v", tt.wantError, resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var state GarageObjectHeadDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			if state.Exists.ValueBool() != tt.wantExists {
+				t.Errorf("Expected exists %v, got %s", tt.wantExists, state.Exists)
+			}
+			if state.ID.ValueString() != "bucket/key.txt" {
+				t.Errorf("Expected id bucket/key.txt, got %s", state.ID)
+			}
+			if !tt.wantExists {
+				if !state.ETag.IsNull() || !state.Metadata.IsNull() || !state.ChecksumSource.IsNull() {
+					t.Errorf("Expected null attributes for a missing object, got %+v", state)
+				}
+				return
+			}
+			if state.ContentLength.ValueInt64() != 5 || state.ContentType.ValueString() != "text/plain" {
+				t.Errorf("Unexpected headers: %+v", state)
+			}
+			if owner, ok := state.Metadata.Elements()["owner"]; !ok || owner.String() != `"ops"` {
+				t.Errorf("Expected owner metadata, got %s", state.Metadata)
+			}
+			if state.ChecksumSource.ValueString() != "server" || state.ChecksumCRC32.ValueString() != "3610a686" || !state.ChecksumSHA256.IsNull() {
+				t.Errorf("Expected the server crc32 checksum only, got %s %s (%s)", state.ChecksumCRC32, state.ChecksumSHA256, state.ChecksumSource)
+			}
+		})
+	}
+}
+
+func testAccGarageObjectHeadDataSourceConfig() string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-object-head"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_object" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket        = garage_bucket.test.global_alias
+  key           = "head.txt"
+  content       = "hello"
+  content_type  = "text/plain"
+  cache_control = "no-cache"
+  metadata = {
+    owner = "ops"
+  }
+}
+
+data "garage_object_head" "test" {
+  bucket = garage_object.test.bucket
+  key    = garage_object.test.key
+}
+
+data "garage_object_head" "missing" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket        = garage_bucket.test.global_alias
+  key           = "missing.txt"
+  allow_missing = true
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"))
+}
@@ -4,36 +4,139 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash/crc32"
 	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
 )
 
 var _ resource.Resource = &GarageObjectResource{}
 var _ resource.ResourceWithImportState = &GarageObjectResource{}
+var _ resource.ResourceWithIdentity = &GarageObjectResource{}
+var _ resource.ResourceWithValidateConfig = &GarageObjectResource{}
+var _ resource.ResourceWithModifyPlan = &GarageObjectResource{}
+
+// objectRenameDeleteAttempts bounds the attempts to delete an object at its
+// previous key after rename_via_copy copied it to the new one.
+const objectRenameDeleteAttempts = 3
+
+// objectRenameDeleteRetryDelay is the wait before the second attempt to
+// delete a renamed object at its previous key, doubled for each further
+// attempt.
+var objectRenameDeleteRetryDelay = time.Second
+
+// sourceURLTimeout bounds the download of source_url, so that a stalled
+// server fails the plan or apply instead of hanging it. It is a variable so
+// tests can shorten it.
+var sourceURLTimeout = 10 * time.Minute
+
+// maxDriftContentSize is the largest body of an object changed outside of
+// Terraform that Read stores in content or content_base64. It is a variable
+// so tests can shorten it.
+var maxDriftContentSize int64 = 1 << 20
+
+// Values of content_storage. With contentStorageHash, the content is given
+// through content_wo and only its SHA-256 is kept in the state.
+const (
+	contentStorageLiteral = "literal"
+	contentStorageHash    = "hash"
+)
 
 type GarageObjectResource struct {
-	s3Client *s3.Client
+	s3Client    objectAPI
+	s3Endpoint  string
+	s3AccessKey string
+
+	skipChecksumHeaders  bool
+	contentTypeOverrides map[string]string
 }
 
 type GarageObjectResourceModel struct {
-	Bucket      types.String `tfsdk:"bucket"`
-	Key         types.String `tfsdk:"key"`
-	Source      types.String `tfsdk:"source"`
-	Content     types.String `tfsdk:"content"`
-	ContentType types.String `tfsdk:"content_type"`
-	ETag        types.String `tfsdk:"etag"`
-	ID          types.String `tfsdk:"id"`
+	Bucket    types.String `tfsdk:"bucket"`
+	Key       types.String `tfsdk:"key"`
+	Source    types.String `tfsdk:"source"`
+	SourceURL types.String `tfsdk:"source_url"`
+	Content   types.String `tfsdk:"content"`
+	// ContentBase64 holds binary content, decoded before the upload
+	ContentBase64 types.String `tfsdk:"content_base64"`
+	ContentType   types.String `tfsdk:"content_type"`
+	ETag          types.String `tfsdk:"etag"`
+	ID            types.String `tfsdk:"id"`
+
+	Override *S3OverrideModel `tfsdk:"override"`
+
+	ContentEncoding    types.String `tfsdk:"content_encoding"`
+	ContentDisposition types.String `tfsdk:"content_disposition"`
+	ContentLanguage    types.String `tfsdk:"content_language"`
+	CacheControl       types.String `tfsdk:"cache_control"`
+	WebsiteRedirect    types.String `tfsdk:"website_redirect"`
+	Metadata           types.Map    `tfsdk:"metadata"`
+
+	ChecksumAlgorithm types.String `tfsdk:"checksum_algorithm"`
+	ChecksumSHA256    types.String `tfsdk:"checksum_sha256"`
+	ChecksumCRC32     types.String `tfsdk:"checksum_crc32"`
+	ChecksumCRC32C    types.String `tfsdk:"checksum_crc32c"`
+	ChecksumSHA1      types.String `tfsdk:"checksum_sha1"`
+	LastModified      types.String `tfsdk:"last_modified"`
+	S3URI             types.String `tfsdk:"s3_uri"`
+	URL               types.String `tfsdk:"url"`
+
+	SourceURLChecksum types.String `tfsdk:"source_url_checksum"`
+	ContentMD5        types.String `tfsdk:"content_md5"`
+
+	Overwrite      types.Bool `tfsdk:"overwrite"`
+	AdoptExisting  types.Bool `tfsdk:"adopt_existing"`
+	RetainOnDelete types.Bool `tfsdk:"retain_on_delete"`
+	RenameViaCopy  types.Bool `tfsdk:"rename_via_copy"`
+
+	ContentWO        types.String `tfsdk:"content_wo"`
+	ContentWOVersion types.Int64  `tfsdk:"content_wo_version"`
+	ContentStorage   types.String `tfsdk:"content_storage"`
+	ContentSHA256    types.String `tfsdk:"content_sha256"`
+}
+
+type GarageObjectIdentityModel struct {
+	Bucket types.String `tfsdk:"bucket"`
+	Key    types.String `tfsdk:"key"`
 }
 
 func NewGarageObjectResource() resource.Resource {
@@ -42,6 +145,8 @@ func NewGarageObjectResource() resource.Resource {
 
 func (r *GarageObjectResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_object"
+	// Changing key moves the object in place, see Update
+	resp.ResourceBehavior.MutableIdentity = true
 }
 
 func (r *GarageObjectResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -50,29 +155,221 @@ func (r *GarageObjectResource) Schema(_ context.Context, _ resource.SchemaReques
 		Attributes: map[string]schema.Attribute{
 			"bucket": schema.StringAttribute{
 				Required:    true,
-				Description: "Name of the bucket to store the object",
+				Description: "Name of the bucket to store the object. Changing it forces a new resource",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"key": schema.StringAttribute{
 				Required:    true,
-				Description: "Name of the object in the bucket",
+				Description: "Name of the object in the bucket. Must not be empty, be longer than 1024 bytes or start with /. Changing it forces a new resource unless rename_via_copy is set",
+				Validators: []validator.String{
+					validators.ObjectKey(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(
+						renameViaCopyDisabled,
+						"Changing key forces a new resource unless rename_via_copy is set.",
+						"Changing `key` forces a new resource unless `rename_via_copy` is set.",
+					),
+				},
 			},
 			"source": schema.StringAttribute{
 				Optional:    true,
-				Description: "Path to a file that will be uploaded",
+				Description: "Path to a file that will be uploaded. At most one of source, content, content_base64, content_wo or source_url can be set. Without any of them the object body is not managed, as after an import, and the object must already exist",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("content"), path.MatchRoot("content_wo"), path.MatchRoot("source_url")),
+				},
+			},
+			"source_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "HTTP(S) URL to download the object content from. Redirects are followed, and the download fails after 10 minutes. At most one of source, content, content_base64, content_wo or source_url can be set",
+			},
+			"source_url_checksum": schema.StringAttribute{
+				Optional:    true,
+				Description: "Expected hex-encoded SHA-256 of the content downloaded from source_url. The apply fails without uploading on mismatch, and changing it uploads the object again",
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("source_url")),
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[0-9a-f]{64}$`), "must be a lowercase hex-encoded SHA-256"),
+				},
+			},
+			"content_md5": schema.StringAttribute{
+				Optional:    true,
+				Description: "Base64-encoded MD5 digest of the object content, sent as the Content-MD5 header so that Garage rejects corrupted uploads. The ETag returned for single-part uploads is also checked against it",
+				Validators: []validator.String{
+					validators.ContentMD5(),
+				},
 			},
 			"content": schema.StringAttribute{
 				Optional:    true,
 				Sensitive:   true,
-				Description: "Literal string value to use as object content",
+				Description: "Literal string value to use as object content. At most one of source, content, content_base64, content_wo or source_url can be set. An empty string creates a zero-byte object (e.g. a folder/ placeholder)",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("content_wo"), path.MatchRoot("source_url")),
+				},
+			},
+			"content_base64": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Base64-encoded object content, for binary objects such as the result of filebase64(). At most one of source, content, content_base64, content_wo or source_url can be set. The value is kept in the state, prefer source for large files",
+				Validators: []validator.String{
+					validators.Base64(),
+					stringvalidator.ConflictsWith(path.MatchRoot("source"), path.MatchRoot("content"), path.MatchRoot("content_wo"), path.MatchRoot("source_url")),
+				},
+			},
+			"content_wo": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+				Description: "Write-only literal string value to use as object content. It is uploaded but never stored in the state, requires Terraform 1.11 or later, and is only uploaded again when content_wo_version changes, " +
+					"or when its hash changes with content_storage = \"hash\". Requires content_wo_version unless content_storage is hash",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("source_url")),
+				},
+			},
+			"content_wo_version": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Version of content_wo. Change it to upload a new content_wo",
+				Validators: []validator.Int64{
+					int64validator.AlsoRequires(path.MatchRoot("content_wo")),
+				},
+			},
+			"content_storage": schema.StringAttribute{
+				Optional: true,
+				Description: "How the object content is tracked in the state: literal, or hash to store only content_sha256. With hash, the content is set through content_wo, " +
+					"which needs no content_wo_version, and is uploaded again whenever its hash changes. The content cannot be recovered from the state, " +
+					"and the hash of short or guessable content can be brute-forced. Changing it does not replace or upload the object when the content is the same. Defaults to literal",
+				Validators: []validator.String{
+					stringvalidator.OneOf(contentStorageLiteral, contentStorageHash),
+				},
+			},
+			"content_sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "Hex-encoded SHA-256 of content_wo when content_storage is hash, compared with the configured content to detect changes. Null otherwise",
 			},
 			"content_type": schema.StringAttribute{
 				Optional:    true,
 				Computed:    true,
-				Description: "MIME type of the object",
+				Description: "MIME type of the object. When omitted, it is detected from the extension of key (or source), falling back to application/octet-stream for files and text/plain for content",
+			},
+			"content_encoding": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Content-Encoding header of the object (e.g. gzip for pre-compressed assets)",
+			},
+			"content_disposition": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Content-Disposition header of the object (e.g. attachment; filename=\"report.pdf\")",
+			},
+			"content_language": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Content-Language header of the object (e.g. en-US)",
+			},
+			"cache_control": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Cache-Control header of the object (e.g. max-age=3600)",
+			},
+			"website_redirect": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "URL or absolute path that requests for the object through the website endpoint are redirected to (x-amz-website-redirect-location)",
+			},
+			"metadata": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Description: "User-defined metadata of the object, stored as x-amz-meta-* headers. Keys are case-insensitive",
 			},
 			"etag": schema.StringAttribute{
 				Computed:    true,
-				Description: "ETag of the object",
+				Description: "ETag of the object. Known at plan time for objects defined with content",
+				PlanModifiers: []planmodifier.String{
+					contentETagPlanModifier{},
+				},
+			},
+			"override": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "S3 credentials and endpoint to use for this object instead of the provider-level ones",
+				Attributes: map[string]schema.Attribute{
+					"access_key": schema.StringAttribute{
+						Required:    true,
+						Sensitive:   true,
+						Description: "S3 access key",
+					},
+					"secret_key": schema.StringAttribute{
+						Required:    true,
+						Sensitive:   true,
+						Description: "S3 secret key",
+					},
+					"endpoint": schema.StringAttribute{
+						Optional:    true,
+						Description: "S3 API endpoint. Defaults to the provider endpoints.s3",
+					},
+				},
+			},
+			"overwrite": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Whether creating the resource may overwrite an object that already exists at the key. When false, creation fails (or adopts the object, see adopt_existing) if the key is taken. Defaults to true",
+			},
+			"adopt_existing": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "When overwrite is false and the object already exists, adopt it into the state instead of failing. Later changes are uploaded as usual. Defaults to false",
+			},
+			"retain_on_delete": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Keep the object in the bucket when the resource is destroyed or replaced, only removing it from the state. Defaults to false",
+			},
+			"rename_via_copy": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				Description: "When key changes within the same bucket, copy the object server-side to the new key then delete it at the previous one, instead of uploading it again. " +
+					"Headers and metadata are kept unless they change too. If the previous key cannot be deleted, the object is left there with a warning. Defaults to false",
+			},
+			"last_modified": schema.StringAttribute{
+				Computed:    true,
+				Description: "Last modification time of the object, in RFC 3339 format",
+			},
+			"s3_uri": schema.StringAttribute{
+				Computed:    true,
+				Description: "S3 URI of the object (s3://bucket/key)",
+			},
+			"url": schema.StringAttribute{
+				Computed:    true,
+				Description: "HTTP URL of the object on the configured S3 endpoint, using path-style addressing, with the key percent-encoded",
+			},
+			"checksum_algorithm": schema.StringAttribute{
+				Optional: true,
+				Description: "Checksum Garage verifies the uploaded content against: CRC32, CRC32C, SHA1 or SHA256, or none to send no checksum for Garage versions without checksum support. " +
+					"Defaults to SHA256, or none with skip_checksum_headers. Changing it uploads the object again",
+				Validators: []validator.String{
+					stringvalidator.OneOf(checksumAlgorithms...),
+				},
+			},
+			"checksum_sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "Hex-encoded SHA-256 checksum of the object content",
+			},
+			"checksum_crc32": schema.StringAttribute{
+				Computed:    true,
+				Description: "Hex-encoded CRC32 checksum of the object content",
+			},
+			"checksum_crc32c": schema.StringAttribute{
+				Computed:    true,
+				Description: "Hex-encoded CRC32C checksum of the object content, when checksum_algorithm is CRC32C or Garage reports it",
+			},
+			"checksum_sha1": schema.StringAttribute{
+				Computed:    true,
+				Description: "Hex-encoded SHA-1 checksum of the object content, when checksum_algorithm is SHA1 or Garage reports it",
 			},
 			"id": schema.StringAttribute{
 				Computed:    true,
@@ -82,6 +379,215 @@ func (r *GarageObjectResource) Schema(_ context.Context, _ resource.SchemaReques
 	}
 }
 
+func (r *GarageObjectResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"bucket": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Name of the bucket containing the object",
+			},
+			"key": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Name of the object in the bucket",
+			},
+		},
+	}
+}
+
+func (r *GarageObjectResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	resp.Diagnostics.Append(validateContentStorage(ctx, req.Config)...)
+
+	var source types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("source"), &source)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Interpolated paths are only known at apply time
+	if source.IsNull() || source.IsUnknown() {
+		return
+	}
+
+	absPath, size, err := checkSourceFile(source.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("source"),
+			"Invalid Source File",
+			fmt.Sprintf("Unable to use %s (resolved to %s) as object source: %s", source.ValueString(), absPath, err),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "Source file will be uploaded in a single part", map[string]interface{}{
+		"source": absPath,
+		"size":   size,
+	})
+}
+
+// validateContentStorage checks the content attributes against
+// content_storage: with hash, the content must come from content_wo, which
+// needs content_wo_version otherwise.
+func validateContentStorage(ctx context.Context, config tfsdk.Config) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var data GarageObjectResourceModel
+	diags.Append(config.GetAttribute(ctx, path.Root("content_storage"), &data.ContentStorage)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("content"), &data.Content)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("content_base64"), &data.ContentBase64)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("source"), &data.Source)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("source_url"), &data.SourceURL)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("content_wo"), &data.ContentWO)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("content_wo_version"), &data.ContentWOVersion)...)
+	if diags.HasError() || data.ContentStorage.IsUnknown() {
+		return diags
+	}
+
+	if data.ContentStorage.ValueString() != contentStorageHash {
+		if !data.ContentWO.IsNull() && data.ContentWOVersion.IsNull() {
+			diags.AddAttributeError(
+				path.Root("content_wo"),
+				"Invalid Attribute Combination",
+				"content_wo_version must be set with content_wo, so that a new content is only uploaded when it changes. "+
+					"Alternatively, set content_storage = \"hash\" to upload it whenever its hash changes.",
+			)
+		}
+		return diags
+	}
+
+	for _, field := range []struct {
+		name  string
+		value types.String
+	}{
+		{"content", data.Content},
+		{"content_base64", data.ContentBase64},
+		{"source", data.Source},
+		{"source_url", data.SourceURL},
+	} {
+		if !field.value.IsNull() {
+			diags.AddAttributeError(
+				path.Root(field.name),
+				"Invalid Attribute Combination",
+				fmt.Sprintf("%s cannot be set with content_storage = \"hash\", which only applies to content_wo: "+
+					"Terraform stores configured values in the state as they are, only write-only ones are left out. "+
+					"Move the content to content_wo, or remove content_storage.", field.name),
+			)
+		}
+	}
+	if data.ContentWO.IsNull() && !diags.HasError() {
+		diags.AddAttributeError(
+			path.Root("content_storage"),
+			"Missing Object Content",
+			"content_storage = \"hash\" requires content_wo, whose SHA-256 is stored in the state instead of the content.",
+		)
+	}
+
+	return diags
+}
+
+func (r *GarageObjectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check when destroying
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// An object moved to another key in Update is planned with its new
+	// identity
+	if !req.State.Raw.IsNull() && resp.Identity != nil {
+		var plan GarageObjectIdentityModel
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("bucket"), &plan.Bucket)...)
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("key"), &plan.Key)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !plan.Bucket.IsUnknown() && !plan.Key.IsUnknown() {
+			resp.Diagnostics.Append(resp.Identity.Set(ctx, plan)...)
+		}
+	}
+
+	// Only imported objects may manage their headers without a body
+	if req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(requireContentSource(ctx, req.Config)...)
+	}
+
+	resp.Diagnostics.Append(planContentSHA256(ctx, req, resp)...)
+
+	var algorithm types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("checksum_algorithm"), &algorithm)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The provider settings are only known once configured
+	if r.skipChecksumHeaders && !algorithm.IsNull() && !algorithm.IsUnknown() && algorithm.ValueString() != checksumAlgorithmNone {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("checksum_algorithm"),
+			"Incompatible Checksum Settings",
+			fmt.Sprintf("checksum_algorithm is %s but skip_checksum_headers is enabled in the provider, so no checksum would be sent. "+
+				"Remove checksum_algorithm, set it to %q, or disable skip_checksum_headers.", algorithm.ValueString(), checksumAlgorithmNone),
+		)
+	}
+}
+
+// requireContentSource reports an error when config sets none of the
+// attributes an object body is uploaded from. Unknown values count as set.
+func requireContentSource(ctx context.Context, config tfsdk.Config) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var data GarageObjectResourceModel
+	diags.Append(config.GetAttribute(ctx, path.Root("content"), &data.Content)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("content_base64"), &data.ContentBase64)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("content_wo"), &data.ContentWO)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("content_wo_version"), &data.ContentWOVersion)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("content_storage"), &data.ContentStorage)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("source"), &data.Source)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("source_url"), &data.SourceURL)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if data.ContentWO.IsNull() && !hasContentSource(data) {
+		diags.AddError(
+			"Missing Object Content",
+			"None of source, content, content_base64, content_wo or source_url is set, so there is no body to upload. "+
+				"Set one of them to create the object, or import an existing object to only manage its headers.",
+		)
+	}
+
+	return diags
+}
+
+// planContentSHA256 plans content_sha256 as the hash of the configured
+// content_wo when content_storage is hash, which shows a content change as a
+// difference with the hash in state. It stays unknown for an object that may
+// be adopted as it is.
+func planContentSHA256(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var data GarageObjectResourceModel
+	diags.Append(req.Config.GetAttribute(ctx, path.Root("content_storage"), &data.ContentStorage)...)
+	diags.Append(req.Config.GetAttribute(ctx, path.Root("content_wo"), &data.ContentWO)...)
+	diags.Append(req.Plan.GetAttribute(ctx, path.Root("overwrite"), &data.Overwrite)...)
+	diags.Append(req.Plan.GetAttribute(ctx, path.Root("adopt_existing"), &data.AdoptExisting)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	planned := types.StringNull()
+	switch {
+	case data.ContentStorage.IsUnknown():
+		planned = types.StringUnknown()
+	case !isHashedObject(data):
+		// Only hashed objects store the hash of their content
+	case data.ContentWO.IsUnknown():
+		planned = types.StringUnknown()
+	case req.State.Raw.IsNull() && !data.Overwrite.ValueBool() && data.AdoptExisting.ValueBool():
+		planned = types.StringUnknown()
+	default:
+		sum := sha256.Sum256([]byte(data.ContentWO.ValueString()))
+		planned = types.StringValue(hex.EncodeToString(sum[:]))
+	}
+
+	diags.Append(resp.Plan.SetAttribute(ctx, path.Root("content_sha256"), planned)...)
+	return diags
+}
+
 func (r *GarageObjectResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -93,27 +599,15 @@ func (r *GarageObjectResource) Configure(_ context.Context, req resource.Configu
 		return
 	}
 
-	s3Endpoint := providerData.Endpoints.S3.ValueString()
-	if s3Endpoint == "" {
-		resp.Diagnostics.AddError(
-			"Missing S3 Endpoint",
-			"S3 endpoint must be configured in endpoints.s3 for object operations",
-		)
-		return
+	// A missing client is only an error for resources without an override,
+	// see useOverride. A nil *s3.Client must stay a nil objectAPI for it.
+	if providerData.S3Client != nil {
+		r.s3Client = providerData.S3Client
 	}
-
-	// Create S3 client with BaseEndpoint (new method)
-	r.s3Client = s3.NewFromConfig(aws.Config{
-		Region: "garage",
-		Credentials: credentials.NewStaticCredentialsProvider(
-			providerData.AccessKey.ValueString(),
-			providerData.SecretKey.ValueString(),
-			"",
-		),
-	}, func(o *s3.Options) {
-		o.BaseEndpoint = aws.String(s3Endpoint)
-		o.UsePathStyle = true // Important for S3-compatible storage like Garage
-	})
+	r.s3Endpoint = providerData.Endpoints.S3.ValueString()
+	r.s3AccessKey = providerData.AccessKey.ValueString()
+	r.skipChecksumHeaders = providerData.SkipChecksumHeaders.ValueBool()
+	r.contentTypeOverrides = providerData.contentTypeOverrides()
 }
 
 func (r *GarageObjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -124,56 +618,57 @@ func (r *GarageObjectResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	// Prepare object content
-	var body io.Reader
-	var contentType string
+	// Write-only values are only available in the configuration
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("content_wo"), &plan.ContentWO)...)
+	resp.Diagnostics.Append(r.useOverride(plan.Override)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	if !plan.Source.IsNull() {
-		file, err := os.Open(plan.Source.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError("File Read Error", err.Error())
-			return
-		}
-		defer func(file *os.File) {
-			_ = file.Close()
-		}(file)
+	noClobber := !plan.Overwrite.ValueBool()
 
-		if plan.ContentType.IsNull() {
-			contentType = "application/octet-stream"
-		} else {
-			contentType = plan.ContentType.ValueString()
+	if noClobber {
+		// Garage may not support conditional writes, so check first and
+		// only rely on If-None-Match to catch concurrent creations
+		headOutput, err := r.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket:       aws.String(plan.Bucket.ValueString()),
+			Key:          aws.String(plan.Key.ValueString()),
+			ChecksumMode: s3types.ChecksumModeEnabled,
+		})
+		if err == nil {
+			if plan.AdoptExisting.ValueBool() {
+				resp.Diagnostics.Append(r.adopt(ctx, &plan, headOutput)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				r.setLocation(&plan)
+				plan.ContentWO = types.StringNull()
+
+				diags = resp.State.Set(ctx, plan)
+				resp.Diagnostics.Append(diags...)
+				resp.Diagnostics.Append(setObjectIdentity(ctx, resp.Identity, plan)...)
+				return
+			}
+
+			resp.Diagnostics.AddError("Object Already Exists", objectExistsDetail(plan))
+			return
 		}
-	} else if !plan.Content.IsNull() {
-		body = strings.NewReader(plan.Content.ValueString())
-		if plan.ContentType.IsNull() {
-			contentType = "text/plain"
-		} else {
-			contentType = plan.ContentType.ValueString()
+		if !isObjectNotFound(err) {
+			resp.Diagnostics.AddError("Object Read Failed", objectErrorDetail(r.s3AccessKey, plan.Bucket.ValueString(), false, err))
+			return
 		}
-	} else {
-		resp.Diagnostics.AddError("Missing Content", "Either source or content must be specified")
-		return
 	}
 
-	// Upload object
-	putOutput, err := r.s3Client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(plan.Bucket.ValueString()),
-		Key:         aws.String(plan.Key.ValueString()),
-		Body:        body,
-		ContentType: aws.String(contentType),
-	})
-	if err != nil {
-		resp.Diagnostics.AddError("Object Upload Failed", err.Error())
+	resp.Diagnostics.Append(r.upload(ctx, &plan, noClobber)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-
-	// Set computed values
-	plan.ID = types.StringValue(plan.Bucket.ValueString() + "/" + plan.Key.ValueString())
-	plan.ETag = types.StringValue(*putOutput.ETag)
-	plan.ContentType = types.StringValue(contentType)
+	r.setLocation(&plan)
+	plan.ContentWO = types.StringNull()
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setObjectIdentity(ctx, resp.Identity, plan)...)
 }
 
 func (r *GarageObjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -184,29 +679,237 @@ func (r *GarageObjectResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
+	// The identity does not depend on the remote object, set it first so that
+	// it is also present when the object is gone
+	resp.Diagnostics.Append(setObjectIdentity(ctx, resp.Identity, state)...)
+	resp.Diagnostics.Append(r.useOverride(state.Override)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Check if object exists
 	headOutput, err := r.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
-		Bucket: aws.String(state.Bucket.ValueString()),
-		Key:    aws.String(state.Key.ValueString()),
+		Bucket:       aws.String(state.Bucket.ValueString()),
+		Key:          aws.String(state.Key.ValueString()),
+		ChecksumMode: s3types.ChecksumModeEnabled,
 	})
-	if err != nil {
+	if isObjectNotFound(err) {
+		tflog.Debug(ctx, "Object no longer exists, removing from state", map[string]interface{}{
+			"bucket": state.Bucket.ValueString(),
+			"key":    state.Key.ValueString(),
+		})
 		resp.State.RemoveResource(ctx)
 		return
 	}
+	if err != nil {
+		resp.Diagnostics.AddError("Object Read Failed", fmt.Sprintf("Unable to read object %s, got error: %s",
+			state.ID.ValueString(), objectErrorDetail(r.s3AccessKey, state.Bucket.ValueString(), false, err)))
+		return
+	}
+
+	// Write-only content cannot be compared: an out-of-band change of the
+	// ETag clears content_wo_version so that the next plan uploads it again
+	writeOnly := isWriteOnlyObject(state)
+	if writeOnly && !state.ETag.IsNull() && aws.ToString(headOutput.ETag) != state.ETag.ValueString() {
+		tflog.Debug(ctx, "Write-only object changed outside of Terraform", map[string]interface{}{
+			"bucket": state.Bucket.ValueString(),
+			"key":    state.Key.ValueString(),
+			"etag":   aws.ToString(headOutput.ETag),
+		})
+		state.ContentWOVersion = types.Int64Null()
+	}
+
+	// Hashed content is not in the state either, but its hash is: an
+	// out-of-band change of the ETag stores the hash of the remote body, so
+	// that the next plan shows it differs from the configured content
+	if isHashedObject(state) && !state.ETag.IsNull() && aws.ToString(headOutput.ETag) != state.ETag.ValueString() {
+		tflog.Debug(ctx, "Hashed object changed outside of Terraform", map[string]interface{}{
+			"bucket": state.Bucket.ValueString(),
+			"key":    state.Key.ValueString(),
+			"etag":   aws.ToString(headOutput.ETag),
+		})
+		checksums, err := r.remoteChecksums(ctx, state.Bucket.ValueString(), state.Key.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Object Read Failed", objectErrorDetail(r.s3AccessKey, state.Bucket.ValueString(), false, err))
+			return
+		}
+		state.ContentSHA256 = types.StringValue(hex.EncodeToString(checksums.sha256))
+	}
 
 	// Update state with current metadata
 	state.ETag = types.StringValue(*headOutput.ETag)
+	state.LastModified = lastModifiedValue(headOutput.LastModified)
+	r.setLocation(&state)
 	if headOutput.ContentType != nil {
 		state.ContentType = types.StringValue(*headOutput.ContentType)
 	}
+	state.ContentEncoding = refreshedHeader(state.ContentEncoding, headOutput.ContentEncoding)
+	state.ContentDisposition = refreshedHeader(state.ContentDisposition, headOutput.ContentDisposition)
+	state.ContentLanguage = refreshedHeader(state.ContentLanguage, headOutput.ContentLanguage)
+	state.CacheControl = refreshedHeader(state.CacheControl, headOutput.CacheControl)
+	state.WebsiteRedirect = types.StringPointerValue(headOutput.WebsiteRedirectLocation)
+	state.Metadata = refreshedMetadata(state.Metadata, headOutput.Metadata)
+
+	// Refresh checksums when Garage reports them, keep the values computed
+	// at upload time otherwise. Write-only objects never store a hash of
+	// their content.
+	if v, ok := checksumHex(headOutput.ChecksumSHA256); ok && !writeOnly {
+		state.ChecksumSHA256 = types.StringValue(v)
+	}
+	if v, ok := checksumHex(headOutput.ChecksumCRC32); ok && !writeOnly {
+		state.ChecksumCRC32 = types.StringValue(v)
+	}
+	if v, ok := checksumHex(headOutput.ChecksumCRC32C); ok && !writeOnly {
+		state.ChecksumCRC32C = types.StringValue(v)
+	}
+	if v, ok := checksumHex(headOutput.ChecksumSHA1); ok && !writeOnly {
+		state.ChecksumSHA1 = types.StringValue(v)
+	}
+
+	// Imported objects have no checksums yet: compute them from the remote
+	// body once, they are kept in state afterwards
+	if !writeOnly && (state.ChecksumSHA256.IsNull() || state.ChecksumCRC32.IsNull()) {
+		checksums, err := r.remoteChecksums(ctx, state.Bucket.ValueString(), state.Key.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Object Read Failed", objectErrorDetail(r.s3AccessKey, state.Bucket.ValueString(), false, err))
+			return
+		}
+		if state.ChecksumSHA256.IsNull() {
+			state.ChecksumSHA256 = types.StringValue(hex.EncodeToString(checksums.sha256))
+		}
+		if state.ChecksumCRC32.IsNull() {
+			state.ChecksumCRC32 = types.StringValue(hex.EncodeToString(checksums.crc32))
+		}
+	}
+
+	// Detect out-of-band overwrites of inline content by comparing the MD5 of
+	// the content in state with the remote ETag
+	if inline, ok := inlineContent(state); ok {
+		remoteETag := aws.ToString(headOutput.ETag)
+		if isMultipartETag(remoteETag) {
+			tflog.Debug(ctx, "Skipping content drift detection for multipart ETag", map[string]interface{}{
+				"bucket": state.Bucket.ValueString(),
+				"key":    state.Key.ValueString(),
+				"etag":   remoteETag,
+			})
+		} else if remoteETag != contentETag(string(inline)) {
+			tflog.Debug(ctx, "Object content changed outside of Terraform", map[string]interface{}{
+				"bucket": state.Bucket.ValueString(),
+				"key":    state.Key.ValueString(),
+				"etag":   remoteETag,
+			})
+
+			// Only the difference with the configuration matters: large and,
+			// for content, binary bodies are replaced with a sentinel rather
+			// than stored in the state
+			body, bodyBase64 := types.StringNull(), types.StringNull()
+			if aws.ToInt64(headOutput.ContentLength) <= maxDriftContentSize {
+				content, ok, err := r.getContent(ctx, state.Bucket.ValueString(), state.Key.ValueString(), maxDriftContentSize)
+				if err != nil {
+					resp.Diagnostics.AddError("Object Read Failed", objectErrorDetail(r.s3AccessKey, state.Bucket.ValueString(), false, err))
+					return
+				}
+				if ok {
+					body, bodyBase64 = objectBodyValues(content)
+				}
+			}
+			if state.ContentBase64.IsNull() {
+				state.Content = driftedContent(body, remoteETag)
+			} else {
+				state.ContentBase64 = driftedContent(bodyBase64, remoteETag)
+			}
+		}
+	}
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 }
 
 func (r *GarageObjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Treat update as delete + create
-	r.Create(ctx, resource.CreateRequest{Plan: req.Plan}, (*resource.CreateResponse)(resp))
+	var plan GarageObjectResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("content_wo"), &plan.ContentWO)...)
+	resp.Diagnostics.Append(r.useOverride(plan.Override)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state GarageObjectResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	start := time.Now()
+	// With rename_via_copy, a new key within the bucket is reached by copying
+	// the object rather than uploading it again
+	renamed := plan.RenameViaCopy.ValueBool() && plan.Bucket.Equal(state.Bucket) && !plan.Key.Equal(state.Key)
+
+	sameContent := plan.Content.Equal(state.Content) && plan.ContentBase64.Equal(state.ContentBase64)
+	if isHashedObject(plan) || isHashedObject(state) {
+		// Switching content_storage keeps the body when its hash is the same
+		sameContent = contentSHA256(plan) != "" && contentSHA256(plan) == contentSHA256(state)
+	}
+
+	// Without a content source the body is left as is, e.g. after an import
+	// or when content was removed from the configuration
+	contentChanged := hasContentSource(plan) && (!sameContent ||
+		!plan.Source.Equal(state.Source) ||
+		!plan.SourceURL.Equal(state.SourceURL) || !plan.SourceURLChecksum.Equal(state.SourceURLChecksum) ||
+		!plan.ContentWOVersion.Equal(state.ContentWOVersion) || !plan.ContentMD5.Equal(state.ContentMD5) ||
+		!plan.ChecksumAlgorithm.Equal(state.ChecksumAlgorithm))
+	contentChanged = contentChanged || !plan.Bucket.Equal(state.Bucket) || (!plan.Key.Equal(state.Key) && !renamed)
+
+	switch {
+	case !contentChanged && !renamed && !headersChanged(plan, state, r.contentTypeOverrides):
+		// Only Terraform-side settings changed, nothing to do remotely
+		keepObjectComputedValues(&plan, state)
+	case !contentChanged:
+		// Rewrite the headers or move the object server-side instead of
+		// re-uploading the body
+		err := r.copyWithHeaders(ctx, &plan, state)
+		if err == nil {
+			tflog.Debug(ctx, "Updated object with CopyObject", map[string]interface{}{
+				"key":          plan.Key.ValueString(),
+				"previous_key": state.Key.ValueString(),
+				"duration":     time.Since(start).String(),
+			})
+			break
+		}
+
+		tflog.Warn(ctx, "CopyObject failed, falling back to a full upload", map[string]interface{}{
+			"key":   plan.Key.ValueString(),
+			"error": err.Error(),
+		})
+		fallthrough
+	default:
+		// Re-upload the object, overwriting the previous version
+		resp.Diagnostics.Append(r.upload(ctx, &plan, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		tflog.Debug(ctx, "Re-uploaded object", map[string]interface{}{
+			"key":      plan.Key.ValueString(),
+			"duration": time.Since(start).String(),
+		})
+	}
+	if renamed {
+		// The object is at its new key, failing to delete the previous one
+		// must not fail the apply and lose track of it
+		resp.Diagnostics.Append(r.deletePreviousKey(ctx, plan, state)...)
+	}
+	r.setLocation(&plan)
+	plan.ContentWO = types.StringNull()
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setObjectIdentity(ctx, resp.Identity, plan)...)
 }
 
 func (r *GarageObjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -217,34 +920,1115 @@ func (r *GarageObjectResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 
+	if state.RetainOnDelete.ValueBool() {
+		tflog.Info(ctx, "Retaining object in bucket, removing it from state only", map[string]interface{}{
+			"bucket": state.Bucket.ValueString(),
+			"key":    state.Key.ValueString(),
+		})
+		return
+	}
+
+	resp.Diagnostics.Append(r.useOverride(state.Override)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	_, err := r.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(state.Bucket.ValueString()),
 		Key:    aws.String(state.Key.ValueString()),
 	})
+	if isObjectNotFound(err) {
+		// Removed out-of-band, possibly with its bucket: nothing left to delete
+		tflog.Debug(ctx, "Object already deleted", map[string]interface{}{
+			"bucket": state.Bucket.ValueString(),
+			"key":    state.Key.ValueString(),
+			"error":  err.Error(),
+		})
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Object Deletion Failed", err.Error())
+		resp.Diagnostics.AddError("Object Deletion Failed", objectErrorDetail(r.s3AccessKey, state.Bucket.ValueString(), true, err))
 		return
 	}
 }
 
-func (r *GarageObjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Import format: bucket/key (same as AWS provider)
-	// Supports keys with slashes by treating everything after first / as the key
-	parts := strings.SplitN(req.ID, "/", 2)
-
-	if len(parts) != 2 {
-		resp.Diagnostics.AddError(
-			"Invalid Import ID",
-			fmt.Sprintf("Expected import ID in format 'bucket/key', got: %s", req.ID),
-		)
-		return
-	}
+// upload puts the object described by plan and sets its computed values.
+// It is shared by Create and Update. When noClobber is set, the upload is
+// conditional on the key not existing yet.
+func (r *GarageObjectResource) upload(ctx context.Context, plan *GarageObjectResourceModel, noClobber bool) diag.Diagnostics {
+	var diags diag.Diagnostics
 
-	bucket := parts[0]
-	key := parts[1]
+	// Prepare object content
+	var body io.ReadSeeker
+	var contentType string
 
-	// Set the state attributes
+	if !plan.Source.IsNull() {
+		file, err := os.Open(plan.Source.ValueString())
+		if err != nil {
+			diags.AddError("File Read Error", err.Error())
+			return diags
+		}
+		defer func(file *os.File) {
+			_ = file.Close()
+		}(file)
+
+		body = file
+	} else if !plan.SourceURL.IsNull() {
+		file, err := downloadToTempFile(ctx, plan.SourceURL.ValueString())
+		if err != nil {
+			diags.AddError("Source Download Failed", err.Error())
+			return diags
+		}
+		defer func(file *os.File) {
+			_ = file.Close()
+			_ = os.Remove(file.Name())
+		}(file)
+
+		body = file
+	} else if !plan.ContentWO.IsNull() {
+		body = strings.NewReader(plan.ContentWO.ValueString())
+	} else if inline, ok := inlineContent(*plan); ok {
+		body = bytes.NewReader(inline)
+	} else {
+		// Imported objects only manage their headers, there is no body to
+		// upload in their place
+		diags.AddError(
+			"Missing Object Content",
+			fmt.Sprintf("Object %s cannot be uploaded to bucket %s: none of content, content_wo, source or source_url is set. "+
+				"Set one of them to manage the object body.", plan.Key.ValueString(), plan.Bucket.ValueString()),
+		)
+		return diags
+	}
+	contentType = resolveContentType(*plan, r.contentTypeOverrides)
+
+	checksums, err := computeChecksums(body)
+	if err != nil {
+		diags.AddError("File Read Error", err.Error())
+		return diags
+	}
+
+	if !plan.SourceURLChecksum.IsNull() && !plan.SourceURLChecksum.IsUnknown() {
+		if got := hex.EncodeToString(checksums.sha256); got != plan.SourceURLChecksum.ValueString() {
+			diags.AddAttributeError(
+				path.Root("source_url_checksum"),
+				"Source Checksum Mismatch",
+				fmt.Sprintf("Content downloaded from %s has SHA-256 %s, expected %s. The object was not uploaded.",
+					plan.SourceURL.ValueString(), got, plan.SourceURLChecksum.ValueString()),
+			)
+			return diags
+		}
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(plan.Bucket.ValueString()),
+		Key:         aws.String(plan.Key.ValueString()),
+		Body:        body,
+		ContentType: aws.String(contentType),
+
+		ContentEncoding:    knownStringPointer(plan.ContentEncoding),
+		ContentDisposition: knownStringPointer(plan.ContentDisposition),
+		ContentLanguage:    knownStringPointer(plan.ContentLanguage),
+
+		CacheControl:            knownStringPointer(plan.CacheControl),
+		WebsiteRedirectLocation: knownStringPointer(plan.WebsiteRedirect),
+		Metadata:                knownMetadata(plan.Metadata),
+	}
+	// Let Garage verify the body it receives
+	setChecksumHeader(input, plan.ChecksumAlgorithm.ValueString(), r.skipChecksumHeaders, checksums)
+	if noClobber {
+		input.IfNoneMatch = aws.String("*")
+	}
+	input.ContentMD5 = knownStringPointer(plan.ContentMD5)
+
+	// Upload object
+	putOutput, err := r.s3Client.PutObject(ctx, input)
+	if isPreconditionFailed(err) {
+		diags.AddError("Object Already Exists", objectExistsDetail(*plan))
+		return diags
+	}
+	if err != nil {
+		diags.AddError("Object Upload Failed", objectErrorDetail(r.s3AccessKey, plan.Bucket.ValueString(), true, err))
+		return diags
+	}
+
+	if err := verifyContentMD5(plan.ContentMD5, aws.ToString(putOutput.ETag)); err != nil {
+		diags.AddAttributeError(
+			path.Root("content_md5"),
+			"Object Integrity Error",
+			fmt.Sprintf("Object %s was uploaded to bucket %s but %s. The stored object may not match the expected content.",
+				plan.Key.ValueString(), plan.Bucket.ValueString(), err),
+		)
+		return diags
+	}
+
+	// PutObject does not return the modification time
+	headOutput, err := r.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(plan.Bucket.ValueString()),
+		Key:    aws.String(plan.Key.ValueString()),
+	})
+	if err != nil {
+		diags.AddError("Object Read Failed", fmt.Sprintf("Unable to read uploaded object, got error: %s", err))
+		return diags
+	}
+
+	// Set computed values
+	plan.ID = types.StringValue(objectID(plan.Bucket.ValueString(), plan.Key.ValueString()))
+	plan.ETag = types.StringValue(aws.ToString(putOutput.ETag))
+	plan.LastModified = lastModifiedValue(headOutput.LastModified)
+	plan.ContentType = types.StringValue(contentType)
+	plan.ContentEncoding = types.StringPointerValue(knownStringPointer(plan.ContentEncoding))
+	plan.ContentDisposition = types.StringPointerValue(knownStringPointer(plan.ContentDisposition))
+	plan.ContentLanguage = types.StringPointerValue(knownStringPointer(plan.ContentLanguage))
+	setUploadedHeaders(plan)
+	setObjectChecksums(plan, checksums)
+
+	return diags
+}
+
+// verifyContentMD5 checks the ETag returned for an upload against the
+// expected base64 MD5 digest. Multipart ETags are not the MD5 of the body and
+// are not checked.
+func verifyContentMD5(contentMD5 types.String, etag string) error {
+	if contentMD5.IsNull() || contentMD5.IsUnknown() || isMultipartETag(etag) {
+		return nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(contentMD5.ValueString())
+	if err != nil {
+		return fmt.Errorf("content_md5 is not valid base64: %w", err)
+	}
+
+	if want := `"` + hex.EncodeToString(raw) + `"`; etag != want {
+		return fmt.Errorf("the returned ETag %s does not match %s from content_md5", etag, want)
+	}
+
+	return nil
+}
+
+// hasContentSource reports whether the object body is managed through one of
+// content, content_wo, source or source_url.
+func hasContentSource(data GarageObjectResourceModel) bool {
+	return !data.Content.IsNull() || !data.ContentBase64.IsNull() || !data.ContentWOVersion.IsNull() ||
+		!data.Source.IsNull() || !data.SourceURL.IsNull() || isHashedObject(data)
+}
+
+// isHashedObject reports whether content_storage is hash, so that only the
+// SHA-256 of content_wo is kept in the state.
+func isHashedObject(data GarageObjectResourceModel) bool {
+	return data.ContentStorage.ValueString() == contentStorageHash
+}
+
+// contentSHA256 returns the hex-encoded SHA-256 of the content of data: the
+// stored or planned content_sha256 of a hashed object, or the hash of its
+// inline content. It is empty when unknown or without inline content.
+func contentSHA256(data GarageObjectResourceModel) string {
+	if isHashedObject(data) {
+		return data.ContentSHA256.ValueString()
+	}
+	inline, ok := inlineContent(data)
+	if !ok {
+		return ""
+	}
+	sum := sha256.Sum256(inline)
+	return hex.EncodeToString(sum[:])
+}
+
+// inlineContent returns the body set through content or content_base64, and
+// whether one of them is set. Invalid base64 is rejected at plan time and
+// reported as unset.
+func inlineContent(data GarageObjectResourceModel) ([]byte, bool) {
+	if !data.Content.IsNull() && !data.Content.IsUnknown() {
+		return []byte(data.Content.ValueString()), true
+	}
+	if data.ContentBase64.IsNull() || data.ContentBase64.IsUnknown() {
+		return nil, false
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(data.ContentBase64.ValueString())
+	if err != nil {
+		return nil, false
+	}
+	return raw, true
+}
+
+// isWriteOnlyObject reports whether the object content comes from
+// content_wo, which content_wo_version is required with, and is not hashed.
+func isWriteOnlyObject(data GarageObjectResourceModel) bool {
+	return !data.ContentWOVersion.IsNull() && data.Content.IsNull() && data.ContentBase64.IsNull() &&
+		data.Source.IsNull() && data.SourceURL.IsNull() && !isHashedObject(data)
+}
+
+// copyWithHeaders copies the object from its key in state to the planned
+// one, which Garage handles without transferring the body. The headers are
+// replaced with the planned ones when they changed, e.g. on a self-copy, and
+// copied along otherwise. The content and its checksums are unchanged.
+func (r *GarageObjectResource) copyWithHeaders(ctx context.Context, plan *GarageObjectResourceModel, state GarageObjectResourceModel) error {
+	input := &s3.CopyObjectInput{
+		Bucket:            aws.String(plan.Bucket.ValueString()),
+		Key:               aws.String(plan.Key.ValueString()),
+		CopySource:        aws.String(copySource(state.Bucket.ValueString(), state.Key.ValueString())),
+		MetadataDirective: s3types.MetadataDirectiveCopy,
+	}
+	replaceHeaders := headersChanged(*plan, state, r.contentTypeOverrides)
+	contentType := resolveContentType(*plan, r.contentTypeOverrides)
+	if replaceHeaders {
+		input.MetadataDirective = s3types.MetadataDirectiveReplace
+		input.ContentType = aws.String(contentType)
+		input.ContentEncoding = knownStringPointer(plan.ContentEncoding)
+		input.ContentDisposition = knownStringPointer(plan.ContentDisposition)
+		input.ContentLanguage = knownStringPointer(plan.ContentLanguage)
+		input.CacheControl = knownStringPointer(plan.CacheControl)
+		input.WebsiteRedirectLocation = knownStringPointer(plan.WebsiteRedirect)
+		input.Metadata = knownMetadata(plan.Metadata)
+	}
+
+	copyOutput, err := r.s3Client.CopyObject(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	keepObjectComputedValues(plan, state)
+	plan.ID = types.StringValue(objectID(plan.Bucket.ValueString(), plan.Key.ValueString()))
+	if replaceHeaders {
+		plan.ContentType = types.StringValue(contentType)
+		plan.ContentEncoding = types.StringPointerValue(knownStringPointer(plan.ContentEncoding))
+		plan.ContentDisposition = types.StringPointerValue(knownStringPointer(plan.ContentDisposition))
+		plan.ContentLanguage = types.StringPointerValue(knownStringPointer(plan.ContentLanguage))
+		setUploadedHeaders(plan)
+	}
+	if copyOutput.CopyObjectResult != nil {
+		if copyOutput.CopyObjectResult.ETag != nil {
+			plan.ETag = types.StringValue(*copyOutput.CopyObjectResult.ETag)
+		}
+		if copyOutput.CopyObjectResult.LastModified != nil {
+			plan.LastModified = lastModifiedValue(copyOutput.CopyObjectResult.LastModified)
+		}
+	}
+
+	return nil
+}
+
+// renameViaCopyDisabled replaces the object when its key changes, unless
+// rename_via_copy lets Update move it within the bucket.
+func renameViaCopyDisabled(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	var rename types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("rename_via_copy"), &rename)...)
+	resp.RequiresReplace = !rename.ValueBool()
+}
+
+// deletePreviousKey deletes the object at its key in state once rename_via_copy
+// put it at the planned key, retrying on errors. When it keeps failing, the
+// object is left at both keys and a warning is returned, since the copy at the
+// new key is already tracked in state.
+func (r *GarageObjectResource) deletePreviousKey(ctx context.Context, plan, state GarageObjectResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	bucket, key := state.Bucket.ValueString(), state.Key.ValueString()
+	delay := objectRenameDeleteRetryDelay
+	for attempt := 1; ; attempt++ {
+		_, err := r.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		if err == nil || isObjectNotFound(err) {
+			return diags
+		}
+		if attempt >= objectRenameDeleteAttempts {
+			diags.AddWarning(
+				"Previous Object Not Deleted",
+				fmt.Sprintf("Object %s was copied to %s in bucket %s, but deleting it at its previous key failed, so it is still there. "+
+					"Delete %s by hand once the cause is fixed.\n\n%s",
+					key, plan.Key.ValueString(), bucket, objectS3URI(bucket, key), objectErrorDetail(r.s3AccessKey, bucket, true, err)),
+			)
+			return diags
+		}
+
+		tflog.Debug(ctx, "Renamed object not deleted at its previous key, retrying", map[string]interface{}{
+			"bucket":  bucket,
+			"key":     key,
+			"attempt": attempt,
+			"error":   err.Error(),
+		})
+		select {
+		case <-ctx.Done():
+			diags.AddWarning(
+				"Previous Object Not Deleted",
+				fmt.Sprintf("Object %s was copied to %s in bucket %s, but the apply was interrupted before deleting it at its previous key. Delete %s by hand.",
+					key, plan.Key.ValueString(), bucket, objectS3URI(bucket, key)),
+			)
+			return diags
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// headersChanged reports whether the planned object headers differ from the
+// ones in state.
+func headersChanged(plan, state GarageObjectResourceModel, overrides map[string]string) bool {
+	return resolveContentType(plan, overrides) != state.ContentType.ValueString() ||
+		!types.StringPointerValue(knownStringPointer(plan.ContentEncoding)).Equal(state.ContentEncoding) ||
+		!types.StringPointerValue(knownStringPointer(plan.ContentDisposition)).Equal(state.ContentDisposition) ||
+		!types.StringPointerValue(knownStringPointer(plan.ContentLanguage)).Equal(state.ContentLanguage) ||
+		!types.StringPointerValue(knownStringPointer(plan.CacheControl)).Equal(state.CacheControl) ||
+		!types.StringPointerValue(knownStringPointer(plan.WebsiteRedirect)).Equal(state.WebsiteRedirect) ||
+		!metadataValue(knownMetadata(plan.Metadata)).Equal(metadataValue(knownMetadata(state.Metadata)))
+}
+
+// setUploadedHeaders sets the headers without a default to null when they
+// were left unset, after an upload or a copy.
+func setUploadedHeaders(plan *GarageObjectResourceModel) {
+	plan.CacheControl = types.StringPointerValue(knownStringPointer(plan.CacheControl))
+	plan.WebsiteRedirect = types.StringPointerValue(knownStringPointer(plan.WebsiteRedirect))
+	if plan.Metadata.IsUnknown() {
+		plan.Metadata = types.MapNull(types.StringType)
+	}
+}
+
+// refreshedHeader returns the header value read from Garage, keeping the one
+// in state when they only differ in case so that Garage normalizing the
+// capitalization does not show as drift.
+func refreshedHeader(prior types.String, remote *string) types.String {
+	if remote != nil && strings.EqualFold(prior.ValueString(), *remote) && !prior.IsNull() {
+		return prior
+	}
+	return types.StringPointerValue(remote)
+}
+
+// refreshedMetadata returns the metadata read from Garage, which lowercases
+// the keys, spelled as in state when they only differ in case.
+func refreshedMetadata(prior types.Map, remote map[string]string) types.Map {
+	if len(remote) == 0 {
+		// Keep an explicitly empty map
+		if !prior.IsNull() && !prior.IsUnknown() && len(prior.Elements()) == 0 {
+			return prior
+		}
+		return types.MapNull(types.StringType)
+	}
+
+	priorKeys := map[string]string{}
+	for k := range knownMetadata(prior) {
+		priorKeys[strings.ToLower(k)] = k
+	}
+
+	metadata := make(map[string]string, len(remote))
+	for k, v := range remote {
+		if priorKey, ok := priorKeys[strings.ToLower(k)]; ok {
+			k = priorKey
+		}
+		metadata[k] = v
+	}
+
+	return metadataValue(metadata)
+}
+
+// knownMetadata returns the metadata as a Go map, or nil when it is null or
+// unknown.
+func knownMetadata(v types.Map) map[string]string {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+
+	metadata := make(map[string]string, len(v.Elements()))
+	for k, e := range v.Elements() {
+		if s, ok := e.(types.String); ok {
+			metadata[k] = s.ValueString()
+		}
+	}
+	return metadata
+}
+
+// metadataValue converts metadata to a map value, null when there is none.
+func metadataValue(metadata map[string]string) types.Map {
+	if metadata == nil {
+		return types.MapNull(types.StringType)
+	}
+
+	elements := make(map[string]attr.Value, len(metadata))
+	for k, v := range metadata {
+		elements[k] = types.StringValue(v)
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
+
+// keepObjectComputedValues copies the values computed at upload time from
+// state into plan, for updates that do not touch the body.
+func keepObjectComputedValues(plan *GarageObjectResourceModel, state GarageObjectResourceModel) {
+	plan.ID = state.ID
+	plan.ETag = state.ETag
+	plan.ChecksumSHA256 = state.ChecksumSHA256
+	plan.ChecksumCRC32 = state.ChecksumCRC32
+	plan.ChecksumCRC32C = state.ChecksumCRC32C
+	plan.ChecksumSHA1 = state.ChecksumSHA1
+	plan.LastModified = state.LastModified
+
+	if plan.ContentType.IsUnknown() {
+		plan.ContentType = state.ContentType
+	}
+	if plan.ContentEncoding.IsUnknown() {
+		plan.ContentEncoding = state.ContentEncoding
+	}
+	if plan.ContentDisposition.IsUnknown() {
+		plan.ContentDisposition = state.ContentDisposition
+	}
+	if plan.ContentLanguage.IsUnknown() {
+		plan.ContentLanguage = state.ContentLanguage
+	}
+	if plan.CacheControl.IsUnknown() {
+		plan.CacheControl = state.CacheControl
+	}
+	if plan.WebsiteRedirect.IsUnknown() {
+		plan.WebsiteRedirect = state.WebsiteRedirect
+	}
+	if plan.Metadata.IsUnknown() {
+		plan.Metadata = state.Metadata
+	}
+}
+
+// copySource builds the URL-escaped CopySource of an object. The SDK sends it
+// as is, unlike the Key of requests, which it escapes itself.
+func copySource(bucket, key string) string {
+	return escapeObjectKey(bucket) + "/" + escapeObjectKey(key)
+}
+
+// resolveContentType returns the content type to upload the object with: the
+// configured one, else the one detected from the key or source extension,
+// else a default depending on whether the content comes from a file.
+func resolveContentType(plan GarageObjectResourceModel, overrides map[string]string) string {
+	// An explicit content_type always wins over the detected one
+	if !plan.ContentType.IsNull() && !plan.ContentType.IsUnknown() {
+		return plan.ContentType.ValueString()
+	}
+	source := plan.Source.ValueString()
+	if !plan.SourceURL.IsNull() {
+		if u, err := url.Parse(plan.SourceURL.ValueString()); err == nil {
+			source = u.Path
+		}
+	}
+	if detected := detectContentType(plan.Key.ValueString(), source, overrides); detected != "" {
+		return detected
+	}
+	if !plan.Source.IsNull() || !plan.SourceURL.IsNull() {
+		return "application/octet-stream"
+	}
+	return "text/plain"
+}
+
+// adopt fills the computed values of plan from an existing object instead of
+// uploading it. Configured values are kept as planned so that differences
+// with the remote object show up as drift on the next plan.
+func (r *GarageObjectResource) adopt(ctx context.Context, plan *GarageObjectResourceModel, headOutput *s3.HeadObjectOutput) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	tflog.Debug(ctx, "Adopting existing object", map[string]interface{}{
+		"bucket": plan.Bucket.ValueString(),
+		"key":    plan.Key.ValueString(),
+	})
+
+	plan.ID = types.StringValue(objectID(plan.Bucket.ValueString(), plan.Key.ValueString()))
+	plan.ETag = types.StringValue(aws.ToString(headOutput.ETag))
+	plan.LastModified = lastModifiedValue(headOutput.LastModified)
+
+	if plan.ContentType.IsUnknown() {
+		plan.ContentType = types.StringPointerValue(headOutput.ContentType)
+	}
+	if plan.ContentEncoding.IsUnknown() {
+		plan.ContentEncoding = types.StringPointerValue(headOutput.ContentEncoding)
+	}
+	if plan.ContentDisposition.IsUnknown() {
+		plan.ContentDisposition = types.StringPointerValue(headOutput.ContentDisposition)
+	}
+	if plan.ContentLanguage.IsUnknown() {
+		plan.ContentLanguage = types.StringPointerValue(headOutput.ContentLanguage)
+	}
+	if plan.CacheControl.IsUnknown() {
+		plan.CacheControl = types.StringPointerValue(headOutput.CacheControl)
+	}
+	if plan.WebsiteRedirect.IsUnknown() {
+		plan.WebsiteRedirect = types.StringPointerValue(headOutput.WebsiteRedirectLocation)
+	}
+	if plan.Metadata.IsUnknown() {
+		plan.Metadata = refreshedMetadata(types.MapNull(types.StringType), headOutput.Metadata)
+	}
+
+	if isWriteOnlyObject(*plan) {
+		setObjectChecksums(plan, objectChecksums{})
+		return diags
+	}
+
+	checksums, err := r.remoteChecksums(ctx, plan.Bucket.ValueString(), plan.Key.ValueString())
+	if err != nil {
+		diags.AddError("Object Read Failed", objectErrorDetail(r.s3AccessKey, plan.Bucket.ValueString(), false, err))
+		return diags
+	}
+	setObjectChecksums(plan, checksums)
+
+	return diags
+}
+
+// objectExistsDetail is the diagnostic detail for a no-clobber creation that
+// found the key taken.
+func objectExistsDetail(plan GarageObjectResourceModel) string {
+	return fmt.Sprintf("Object %s already exists in bucket %s and overwrite is false. "+
+		"Import it with: terraform import <address> %s/%s, or set adopt_existing = true.",
+		plan.Key.ValueString(), plan.Bucket.ValueString(), plan.Bucket.ValueString(), plan.Key.ValueString())
+}
+
+// isObjectNotFound reports whether err means the object (or its bucket) does
+// not exist, including bare 404 responses without an error code.
+func isObjectNotFound(err error) bool {
+	var notFound *s3types.NotFound
+	var noSuchKey *s3types.NoSuchKey
+	if errors.As(err, &notFound) || errors.As(err, &noSuchKey) {
+		return true
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NotFound", "NoSuchKey", "NoSuchBucket":
+			return true
+		}
+	}
+
+	var respErr *smithyhttp.ResponseError
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound
+}
+
+// isPreconditionFailed reports whether err is the answer to a failed
+// conditional request.
+func isPreconditionFailed(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "PreconditionFailed"
+}
+
+// contentETagPlanModifier predicts the ETag of objects defined with content,
+// so that references to it do not show as known after apply. Objects
+// uploaded from a file, or that may be adopted as they are, stay unknown.
+type contentETagPlanModifier struct{}
+
+func (m contentETagPlanModifier) Description(_ context.Context) string {
+	return "Sets the planned ETag to the MD5 of content or content_base64 when it is known."
+}
+
+func (m contentETagPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m contentETagPlanModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	// Nothing to predict on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data GarageObjectResourceModel
+	var overwrite, adoptExisting types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("content"), &data.Content)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("content_base64"), &data.ContentBase64)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("overwrite"), &overwrite)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("adopt_existing"), &adoptExisting)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	content, ok := inlineContent(data)
+	if !ok {
+		return
+	}
+
+	// An adopted object keeps its remote ETag on creation
+	if req.State.Raw.IsNull() && !overwrite.ValueBool() && adoptExisting.ValueBool() {
+		return
+	}
+
+	resp.PlanValue = types.StringValue(contentETag(string(content)))
+}
+
+// checkSourceFile checks that source is a readable regular file and returns
+// its absolute path and size.
+func checkSourceFile(source string) (string, int64, error) {
+	absPath, err := filepath.Abs(source)
+	if err != nil {
+		return source, 0, err
+	}
+
+	info, err := os.Stat(absPath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return absPath, 0, errors.New("file does not exist")
+	case err != nil:
+		return absPath, 0, err
+	case info.IsDir():
+		return absPath, 0, errors.New("path is a directory")
+	}
+
+	file, err := os.Open(absPath)
+	if err != nil {
+		return absPath, 0, fmt.Errorf("file is not readable: %w", err)
+	}
+	_ = file.Close()
+
+	return absPath, info.Size(), nil
+}
+
+// downloadToTempFile downloads sourceURL into a temporary file, so that large
+// bodies are neither buffered in memory nor streamed unseekable to the S3
+// client. The caller must close and remove the file.
+func downloadToTempFile(ctx context.Context, sourceURL string) (*os.File, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The client follows redirects, and the timeout covers reading the body
+	httpClient := &http.Client{Timeout: sourceURLTimeout}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("GET %s returned status %d", sourceURL, resp.StatusCode)
+	}
+
+	file, err := os.CreateTemp("", "garage-object-*")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return nil, fmt.Errorf("unable to download %s: %w", sourceURL, err)
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// webContentTypes are the content types of web assets, by lowercase
+// extension. They take precedence over the mime package, whose table misses
+// some of them and can be overridden by the mime.types file of the host, so
+// that websites get the same content types wherever Terraform runs.
+var webContentTypes = map[string]string{
+	".html":        "text/html; charset=utf-8",
+	".htm":         "text/html; charset=utf-8",
+	".css":         "text/css; charset=utf-8",
+	".js":          "text/javascript; charset=utf-8",
+	".mjs":         "text/javascript; charset=utf-8",
+	".json":        "application/json",
+	".map":         "application/json",
+	".webmanifest": "application/manifest+json",
+	".wasm":        "application/wasm",
+	".txt":         "text/plain; charset=utf-8",
+	".md":          "text/markdown; charset=utf-8",
+	".csv":         "text/csv; charset=utf-8",
+	".xml":         "text/xml; charset=utf-8",
+	".svg":         "image/svg+xml",
+	".png":         "image/png",
+	".jpg":         "image/jpeg",
+	".jpeg":        "image/jpeg",
+	".gif":         "image/gif",
+	".webp":        "image/webp",
+	".avif":        "image/avif",
+	".ico":         "image/x-icon",
+	".woff":        "font/woff",
+	".woff2":       "font/woff2",
+	".ttf":         "font/ttf",
+	".otf":         "font/otf",
+	".pdf":         "application/pdf",
+	".mp4":         "video/mp4",
+	".webm":        "video/webm",
+}
+
+// contentTypeExtensionRegexp matches the extensions of content_type_overrides,
+// as returned by filepath.Ext.
+var contentTypeExtensionRegexp = regexp.MustCompile(`^\.[^./\\]+$`)
+
+// lowerContentTypeOverrides returns overrides by lowercase extension, or an
+// error naming the first key that is not an extension.
+func lowerContentTypeOverrides(overrides map[string]string) (map[string]string, error) {
+	lower := make(map[string]string, len(overrides))
+	for ext, contentType := range overrides {
+		if !contentTypeExtensionRegexp.MatchString(ext) {
+			return nil, fmt.Errorf("invalid extension %q: must start with a dot, e.g. .glb", ext)
+		}
+		lower[strings.ToLower(ext)] = contentType
+	}
+	return lower, nil
+}
+
+// detectContentType guesses the MIME type from the extension of the object
+// key, then of the source file. The content_type_overrides of the provider,
+// by lowercase extension, win over the built-in tables. It returns "" when
+// neither extension is known.
+func detectContentType(key, source string, overrides map[string]string) string {
+	for _, name := range []string{key, source} {
+		if ext := filepath.Ext(name); ext != "" {
+			if contentType, ok := overrides[strings.ToLower(ext)]; ok {
+				return contentType
+			}
+			if contentType, ok := webContentTypes[strings.ToLower(ext)]; ok {
+				return contentType
+			}
+			if contentType := mime.TypeByExtension(ext); contentType != "" {
+				return contentType
+			}
+		}
+	}
+	return ""
+}
+
+// fileContentType returns the MIME type of a file named name, from its
+// extension, or application/octet-stream when it is unknown.
+func fileContentType(name string, overrides map[string]string) string {
+	if contentType := detectContentType(name, "", overrides); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// objectChecksums holds the raw checksums of an object body.
+type objectChecksums struct {
+	sha256 []byte
+	crc32  []byte
+	crc32c []byte
+	sha1   []byte
+}
+
+// checksumAlgorithmNone is the checksum_algorithm sending no checksum.
+const checksumAlgorithmNone = "none"
+
+// checksumAlgorithms are the values of checksum_algorithm.
+var checksumAlgorithms = []string{
+	string(s3types.ChecksumAlgorithmCrc32),
+	string(s3types.ChecksumAlgorithmCrc32c),
+	string(s3types.ChecksumAlgorithmSha1),
+	string(s3types.ChecksumAlgorithmSha256),
+	checksumAlgorithmNone,
+}
+
+// setChecksumHeader sets the checksum of the body Garage verifies on upload:
+// the one of algorithm, SHA-256 when algorithm is empty, or none when it is
+// "none" or skip is set for Garage versions without checksum support.
+func setChecksumHeader(input *s3.PutObjectInput, algorithm string, skip bool, checksums objectChecksums) {
+	if skip || algorithm == checksumAlgorithmNone {
+		return
+	}
+
+	encode := base64.StdEncoding.EncodeToString
+	switch s3types.ChecksumAlgorithm(algorithm) {
+	case s3types.ChecksumAlgorithmCrc32:
+		input.ChecksumCRC32 = aws.String(encode(checksums.crc32))
+	case s3types.ChecksumAlgorithmCrc32c:
+		input.ChecksumCRC32C = aws.String(encode(checksums.crc32c))
+	case s3types.ChecksumAlgorithmSha1:
+		input.ChecksumSHA1 = aws.String(encode(checksums.sha1))
+	case s3types.ChecksumAlgorithmSha256:
+		input.ChecksumSHA256 = aws.String(encode(checksums.sha256))
+	default:
+		// The checksum sent before checksum_algorithm existed
+		input.ChecksumSHA256 = aws.String(encode(checksums.sha256))
+		return
+	}
+	input.ChecksumAlgorithm = s3types.ChecksumAlgorithm(algorithm)
+}
+
+// setObjectChecksums sets the checksum attributes of plan from the checksums
+// of its content. SHA-256 and CRC32 are always set, CRC32C and SHA-1 only
+// when checksum_algorithm selects them, and content_sha256 for hashed
+// objects. Write-only objects never store a hash of their content.
+func setObjectChecksums(plan *GarageObjectResourceModel, checksums objectChecksums) {
+	plan.ChecksumSHA256 = types.StringNull()
+	plan.ChecksumCRC32 = types.StringNull()
+	plan.ChecksumCRC32C = types.StringNull()
+	plan.ChecksumSHA1 = types.StringNull()
+	plan.ContentSHA256 = types.StringNull()
+	if isWriteOnlyObject(*plan) {
+		return
+	}
+
+	plan.ChecksumSHA256 = types.StringValue(hex.EncodeToString(checksums.sha256))
+	if isHashedObject(*plan) {
+		plan.ContentSHA256 = plan.ChecksumSHA256
+	}
+	plan.ChecksumCRC32 = types.StringValue(hex.EncodeToString(checksums.crc32))
+	switch s3types.ChecksumAlgorithm(plan.ChecksumAlgorithm.ValueString()) {
+	case s3types.ChecksumAlgorithmCrc32c:
+		plan.ChecksumCRC32C = types.StringValue(hex.EncodeToString(checksums.crc32c))
+	case s3types.ChecksumAlgorithmSha1:
+		plan.ChecksumSHA1 = types.StringValue(hex.EncodeToString(checksums.sha1))
+	}
+}
+
+// computeChecksums reads body once to compute its checksums and rewinds it
+// so it can be uploaded.
+func computeChecksums(body io.ReadSeeker) (objectChecksums, error) {
+	checksums, err := hashBody(body)
+	if err != nil {
+		return objectChecksums{}, err
+	}
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return objectChecksums{}, err
+	}
+
+	return checksums, nil
+}
+
+// remoteChecksums streams the object body from Garage to compute its
+// checksums without buffering it.
+func (r *GarageObjectResource) remoteChecksums(ctx context.Context, bucket, key string) (objectChecksums, error) {
+	getOutput, err := r.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return objectChecksums{}, err
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(getOutput.Body)
+
+	return hashBody(getOutput.Body)
+}
+
+// hashBody computes the checksums of everything read from body.
+func hashBody(body io.Reader) (objectChecksums, error) {
+	sha := sha256.New()
+	crc := crc32.NewIEEE()
+	crcC := crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	sha1Hash := sha1.New()
+
+	if _, err := io.Copy(io.MultiWriter(sha, crc, crcC, sha1Hash), body); err != nil {
+		return objectChecksums{}, err
+	}
+
+	return objectChecksums{sha256: sha.Sum(nil), crc32: crc.Sum(nil), crc32c: crcC.Sum(nil), sha1: sha1Hash.Sum(nil)}, nil
+}
+
+// checksumHex converts a base64 checksum returned by S3 to hex. Composite
+// checksums of multipart uploads ("<checksum>-<parts>") are not checksums of
+// the whole body and are ignored.
+func checksumHex(v *string) (string, bool) {
+	if v == nil || strings.Contains(*v, "-") {
+		return "", false
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(*v)
+	if err != nil {
+		return "", false
+	}
+
+	return hex.EncodeToString(raw), true
+}
+
+// getContent downloads the object body. It reports false without the body
+// when the body is larger than maxSize.
+func (r *GarageObjectResource) getContent(ctx context.Context, bucket, key string, maxSize int64) ([]byte, bool, error) {
+	getOutput, err := r.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(getOutput.Body)
+
+	content, err := io.ReadAll(io.LimitReader(getOutput.Body, maxSize+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if int64(len(content)) > maxSize {
+		return nil, false, nil
+	}
+
+	return content, true, nil
+}
+
+// driftedContent returns the content or content_base64 value of an object
+// changed outside of Terraform: its body when it could be represented, a
+// sentinel naming its ETag otherwise. Either differs from the configuration,
+// so the object is uploaded again.
+func driftedContent(body types.String, etag string) types.String {
+	if body.IsNull() {
+		return types.StringValue(fmt.Sprintf("<changed outside of Terraform, ETag %s>", etag))
+	}
+	return body
+}
+
+// contentETag returns the ETag S3 computes for a single-part upload of
+// content: the quoted hex MD5 of the body.
+func contentETag(content string) string {
+	sum := md5.Sum([]byte(content))
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// isMultipartETag reports whether the ETag belongs to a multipart upload
+// ("<md5 of part md5s>-<part count>"), which is not the MD5 of the body.
+func isMultipartETag(etag string) bool {
+	return strings.Contains(etag, "-")
+}
+
+// useOverride switches the resource to the S3 client of override when set.
+// Resources are instantiated per operation, so this does not leak to other
+// objects.
+func (r *GarageObjectResource) useOverride(override *S3OverrideModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if override != nil {
+		s3Client, endpoint := overrideS3Client(override, nil, r.s3Endpoint)
+		r.s3Client, r.s3Endpoint = nil, endpoint
+		if s3Client != nil {
+			r.s3Client = s3Client
+		}
+		r.s3AccessKey = overrideAccessKey(override, r.s3AccessKey)
+	}
+	if r.s3Client == nil {
+		diags.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 or override.endpoint for object operations",
+		)
+	}
+
+	return diags
+}
+
+// setLocation sets the computed S3 URI and HTTP URL of the object.
+func (r *GarageObjectResource) setLocation(data *GarageObjectResourceModel) {
+	data.S3URI = types.StringValue(objectS3URI(data.Bucket.ValueString(), data.Key.ValueString()))
+	data.URL = objectURL(r.s3Endpoint, data.Bucket.ValueString(), data.Key.ValueString())
+}
+
+// objectS3URI returns the s3://bucket/key form used by S3 tooling.
+func objectS3URI(bucket, key string) string {
+	return "s3://" + bucket + "/" + key
+}
+
+// objectURL returns the path-style HTTP URL of an object on the S3 endpoint,
+// with the key escaped, or null when no endpoint is configured.
+func objectURL(endpoint, bucket, key string) types.String {
+	if endpoint == "" {
+		return types.StringNull()
+	}
+
+	return types.StringValue(strings.TrimSuffix(endpoint, "/") + "/" + escapeObjectKey(bucket) + "/" + escapeObjectKey(key))
+}
+
+// escapeObjectKey percent-encodes key for a URL path the way S3 does: every
+// byte but unreserved characters and slashes. Unlike url.PathEscape, "+" is
+// escaped too, as some S3 servers decode it as a space in paths.
+func escapeObjectKey(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-_.~/", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// lastModifiedValue formats an object modification time as RFC 3339.
+func lastModifiedValue(t *time.Time) types.String {
+	if t == nil {
+		return types.StringNull()
+	}
+	return types.StringValue(t.UTC().Format(time.RFC3339))
+}
+
+// knownStringPointer returns a pointer to the value, or nil when it is null
+// or unknown (an unset optional+computed attribute).
+func knownStringPointer(v types.String) *string {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+	return v.ValueStringPointer()
+}
+
+// setObjectIdentity sets the resource identity from data. identity is nil
+// when Terraform does not support resource identities.
+func setObjectIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, data GarageObjectResourceModel) diag.Diagnostics {
+	if identity == nil {
+		return nil
+	}
+
+	return identity.Set(ctx, GarageObjectIdentityModel{
+		Bucket: data.Bucket,
+		Key:    data.Key,
+	})
+}
+
+func (r *GarageObjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var bucket, key string
+
+	if req.ID == "" {
+		// Import by identity (import block with an identity attribute)
+		var identity GarageObjectIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		bucket, key = identity.Bucket.ValueString(), identity.Key.ValueString()
+		if err := validators.ValidateObjectKey(key); bucket == "" || err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Import Identity",
+				fmt.Sprintf("Expected a bucket and a valid object key in the import identity, got bucket %q and key %q", bucket, key),
+			)
+			return
+		}
+	} else {
+		var err error
+		bucket, key, err = parseObjectImportID(req.ID)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Import ID",
+				fmt.Sprintf("Expected import ID in format 'bucket/key', got: %s (%s)", req.ID, err),
+			)
+			return
+		}
+	}
+
+	// Set the state attributes
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), bucket)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key"), key)...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), objectID(bucket, key))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("overwrite"), true)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("adopt_existing"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("retain_on_delete"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("rename_via_copy"), false)...)
+}
+
+// objectID returns the ID of the object key of bucket, the inverse of
+// parseObjectImportID.
+func objectID(bucket, key string) string {
+	return bucket + "/" + key
+}
+
+// parseObjectImportID parses an import ID in the format "bucket/key" (same as
+// the AWS provider). Keys may contain slashes: everything after the first one
+// is the key, which must be a valid object key.
+func parseObjectImportID(id string) (bucket, key string, err error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", errors.New("missing bucket")
+	}
+
+	if err := validators.ValidateObjectKey(parts[1]); err != nil {
+		return "", "", err
+	}
+
+	return parts[0], parts[1], nil
 }
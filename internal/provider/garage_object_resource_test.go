@@ -4,11 +4,48 @@
 package provider
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/config"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
 )
 
 func TestAccGarageObjectResource(t *testing.T) {
@@ -24,6 +61,11 @@ func TestAccGarageObjectResource(t *testing.T) {
 					resource.TestCheckResourceAttr("garage_object.test", "content_type", "text/plain"),
 					resource.TestCheckResourceAttrSet("garage_object.test", "etag"),
 					resource.TestCheckResourceAttrSet("garage_object.test", "id"),
+					resource.TestCheckResourceAttr("garage_object.test", "checksum_sha256", "0a3666a0710c08aa6d0de92ce72beeb5b93124cce1bf3701c9d6cdeb543cb73e"),
+					resource.TestCheckResourceAttr("garage_object.test", "checksum_crc32", "ccf1728c"),
+					resource.TestMatchResourceAttr("garage_object.test", "s3_uri", regexp.MustCompile(`^s3://[^/]+/test-object\.txt$`)),
+					resource.TestMatchResourceAttr("garage_object.test", "url", regexp.MustCompile(`^https?://.+/test-object\.txt$`)),
+					resource.TestMatchResourceAttr("garage_object.test", "last_modified", regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`)),
 				),
 			},
 			{
@@ -34,39 +76,3279 @@ func TestAccGarageObjectResource(t *testing.T) {
 			},
 			{
 				Config: testAccGarageObjectResourceConfig("updated-content"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						// The predicted ETag must match the one Garage computes
+						plancheck.ExpectKnownValue("garage_object.test", tfjsonpath.New("etag"),
+							knownvalue.StringExact(`"b5b4e55b4939d16a3c0e3ff6e3a7ecc5"`)),
+					},
+				},
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("garage_object.test", "content", "updated-content"),
+					resource.TestCheckResourceAttr("garage_object.test", "etag", `"b5b4e55b4939d16a3c0e3ff6e3a7ecc5"`),
 				),
 			},
 		},
 	})
 }
 
-func testAccGarageObjectResourceConfig(content string) string {
-	return testAccProviderConfig() + fmt.Sprintf(`
-		resource "garage_bucket" "test" {
-			global_alias = "test-bucket-object"
+func TestAccGarageObjectResource_inPlaceUpdate(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageObjectResourceConfig("original-content"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckGarageObjectContent("garage_object.test", "original-content"),
+				),
+			},
+			{
+				Config: testAccGarageObjectResourceConfig("changed-content"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("garage_object.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_object.test", "content", "changed-content"),
+					resource.TestCheckResourceAttrSet("garage_object.test", "etag"),
+					testAccCheckGarageObjectContent("garage_object.test", "changed-content"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccGarageObjectResource_importAllAttributes(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageObjectResourceConfig_headers(),
+			},
+			// Only the content input cannot be recovered from Garage
+			{
+				ResourceName:            "garage_object.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"content"},
+			},
+		},
+	})
+}
+
+func TestAccGarageObjectResource_identity(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageObjectResourceConfig_headers(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity("garage_object.test", map[string]knownvalue.Check{
+						"bucket": knownvalue.NotNull(),
+						"key":    knownvalue.StringExact("docs/index.html"),
+					}),
+					statecheck.ExpectIdentityValueMatchesState("garage_object.test", tfjsonpath.New("bucket")),
+				},
+			},
+			// The content input cannot be recovered from Garage, so the
+			// imported object plans an update of it
+			{
+				ResourceName:       "garage_object.test",
+				ImportState:        true,
+				ImportStateKind:    resource.ImportBlockWithResourceIdentity,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// TestAccGarageObjectResource_generatedConfig imports an existing bucket and
+// object with import blocks and plans against the configuration written by
+// terraform plan -generate-config-out, kept in testdata/.
+func TestAccGarageObjectResource_generatedConfig(t *testing.T) {
+	const bucket = "test-generate-config"
+
+	configVariables := config.Variables{
+		"admin_endpoint": config.StringVariable(os.Getenv("GARAGE_ADMIN_ENDPOINT")),
+		"s3_endpoint":    config.StringVariable(os.Getenv("GARAGE_S3_ENDPOINT")),
+		"token":          config.StringVariable(os.Getenv("GARAGE_TOKEN")),
+		"access_key":     config.StringVariable(os.Getenv("GARAGE_ACCESS_KEY")),
+		"secret_key":     config.StringVariable(os.Getenv("GARAGE_SECRET_KEY")),
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheckS3(t) },
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_5_0),
+		},
+		Steps: []resource.TestStep{
+			// Importing plans no changes against the generated configuration
+			{
+				PreConfig:                func() { testAccCreateUnmanagedObject(t, bucket, "docs/index.html") },
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				ConfigDirectory:          config.StaticDirectory("testdata/TestAccGarageObjectResource_generatedConfig"),
+				ConfigVariables:          configVariables,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("garage_bucket.imported", plancheck.ResourceActionNoop),
+						plancheck.ExpectResourceAction("garage_object.imported", plancheck.ResourceActionNoop),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_bucket.imported", "website_index_document", "index.html"),
+					resource.TestCheckNoResourceAttr("garage_bucket.imported", "website_error_document"),
+					resource.TestCheckResourceAttr("garage_object.imported", "content_type", "text/html"),
+					resource.TestCheckNoResourceAttr("garage_object.imported", "content"),
+				),
+			},
+			{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				ConfigDirectory:          config.StaticDirectory("testdata/TestAccGarageObjectResource_generatedConfig"),
+				ConfigVariables:          configVariables,
+				PlanOnly:                 true,
+			},
+			// Remove the object before the bucket, which must be empty to be
+			// deleted
+			{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Config: testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "imported" {
+  global_alias           = %q
+  website_enabled        = true
+  website_index_document = "index.html"
+}
+`, bucket),
+			},
+		},
+	})
+}
+
+// testAccCreateUnmanagedObject creates a website bucket the access key can
+// write to and uploads an HTML object, outside of Terraform.
+func testAccCreateUnmanagedObject(t *testing.T, bucket, key string) {
+	ctx := context.Background()
+	c := client.NewClient(os.Getenv("GARAGE_ADMIN_ENDPOINT"), os.Getenv("GARAGE_TOKEN"))
+
+	b, err := c.CreateBucket(ctx, client.CreateBucketRequest{GlobalAlias: aws.String(bucket)})
+	if err != nil {
+		t.Fatalf("Unable to create bucket: %s", err)
+	}
+
+	website := client.UpdateBucketRequest{}
+	website.WebsiteAccess = &struct {
+		Enabled       bool    `json:"enabled"`
+		IndexDocument *string `json:"indexDocument,omitempty"`
+		ErrorDocument *string `json:"errorDocument,omitempty"`
+	}{Enabled: true, IndexDocument: aws.String("index.html")}
+	if _, err := c.UpdateBucket(ctx, b.ID, website); err != nil {
+		t.Fatalf("Unable to configure bucket website: %s", err)
+	}
+
+	if _, err := c.AllowBucketKey(ctx, client.BucketKeyPermRequest{
+		BucketID:    b.ID,
+		AccessKeyID: os.Getenv("GARAGE_ACCESS_KEY"),
+		Permissions: client.Permissions{Read: true, Write: true},
+	}); err != nil {
+		t.Fatalf("Unable to allow access key on bucket: %s", err)
+	}
+
+	if _, err := testAccS3Client().PutObject(ctx, &s3.PutObjectInput{
+		Bucket:       aws.String(bucket),
+		Key:          aws.String(key),
+		Body:         strings.NewReader("<h1>docs</h1>"),
+		ContentType:  aws.String("text/html"),
+		CacheControl: aws.String("max-age=300"),
+		Metadata:     map[string]string{"owner": "docs"},
+	}); err != nil {
+		t.Fatalf("Unable to upload object: %s", err)
+	}
+}
+
+func TestAccGarageObjectResource_noClobber(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Clean create: the key is free
+			{
+				Config: testAccGarageObjectResourceConfig_noClobber(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_object.test", "overwrite", "false"),
+					testAccCheckGarageObjectContent("garage_object.test", "initial"),
+				),
+			},
+			// A second resource on the same key must not clobber it
+			{
+				Config:      testAccGarageObjectResourceConfig_noClobber() + testAccGarageObjectResourceConfig_noClobberCopy(false),
+				ExpectError: regexp.MustCompile("Object Already Exists"),
+			},
+			// ...unless it adopts the existing object
+			{
+				Config: testAccGarageObjectResourceConfig_noClobber() + testAccGarageObjectResourceConfig_noClobberCopy(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("garage_object.copy", "etag", "garage_object.test", "etag"),
+					testAccCheckGarageObjectContent("garage_object.test", "initial"),
+				),
+			},
+		},
+	})
+}
+
+func TestGarageObjectResourceCreate_noClobber(t *testing.T) {
+	tests := []struct {
+		name          string
+		exists        bool
+		adoptExisting bool
+		wantError     string
+		wantPut       bool
+	}{
+		{name: "clean create", wantPut: true},
+		{name: "already exists", exists: true, wantError: "Object Already Exists"},
+		{name: "adopt existing", exists: true, adoptExisting: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var put bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodHead:
+					if !tt.exists && !put {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					w.Header().Set("ETag", `"existing"`)
+					w.Header().Set("Content-Type", "text/plain")
+					w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+				case http.MethodGet:
+					_, _ = w.Write([]byte("existing content"))
+				case http.MethodPut:
+					put = true
+					if r.Header.Get("If-None-Match") != "*" {
+						t.Errorf("Expected If-None-Match: *, got %q", r.Header.Get("If-None-Match"))
+					}
+					w.Header().Set("ETag", `"uploaded"`)
+				default:
+					t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			r := &GarageObjectResource{s3Client: testS3Client(server.URL)}
+			plan := testGarageObjectValue(t, map[string]tftypes.Value{
+				"bucket":         tftypes.NewValue(tftypes.String, "bucket"),
+				"key":            tftypes.NewValue(tftypes.String, "bootstrap.conf"),
+				"content":        tftypes.NewValue(tftypes.String, "new content"),
+				"overwrite":      tftypes.NewValue(tftypes.Bool, false),
+				"adopt_existing": tftypes.NewValue(tftypes.Bool, tt.adoptExisting),
+			})
+
+			resp := testGarageObjectCreate(t, r, plan)
+
+			if tt.wantError != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantError {
+					t.Fatalf("Expected %q error, got %v", tt.wantError, resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if put != tt.wantPut {
+				t.Errorf("Expected upload %v, got %v", tt.wantPut, put)
+			}
+
+			var state GarageObjectResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			wantETag := `"uploaded"`
+			if tt.exists {
+				wantETag = `"existing"`
+			}
+			if state.ETag.ValueString() != wantETag {
+				t.Errorf("Expected etag %s, got %s", wantETag, state.ETag.ValueString())
+			}
+			if state.LastModified.ValueString() != "2006-01-02T15:04:05Z" {
+				t.Errorf("Expected RFC 3339 last_modified, got %s", state.LastModified.ValueString())
+			}
+		})
+	}
+}
+
+func TestGarageObjectResourceRead_errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		wantRemoved bool
+		wantError   bool
+	}{
+		{name: "not found", status: http.StatusNotFound, wantRemoved: true},
+		{name: "access denied", status: http.StatusForbidden, wantError: true},
+		{name: "server error", status: http.StatusInternalServerError, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			r := &GarageObjectResource{s3Client: testS3Client(server.URL, func(o *s3.Options) {
+				o.RetryMaxAttempts = 1
+			})}
+			resp := testGarageObjectRead(t, r, testGarageObjectValue(t, testGarageObjectStateAttrs()))
+
+			if removed := resp.State.Raw.IsNull(); removed != tt.wantRemoved {
+				t.Errorf("Expected removed %v, got %v", tt.wantRemoved, removed)
+			}
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("Expected error %v, got %v", tt.wantError, resp.Diagnostics)
+			}
+		})
+	}
+
+	t.Run("connection error", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		r := &GarageObjectResource{s3Client: testS3Client(server.URL, func(o *s3.Options) {
+			o.RetryMaxAttempts = 1
+		})}
+		resp := testGarageObjectRead(t, r, testGarageObjectValue(t, testGarageObjectStateAttrs()))
+
+		if resp.State.Raw.IsNull() {
+			t.Error("Expected the object to stay in state")
 		}
-		
-		resource "garage_bucket_permission" "test" {
-			bucket_id = garage_bucket.test.id
-			access_key_id = %[2]q
-			
-			read  = true
-			write = true
-			owner = false
+		if !resp.Diagnostics.HasError() {
+			t.Error("Expected an error diagnostic")
 		}
-		
-		resource "garage_object" "test" {
-			depends_on = [garage_bucket_permission.test]
+	})
+}
 
-			bucket       = garage_bucket.test.id
-			key          = "test-object.txt"
-			content      = %[1]q
-			content_type = "text/plain"
-			
-			
+func TestGarageObjectResourceRead_headers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+			return
 		}
-		`, content, os.Getenv("GARAGE_ACCESS_KEY"),
-	)
+		w.Header().Set("ETag", `"5d41402abc4b2a76b9719d911017c592"`)
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Cache-Control", "Max-Age=60")
+		w.Header().Set("Content-Language", "en-US")
+		w.Header().Set("X-Amz-Website-Redirect-Location", "/new.html")
+		w.Header().Set("X-Amz-Meta-Owner", "team-b")
+		w.Header().Set("X-Amz-Meta-Build", "42")
+	}))
+	defer server.Close()
+
+	attrs := testGarageObjectStateAttrs()
+	attrs["cache_control"] = tftypes.NewValue(tftypes.String, "max-age=60")
+	attrs["content_language"] = tftypes.NewValue(tftypes.String, "en-GB")
+	attrs["metadata"] = tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+		"Owner": tftypes.NewValue(tftypes.String, "team-a"),
+	})
+
+	r := &GarageObjectResource{s3Client: testS3Client(server.URL)}
+	resp := testGarageObjectRead(t, r, testGarageObjectValue(t, attrs))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var state GarageObjectResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+
+	// Capitalization changes are not drift, value changes are
+	if state.CacheControl.ValueString() != "max-age=60" {
+		t.Errorf("Expected cache_control to keep its spelling, got %s", state.CacheControl)
+	}
+	if state.ContentLanguage.ValueString() != "en-US" {
+		t.Errorf("Expected content_language en-US, got %s", state.ContentLanguage)
+	}
+	if state.WebsiteRedirect.ValueString() != "/new.html" {
+		t.Errorf("Expected website_redirect /new.html, got %s", state.WebsiteRedirect)
+	}
+
+	want := map[string]string{"Owner": "team-b", "build": "42"}
+	if got := knownMetadata(state.Metadata); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Expected metadata %v, got %v", want, got)
+	}
+}
+
+func TestGarageObjectResourceDelete_errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		code       string
+		wantError  bool
+		wantDetail string
+	}{
+		{name: "no such bucket", status: http.StatusNotFound, code: "NoSuchBucket"},
+		{name: "no such key", status: http.StatusNotFound, code: "NoSuchKey"},
+		{name: "bare not found", status: http.StatusNotFound},
+		{
+			name: "access denied", status: http.StatusForbidden, code: "AccessDenied", wantError: true,
+			wantDetail: "Access key GKtest is not allowed to write objects in bucket bucket.",
+		},
+		{name: "server error", status: http.StatusInternalServerError, code: "InternalError", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete {
+					t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				if tt.code != "" {
+					_, _ = fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", tt.code, tt.name)
+				}
+			}))
+			defer server.Close()
+
+			r := &GarageObjectResource{s3AccessKey: "GKtest", s3Client: testS3Client(server.URL, func(o *s3.Options) {
+				o.RetryMaxAttempts = 1
+			})}
+			state := testGarageObjectValue(t, testGarageObjectStateAttrs())
+			resp := &fwresource.DeleteResponse{State: tfsdk.State{Schema: state.Schema, Raw: state.Raw}}
+			r.Delete(context.Background(), fwresource.DeleteRequest{
+				State: tfsdk.State{Schema: state.Schema, Raw: state.Raw},
+			}, resp)
+
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("Expected error %v, got %v", tt.wantError, resp.Diagnostics)
+			}
+			if tt.wantDetail != "" && !strings.HasPrefix(resp.Diagnostics.Errors()[0].Detail(), tt.wantDetail) {
+				t.Errorf("Expected the detail to start with %q, got %q", tt.wantDetail, resp.Diagnostics.Errors()[0].Detail())
+			}
+		})
+	}
+}
+
+func TestGarageObjectResourceDelete_retainOnDelete(t *testing.T) {
+	for _, retain := range []bool{false, true} {
+		t.Run(fmt.Sprintf("retain_on_delete=%v", retain), func(t *testing.T) {
+			var deleted bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete {
+					t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+				}
+				deleted = true
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			attrs := testGarageObjectStateAttrs()
+			attrs["retain_on_delete"] = tftypes.NewValue(tftypes.Bool, retain)
+			state := testGarageObjectValue(t, attrs)
+
+			// Destroy and replacement both call Delete with the prior state
+			r := &GarageObjectResource{s3Client: testS3Client(server.URL)}
+			resp := &fwresource.DeleteResponse{State: tfsdk.State{Schema: state.Schema, Raw: state.Raw}}
+			r.Delete(context.Background(), fwresource.DeleteRequest{
+				State: tfsdk.State{Schema: state.Schema, Raw: state.Raw},
+			}, resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if deleted == retain {
+				t.Errorf("Expected DeleteObject called %v, got %v", !retain, deleted)
+			}
+		})
+	}
+}
+
+func TestAccGarageObjectResource_retainOnDelete(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageObjectResourceConfig_retainOnDelete(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_object.test", "retain_on_delete", "true"),
+				),
+			},
+			// Replacing the object skips the delete of the old one
+			{
+				Config: testAccGarageObjectResourceConfig_retainOnDelete(true),
+				Taint:  []string{"garage_object.test"},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckGarageObjectContent("garage_object.test", "retained"),
+				),
+			},
+			// Destroying the object only removes it from the state
+			{
+				Config: testAccGarageObjectResourceConfig_retainOnDelete(false),
+				Check:  testAccCheckGarageObjectRetained("test-bucket-object-retain", "retained.txt"),
+			},
+		},
+	})
+}
+
+func TestGarageObjectResourceUpdate_headersOnly(t *testing.T) {
+	tests := []struct {
+		name       string
+		copyStatus int
+		wantCopy   bool
+		wantUpload bool
+	}{
+		{name: "copy succeeds", copyStatus: http.StatusOK, wantCopy: true},
+		{name: "copy fails", copyStatus: http.StatusNotImplemented, wantCopy: true, wantUpload: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var copied, uploaded bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodHead {
+					w.Header().Set("ETag", `"5d41402abc4b2a76b9719d911017c592"`)
+					w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+					return
+				}
+				if r.Method != http.MethodPut {
+					t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+					return
+				}
+
+				if source := r.Header.Get("X-Amz-Copy-Source"); source != "" {
+					copied = true
+					if source != "bucket/key.txt" {
+						t.Errorf("Unexpected copy source %q", source)
+					}
+					if r.Header.Get("X-Amz-Metadata-Directive") != "REPLACE" {
+						t.Errorf("Expected metadata directive REPLACE")
+					}
+					w.WriteHeader(tt.copyStatus)
+					if tt.copyStatus == http.StatusOK {
+						_, _ = w.Write([]byte(`<CopyObjectResult><ETag>"5d41402abc4b2a76b9719d911017c592"</ETag>` +
+							`<LastModified>2006-01-02T15:04:05.000Z</LastModified></CopyObjectResult>`))
+					}
+					return
+				}
+
+				uploaded = true
+				w.Header().Set("ETag", `"5d41402abc4b2a76b9719d911017c592"`)
+			}))
+			defer server.Close()
+
+			r := &GarageObjectResource{s3Client: testS3Client(server.URL, func(o *s3.Options) {
+				o.RetryMaxAttempts = 1
+			})}
+
+			stateAttrs := testGarageObjectStateAttrs()
+			planAttrs := testGarageObjectStateAttrs()
+			planAttrs["content_type"] = tftypes.NewValue(tftypes.String, "text/markdown")
+			planAttrs["etag"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+
+			resp := testGarageObjectUpdate(t, r, testGarageObjectValue(t, planAttrs), testGarageObjectValue(t, stateAttrs))
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if copied != tt.wantCopy || uploaded != tt.wantUpload {
+				t.Errorf("Expected copy=%v upload=%v, got copy=%v upload=%v", tt.wantCopy, tt.wantUpload, copied, uploaded)
+			}
+
+			var state GarageObjectResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			if state.ContentType.ValueString() != "text/markdown" {
+				t.Errorf("Expected content_type text/markdown, got %s", state.ContentType.ValueString())
+			}
+			if state.LastModified.ValueString() != "2006-01-02T15:04:05Z" {
+				t.Errorf("Expected RFC 3339 last_modified, got %s", state.LastModified.ValueString())
+			}
+			if state.ChecksumSHA256.ValueString() != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+				t.Errorf("Unexpected checksum_sha256 %s", state.ChecksumSHA256.ValueString())
+			}
+		})
+	}
+}
+
+func TestGarageObjectResourceUpdate_withoutContent(t *testing.T) {
+	var copied, uploaded bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodHead:
+			w.Header().Set("ETag", `"5d41402abc4b2a76b9719d911017c592"`)
+		case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+			copied = true
+			_, _ = w.Write([]byte(`<CopyObjectResult><ETag>"5d41402abc4b2a76b9719d911017c592"</ETag></CopyObjectResult>`))
+		default:
+			uploaded = true
+			t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	r := &GarageObjectResource{s3Client: testS3Client(server.URL)}
+
+	// Imported object: the body is not managed, header changes are copied
+	stateAttrs := testGarageObjectStateAttrs()
+	stateAttrs["content"] = tftypes.NewValue(tftypes.String, nil)
+	planAttrs := testGarageObjectStateAttrs()
+	planAttrs["content"] = tftypes.NewValue(tftypes.String, nil)
+	planAttrs["content_type"] = tftypes.NewValue(tftypes.String, "text/markdown")
+
+	resp := testGarageObjectUpdate(t, r, testGarageObjectValue(t, planAttrs), testGarageObjectValue(t, stateAttrs))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if !copied || uploaded {
+		t.Errorf("Expected a copy without upload, got copy=%v upload=%v", copied, uploaded)
+	}
+
+	// Moving it requires a body to upload
+	planAttrs["key"] = tftypes.NewValue(tftypes.String, "other.txt")
+
+	resp = testGarageObjectUpdate(t, r, testGarageObjectValue(t, planAttrs), testGarageObjectValue(t, stateAttrs))
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Missing Object Content" {
+		t.Fatalf("Expected Missing Object Content error, got %v", resp.Diagnostics)
+	}
+}
+
+func TestAccGarageObjectResource_renameViaCopy(t *testing.T) {
+	var etag string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageObjectResourceConfig_renameViaCopy("before.txt"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("garage_object.test", "etag", func(value string) error {
+						etag = value
+						return nil
+					}),
+				),
+			},
+			// The object is moved in place, keeping its ETag
+			{
+				Config: testAccGarageObjectResourceConfig_renameViaCopy("after.txt"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("garage_object.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_object.test", "id", "test-bucket-object-rename/after.txt"),
+					resource.TestCheckResourceAttrWith("garage_object.test", "etag", func(value string) error {
+						if value != etag {
+							return fmt.Errorf("expected etag %s to be preserved, got %s", etag, value)
+						}
+						return nil
+					}),
+					testAccCheckGarageObjectContent("garage_object.test", "renamed-content"),
+					func(*terraform.State) error {
+						_, err := testAccS3Client().HeadObject(context.Background(), &s3.HeadObjectInput{
+							Bucket: aws.String("test-bucket-object-rename"),
+							Key:    aws.String("before.txt"),
+						})
+						if !isObjectNotFound(err) {
+							return fmt.Errorf("expected the object to be gone at its previous key, got %v", err)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestGarageObjectResourceUpdate_fakeRenameViaCopy(t *testing.T) {
+	oldDelay := objectRenameDeleteRetryDelay
+	objectRenameDeleteRetryDelay = time.Millisecond
+	defer func() { objectRenameDeleteRetryDelay = oldDelay }()
+
+	tests := []struct {
+		name        string
+		deleteErr   error
+		wantWarning string
+	}{
+		{name: "previous key deleted"},
+		{
+			name:        "previous key kept",
+			deleteErr:   &smithy.GenericAPIError{Code: "InternalError", Message: "We encountered an internal error"},
+			wantWarning: "Previous Object Not Deleted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeObjectAPI("bucket")
+			r := &GarageObjectResource{s3Client: fake}
+
+			attrs := map[string]tftypes.Value{
+				"bucket":          tftypes.NewValue(tftypes.String, "bucket"),
+				"key":             tftypes.NewValue(tftypes.String, "key.txt"),
+				"content":         tftypes.NewValue(tftypes.String, "hello"),
+				"cache_control":   tftypes.NewValue(tftypes.String, "max-age=60"),
+				"overwrite":       tftypes.NewValue(tftypes.Bool, true),
+				"adopt_existing":  tftypes.NewValue(tftypes.Bool, false),
+				"rename_via_copy": tftypes.NewValue(tftypes.Bool, true),
+			}
+			createResp := testGarageObjectCreate(t, r, testGarageObjectValue(t, attrs))
+			if createResp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", createResp.Diagnostics)
+			}
+			prior := tfsdk.Plan{Schema: createResp.State.Schema, Raw: createResp.State.Raw}
+			var priorState GarageObjectResourceModel
+			createResp.Diagnostics.Append(createResp.State.Get(context.Background(), &priorState)...)
+
+			fake.calls = nil
+			fake.errs["DeleteObject"] = tt.deleteErr
+			attrs["key"] = tftypes.NewValue(tftypes.String, "renamed.txt")
+			resp := testGarageObjectUpdate(t, r, testGarageObjectValue(t, attrs), prior)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if fake.called("PutObject") || !fake.called("CopyObject") {
+				t.Errorf("Expected a copy without upload, got calls %v", fake.calls)
+			}
+			renamed := fake.object("bucket", "renamed.txt")
+			if renamed == nil || string(renamed.body) != "hello" || aws.ToString(renamed.cacheControl) != "max-age=60" {
+				t.Fatalf("Expected the object and its headers at the new key, got %+v", renamed)
+			}
+
+			var state GarageObjectResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			if state.ID.ValueString() != "bucket/renamed.txt" || state.S3URI.ValueString() != "s3://bucket/renamed.txt" {
+				t.Errorf("Unexpected id %s and s3_uri %s", state.ID, state.S3URI)
+			}
+			if !state.ETag.Equal(priorState.ETag) || !state.ChecksumSHA256.Equal(priorState.ChecksumSHA256) {
+				t.Errorf("Expected etag %s and checksum %s to be preserved, got %s and %s",
+					priorState.ETag, priorState.ChecksumSHA256, state.ETag, state.ChecksumSHA256)
+			}
+
+			if tt.wantWarning == "" {
+				if fake.object("bucket", "key.txt") != nil {
+					t.Errorf("Expected the previous key to be deleted")
+				}
+				if len(resp.Diagnostics) != 0 {
+					t.Errorf("Unexpected diagnostics: %v", resp.Diagnostics)
+				}
+				return
+			}
+
+			if fake.object("bucket", "key.txt") == nil {
+				t.Errorf("Expected the object to be kept at the previous key")
+			}
+			if len(resp.Diagnostics.Warnings()) != 1 || resp.Diagnostics.Warnings()[0].Summary() != tt.wantWarning {
+				t.Errorf("Expected a %q warning, got %v", tt.wantWarning, resp.Diagnostics)
+			}
+			deletes := 0
+			for _, call := range fake.calls {
+				if call == "DeleteObject" {
+					deletes++
+				}
+			}
+			if deletes != objectRenameDeleteAttempts {
+				t.Errorf("Expected %d delete attempts, got calls %v", objectRenameDeleteAttempts, fake.calls)
+			}
+		})
+	}
+}
+
+// testAccSpecialObjectKeys are object keys with characters that need escaping
+// in URLs, by name.
+var testAccSpecialObjectKeys = map[string]string{
+	"spaces":  "reports/2024 Q3/résumé.pdf",
+	"plus":    "a+b/c++.txt",
+	"hash":    "notes#1?draft.txt",
+	"unicode": "日本語/ファイル名 ünïcode.txt",
+}
+
+func TestAccGarageObjectResource_specialKeys(t *testing.T) {
+	steps := []resource.TestStep{
+		{
+			Config: testAccGarageObjectResourceConfig_specialKeys(true),
+			Check: func(s *terraform.State) error {
+				var checks []resource.TestCheckFunc
+				for name, key := range testAccSpecialObjectKeys {
+					object := fmt.Sprintf("garage_object.test[%q]", name)
+					data := fmt.Sprintf("data.garage_object.test[%q]", name)
+					checks = append(checks,
+						resource.TestCheckResourceAttr(object, "key", key),
+						resource.TestCheckResourceAttrWith(object, "id", func(id string) error {
+							if !strings.HasSuffix(id, "/"+key) {
+								return fmt.Errorf("expected id to end with the raw key %q, got %q", key, id)
+							}
+							return nil
+						}),
+						resource.TestCheckResourceAttrWith(object, "url", func(u string) error {
+							if !strings.HasSuffix(u, "/test-bucket-object-keys/"+escapeObjectKey(key)) {
+								return fmt.Errorf("expected url to end with the escaped key, got %q", u)
+							}
+							return nil
+						}),
+						testAccCheckGarageObjectContent(object, key),
+						resource.TestCheckResourceAttr(data, "content", key),
+						resource.TestCheckResourceAttrPair(data, "id", object, "id"),
+						resource.TestCheckResourceAttrPair(data, "url", object, "url"),
+					)
+				}
+				return resource.ComposeAggregateTestCheckFunc(checks...)(s)
+			},
+		},
+	}
+	for name := range testAccSpecialObjectKeys {
+		steps = append(steps, resource.TestStep{
+			ResourceName:            fmt.Sprintf("garage_object.test[%q]", name),
+			ImportState:             true,
+			ImportStateVerify:       true,
+			ImportStateVerifyIgnore: []string{"content"},
+		})
+	}
+	// Removing the objects deletes them, keys included as is
+	steps = append(steps, resource.TestStep{
+		Config: testAccGarageObjectResourceConfig_specialKeys(false),
+		Check: func(*terraform.State) error {
+			for _, key := range testAccSpecialObjectKeys {
+				_, err := testAccS3Client().HeadObject(context.Background(), &s3.HeadObjectInput{
+					Bucket: aws.String("test-bucket-object-keys"),
+					Key:    aws.String(key),
+				})
+				if err == nil {
+					return fmt.Errorf("expected object %q to be deleted", key)
+				}
+			}
+			return nil
+		},
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps:                    steps,
+	})
+}
+
+func TestAccGarageObjectResource_empty(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageObjectResourceConfig_empty(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_object.test", "content", ""),
+					resource.TestCheckResourceAttr("garage_object.test", "etag", `"d41d8cd98f00b204e9800998ecf8427e"`),
+					testAccCheckGarageObjectContent("garage_object.test", ""),
+				),
+			},
+			// The empty-body ETag must not be reported as drift
+			{
+				Config:   testAccGarageObjectResourceConfig_empty(),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestGarageObjectResourceRead_emptyContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+			return
+		}
+		w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+		w.Header().Set("Content-Type", "text/plain")
+	}))
+	defer server.Close()
+
+	attrs := testGarageObjectStateAttrs()
+	attrs["key"] = tftypes.NewValue(tftypes.String, "folder/")
+	attrs["content"] = tftypes.NewValue(tftypes.String, "")
+	attrs["etag"] = tftypes.NewValue(tftypes.String, `"d41d8cd98f00b204e9800998ecf8427e"`)
+
+	r := &GarageObjectResource{s3Client: testS3Client(server.URL)}
+	resp := testGarageObjectRead(t, r, testGarageObjectValue(t, attrs))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var state GarageObjectResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+	if state.Content.IsNull() || state.Content.ValueString() != "" {
+		t.Errorf("Expected empty content to be kept, got %s", state.Content)
+	}
+}
+
+func TestAccGarageObjectResource_sourceURL(t *testing.T) {
+	// The provider runs in-process, so it can reach a local server
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer source.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageObjectResourceConfig_sourceURL(source.URL+"/hello.txt", "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_object.test", "checksum_sha256", "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"),
+					testAccCheckGarageObjectContent("garage_object.test", "hello"),
+				),
+			},
+			{
+				Config:      testAccGarageObjectResourceConfig_sourceURL(source.URL+"/hello.txt", "0a3666a0710c08aa6d0de92ce72beeb5b93124cce1bf3701c9d6cdeb543cb73e"),
+				ExpectError: regexp.MustCompile("Source Checksum Mismatch"),
+			},
+		},
+	})
+}
+
+func TestDownloadToTempFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/artifact":
+			_, _ = w.Write([]byte("artifact body"))
+		case "/redirect":
+			http.Redirect(w, r, "/artifact", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	for _, p := range []string{"/artifact", "/redirect"} {
+		file, err := downloadToTempFile(context.Background(), server.URL+p)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %s", p, err)
+		}
+		body, _ := io.ReadAll(file)
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+
+		if string(body) != "artifact body" {
+			t.Errorf("Expected downloaded body for %s, got %q", p, string(body))
+		}
+	}
+
+	_, err := downloadToTempFile(context.Background(), server.URL+"/missing")
+	if err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Errorf("Expected a status 404 error, got %v", err)
+	}
+}
+
+func TestDownloadToTempFile_timeout(t *testing.T) {
+	oldTimeout := sourceURLTimeout
+	sourceURLTimeout = 50 * time.Millisecond
+	defer func() { sourceURLTimeout = oldTimeout }()
+
+	// The server sends the headers then stalls
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	_, err := downloadToTempFile(context.Background(), server.URL+"/artifact")
+	if err == nil {
+		t.Fatal("Expected the stalled download to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the download to stop after the timeout, took %s", elapsed)
+	}
+}
+
+func TestGarageObjectResourceCreate_sourceURLChecksumMismatch(t *testing.T) {
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("tampered"))
+	}))
+	defer source.Close()
+
+	s3Server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+	}))
+	defer s3Server.Close()
+
+	r := &GarageObjectResource{s3Client: testS3Client(s3Server.URL)}
+	plan := testGarageObjectValue(t, map[string]tftypes.Value{
+		"bucket":              tftypes.NewValue(tftypes.String, "bucket"),
+		"key":                 tftypes.NewValue(tftypes.String, "artifact.txt"),
+		"source_url":          tftypes.NewValue(tftypes.String, source.URL+"/artifact.txt"),
+		"source_url_checksum": tftypes.NewValue(tftypes.String, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"),
+		"overwrite":           tftypes.NewValue(tftypes.Bool, true),
+		"adopt_existing":      tftypes.NewValue(tftypes.Bool, false),
+	})
+
+	resp := testGarageObjectCreate(t, r, plan)
+
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Source Checksum Mismatch" {
+		t.Fatalf("Expected a checksum mismatch error, got %v", resp.Diagnostics)
+	}
+}
+
+func TestAccGarageObjectResource_headersDrift(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageObjectResourceConfig_metadata(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_object.test", "metadata.owner", "team-a"),
+					resource.TestCheckResourceAttr("garage_object.test", "cache_control", "max-age=3600"),
+				),
+			},
+			// Rewrite the metadata out-of-band: the next plan must correct it
+			{
+				PreConfig: func() {
+					_, err := testAccS3Client().CopyObject(context.Background(), &s3.CopyObjectInput{
+						Bucket:            aws.String("test-bucket-object-metadata"),
+						Key:               aws.String("meta.txt"),
+						CopySource:        aws.String("test-bucket-object-metadata/meta.txt"),
+						MetadataDirective: s3types.MetadataDirectiveReplace,
+						ContentType:       aws.String("text/plain"),
+						CacheControl:      aws.String("no-cache"),
+						Metadata:          map[string]string{"owner": "someone-else"},
+					})
+					if err != nil {
+						t.Fatalf("Unable to rewrite object metadata: %s", err)
+					}
+				},
+				Config: testAccGarageObjectResourceConfig_metadata(),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("garage_object.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_object.test", "metadata.owner", "team-a"),
+					resource.TestCheckResourceAttr("garage_object.test", "cache_control", "max-age=3600"),
+				),
+			},
+			// Once corrected, the configuration is stable
+			{
+				Config:   testAccGarageObjectResourceConfig_metadata(),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccGarageObjectResource_contentDrift(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageObjectResourceConfig("managed-content"),
+			},
+			// Overwrite the object out-of-band: the next plan must re-upload it
+			{
+				PreConfig: func() {
+					_, err := testAccS3Client().PutObject(context.Background(), &s3.PutObjectInput{
+						Bucket: aws.String("test-bucket-object"),
+						Key:    aws.String("test-object.txt"),
+						Body:   strings.NewReader("overwritten-content"),
+					})
+					if err != nil {
+						t.Fatalf("Unable to overwrite object: %s", err)
+					}
+				},
+				Config:             testAccGarageObjectResourceConfig("managed-content"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccGarageObjectResource_writeOnly(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageObjectResourceConfig_writeOnly("secret-v1", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("garage_object.test", "content_wo"),
+					resource.TestCheckNoResourceAttr("garage_object.test", "content"),
+					resource.TestCheckNoResourceAttr("garage_object.test", "checksum_sha256"),
+					resource.TestCheckNoResourceAttr("garage_object.test", "checksum_crc32"),
+					resource.TestCheckResourceAttr("garage_object.test", "content_wo_version", "1"),
+					resource.TestCheckResourceAttr("garage_object.test", "etag", contentETag("secret-v1")),
+					testAccCheckGarageObjectContent("garage_object.test", "secret-v1"),
+				),
+			},
+			// A new value alone is not uploaded
+			{
+				Config:   testAccGarageObjectResourceConfig_writeOnly("secret-v2", 1),
+				PlanOnly: true,
+			},
+			// Bumping the version uploads it
+			{
+				Config: testAccGarageObjectResourceConfig_writeOnly("secret-v2", 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_object.test", "content_wo_version", "2"),
+					testAccCheckGarageObjectContent("garage_object.test", "secret-v2"),
+				),
+			},
+			// An out-of-band overwrite changes the ETag and is uploaded again
+			{
+				PreConfig: func() {
+					_, err := testAccS3Client().PutObject(context.Background(), &s3.PutObjectInput{
+						Bucket: aws.String("test-bucket-object-wo"),
+						Key:    aws.String("secret.conf"),
+						Body:   strings.NewReader("overwritten-content"),
+					})
+					if err != nil {
+						t.Fatalf("Unable to overwrite object: %s", err)
+					}
+				},
+				Config: testAccGarageObjectResourceConfig_writeOnly("secret-v2", 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckGarageObjectContent("garage_object.test", "secret-v2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccGarageObjectResource_contentStorage(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGarageObjectResourceConfig_contentStorage("config-v1", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_object.test", "content", "config-v1"),
+					resource.TestCheckNoResourceAttr("garage_object.test", "content_sha256"),
+				),
+			},
+			// Switching to hash keeps the object and only drops the content
+			// from the state
+			{
+				Config: testAccGarageObjectResourceConfig_contentStorage("config-v1", true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("garage_object.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("garage_object.test", "content"),
+					resource.TestCheckNoResourceAttr("garage_object.test", "content_wo"),
+					resource.TestCheckResourceAttr("garage_object.test", "content_sha256", testSHA256Hex("config-v1")),
+					resource.TestCheckResourceAttr("garage_object.test", "etag", contentETag("config-v1")),
+					testAccCheckGarageObjectContent("garage_object.test", "config-v1"),
+				),
+			},
+			// A new content is uploaded as its hash changes
+			{
+				Config: testAccGarageObjectResourceConfig_contentStorage("config-v2", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_object.test", "content_sha256", testSHA256Hex("config-v2")),
+					testAccCheckGarageObjectContent("garage_object.test", "config-v2"),
+				),
+			},
+			// An out-of-band overwrite changes the hash and is uploaded again
+			{
+				PreConfig: func() {
+					_, err := testAccS3Client().PutObject(context.Background(), &s3.PutObjectInput{
+						Bucket: aws.String("test-bucket-object-hash"),
+						Key:    aws.String("config.yaml"),
+						Body:   strings.NewReader("overwritten-content"),
+					})
+					if err != nil {
+						t.Fatalf("Unable to overwrite object: %s", err)
+					}
+				},
+				Config: testAccGarageObjectResourceConfig_contentStorage("config-v2", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_object.test", "content_sha256", testSHA256Hex("config-v2")),
+					testAccCheckGarageObjectContent("garage_object.test", "config-v2"),
+				),
+			},
+		},
+	})
+}
+
+func TestGarageObjectResourceCreate_writeOnly(t *testing.T) {
+	var uploaded string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			uploaded = string(body)
+			w.Header().Set("ETag", contentETag(uploaded))
+		case http.MethodHead:
+			w.Header().Set("ETag", contentETag(uploaded))
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		default:
+			t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	r := &GarageObjectResource{s3Client: testS3Client(server.URL)}
+	plan := testGarageObjectValue(t, map[string]tftypes.Value{
+		"bucket":             tftypes.NewValue(tftypes.String, "bucket"),
+		"key":                tftypes.NewValue(tftypes.String, "secret.conf"),
+		"content_wo":         tftypes.NewValue(tftypes.String, "password=hunter2"),
+		"content_wo_version": tftypes.NewValue(tftypes.Number, 1),
+		"overwrite":          tftypes.NewValue(tftypes.Bool, true),
+		"adopt_existing":     tftypes.NewValue(tftypes.Bool, false),
+	})
+
+	resp := testGarageObjectCreate(t, r, plan)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if uploaded != "password=hunter2" {
+		t.Errorf("Expected write-only content to be uploaded, got %q", uploaded)
+	}
+
+	var state GarageObjectResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+	if !state.ContentWO.IsNull() {
+		t.Error("Expected content_wo to be null in state")
+	}
+	if !state.ChecksumSHA256.IsNull() || !state.ChecksumCRC32.IsNull() {
+		t.Errorf("Expected no checksums in state, got %s and %s", state.ChecksumSHA256, state.ChecksumCRC32)
+	}
+	if state.ETag.ValueString() != contentETag("password=hunter2") {
+		t.Errorf("Unexpected etag %s", state.ETag.ValueString())
+	}
+}
+
+func TestGarageObjectResourceRead_writeOnlyDrift(t *testing.T) {
+	tests := []struct {
+		name        string
+		remoteETag  string
+		wantVersion bool
+	}{
+		{name: "unchanged", remoteETag: `"5d41402abc4b2a76b9719d911017c592"`, wantVersion: true},
+		{name: "overwritten", remoteETag: `"0a8b2d8b6bf1d9f5e5c2b5b6a2d1c3e4"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodHead {
+					t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+					return
+				}
+				w.Header().Set("ETag", tt.remoteETag)
+				w.Header().Set("Content-Type", "text/plain")
+				w.Header().Set("X-Amz-Checksum-Sha256", "LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=")
+			}))
+			defer server.Close()
+
+			attrs := testGarageObjectStateAttrs()
+			attrs["content"] = tftypes.NewValue(tftypes.String, nil)
+			attrs["content_wo_version"] = tftypes.NewValue(tftypes.Number, 1)
+			attrs["checksum_sha256"] = tftypes.NewValue(tftypes.String, nil)
+			attrs["checksum_crc32"] = tftypes.NewValue(tftypes.String, nil)
+
+			r := &GarageObjectResource{s3Client: testS3Client(server.URL)}
+			resp := testGarageObjectRead(t, r, testGarageObjectValue(t, attrs))
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var state GarageObjectResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			if state.ContentWOVersion.IsNull() == tt.wantVersion {
+				t.Errorf("Expected content_wo_version kept %v, got %s", tt.wantVersion, state.ContentWOVersion)
+			}
+			if !state.ChecksumSHA256.IsNull() {
+				t.Errorf("Expected no checksum in state, got %s", state.ChecksumSHA256)
+			}
+		})
+	}
+}
+
+func TestGarageObjectResourceUpdate_contentStorage(t *testing.T) {
+	const helloSHA256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	const byeSHA256 = "b49f425a7e1f9cff3856329ada223f2f9d368f15a00cf48df16ca95986137fe8"
+
+	literal := testGarageObjectStateAttrs()
+	literal["cache_control"] = tftypes.NewValue(tftypes.String, nil)
+	literal["website_redirect"] = tftypes.NewValue(tftypes.String, nil)
+	literal["metadata"] = tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil)
+	hashed := maps.Clone(literal)
+	hashed["content"] = tftypes.NewValue(tftypes.String, nil)
+	hashed["content_storage"] = tftypes.NewValue(tftypes.String, "hash")
+	hashed["content_sha256"] = tftypes.NewValue(tftypes.String, helloSHA256)
+
+	tests := []struct {
+		name         string
+		state        map[string]tftypes.Value
+		plan         map[string]tftypes.Value
+		wantUploaded string
+		wantSHA256   string
+	}{
+		{
+			name:  "literal to hash",
+			state: literal,
+			plan: map[string]tftypes.Value{
+				"content":         tftypes.NewValue(tftypes.String, nil),
+				"content_wo":      tftypes.NewValue(tftypes.String, "hello"),
+				"content_storage": tftypes.NewValue(tftypes.String, "hash"),
+				"content_sha256":  tftypes.NewValue(tftypes.String, helloSHA256),
+			},
+			wantSHA256: helloSHA256,
+		},
+		{
+			name:  "hash to literal",
+			state: hashed,
+			plan: map[string]tftypes.Value{
+				"content_storage": tftypes.NewValue(tftypes.String, "literal"),
+			},
+		},
+		{
+			name:  "hashed content changed",
+			state: hashed,
+			plan: map[string]tftypes.Value{
+				"content":         tftypes.NewValue(tftypes.String, nil),
+				"content_wo":      tftypes.NewValue(tftypes.String, "bye"),
+				"content_storage": tftypes.NewValue(tftypes.String, "hash"),
+				"content_sha256":  tftypes.NewValue(tftypes.String, byeSHA256),
+				"etag":            tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"checksum_sha256": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"checksum_crc32":  tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			},
+			wantUploaded: "bye",
+			wantSHA256:   byeSHA256,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var uploaded string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodPut:
+					body, _ := io.ReadAll(r.Body)
+					uploaded = string(body)
+					w.Header().Set("ETag", contentETag(uploaded))
+				case http.MethodHead:
+					w.Header().Set("ETag", contentETag(uploaded))
+					w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+				default:
+					t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			planAttrs := maps.Clone(literal)
+			maps.Copy(planAttrs, tt.plan)
+
+			r := &GarageObjectResource{s3Client: testS3Client(server.URL)}
+			resp := testGarageObjectUpdate(t, r, testGarageObjectValue(t, planAttrs), testGarageObjectValue(t, tt.state))
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if uploaded != tt.wantUploaded {
+				t.Errorf("Expected %q to be uploaded, got %q", tt.wantUploaded, uploaded)
+			}
+
+			var state GarageObjectResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			if state.ContentSHA256.ValueString() != tt.wantSHA256 {
+				t.Errorf("Expected content_sha256 %q, got %s", tt.wantSHA256, state.ContentSHA256)
+			}
+			if tt.wantSHA256 != "" && (!state.Content.IsNull() || !state.ContentWO.IsNull()) {
+				t.Errorf("Expected no content in state, got %s", state.Content)
+			}
+		})
+	}
+}
+
+func TestGarageObjectResourceRead_hashedDrift(t *testing.T) {
+	tests := []struct {
+		name       string
+		remote     string
+		wantSHA256 string
+	}{
+		{name: "unchanged", remote: "hello", wantSHA256: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		{name: "overwritten", remote: "bye", wantSHA256: "b49f425a7e1f9cff3856329ada223f2f9d368f15a00cf48df16ca95986137fe8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("ETag", contentETag(tt.remote))
+				switch r.Method {
+				case http.MethodHead:
+				case http.MethodGet:
+					if tt.remote == "hello" {
+						t.Errorf("Unexpected download of an unchanged object")
+					}
+					_, _ = w.Write([]byte(tt.remote))
+				default:
+					t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			attrs := testGarageObjectStateAttrs()
+			attrs["content"] = tftypes.NewValue(tftypes.String, nil)
+			attrs["content_storage"] = tftypes.NewValue(tftypes.String, "hash")
+			attrs["content_sha256"] = tftypes.NewValue(tftypes.String, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824")
+
+			r := &GarageObjectResource{s3Client: testS3Client(server.URL)}
+			resp := testGarageObjectRead(t, r, testGarageObjectValue(t, attrs))
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var state GarageObjectResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			if state.ContentSHA256.ValueString() != tt.wantSHA256 {
+				t.Errorf("Expected content_sha256 %s, got %s", tt.wantSHA256, state.ContentSHA256)
+			}
+			if !state.Content.IsNull() {
+				t.Errorf("Expected no content in state, got %s", state.Content)
+			}
+		})
+	}
+}
+
+func TestGarageObjectResourceCreate_contentMD5(t *testing.T) {
+	tests := []struct {
+		name      string
+		etag      string
+		wantError string
+	}{
+		{name: "matching etag", etag: `"5d41402abc4b2a76b9719d911017c592"`},
+		{name: "multipart etag", etag: `"0a8b2d8b6bf1d9f5e5c2b5b6a2d1c3e4-2"`},
+		{name: "mismatching etag", etag: `"00000000000000000000000000000000"`, wantError: "Object Integrity Error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodPut:
+					if got := r.Header.Get("Content-MD5"); got != "XUFAKrxLKna5cZ2REBfFkg==" {
+						t.Errorf("Expected Content-MD5 header, got %q", got)
+					}
+					w.Header().Set("ETag", tt.etag)
+				case http.MethodHead:
+					w.Header().Set("ETag", tt.etag)
+				default:
+					t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			r := &GarageObjectResource{s3Client: testS3Client(server.URL)}
+			plan := testGarageObjectValue(t, map[string]tftypes.Value{
+				"bucket":         tftypes.NewValue(tftypes.String, "bucket"),
+				"key":            tftypes.NewValue(tftypes.String, "key.txt"),
+				"content":        tftypes.NewValue(tftypes.String, "hello"),
+				"content_md5":    tftypes.NewValue(tftypes.String, "XUFAKrxLKna5cZ2REBfFkg=="),
+				"overwrite":      tftypes.NewValue(tftypes.Bool, true),
+				"adopt_existing": tftypes.NewValue(tftypes.Bool, false),
+			})
+
+			resp := testGarageObjectCreate(t, r, plan)
+
+			if tt.wantError == "" {
+				if resp.Diagnostics.HasError() {
+					t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+				}
+				return
+			}
+			if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantError {
+				t.Fatalf("Expected %q error, got %v", tt.wantError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestContentETag(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{content: "", want: `"d41d8cd98f00b204e9800998ecf8427e"`},
+		{content: "hello", want: `"5d41402abc4b2a76b9719d911017c592"`},
+	}
+
+	for _, tt := range tests {
+		if got := contentETag(tt.content); got != tt.want {
+			t.Errorf("contentETag(%q) = %s, want %s", tt.content, got, tt.want)
+		}
+	}
+
+	if !isMultipartETag(`"9b2cf535f27731c974343645a3985328-2"`) {
+		t.Error("Expected multipart ETag to be detected")
+	}
+	if isMultipartETag(`"5d41402abc4b2a76b9719d911017c592"`) {
+		t.Error("Expected single-part ETag not to be detected as multipart")
+	}
+}
+
+func TestDetectContentType(t *testing.T) {
+	tests := []struct {
+		key    string
+		source string
+		want   string
+	}{
+		{key: "site/style.css", want: "text/css; charset=utf-8"},
+		{key: "site/app.js", want: "text/javascript; charset=utf-8"},
+		{key: "index.html", want: "text/html; charset=utf-8"},
+		{key: "data.json", want: "application/json"},
+		{key: "logo.png", want: "image/png"},
+		{key: "icon.svg", want: "image/svg+xml"},
+		{key: "module.wasm", want: "application/wasm"},
+		{key: "upper.PNG", want: "image/png"},
+		// Web assets that the mime package misses or gets wrong
+		{key: "fonts/inter.woff2", want: "font/woff2"},
+		{key: "fonts/inter.woff", want: "font/woff"},
+		{key: "site/app.mjs", want: "text/javascript; charset=utf-8"},
+		{key: "site/APP.MJS", want: "text/javascript; charset=utf-8"},
+		{key: "site/app.js.map", want: "application/json"},
+		{key: "site.webmanifest", want: "application/manifest+json"},
+		{key: "favicon.ico", want: "image/x-icon"},
+		{key: "photo.avif", want: "image/avif"},
+		// The key has no usable extension: fall back to the source file
+		{key: "latest", source: "build/report.pdf", want: "application/pdf"},
+		// The key wins over the source
+		{key: "page.html", source: "page.tmpl", want: "text/html; charset=utf-8"},
+		// Unknown extensions leave the resource default in place
+		{key: "archive.unknownext", want: ""},
+		{key: "no-extension", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := detectContentType(tt.key, tt.source, nil); got != tt.want {
+				t.Errorf("detectContentType(%q, %q) = %q, want %q", tt.key, tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectContentType_overrides(t *testing.T) {
+	overrides := map[string]string{
+		".glb":  "model/gltf-binary",
+		".avif": "image/avif; profile=custom",
+		".map":  "application/source-map+json",
+	}
+
+	tests := []struct {
+		key, source, want string
+	}{
+		{key: "models/ship.glb", want: "model/gltf-binary"},
+		{key: "models/SHIP.GLB", want: "model/gltf-binary"},
+		// Overrides win over the built-in tables
+		{key: "photo.avif", want: "image/avif; profile=custom"},
+		{key: "app.js.map", want: "application/source-map+json"},
+		{key: "latest", source: "build/ship.glb", want: "model/gltf-binary"},
+		{key: "index.html", want: "text/html; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := detectContentType(tt.key, tt.source, overrides); got != tt.want {
+				t.Errorf("detectContentType(%q, %q) = %q, want %q", tt.key, tt.source, got, tt.want)
+			}
+		})
+	}
+
+	plan := GarageObjectResourceModel{Key: types.StringValue("ship.glb"), ContentType: types.StringNull(), Source: types.StringNull(), SourceURL: types.StringNull()}
+	if got := resolveContentType(plan, overrides); got != "model/gltf-binary" {
+		t.Errorf("Expected the override for the object, got %q", got)
+	}
+	plan.ContentType = types.StringValue("application/octet-stream")
+	if got := resolveContentType(plan, overrides); got != "application/octet-stream" {
+		t.Errorf("Expected content_type to win over the override, got %q", got)
+	}
+}
+
+func TestContentETagPlanModifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		attrs    map[string]tftypes.Value
+		create   bool
+		wantETag string
+	}{
+		{
+			name: "content",
+			attrs: map[string]tftypes.Value{
+				"content": tftypes.NewValue(tftypes.String, "hello"),
+			},
+			wantETag: `"5d41402abc4b2a76b9719d911017c592"`,
+		},
+		{
+			name: "content_base64",
+			attrs: map[string]tftypes.Value{
+				"content_base64": tftypes.NewValue(tftypes.String, "aGVsbG8="),
+			},
+			wantETag: `"5d41402abc4b2a76b9719d911017c592"`,
+		},
+		{
+			name: "unknown content",
+			attrs: map[string]tftypes.Value{
+				"content": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			},
+		},
+		{
+			name: "source",
+			attrs: map[string]tftypes.Value{
+				"source": tftypes.NewValue(tftypes.String, "file.txt"),
+			},
+		},
+		{
+			name: "adopted on creation",
+			attrs: map[string]tftypes.Value{
+				"content":        tftypes.NewValue(tftypes.String, "hello"),
+				"overwrite":      tftypes.NewValue(tftypes.Bool, false),
+				"adopt_existing": tftypes.NewValue(tftypes.Bool, true),
+			},
+			create: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs := map[string]tftypes.Value{
+				"overwrite":      tftypes.NewValue(tftypes.Bool, true),
+				"adopt_existing": tftypes.NewValue(tftypes.Bool, false),
+			}
+			for k, v := range tt.attrs {
+				attrs[k] = v
+			}
+			plan := testGarageObjectValue(t, attrs)
+
+			state := tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}
+			if tt.create {
+				state.Raw = tftypes.NewValue(plan.Raw.Type(), nil)
+			}
+
+			req := planmodifier.StringRequest{
+				Path:       path.Root("etag"),
+				Plan:       plan,
+				State:      state,
+				PlanValue:  types.StringUnknown(),
+				StateValue: types.StringNull(),
+			}
+			resp := &planmodifier.StringResponse{PlanValue: req.PlanValue}
+			contentETagPlanModifier{}.PlanModifyString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if tt.wantETag == "" {
+				if !resp.PlanValue.IsUnknown() {
+					t.Errorf("Expected unknown etag, got %s", resp.PlanValue)
+				}
+				return
+			}
+			if resp.PlanValue.ValueString() != tt.wantETag {
+				t.Errorf("Expected etag %s, got %s", tt.wantETag, resp.PlanValue)
+			}
+		})
+	}
+}
+
+func TestParseObjectImportID(t *testing.T) {
+	tests := []struct {
+		id         string
+		wantBucket string
+		wantKey    string
+		wantErr    bool
+	}{
+		{id: "bucket/key.txt", wantBucket: "bucket", wantKey: "key.txt"},
+		{id: "bucket/nested/path/key.txt", wantBucket: "bucket", wantKey: "nested/path/key.txt"},
+		{id: "bucket/", wantErr: true},
+		{id: "bucket", wantErr: true},
+		{id: "/key.txt", wantErr: true},
+		{id: "bucket//key.txt", wantErr: true},
+		{id: "bucket/" + strings.Repeat("a", 1025), wantErr: true},
+		// Keys are kept as is, without unescaping
+		{id: "bucket/reports/2024 Q3/résumé.pdf", wantBucket: "bucket", wantKey: "reports/2024 Q3/résumé.pdf"},
+		{id: "bucket/a+b#c?.txt", wantBucket: "bucket", wantKey: "a+b#c?.txt"},
+		{id: "bucket/100%25.txt", wantBucket: "bucket", wantKey: "100%25.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			bucket, key, err := parseObjectImportID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if bucket != tt.wantBucket || key != tt.wantKey {
+				t.Errorf("Expected (%q, %q), got (%q, %q)", tt.wantBucket, tt.wantKey, bucket, key)
+			}
+		})
+	}
+}
+
+func TestGarageObjectResourceImportState_identity(t *testing.T) {
+	tests := []struct {
+		name      string
+		bucket    string
+		key       string
+		wantError string
+	}{
+		{name: "valid", bucket: "bucket", key: "path/to/file.txt"},
+		{name: "empty key", bucket: "bucket", key: "", wantError: "Invalid Import Identity"},
+		{name: "empty bucket", bucket: "", key: "file.txt", wantError: "Invalid Import Identity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := &GarageObjectResource{}
+
+			var identitySchemaResp fwresource.IdentitySchemaResponse
+			r.IdentitySchema(ctx, fwresource.IdentitySchemaRequest{}, &identitySchemaResp)
+			identityType := identitySchemaResp.IdentitySchema.Type().TerraformType(ctx)
+			identity := &tfsdk.ResourceIdentity{
+				Schema: identitySchemaResp.IdentitySchema,
+				Raw: tftypes.NewValue(identityType, map[string]tftypes.Value{
+					"bucket": tftypes.NewValue(tftypes.String, tt.bucket),
+					"key":    tftypes.NewValue(tftypes.String, tt.key),
+				}),
+			}
+
+			empty := testGarageObjectValue(t, map[string]tftypes.Value{})
+			resp := &fwresource.ImportStateResponse{
+				State:    tfsdk.State{Schema: empty.Schema, Raw: tftypes.NewValue(empty.Raw.Type(), nil)},
+				Identity: identity,
+			}
+			r.ImportState(ctx, fwresource.ImportStateRequest{Identity: identity}, resp)
+
+			if tt.wantError != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantError {
+					t.Fatalf("Expected %q error, got %v", tt.wantError, resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var id types.String
+			resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("id"), &id)...)
+			if id.ValueString() != tt.bucket+"/"+tt.key {
+				t.Errorf("Expected id %s/%s, got %s", tt.bucket, tt.key, id.ValueString())
+			}
+
+			// The settings with a default are set so that the imported
+			// object plans no update
+			var data GarageObjectResourceModel
+			resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+			for _, setting := range []struct {
+				name  string
+				value types.Bool
+				want  bool
+			}{
+				{"overwrite", data.Overwrite, true},
+				{"adopt_existing", data.AdoptExisting, false},
+				{"retain_on_delete", data.RetainOnDelete, false},
+				{"rename_via_copy", data.RenameViaCopy, false},
+			} {
+				if !setting.value.Equal(types.BoolValue(setting.want)) {
+					t.Errorf("Expected %s %t, got %s", setting.name, setting.want, setting.value)
+				}
+			}
+		})
+	}
+}
+
+func TestObjectLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		key      string
+		wantURI  string
+		wantURL  string
+	}{
+		{
+			name:     "simple",
+			endpoint: "http://localhost:3900",
+			key:      "dir/file.txt",
+			wantURI:  "s3://bucket/dir/file.txt",
+			wantURL:  "http://localhost:3900/bucket/dir/file.txt",
+		},
+		{
+			name:     "trailing slash endpoint",
+			endpoint: "https://s3.example.com/",
+			key:      "file.txt",
+			wantURI:  "s3://bucket/file.txt",
+			wantURL:  "https://s3.example.com/bucket/file.txt",
+		},
+		{
+			name:     "spaces and unicode",
+			endpoint: "http://localhost:3900",
+			key:      "my docs/résumé 2024.pdf",
+			wantURI:  "s3://bucket/my docs/résumé 2024.pdf",
+			wantURL:  "http://localhost:3900/bucket/my%20docs/r%C3%A9sum%C3%A9%202024.pdf",
+		},
+		{
+			name:     "reserved characters",
+			endpoint: "http://localhost:3900",
+			key:      "a+b?c#d.txt",
+			wantURI:  "s3://bucket/a+b?c#d.txt",
+			wantURL:  "http://localhost:3900/bucket/a%2Bb%3Fc%23d.txt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := objectS3URI("bucket", tt.key); got != tt.wantURI {
+				t.Errorf("Expected s3_uri %q, got %q", tt.wantURI, got)
+			}
+
+			got := objectURL(tt.endpoint, "bucket", tt.key).ValueString()
+			if got != tt.wantURL {
+				t.Errorf("Expected url %q, got %q", tt.wantURL, got)
+			}
+
+			// The URL must decode back to the original key
+			parsed, err := url.Parse(got)
+			if err != nil {
+				t.Fatalf("Unable to parse url: %s", err)
+			}
+			if key := strings.TrimPrefix(parsed.Path, "/bucket/"); key != tt.key {
+				t.Errorf("Expected url to round-trip to %q, got %q", tt.key, key)
+			}
+		})
+	}
+
+	if !objectURL("", "bucket", "key").IsNull() {
+		t.Error("Expected null url without an S3 endpoint")
+	}
+}
+
+func TestCopySource(t *testing.T) {
+	tests := map[string]string{
+		"key.txt":                    "bucket/key.txt",
+		"reports/2024 Q3/résumé.pdf": "bucket/reports/2024%20Q3/r%C3%A9sum%C3%A9.pdf",
+		"a+b#c?d.txt":                "bucket/a%2Bb%23c%3Fd.txt",
+		"100%/x~y_z-1.txt":           "bucket/100%25/x~y_z-1.txt",
+	}
+
+	for key, expected := range tests {
+		got := copySource("bucket", key)
+		if got != expected {
+			t.Errorf("copySource(%q): expected %q, got %q", key, expected, got)
+		}
+		// The server unescapes it back to the key
+		if unescaped, err := url.PathUnescape(got); err != nil || unescaped != "bucket/"+key {
+			t.Errorf("Expected %q to unescape to the key, got %q and %v", got, unescaped, err)
+		}
+	}
+}
+
+func TestComputeChecksums(t *testing.T) {
+	body := strings.NewReader("hello")
+
+	checksums, err := computeChecksums(body)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if got := hex.EncodeToString(checksums.sha256); got != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("Unexpected SHA-256: %s", got)
+	}
+	if got := hex.EncodeToString(checksums.crc32); got != "3610a686" {
+		t.Errorf("Unexpected CRC32: %s", got)
+	}
+
+	// The body must be rewound for the upload
+	if rest, _ := io.ReadAll(body); string(rest) != "hello" {
+		t.Errorf("Expected body to be rewound, got %q", string(rest))
+	}
+}
+
+func TestChecksumHex(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  *string
+		want   string
+		wantOK bool
+	}{
+		{name: "nil", value: nil},
+		{name: "composite", value: aws.String("LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=-2")},
+		{name: "invalid", value: aws.String("not base64!")},
+		{
+			name:   "sha256",
+			value:  aws.String("LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ="),
+			want:   "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := checksumHex(tt.value)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("checksumHex() = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGarageObjectResourceValidateConfig(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(source, []byte("hello"), 0o600); err != nil {
+		t.Fatalf("Unable to write source file: %s", err)
+	}
+
+	tests := []struct {
+		name      string
+		attrs     map[string]tftypes.Value
+		wantError string
+	}{
+		{
+			name: "content only",
+			attrs: map[string]tftypes.Value{
+				"content": tftypes.NewValue(tftypes.String, "hello"),
+			},
+		},
+		{
+			name: "source only",
+			attrs: map[string]tftypes.Value{
+				"source": tftypes.NewValue(tftypes.String, source),
+			},
+		},
+		{
+			name: "empty content",
+			attrs: map[string]tftypes.Value{
+				"content": tftypes.NewValue(tftypes.String, ""),
+			},
+		},
+		{
+			name: "missing source file",
+			attrs: map[string]tftypes.Value{
+				"source": tftypes.NewValue(tftypes.String, filepath.Join(dir, "missing.txt")),
+			},
+			wantError: "Invalid Source File",
+		},
+		{
+			name: "source directory",
+			attrs: map[string]tftypes.Value{
+				"source": tftypes.NewValue(tftypes.String, dir),
+			},
+			wantError: "Invalid Source File",
+		},
+		{
+			name: "unknown source",
+			attrs: map[string]tftypes.Value{
+				"source": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			},
+		},
+		{
+			name: "both source and content",
+			attrs: map[string]tftypes.Value{
+				"source":  tftypes.NewValue(tftypes.String, source),
+				"content": tftypes.NewValue(tftypes.String, "hello"),
+			},
+			wantError: "Invalid Attribute Combination",
+		},
+		{
+			// Generated configuration of an imported object
+			name:  "neither source nor content",
+			attrs: map[string]tftypes.Value{},
+		},
+		{
+			name: "source_url only",
+			attrs: map[string]tftypes.Value{
+				"source_url":          tftypes.NewValue(tftypes.String, "https://example.com/app.tar.gz"),
+				"source_url_checksum": tftypes.NewValue(tftypes.String, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"),
+			},
+		},
+		{
+			name: "source_url and content",
+			attrs: map[string]tftypes.Value{
+				"source_url": tftypes.NewValue(tftypes.String, "https://example.com/app.tar.gz"),
+				"content":    tftypes.NewValue(tftypes.String, "hello"),
+			},
+			wantError: "Invalid Attribute Combination",
+		},
+		{
+			name: "source_url_checksum without source_url",
+			attrs: map[string]tftypes.Value{
+				"content":             tftypes.NewValue(tftypes.String, "hello"),
+				"source_url_checksum": tftypes.NewValue(tftypes.String, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"),
+			},
+			wantError: "Invalid Attribute Combination",
+		},
+		{
+			name: "malformed source_url_checksum",
+			attrs: map[string]tftypes.Value{
+				"source_url":          tftypes.NewValue(tftypes.String, "https://example.com/app.tar.gz"),
+				"source_url_checksum": tftypes.NewValue(tftypes.String, "md5:abc"),
+			},
+			wantError: "Invalid Attribute Value Match",
+		},
+		{
+			name: "malformed content_md5",
+			attrs: map[string]tftypes.Value{
+				"content":     tftypes.NewValue(tftypes.String, "hello"),
+				"content_md5": tftypes.NewValue(tftypes.String, "5d41402abc4b2a76b9719d911017c592"),
+			},
+			wantError: "Invalid Content MD5",
+		},
+		{
+			name: "content_base64 only",
+			attrs: map[string]tftypes.Value{
+				"content_base64": tftypes.NewValue(tftypes.String, "iVBORw0KGgo="),
+			},
+		},
+		{
+			name: "content_base64 and source",
+			attrs: map[string]tftypes.Value{
+				"content_base64": tftypes.NewValue(tftypes.String, "iVBORw0KGgo="),
+				"source":         tftypes.NewValue(tftypes.String, source),
+			},
+			wantError: "Invalid Attribute Combination",
+		},
+		{
+			name: "malformed content_base64",
+			attrs: map[string]tftypes.Value{
+				"content_base64": tftypes.NewValue(tftypes.String, "not base64!"),
+			},
+			wantError: "Invalid Base64 Value",
+		},
+		{
+			name: "content_wo with version",
+			attrs: map[string]tftypes.Value{
+				"content_wo":         tftypes.NewValue(tftypes.String, "secret"),
+				"content_wo_version": tftypes.NewValue(tftypes.Number, 1),
+			},
+		},
+		{
+			name: "content_wo and content",
+			attrs: map[string]tftypes.Value{
+				"content_wo":         tftypes.NewValue(tftypes.String, "secret"),
+				"content_wo_version": tftypes.NewValue(tftypes.Number, 1),
+				"content":            tftypes.NewValue(tftypes.String, "hello"),
+			},
+			wantError: "Invalid Attribute Combination",
+		},
+		{
+			name: "content_wo without version",
+			attrs: map[string]tftypes.Value{
+				"content_wo": tftypes.NewValue(tftypes.String, "secret"),
+			},
+			wantError: "Invalid Attribute Combination",
+		},
+		{
+			name: "content_wo hashed without version",
+			attrs: map[string]tftypes.Value{
+				"content_wo":      tftypes.NewValue(tftypes.String, "secret"),
+				"content_storage": tftypes.NewValue(tftypes.String, "hash"),
+			},
+		},
+		{
+			name: "content hashed",
+			attrs: map[string]tftypes.Value{
+				"content":         tftypes.NewValue(tftypes.String, "hello"),
+				"content_storage": tftypes.NewValue(tftypes.String, "hash"),
+			},
+			wantError: "Invalid Attribute Combination",
+		},
+		{
+			name: "hashed without content_wo",
+			attrs: map[string]tftypes.Value{
+				"content_storage": tftypes.NewValue(tftypes.String, "hash"),
+			},
+			wantError: "Missing Object Content",
+		},
+		{
+			name: "invalid content_storage",
+			attrs: map[string]tftypes.Value{
+				"content":         tftypes.NewValue(tftypes.String, "hello"),
+				"content_storage": tftypes.NewValue(tftypes.String, "md5"),
+			},
+			wantError: "Invalid Attribute Value Match",
+		},
+		{
+			name: "leading slash in key",
+			attrs: map[string]tftypes.Value{
+				"key":     tftypes.NewValue(tftypes.String, "/leading/slash"),
+				"content": tftypes.NewValue(tftypes.String, "hello"),
+			},
+			wantError: "Invalid Object Key",
+		},
+		{
+			name: "empty key",
+			attrs: map[string]tftypes.Value{
+				"key":     tftypes.NewValue(tftypes.String, ""),
+				"content": tftypes.NewValue(tftypes.String, "hello"),
+			},
+			wantError: "Invalid Object Key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs := map[string]tftypes.Value{
+				"bucket": tftypes.NewValue(tftypes.String, "bucket"),
+				"key":    tftypes.NewValue(tftypes.String, "key"),
+			}
+			for k, v := range tt.attrs {
+				attrs[k] = v
+			}
+
+			diags := testValidateResourceConfig(t, NewGarageObjectResource(), "garage_object", attrs)
+
+			if tt.wantError == "" {
+				if len(diags) != 0 {
+					t.Fatalf("Expected no diagnostics, got %+v", diags[0])
+				}
+				return
+			}
+
+			if len(diags) != 1 {
+				t.Fatalf("Expected 1 diagnostic, got %d", len(diags))
+			}
+			if diags[0].Summary != tt.wantError {
+				t.Errorf("Expected %q, got %q: %s", tt.wantError, diags[0].Summary, diags[0].Detail)
+			}
+			if diags[0].Attribute == nil {
+				t.Errorf("Expected an attribute path on the diagnostic")
+			}
+		})
+	}
+}
+
+// testValidateResourceConfig runs the framework config validation for a
+// resource and returns its diagnostics. Attributes missing from attrs are null.
+func testValidateResourceConfig(t *testing.T, r fwresource.Resource, typeName string, attrs map[string]tftypes.Value) []*tfprotov6.Diagnostic {
+	t.Helper()
+
+	ctx := context.Background()
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	objType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatalf("Unexpected schema type for %s", typeName)
+	}
+
+	values := map[string]tftypes.Value{}
+	for name, attrType := range objType.AttributeTypes {
+		if v, ok := attrs[name]; ok {
+			values[name] = v
+		} else {
+			values[name] = tftypes.NewValue(attrType, nil)
+		}
+	}
+
+	config, err := tfprotov6.NewDynamicValue(objType, tftypes.NewValue(objType, values))
+	if err != nil {
+		t.Fatalf("Unable to build config: %s", err)
+	}
+
+	server := providerserver.NewProtocol6(New("test")())()
+	resp, err := server.ValidateResourceConfig(ctx, &tfprotov6.ValidateResourceConfigRequest{
+		TypeName: typeName,
+		Config:   &config,
+		ClientCapabilities: &tfprotov6.ValidateResourceConfigClientCapabilities{
+			WriteOnlyAttributesAllowed: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	return resp.Diagnostics
+}
+
+// testS3Client builds an S3 client for a stub server.
+func testS3Client(endpoint string, optFns ...func(*s3.Options)) *s3.Client {
+	return s3.NewFromConfig(aws.Config{
+		Region:      "garage",
+		Credentials: credentials.NewStaticCredentialsProvider("GK00000000000000000000000", "secret", ""),
+	}, append([]func(*s3.Options){func(o *s3.Options) {
+		o.BaseEndpoint = aws.String(endpoint)
+		o.UsePathStyle = true
+	}}, optFns...)...)
+}
+
+// testGarageObjectValue builds a garage_object plan or state value. Computed
+// attributes missing from attrs are unknown, the others are null.
+func testGarageObjectValue(t *testing.T, attrs map[string]tftypes.Value) tfsdk.Plan {
+	t.Helper()
+
+	ctx := context.Background()
+
+	var schemaResp fwresource.SchemaResponse
+	NewGarageObjectResource().Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	objType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatal("Unexpected schema type for garage_object")
+	}
+
+	values := map[string]tftypes.Value{}
+	for name, attrType := range objType.AttributeTypes {
+		if v, ok := attrs[name]; ok {
+			values[name] = v
+		} else if a := schemaResp.Schema.Attributes[name]; a.IsComputed() {
+			values[name] = tftypes.NewValue(attrType, tftypes.UnknownValue)
+		} else {
+			values[name] = tftypes.NewValue(attrType, nil)
+		}
+	}
+
+	return tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objType, values)}
+}
+
+// testGarageObjectCreate runs Create for plan and returns the response.
+func testGarageObjectCreate(t *testing.T, r *GarageObjectResource, plan tfsdk.Plan) *fwresource.CreateResponse {
+	t.Helper()
+
+	resp := &fwresource.CreateResponse{
+		State: tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)},
+	}
+	r.Create(context.Background(), fwresource.CreateRequest{
+		Plan:   plan,
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+	}, resp)
+
+	return resp
+}
+
+// testGarageObjectStateAttrs returns the attributes of a fully known
+// garage_object state.
+func testGarageObjectStateAttrs() map[string]tftypes.Value {
+	return map[string]tftypes.Value{
+		"id":                  tftypes.NewValue(tftypes.String, "bucket/key.txt"),
+		"bucket":              tftypes.NewValue(tftypes.String, "bucket"),
+		"key":                 tftypes.NewValue(tftypes.String, "key.txt"),
+		"content":             tftypes.NewValue(tftypes.String, "hello"),
+		"source_url":          tftypes.NewValue(tftypes.String, nil),
+		"source_url_checksum": tftypes.NewValue(tftypes.String, nil),
+		"content_type":        tftypes.NewValue(tftypes.String, "text/plain"),
+		"content_encoding":    tftypes.NewValue(tftypes.String, nil),
+		"content_disposition": tftypes.NewValue(tftypes.String, nil),
+		"content_language":    tftypes.NewValue(tftypes.String, nil),
+		"etag":                tftypes.NewValue(tftypes.String, `"5d41402abc4b2a76b9719d911017c592"`),
+		"checksum_sha256":     tftypes.NewValue(tftypes.String, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"),
+		"checksum_crc32":      tftypes.NewValue(tftypes.String, "3610a686"),
+		"checksum_crc32c":     tftypes.NewValue(tftypes.String, nil),
+		"checksum_sha1":       tftypes.NewValue(tftypes.String, nil),
+		"last_modified":       tftypes.NewValue(tftypes.String, "2006-01-01T00:00:00Z"),
+		"s3_uri":              tftypes.NewValue(tftypes.String, "s3://bucket/key.txt"),
+		"url":                 tftypes.NewValue(tftypes.String, nil),
+		"overwrite":           tftypes.NewValue(tftypes.Bool, true),
+		"adopt_existing":      tftypes.NewValue(tftypes.Bool, false),
+		"content_sha256":      tftypes.NewValue(tftypes.String, nil),
+	}
+}
+
+// testGarageObjectRead runs Read for the state held in value and returns the
+// response.
+func testGarageObjectRead(t *testing.T, r *GarageObjectResource, value tfsdk.Plan) *fwresource.ReadResponse {
+	t.Helper()
+
+	state := tfsdk.State{Schema: value.Schema, Raw: value.Raw}
+	resp := &fwresource.ReadResponse{State: state}
+	r.Read(context.Background(), fwresource.ReadRequest{State: state}, resp)
+
+	return resp
+}
+
+// testGarageObjectUpdate runs Update from prior to plan and returns the
+// response.
+func testGarageObjectUpdate(t *testing.T, r *GarageObjectResource, plan, prior tfsdk.Plan) *fwresource.UpdateResponse {
+	t.Helper()
+
+	resp := &fwresource.UpdateResponse{State: tfsdk.State{Schema: prior.Schema, Raw: prior.Raw}}
+	r.Update(context.Background(), fwresource.UpdateRequest{
+		Plan:   plan,
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		State:  tfsdk.State{Schema: prior.Schema, Raw: prior.Raw},
+	}, resp)
+
+	return resp
+}
+
+// testAccS3Client builds an S3 client from the acceptance test environment.
+func testAccS3Client() *s3.Client {
+	return s3.NewFromConfig(aws.Config{
+		Region: "garage",
+		Credentials: credentials.NewStaticCredentialsProvider(
+			os.Getenv("GARAGE_ACCESS_KEY"),
+			os.Getenv("GARAGE_SECRET_KEY"),
+			"",
+		),
+	}, func(o *s3.Options) {
+		o.BaseEndpoint = aws.String(os.Getenv("GARAGE_S3_ENDPOINT"))
+		o.UsePathStyle = true
+	})
+}
+
+// testAccCheckGarageObjectContent fetches the object from Garage and checks
+// that its body matches the expected content.
+func testAccCheckGarageObjectContent(name, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", name)
+		}
+
+		out, err := testAccS3Client().GetObject(context.Background(), &s3.GetObjectInput{
+			Bucket: aws.String(rs.Primary.Attributes["bucket"]),
+			Key:    aws.String(rs.Primary.Attributes["key"]),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to get object: %w", err)
+		}
+		defer func() {
+			_ = out.Body.Close()
+		}()
+
+		body, err := io.ReadAll(out.Body)
+		if err != nil {
+			return fmt.Errorf("unable to read object body: %w", err)
+		}
+
+		if string(body) != expected {
+			return fmt.Errorf("expected object content %q, got %q", expected, string(body))
+		}
+
+		if etag := aws.ToString(out.ETag); etag != rs.Primary.Attributes["etag"] {
+			return fmt.Errorf("expected etag %q in state, got %q from Garage", etag, rs.Primary.Attributes["etag"])
+		}
+
+		return nil
+	}
+}
+
+// testAccCheckGarageObjectRetained checks that the object is still in the
+// bucket, then deletes it so that the bucket can be destroyed.
+func testAccCheckGarageObjectRetained(bucket, key string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+
+		if _, err := testAccS3Client().HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		}); err != nil {
+			return fmt.Errorf("expected object %s to be retained: %w", key, err)
+		}
+
+		_, err := testAccS3Client().DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		return err
+	}
+}
+
+func testAccGarageObjectResourceConfig_specialKeys(objects bool) string {
+	keys := testAccSpecialObjectKeys
+	if !objects {
+		keys = map[string]string{}
+	}
+	var entries []string
+	for _, name := range sortedKeys(keys) {
+		entries = append(entries, fmt.Sprintf("%q = %q", name, keys[name]))
+	}
+
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-object-keys"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+  owner = false
+
+  wait_for_propagation = "30s"
+}
+
+locals {
+  keys = {
+    %[2]s
+  }
+}
+
+resource "garage_object" "test" {
+  for_each   = local.keys
+  depends_on = [garage_bucket_permission.test]
+
+  bucket       = garage_bucket.test.id
+  key          = each.value
+  content      = each.value
+  content_type = "text/plain"
+}
+
+data "garage_object" "test" {
+  for_each = garage_object.test
+
+  bucket = garage_bucket.test.id
+  key    = each.value.key
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"), strings.Join(entries, "\n    "))
+}
+
+func testAccGarageObjectResourceConfig_noClobber() string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-object-noclobber"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_object" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket         = garage_bucket.test.id
+  key            = "bootstrap.conf"
+  content        = "initial"
+  content_type   = "text/plain"
+  overwrite      = false
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"))
+}
+
+// testAccGarageObjectResourceConfig_noClobberCopy adds a second no-clobber
+// object on the key already created by testAccGarageObjectResourceConfig_noClobber.
+func testAccGarageObjectResourceConfig_noClobberCopy(adopt bool) string {
+	return fmt.Sprintf(`
+resource "garage_object" "copy" {
+  depends_on = [garage_object.test]
+
+  bucket         = garage_bucket.test.id
+  key            = garage_object.test.key
+  content        = "initial"
+  content_type   = "text/plain"
+  overwrite      = false
+  adopt_existing = %[1]t
+}
+`, adopt)
+}
+
+func testAccGarageObjectResourceConfig_sourceURL(sourceURL, checksum string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-object-source-url"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_object" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket              = garage_bucket.test.id
+  key                 = "hello.txt"
+  source_url          = %[2]q
+  source_url_checksum = %[3]q
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"), sourceURL, checksum)
+}
+
+func testAccGarageObjectResourceConfig_empty() string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-object-empty"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_object" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket  = garage_bucket.test.id
+  key     = "folder/"
+  content = ""
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"))
+}
+
+func testAccGarageObjectResourceConfig_writeOnly(content string, version int) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-object-wo"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_object" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket             = garage_bucket.test.id
+  key                = "secret.conf"
+  content_wo         = %[2]q
+  content_wo_version = %[3]d
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"), content, version)
+}
+
+func testAccGarageObjectResourceConfig_contentStorage(content string, hashed bool) string {
+	object := fmt.Sprintf("content = %q", content)
+	if hashed {
+		object = fmt.Sprintf("content_wo      = %q\n  content_storage = \"hash\"", content)
+	}
+
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-object-hash"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  permissions = {
+    read  = true
+    write = true
+  }
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_object" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket = garage_bucket.test.id
+  key    = "config.yaml"
+  %[2]s
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"), object)
+}
+
+// testSHA256Hex returns the hex-encoded SHA-256 of content.
+func testSHA256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func testAccGarageObjectResourceConfig_retainOnDelete(withObject bool) string {
+	object := ""
+	if withObject {
+		object = `
+resource "garage_object" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket           = garage_bucket.test.id
+  key              = "retained.txt"
+  content          = "retained"
+  retain_on_delete = true
+}
+`
+	}
+
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-object-retain"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+
+  wait_for_propagation = "30s"
+}
+%[2]s`, os.Getenv("GARAGE_ACCESS_KEY"), object)
+}
+
+func testAccGarageObjectResourceConfig_renameViaCopy(key string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-object-rename"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_object" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket          = "test-bucket-object-rename"
+  key             = %[2]q
+  content         = "renamed-content"
+  rename_via_copy = true
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"), key)
+}
+
+func testAccGarageObjectResourceConfig_metadata() string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-object-metadata"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_object" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket        = garage_bucket.test.id
+  key           = "meta.txt"
+  content       = "metadata"
+  cache_control = "max-age=3600"
+
+  metadata = {
+    owner = "team-a"
+  }
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"))
+}
+
+func testAccGarageObjectResourceConfig_headers() string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-object-import"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_object" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket              = garage_bucket.test.id
+  key                 = "docs/index.html"
+  content             = "<h1>Hello</h1>"
+  content_encoding    = "identity"
+  content_disposition = "inline"
+  content_language    = "en"
+}
+`, os.Getenv("GARAGE_ACCESS_KEY"))
+}
+
+func testAccGarageObjectResourceConfig(content string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+		resource "garage_bucket" "test" {
+			global_alias = "test-bucket-object"
+		}
+		
+		resource "garage_bucket_permission" "test" {
+			bucket_id = garage_bucket.test.id
+			access_key_id = %[2]q
+			
+			read  = true
+			write = true
+			owner = false
+
+			wait_for_propagation = "30s"
+		}
+		
+		resource "garage_object" "test" {
+			depends_on = [garage_bucket_permission.test]
+
+			bucket       = garage_bucket.test.id
+			key          = "test-object.txt"
+			content      = %[1]q
+			content_type = "text/plain"
+			
+			
+		}
+		`, content, os.Getenv("GARAGE_ACCESS_KEY"),
+	)
+}
+
+func TestGarageObjectResourceCreate_fakeContent(t *testing.T) {
+	fake := newFakeObjectAPI("bucket")
+	r := &GarageObjectResource{s3Client: fake}
+	plan := testGarageObjectValue(t, map[string]tftypes.Value{
+		"bucket":         tftypes.NewValue(tftypes.String, "bucket"),
+		"key":            tftypes.NewValue(tftypes.String, "notes/hello"),
+		"content":        tftypes.NewValue(tftypes.String, "hello"),
+		"overwrite":      tftypes.NewValue(tftypes.Bool, true),
+		"adopt_existing": tftypes.NewValue(tftypes.Bool, false),
+	})
+
+	resp := testGarageObjectCreate(t, r, plan)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	object := fake.object("bucket", "notes/hello")
+	if object == nil || string(object.body) != "hello" {
+		t.Fatalf("Expected hello to be uploaded, got %+v", object)
+	}
+	if fake.called("HeadObject") && fake.calls[0] == "HeadObject" {
+		t.Errorf("Expected no existence check with overwrite, got calls %v", fake.calls)
+	}
+
+	var state GarageObjectResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+	if state.ID.ValueString() != "bucket/notes/hello" || state.S3URI.ValueString() != "s3://bucket/notes/hello" {
+		t.Errorf("Unexpected id %s and s3_uri %s", state.ID, state.S3URI)
+	}
+	if state.ETag.ValueString() != contentETag("hello") {
+		t.Errorf("Expected etag %s, got %s", contentETag("hello"), state.ETag)
+	}
+	if state.ContentType.ValueString() != "text/plain" || aws.ToString(object.contentType) != "text/plain" {
+		t.Errorf("Expected text/plain content without extension, got %s and %s", state.ContentType, aws.ToString(object.contentType))
+	}
+	if state.ChecksumSHA256.ValueString() != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" || state.ChecksumCRC32.ValueString() != "3610a686" {
+		t.Errorf("Unexpected checksums %s and %s", state.ChecksumSHA256, state.ChecksumCRC32)
+	}
+	if state.LastModified.IsUnknown() || state.LastModified.IsNull() {
+		t.Errorf("Expected last_modified to be set, got %s", state.LastModified)
+	}
+}
+
+func TestGarageObjectResourceCreate_fakeChecksumAlgorithm(t *testing.T) {
+	tests := []struct {
+		algorithm    string
+		skip         bool
+		expected     map[s3types.ChecksumAlgorithm]string
+		crc32c, sha1 string
+		expectedAlgo s3types.ChecksumAlgorithm
+	}{
+		{
+			// The checksum sent before checksum_algorithm existed
+			expected: map[s3types.ChecksumAlgorithm]string{s3types.ChecksumAlgorithmSha256: "LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ="},
+		},
+		{
+			algorithm:    "SHA256",
+			expected:     map[s3types.ChecksumAlgorithm]string{s3types.ChecksumAlgorithmSha256: "LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ="},
+			expectedAlgo: s3types.ChecksumAlgorithmSha256,
+		},
+		{
+			algorithm:    "CRC32",
+			expected:     map[s3types.ChecksumAlgorithm]string{s3types.ChecksumAlgorithmCrc32: "NhCmhg=="},
+			expectedAlgo: s3types.ChecksumAlgorithmCrc32,
+		},
+		{
+			algorithm:    "CRC32C",
+			expected:     map[s3types.ChecksumAlgorithm]string{s3types.ChecksumAlgorithmCrc32c: "mnG7TA=="},
+			crc32c:       "9a71bb4c",
+			expectedAlgo: s3types.ChecksumAlgorithmCrc32c,
+		},
+		{
+			algorithm:    "SHA1",
+			expected:     map[s3types.ChecksumAlgorithm]string{s3types.ChecksumAlgorithmSha1: "qvTGHdzF6KLavt4PO0gs2a6pQ00="},
+			sha1:         "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d",
+			expectedAlgo: s3types.ChecksumAlgorithmSha1,
+		},
+		{algorithm: "none", expected: map[s3types.ChecksumAlgorithm]string{}},
+		{skip: true, expected: map[s3types.ChecksumAlgorithm]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s skip=%t", tt.algorithm, tt.skip), func(t *testing.T) {
+			algorithm := tftypes.NewValue(tftypes.String, nil)
+			if tt.algorithm != "" {
+				algorithm = tftypes.NewValue(tftypes.String, tt.algorithm)
+			}
+			fake := newFakeObjectAPI("bucket")
+			r := &GarageObjectResource{s3Client: fake, skipChecksumHeaders: tt.skip}
+			plan := testGarageObjectValue(t, map[string]tftypes.Value{
+				"bucket":             tftypes.NewValue(tftypes.String, "bucket"),
+				"key":                tftypes.NewValue(tftypes.String, "notes/hello"),
+				"content":            tftypes.NewValue(tftypes.String, "hello"),
+				"checksum_algorithm": algorithm,
+				"overwrite":          tftypes.NewValue(tftypes.Bool, true),
+				"adopt_existing":     tftypes.NewValue(tftypes.Bool, false),
+			})
+
+			resp := testGarageObjectCreate(t, r, plan)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			object := fake.object("bucket", "notes/hello")
+			if object.checksumAlgorithm != tt.expectedAlgo || !reflect.DeepEqual(object.checksums, tt.expected) {
+				t.Errorf("Expected checksums %v with algorithm %q, got %v with %q", tt.expected, tt.expectedAlgo, object.checksums, object.checksumAlgorithm)
+			}
+
+			var state GarageObjectResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			// SHA-256 and CRC32 are always known, the others when selected
+			if state.ChecksumSHA256.IsNull() || state.ChecksumCRC32.ValueString() != "3610a686" {
+				t.Errorf("Unexpected checksums %s and %s", state.ChecksumSHA256, state.ChecksumCRC32)
+			}
+			if state.ChecksumCRC32C.ValueString() != tt.crc32c || state.ChecksumSHA1.ValueString() != tt.sha1 {
+				t.Errorf("Expected crc32c %q and sha1 %q, got %s and %s", tt.crc32c, tt.sha1, state.ChecksumCRC32C, state.ChecksumSHA1)
+			}
+		})
+	}
+}
+
+func TestGarageObjectResourceModifyPlan_checksumAlgorithm(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		skip      bool
+		wantError bool
+	}{
+		{algorithm: "SHA256", skip: true, wantError: true},
+		{algorithm: "CRC32C", skip: true, wantError: true},
+		{algorithm: "none", skip: true},
+		{algorithm: "CRC32C"},
+		{skip: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s skip=%t", tt.algorithm, tt.skip), func(t *testing.T) {
+			algorithm := tftypes.NewValue(tftypes.String, nil)
+			if tt.algorithm != "" {
+				algorithm = tftypes.NewValue(tftypes.String, tt.algorithm)
+			}
+			plan := testGarageObjectValue(t, map[string]tftypes.Value{
+				"bucket":             tftypes.NewValue(tftypes.String, "bucket"),
+				"key":                tftypes.NewValue(tftypes.String, "notes/hello"),
+				"content":            tftypes.NewValue(tftypes.String, "hello"),
+				"checksum_algorithm": algorithm,
+			})
+
+			r := &GarageObjectResource{skipChecksumHeaders: tt.skip}
+			resp := &fwresource.ModifyPlanResponse{Plan: plan}
+			r.ModifyPlan(context.Background(), fwresource.ModifyPlanRequest{
+				Plan:   plan,
+				Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+			}, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("Expected error %t, got %v", tt.wantError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestGarageObjectResourceModifyPlan_contentStorage(t *testing.T) {
+	tests := []struct {
+		name    string
+		attrs   map[string]tftypes.Value
+		created bool
+		want    types.String
+	}{
+		{
+			name:  "literal",
+			attrs: map[string]tftypes.Value{"content": tftypes.NewValue(tftypes.String, "hello")},
+			want:  types.StringNull(),
+		},
+		{
+			name: "hash",
+			attrs: map[string]tftypes.Value{
+				"content_wo":      tftypes.NewValue(tftypes.String, "hello"),
+				"content_storage": tftypes.NewValue(tftypes.String, "hash"),
+			},
+			want: types.StringValue("2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"),
+		},
+		{
+			name: "unknown content_wo",
+			attrs: map[string]tftypes.Value{
+				"content_wo":      tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"content_storage": tftypes.NewValue(tftypes.String, "hash"),
+			},
+			want: types.StringUnknown(),
+		},
+		{
+			name: "adopted on creation",
+			attrs: map[string]tftypes.Value{
+				"content_wo":      tftypes.NewValue(tftypes.String, "hello"),
+				"content_storage": tftypes.NewValue(tftypes.String, "hash"),
+				"overwrite":       tftypes.NewValue(tftypes.Bool, false),
+				"adopt_existing":  tftypes.NewValue(tftypes.Bool, true),
+			},
+			created: true,
+			want:    types.StringUnknown(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs := map[string]tftypes.Value{
+				"bucket":         tftypes.NewValue(tftypes.String, "bucket"),
+				"key":            tftypes.NewValue(tftypes.String, "key.txt"),
+				"overwrite":      tftypes.NewValue(tftypes.Bool, true),
+				"adopt_existing": tftypes.NewValue(tftypes.Bool, false),
+			}
+			for k, v := range tt.attrs {
+				attrs[k] = v
+			}
+			plan := testGarageObjectValue(t, attrs)
+			state := tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}
+			if tt.created {
+				state.Raw = tftypes.NewValue(plan.Raw.Type(), nil)
+			}
+
+			r := &GarageObjectResource{}
+			resp := &fwresource.ModifyPlanResponse{Plan: plan}
+			r.ModifyPlan(context.Background(), fwresource.ModifyPlanRequest{
+				Plan:   plan,
+				State:  state,
+				Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+			}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var got types.String
+			resp.Diagnostics.Append(resp.Plan.GetAttribute(context.Background(), path.Root("content_sha256"), &got)...)
+			if !got.Equal(tt.want) {
+				t.Errorf("Expected content_sha256 %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGarageObjectResourceModifyPlan_missingContent(t *testing.T) {
+	tests := []struct {
+		name      string
+		attrs     map[string]tftypes.Value
+		imported  bool
+		wantError bool
+	}{
+		{
+			name:      "no content on creation",
+			wantError: true,
+		},
+		{
+			name:     "imported object without content",
+			imported: true,
+		},
+		{
+			name:  "content",
+			attrs: map[string]tftypes.Value{"content": tftypes.NewValue(tftypes.String, "hello")},
+		},
+		{
+			name:  "unknown source",
+			attrs: map[string]tftypes.Value{"source": tftypes.NewValue(tftypes.String, tftypes.UnknownValue)},
+		},
+		{
+			name:  "source_url",
+			attrs: map[string]tftypes.Value{"source_url": tftypes.NewValue(tftypes.String, "https://example.com/file.txt")},
+		},
+		{
+			name: "hashed content_wo",
+			attrs: map[string]tftypes.Value{
+				"content_wo":      tftypes.NewValue(tftypes.String, "hello"),
+				"content_storage": tftypes.NewValue(tftypes.String, "hash"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs := map[string]tftypes.Value{
+				"bucket":         tftypes.NewValue(tftypes.String, "bucket"),
+				"key":            tftypes.NewValue(tftypes.String, "key.txt"),
+				"overwrite":      tftypes.NewValue(tftypes.Bool, true),
+				"adopt_existing": tftypes.NewValue(tftypes.Bool, false),
+			}
+			for k, v := range tt.attrs {
+				attrs[k] = v
+			}
+			plan := testGarageObjectValue(t, attrs)
+			state := tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Raw.Type(), nil)}
+			if tt.imported {
+				state.Raw = plan.Raw
+			}
+
+			r := &GarageObjectResource{}
+			resp := &fwresource.ModifyPlanResponse{Plan: plan}
+			r.ModifyPlan(context.Background(), fwresource.ModifyPlanRequest{
+				Plan:   plan,
+				State:  state,
+				Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+			}, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Fatalf("Expected error %t, got %v", tt.wantError, resp.Diagnostics)
+			}
+			if tt.wantError && resp.Diagnostics.Errors()[0].Summary() != "Missing Object Content" {
+				t.Errorf("Expected a Missing Object Content error, got %v", resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestRenameViaCopyDisabled(t *testing.T) {
+	for _, rename := range []bool{false, true} {
+		t.Run(fmt.Sprintf("rename_via_copy=%t", rename), func(t *testing.T) {
+			plan := testGarageObjectValue(t, map[string]tftypes.Value{
+				"bucket":          tftypes.NewValue(tftypes.String, "bucket"),
+				"key":             tftypes.NewValue(tftypes.String, "renamed.txt"),
+				"rename_via_copy": tftypes.NewValue(tftypes.Bool, rename),
+			})
+
+			resp := &stringplanmodifier.RequiresReplaceIfFuncResponse{}
+			renameViaCopyDisabled(context.Background(), planmodifier.StringRequest{Plan: plan}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if resp.RequiresReplace == rename {
+				t.Errorf("Expected replacement %t, got %t", !rename, resp.RequiresReplace)
+			}
+		})
+	}
+}
+
+func TestGarageObjectResourceCreate_fakeSource(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "style.css")
+	if err := os.WriteFile(source, []byte("body { margin: 0 }"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := newFakeObjectAPI("bucket")
+	r := &GarageObjectResource{s3Client: fake}
+	plan := testGarageObjectValue(t, map[string]tftypes.Value{
+		"bucket":         tftypes.NewValue(tftypes.String, "bucket"),
+		"key":            tftypes.NewValue(tftypes.String, "css/style.css"),
+		"source":         tftypes.NewValue(tftypes.String, source),
+		"overwrite":      tftypes.NewValue(tftypes.Bool, false),
+		"adopt_existing": tftypes.NewValue(tftypes.Bool, false),
+	})
+
+	resp := testGarageObjectCreate(t, r, plan)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	object := fake.object("bucket", "css/style.css")
+	if object == nil || string(object.body) != "body { margin: 0 }" {
+		t.Fatalf("Expected the file to be uploaded, got %+v", object)
+	}
+	if len(fake.calls) < 2 || fake.calls[0] != "HeadObject" || fake.calls[1] != "PutObject" {
+		t.Errorf("Expected an existence check before the upload, got calls %v", fake.calls)
+	}
+
+	var state GarageObjectResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+	if state.ContentType.ValueString() != "text/css; charset=utf-8" {
+		t.Errorf("Expected the content type of the extension, got %s", state.ContentType)
+	}
+	if state.ETag.ValueString() != contentETag("body { margin: 0 }") {
+		t.Errorf("Expected the MD5 of the file as etag, got %s", state.ETag)
+	}
+
+	// A second creation of the same key fails instead of overwriting it
+	resp = testGarageObjectCreate(t, r, plan)
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Object Already Exists" {
+		t.Errorf("Expected Object Already Exists, got %v", resp.Diagnostics)
+	}
+}
+
+func TestGarageObjectResourceRead_fakeContentDrift(t *testing.T) {
+	sentinel := func(remote string) string {
+		return fmt.Sprintf("<changed outside of Terraform, ETag %s>", contentETag(remote))
+	}
+	binary := "\xff\xfe\x00binary"
+
+	tests := []struct {
+		name        string
+		remote      string
+		base64      bool
+		maxSize     int64
+		wantContent string
+		wantGet     bool
+	}{
+		{name: "unchanged", remote: "hello", wantContent: "hello"},
+		{name: "overwritten", remote: "changed outside", wantContent: "changed outside", wantGet: true},
+		// Binary bodies cannot be stored in content without mangling them
+		{name: "binary", remote: binary, wantContent: sentinel(binary), wantGet: true},
+		{name: "binary base64", remote: binary, base64: true, wantContent: base64.StdEncoding.EncodeToString([]byte(binary)), wantGet: true},
+		// Large bodies are not downloaded into the state
+		{name: "too large", remote: "changed outside", maxSize: 8, wantContent: sentinel("changed outside")},
+		{name: "too large base64", remote: "changed outside", base64: true, maxSize: 8, wantContent: sentinel("changed outside")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.maxSize != 0 {
+				defer func(previous int64) { maxDriftContentSize = previous }(maxDriftContentSize)
+				maxDriftContentSize = tt.maxSize
+			}
+			fake := newFakeObjectAPI("bucket")
+			fake.put("bucket", "key.txt", tt.remote)
+
+			attrs := testGarageObjectStateAttrs()
+			if tt.base64 {
+				attrs["content"] = tftypes.NewValue(tftypes.String, nil)
+				attrs["content_base64"] = tftypes.NewValue(tftypes.String, base64.StdEncoding.EncodeToString([]byte("hello")))
+			}
+
+			r := &GarageObjectResource{s3Client: fake}
+			resp := testGarageObjectRead(t, r, testGarageObjectValue(t, attrs))
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var state GarageObjectResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+			got := state.Content
+			if tt.base64 {
+				got = state.ContentBase64
+			}
+			if got.ValueString() != tt.wantContent {
+				t.Errorf("Expected content %q, got %s", tt.wantContent, got)
+			}
+			if state.ETag.ValueString() != contentETag(tt.remote) {
+				t.Errorf("Expected the remote etag, got %s", state.ETag)
+			}
+			if fake.called("GetObject") != tt.wantGet {
+				t.Errorf("Expected body download %v, got calls %v", tt.wantGet, fake.calls)
+			}
+		})
+	}
+}
+
+func TestGarageObjectResourceRead_fakeErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		buckets     []string
+		headErr     error
+		wantRemoved bool
+		wantError   string
+	}{
+		{name: "missing object", buckets: []string{"bucket"}, wantRemoved: true},
+		{name: "missing bucket", wantRemoved: true},
+		{
+			name:      "access denied",
+			buckets:   []string{"bucket"},
+			headErr:   &smithy.GenericAPIError{Code: "AccessDenied", Message: "Forbidden"},
+			wantError: "garage_bucket_permission",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeObjectAPI(tt.buckets...)
+			if tt.headErr != nil {
+				fake.errs["HeadObject"] = tt.headErr
+			}
+
+			r := &GarageObjectResource{s3Client: fake, s3AccessKey: "GKtest"}
+			resp := testGarageObjectRead(t, r, testGarageObjectValue(t, testGarageObjectStateAttrs()))
+
+			if removed := resp.State.Raw.IsNull(); removed != tt.wantRemoved {
+				t.Errorf("Expected removed %v, got %v", tt.wantRemoved, removed)
+			}
+			if tt.wantError == "" {
+				if resp.Diagnostics.HasError() {
+					t.Errorf("Unexpected diagnostics: %v", resp.Diagnostics)
+				}
+				return
+			}
+			if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), tt.wantError) ||
+				!strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "GKtest") {
+				t.Errorf("Expected an error naming the key and %q, got %v", tt.wantError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestGarageObjectResourceDelete_fake(t *testing.T) {
+	tests := []struct {
+		name      string
+		buckets   []string
+		existing  bool
+		deleteErr error
+		wantError bool
+	}{
+		{name: "existing object", buckets: []string{"bucket"}, existing: true},
+		{name: "missing object", buckets: []string{"bucket"}},
+		{name: "missing bucket"},
+		{
+			name:      "access denied",
+			buckets:   []string{"bucket"},
+			existing:  true,
+			deleteErr: &smithy.GenericAPIError{Code: "AccessDenied", Message: "Forbidden"},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeObjectAPI(tt.buckets...)
+			if tt.existing {
+				fake.put("bucket", "key.txt", "hello")
+			}
+			if tt.deleteErr != nil {
+				fake.errs["DeleteObject"] = tt.deleteErr
+			}
+
+			r := &GarageObjectResource{s3Client: fake}
+			state := testGarageObjectValue(t, testGarageObjectStateAttrs())
+			resp := &fwresource.DeleteResponse{State: tfsdk.State{Schema: state.Schema, Raw: state.Raw}}
+			r.Delete(context.Background(), fwresource.DeleteRequest{
+				State: tfsdk.State{Schema: state.Schema, Raw: state.Raw},
+			}, resp)
+
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("Expected error %v, got %v", tt.wantError, resp.Diagnostics)
+			}
+			if exists := len(tt.buckets) > 0 && fake.object("bucket", "key.txt") != nil; exists != tt.wantError {
+				t.Errorf("Expected the object to exist %v, got %v", tt.wantError, exists)
+			}
+		})
+	}
 }
@@ -0,0 +1,238 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+const (
+	defaultPresignExpiry = 15 * time.Minute
+	// SigV4 presigned URLs cannot be valid for longer than 7 days
+	maxPresignExpiry = 7 * 24 * time.Hour
+)
+
+var _ ephemeral.EphemeralResourceWithConfigure = &GaragePresignedURLEphemeralResource{}
+
+// GaragePresignedURLEphemeralResource signs a URL for an object locally. The
+// URL embeds a signature made with the provider credentials, so it must never
+// be stored in the state.
+type GaragePresignedURLEphemeralResource struct {
+	s3Client *s3.Client
+}
+
+type GaragePresignedURLEphemeralResourceModel struct {
+	Bucket    types.String `tfsdk:"bucket"`
+	Key       types.String `tfsdk:"key"`
+	Method    types.String `tfsdk:"method"`
+	ExpiresIn types.String `tfsdk:"expires_in"`
+
+	ContentType   types.String `tfsdk:"content_type"`
+	ContentLength types.Int64  `tfsdk:"content_length"`
+
+	URL       types.String `tfsdk:"url"`
+	ExpiresAt types.String `tfsdk:"expires_at"`
+	Headers   types.Map    `tfsdk:"headers"`
+}
+
+func NewGaragePresignedURLEphemeralResource() ephemeral.EphemeralResource {
+	return &GaragePresignedURLEphemeralResource{}
+}
+
+func (e *GaragePresignedURLEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_presigned_url"
+}
+
+func (e *GaragePresignedURLEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Generates a short-lived presigned URL for an object in a Garage bucket, without storing it in the plan or state",
+		Attributes: map[string]schema.Attribute{
+			"bucket": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the bucket containing the object",
+			},
+			"key": schema.StringAttribute{
+				Required:    true,
+				Description: "Key (name) of the object in the bucket",
+			},
+			"method": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "HTTP method the URL is signed for: GET, HEAD or PUT. Defaults to GET",
+				Validators: []validator.String{
+					stringvalidator.OneOf(http.MethodGet, http.MethodHead, http.MethodPut),
+				},
+			},
+			"expires_in": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "How long the URL stays valid, as a Go duration between 1s and 168h (7 days). Defaults to 15m",
+				Validators: []validator.String{
+					validators.DurationBetween(time.Second, maxPresignExpiry),
+				},
+			},
+			"content_type": schema.StringAttribute{
+				Optional:    true,
+				Description: "Content-Type the upload must be sent with. Only valid with the PUT method",
+			},
+			"content_length": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Exact size in bytes the upload must have. Only valid with the PUT method. A presigned URL cannot enforce a size range, only an exact size",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"url": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Presigned URL of the object",
+			},
+			"expires_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Time at which the URL expires, in RFC 3339 format",
+			},
+			"headers": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Signed headers the request must be sent with, e.g. Content-Type and Content-Length for uploads. The request is rejected if they differ",
+			},
+		},
+	}
+}
+
+func (e *GaragePresignedURLEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			"Expected *GarageProviderModel",
+		)
+		return
+	}
+
+	e.s3Client = providerData.S3Client
+}
+
+func (e *GaragePresignedURLEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data GaragePresignedURLEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if e.s3Client == nil {
+		resp.Diagnostics.AddError(
+			"Missing S3 Endpoint",
+			"S3 endpoint must be configured in endpoints.s3 for object operations",
+		)
+		return
+	}
+
+	if data.Method.IsNull() {
+		data.Method = types.StringValue(http.MethodGet)
+	}
+	if data.Method.ValueString() != http.MethodPut {
+		for _, attribute := range []struct {
+			name string
+			set  bool
+		}{
+			{name: "content_type", set: !data.ContentType.IsNull()},
+			{name: "content_length", set: !data.ContentLength.IsNull()},
+		} {
+			if attribute.set {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(attribute.name),
+					"Invalid Attribute Combination",
+					fmt.Sprintf("%s can only be set when method is PUT, got %s", attribute.name, data.Method.ValueString()),
+				)
+			}
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	expiresIn := defaultPresignExpiry
+	if data.ExpiresIn.IsNull() {
+		data.ExpiresIn = types.StringValue(expiresIn.String())
+	} else {
+		// Already validated, unless the value was unknown at validation
+		var err error
+		expiresIn, err = time.ParseDuration(data.ExpiresIn.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("expires_in"),
+				"Invalid Duration",
+				fmt.Sprintf("Unable to parse expires_in %q: %s", data.ExpiresIn.ValueString(), err),
+			)
+			return
+		}
+	}
+
+	presignClient := s3.NewPresignClient(e.s3Client, s3.WithPresignExpires(expiresIn))
+	bucket := aws.String(data.Bucket.ValueString())
+	key := aws.String(data.Key.ValueString())
+	signedAt := time.Now()
+
+	var presigned *v4.PresignedHTTPRequest
+	var err error
+	switch data.Method.ValueString() {
+	case http.MethodHead:
+		presigned, err = presignClient.PresignHeadObject(ctx, &s3.HeadObjectInput{Bucket: bucket, Key: key})
+	case http.MethodPut:
+		presigned, err = presignClient.PresignPutObject(ctx, &s3.PutObjectInput{
+			Bucket:        bucket,
+			Key:           key,
+			ContentType:   data.ContentType.ValueStringPointer(),
+			ContentLength: data.ContentLength.ValueInt64Pointer(),
+		})
+	default:
+		presigned, err = presignClient.PresignGetObject(ctx, &s3.GetObjectInput{Bucket: bucket, Key: key})
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Presign URL",
+			fmt.Sprintf("Could not presign %s URL for object %s in bucket %s: %s",
+				data.Method.ValueString(), data.Key.ValueString(), data.Bucket.ValueString(), err),
+		)
+		return
+	}
+
+	data.URL = types.StringValue(presigned.URL)
+	data.ExpiresAt = types.StringValue(signedAt.Add(expiresIn).UTC().Format(time.RFC3339))
+
+	// Host is set by every HTTP client from the URL, the callers only need
+	// to send the other signed headers
+	headers := make(map[string]attr.Value, len(presigned.SignedHeader))
+	for name, values := range presigned.SignedHeader {
+		if name == "Host" {
+			continue
+		}
+		headers[name] = types.StringValue(strings.Join(values, ","))
+	}
+	data.Headers = types.MapValueMust(types.StringType, headers)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
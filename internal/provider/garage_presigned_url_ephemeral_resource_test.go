@@ -0,0 +1,217 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccGaragePresignedURLEphemeralResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckS3(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesWithEcho,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGaragePresignedURLEphemeralResourceConfig(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("download").AtMapKey("method"), knownvalue.StringExact("GET")),
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("download").AtMapKey("expires_in"), knownvalue.StringExact("5m0s")),
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("download").AtMapKey("url"),
+						knownvalue.StringRegexp(regexp.MustCompile(`/test-bucket-presigned-url/presigned\.txt\?.*X-Amz-Signature=`))),
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("upload").AtMapKey("headers"), knownvalue.MapExact(map[string]knownvalue.Check{
+						"Content-Type":   knownvalue.StringExact("text/plain"),
+						"Content-Length": knownvalue.StringExact("5"),
+					})),
+				},
+			},
+		},
+	})
+}
+
+func TestGaragePresignedURLEphemeralResourceOpen(t *testing.T) {
+	tests := []struct {
+		name        string
+		attrs       map[string]tftypes.Value
+		wantMethod  string
+		wantExpires string
+		wantSeconds string
+		wantHeaders map[string]string
+		wantError   string
+	}{
+		{
+			name:        "defaults",
+			attrs:       map[string]tftypes.Value{},
+			wantMethod:  "GET",
+			wantExpires: "15m0s",
+			wantSeconds: "900",
+			wantHeaders: map[string]string{},
+		},
+		{
+			name: "put for an hour",
+			attrs: map[string]tftypes.Value{
+				"method":     tftypes.NewValue(tftypes.String, "PUT"),
+				"expires_in": tftypes.NewValue(tftypes.String, "1h"),
+			},
+			wantMethod:  "PUT",
+			wantExpires: "1h",
+			wantSeconds: "3600",
+			wantHeaders: map[string]string{},
+		},
+		{
+			name: "put with signed headers",
+			attrs: map[string]tftypes.Value{
+				"method":         tftypes.NewValue(tftypes.String, "PUT"),
+				"content_type":   tftypes.NewValue(tftypes.String, "application/gzip"),
+				"content_length": tftypes.NewValue(tftypes.Number, 1024),
+			},
+			wantMethod:  "PUT",
+			wantExpires: "15m0s",
+			wantSeconds: "900",
+			wantHeaders: map[string]string{"Content-Type": "application/gzip", "Content-Length": "1024"},
+		},
+		{
+			name: "content type with get",
+			attrs: map[string]tftypes.Value{
+				"content_type": tftypes.NewValue(tftypes.String, "application/gzip"),
+			},
+			wantError: "Invalid Attribute Combination",
+		},
+		{
+			// Skips the validator, like a value unknown at validation
+			name: "invalid expires_in",
+			attrs: map[string]tftypes.Value{
+				"expires_in": tftypes.NewValue(tftypes.String, "soon"),
+			},
+			wantError: "Invalid Duration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &GaragePresignedURLEphemeralResource{s3Client: testS3Client("http://localhost:3900")}
+			tt.attrs["bucket"] = tftypes.NewValue(tftypes.String, "bucket")
+			tt.attrs["key"] = tftypes.NewValue(tftypes.String, "dir/file.txt")
+
+			before := time.Now().UTC().Truncate(time.Second)
+			resp := testEphemeralResourceOpen(t, e, tt.attrs)
+
+			if tt.wantError != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantError {
+					t.Fatalf("Expected %q error, got %v", tt.wantError, resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var result GaragePresignedURLEphemeralResourceModel
+			resp.Diagnostics.Append(resp.Result.Get(context.Background(), &result)...)
+			if result.Method.ValueString() != tt.wantMethod || result.ExpiresIn.ValueString() != tt.wantExpires {
+				t.Errorf("Expected method %s and expires_in %s, got %s and %s", tt.wantMethod, tt.wantExpires, result.Method, result.ExpiresIn)
+			}
+
+			u, err := url.Parse(result.URL.ValueString())
+			if err != nil {
+				t.Fatalf("Invalid url %q: %s", result.URL.ValueString(), err)
+			}
+			if u.Host != "localhost:3900" || u.Path != "/bucket/dir/file.txt" {
+				t.Errorf("Unexpected url %s", u)
+			}
+			if u.Query().Get("X-Amz-Expires") != tt.wantSeconds || u.Query().Get("X-Amz-Signature") == "" {
+				t.Errorf("Expected a signature valid for %s seconds, got %s", tt.wantSeconds, u.RawQuery)
+			}
+
+			headers := map[string]string{}
+			for name, value := range result.Headers.Elements() {
+				if v, ok := value.(types.String); ok {
+					headers[name] = v.ValueString()
+				}
+			}
+			if fmt.Sprint(headers) != fmt.Sprint(tt.wantHeaders) {
+				t.Errorf("Expected headers %v, got %v", tt.wantHeaders, headers)
+			}
+			for name := range tt.wantHeaders {
+				if !strings.Contains(u.Query().Get("X-Amz-SignedHeaders"), strings.ToLower(name)) {
+					t.Errorf("Expected %s to be signed, got %s", name, u.Query().Get("X-Amz-SignedHeaders"))
+				}
+			}
+
+			expiresAt, err := time.Parse(time.RFC3339, result.ExpiresAt.ValueString())
+			if err != nil {
+				t.Fatalf("Invalid expires_at %q: %s", result.ExpiresAt.ValueString(), err)
+			}
+			if expiresAt.Before(before) || expiresAt.After(time.Now().Add(2*time.Hour)) {
+				t.Errorf("Unexpected expires_at %s", expiresAt)
+			}
+		})
+	}
+}
+
+func testAccGaragePresignedURLEphemeralResourceConfig() string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "test" {
+  global_alias = "test-bucket-presigned-url"
+}
+
+resource "garage_bucket_permission" "test" {
+  bucket_id     = garage_bucket.test.id
+  access_key_id = %[1]q
+
+  read  = true
+  write = true
+
+  wait_for_propagation = "30s"
+}
+
+resource "garage_object" "test" {
+  depends_on = [garage_bucket_permission.test]
+
+  bucket  = garage_bucket.test.global_alias
+  key     = "presigned.txt"
+  content = "hello"
+}
+
+ephemeral "garage_presigned_url" "test" {
+  bucket     = garage_object.test.bucket
+  key        = garage_object.test.key
+  expires_in = "5m0s"
+}
+
+ephemeral "garage_presigned_url" "upload" {
+  bucket         = garage_bucket.test.global_alias
+  key            = "upload.txt"
+  method         = "PUT"
+  content_type   = "text/plain"
+  content_length = 5
+}
+
+provider "echo" {
+  data = {
+    download = ephemeral.garage_presigned_url.test
+    upload   = ephemeral.garage_presigned_url.upload
+  }
+}
+
+resource "echo" "test" {}
+`, os.Getenv("GARAGE_ACCESS_KEY"))
+}
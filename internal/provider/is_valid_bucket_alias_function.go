@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &IsValidBucketAliasFunction{}
+
+func NewIsValidBucketAliasFunction() function.Function {
+	return &IsValidBucketAliasFunction{}
+}
+
+// IsValidBucketAliasFunction defines the function implementation.
+type IsValidBucketAliasFunction struct{}
+
+func (f *IsValidBucketAliasFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "is_valid_bucket_alias"
+}
+
+func (f *IsValidBucketAliasFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Checks whether a string is a valid global bucket alias",
+		MarkdownDescription: "Returns whether a string is accepted as `global_alias` of a `garage_bucket`, with the same rules: " +
+			"3 to 63 lowercase letters, digits, dashes and dots, starting and ending with a letter or digit, " +
+			"not formatted as an IP address, not starting with `xn--` and not ending with `-s3alias`. " +
+			"Use `validate_bucket_alias` to get the rule an alias breaks.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "alias",
+				MarkdownDescription: "The alias to check.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *IsValidBucketAliasFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var alias string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &alias))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, validators.ValidateBucketAlias(alias) == nil))
+}
@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccIsValidBucketAliasFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "valid" {
+  value = provider::garage::is_valid_bucket_alias("team-assets")
+}
+
+output "invalid" {
+  value = provider::garage::is_valid_bucket_alias("Team_Assets")
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("valid", knownvalue.Bool(true)),
+					statecheck.ExpectKnownOutputValue("invalid", knownvalue.Bool(false)),
+				},
+			},
+		},
+	})
+}
+
+func TestIsValidBucketAliasFunction_run(t *testing.T) {
+	f := NewIsValidBucketAliasFunction()
+
+	tests := map[string]bool{
+		"team-assets": true,
+		"a.b.c":       true,
+		"ab":          false,
+		"Team":        false,
+		"-team":       false,
+		"team.":       false,
+		"10.0.0.1":    false,
+	}
+
+	for alias, expected := range tests {
+		resp := testFunctionRun(t, f, types.StringValue(alias))
+		if resp.Error != nil || !resp.Result.Value().Equal(types.BoolValue(expected)) {
+			t.Errorf("is_valid_bucket_alias(%q): expected %t, got %s and %v", alias, expected, resp.Result.Value(), resp.Error)
+		}
+	}
+}
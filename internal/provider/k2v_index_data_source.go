@@ -0,0 +1,238 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+// defaultK2VIndexLimit is the number of partition keys returned when limit
+// is not set.
+const defaultK2VIndexLimit = 1000
+
+// k2vIndexPageSize is the number of partition keys requested per page. It is
+// a variable so that tests can page small indexes.
+var k2vIndexPageSize = 1000
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &K2VIndexDataSource{}
+
+func NewK2VIndexDataSource() datasource.DataSource {
+	return &K2VIndexDataSource{}
+}
+
+// K2VIndexDataSource defines the data source implementation.
+type K2VIndexDataSource struct {
+	client *client.K2VClient
+}
+
+// K2VIndexDataSourceModel describes the data source data model.
+type K2VIndexDataSourceModel struct {
+	Bucket        types.String `tfsdk:"bucket"`
+	Prefix        types.String `tfsdk:"prefix"`
+	Start         types.String `tfsdk:"start"`
+	Limit         types.Int64  `tfsdk:"limit"`
+	PartitionKeys types.List   `tfsdk:"partition_keys"`
+	Truncated     types.Bool   `tfsdk:"truncated"`
+	NextStart     types.String `tfsdk:"next_start"`
+}
+
+// k2vPartitionKeyAttrTypes are the attribute types of a partition key object.
+var k2vPartitionKeyAttrTypes = map[string]attr.Type{
+	"partition_key": types.StringType,
+	"entries":       types.Int64Type,
+	"conflicts":     types.Int64Type,
+	"values":        types.Int64Type,
+	"bytes":         types.Int64Type,
+}
+
+func (d *K2VIndexDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_k2v_index"
+}
+
+func (d *K2VIndexDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the partition keys of a bucket from the index of the K2V API, in the order of the server. Requires `endpoints.k2v` and the S3 credentials of a key with read access to the bucket.",
+
+		Attributes: map[string]schema.Attribute{
+			"bucket": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The name of the bucket.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"prefix": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list the partition keys starting with this prefix.",
+			},
+			"start": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "List the partition keys from this one, included. Use `next_start` of a previous read to continue it.",
+			},
+			"limit": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: fmt.Sprintf("The maximum number of partition keys to list. The index is read in pages until this many keys are listed. Default: `%d`.", defaultK2VIndexLimit),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"partition_keys": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "The partition keys, in the order of the server.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"partition_key": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The partition key.",
+						},
+						"entries": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "The number of items of the partition, deleted ones included until they are garbage collected.",
+						},
+						"conflicts": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "The number of items with concurrent values.",
+						},
+						"values": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "The number of values of the items, concurrent ones included.",
+						},
+						"bytes": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "The total size of the values, in bytes.",
+						},
+					},
+				},
+			},
+			"truncated": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether there are more partition keys than `limit`.",
+			},
+			"next_start": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The partition key to use as `start` to list the next keys. Null when `truncated` is false.",
+			},
+		},
+	}
+}
+
+func (d *K2VIndexDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	if providerData.K2VClient == nil {
+		resp.Diagnostics.AddError(
+			"Missing K2V Endpoint",
+			"K2V endpoint must be configured in endpoints.k2v for the K2V index",
+		)
+		return
+	}
+
+	d.client = providerData.K2VClient
+}
+
+func (d *K2VIndexDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data K2VIndexDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	limit := defaultK2VIndexLimit
+	if !data.Limit.IsNull() {
+		limit = int(data.Limit.ValueInt64())
+	}
+
+	tflog.Debug(ctx, "Reading K2V index data source", map[string]interface{}{
+		"bucket": data.Bucket.ValueString(),
+		"prefix": data.Prefix.ValueString(),
+		"limit":  limit,
+	})
+
+	keys, nextStart, err := readK2VIndex(ctx, d.client, data.Bucket.ValueString(), client.K2VIndexQuery{
+		Prefix: data.Prefix.ValueString(),
+		Start:  data.Start.ValueString(),
+		Limit:  limit,
+	})
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError(fmt.Sprintf("Unable to read K2V index of bucket %s", data.Bucket.ValueString()), err))
+		return
+	}
+
+	elements := make([]attr.Value, 0, len(keys))
+	for _, key := range keys {
+		elements = append(elements, types.ObjectValueMust(k2vPartitionKeyAttrTypes, map[string]attr.Value{
+			"partition_key": types.StringValue(key.PK),
+			"entries":       types.Int64Value(key.Entries),
+			"conflicts":     types.Int64Value(key.Conflicts),
+			"values":        types.Int64Value(key.Values),
+			"bytes":         types.Int64Value(key.Bytes),
+		}))
+	}
+
+	data.PartitionKeys = types.ListValueMust(types.ObjectType{AttrTypes: k2vPartitionKeyAttrTypes}, elements)
+	data.Truncated = types.BoolValue(nextStart != nil)
+	data.NextStart = types.StringPointerValue(nextStart)
+
+	tflog.Trace(ctx, "Read K2V index data source", map[string]interface{}{
+		"partition_keys": len(keys),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// readK2VIndex reads up to query.Limit partition keys of bucket, one page
+// after the other, and returns them in the order of the server with the
+// start of the next keys, nil when there are none.
+func readK2VIndex(ctx context.Context, c *client.K2VClient, bucket string, query client.K2VIndexQuery) ([]client.K2VPartitionKey, *string, error) {
+	var keys []client.K2VPartitionKey
+	start := query.Start
+
+	for len(keys) < query.Limit {
+		page, err := c.ReadIndex(ctx, bucket, client.K2VIndexQuery{
+			Prefix: query.Prefix,
+			Start:  start,
+			Limit:  min(k2vIndexPageSize, query.Limit-len(keys)),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		keys = append(keys, page.PartitionKeys...)
+		if !page.More {
+			return keys, nil, nil
+		}
+		if page.NextStart == nil {
+			return nil, nil, fmt.Errorf("the page after %d partition keys has no start", len(keys))
+		}
+		start = *page.NextStart
+	}
+
+	return keys, &start, nil
+}
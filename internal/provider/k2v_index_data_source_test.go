@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+// newTestK2VIndexServer serves the index of a bucket holding the given
+// partition keys, paged by the limit of the requests, and records the
+// queries it receives.
+func newTestK2VIndexServer(t *testing.T, keys []string, queries *[]string) *httptest.Server {
+	sort.Strings(keys)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/meta" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+		*queries = append(*queries, r.URL.RawQuery)
+
+		query := r.URL.Query()
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		var page client.K2VIndex
+		for _, key := range keys {
+			if !strings.HasPrefix(key, query.Get("prefix")) || key < query.Get("start") {
+				continue
+			}
+			if len(page.PartitionKeys) == limit {
+				page.More = true
+				page.NextStart = &key
+				break
+			}
+			page.PartitionKeys = append(page.PartitionKeys, client.K2VPartitionKey{PK: key, Entries: 1, Values: 1, Bytes: int64(len(key))})
+		}
+
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestK2VIndexDataSource(t *testing.T) {
+	pageSize := k2vIndexPageSize
+	k2vIndexPageSize = 2
+	t.Cleanup(func() { k2vIndexPageSize = pageSize })
+
+	var queries []string
+	server := newTestK2VIndexServer(t, []string{"node/c", "node/a", "node/e", "node/b", "node/d", "zone/a"}, &queries)
+	d := &K2VIndexDataSource{client: client.NewK2VClient(server.URL, "GKtest", "secret")}
+
+	t.Run("all pages", func(t *testing.T) {
+		queries = nil
+		resp := testDataSourceRead(t, d, map[string]tftypes.Value{
+			"bucket": tftypes.NewValue(tftypes.String, "meta"),
+			"prefix": tftypes.NewValue(tftypes.String, "node/"),
+		})
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+		}
+
+		var data K2VIndexDataSourceModel
+		resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+		var keys []struct {
+			PartitionKey string `tfsdk:"partition_key"`
+			Entries      int64  `tfsdk:"entries"`
+			Conflicts    int64  `tfsdk:"conflicts"`
+			Values       int64  `tfsdk:"values"`
+			Bytes        int64  `tfsdk:"bytes"`
+		}
+		resp.Diagnostics.Append(data.PartitionKeys.ElementsAs(context.Background(), &keys, false)...)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+		}
+
+		var names []string
+		for _, key := range keys {
+			names = append(names, key.PartitionKey)
+		}
+		if strings.Join(names, ",") != "node/a,node/b,node/c,node/d,node/e" || data.Truncated.ValueBool() || !data.NextStart.IsNull() {
+			t.Errorf("Expected the five node keys in order, got %v, truncated %s and next start %s", names, data.Truncated, data.NextStart)
+		}
+		if len(queries) != 3 {
+			t.Errorf("Expected 3 pages, got queries %v", queries)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		queries = nil
+		resp := testDataSourceRead(t, d, map[string]tftypes.Value{
+			"bucket": tftypes.NewValue(tftypes.String, "meta"),
+			"start":  tftypes.NewValue(tftypes.String, "node/b"),
+			"limit":  tftypes.NewValue(tftypes.Number, 3),
+		})
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+		}
+
+		var data K2VIndexDataSourceModel
+		resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+		if len(data.PartitionKeys.Elements()) != 3 || !data.Truncated.ValueBool() || data.NextStart.ValueString() != "node/e" {
+			t.Errorf("Expected 3 keys then node/e, got %s, truncated %s and next start %s", data.PartitionKeys, data.Truncated, data.NextStart)
+		}
+		// The last page only asks for the keys still needed
+		expected := []string{"limit=2&start=node%2Fb", "limit=1&start=node%2Fd"}
+		if strings.Join(queries, " ") != strings.Join(expected, " ") {
+			t.Errorf("Expected queries %v, got %v", expected, queries)
+		}
+	})
+}
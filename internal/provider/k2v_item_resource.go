@@ -0,0 +1,307 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &K2VItemResource{}
+var _ resource.ResourceWithImportState = &K2VItemResource{}
+
+func NewK2VItemResource() resource.Resource {
+	return &K2VItemResource{}
+}
+
+// K2VItemResource defines the resource implementation.
+type K2VItemResource struct {
+	client *client.K2VClient
+}
+
+// K2VItemResourceModel describes the resource data model.
+type K2VItemResourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Bucket       types.String `tfsdk:"bucket"`
+	PartitionKey types.String `tfsdk:"partition_key"`
+	SortKey      types.String `tfsdk:"sort_key"`
+	Value        types.String `tfsdk:"value"`
+	ValueBase64  types.String `tfsdk:"value_base64"`
+}
+
+func (r *K2VItemResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_k2v_item"
+}
+
+func (r *K2VItemResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages an item of the K2V API of Garage, a key-value store for small values. Requires `endpoints.k2v` and the S3 credentials of a key with read and write access to the bucket. " +
+			"Writes supersede the values read just before, so they do not add concurrent values to the item.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The identifier of the item, as `bucket/partition_key/sort_key`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"bucket": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The name of the bucket holding the item. Changing this forces a new resource.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"partition_key": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The partition key of the item. Changing this forces a new resource.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"sort_key": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The sort key of the item. Changing this forces a new resource.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"value": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The value of the item, as a string. Exactly one of `value` or `value_base64` must be set.",
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("value_base64")),
+				},
+			},
+			"value_base64": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The value of the item, base64-encoded, for binary values. Exactly one of `value` or `value_base64` must be set.",
+				Validators: []validator.String{
+					validators.Base64(),
+				},
+			},
+		},
+	}
+}
+
+func (r *K2VItemResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	if providerData.K2VClient == nil {
+		resp.Diagnostics.AddError(
+			"Missing K2V Endpoint",
+			"K2V endpoint must be configured in endpoints.k2v for K2V items",
+		)
+		return
+	}
+
+	r.client = providerData.K2VClient
+}
+
+func (r *K2VItemResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data K2VItemResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Creating K2V item", map[string]interface{}{
+		"bucket":        data.Bucket.ValueString(),
+		"partition_key": data.PartitionKey.ValueString(),
+		"sort_key":      data.SortKey.ValueString(),
+	})
+
+	// An existing item is overwritten rather than given a concurrent value
+	if err := r.write(ctx, data); err != nil {
+		resp.Diagnostics.Append(diagFromClientError(fmt.Sprintf("Unable to write K2V item %s", k2vItemID(data)), err))
+		return
+	}
+
+	data.ID = types.StringValue(k2vItemID(data))
+
+	tflog.Trace(ctx, "Created K2V item resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *K2VItemResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data K2VItemResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	item, err := r.client.ReadItem(ctx, data.Bucket.ValueString(), data.PartitionKey.ValueString(), data.SortKey.ValueString())
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError(fmt.Sprintf("Unable to read K2V item %s", k2vItemID(data)), err))
+		return
+	}
+
+	var values [][]byte
+	if item != nil {
+		values = item.LiveValues()
+	}
+
+	switch len(values) {
+	case 0:
+		resp.State.RemoveResource(ctx)
+		return
+	case 1:
+	default:
+		resp.Diagnostics.AddError(
+			"K2V Item Conflict",
+			fmt.Sprintf("K2V item %s has %d concurrent values, written by clients that did not send the causality token of the item. "+
+				"Resolve the conflict by writing the item with its causality token, for example by replacing this resource with "+
+				"terraform apply -refresh=false -replace=<address>, which supersedes all the values with the configured one.",
+				k2vItemID(data), len(values)),
+		)
+		return
+	}
+
+	// Keep the attribute in use, binary values can only be base64-encoded
+	value := values[0]
+	if data.ValueBase64.IsNull() && utf8.Valid(value) {
+		data.Value = types.StringValue(string(value))
+		data.ValueBase64 = types.StringNull()
+	} else {
+		data.Value = types.StringNull()
+		data.ValueBase64 = types.StringValue(base64.StdEncoding.EncodeToString(value))
+	}
+	data.ID = types.StringValue(k2vItemID(data))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *K2VItemResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data K2VItemResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Updating K2V item", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+
+	if err := r.write(ctx, data); err != nil {
+		resp.Diagnostics.Append(diagFromClientError(fmt.Sprintf("Unable to write K2V item %s", k2vItemID(data)), err))
+		return
+	}
+
+	tflog.Trace(ctx, "Updated K2V item resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *K2VItemResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data K2VItemResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Deletion needs the causality token of the values it removes
+	item, err := r.client.ReadItem(ctx, data.Bucket.ValueString(), data.PartitionKey.ValueString(), data.SortKey.ValueString())
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError(fmt.Sprintf("Unable to read K2V item %s", k2vItemID(data)), err))
+		return
+	}
+	if item == nil {
+		return
+	}
+
+	if err := r.client.DeleteItem(ctx, data.Bucket.ValueString(), data.PartitionKey.ValueString(), data.SortKey.ValueString(), item.CausalityToken); err != nil {
+		resp.Diagnostics.Append(diagFromClientError(fmt.Sprintf("Unable to delete K2V item %s", k2vItemID(data)), err))
+		return
+	}
+}
+
+func (r *K2VItemResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import ID format: bucket/partition_key/sort_key, the sort key may
+	// contain slashes
+	parts := strings.SplitN(req.ID, "/", 3)
+	if len(parts) != 3 || parts[0] == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Expected import ID format: bucket/partition_key/sort_key, got: %s", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("partition_key"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("sort_key"), parts[2])...)
+}
+
+// write sets the value of the item to the one of data, superseding all the
+// values it currently has.
+func (r *K2VItemResource) write(ctx context.Context, data K2VItemResourceModel) error {
+	value := []byte(data.Value.ValueString())
+	if !data.ValueBase64.IsNull() {
+		decoded, err := base64.StdEncoding.DecodeString(data.ValueBase64.ValueString())
+		if err != nil {
+			return fmt.Errorf("decoding value_base64: %w", err)
+		}
+		value = decoded
+	}
+
+	// Without the causality token of the current values, the new value
+	// would be stored next to them as a conflict
+	causalityToken := ""
+	item, err := r.client.ReadItem(ctx, data.Bucket.ValueString(), data.PartitionKey.ValueString(), data.SortKey.ValueString())
+	if err != nil {
+		return err
+	}
+	if item != nil {
+		causalityToken = item.CausalityToken
+	}
+
+	return r.client.InsertItem(ctx, data.Bucket.ValueString(), data.PartitionKey.ValueString(), data.SortKey.ValueString(), value, causalityToken)
+}
+
+// k2vItemID returns the identifier of the item of data.
+func k2vItemID(data K2VItemResourceModel) string {
+	return data.Bucket.ValueString() + "/" + data.PartitionKey.ValueString() + "/" + data.SortKey.ValueString()
+}
@@ -0,0 +1,204 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+// testK2VServer is a K2V API holding the values of items by path and sort
+// key. Each write bumps the causality token of the item; writes with the
+// current token supersede its values, others add a concurrent value.
+type testK2VServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	items  map[string][][]byte
+	tokens map[string]int
+}
+
+func newTestK2VServer(t *testing.T) *testK2VServer {
+	server := &testK2VServer{items: map[string][][]byte{}, tokens: map[string]int{}}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+
+		key := r.URL.Path + "?" + r.URL.Query().Get("sort_key")
+		token := r.Header.Get("X-Garage-Causality-Token")
+		current := strconv.Itoa(server.tokens[key])
+
+		switch r.Method {
+		case http.MethodGet:
+			if len(server.items[key]) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("X-Garage-Causality-Token", current)
+			_ = json.NewEncoder(w).Encode(server.items[key])
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			if token != current {
+				server.items[key] = append(server.items[key], body)
+			} else {
+				server.items[key] = [][]byte{body}
+			}
+			server.tokens[key]++
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			if token != current {
+				t.Errorf("Expected causality token %s to delete %s, got %q", current, key, token)
+			}
+			delete(server.items, key)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestAccK2VItemResource(t *testing.T) {
+	server := newTestK2VServer(t)
+	key := "/meta/cluster?name"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccK2VItemResourceConfig(server.URL, `value = "prod"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_k2v_item.test", "id", "meta/cluster/name"),
+					resource.TestCheckResourceAttr("garage_k2v_item.test", "value", "prod"),
+				),
+			},
+			// The update supersedes the value instead of adding a conflict
+			{
+				Config: testAccK2VItemResourceConfig(server.URL, `value_base64 = base64encode("staging")`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_k2v_item.test", "value_base64", "c3RhZ2luZw=="),
+					resource.TestCheckNoResourceAttr("garage_k2v_item.test", "value"),
+					func(*terraform.State) error {
+						server.mu.Lock()
+						defer server.mu.Unlock()
+						if len(server.items[key]) != 1 || string(server.items[key][0]) != "staging" {
+							return fmt.Errorf("expected a single value, got %q", server.items[key])
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "garage_k2v_item.test",
+				ImportState:       true,
+				ImportStateId:     "meta/cluster/name",
+				ImportStateVerify: true,
+				// Imports of UTF-8 values use value
+				ImportStateVerifyIgnore: []string{"value", "value_base64"},
+			},
+			// A value written concurrently by another client is a conflict
+			{
+				PreConfig: func() {
+					server.mu.Lock()
+					defer server.mu.Unlock()
+					server.items[key] = append(server.items[key], []byte("other"))
+				},
+				Config:      testAccK2VItemResourceConfig(server.URL, `value_base64 = base64encode("staging")`),
+				ExpectError: regexp.MustCompile(`has 2 concurrent values`),
+			},
+			{
+				PreConfig: func() {
+					server.mu.Lock()
+					defer server.mu.Unlock()
+					server.items[key] = server.items[key][:1]
+				},
+				Config: testAccK2VItemResourceConfig(server.URL, `value_base64 = base64encode("staging")`),
+			},
+		},
+	})
+}
+
+func TestK2VItemWrite(t *testing.T) {
+	server := newTestK2VServer(t)
+	key := "/meta/cluster?name"
+	r := &K2VItemResource{client: client.NewK2VClient(server.URL, "GKtest", "secret")}
+	data := K2VItemResourceModel{
+		Bucket:       types.StringValue("meta"),
+		PartitionKey: types.StringValue("cluster"),
+		SortKey:      types.StringValue("name"),
+		Value:        types.StringValue("prod"),
+		ValueBase64:  types.StringNull(),
+	}
+
+	if err := r.write(context.Background(), data); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Values written concurrently by other clients are superseded too
+	server.items[key] = append(server.items[key], []byte("other"))
+	data.Value = types.StringNull()
+	data.ValueBase64 = types.StringValue("AAE=")
+	if err := r.write(context.Background(), data); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(server.items[key]) != 1 || string(server.items[key][0]) != "\x00\x01" {
+		t.Errorf("Expected a single binary value, got %q", server.items[key])
+	}
+}
+
+func TestAccK2VItemResource_missingEndpoint(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMockProviderConfig("http://localhost:3903") + `
+resource "garage_k2v_item" "test" {
+  bucket        = "meta"
+  partition_key = "cluster"
+  sort_key      = "name"
+  value         = "prod"
+}
+`,
+				ExpectError: regexp.MustCompile(`Missing K2V Endpoint`),
+			},
+		},
+	})
+}
+
+func testAccK2VItemResourceConfig(k2vEndpoint, value string) string {
+	return fmt.Sprintf(`
+provider "garage" {
+  endpoints = {
+    admin = "http://localhost:3903"
+    k2v   = %q
+  }
+  access_key = "GKtest"
+  secret_key = "secret"
+}
+
+resource "garage_k2v_item" "test" {
+  bucket        = "meta"
+  partition_key = "cluster"
+  sort_key      = "name"
+  %s
+}
+`, k2vEndpoint, value)
+}
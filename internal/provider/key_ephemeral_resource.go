@@ -0,0 +1,346 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/boolvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ ephemeral.EphemeralResourceWithConfigure = &KeyEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithRenew = &KeyEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithClose = &KeyEphemeralResource{}
+
+const (
+	// defaultEphemeralKeyName is the name of the keys created without one.
+	defaultEphemeralKeyName = "terraform-ephemeral"
+	// ephemeralKeyPrivateKey is the private data key holding the access key
+	// ID between Open and Close.
+	ephemeralKeyPrivateKey = "access_key_id"
+	// ephemeralKeyTTLPrivateKey is the private data key holding the ttl the
+	// key is extended by on renewal, absent for keys that never expire.
+	ephemeralKeyTTLPrivateKey = "ttl"
+	// maxEphemeralKeyRenewMargin is how long before its expiration a key is
+	// extended at most, to leave time for the UpdateKey request.
+	maxEphemeralKeyRenewMargin = 5 * time.Minute
+)
+
+func NewKeyEphemeralResource() ephemeral.EphemeralResource {
+	return &KeyEphemeralResource{}
+}
+
+// KeyEphemeralResource creates an access key when opened, optionally allowed
+// on a single bucket, extends its expiration when renewed and deletes it when
+// closed. Neither the key nor its secret are stored in the plan or the state.
+type KeyEphemeralResource struct {
+	client *client.Client
+
+	// now returns the current time, time.Now unless replaced in tests.
+	now func() time.Time
+}
+
+// KeyEphemeralResourceModel describes the ephemeral resource data model.
+type KeyEphemeralResourceModel struct {
+	Name            types.String `tfsdk:"name"`
+	TTL             types.String `tfsdk:"ttl"`
+	BucketID        types.String `tfsdk:"bucket_id"`
+	Read            types.Bool   `tfsdk:"read"`
+	Write           types.Bool   `tfsdk:"write"`
+	Owner           types.Bool   `tfsdk:"owner"`
+	AccessKeyID     types.String `tfsdk:"access_key_id"`
+	SecretAccessKey types.String `tfsdk:"secret_access_key"`
+	Expiration      types.String `tfsdk:"expiration"`
+}
+
+func (e *KeyEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_key"
+}
+
+func (e *KeyEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	bucketPermission := []validator.Bool{
+		boolvalidator.AlsoRequires(path.MatchRoot("bucket_id")),
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Creates a Garage access key for the duration of a Terraform run and deletes it at the end of the run, optionally allowed on a single bucket. " +
+			"The key and its secret are never stored in the plan nor the state. " +
+			"With a `ttl`, the key expires after `ttl` in case it cannot be deleted, and is extended by `ttl` shortly before it expires while the run lasts.",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The name of the key. Defaults to `" + defaultEphemeralKeyName + "`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"ttl": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "How long the key stays valid if it is not deleted at the end of the run, as a Go duration (e.g. `30m`). Runs that last longer extend it by `ttl` again before it expires. Without it, the key never expires.",
+				Validators: []validator.String{
+					validators.DurationBetween(time.Minute, 7*24*time.Hour),
+				},
+			},
+			"bucket_id": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The ID of the bucket the key is allowed on with `read`, `write` and `owner`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"read": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Allow the key to read the objects of `bucket_id`.",
+				Validators:          bucketPermission,
+			},
+			"write": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Allow the key to write the objects of `bucket_id`.",
+				Validators:          bucketPermission,
+			},
+			"owner": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Allow the key to manage `bucket_id`.",
+				Validators:          bucketPermission,
+			},
+			"access_key_id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The access key ID.",
+			},
+			"secret_access_key": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The secret access key.",
+			},
+			"expiration": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The expiration date of the key when it was created, in RFC 3339 format. Renewals extend it without updating this attribute. Null without `ttl`.",
+			},
+		},
+	}
+}
+
+func (e *KeyEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	var diags diag.Diagnostics
+	e.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
+}
+
+func (e *KeyEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data KeyEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Name.IsNull() {
+		data.Name = types.StringValue(defaultEphemeralKeyName)
+	}
+	name := data.Name.ValueString()
+	createReq := client.CreateKeyRequest{Name: &name}
+
+	openedAt := e.clock()
+	var ttl time.Duration
+	if !data.TTL.IsNull() {
+		var err error
+		ttl, err = time.ParseDuration(data.TTL.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("ttl"), "Invalid Duration", fmt.Sprintf("Unable to parse ttl %q: %s", data.TTL.ValueString(), err))
+			return
+		}
+		expiration := openedAt.Add(ttl).UTC().Format(time.RFC3339)
+		createReq.Expiration = &expiration
+	}
+
+	tflog.Debug(ctx, "Creating ephemeral access key", map[string]interface{}{
+		"name": name,
+		"ttl":  data.TTL.ValueString(),
+	})
+
+	key, err := e.client.CreateKey(ctx, createReq)
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError("Unable to create access key", err))
+		return
+	}
+
+	if !data.BucketID.IsNull() {
+		_, err := e.client.AllowBucketKey(ctx, client.BucketKeyPermRequest{
+			BucketID:    data.BucketID.ValueString(),
+			AccessKeyID: key.AccessKeyID,
+			Permissions: client.Permissions{
+				Read:  data.Read.ValueBool(),
+				Write: data.Write.ValueBool(),
+				Owner: data.Owner.ValueBool(),
+			},
+		})
+		if err != nil {
+			resp.Diagnostics.Append(diagFromClientError(fmt.Sprintf("Unable to allow access key on bucket %s", data.BucketID.ValueString()), err))
+			// Open failed, so Close is not called for this key
+			if err := e.client.DeleteKey(ctx, client.DeleteKeyRequest{ID: key.AccessKeyID}); err != nil {
+				resp.Diagnostics.Append(diagFromClientError(fmt.Sprintf("Unable to delete access key %s", key.AccessKeyID), err))
+			}
+			return
+		}
+	}
+
+	// Renew and Close only receive the private data, which is never
+	// persisted either
+	resp.Diagnostics.Append(setEphemeralKeyPrivateString(ctx, resp.Private, ephemeralKeyPrivateKey, key.AccessKeyID)...)
+	if ttl > 0 {
+		resp.Diagnostics.Append(setEphemeralKeyPrivateString(ctx, resp.Private, ephemeralKeyTTLPrivateKey, ttl.String())...)
+		resp.RenewAt = ephemeralKeyRenewAt(openedAt, ttl)
+	}
+
+	data.AccessKeyID = types.StringValue(key.AccessKeyID)
+	data.SecretAccessKey = types.StringPointerValue(key.SecretAccessKey)
+	data.Expiration = types.StringPointerValue(key.Expiration)
+
+	tflog.Trace(ctx, "Opened access key ephemeral resource")
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+func (e *KeyEphemeralResource) Renew(ctx context.Context, req ephemeral.RenewRequest, resp *ephemeral.RenewResponse) {
+	id, diags := ephemeralKeyPrivateString(ctx, req.Private, ephemeralKeyPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	ttlData, diags := ephemeralKeyPrivateString(ctx, req.Private, ephemeralKeyTTLPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	// Keys without an expiration are never renewed
+	if resp.Diagnostics.HasError() || id == "" || ttlData == "" {
+		return
+	}
+
+	ttl, err := time.ParseDuration(ttlData)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to decode the access key ttl: %s", err))
+		return
+	}
+
+	renewedAt := e.clock()
+	expiration := renewedAt.Add(ttl).UTC().Format(time.RFC3339)
+
+	tflog.Debug(ctx, "Extending ephemeral access key", map[string]interface{}{
+		"id":         id,
+		"expiration": expiration,
+	})
+
+	if _, err := e.client.UpdateKey(ctx, id, client.UpdateKeyRequest{Expiration: &expiration}); err != nil {
+		resp.Diagnostics.Append(diagFromClientError(fmt.Sprintf("Unable to extend access key %s, it expires at its previous expiration", id), err))
+		return
+	}
+	resp.RenewAt = ephemeralKeyRenewAt(renewedAt, ttl)
+
+	tflog.Trace(ctx, "Renewed access key ephemeral resource")
+}
+
+func (e *KeyEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	id, diags := ephemeralKeyPrivateString(ctx, req.Private, ephemeralKeyPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || id == "" {
+		return
+	}
+
+	tflog.Debug(ctx, "Deleting ephemeral access key", map[string]interface{}{
+		"id": id,
+	})
+
+	if err := e.client.DeleteKey(ctx, client.DeleteKeyRequest{ID: id}); err != nil {
+		resp.Diagnostics.Append(diagFromClientError(fmt.Sprintf("Unable to delete access key %s, it stays valid until its expiration", id), err))
+		return
+	}
+
+	tflog.Trace(ctx, "Closed access key ephemeral resource")
+}
+
+// clock returns the current time.
+func (e *KeyEphemeralResource) clock() time.Time {
+	if e.now != nil {
+		return e.now()
+	}
+	return time.Now()
+}
+
+// ephemeralKeyRenewAt returns when to extend a key valid for ttl from from:
+// a fifth of ttl before it expires, maxEphemeralKeyRenewMargin at most.
+func ephemeralKeyRenewAt(from time.Time, ttl time.Duration) time.Time {
+	margin := ttl / 5
+	if margin > maxEphemeralKeyRenewMargin {
+		margin = maxEphemeralKeyRenewMargin
+	}
+	return from.Add(ttl - margin)
+}
+
+// privateData is the private data of an ephemeral resource, whose types are
+// internal to the framework.
+type privateData interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+// privateDataSetter is the private data of an opened ephemeral resource.
+type privateDataSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// setEphemeralKeyPrivateString stores value as JSON under key in the private
+// data of the ephemeral resource.
+func setEphemeralKeyPrivateString(ctx context.Context, private privateDataSetter, key, value string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to encode %s into the private data of the access key: %s", key, err))
+		return diags
+	}
+	return private.SetKey(ctx, key, data)
+}
+
+// ephemeralKeyPrivateString returns the JSON string stored under key in the
+// private data of the ephemeral resource, "" when there is none.
+func ephemeralKeyPrivateString(ctx context.Context, private privateData, key string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	data, getDiags := private.GetKey(ctx, key)
+	diags.Append(getDiags...)
+	if diags.HasError() || data == nil {
+		return "", diags
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to decode %s from the private data of the access key: %s", key, err))
+	}
+	return value, diags
+}
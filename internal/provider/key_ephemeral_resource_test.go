@@ -0,0 +1,261 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+func TestAccKeyEphemeralResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesWithEcho,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig() + `
+resource "garage_bucket" "test" {
+  global_alias = "acc-ephemeral-key"
+}
+
+ephemeral "garage_key" "test" {
+  name      = "acc-ephemeral"
+  ttl       = "5m"
+  bucket_id = garage_bucket.test.id
+  read      = true
+}
+
+provider "echo" {
+  data = {
+    name = ephemeral.garage_key.test.name
+  }
+}
+
+resource "echo" "test" {}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("name"), knownvalue.StringExact("acc-ephemeral")),
+				},
+			},
+		},
+	})
+}
+
+// testKeyEphemeralServer serves the key endpoints of the admin API, recording
+// the requests in the returned key server.
+func testKeyEphemeralServer(t *testing.T) (*httptest.Server, *testKeyServer) {
+	t.Helper()
+
+	keys := &testKeyServer{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v2/CreateKey":
+			if err := json.NewDecoder(r.Body).Decode(&keys.created); err != nil {
+				t.Errorf("Unexpected request body: %s", err)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"accessKeyId": "GK1", "name": keys.created.Name, "expiration": keys.created.Expiration,
+				"secretAccessKey": "secret", "permissions": map[string]bool{}, "buckets": []interface{}{},
+			})
+		case "/v2/AllowBucketKey":
+			var allowed client.BucketKeyPermRequest
+			if err := json.NewDecoder(r.Body).Decode(&allowed); err != nil {
+				t.Errorf("Unexpected request body: %s", err)
+			}
+			keys.allowed = append(keys.allowed, allowed)
+			if keys.allowStatus != 0 {
+				w.WriteHeader(keys.allowStatus)
+				_, _ = w.Write([]byte(`{"code": "NoSuchBucket", "message": "Bucket not found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id": "` + allowed.BucketID + `"}`))
+		case "/v2/UpdateKey":
+			var update client.UpdateKeyRequest
+			if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+				t.Errorf("Unexpected request body: %s", err)
+			}
+			if r.URL.Query().Get("id") != "GK1" || update.Expiration == nil || update.Name != nil || update.NeverExpires {
+				t.Errorf("Expected only the expiration of GK1 to be updated, got %s %+v", r.URL.RawQuery, update)
+				return
+			}
+			keys.updates = append(keys.updates, *update.Expiration)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"accessKeyId": "GK1", "expiration": update.Expiration})
+		case "/v2/DeleteKey":
+			keys.deleted = append(keys.deleted, r.URL.Query().Get("id"))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+
+	return server, keys
+}
+
+// testKeyServer records the key requests of testKeyEphemeralServer.
+type testKeyServer struct {
+	created     client.CreateKeyRequest
+	allowed     []client.BucketKeyPermRequest
+	updates     []string
+	deleted     []string
+	allowStatus int
+}
+
+func TestKeyEphemeralResource(t *testing.T) {
+	server, keys := testKeyEphemeralServer(t)
+	defer server.Close()
+
+	e := &KeyEphemeralResource{client: client.NewClient(server.URL, "test-token")}
+	resp := testEphemeralResourceOpen(t, e, map[string]tftypes.Value{
+		"bucket_id": tftypes.NewValue(tftypes.String, "b1"),
+		"read":      tftypes.NewValue(tftypes.Bool, true),
+		"write":     tftypes.NewValue(tftypes.Bool, true),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var result KeyEphemeralResourceModel
+	resp.Diagnostics.Append(resp.Result.Get(context.Background(), &result)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if result.AccessKeyID.ValueString() != "GK1" || result.SecretAccessKey.ValueString() != "secret" || result.Name.ValueString() != defaultEphemeralKeyName {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	// Without a ttl the key never expires, so it is never renewed
+	if keys.created.Expiration != nil || !result.Expiration.IsNull() || !resp.RenewAt.IsZero() {
+		t.Errorf("Expected a key without expiration nor renewal, got %+v and renewal at %s", keys.created, resp.RenewAt)
+	}
+	expected := client.BucketKeyPermRequest{BucketID: "b1", AccessKeyID: "GK1", Permissions: client.Permissions{Read: true, Write: true}}
+	if len(keys.allowed) != 1 || keys.allowed[0] != expected {
+		t.Errorf("Expected %+v to be allowed, got %+v", expected, keys.allowed)
+	}
+
+	renewResp := &ephemeral.RenewResponse{Private: resp.Private}
+	e.Renew(context.Background(), ephemeral.RenewRequest{Private: resp.Private}, renewResp)
+	if renewResp.Diagnostics.HasError() || len(keys.updates) != 0 {
+		t.Errorf("Expected a key without expiration not to be extended, got %v and %v", renewResp.Diagnostics, keys.updates)
+	}
+
+	closeResp := &ephemeral.CloseResponse{}
+	e.Close(context.Background(), ephemeral.CloseRequest{Private: resp.Private}, closeResp)
+	if closeResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", closeResp.Diagnostics)
+	}
+	if len(keys.deleted) != 1 || keys.deleted[0] != "GK1" {
+		t.Errorf("Expected key GK1 to be deleted, got %v", keys.deleted)
+	}
+}
+
+func TestKeyEphemeralResource_allowError(t *testing.T) {
+	server, keys := testKeyEphemeralServer(t)
+	defer server.Close()
+	keys.allowStatus = http.StatusNotFound
+
+	e := &KeyEphemeralResource{client: client.NewClient(server.URL, "test-token")}
+	resp := testEphemeralResourceOpen(t, e, map[string]tftypes.Value{
+		"bucket_id": tftypes.NewValue(tftypes.String, "missing"),
+		"read":      tftypes.NewValue(tftypes.Bool, true),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("Expected an error for a missing bucket")
+	}
+
+	// Close is not called after a failed Open, so the key is deleted there
+	if len(keys.deleted) != 1 || keys.deleted[0] != "GK1" {
+		t.Errorf("Expected key GK1 to be deleted, got %v", keys.deleted)
+	}
+}
+
+func TestEphemeralKeyRenewAt(t *testing.T) {
+	from := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		ttl      time.Duration
+		expected time.Time
+	}{
+		// Long-lived keys are extended 5 minutes before they expire
+		{ttl: time.Hour, expected: from.Add(55 * time.Minute)},
+		{ttl: 7 * 24 * time.Hour, expected: from.Add(7*24*time.Hour - 5*time.Minute)},
+		// Short-lived ones a fifth of their ttl before
+		{ttl: 10 * time.Minute, expected: from.Add(8 * time.Minute)},
+		{ttl: time.Minute, expected: from.Add(48 * time.Second)},
+	}
+
+	for _, tt := range tests {
+		if got := ephemeralKeyRenewAt(from, tt.ttl); !got.Equal(tt.expected) {
+			t.Errorf("ephemeralKeyRenewAt(%s): expected %s, got %s", tt.ttl, tt.expected, got)
+		}
+	}
+}
+
+func TestKeyEphemeralResourceRenew(t *testing.T) {
+	server, keys := testKeyEphemeralServer(t)
+	defer server.Close()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	e := &KeyEphemeralResource{
+		client: client.NewClient(server.URL, "test-token"),
+		now:    func() time.Time { return now },
+	}
+	openResp := testEphemeralResourceOpen(t, e, map[string]tftypes.Value{
+		"ttl": tftypes.NewValue(tftypes.String, "30m"),
+	})
+	if openResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", openResp.Diagnostics)
+	}
+	if expected := now.Add(30 * time.Minute).Format(time.RFC3339); keys.created.Expiration == nil || *keys.created.Expiration != expected {
+		t.Errorf("Expected the key to expire at %s, got %+v", expected, keys.created)
+	}
+	if expected := now.Add(25 * time.Minute); !openResp.RenewAt.Equal(expected) {
+		t.Errorf("Expected Open to renew at %s, got %s", expected, openResp.RenewAt)
+	}
+
+	// Terraform renews on or after RenewAt, possibly late
+	private := openResp.Private
+	for i, renewedAt := range []time.Time{now.Add(25 * time.Minute), now.Add(52 * time.Minute)} {
+		now = renewedAt
+		renewResp := &ephemeral.RenewResponse{Private: private}
+		e.Renew(context.Background(), ephemeral.RenewRequest{Private: private}, renewResp)
+		if renewResp.Diagnostics.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", renewResp.Diagnostics)
+		}
+
+		if expected := renewedAt.Add(30 * time.Minute).Format(time.RFC3339); len(keys.updates) != i+1 || keys.updates[i] != expected {
+			t.Errorf("Expected the key to be extended to %s, got %v", expected, keys.updates)
+		}
+		if expected := renewedAt.Add(25 * time.Minute); !renewResp.RenewAt.Equal(expected) {
+			t.Errorf("Expected to renew again at %s, got %s", expected, renewResp.RenewAt)
+		}
+		private = renewResp.Private
+	}
+
+	closeResp := &ephemeral.CloseResponse{}
+	e.Close(context.Background(), ephemeral.CloseRequest{Private: private}, closeResp)
+	if closeResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", closeResp.Diagnostics)
+	}
+	if len(keys.deleted) != 1 || keys.deleted[0] != "GK1" {
+		t.Errorf("Expected key GK1 to be deleted after renewals, got %v", keys.deleted)
+	}
+}
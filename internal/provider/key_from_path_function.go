@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &KeyFromPathFunction{}
+
+func NewKeyFromPathFunction() function.Function {
+	return &KeyFromPathFunction{}
+}
+
+// KeyFromPathFunction defines the function implementation.
+type KeyFromPathFunction struct{}
+
+func (f *KeyFromPathFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "key_from_path"
+}
+
+func (f *KeyFromPathFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds an object key from the path of a local file",
+		MarkdownDescription: "Builds the key of an object from the path of a local file, e.g. from `fileset()`: " +
+			"backslashes are converted to slashes, duplicate slashes and `.` segments are removed, `strip_prefix` is stripped " +
+			"and `key_prefix` is prepended, as `garage_object_directory` does. The key always uses forward slashes, " +
+			"whatever the OS Terraform runs on. Paths with `..` segments after the prefix are errors, so keys cannot escape the directory.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "path",
+				MarkdownDescription: "The path of the file, e.g. `\"${path.module}/site/css/style.css\"`.",
+			},
+			function.StringParameter{
+				Name:                "strip_prefix",
+				MarkdownDescription: "The directory to strip from the path, e.g. `\"${path.module}/site\"`. It must match whole segments of the path. Use `\"\"` to keep the whole path.",
+			},
+		},
+		VariadicParameter: function.StringParameter{
+			Name:                "key_prefix",
+			MarkdownDescription: "The prefix prepended as is to the key, e.g. `site/`. Default: none. At most one key prefix can be given.",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *KeyFromPathFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var path, stripPrefix string
+	var keyPrefixes []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &path, &stripPrefix, &keyPrefixes))
+	if resp.Error != nil {
+		return
+	}
+
+	var keyPrefix string
+	switch {
+	case len(keyPrefixes) > 1:
+		resp.Error = function.NewArgumentFuncError(2, fmt.Sprintf("invalid key prefix: expected at most one key prefix, got %d", len(keyPrefixes)))
+		return
+	case len(keyPrefixes) == 1:
+		keyPrefix = keyPrefixes[0]
+	}
+
+	key, err := keyFromPath(path, stripPrefix, keyPrefix)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid path %q: %s", path, err))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, key))
+}
+
+// keyFromPath returns keyPrefix followed by the slash-separated segments of
+// path after those of stripPrefix. Both paths may use backslashes, and their
+// empty and "." segments are ignored.
+func keyFromPath(path, stripPrefix, keyPrefix string) (string, error) {
+	segments := pathSegments(path)
+	prefix := pathSegments(stripPrefix)
+
+	if len(segments) < len(prefix) || !slices.Equal(segments[:len(prefix)], prefix) {
+		return "", fmt.Errorf("path is not under %q", stripPrefix)
+	}
+	segments = segments[len(prefix):]
+
+	if slices.Contains(segments, "..") {
+		return "", errors.New("path contains a .. segment")
+	}
+	if len(segments) == 0 {
+		return "", fmt.Errorf("path is %q itself", stripPrefix)
+	}
+
+	key := keyPrefix + strings.Join(segments, "/")
+	if err := validators.ValidateObjectKey(key); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+// pathSegments returns the segments of path separated by slashes or
+// backslashes, without the empty and "." ones.
+func pathSegments(path string) []string {
+	segments := strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '\\'
+	})
+	return slices.DeleteFunc(segments, func(segment string) bool {
+		return segment == "."
+	})
+}
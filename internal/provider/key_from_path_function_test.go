@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccKeyFromPathFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "key" {
+  value = provider::garage::key_from_path("./site//css/style.css", "site")
+}
+
+output "prefixed" {
+  value = provider::garage::key_from_path("C:\\build\\site\\index.html", "C:\\build", "v2/")
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("key", knownvalue.StringExact("css/style.css")),
+					statecheck.ExpectKnownOutputValue("prefixed", knownvalue.StringExact("v2/site/index.html")),
+				},
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::garage::key_from_path("site/../secrets.txt", "site")
+}
+`,
+				ExpectError: regexp.MustCompile(`contains a \.\. segment`),
+			},
+		},
+	})
+}
+
+func TestKeyFromPath(t *testing.T) {
+	tests := []struct {
+		path, stripPrefix, keyPrefix string
+		expected                     string
+	}{
+		{"site/index.html", "site", "", "index.html"},
+		{"site/css/style.css", "site/", "", "css/style.css"},
+		{"./site/css/style.css", "site", "", "css/style.css"},
+		{"site//css///style.css", "site", "", "css/style.css"},
+		{"site/./css/./style.css", "./site", "", "css/style.css"},
+		{"/home/ci/site/index.html", "/home/ci/site", "", "index.html"},
+		{"css/style.css", "", "", "css/style.css"},
+		{"/abs/style.css", "", "", "abs/style.css"},
+		{"site/index.html", "site", "www/", "www/index.html"},
+		{"site/index.html", "site", "www-", "www-index.html"},
+		{"site/dir/", "site", "", "dir"},
+		// The prefix may climb out of the module, as in fileset("${path.module}/../shared", "**")
+		{"./../shared/logo.png", "../shared", "", "logo.png"},
+		// Windows-style paths
+		{`site\css\style.css`, "site", "", "css/style.css"},
+		{`C:\Users\ci\site\css\style.css`, `C:\Users\ci\site`, "", "css/style.css"},
+		{`C:\Users\ci\site\css\style.css`, `C:\Users\ci\site\`, "assets/", "assets/css/style.css"},
+		{`C:/Users/ci/site\css/style.css`, `C:\Users\ci\site`, "", "css/style.css"},
+		{`.\site\\css\.\style.css`, `site`, "", "css/style.css"},
+		{`\\server\share\site\index.html`, `\\server\share`, "", "site/index.html"},
+		// Unicode and spaces are kept as is
+		{"site/docs/getting started/été.md", "site", "", "docs/getting started/été.md"},
+	}
+
+	for _, test := range tests {
+		got, err := keyFromPath(test.path, test.stripPrefix, test.keyPrefix)
+		if err != nil || got != test.expected {
+			t.Errorf("keyFromPath(%q, %q, %q): expected %q, got %q and %v", test.path, test.stripPrefix, test.keyPrefix, test.expected, got, err)
+		}
+	}
+}
+
+func TestKeyFromPath_invalid(t *testing.T) {
+	tests := []struct {
+		path, stripPrefix, keyPrefix string
+		error                        string
+	}{
+		{"site/../secrets.txt", "site", "", "contains a .. segment"},
+		{"site/css/../../secrets.txt", "site", "", "contains a .. segment"},
+		{`site\..\secrets.txt`, "site", "", "contains a .. segment"},
+		{`C:\site\..\..\Windows\win.ini`, `C:\site`, "", "contains a .. segment"},
+		{"../secrets.txt", "", "", "contains a .. segment"},
+		{"other/index.html", "site", "", `path is not under "site"`},
+		{"site-old/index.html", "site", "", `path is not under "site"`},
+		{"site", "site/index.html", "", `path is not under "site/index.html"`},
+		{`C:\site\index.html`, `D:\site`, "", `path is not under "D:\\site"`},
+		{"site/", "site", "", `path is "site" itself`},
+		{"", "", "", `path is "" itself`},
+		{"site/index.html", "site", "/www/", "starts with /"},
+	}
+
+	for _, test := range tests {
+		_, err := keyFromPath(test.path, test.stripPrefix, test.keyPrefix)
+		if err == nil || !strings.Contains(err.Error(), test.error) {
+			t.Errorf("keyFromPath(%q, %q, %q): expected an error containing %q, got %v", test.path, test.stripPrefix, test.keyPrefix, test.error, err)
+		}
+	}
+}
+
+func TestKeyFromPathFunction_run(t *testing.T) {
+	f := NewKeyFromPathFunction()
+
+	run := func(path, stripPrefix string, keyPrefixes ...string) (string, *int64, string) {
+		keyPrefixTypes := make([]attr.Type, 0, len(keyPrefixes))
+		keyPrefixValues := make([]attr.Value, 0, len(keyPrefixes))
+		for _, keyPrefix := range keyPrefixes {
+			keyPrefixTypes = append(keyPrefixTypes, types.StringType)
+			keyPrefixValues = append(keyPrefixValues, types.StringValue(keyPrefix))
+		}
+		resp := testFunctionRun(t, f, types.StringValue(path), types.StringValue(stripPrefix), types.TupleValueMust(keyPrefixTypes, keyPrefixValues))
+		if resp.Error != nil {
+			return "", resp.Error.FunctionArgument, resp.Error.Text
+		}
+		return resp.Result.Value().(types.String).ValueString(), nil, ""
+	}
+
+	if key, _, text := run(`build\site\index.html`, "build"); key != "site/index.html" {
+		t.Errorf("Expected site/index.html, got %q and %q", key, text)
+	}
+	if key, _, text := run("build/site/index.html", "build/site", "www/"); key != "www/index.html" {
+		t.Errorf("Expected www/index.html, got %q and %q", key, text)
+	}
+	if _, argument, text := run("build/../secrets.txt", "build"); argument == nil || *argument != 0 || !strings.Contains(text, `"build/../secrets.txt"`) {
+		t.Errorf("Expected an error on the path naming it, got %v and %q", argument, text)
+	}
+	if _, argument, text := run("build/index.html", "build", "a/", "b/"); argument == nil || *argument != 2 {
+		t.Errorf("Expected an error on the key prefix, got %v and %q", argument, text)
+	}
+}
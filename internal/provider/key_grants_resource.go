@@ -0,0 +1,448 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &KeyGrantsResource{}
+var _ resource.ResourceWithImportState = &KeyGrantsResource{}
+
+func NewKeyGrantsResource() resource.Resource {
+	return &KeyGrantsResource{}
+}
+
+// KeyGrantsResource defines the resource implementation.
+type KeyGrantsResource struct {
+	client *client.Client
+}
+
+// KeyGrantsResourceModel describes the resource data model.
+type KeyGrantsResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	AccessKeyID types.String `tfsdk:"access_key_id"`
+	Grants      types.Set    `tfsdk:"grant"`
+}
+
+// KeyGrantModel describes a single bucket grant of a key.
+type KeyGrantModel struct {
+	BucketID    types.String `tfsdk:"bucket_id"`
+	BucketAlias types.String `tfsdk:"bucket_alias"`
+	Read        types.Bool   `tfsdk:"read"`
+	Write       types.Bool   `tfsdk:"write"`
+	Owner       types.Bool   `tfsdk:"owner"`
+}
+
+// keyGrantAttrTypes are the attribute types of a grant object.
+var keyGrantAttrTypes = map[string]attr.Type{
+	"bucket_id":    types.StringType,
+	"bucket_alias": types.StringType,
+	"read":         types.BoolType,
+	"write":        types.BoolType,
+	"owner":        types.BoolType,
+}
+
+func (r *KeyGrantsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_key_grants"
+}
+
+func (r *KeyGrantsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Authoritatively manages every bucket grant of a single Garage access key. " +
+			"Buckets the key has access to that are not listed in `grant` are revoked. " +
+			"Destroying the resource revokes all grants of the key but keeps the key itself. " +
+			"Do not combine this resource with `garage_bucket_permission` for the same key, as both would fight over the same grants.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The identifier of the resource, equal to `access_key_id`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"access_key_id": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The ID of the access key whose grants are managed.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					validators.AccessKeyID(),
+				},
+			},
+			"grant": schema.SetNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "The complete set of buckets the key has access to. Each grant must set at least one of `read`, `write` or `owner`. An empty or unset value revokes every grant of the key.",
+				NestedObject: schema.NestedAttributeObject{
+					Validators: []validator.Object{
+						validators.AtLeastOneTrueAttribute("read", "write", "owner"),
+					},
+					Attributes: map[string]schema.Attribute{
+						"bucket_id": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "The ID of the bucket. Exactly one of `bucket_id` or `bucket_alias` must be set.",
+							Validators: []validator.String{
+								stringvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("bucket_alias")),
+							},
+						},
+						"bucket_alias": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "The global alias of the bucket. Exactly one of `bucket_id` or `bucket_alias` must be set.",
+						},
+						"read": schema.BoolAttribute{
+							Optional:            true,
+							MarkdownDescription: "Grant read permission on the bucket. Defaults to `false`.",
+						},
+						"write": schema.BoolAttribute{
+							Optional:            true,
+							MarkdownDescription: "Grant write permission on the bucket. Defaults to `false`.",
+						},
+						"owner": schema.BoolAttribute{
+							Optional:            true,
+							MarkdownDescription: "Grant owner permission on the bucket. Defaults to `false`.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *KeyGrantsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	var diags diag.Diagnostics
+	r.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *KeyGrantsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data KeyGrantsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Creating key grants", map[string]interface{}{
+		"access_key_id": data.AccessKeyID.ValueString(),
+	})
+
+	resp.Diagnostics.Append(r.reconcile(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = data.AccessKeyID
+
+	tflog.Trace(ctx, "Created key grants resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *KeyGrantsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data KeyGrantsResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	key, err := r.client.GetKeyInfo(ctx, client.GetKeyInfoRequest{
+		ID: data.AccessKeyID.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError("Unable to read access key", err))
+		return
+	}
+
+	if key == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	var prior []KeyGrantModel
+	if !data.Grants.IsNull() && !data.Grants.IsUnknown() {
+		resp.Diagnostics.Append(data.Grants.ElementsAs(ctx, &prior, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	grants := keyGrantsFromKey(key, prior)
+	if len(grants) == 0 && data.Grants.IsNull() {
+		// Keep an unset attribute unset when the key has no grants
+		data.Grants = types.SetNull(types.ObjectType{AttrTypes: keyGrantAttrTypes})
+	} else {
+		set, diags := types.SetValueFrom(ctx, types.ObjectType{AttrTypes: keyGrantAttrTypes}, grants)
+		resp.Diagnostics.Append(diags...)
+		data.Grants = set
+	}
+
+	data.ID = types.StringValue(key.AccessKeyID)
+	data.AccessKeyID = types.StringValue(key.AccessKeyID)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *KeyGrantsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data KeyGrantsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Updating key grants", map[string]interface{}{
+		"access_key_id": data.AccessKeyID.ValueString(),
+	})
+
+	resp.Diagnostics.Append(r.reconcile(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = data.AccessKeyID
+
+	tflog.Trace(ctx, "Updated key grants resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *KeyGrantsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data KeyGrantsResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Deleting key grants", map[string]interface{}{
+		"access_key_id": data.AccessKeyID.ValueString(),
+	})
+
+	// Revoke every grant, leaving the key itself in place
+	data.Grants = types.SetNull(types.ObjectType{AttrTypes: keyGrantAttrTypes})
+	resp.Diagnostics.Append(r.reconcile(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "Deleted key grants resource")
+}
+
+func (r *KeyGrantsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("access_key_id"), req.ID)...)
+}
+
+// reconcile makes the grants of the key match the desired grants in data,
+// granting before revoking on each bucket so access that is still desired is
+// never interrupted.
+func (r *KeyGrantsResource) reconcile(ctx context.Context, data *KeyGrantsResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	accessKeyID := data.AccessKeyID.ValueString()
+
+	var grants []KeyGrantModel
+	if !data.Grants.IsNull() && !data.Grants.IsUnknown() {
+		diags.Append(data.Grants.ElementsAs(ctx, &grants, false)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	// Resolve the desired permissions per bucket ID
+	desired := make(map[string]client.Permissions, len(grants))
+	for _, g := range grants {
+		bucketID := g.BucketID.ValueString()
+		if g.BucketID.IsNull() {
+			alias := g.BucketAlias.ValueString()
+			bucket, err := r.client.GetBucketInfo(ctx, client.GetBucketInfoRequest{GlobalAlias: &alias})
+			if err != nil {
+				diags.Append(diagFromClientError(fmt.Sprintf("Unable to read bucket %q", alias), err))
+				return diags
+			}
+			if bucket == nil {
+				diags.AddError("Bucket Not Found", fmt.Sprintf("No bucket with global alias %q exists on this cluster.", alias))
+				return diags
+			}
+			bucketID = bucket.ID
+		}
+
+		p := desired[bucketID]
+		p.Read = p.Read || g.Read.ValueBool()
+		p.Write = p.Write || g.Write.ValueBool()
+		p.Owner = p.Owner || g.Owner.ValueBool()
+		desired[bucketID] = p
+	}
+
+	key, err := r.client.GetKeyInfo(ctx, client.GetKeyInfoRequest{ID: accessKeyID})
+	if err != nil {
+		diags.Append(diagFromClientError("Unable to read access key", err))
+		return diags
+	}
+	if key == nil {
+		if len(desired) == 0 {
+			// Nothing to revoke on a key that no longer exists
+			return diags
+		}
+		diags.AddError("Access Key Not Found", fmt.Sprintf("Access key %s does not exist on this cluster.", accessKeyID))
+		return diags
+	}
+
+	current := make(map[string]client.Permissions, len(key.Buckets))
+	for _, b := range key.Buckets {
+		current[b.ID] = b.Permissions
+	}
+
+	bucketIDs := make([]string, 0, len(desired)+len(current))
+	for id := range desired {
+		bucketIDs = append(bucketIDs, id)
+	}
+	for id := range current {
+		if _, ok := desired[id]; !ok {
+			bucketIDs = append(bucketIDs, id)
+		}
+	}
+	sort.Strings(bucketIDs)
+
+	for _, bucketID := range bucketIDs {
+		grant, revoke := permissionDelta(current[bucketID], desired[bucketID])
+
+		if hasAnyPermission(grant) {
+			tflog.Debug(ctx, "Granting bucket permissions", map[string]interface{}{
+				"bucket_id":     bucketID,
+				"access_key_id": accessKeyID,
+			})
+			_, err := r.client.AllowBucketKey(ctx, client.BucketKeyPermRequest{
+				BucketID:    bucketID,
+				AccessKeyID: accessKeyID,
+				Permissions: grant,
+			})
+			if err != nil {
+				diags.Append(diagFromClientError(fmt.Sprintf("Unable to grant permissions on bucket %s", bucketID), err))
+				return diags
+			}
+		}
+
+		if hasAnyPermission(revoke) {
+			tflog.Debug(ctx, "Revoking bucket permissions", map[string]interface{}{
+				"bucket_id":     bucketID,
+				"access_key_id": accessKeyID,
+			})
+			_, err := r.client.DenyBucketKey(ctx, client.BucketKeyPermRequest{
+				BucketID:    bucketID,
+				AccessKeyID: accessKeyID,
+				Permissions: revoke,
+			})
+			if err != nil {
+				diags.Append(diagFromClientError(fmt.Sprintf("Unable to revoke permissions on bucket %s", bucketID), err))
+				return diags
+			}
+		}
+	}
+
+	return diags
+}
+
+// keyGrantsFromKey converts the grants reported by the API into grant models.
+// Buckets referenced by alias in prior are reported by that alias, and flags
+// that were unset in prior stay unset while they are false, so a key that
+// matches the configuration produces no diff.
+func keyGrantsFromKey(key *client.AccessKey, prior []KeyGrantModel) []KeyGrantModel {
+	grants := make([]KeyGrantModel, 0, len(key.Buckets))
+
+	for _, b := range key.Buckets {
+		if !hasAnyPermission(b.Permissions) {
+			continue
+		}
+
+		var match *KeyGrantModel
+		for i := range prior {
+			p := &prior[i]
+			if !p.BucketID.IsNull() && p.BucketID.ValueString() == b.ID {
+				match = p
+				break
+			}
+			if !p.BucketAlias.IsNull() {
+				for _, alias := range b.GlobalAliases {
+					if alias == p.BucketAlias.ValueString() {
+						match = p
+						break
+					}
+				}
+				if match != nil {
+					break
+				}
+			}
+		}
+
+		grant := KeyGrantModel{
+			BucketID:    types.StringValue(b.ID),
+			BucketAlias: types.StringNull(),
+			Read:        types.BoolValue(b.Permissions.Read),
+			Write:       types.BoolValue(b.Permissions.Write),
+			Owner:       types.BoolValue(b.Permissions.Owner),
+		}
+
+		if match != nil {
+			if match.BucketID.IsNull() {
+				grant.BucketID = types.StringNull()
+				grant.BucketAlias = match.BucketAlias
+			}
+			grant.Read = boolPreservingNull(match.Read, b.Permissions.Read)
+			grant.Write = boolPreservingNull(match.Write, b.Permissions.Write)
+			grant.Owner = boolPreservingNull(match.Owner, b.Permissions.Owner)
+		}
+
+		grants = append(grants, grant)
+	}
+
+	return grants
+}
+
+// boolPreservingNull returns v, unless v is false and prior was null, in which
+// case null is kept to match an unset optional attribute.
+func boolPreservingNull(prior types.Bool, v bool) types.Bool {
+	if !v && prior.IsNull() {
+		return types.BoolNull()
+	}
+	return types.BoolValue(v)
+}
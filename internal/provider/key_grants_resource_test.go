@@ -0,0 +1,188 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+func TestAccKeyGrantsResource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Grant the key access to both buckets
+			{
+				Config: testAccKeyGrantsResourceConfig(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("garage_key_grants.test", "id", "garage_key.test", "id"),
+					resource.TestCheckResourceAttr("garage_key_grants.test", "grant.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("garage_key_grants.test", "grant.*", map[string]string{
+						"bucket_alias": "test-grants-bucket-b",
+						"read":         "true",
+						"write":        "true",
+					}),
+				),
+			},
+			// ImportState testing: imported grants are reported by bucket ID
+			{
+				ResourceName:            "garage_key_grants.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"grant"},
+			},
+			// Dropping a grant from the configuration revokes it
+			{
+				Config: testAccKeyGrantsResourceConfig(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_key_grants.test", "grant.#", "1"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestKeyGrantsReconcile(t *testing.T) {
+	var allowed, denied []client.BucketKeyPermRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v2/GetBucketInfo":
+			_ = json.NewEncoder(w).Encode(client.Bucket{ID: "bucket-b", GlobalAliases: []string{"alias-b"}})
+		case "/v2/GetKeyInfo":
+			_ = json.NewEncoder(w).Encode(client.AccessKey{
+				AccessKeyID: "GK123",
+				Buckets: []client.KeyBucketInfo{
+					{ID: "bucket-a", Permissions: client.Permissions{Read: true, Write: true}},
+					{ID: "bucket-c", Permissions: client.Permissions{Owner: true}},
+				},
+			})
+		case "/v2/AllowBucketKey", "/v2/DenyBucketKey":
+			var req client.BucketKeyPermRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if r.URL.Path == "/v2/AllowBucketKey" {
+				allowed = append(allowed, req)
+			} else {
+				denied = append(denied, req)
+			}
+			_ = json.NewEncoder(w).Encode(client.Bucket{ID: req.BucketID})
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	grants, diags := types.SetValueFrom(context.Background(), types.ObjectType{AttrTypes: keyGrantAttrTypes}, []KeyGrantModel{
+		{BucketID: types.StringValue("bucket-a"), BucketAlias: types.StringNull(), Read: types.BoolValue(true), Write: types.BoolNull(), Owner: types.BoolNull()},
+		{BucketID: types.StringNull(), BucketAlias: types.StringValue("alias-b"), Read: types.BoolValue(true), Write: types.BoolNull(), Owner: types.BoolNull()},
+	})
+	if diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+
+	r := &KeyGrantsResource{client: client.NewClient(server.URL, "test-token")}
+	data := &KeyGrantsResourceModel{AccessKeyID: types.StringValue("GK123"), Grants: grants}
+
+	if diags := r.reconcile(context.Background(), data); diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+
+	expectedAllowed := []client.BucketKeyPermRequest{
+		{BucketID: "bucket-b", AccessKeyID: "GK123", Permissions: client.Permissions{Read: true}},
+	}
+	expectedDenied := []client.BucketKeyPermRequest{
+		{BucketID: "bucket-a", AccessKeyID: "GK123", Permissions: client.Permissions{Write: true}},
+		{BucketID: "bucket-c", AccessKeyID: "GK123", Permissions: client.Permissions{Owner: true}},
+	}
+
+	if fmt.Sprint(allowed) != fmt.Sprint(expectedAllowed) {
+		t.Errorf("Expected allow calls %+v, got %+v", expectedAllowed, allowed)
+	}
+	if fmt.Sprint(denied) != fmt.Sprint(expectedDenied) {
+		t.Errorf("Expected deny calls %+v, got %+v", expectedDenied, denied)
+	}
+}
+
+func TestKeyGrantsFromKey(t *testing.T) {
+	key := &client.AccessKey{
+		AccessKeyID: "GK123",
+		Buckets: []client.KeyBucketInfo{
+			{ID: "bucket-a", GlobalAliases: []string{"alias-a"}, Permissions: client.Permissions{Read: true}},
+			{ID: "bucket-b", Permissions: client.Permissions{Read: true, Write: true}},
+			{ID: "bucket-c"},
+		},
+	}
+	prior := []KeyGrantModel{
+		{BucketID: types.StringNull(), BucketAlias: types.StringValue("alias-a"), Read: types.BoolValue(true), Write: types.BoolNull(), Owner: types.BoolNull()},
+	}
+
+	grants := keyGrantsFromKey(key, prior)
+
+	if len(grants) != 2 {
+		t.Fatalf("Expected 2 grants, got %d", len(grants))
+	}
+
+	// Grant referenced by alias keeps the alias and its unset flags
+	if !grants[0].BucketID.IsNull() || grants[0].BucketAlias.ValueString() != "alias-a" {
+		t.Errorf("Expected first grant to reference alias-a, got %+v", grants[0])
+	}
+	if !grants[0].Write.IsNull() || !grants[0].Owner.IsNull() {
+		t.Errorf("Expected unset flags to stay null, got %+v", grants[0])
+	}
+
+	// Unmanaged grant is reported by bucket ID with explicit flags
+	if grants[1].BucketID.ValueString() != "bucket-b" || !grants[1].Write.ValueBool() || grants[1].Owner.IsNull() {
+		t.Errorf("Unexpected second grant: %+v", grants[1])
+	}
+}
+
+func testAccKeyGrantsResourceConfig(withBucketA bool) string {
+	grantA := ""
+	if withBucketA {
+		grantA = `
+    {
+      bucket_id = garage_bucket.a.id
+      read      = true
+    },`
+	}
+
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_bucket" "a" {
+  global_alias = "test-grants-bucket-a"
+}
+
+resource "garage_bucket" "b" {
+  global_alias = "test-grants-bucket-b"
+}
+
+resource "garage_key" "test" {
+  name = "test-grants-key"
+}
+
+resource "garage_key_grants" "test" {
+  access_key_id = garage_key.test.id
+
+  grant = [%s
+    {
+      bucket_alias = garage_bucket.b.global_alias
+      read         = true
+      write        = true
+    },
+  ]
+}
+`, grantA)
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -89,24 +90,9 @@ func (r *KeyResource) Configure(ctx context.Context, req resource.ConfigureReque
 		return
 	}
 
-	// Get admin endpoint with backwards compatibility
-	adminEndpoint := ""
-	if providerData.Endpoints != nil && !providerData.Endpoints.Admin.IsNull() {
-		adminEndpoint = providerData.Endpoints.Admin.ValueString()
-	} else if !providerData.Endpoint.IsNull() {
-		// Fallback to deprecated endpoint
-		adminEndpoint = providerData.Endpoint.ValueString()
-	}
-
-	if adminEndpoint == "" {
-		resp.Diagnostics.AddError(
-			"Missing Admin Endpoint",
-			"Admin endpoint must be configured via 'endpoints.admin' or deprecated 'endpoint' attribute",
-		)
-		return
-	}
-
-	r.client = client.NewClient(adminEndpoint, providerData.Token.ValueString())
+	var diags diag.Diagnostics
+	r.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
 }
 
 func (r *KeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -140,7 +126,7 @@ func (r *KeyResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 		key, err := r.client.ImportKey(ctx, importReq)
 		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to import access key, got error: %s", err))
+			resp.Diagnostics.Append(diagFromClientError("Unable to import access key", err))
 			return
 		}
 
@@ -163,7 +149,7 @@ func (r *KeyResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 		key, err := r.client.CreateKey(ctx, createReq)
 		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create access key, got error: %s", err))
+			resp.Diagnostics.Append(diagFromClientError("Unable to create access key", err))
 			return
 		}
 
@@ -201,7 +187,7 @@ func (r *KeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	})
 
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read access key, got error: %s", err))
+		resp.Diagnostics.Append(diagFromClientError("Unable to read access key", err))
 		return
 	}
 
@@ -253,7 +239,7 @@ func (r *KeyResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 	})
 
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete access key, got error: %s", err))
+		resp.Diagnostics.Append(diagFromClientError("Unable to delete access key", err))
 		return
 	}
 
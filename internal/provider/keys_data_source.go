@@ -0,0 +1,280 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// keysInfoParallelism is the number of keys read at once when a filter needs
+// more than the listing.
+const keysInfoParallelism = 8
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &KeysDataSource{}
+
+func NewKeysDataSource() datasource.DataSource {
+	return &KeysDataSource{}
+}
+
+// KeysDataSource defines the data source implementation.
+type KeysDataSource struct {
+	client *client.Client
+}
+
+// KeysDataSourceModel describes the data source data model.
+type KeysDataSourceModel struct {
+	NamePrefix  types.String `tfsdk:"name_prefix"`
+	NameRegex   types.String `tfsdk:"name_regex"`
+	ExpiredOnly types.Bool   `tfsdk:"expired_only"`
+	UnnamedOnly types.Bool   `tfsdk:"unnamed_only"`
+	Keys        types.List   `tfsdk:"keys"`
+}
+
+// KeysDataSourceKeyModel describes a key of the data source.
+type KeysDataSourceKeyModel struct {
+	ID         types.String `tfsdk:"id"`
+	Name       types.String `tfsdk:"name"`
+	Created    types.String `tfsdk:"created"`
+	Expiration types.String `tfsdk:"expiration"`
+	Expired    types.Bool   `tfsdk:"expired"`
+}
+
+// keysKeyAttrTypes are the attribute types of a key object.
+var keysKeyAttrTypes = map[string]attr.Type{
+	"id":         types.StringType,
+	"name":       types.StringType,
+	"created":    types.StringType,
+	"expiration": types.StringType,
+	"expired":    types.BoolType,
+}
+
+func (d *KeysDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_keys"
+}
+
+func (d *KeysDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the Garage access keys, optionally filtered. The secrets of the keys are never returned. " +
+			"All the filters set must match for a key to be listed.",
+
+		Attributes: map[string]schema.Attribute{
+			"name_prefix": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list the keys whose name starts with this prefix, e.g. `ci-`.",
+			},
+			"name_regex": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list the keys whose name matches this regular expression, in [RE2 syntax](https://github.com/google/re2/wiki/Syntax), e.g. `^ci-[0-9]+$`.",
+				Validators: []validator.String{
+					validators.Regexp(),
+				},
+			},
+			"expired_only": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list the keys that have expired. Each key with an expiration is read to check it. Requires Garage v2.0 or later.",
+			},
+			"unnamed_only": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list the keys without a name.",
+			},
+			"keys": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "The keys, sorted by ID.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The access key ID.",
+						},
+						"name": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The name of the key, empty when unnamed.",
+						},
+						"created": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The creation date of the key, in RFC 3339 format.",
+						},
+						"expiration": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The expiration date of the key, in RFC 3339 format. Null when the key never expires.",
+						},
+						"expired": schema.BoolAttribute{
+							Computed:            true,
+							MarkdownDescription: "Whether the key has expired.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *KeysDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	var diags diag.Diagnostics
+	d.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
+}
+
+func (d *KeysDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data KeysDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Reading keys data source", map[string]interface{}{
+		"name_prefix":  data.NamePrefix.ValueString(),
+		"name_regex":   data.NameRegex.ValueString(),
+		"expired_only": data.ExpiredOnly.ValueBool(),
+		"unnamed_only": data.UnnamedOnly.ValueBool(),
+	})
+
+	var nameRegex *regexp.Regexp
+	if !data.NameRegex.IsNull() {
+		var err error
+		// Already validated, unless the value was unknown at validation
+		nameRegex, err = regexp.Compile(data.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name_regex"),
+				"Invalid Regular Expression",
+				fmt.Sprintf("Unable to compile name_regex: %s", err),
+			)
+			return
+		}
+	}
+
+	keys, err := d.client.ListKeys(ctx)
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError("Unable to list keys", err))
+		return
+	}
+
+	// The filters on the listing come first, so that only the keys left are
+	// read for the others.
+	listed := keys[:0]
+	for _, key := range keys {
+		if !data.NamePrefix.IsNull() && !strings.HasPrefix(key.Name, data.NamePrefix.ValueString()) {
+			continue
+		}
+		if nameRegex != nil && !nameRegex.MatchString(key.Name) {
+			continue
+		}
+		if data.UnnamedOnly.ValueBool() && key.Name != "" {
+			continue
+		}
+		listed = append(listed, key)
+	}
+
+	if data.ExpiredOnly.ValueBool() {
+		var diags diag.Diagnostics
+		listed, diags = d.expiredKeys(ctx, listed)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	sort.Slice(listed, func(i, j int) bool {
+		return listed[i].ID < listed[j].ID
+	})
+
+	models := make([]KeysDataSourceKeyModel, 0, len(listed))
+	for _, key := range listed {
+		models = append(models, KeysDataSourceKeyModel{
+			ID:         types.StringValue(key.ID),
+			Name:       types.StringValue(key.Name),
+			Created:    types.StringPointerValue(key.Created),
+			Expiration: types.StringPointerValue(key.Expiration),
+			Expired:    types.BoolValue(key.Expired),
+		})
+	}
+
+	list, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: keysKeyAttrTypes}, models)
+	resp.Diagnostics.Append(diags...)
+	data.Keys = list
+
+	tflog.Trace(ctx, "Read keys data source", map[string]interface{}{
+		"listed": len(keys),
+		"kept":   len(models),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// expiredKeys returns the keys of keys that have expired. Keys without an
+// expiration never expire; the others are read with bounded parallelism to
+// check the state of the key itself. Keys deleted in the meantime are
+// dropped.
+func (d *KeysDataSource) expiredKeys(ctx context.Context, keys []client.KeyListItem) ([]client.KeyListItem, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	infos := make([]*client.AccessKey, len(keys))
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, keysInfoParallelism)
+
+	for i, key := range keys {
+		if key.Expiration == nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			infos[i], errs[i] = d.client.GetKeyInfo(ctx, client.GetKeyInfoRequest{ID: key.ID})
+		}()
+	}
+	wg.Wait()
+
+	expired := make([]client.KeyListItem, 0, len(keys))
+	for i, key := range keys {
+		if errs[i] != nil {
+			diags.Append(diagFromClientError(fmt.Sprintf("Unable to read key %s", key.ID), errs[i]))
+			return nil, diags
+		}
+		if infos[i] == nil || !infos[i].Expired {
+			continue
+		}
+		key.Expiration = infos[i].Expiration
+		key.Expired = true
+		expired = append(expired, key)
+	}
+
+	return expired, diags
+}
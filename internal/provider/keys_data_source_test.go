@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+func TestKeysDataSource(t *testing.T) {
+	var mu sync.Mutex
+	var infos []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/ListKeys":
+			_, _ = w.Write([]byte(`[
+				{"id": "GK3", "name": "ci-12", "created": "2025-03-01T00:00:00Z", "expiration": "2025-04-01T00:00:00Z", "expired": true},
+				{"id": "GK1", "name": "ci-10", "created": "2025-01-01T00:00:00Z", "expired": false},
+				{"id": "GK4", "name": "", "created": "2025-04-01T00:00:00Z", "expiration": "2099-01-01T00:00:00Z", "expired": false},
+				{"id": "GK2", "name": "ci-build", "created": "2025-02-01T00:00:00Z", "expiration": "2025-03-01T00:00:00Z", "expired": true},
+				{"id": "GK5", "name": "backup", "created": "2025-05-01T00:00:00Z", "expired": false}
+			]`))
+		case "/v2/GetKeyInfo":
+			id := r.URL.Query().Get("id")
+			mu.Lock()
+			infos = append(infos, id)
+			mu.Unlock()
+			expired := id != "GK4"
+			_, _ = fmt.Fprintf(w, `{"accessKeyId": %q, "name": "", "expired": %t, "expiration": "2025-03-01T00:00:00Z", "permissions": {}, "buckets": []}`, id, expired)
+		default:
+			t.Errorf("Unexpected request to %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	d := &KeysDataSource{client: client.NewClient(server.URL, "test-token")}
+
+	tests := []struct {
+		name     string
+		attrs    map[string]tftypes.Value
+		expected string
+		infos    string
+	}{
+		{name: "all", expected: "[GK1 GK2 GK3 GK4 GK5]", infos: "[]"},
+		{
+			name:     "name prefix",
+			attrs:    map[string]tftypes.Value{"name_prefix": tftypes.NewValue(tftypes.String, "ci-")},
+			expected: "[GK1 GK2 GK3]",
+			infos:    "[]",
+		},
+		{
+			name:     "name regex",
+			attrs:    map[string]tftypes.Value{"name_regex": tftypes.NewValue(tftypes.String, "^ci-[0-9]+$")},
+			expected: "[GK1 GK3]",
+			infos:    "[]",
+		},
+		{
+			name:     "unnamed only",
+			attrs:    map[string]tftypes.Value{"unnamed_only": tftypes.NewValue(tftypes.Bool, true)},
+			expected: "[GK4]",
+			infos:    "[]",
+		},
+		{
+			name:     "expired only",
+			attrs:    map[string]tftypes.Value{"expired_only": tftypes.NewValue(tftypes.Bool, true)},
+			expected: "[GK2 GK3]",
+			infos:    "[GK2 GK3 GK4]",
+		},
+		{
+			name: "expired only with a name prefix",
+			attrs: map[string]tftypes.Value{
+				"name_prefix":  tftypes.NewValue(tftypes.String, "ci-b"),
+				"expired_only": tftypes.NewValue(tftypes.Bool, true),
+			},
+			expected: "[GK2]",
+			infos:    "[GK2]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			infos = nil
+
+			resp := testDataSourceRead(t, d, tt.attrs)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var keys []KeysDataSourceKeyModel
+			resp.Diagnostics.Append(resp.State.GetAttribute(context.Background(), path.Root("keys"), &keys)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var ids []string
+			for _, key := range keys {
+				ids = append(ids, key.ID.ValueString())
+			}
+			if fmt.Sprint(ids) != tt.expected {
+				t.Errorf("Expected keys %s, got %v", tt.expected, ids)
+			}
+			sort.Strings(infos)
+			if fmt.Sprint(infos) != tt.infos {
+				t.Errorf("Expected the keys %s to be read, got %v", tt.infos, infos)
+			}
+		})
+	}
+}
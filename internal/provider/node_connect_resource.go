@@ -0,0 +1,397 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &NodeConnectResource{}
+
+// nodeConnectVerifyAttempts is the number of times the cluster status is
+// checked for the peers after connecting to them.
+const nodeConnectVerifyAttempts = 5
+
+// nodeConnectVerifyDelay is the delay between two checks of the cluster
+// status, and between two connection attempts with wait_for_connection. It
+// is a variable so tests can shorten it.
+var nodeConnectVerifyDelay = 2 * time.Second
+
+// defaultNodeConnectTimeout is how long peers are retried with
+// wait_for_connection when connection_timeout is not set.
+const defaultNodeConnectTimeout = "5m"
+
+func NewNodeConnectResource() resource.Resource {
+	return &NodeConnectResource{}
+}
+
+// NodeConnectResource defines the resource implementation.
+type NodeConnectResource struct {
+	client *client.Client
+}
+
+// NodeConnectResourceModel describes the resource data model.
+type NodeConnectResourceModel struct {
+	ID                types.String `tfsdk:"id"`
+	Peers             types.Set    `tfsdk:"peers"`
+	WaitForConnection types.Bool   `tfsdk:"wait_for_connection"`
+	ConnectionTimeout types.String `tfsdk:"connection_timeout"`
+}
+
+func (r *NodeConnectResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_connect"
+}
+
+func (r *NodeConnectResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Connects the node serving the admin API to other Garage nodes, as `garage node connect` does when bootstrapping a cluster. " +
+			"The peers are connected on creation and again whenever `peers` changes, then checked to be known to the cluster. " +
+			"With `wait_for_connection`, peers that are not listening yet are retried until they are up or `connection_timeout` expires. " +
+			"Garage cannot disconnect nodes: destroying the resource only removes it from the state.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "A hash of the connected peers.",
+			},
+			"peers": schema.SetAttribute{
+				Required:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The nodes to connect to, as `<node_id>@<host>:<port>` with the full hexadecimal node ID and the RPC port, as printed by `garage node id`.",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ValueStringsAre(validators.NodePeer()),
+				},
+			},
+			"wait_for_connection": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Retry connecting to the peers until every one of them is up in the cluster, for nodes still booting when Terraform runs. Without it, a peer that refuses the connection fails the apply. Defaults to `false`.",
+				Default:             booldefault.StaticBool(false),
+			},
+			"connection_timeout": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "How long to retry the peers with `wait_for_connection`, as a Go duration (e.g. `10m`). Defaults to `" + defaultNodeConnectTimeout + "`.",
+				Default:             stringdefault.StaticString(defaultNodeConnectTimeout),
+				Validators: []validator.String{
+					validators.DurationBetween(time.Second, 24*time.Hour),
+				},
+			},
+		},
+	}
+}
+
+func (r *NodeConnectResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	var diags diag.Diagnostics
+	r.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *NodeConnectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data NodeConnectResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.connect(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "Created node connect resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *NodeConnectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Nothing to refresh: peers that went down since are reported by the
+	// cluster status, not by this resource
+	var data NodeConnectResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *NodeConnectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data NodeConnectResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.connect(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "Updated node connect resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *NodeConnectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Garage has no way to disconnect nodes, removing from state is enough
+	tflog.Debug(ctx, "Removing node connect resource from state, peers stay connected")
+}
+
+// connect connects to every peer of data, waits for them to be known to the
+// cluster and sets the ID. Every peer that failed is reported in a single
+// error.
+func (r *NodeConnectResource) connect(ctx context.Context, data *NodeConnectResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var peers []string
+	diags.Append(data.Peers.ElementsAs(ctx, &peers, false)...)
+	if diags.HasError() {
+		return diags
+	}
+	sort.Strings(peers)
+
+	if data.WaitForConnection.ValueBool() {
+		diags.Append(r.connectWithRetries(ctx, peers, data.ConnectionTimeout.ValueString())...)
+		if diags.HasError() {
+			return diags
+		}
+		data.ID = nodeConnectID(peers)
+		return diags
+	}
+
+	tflog.Debug(ctx, "Connecting to peers", map[string]interface{}{
+		"peers": peers,
+	})
+
+	results, err := r.client.ConnectClusterNodes(ctx, peers)
+	if err != nil {
+		diags.Append(diagFromClientError("Unable to connect to peers", err))
+		return diags
+	}
+
+	failures := make(map[string]string)
+	var connected []string
+	for i, result := range results {
+		if result.Success {
+			connected = append(connected, peers[i])
+			continue
+		}
+
+		reason := "unknown error"
+		if result.Error != nil {
+			reason = *result.Error
+		}
+		failures[peers[i]] = reason
+	}
+
+	unknown, err := r.waitForPeers(ctx, connected)
+	if err != nil {
+		diags.Append(diagFromClientError("Unable to read cluster status", err))
+		return diags
+	}
+	for _, peer := range unknown {
+		failures[peer] = fmt.Sprintf("connected but still not known to the cluster after %d checks", nodeConnectVerifyAttempts)
+	}
+
+	if len(failures) > 0 {
+		diags.AddError("Failed to Connect Peers", nodeConnectFailures(fmt.Sprintf("Could not connect to %d of %d peers:", len(failures), len(peers)), failures))
+		return diags
+	}
+
+	data.ID = nodeConnectID(peers)
+
+	return diags
+}
+
+// connectWithRetries connects to the peers until every one of them is up in
+// the cluster, retrying the peers that refused the connection or are not up
+// yet until timeout expires. The peers that never connected are reported in
+// a single error.
+func (r *NodeConnectResource) connectWithRetries(ctx context.Context, peers []string, timeout string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	duration, err := time.ParseDuration(timeout)
+	if err != nil {
+		diags.AddAttributeError(path.Root("connection_timeout"), "Invalid Duration", fmt.Sprintf("Unable to parse connection_timeout %q: %s", timeout, err))
+		return diags
+	}
+	deadline := time.Now().Add(duration)
+
+	pending := peers
+	var failures map[string]string
+	for attempt := 1; ; attempt++ {
+		tflog.Debug(ctx, "Connecting to peers", map[string]interface{}{
+			"peers":   pending,
+			"attempt": attempt,
+		})
+
+		results, err := r.client.ConnectClusterNodes(ctx, pending)
+		if err != nil {
+			diags.Append(diagFromClientError("Unable to connect to peers", err))
+			return diags
+		}
+
+		failures = make(map[string]string)
+		var connected []string
+		for i, result := range results {
+			if result.Success {
+				connected = append(connected, pending[i])
+				continue
+			}
+
+			reason := "unknown error"
+			if result.Error != nil {
+				reason = *result.Error
+			}
+			failures[pending[i]] = reason
+		}
+
+		status, err := r.client.GetClusterStatus(ctx)
+		if err != nil {
+			diags.Append(diagFromClientError("Unable to read cluster status", err))
+			return diags
+		}
+		up := make(map[string]bool, len(status.Nodes))
+		for _, node := range status.Nodes {
+			up[node.ID] = node.IsUp
+		}
+		for _, peer := range connected {
+			nodeID, _, _ := strings.Cut(peer, "@")
+			if !up[nodeID] {
+				failures[peer] = "connected but not up in the cluster yet"
+			}
+		}
+
+		if len(failures) == 0 {
+			return diags
+		}
+		if !time.Now().Add(nodeConnectVerifyDelay).Before(deadline) {
+			break
+		}
+
+		pending = sortedKeys(failures)
+		for _, peer := range pending {
+			tflog.Info(ctx, "Retrying connection to peer", map[string]interface{}{
+				"peer":    peer,
+				"reason":  failures[peer],
+				"attempt": attempt,
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			diags.AddError("Client Error", fmt.Sprintf("Interrupted while connecting to peers: %s", ctx.Err()))
+			return diags
+		case <-time.After(nodeConnectVerifyDelay):
+		}
+	}
+
+	diags.AddError(
+		"Failed to Connect Peers",
+		nodeConnectFailures(fmt.Sprintf("%d of %d peers did not connect within %s:", len(failures), len(peers), timeout), failures),
+	)
+	return diags
+}
+
+// nodeConnectFailures lists the peers that failed after summary, with their
+// last error.
+func nodeConnectFailures(summary string, failures map[string]string) string {
+	var detail strings.Builder
+	detail.WriteString(summary)
+	for _, peer := range sortedKeys(failures) {
+		fmt.Fprintf(&detail, "\n  - %s: %s", peer, failures[peer])
+	}
+	return detail.String()
+}
+
+// nodeConnectID returns the ID of the resource, a hash of the sorted peers.
+func nodeConnectID(peers []string) types.String {
+	hash := sha256.Sum256([]byte(strings.Join(peers, "\n")))
+	return types.StringValue(hex.EncodeToString(hash[:8]))
+}
+
+// waitForPeers checks the cluster status until every peer is known, and
+// returns the peers that are still unknown after the last check.
+func (r *NodeConnectResource) waitForPeers(ctx context.Context, peers []string) ([]string, error) {
+	unknown := peers
+	for attempt := 1; len(unknown) > 0; attempt++ {
+		status, err := r.client.GetClusterStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		known := make(map[string]bool, len(status.Nodes))
+		for _, node := range status.Nodes {
+			known[node.ID] = true
+		}
+
+		var remaining []string
+		for _, peer := range unknown {
+			nodeID, _, _ := strings.Cut(peer, "@")
+			if !known[nodeID] {
+				remaining = append(remaining, peer)
+			}
+		}
+		unknown = remaining
+
+		if len(unknown) == 0 || attempt == nodeConnectVerifyAttempts {
+			break
+		}
+
+		tflog.Debug(ctx, "Waiting for peers to be known to the cluster", map[string]interface{}{
+			"unknown": unknown,
+			"attempt": attempt,
+		})
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(nodeConnectVerifyDelay):
+		}
+	}
+
+	return unknown, nil
+}
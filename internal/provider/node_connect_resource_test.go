@@ -0,0 +1,205 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+func TestAccNodeConnectResource_basic(t *testing.T) {
+	// A second node is needed to connect to
+	peer := os.Getenv("GARAGE_TEST_PEER")
+	if peer == "" {
+		t.Skip("GARAGE_TEST_PEER must be set to <node_id>@<host>:<port> of another node for this test")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig() + fmt.Sprintf(`
+resource "garage_node_connect" "test" {
+  peers = [%q]
+}
+`, peer),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_node_connect.test", "peers.#", "1"),
+					resource.TestCheckResourceAttrSet("garage_node_connect.test", "id"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestNodeConnect(t *testing.T) {
+	nodeA := strings.Repeat("a", 64)
+	nodeB := strings.Repeat("b", 64)
+	nodeC := strings.Repeat("c", 64)
+
+	oldDelay := nodeConnectVerifyDelay
+	nodeConnectVerifyDelay = 0
+	defer func() { nodeConnectVerifyDelay = oldDelay }()
+
+	statusCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v2/ConnectClusterNodes":
+			var peers []string
+			_ = json.NewDecoder(r.Body).Decode(&peers)
+			results := make([]client.ConnectNodeResult, len(peers))
+			for i, peer := range peers {
+				results[i].Success = true
+				if strings.HasPrefix(peer, nodeB) {
+					reason := "connection refused"
+					results[i] = client.ConnectNodeResult{Error: &reason}
+				}
+			}
+			_ = json.NewEncoder(w).Encode(results)
+		case "/v2/GetClusterStatus":
+			// Node A becomes known on the second check, node C never does
+			statusCalls++
+			status := client.ClusterStatus{}
+			if statusCalls > 1 {
+				status.Nodes = []client.NodeStatus{{ID: nodeA, IsUp: true}}
+			}
+			_ = json.NewEncoder(w).Encode(status)
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	r := &NodeConnectResource{client: client.NewClient(server.URL, "test-token")}
+	peers, _ := types.SetValueFrom(context.Background(), types.StringType, []string{
+		nodeA + "@10.0.0.1:3901",
+		nodeB + "@10.0.0.2:3901",
+		nodeC + "@10.0.0.3:3901",
+	})
+
+	data := &NodeConnectResourceModel{Peers: peers}
+	diags := r.connect(context.Background(), data)
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("Expected 1 error, got %v", diags)
+	}
+
+	detail := diags.Errors()[0].Detail()
+	if !strings.Contains(detail, "2 of 3 peers") ||
+		!strings.Contains(detail, nodeB+"@10.0.0.2:3901: connection refused") ||
+		!strings.Contains(detail, nodeC+"@10.0.0.3:3901: connected but still not known") ||
+		strings.Contains(detail, nodeA) {
+		t.Errorf("Expected nodes B and C to be reported, got %s", detail)
+	}
+	if statusCalls != nodeConnectVerifyAttempts {
+		t.Errorf("Expected %d status checks, got %d", nodeConnectVerifyAttempts, statusCalls)
+	}
+
+	// Without failures the ID is set
+	peers, _ = types.SetValueFrom(context.Background(), types.StringType, []string{nodeA + "@10.0.0.1:3901"})
+	data = &NodeConnectResourceModel{Peers: peers}
+	if diags := r.connect(context.Background(), data); diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	if data.ID.ValueString() == "" {
+		t.Error("Expected the ID to be set")
+	}
+}
+
+func TestNodeConnectWaitForConnection(t *testing.T) {
+	nodeA := strings.Repeat("a", 64)
+	nodeB := strings.Repeat("b", 64)
+	nodeC := strings.Repeat("c", 64)
+
+	oldDelay := nodeConnectVerifyDelay
+	nodeConnectVerifyDelay = time.Millisecond
+	defer func() { nodeConnectVerifyDelay = oldDelay }()
+
+	var mu sync.Mutex
+	connects := make(map[string]int)
+	statusCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v2/ConnectClusterNodes":
+			// Node B listens from its third attempt, node C never does
+			var peers []string
+			_ = json.NewDecoder(r.Body).Decode(&peers)
+			results := make([]client.ConnectNodeResult, len(peers))
+			for i, peer := range peers {
+				connects[peer]++
+				results[i].Success = true
+				if strings.HasPrefix(peer, nodeC) || (strings.HasPrefix(peer, nodeB) && connects[peer] < 3) {
+					reason := "connection refused"
+					results[i] = client.ConnectNodeResult{Error: &reason}
+				}
+			}
+			_ = json.NewEncoder(w).Encode(results)
+		case "/v2/GetClusterStatus":
+			// Node A is known from the start but only up on the second check
+			statusCalls++
+			status := client.ClusterStatus{Nodes: []client.NodeStatus{
+				{ID: nodeA, IsUp: statusCalls > 1},
+				{ID: nodeB, IsUp: true},
+			}}
+			_ = json.NewEncoder(w).Encode(status)
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	r := &NodeConnectResource{client: client.NewClient(server.URL, "test-token")}
+	peerA, peerB, peerC := nodeA+"@10.0.0.1:3901", nodeB+"@10.0.0.2:3901", nodeC+"@10.0.0.3:3901"
+
+	peers, _ := types.SetValueFrom(context.Background(), types.StringType, []string{peerA, peerB})
+	data := &NodeConnectResourceModel{Peers: peers, WaitForConnection: types.BoolValue(true), ConnectionTimeout: types.StringValue("1m")}
+	if diags := r.connect(context.Background(), data); diags.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", diags)
+	}
+	if data.ID.ValueString() == "" {
+		t.Error("Expected the ID to be set")
+	}
+	// Only the peers that did not connect are retried
+	if connects[peerA] != 2 || connects[peerB] != 3 {
+		t.Errorf("Expected 2 attempts for node A and 3 for node B, got %v", connects)
+	}
+
+	// Peers that never connect are reported once the timeout expires
+	peers, _ = types.SetValueFrom(context.Background(), types.StringType, []string{peerA, peerC})
+	data = &NodeConnectResourceModel{Peers: peers, WaitForConnection: types.BoolValue(true), ConnectionTimeout: types.StringValue("20ms")}
+	diags := r.connect(context.Background(), data)
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("Expected 1 error, got %v", diags)
+	}
+
+	detail := diags.Errors()[0].Detail()
+	if !strings.Contains(detail, "1 of 2 peers did not connect within 20ms") ||
+		!strings.Contains(detail, peerC+": connection refused") ||
+		strings.Contains(detail, nodeA) {
+		t.Errorf("Expected node C to be reported, got %s", detail)
+	}
+	if !data.ID.IsNull() {
+		t.Errorf("Expected no ID, got %s", data.ID)
+	}
+}
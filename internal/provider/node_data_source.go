@@ -0,0 +1,295 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &NodeDataSource{}
+
+func NewNodeDataSource() datasource.DataSource {
+	return &NodeDataSource{}
+}
+
+// NodeDataSource defines the data source implementation.
+type NodeDataSource struct {
+	client *client.Client
+}
+
+// NodeDataSourceModel describes the data source data model. It is also the
+// model of the nodes of garage_nodes.
+type NodeDataSourceModel struct {
+	ID                types.String  `tfsdk:"id"`
+	Hostname          types.String  `tfsdk:"hostname"`
+	Addr              types.String  `tfsdk:"addr"`
+	GarageVersion     types.String  `tfsdk:"garage_version"`
+	IsUp              types.Bool    `tfsdk:"is_up"`
+	LastSeenSecsAgo   types.Int64   `tfsdk:"last_seen_secs_ago"`
+	Draining          types.Bool    `tfsdk:"draining"`
+	Role              types.String  `tfsdk:"role"`
+	Zone              types.String  `tfsdk:"zone"`
+	Capacity          types.Int64   `tfsdk:"capacity"`
+	Tags              types.Set     `tfsdk:"tags"`
+	DataPartition     types.Object  `tfsdk:"data_partition"`
+	MetadataPartition types.Object  `tfsdk:"metadata_partition"`
+	DataUsedPercent   types.Float64 `tfsdk:"data_used_percent"`
+}
+
+// Kinds of roles of a node in the layout.
+const (
+	nodeRoleStorage    = "storage"
+	nodeRoleGateway    = "gateway"
+	nodeRoleUnassigned = "unassigned"
+)
+
+// nodePartitionAttrTypes are the attribute types of a partition object.
+var nodePartitionAttrTypes = map[string]attr.Type{
+	"available": types.Int64Type,
+	"total":     types.Int64Type,
+}
+
+// nodeAttrTypes are the attribute types of a node object.
+var nodeAttrTypes = map[string]attr.Type{
+	"id":                 types.StringType,
+	"hostname":           types.StringType,
+	"addr":               types.StringType,
+	"garage_version":     types.StringType,
+	"is_up":              types.BoolType,
+	"last_seen_secs_ago": types.Int64Type,
+	"draining":           types.BoolType,
+	"role":               types.StringType,
+	"zone":               types.StringType,
+	"capacity":           types.Int64Type,
+	"tags":               types.SetType{ElemType: types.StringType},
+	"data_partition":     types.ObjectType{AttrTypes: nodePartitionAttrTypes},
+	"metadata_partition": types.ObjectType{AttrTypes: nodePartitionAttrTypes},
+	"data_used_percent":  types.Float64Type,
+}
+
+// nodeComputedAttributes returns the schema of the attributes of a node
+// reported by the cluster status, except its ID.
+func nodeComputedAttributes() map[string]schema.Attribute {
+	partition := func(name string) schema.SingleNestedAttribute {
+		return schema.SingleNestedAttribute{
+			Computed:            true,
+			MarkdownDescription: fmt.Sprintf("The space of the %s partition of the node. Null when the node does not report it, e.g. when it is down.", name),
+			Attributes: map[string]schema.Attribute{
+				"available": schema.Int64Attribute{
+					Computed:            true,
+					MarkdownDescription: "The available space in bytes.",
+				},
+				"total": schema.Int64Attribute{
+					Computed:            true,
+					MarkdownDescription: "The total space in bytes.",
+				},
+			},
+		}
+	}
+
+	return map[string]schema.Attribute{
+		"hostname": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "The hostname of the node.",
+		},
+		"addr": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "The RPC address of the node.",
+		},
+		"garage_version": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "The Garage version of the node.",
+		},
+		"is_up": schema.BoolAttribute{
+			Computed:            true,
+			MarkdownDescription: "Whether the node is connected to the cluster.",
+		},
+		"last_seen_secs_ago": schema.Int64Attribute{
+			Computed:            true,
+			MarkdownDescription: "Seconds since the node was last seen. Null when it is up or was never seen.",
+		},
+		"draining": schema.BoolAttribute{
+			Computed:            true,
+			MarkdownDescription: "Whether the node was removed from the layout but still holds data.",
+		},
+		"role": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "The role of the node in the current layout: `storage`, `gateway` or `unassigned`.",
+		},
+		"zone": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "The zone of the node in the layout. Null when the node has no role.",
+		},
+		"capacity": schema.Int64Attribute{
+			Computed:            true,
+			MarkdownDescription: "The capacity of the node in the layout in bytes. Null for gateways and nodes without a role.",
+		},
+		"tags": schema.SetAttribute{
+			Computed:            true,
+			ElementType:         types.StringType,
+			MarkdownDescription: "The tags of the node in the layout. Null when the node has no role.",
+		},
+		"data_partition":     partition("data"),
+		"metadata_partition": partition("metadata"),
+		"data_used_percent": schema.Float64Attribute{
+			Computed:            true,
+			MarkdownDescription: "The used space of the data partition in percent, unrounded. Null when the data partition is not reported.",
+		},
+	}
+}
+
+func (d *NodeDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node"
+}
+
+func (d *NodeDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := nodeComputedAttributes()
+	attributes["id"] = schema.StringAttribute{
+		Required:            true,
+		MarkdownDescription: "The full hexadecimal ID of the node.",
+		Validators: []validator.String{
+			validators.NodeID(),
+		},
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Retrieves the status of a node of the Garage cluster, including the disk usage of its partitions.",
+		Attributes:          attributes,
+	}
+}
+
+func (d *NodeDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	var diags diag.Diagnostics
+	d.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
+}
+
+func (d *NodeDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data NodeDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Reading node data source", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+
+	status, err := d.client.GetClusterStatus(ctx)
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError("Unable to read cluster status", err))
+		return
+	}
+
+	var node *client.NodeStatus
+	for i := range status.Nodes {
+		if status.Nodes[i].ID == data.ID.ValueString() {
+			node = &status.Nodes[i]
+			break
+		}
+	}
+	if node == nil {
+		resp.Diagnostics.AddError(
+			"Node Not Found",
+			fmt.Sprintf("Node %s is not known to the cluster.", data.ID.ValueString()),
+		)
+		return
+	}
+
+	data = nodeModel(*node)
+
+	tflog.Trace(ctx, "Read node data source")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// nodeModel converts a node reported by the cluster status into its model.
+func nodeModel(node client.NodeStatus) NodeDataSourceModel {
+	model := NodeDataSourceModel{
+		ID:                types.StringValue(node.ID),
+		Hostname:          types.StringPointerValue(node.Hostname),
+		Addr:              types.StringPointerValue(node.Addr),
+		GarageVersion:     types.StringPointerValue(node.GarageVersion),
+		IsUp:              types.BoolValue(node.IsUp),
+		LastSeenSecsAgo:   types.Int64PointerValue(node.LastSeenSecsAgo),
+		Draining:          types.BoolValue(node.Draining),
+		Role:              types.StringValue(nodeRoleName(node.Role)),
+		Zone:              types.StringNull(),
+		Capacity:          types.Int64Null(),
+		Tags:              types.SetNull(types.StringType),
+		DataPartition:     nodePartitionValue(node.DataPartition),
+		MetadataPartition: nodePartitionValue(node.MetadataPartition),
+		DataUsedPercent:   types.Float64Null(),
+	}
+
+	if node.Role != nil {
+		tags := make([]attr.Value, 0, len(node.Role.Tags))
+		for _, tag := range node.Role.Tags {
+			tags = append(tags, types.StringValue(tag))
+		}
+
+		model.Zone = types.StringValue(node.Role.Zone)
+		model.Capacity = types.Int64PointerValue(node.Role.Capacity)
+		model.Tags = types.SetValueMust(types.StringType, tags)
+	}
+
+	if p := node.DataPartition; p != nil && p.Total > 0 {
+		model.DataUsedPercent = types.Float64Value(float64(p.Total-p.Available) / float64(p.Total) * 100)
+	}
+
+	return model
+}
+
+// nodeRoleName returns the kind of role of a node in the layout.
+func nodeRoleName(role *client.NodeRole) string {
+	switch {
+	case role == nil:
+		return nodeRoleUnassigned
+	case role.Capacity == nil:
+		return nodeRoleGateway
+	default:
+		return nodeRoleStorage
+	}
+}
+
+// nodePartitionValue converts the space of a partition into its object, null
+// when it is not reported.
+func nodePartitionValue(partition *client.FreeSpaceInfo) types.Object {
+	if partition == nil {
+		return types.ObjectNull(nodePartitionAttrTypes)
+	}
+
+	return types.ObjectValueMust(nodePartitionAttrTypes, map[string]attr.Value{
+		"available": types.Int64Value(partition.Available),
+		"total":     types.Int64Value(partition.Total),
+	})
+}
@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+// testClusterStatusServer serves a cluster status with a storage node, a
+// gateway and a node that is down.
+func testClusterStatusServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/GetClusterStatus" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"layoutVersion": 3,
+			"nodes": [
+				{"id": "` + strings.Repeat("c", 64) + `", "addr": null, "hostname": null, "isUp": false, "lastSeenSecsAgo": 120, "draining": true},
+				{"id": "` + strings.Repeat("a", 64) + `", "garageVersion": "v2.0.0", "addr": "10.0.0.1:3901", "hostname": "storage", "isUp": true, "lastSeenSecsAgo": null, "draining": false,
+				 "role": {"id": "` + strings.Repeat("a", 64) + `", "zone": "dc1", "capacity": 1000000000000, "tags": ["ssd"]},
+				 "dataPartition": {"available": 333333333333, "total": 1000000000000},
+				 "metadataPartition": {"available": 50000000000, "total": 100000000000}},
+				{"id": "` + strings.Repeat("b", 64) + `", "garageVersion": "v2.0.0", "addr": "10.0.0.2:3901", "hostname": "gateway", "isUp": true, "lastSeenSecsAgo": null, "draining": false,
+				 "role": {"id": "` + strings.Repeat("b", 64) + `", "zone": "dc1", "capacity": null, "tags": []}}
+			]
+		}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestNodeDataSource(t *testing.T) {
+	server := testClusterStatusServer(t)
+	d := &NodeDataSource{client: client.NewClient(server.URL, "test-token")}
+
+	t.Run("storage", func(t *testing.T) {
+		resp := testDataSourceRead(t, d, map[string]tftypes.Value{
+			"id": tftypes.NewValue(tftypes.String, strings.Repeat("a", 64)),
+		})
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+		}
+
+		var data NodeDataSourceModel
+		resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+		}
+
+		if data.Hostname.ValueString() != "storage" || data.Zone.ValueString() != "dc1" || data.Capacity.ValueInt64() != 1000000000000 {
+			t.Errorf("Unexpected node %+v", data)
+		}
+		partition := data.DataPartition.Attributes()
+		if partition["available"].String() != "333333333333" || partition["total"].String() != "1000000000000" {
+			t.Errorf("Unexpected data partition %s", data.DataPartition)
+		}
+		// The percentage is not rounded
+		if got := data.DataUsedPercent.ValueFloat64(); got != float64(666666666667)/float64(1000000000000)*100 {
+			t.Errorf("Unexpected data used percent %v", got)
+		}
+	})
+
+	t.Run("down", func(t *testing.T) {
+		resp := testDataSourceRead(t, d, map[string]tftypes.Value{
+			"id": tftypes.NewValue(tftypes.String, strings.Repeat("c", 64)),
+		})
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+		}
+
+		var data NodeDataSourceModel
+		resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+		}
+
+		if !data.DataPartition.IsNull() || !data.MetadataPartition.IsNull() || !data.DataUsedPercent.IsNull() {
+			t.Errorf("Expected null partitions, got %+v", data)
+		}
+		if !data.Zone.IsNull() || !data.Capacity.IsNull() || data.LastSeenSecsAgo.ValueInt64() != 120 {
+			t.Errorf("Unexpected node %+v", data)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		resp := testDataSourceRead(t, d, map[string]tftypes.Value{
+			"id": tftypes.NewValue(tftypes.String, strings.Repeat("d", 64)),
+		})
+		if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != "Node Not Found" {
+			t.Errorf("Expected a not found error, got %v", resp.Diagnostics)
+		}
+	})
+}
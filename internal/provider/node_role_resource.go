@@ -0,0 +1,465 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &NodeRoleResource{}
+var _ resource.ResourceWithValidateConfig = &NodeRoleResource{}
+var _ resource.ResourceWithImportState = &NodeRoleResource{}
+
+// nodeRoleMutex serializes the layout changes of the garage_node_role
+// resources of the provider. Staging and applying are separate calls, two
+// batches running concurrently would otherwise apply each other's changes
+// or both try to apply the same layout version.
+var nodeRoleMutex sync.Mutex
+
+// nodeRoleBatchDelay is how long the first change of a batch waits for the
+// changes of the other garage_node_role resources of the operation, which
+// Terraform creates, updates and destroys concurrently. It is a variable so
+// tests can shorten it.
+var nodeRoleBatchDelay = time.Second
+
+var (
+	// nodeRoleBatchesMu guards nodeRoleBatches.
+	nodeRoleBatchesMu sync.Mutex
+	// nodeRoleBatches holds the batch collecting changes for each admin
+	// client, which the resources of a provider configuration share.
+	nodeRoleBatches = map[*client.Client]*nodeRoleBatch{}
+)
+
+// nodeRoleBatch is the set of node role changes staged and applied together
+// as one layout version.
+type nodeRoleBatch struct {
+	changes []client.NodeRoleChange
+	// done is closed once the batch is applied, with its resulting version
+	// and diagnostics.
+	done    chan struct{}
+	version int64
+	diags   diag.Diagnostics
+}
+
+func NewNodeRoleResource() resource.Resource {
+	return &NodeRoleResource{}
+}
+
+// NodeRoleResource defines the resource implementation.
+type NodeRoleResource struct {
+	client *client.Client
+}
+
+// NodeRoleResourceModel describes the resource data model.
+type NodeRoleResourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	NodeID   types.String `tfsdk:"node_id"`
+	Zone     types.String `tfsdk:"zone"`
+	Capacity types.Int64  `tfsdk:"capacity"`
+	Gateway  types.Bool   `tfsdk:"gateway"`
+	Tags     types.Set    `tfsdk:"tags"`
+	Version  types.Int64  `tfsdk:"version"`
+}
+
+// node returns the role of the resource as a node of the layout.
+func (m NodeRoleResourceModel) node() ClusterLayoutNodeModel {
+	return ClusterLayoutNodeModel{
+		ID:       m.NodeID,
+		Zone:     m.Zone,
+		Capacity: m.Capacity,
+		Gateway:  m.Gateway,
+		Tags:     m.Tags,
+	}
+}
+
+func (r *NodeRoleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_role"
+}
+
+func (r *NodeRoleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages the role of a single node in the Garage cluster layout, for setups where nodes are added by separate configurations. " +
+			"The changes of the resources of a configuration made in the same operation are staged together and applied as a single new layout version; " +
+			"resources that depend on each other apply one after the other. " +
+			"The resource refuses to run while changes staged by someone else are pending. " +
+			"Destroying the resource removes the node from the layout, moving its data to the remaining nodes. " +
+			"Do not manage the same cluster with `garage_cluster_layout`, which removes the nodes it does not list.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The identifier of the resource, the ID of the node.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"node_id": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The full ID of the node, as printed by `garage node id`.",
+				Validators: []validator.String{
+					validators.NodeID(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"zone": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The zone of the node. Garage spreads the copies of the data across zones.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"capacity": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "The storage capacity of the node in bytes. Exactly one of `capacity` or `gateway` must be set.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"gateway": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Whether the node is a gateway, which serves requests without storing data. Exactly one of `capacity` or `gateway = true` must be set. Defaults to `false`.",
+				Default:             booldefault.StaticBool(false),
+			},
+			"tags": schema.SetAttribute{
+				Optional:            true,
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Free-form tags of the node.",
+				Default:             setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+			},
+			"version": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The version of the layout, as of the last change or refresh of the resource.",
+			},
+		},
+	}
+}
+
+func (r *NodeRoleResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	var diags diag.Diagnostics
+	r.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *NodeRoleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data NodeRoleResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// gateway is null rather than defaulted in the configuration
+	node := data.node()
+	if node.Gateway.IsNull() {
+		node.Gateway = types.BoolValue(false)
+	}
+	if detail := nodeRoleError(node); detail != "" {
+		resp.Diagnostics.AddAttributeError(path.Root("gateway"), "Invalid Node Role", detail)
+	}
+}
+
+func (r *NodeRoleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data NodeRoleResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	role, diags := clusterLayoutNodeRole(ctx, data.node())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, diags := r.apply(ctx, client.NodeRoleChange{NodeRole: role})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = data.NodeID
+	data.Version = types.Int64Value(version)
+
+	tflog.Trace(ctx, "Created node role resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *NodeRoleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data NodeRoleResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	layout, err := r.client.GetClusterLayout(ctx)
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError("Unable to read cluster layout", err))
+		return
+	}
+
+	var role *client.NodeRole
+	for i := range layout.Roles {
+		if layout.Roles[i].ID == data.NodeID.ValueString() {
+			role = &layout.Roles[i]
+			break
+		}
+	}
+	if role == nil {
+		tflog.Debug(ctx, "Node has no role in the layout, removing from state", map[string]interface{}{
+			"node_id": data.NodeID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	tags := make([]attr.Value, 0, len(role.Tags))
+	for _, tag := range role.Tags {
+		tags = append(tags, types.StringValue(tag))
+	}
+
+	data.ID = data.NodeID
+	data.Zone = types.StringValue(role.Zone)
+	data.Capacity = types.Int64PointerValue(role.Capacity)
+	data.Gateway = types.BoolValue(role.Capacity == nil)
+	data.Tags = types.SetValueMust(types.StringType, tags)
+	data.Version = types.Int64Value(layout.Version)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *NodeRoleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data NodeRoleResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	role, diags := clusterLayoutNodeRole(ctx, data.node())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, diags := r.apply(ctx, client.NodeRoleChange{NodeRole: role})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = data.NodeID
+	data.Version = types.Int64Value(version)
+
+	tflog.Trace(ctx, "Updated node role resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *NodeRoleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data NodeRoleResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	change := client.NodeRoleChange{NodeRole: client.NodeRole{ID: data.NodeID.ValueString()}, Remove: true}
+	_, diags := r.apply(ctx, change)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *NodeRoleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("node_id"), req, resp)
+}
+
+// apply adds change to the batch of the changes made concurrently by the
+// other resources of the provider, and returns the layout version the batch
+// is applied as. The first change of a batch waits nodeRoleBatchDelay for
+// the others, then applies the batch for all of them.
+func (r *NodeRoleResource) apply(ctx context.Context, change client.NodeRoleChange) (int64, diag.Diagnostics) {
+	nodeRoleBatchesMu.Lock()
+	batch, joined := nodeRoleBatches[r.client]
+	if !joined {
+		batch = &nodeRoleBatch{done: make(chan struct{})}
+		nodeRoleBatches[r.client] = batch
+	}
+	batch.changes = append(batch.changes, change)
+	nodeRoleBatchesMu.Unlock()
+
+	if joined {
+		<-batch.done
+		return batch.version, batch.diags
+	}
+
+	select {
+	case <-time.After(nodeRoleBatchDelay):
+	case <-ctx.Done():
+	}
+
+	// Changes made from now on start the next batch
+	nodeRoleBatchesMu.Lock()
+	delete(nodeRoleBatches, r.client)
+	nodeRoleBatchesMu.Unlock()
+
+	batch.version, batch.diags = r.applyChanges(ctx, batch.changes)
+	close(batch.done)
+	return batch.version, batch.diags
+}
+
+// applyChanges stages changes and applies them as a new layout version,
+// unless the layout already matches them. It fails without touching the
+// layout when changes for other nodes or the layout parameters are staged.
+// It returns the resulting layout version.
+func (r *NodeRoleResource) applyChanges(ctx context.Context, changes []client.NodeRoleChange) (int64, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	nodeRoleMutex.Lock()
+	defer nodeRoleMutex.Unlock()
+
+	nodes := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		nodes[change.ID] = true
+	}
+	nodeIDs := sortedKeys(nodes)
+
+	layout, err := r.client.GetClusterLayout(ctx)
+	if err != nil {
+		diags.Append(diagFromClientError("Unable to read cluster layout", err))
+		return 0, diags
+	}
+
+	var unrelated []string
+	for _, staged := range layout.StagedRoleChanges {
+		if !nodes[staged.ID] {
+			unrelated = append(unrelated, staged.ID)
+		}
+	}
+	if len(unrelated) > 0 || layout.StagedParameters != nil {
+		var targets []string
+		if len(unrelated) > 0 {
+			sort.Strings(unrelated)
+			targets = append(targets, "nodes "+strings.Join(unrelated, ", "))
+		}
+		if layout.StagedParameters != nil {
+			targets = append(targets, "the layout parameters")
+		}
+		diags.AddError(
+			"Unrelated Staged Layout Changes",
+			fmt.Sprintf("The cluster layout has staged changes that were not made by this resource, for %s. "+
+				"Apply them with `garage layout apply` or discard them with `garage layout revert` before running Terraform again.",
+				strings.Join(targets, " and ")),
+		)
+		return 0, diags
+	}
+
+	// Start over from the applied layout rather than amending a failed run
+	if len(layout.StagedRoleChanges) > 0 {
+		tflog.Debug(ctx, "Reverting the layout changes previously staged for the nodes", map[string]interface{}{
+			"node_ids": nodeIDs,
+		})
+		layout, err = r.client.RevertClusterLayout(ctx)
+		if err != nil {
+			diags.Append(diagFromClientError("Unable to revert previously staged layout changes", err))
+			return 0, diags
+		}
+	}
+
+	desired := make(map[string]client.NodeRole, len(layout.Roles))
+	for _, role := range layout.Roles {
+		desired[role.ID] = role
+	}
+	for _, change := range changes {
+		if change.Remove {
+			delete(desired, change.ID)
+		} else {
+			desired[change.ID] = change.NodeRole
+		}
+	}
+	staged := clusterLayoutRoleChanges(layout.Roles, desired)
+	if len(staged) == 0 {
+		return layout.Version, diags
+	}
+
+	tflog.Debug(ctx, "Staging node role changes", map[string]interface{}{
+		"node_ids": nodeIDs,
+		"changes":  len(staged),
+		"version":  layout.Version,
+	})
+
+	update := client.UpdateClusterLayoutRequest{Roles: staged}
+	if _, err := r.client.UpdateClusterLayout(ctx, update); err != nil {
+		diags.Append(diagFromClientError(fmt.Sprintf("Unable to stage the roles of nodes %s", strings.Join(nodeIDs, ", ")), err))
+		return 0, diags
+	}
+
+	applied, err := r.client.ApplyClusterLayout(ctx, layout.Version+1)
+	if err != nil {
+		// Only the changes of these resources are staged, reverting cannot
+		// lose anyone else's work
+		detail := clientErrorDetail(fmt.Sprintf("Unable to apply cluster layout version %d", layout.Version+1), err)
+		if _, revertErr := r.client.RevertClusterLayout(ctx); revertErr != nil {
+			detail += fmt.Sprintf("\n\nReverting the staged changes also failed, run `garage layout revert` before retrying: %s", revertErr)
+		} else {
+			detail += "\n\nThe staged changes were reverted."
+		}
+		diags.AddError("Failed to Apply Cluster Layout", detail)
+		return 0, diags
+	}
+
+	tflog.Info(ctx, "Applied cluster layout", map[string]interface{}{
+		"node_ids": nodeIDs,
+		"version":  applied.Version,
+	})
+
+	return applied.Version, diags
+}
@@ -0,0 +1,206 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+func TestAccNodeRoleResource_twoNodes(t *testing.T) {
+	nodeA := strings.Repeat("a", 64)
+	nodeB := strings.Repeat("b", 64)
+	server := newTestLayoutServer(t, client.ClusterLayout{Version: 1})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccNodeRoleResourceConfig(server.URL, nodeA, nodeB, "capacity = 1000\n  gateway = true"),
+				ExpectError: regexp.MustCompile(`is a gateway and cannot have a capacity`),
+			},
+			// Both nodes are applied in the same run, as a single version
+			{
+				Config: testAccNodeRoleResourceConfig(server.URL, nodeA, nodeB, "gateway = true"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("garage_node_role.a", "id", nodeA),
+					resource.TestCheckResourceAttr("garage_node_role.a", "capacity", "1000"),
+					resource.TestCheckResourceAttr("garage_node_role.b", "gateway", "true"),
+					func(*terraform.State) error {
+						server.mu.Lock()
+						defer server.mu.Unlock()
+						if server.layout.Version != 2 || len(server.layout.Roles) != 2 {
+							return fmt.Errorf("expected two roles applied as version 2, got %+v", server.layout)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "garage_node_role.a",
+				ImportState:       true,
+				ImportStateId:     nodeA,
+				ImportStateVerify: true,
+				// The version changes with every role applied after node A
+				ImportStateVerifyIgnore: []string{"version"},
+			},
+		},
+	})
+}
+
+func TestNodeRoleApply(t *testing.T) {
+	nodeA := strings.Repeat("a", 64)
+	nodeB := strings.Repeat("b", 64)
+
+	defer func(previous time.Duration) { nodeRoleBatchDelay = previous }(nodeRoleBatchDelay)
+	nodeRoleBatchDelay = 10 * time.Millisecond
+
+	t.Run("add and remove", func(t *testing.T) {
+		server := newTestLayoutServer(t, client.ClusterLayout{Version: 1, Roles: []client.NodeRole{testNodeRole(nodeA, "dc1", 100)}})
+		r := &NodeRoleResource{client: client.NewClient(server.URL, "test-token")}
+
+		version, diags := r.apply(context.Background(), client.NodeRoleChange{NodeRole: testNodeRole(nodeB, "dc2", 100)})
+		if diags.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", diags)
+		}
+		if version != 2 || len(server.layout.Roles) != 2 {
+			t.Errorf("Expected node B added in version 2, got %d and %v", version, server.layout.Roles)
+		}
+
+		version, diags = r.apply(context.Background(), client.NodeRoleChange{NodeRole: client.NodeRole{ID: nodeA}, Remove: true})
+		if diags.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", diags)
+		}
+		if version != 3 || testJSON(t, server.layout.Roles) != testJSON(t, []client.NodeRole{testNodeRole(nodeB, "dc2", 100)}) {
+			t.Errorf("Expected node A removed in version 3, got %d and %v", version, server.layout.Roles)
+		}
+
+		server.expectCalls(t, "GetClusterLayout", "UpdateClusterLayout", "ApplyClusterLayout",
+			"GetClusterLayout", "UpdateClusterLayout", "ApplyClusterLayout")
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		server := newTestLayoutServer(t, client.ClusterLayout{Version: 1, Roles: []client.NodeRole{testNodeRole(nodeA, "dc1", 100)}})
+		r := &NodeRoleResource{client: client.NewClient(server.URL, "test-token")}
+
+		version, diags := r.apply(context.Background(), client.NodeRoleChange{NodeRole: testNodeRole(nodeA, "dc1", 100)})
+		if diags.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", diags)
+		}
+		if version != 1 {
+			t.Errorf("Expected version 1, got %d", version)
+		}
+
+		// Removing a node without a role is a no-op too
+		if _, diags := r.apply(context.Background(), client.NodeRoleChange{NodeRole: client.NodeRole{ID: nodeB}, Remove: true}); diags.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", diags)
+		}
+
+		server.expectCalls(t, "GetClusterLayout", "GetClusterLayout")
+	})
+
+	t.Run("unrelated staged changes", func(t *testing.T) {
+		server := newTestLayoutServer(t, client.ClusterLayout{
+			Version:           1,
+			StagedRoleChanges: []client.NodeRoleChange{{NodeRole: testNodeRole(nodeB, "dc1", 100)}},
+		})
+		r := &NodeRoleResource{client: client.NewClient(server.URL, "test-token")}
+
+		_, diags := r.apply(context.Background(), client.NodeRoleChange{NodeRole: testNodeRole(nodeA, "dc1", 100)})
+		if !diags.HasError() || !strings.Contains(diags.Errors()[0].Detail(), "for nodes "+nodeB) {
+			t.Fatalf("Expected an error about the changes staged for %s, got %v", nodeB, diags)
+		}
+
+		server.expectCalls(t, "GetClusterLayout")
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		server := newTestLayoutServer(t, client.ClusterLayout{Version: 1, Roles: []client.NodeRole{testNodeRole(nodeA, "dc1", 100)}})
+		// The resources of a provider configuration share its admin client
+		c := client.NewClient(server.URL, "test-token")
+		nodeRoleBatchDelay = 200 * time.Millisecond
+
+		// The changes made in the same operation are applied as a single
+		// version
+		changes := []client.NodeRoleChange{
+			{NodeRole: client.NodeRole{ID: nodeA}, Remove: true},
+			{NodeRole: testNodeRole(nodeB, "dc2", 100)},
+		}
+		var wg sync.WaitGroup
+		versions := make(chan int64, len(changes))
+		errs := make(chan string, len(changes))
+		for _, change := range changes {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r := &NodeRoleResource{client: c}
+				version, diags := r.apply(context.Background(), change)
+				if diags.HasError() {
+					errs <- fmt.Sprint(diags)
+				}
+				versions <- version
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		close(versions)
+
+		for err := range errs {
+			t.Errorf("Unexpected diagnostics: %s", err)
+		}
+		for version := range versions {
+			if version != 2 {
+				t.Errorf("Expected every resource to get version 2, got %d", version)
+			}
+		}
+		if server.layout.Version != 2 || testJSON(t, server.layout.Roles) != testJSON(t, []client.NodeRole{testNodeRole(nodeB, "dc2", 100)}) {
+			t.Errorf("Expected both changes applied as version 2, got %+v", server.layout)
+		}
+		server.expectCalls(t, "GetClusterLayout", "UpdateClusterLayout", "ApplyClusterLayout")
+	})
+
+	t.Run("separate operations", func(t *testing.T) {
+		server := newTestLayoutServer(t, client.ClusterLayout{Version: 1})
+		c := client.NewClient(server.URL, "test-token")
+		nodeRoleBatchDelay = 10 * time.Millisecond
+
+		// A change made after a batch was applied starts a new one
+		for i, id := range []string{nodeA, nodeB} {
+			r := &NodeRoleResource{client: c}
+			version, diags := r.apply(context.Background(), client.NodeRoleChange{NodeRole: testNodeRole(id, "dc1", 100)})
+			if diags.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", diags)
+			}
+			if version != int64(i+2) {
+				t.Errorf("Expected version %d, got %d", i+2, version)
+			}
+		}
+	})
+}
+
+func testAccNodeRoleResourceConfig(adminEndpoint, nodeA, nodeB, roleB string) string {
+	return testAccMockProviderConfig(adminEndpoint) + fmt.Sprintf(`
+resource "garage_node_role" "a" {
+  node_id  = %[1]q
+  zone     = "dc1"
+  capacity = 1000
+}
+
+resource "garage_node_role" "b" {
+  node_id = %[2]q
+  zone    = "dc1"
+  %[3]s
+}
+`, nodeA, nodeB, roleB)
+}
@@ -0,0 +1,250 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &NodeStatisticsDataSource{}
+
+func NewNodeStatisticsDataSource() datasource.DataSource {
+	return &NodeStatisticsDataSource{}
+}
+
+// NodeStatisticsDataSource defines the data source implementation.
+type NodeStatisticsDataSource struct {
+	client *client.Client
+}
+
+// NodeStatisticsDataSourceModel describes the data source data model.
+type NodeStatisticsDataSourceModel struct {
+	NodeID            types.String `tfsdk:"node_id"`
+	Tables            types.List   `tfsdk:"tables"`
+	BlockCount        types.Int64  `tfsdk:"block_count"`
+	ResyncQueueLength types.Int64  `tfsdk:"resync_queue_length"`
+	ResyncErrors      types.Int64  `tfsdk:"resync_errors"`
+	Raw               types.String `tfsdk:"raw"`
+}
+
+// nodeStatisticsTableAttrTypes are the attribute types of a table object.
+var nodeStatisticsTableAttrTypes = map[string]attr.Type{
+	"name":         types.StringType,
+	"items":        types.Int64Type,
+	"merkle_items": types.Int64Type,
+	"merkle_todo":  types.Int64Type,
+	"gc_todo":      types.Int64Type,
+}
+
+func (d *NodeStatisticsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_statistics"
+}
+
+func (d *NodeStatisticsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	count := func(description string) schema.Int64Attribute {
+		return schema.Int64Attribute{
+			Computed:            true,
+			MarkdownDescription: description + " Null when the node does not report it.",
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Retrieves the statistics of a node of the Garage cluster: the sizes of its metadata tables and the state of its block manager, " +
+			"parsed from the text printed by `garage stats`. Garage does not document the format of this text, the values it cannot parse are null but kept in `raw`.",
+
+		Attributes: map[string]schema.Attribute{
+			"node_id": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The full hexadecimal ID of the node.",
+				Validators: []validator.String{
+					validators.NodeID(),
+				},
+			},
+			"tables": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "The metadata tables of the node, in the order reported.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The name of the table.",
+						},
+						"items":        count("The number of items stored in the table."),
+						"merkle_items": count("The number of items of the Merkle tree of the table."),
+						"merkle_todo":  count("The number of items waiting to be added to the Merkle tree."),
+						"gc_todo":      count("The number of items waiting for garbage collection."),
+					},
+				},
+			},
+			"block_count":         count("The number of data blocks referenced on the node."),
+			"resync_queue_length": count("The number of blocks waiting to be resynchronized."),
+			"resync_errors":       count("The number of blocks that failed to resynchronize."),
+			"raw": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The statistics as reported by the node.",
+			},
+		},
+	}
+}
+
+func (d *NodeStatisticsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	var diags diag.Diagnostics
+	d.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
+}
+
+func (d *NodeStatisticsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data NodeStatisticsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Reading node statistics data source", map[string]interface{}{
+		"node_id": data.NodeID.ValueString(),
+	})
+
+	stats, err := d.client.GetNodeStatistics(ctx, data.NodeID.ValueString())
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError(fmt.Sprintf("Unable to read statistics of node %s", data.NodeID.ValueString()), err))
+		return
+	}
+
+	parsed := parseNodeStatistics(stats.Freeform)
+
+	tables := make([]attr.Value, 0, len(parsed.tables))
+	for _, table := range parsed.tables {
+		tables = append(tables, types.ObjectValueMust(nodeStatisticsTableAttrTypes, map[string]attr.Value{
+			"name":         types.StringValue(table.name),
+			"items":        types.Int64PointerValue(table.counts[0]),
+			"merkle_items": types.Int64PointerValue(table.counts[1]),
+			"merkle_todo":  types.Int64PointerValue(table.counts[2]),
+			"gc_todo":      types.Int64PointerValue(table.counts[3]),
+		}))
+	}
+
+	data.Tables = types.ListValueMust(types.ObjectType{AttrTypes: nodeStatisticsTableAttrTypes}, tables)
+	data.BlockCount = types.Int64PointerValue(parsed.blockCount)
+	data.ResyncQueueLength = types.Int64PointerValue(parsed.resyncQueueLength)
+	data.ResyncErrors = types.Int64PointerValue(parsed.resyncErrors)
+	data.Raw = types.StringValue(stats.Freeform)
+
+	tflog.Trace(ctx, "Read node statistics data source")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// nodeStatistics holds the values parsed from the statistics of a node.
+type nodeStatistics struct {
+	tables            []nodeStatisticsTable
+	blockCount        *int64
+	resyncQueueLength *int64
+	resyncErrors      *int64
+}
+
+// nodeStatisticsTable holds the items, Merkle items, Merkle todo and GC todo
+// counts of a table.
+type nodeStatisticsTable struct {
+	name   string
+	counts [4]*int64
+}
+
+var (
+	nodeStatisticsBlockCountPattern   = regexp.MustCompile(`number of RC entries[^:]*:\s*(\S+)`)
+	nodeStatisticsResyncQueuePattern  = regexp.MustCompile(`resync queue length:\s*(\S+)`)
+	nodeStatisticsResyncErrorsPattern = regexp.MustCompile(`blocks with resync errors:\s*(\S+)`)
+)
+
+// parseNodeStatistics parses the statistics of a node, as printed by
+// `garage stats`:
+//
+//	Table stats:
+//	  Table      Items  MklItems  MklTodo  GcTodo
+//	  bucket_v2  3      4         0        0
+//
+//	Block manager stats:
+//	  number of RC entries (~= number of blocks): 1234
+//	  resync queue length: 0
+//	  blocks with resync errors: 0
+func parseNodeStatistics(freeform string) nodeStatistics {
+	var stats nodeStatistics
+
+	inTables := false
+	for _, line := range strings.Split(freeform, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case strings.TrimSpace(line) == "Table stats:":
+			inTables = true
+			continue
+		case !inTables:
+			continue
+		case len(fields) == 0:
+			inTables = false
+			continue
+		case fields[0] == "Table":
+			continue
+		}
+
+		table := nodeStatisticsTable{name: fields[0]}
+		for i := range table.counts {
+			if i+1 < len(fields) {
+				table.counts[i] = parseStatisticsCount(fields[i+1])
+			}
+		}
+		stats.tables = append(stats.tables, table)
+	}
+
+	find := func(pattern *regexp.Regexp) *int64 {
+		if match := pattern.FindStringSubmatch(freeform); match != nil {
+			return parseStatisticsCount(match[1])
+		}
+		return nil
+	}
+	stats.blockCount = find(nodeStatisticsBlockCountPattern)
+	stats.resyncQueueLength = find(nodeStatisticsResyncQueuePattern)
+	stats.resyncErrors = find(nodeStatisticsResyncErrorsPattern)
+
+	return stats
+}
+
+// parseStatisticsCount parses a count, possibly approximate such as "~12",
+// or returns nil for values that were not computed such as "NC".
+func parseStatisticsCount(s string) *int64 {
+	n, err := strconv.ParseInt(strings.TrimPrefix(s, "~"), 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
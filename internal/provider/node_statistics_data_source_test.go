@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+const testNodeStatistics = `Garage version: v2.0.0 [features: k2v, lmdb, metrics]
+Rust compiler version: 1.86.0
+
+Database engine: LMDB (using LMDB 0.9.31)
+
+Table stats:
+  Table              Items  MklItems  MklTodo  GcTodo
+  bucket_v2          3      4         0        0
+  key                2      3         0        0
+  object             1520   2032      12       NC
+  block_ref          4096   5120      0        7
+
+Block manager stats:
+  number of RC entries (~= number of blocks): 3989
+  resync queue length: 42
+  blocks with resync errors: 1
+
+If values are missing above (marked as NC), consider adding the --detailed flag (this will be slow).
+`
+
+func TestParseNodeStatistics(t *testing.T) {
+	stats := parseNodeStatistics(testNodeStatistics)
+
+	if len(stats.tables) != 4 {
+		t.Fatalf("Expected 4 tables, got %d", len(stats.tables))
+	}
+	object := stats.tables[2]
+	if object.name != "object" || *object.counts[0] != 1520 || *object.counts[1] != 2032 || *object.counts[2] != 12 {
+		t.Errorf("Unexpected object table %+v", object)
+	}
+	// Values that were not computed are null
+	if object.counts[3] != nil {
+		t.Errorf("Expected no GC todo count, got %d", *object.counts[3])
+	}
+	if *stats.blockCount != 3989 || *stats.resyncQueueLength != 42 || *stats.resyncErrors != 1 {
+		t.Errorf("Unexpected block manager stats %d, %d, %d", *stats.blockCount, *stats.resyncQueueLength, *stats.resyncErrors)
+	}
+
+	// Unknown formats leave the values null
+	stats = parseNodeStatistics("no statistics")
+	if len(stats.tables) != 0 || stats.blockCount != nil || stats.resyncQueueLength != nil {
+		t.Errorf("Expected nothing parsed, got %+v", stats)
+	}
+}
+
+func TestNodeStatisticsDataSource(t *testing.T) {
+	nodeID := strings.Repeat("a", 64)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/GetNodeStatistics" || r.URL.Query().Get("node") != nodeID {
+			t.Errorf("Unexpected request to %s", r.URL)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": map[string]client.NodeStatistics{nodeID: {Freeform: testNodeStatistics}},
+			"error":   map[string]string{},
+		})
+	}))
+	defer server.Close()
+
+	d := &NodeStatisticsDataSource{client: client.NewClient(server.URL, "test-token")}
+	resp := testDataSourceRead(t, d, map[string]tftypes.Value{
+		"node_id": tftypes.NewValue(tftypes.String, nodeID),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data NodeStatisticsDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if len(data.Tables.Elements()) != 4 || data.ResyncQueueLength.ValueInt64() != 42 || data.Raw.ValueString() != testNodeStatistics {
+		t.Errorf("Unexpected statistics %+v", data)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &NodesDataSource{}
+
+func NewNodesDataSource() datasource.DataSource {
+	return &NodesDataSource{}
+}
+
+// NodesDataSource defines the data source implementation.
+type NodesDataSource struct {
+	client *client.Client
+}
+
+// NodesDataSourceModel describes the data source data model.
+type NodesDataSourceModel struct {
+	Zone          types.String `tfsdk:"zone"`
+	Tags          types.Set    `tfsdk:"tags"`
+	Role          types.String `tfsdk:"role"`
+	Up            types.Bool   `tfsdk:"up"`
+	LayoutVersion types.Int64  `tfsdk:"layout_version"`
+	Nodes         types.List   `tfsdk:"nodes"`
+}
+
+func (d *NodesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_nodes"
+}
+
+func (d *NodesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := nodeComputedAttributes()
+	attributes["id"] = schema.StringAttribute{
+		Computed:            true,
+		MarkdownDescription: "The full ID of the node.",
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the nodes known to the Garage cluster with their status and layout role, including the disk usage of their partitions. " +
+			"The optional filters select the nodes matching all of them; a filter matching no node returns an empty list.",
+
+		Attributes: map[string]schema.Attribute{
+			"zone": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list the nodes of this zone in the layout.",
+			},
+			"tags": schema.SetAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Only list the nodes having all of these tags in the layout.",
+			},
+			"role": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list the nodes with this role in the layout: `storage`, `gateway` or `unassigned`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(nodeRoleStorage, nodeRoleGateway, nodeRoleUnassigned),
+				},
+			},
+			"up": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list the nodes that are connected to the cluster (`true`) or not (`false`).",
+			},
+			"layout_version": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The current version of the cluster layout.",
+			},
+			"nodes": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "The nodes known to the cluster matching the filters, sorted by ID.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: attributes,
+				},
+			},
+		},
+	}
+}
+
+func (d *NodesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	var diags diag.Diagnostics
+	d.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
+}
+
+func (d *NodesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data NodesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var filter nodesFilter
+	if !data.Zone.IsNull() {
+		filter.zone = data.Zone.ValueStringPointer()
+	}
+	if !data.Tags.IsNull() {
+		resp.Diagnostics.Append(data.Tags.ElementsAs(ctx, &filter.tags, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	filter.role = data.Role.ValueString()
+	if !data.Up.IsNull() {
+		filter.up = data.Up.ValueBoolPointer()
+	}
+
+	tflog.Debug(ctx, "Reading nodes data source", map[string]interface{}{
+		"zone": data.Zone.ValueString(),
+		"tags": filter.tags,
+		"role": filter.role,
+	})
+
+	status, err := d.client.GetClusterStatus(ctx)
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError("Unable to read cluster status", err))
+		return
+	}
+
+	sort.Slice(status.Nodes, func(i, j int) bool { return status.Nodes[i].ID < status.Nodes[j].ID })
+
+	// The cluster status cannot be filtered by the server
+	models := make([]NodeDataSourceModel, 0, len(status.Nodes))
+	for _, node := range status.Nodes {
+		if filter.matches(node) {
+			models = append(models, nodeModel(node))
+		}
+	}
+
+	nodes, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: nodeAttrTypes}, models)
+	resp.Diagnostics.Append(diags...)
+	data.Nodes = nodes
+	data.LayoutVersion = types.Int64Value(status.LayoutVersion)
+
+	tflog.Trace(ctx, "Read nodes data source")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// nodesFilter selects nodes by their status and layout role. Unset fields
+// match every node.
+type nodesFilter struct {
+	zone *string
+	tags []string
+	role string
+	up   *bool
+}
+
+// matches reports whether node matches every field of f.
+func (f nodesFilter) matches(node client.NodeStatus) bool {
+	if f.up != nil && node.IsUp != *f.up {
+		return false
+	}
+	if f.role != "" && nodeRoleName(node.Role) != f.role {
+		return false
+	}
+	if f.zone == nil && len(f.tags) == 0 {
+		return true
+	}
+
+	// Zones and tags are those of the role
+	if node.Role == nil || (f.zone != nil && node.Role.Zone != *f.zone) {
+		return false
+	}
+	for _, tag := range f.tags {
+		if !slices.Contains(node.Role.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
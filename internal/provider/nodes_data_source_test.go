@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+func TestNodesDataSource(t *testing.T) {
+	server := testClusterStatusServer(t)
+	d := &NodesDataSource{client: client.NewClient(server.URL, "test-token")}
+
+	resp := testDataSourceRead(t, d, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data NodesDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+	var nodes []NodeDataSourceModel
+	resp.Diagnostics.Append(data.Nodes.ElementsAs(context.Background(), &nodes, false)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if data.LayoutVersion.ValueInt64() != 3 {
+		t.Errorf("Expected layout version 3, got %s", data.LayoutVersion)
+	}
+	if len(nodes) != 3 {
+		t.Fatalf("Expected 3 nodes, got %d", len(nodes))
+	}
+	for i, prefix := range []string{"a", "b", "c"} {
+		if nodes[i].ID.ValueString() != strings.Repeat(prefix, 64) {
+			t.Errorf("Expected nodes sorted by ID, got %s at %d", nodes[i].ID, i)
+		}
+	}
+	// Gateways have a zone but neither capacity nor partitions
+	if gateway := nodes[1]; gateway.Zone.ValueString() != "dc1" || !gateway.Capacity.IsNull() || !gateway.DataPartition.IsNull() {
+		t.Errorf("Unexpected gateway %+v", gateway)
+	}
+	if nodes[0].MetadataPartition.IsNull() {
+		t.Errorf("Expected the metadata partition of %s", nodes[0].ID)
+	}
+	// The layout role is included inline
+	for i, role := range []string{"storage", "gateway", "unassigned"} {
+		if nodes[i].Role.ValueString() != role {
+			t.Errorf("Expected role %s for %s, got %s", role, nodes[i].ID, nodes[i].Role)
+		}
+	}
+	if tags := nodes[0].Tags.Elements(); len(tags) != 1 || tags[0].String() != `"ssd"` || !nodes[2].Tags.IsNull() {
+		t.Errorf("Unexpected tags %s and %s", nodes[0].Tags, nodes[2].Tags)
+	}
+}
+
+func TestNodesDataSource_filters(t *testing.T) {
+	server := testClusterStatusServer(t)
+	d := &NodesDataSource{client: client.NewClient(server.URL, "test-token")}
+	tags := func(values ...string) tftypes.Value {
+		elements := make([]tftypes.Value, 0, len(values))
+		for _, v := range values {
+			elements = append(elements, tftypes.NewValue(tftypes.String, v))
+		}
+		return tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, elements)
+	}
+
+	tests := []struct {
+		name     string
+		attrs    map[string]tftypes.Value
+		expected []string
+	}{
+		{"zone", map[string]tftypes.Value{"zone": tftypes.NewValue(tftypes.String, "dc1")}, []string{"a", "b"}},
+		{"tags", map[string]tftypes.Value{"tags": tags("ssd")}, []string{"a"}},
+		{"all tags must match", map[string]tftypes.Value{"tags": tags("ssd", "nvme")}, nil},
+		{"role", map[string]tftypes.Value{"role": tftypes.NewValue(tftypes.String, "gateway")}, []string{"b"}},
+		{"unassigned", map[string]tftypes.Value{"role": tftypes.NewValue(tftypes.String, "unassigned")}, []string{"c"}},
+		{"down", map[string]tftypes.Value{"up": tftypes.NewValue(tftypes.Bool, false)}, []string{"c"}},
+		{"combined", map[string]tftypes.Value{
+			"zone": tftypes.NewValue(tftypes.String, "dc1"),
+			"role": tftypes.NewValue(tftypes.String, "storage"),
+			"up":   tftypes.NewValue(tftypes.Bool, true),
+		}, []string{"a"}},
+		{"no match", map[string]tftypes.Value{"zone": tftypes.NewValue(tftypes.String, "dc2")}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := testDataSourceRead(t, d, tt.attrs)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var data NodesDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+			var nodes []NodeDataSourceModel
+			resp.Diagnostics.Append(data.Nodes.ElementsAs(context.Background(), &nodes, false)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			// Matching nothing is an empty list, not an error
+			if data.Nodes.IsNull() || len(nodes) != len(tt.expected) {
+				t.Fatalf("Expected nodes %v, got %s", tt.expected, data.Nodes)
+			}
+			for i, prefix := range tt.expected {
+				if nodes[i].ID.ValueString() != strings.Repeat(prefix, 64) {
+					t.Errorf("Expected node %s at %d, got %s", prefix, i, nodes[i].ID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &ObjectIDFunction{}
+
+func NewObjectIDFunction() function.Function {
+	return &ObjectIDFunction{}
+}
+
+// ObjectIDFunction defines the function implementation.
+type ObjectIDFunction struct{}
+
+func (f *ObjectIDFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "object_id"
+}
+
+func (f *ObjectIDFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds the ID of a garage_object from its bucket and key",
+		MarkdownDescription: "Builds the ID of a `garage_object`, `bucket/key`, as used to import it. " +
+			"The result is parsed back by `parse_object_id` and by the import of `garage_object`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "bucket",
+				MarkdownDescription: "The name of the bucket. It cannot be empty nor contain a slash.",
+			},
+			function.StringParameter{
+				Name:                "key",
+				MarkdownDescription: "The key of the object, which may contain slashes.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ObjectIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var bucket, key string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &bucket, &key))
+	if resp.Error != nil {
+		return
+	}
+
+	// A slash in the bucket would move the split of the ID
+	if bucket == "" || strings.Contains(bucket, "/") {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid bucket %q: expected a non-empty bucket name without slash", bucket))
+		return
+	}
+	if err := validators.ValidateObjectKey(key); err != nil {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("invalid key %q: %s", key, err))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, objectID(bucket, key)))
+}
@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccObjectIDFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "id" {
+  value = provider::garage::object_id("assets", "css/site.css")
+}
+
+output "parsed" {
+  value = provider::garage::parse_object_id("assets/css/site.css")
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("id", knownvalue.StringExact("assets/css/site.css")),
+					statecheck.ExpectKnownOutputValue("parsed", knownvalue.ObjectExact(map[string]knownvalue.Check{
+						"bucket": knownvalue.StringExact("assets"),
+						"key":    knownvalue.StringExact("css/site.css"),
+					})),
+				},
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::garage::parse_object_id("assets")
+}
+`,
+				ExpectError: regexp.MustCompile(`invalid object ID "assets"`),
+			},
+		},
+	})
+}
+
+func TestObjectIDFunction(t *testing.T) {
+	tests := []struct {
+		bucket   string
+		key      string
+		expected string
+		argument int64
+	}{
+		{bucket: "assets", key: "site.css", expected: "assets/site.css"},
+		{bucket: "assets", key: "css/nested/site.css", expected: "assets/css/nested/site.css"},
+		{bucket: "", key: "site.css", argument: 0},
+		{bucket: "as/sets", key: "site.css", argument: 0},
+		{bucket: "assets", key: "", argument: 1},
+		{bucket: "assets", key: "/site.css", argument: 1},
+	}
+
+	for _, test := range tests {
+		resp := testFunctionRun(t, NewObjectIDFunction(), types.StringValue(test.bucket), types.StringValue(test.key))
+
+		if test.expected == "" {
+			if resp.Error == nil || resp.Error.FunctionArgument == nil || *resp.Error.FunctionArgument != test.argument {
+				t.Errorf("object_id(%q, %q): expected an error on argument %d, got %v", test.bucket, test.key, test.argument, resp.Error)
+			}
+			continue
+		}
+		if resp.Error != nil || !resp.Result.Value().Equal(types.StringValue(test.expected)) {
+			t.Errorf("object_id(%q, %q): expected %q, got %s and %v", test.bucket, test.key, test.expected, resp.Result.Value(), resp.Error)
+		}
+
+		// The ID is parsed back exactly as on import
+		bucket, key, err := parseObjectImportID(test.expected)
+		if err != nil || bucket != test.bucket || key != test.key {
+			t.Errorf("Expected %q to be imported as (%q, %q), got (%q, %q) and %v", test.expected, test.bucket, test.key, bucket, key, err)
+		}
+	}
+}
+
+// testFunctionRun runs f with args and returns its response.
+func testFunctionRun(t *testing.T, f function.Function, args ...attr.Value) *function.RunResponse {
+	t.Helper()
+
+	ctx := context.Background()
+	definition := &function.DefinitionResponse{}
+	f.Definition(ctx, function.DefinitionRequest{}, definition)
+	result, err := definition.Definition.Return.NewResultData(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	resp := &function.RunResponse{Result: result}
+	f.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData(args)}, resp)
+	if resp.Error != nil && !strings.Contains(resp.Error.Text, "invalid") {
+		t.Errorf("Expected errors to name the invalid value, got %v", resp.Error)
+	}
+
+	return resp
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &ParseDurationFunction{}
+
+func NewParseDurationFunction() function.Function {
+	return &ParseDurationFunction{}
+}
+
+// ParseDurationFunction defines the function implementation.
+type ParseDurationFunction struct{}
+
+func (f *ParseDurationFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_duration"
+}
+
+func (f *ParseDurationFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Converts a duration such as 90d or 1d12h into seconds",
+		MarkdownDescription: "Converts a duration into a number of seconds. The duration is a sequence of whole numbers followed by a unit, " +
+			"`d` (days of 24 hours), `h`, `m` or `s`, from the largest unit to the smallest and each unit at most once, " +
+			"e.g. `90d`, `6h` or `1d 12h`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "duration",
+				MarkdownDescription: "The duration to convert, e.g. `90d` or `1d12h30m`.",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *ParseDurationFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var duration string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &duration))
+	if resp.Error != nil {
+		return
+	}
+
+	seconds, err := parseDuration(duration)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, seconds))
+}
+
+// durationPattern matches a duration without spaces: one or more whole
+// numbers each followed by a unit.
+var durationPattern = regexp.MustCompile(`^(?:[0-9]+[dhms])+$`)
+
+// durationComponentPattern matches a number and its unit in a duration.
+var durationComponentPattern = regexp.MustCompile(`([0-9]+)([dhms])`)
+
+// durationUnits are the units of durations in seconds, from the largest.
+var durationUnits = []struct {
+	name    string
+	seconds int64
+}{
+	{"d", 24 * 60 * 60},
+	{"h", 60 * 60},
+	{"m", 60},
+	{"s", 1},
+}
+
+// parseDuration returns the number of seconds of duration, such as "90d" or
+// "1d12h". Spaces between components are ignored.
+func parseDuration(duration string) (int64, error) {
+	compact := strings.Join(strings.Fields(duration), "")
+	if !durationPattern.MatchString(compact) {
+		return 0, fmt.Errorf("invalid duration %q: expected whole numbers followed by a unit d, h, m or s, such as 90d or 1d12h", duration)
+	}
+
+	var seconds int64
+	next := 0
+	for _, match := range durationComponentPattern.FindAllStringSubmatch(compact, -1) {
+		unit := next
+		for unit < len(durationUnits) && durationUnits[unit].name != match[2] {
+			unit++
+		}
+		if unit == len(durationUnits) {
+			return 0, fmt.Errorf("invalid duration %q: unit %s is repeated or after a smaller unit, expected units from d to s", duration, match[2])
+		}
+		next = unit + 1
+
+		n, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || n > (math.MaxInt64-seconds)/durationUnits[unit].seconds {
+			return 0, fmt.Errorf("invalid duration %q: too large", duration)
+		}
+		seconds += n * durationUnits[unit].seconds
+	}
+
+	return seconds, nil
+}
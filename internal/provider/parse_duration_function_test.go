@@ -0,0 +1,116 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccParseDurationFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::garage::parse_duration("1d12h")
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.Int64Exact(129600)),
+				},
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::garage::parse_duration("3 months")
+}
+`,
+				ExpectError: regexp.MustCompile(`invalid duration "3 months"`),
+			},
+		},
+	})
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		duration string
+		expected int64
+	}{
+		{"0s", 0},
+		{"45s", 45},
+		{"30m", 1800},
+		{"6h", 21600},
+		{"90d", 7776000},
+		{"1d12h", 129600},
+		{"1d12h30m15s", 131415},
+		{"1d 12h", 129600},
+		{"  2h 30m  ", 9000},
+		{"1d30s", 86430},
+		{"36h", 129600},
+		{"0d0h0m1s", 1},
+		{"106751991167300d", 9223372036854720000},
+	}
+
+	for _, test := range tests {
+		got, err := parseDuration(test.duration)
+		if err != nil || got != test.expected {
+			t.Errorf("parseDuration(%q): expected %d, got %d and %v", test.duration, test.expected, got, err)
+		}
+	}
+}
+
+func TestParseDuration_invalid(t *testing.T) {
+	tests := []struct {
+		duration string
+		error    string
+	}{
+		{"", "expected whole numbers followed by a unit"},
+		{"90", "expected whole numbers followed by a unit"},
+		{"d", "expected whole numbers followed by a unit"},
+		{"-1d", "expected whole numbers followed by a unit"},
+		{"1.5d", "expected whole numbers followed by a unit"},
+		{"1w", "expected whole numbers followed by a unit"},
+		{"1D", "expected whole numbers followed by a unit"},
+		{"1ms", "expected whole numbers followed by a unit"},
+		{"3 months", "expected whole numbers followed by a unit"},
+		{"12h1d", "unit d is repeated or after a smaller unit"},
+		{"1h1h", "unit h is repeated or after a smaller unit"},
+		{"106751991167301d", "too large"},
+		{"106751991167300d16h", "too large"},
+		{"99999999999999999999s", "too large"},
+	}
+
+	for _, test := range tests {
+		_, err := parseDuration(test.duration)
+		if err == nil || !strings.Contains(err.Error(), test.error) || !strings.Contains(err.Error(), `"`+test.duration+`"`) {
+			t.Errorf("parseDuration(%q): expected an error containing %q and the duration, got %v", test.duration, test.error, err)
+		}
+	}
+}
+
+func TestParseDurationFunction_run(t *testing.T) {
+	f := NewParseDurationFunction()
+
+	resp := testFunctionRun(t, f, types.StringValue("90d"))
+	if resp.Error != nil || !resp.Result.Value().Equal(types.Int64Value(7776000)) {
+		t.Errorf("Expected 7776000, got %s and %v", resp.Result.Value(), resp.Error)
+	}
+
+	resp = testFunctionRun(t, f, types.StringValue("90 days"))
+	if resp.Error == nil || resp.Error.FunctionArgument == nil || *resp.Error.FunctionArgument != 0 {
+		t.Errorf("Expected an error on the duration, got %v", resp.Error)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &ParseObjectIDFunction{}
+
+func NewParseObjectIDFunction() function.Function {
+	return &ParseObjectIDFunction{}
+}
+
+// ParseObjectIDFunction defines the function implementation.
+type ParseObjectIDFunction struct{}
+
+// objectIDAttrTypes are the attribute types of the object returned by
+// parse_object_id.
+var objectIDAttrTypes = map[string]attr.Type{
+	"bucket": types.StringType,
+	"key":    types.StringType,
+}
+
+func (f *ParseObjectIDFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_object_id"
+}
+
+func (f *ParseObjectIDFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Splits the ID of a garage_object into its bucket and key",
+		MarkdownDescription: "Splits the ID of a `garage_object`, `bucket/key`, exactly as the import of `garage_object` does: " +
+			"the bucket is everything before the first slash and the key everything after it, slashes included. " +
+			"Neither can be empty and the key must be a valid object key.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "id",
+				MarkdownDescription: "The ID to split, e.g. `my-bucket/path/to/file.txt`.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: objectIDAttrTypes,
+		},
+	}
+}
+
+func (f *ParseObjectIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var id string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &id))
+	if resp.Error != nil {
+		return
+	}
+
+	bucket, key, err := parseObjectImportID(id)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid object ID %q: expected bucket/key: %s", id, err))
+		return
+	}
+
+	result := types.ObjectValueMust(objectIDAttrTypes, map[string]attr.Value{
+		"bucket": types.StringValue(bucket),
+		"key":    types.StringValue(key),
+	})
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseObjectIDFunction(t *testing.T) {
+	tests := []struct {
+		id     string
+		bucket string
+		key    string
+	}{
+		{id: "assets/site.css", bucket: "assets", key: "site.css"},
+		{id: "assets/css/nested/site.css", bucket: "assets", key: "css/nested/site.css"},
+		{id: "assets/dir/", bucket: "assets", key: "dir/"},
+		{id: "assets"},
+		{id: "assets/"},
+		{id: "/site.css"},
+		{id: "assets//site.css"},
+		{id: ""},
+	}
+
+	for _, test := range tests {
+		resp := testFunctionRun(t, NewParseObjectIDFunction(), types.StringValue(test.id))
+
+		// Same result as the import of garage_object
+		bucket, key, err := parseObjectImportID(test.id)
+		if (err != nil) != (resp.Error != nil) || bucket != test.bucket || key != test.key {
+			t.Fatalf("parse_object_id(%q) disagrees with the import: (%q, %q, %v) and %v", test.id, bucket, key, err, resp.Error)
+		}
+
+		if test.bucket == "" {
+			if resp.Error == nil || *resp.Error.FunctionArgument != 0 || !strings.Contains(resp.Error.Text, `"`+test.id+`"`) {
+				t.Errorf("parse_object_id(%q): expected an error naming the ID, got %v", test.id, resp.Error)
+			}
+			continue
+		}
+
+		expected := types.ObjectValueMust(objectIDAttrTypes, map[string]attr.Value{
+			"bucket": types.StringValue(test.bucket),
+			"key":    types.StringValue(test.key),
+		})
+		if resp.Error != nil || !resp.Result.Value().Equal(expected) {
+			t.Errorf("parse_object_id(%q): expected %s, got %s and %v", test.id, expected, resp.Result.Value(), resp.Error)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &ParseSizeFunction{}
+
+func NewParseSizeFunction() function.Function {
+	return &ParseSizeFunction{}
+}
+
+// ParseSizeFunction defines the function implementation.
+type ParseSizeFunction struct{}
+
+func (f *ParseSizeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_size"
+}
+
+func (f *ParseSizeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Converts a size such as 50GiB into bytes",
+		MarkdownDescription: "Converts a size into a number of bytes, e.g. for quotas. The size is a number, possibly fractional such as `1.5GiB`, " +
+			"followed by a decimal unit (`B`, `KB`, `MB`, `GB`, `TB`, `PB`) or a binary unit (`KiB`, `MiB`, `GiB`, `TiB`, `PiB`). " +
+			"Units are case-insensitive and a number without unit is a number of bytes. The size must amount to a whole number of bytes.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "size",
+				MarkdownDescription: "The size to convert, e.g. `50GiB` or `1.5 TB`.",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *ParseSizeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var size string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &size))
+	if resp.Error != nil {
+		return
+	}
+
+	bytes, err := parseSize(size)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, bytes))
+}
+
+// sizePattern matches a size: a decimal number and an optional unit.
+var sizePattern = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?|\.[0-9]+)\s*([a-zA-Z]*)$`)
+
+// sizeUnits are the multipliers of the units accepted by parseSize and used
+// by formatSize, by lowercase name.
+var sizeUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"kb":  1000,
+	"mb":  1000 * 1000,
+	"gb":  1000 * 1000 * 1000,
+	"tb":  1000 * 1000 * 1000 * 1000,
+	"pb":  1000 * 1000 * 1000 * 1000 * 1000,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+// binarySizeUnits are the names of the binary units, from the smallest.
+var binarySizeUnits = []string{"KiB", "MiB", "GiB", "TiB", "PiB"}
+
+// parseSize returns the number of bytes of size, such as "50GiB" or
+// "1.5 TB". The arithmetic is exact, so fractional sizes that are not a whole
+// number of bytes are errors rather than rounded.
+func parseSize(size string) (int64, error) {
+	match := sizePattern.FindStringSubmatch(strings.TrimSpace(size))
+	if match == nil {
+		return 0, fmt.Errorf("invalid size %q: expected a number followed by an optional unit, such as 50GiB or 1.5TB", size)
+	}
+
+	unit, ok := sizeUnits[strings.ToLower(match[2])]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q, expected one of B, KB, MB, GB, TB, PB, KiB, MiB, GiB, TiB or PiB", size, match[2])
+	}
+
+	value, ok := new(big.Rat).SetString(match[1])
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: invalid number %q", size, match[1])
+	}
+	value.Mul(value, new(big.Rat).SetInt64(unit))
+
+	if !value.IsInt() {
+		return 0, fmt.Errorf("invalid size %q: %s bytes is not a whole number of bytes", size, value.FloatString(3))
+	}
+	if !value.Num().IsInt64() {
+		return 0, fmt.Errorf("invalid size %q: too large", size)
+	}
+
+	return value.Num().Int64(), nil
+}
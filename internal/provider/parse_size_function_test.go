@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccParseSizeFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::garage::parse_size("1.5GiB")
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.Int64Exact(1610612736)),
+				},
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::garage::parse_size("50 gigs")
+}
+`,
+				ExpectError: regexp.MustCompile(`unknown unit "gigs"`),
+			},
+		},
+	})
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		size     string
+		expected int64
+	}{
+		{"0", 0},
+		{"1024", 1024},
+		{"1B", 1},
+		{"1b", 1},
+		{"1KB", 1000},
+		{"1kb", 1000},
+		{"1Kb", 1000},
+		{"1MB", 1000000},
+		{"1GB", 1000000000},
+		{"1TB", 1000000000000},
+		{"1PB", 1000000000000000},
+		{"1KiB", 1024},
+		{"1kib", 1024},
+		{"1KIB", 1024},
+		{"1MiB", 1048576},
+		{"1GiB", 1073741824},
+		{"50GiB", 53687091200},
+		{"1TiB", 1099511627776},
+		{"1PiB", 1125899906842624},
+		{"1.5GiB", 1610612736},
+		{"0.5KB", 500},
+		{".5KiB", 512},
+		{"2.25MB", 2250000},
+		{"0.001KB", 1},
+		{"1.000GB", 1000000000},
+		{"10 GB", 10000000000},
+		{"  10GB  ", 10000000000},
+		{"8191PiB", 9222246136947933184},
+	}
+
+	for _, test := range tests {
+		got, err := parseSize(test.size)
+		if err != nil {
+			t.Errorf("parseSize(%q): unexpected error %v", test.size, err)
+			continue
+		}
+		if got != test.expected {
+			t.Errorf("parseSize(%q): expected %d, got %d", test.size, test.expected, got)
+		}
+	}
+}
+
+func TestParseSize_invalid(t *testing.T) {
+	tests := []struct {
+		size  string
+		error string
+	}{
+		{"", "expected a number followed by an optional unit"},
+		{"GiB", "expected a number followed by an optional unit"},
+		{"-1GiB", "expected a number followed by an optional unit"},
+		{"1,5GiB", "expected a number followed by an optional unit"},
+		{"1.GiB", "expected a number followed by an optional unit"},
+		{"1e3", "expected a number followed by an optional unit"},
+		{"1 GiB extra", "expected a number followed by an optional unit"},
+		{"50 gigs", `unknown unit "gigs"`},
+		{"1KIBB", `unknown unit "KIBB"`},
+		{"1.5B", "is not a whole number of bytes"},
+		{"0.0001KB", "is not a whole number of bytes"},
+		{"8192PiB", "too large"},
+		{"99999999999999999999", "too large"},
+	}
+
+	for _, test := range tests {
+		_, err := parseSize(test.size)
+		if err == nil {
+			t.Errorf("parseSize(%q): expected an error", test.size)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.error) || !strings.Contains(err.Error(), strings.TrimSpace(test.size)) {
+			t.Errorf("parseSize(%q): expected an error containing %q and the size, got %v", test.size, test.error, err)
+		}
+	}
+}
+
+func TestParseSizeFunction_run(t *testing.T) {
+	ctx := context.Background()
+	f := NewParseSizeFunction()
+
+	run := func(size string) *function.RunResponse {
+		resp := &function.RunResponse{Result: function.NewResultData(types.Int64Unknown())}
+		f.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(size)})}, resp)
+		return resp
+	}
+
+	resp := run("50GiB")
+	if resp.Error != nil {
+		t.Fatalf("Unexpected error: %v", resp.Error)
+	}
+	if !resp.Result.Value().Equal(types.Int64Value(53687091200)) {
+		t.Errorf("Expected 53687091200, got %s", resp.Result.Value())
+	}
+
+	resp = run("50 gigs")
+	if resp.Error == nil || resp.Error.FunctionArgument == nil || *resp.Error.FunctionArgument != 0 || !strings.Contains(resp.Error.Text, `"50 gigs"`) {
+		t.Errorf("Expected an error on the size argument naming it, got %v", resp.Error)
+	}
+}
@@ -8,19 +8,28 @@ import (
 	"os"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
 )
 
 // Ensure GarageProvider satisfies various provider interfaces.
 var _ provider.Provider = &GarageProvider{}
 var _ provider.ProviderWithFunctions = &GarageProvider{}
 var _ provider.ProviderWithEphemeralResources = &GarageProvider{}
+var _ provider.ProviderWithActions = &GarageProvider{}
 
 // GarageProvider defines the provider implementation.
 type GarageProvider struct {
@@ -40,11 +49,40 @@ type GarageProviderModel struct {
 	//access keys are needed for s3
 	AccessKey types.String `tfsdk:"access_key"`
 	SecretKey types.String `tfsdk:"secret_key"`
+
+	// S3 compatibility settings for older Garage versions
+	SkipChecksumHeaders types.Bool `tfsdk:"skip_checksum_headers"`
+
+	// ContentTypeOverrides maps extensions such as ".glb" to the content type
+	// of objects, before the built-in tables
+	ContentTypeOverrides types.Map `tfsdk:"content_type_overrides"`
+
+	// MetricsToken protects the Prometheus metrics endpoint
+	MetricsToken types.String `tfsdk:"metrics_token"`
+
+	// S3Client is built once in Configure and shared by all object resources
+	// and data sources. It is nil when no S3 endpoint is configured.
+	S3Client *s3.Client `tfsdk:"-"`
+
+	// K2VClient is built in Configure from the K2V endpoint and the S3
+	// credentials. It is nil when no K2V endpoint is configured.
+	K2VClient *client.K2VClient `tfsdk:"-"`
+
+	// AdminClient is built once in Configure and shared by all admin
+	// resources and data sources, so that a rejected token is only sent once
+	// per endpoint.
+	AdminClient *client.Client `tfsdk:"-"`
+
+	// MetricsClient is built in Configure from the metrics endpoint, the
+	// admin endpoint unless set, and the metrics token.
+	MetricsClient *client.MetricsClient `tfsdk:"-"`
 }
 
 type EndpointsModel struct {
-	Admin types.String `tfsdk:"admin"`
-	S3    types.String `tfsdk:"s3"`
+	Admin   types.String `tfsdk:"admin"`
+	S3      types.String `tfsdk:"s3"`
+	K2V     types.String `tfsdk:"k2v"`
+	Metrics types.String `tfsdk:"metrics"`
 }
 
 func (p *GarageProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -64,7 +102,7 @@ func (p *GarageProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 			"token": schema.StringAttribute{
 				Optional:    true,
 				Sensitive:   true,
-				Description: "Admin API token for Garage cluster management",
+				Description: "Admin API token for Garage cluster management. Can also be set via GARAGE_TOKEN environment variable. Resources and data sources of the admin API warn when it is not set",
 			},
 			"access_key": schema.StringAttribute{
 				Optional:    true,
@@ -76,6 +114,27 @@ func (p *GarageProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 				Sensitive:   true,
 				Description: "S3 secret key for object operations. Can also be set via GARAGE_SECRET_KEY environment variable",
 			},
+			"skip_checksum_headers": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Do not send x-amz-checksum-* headers when uploading objects. Enable this for Garage versions without checksum support",
+			},
+			"content_type_overrides": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Content types of objects by extension, e.g. { \".glb\" = \"model/gltf-binary\" }, used before the built-in table when content_type is not set. Extensions start with a dot and are case-insensitive",
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.RegexMatches(
+						contentTypeExtensionRegexp,
+						"must be a file extension starting with a dot, e.g. .glb",
+					)),
+					mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"metrics_token": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Token of the Prometheus metrics endpoint, the metrics_token of garage.toml. Not needed when the metrics endpoint is public",
+			},
 			"endpoints": schema.SingleNestedAttribute{
 				Optional:    true,
 				Description: "Garage API endpoints configuration",
@@ -88,6 +147,14 @@ func (p *GarageProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 						Optional:    true,
 						Description: "S3 API endpoint (e.g., 'http://localhost:3900')",
 					},
+					"k2v": schema.StringAttribute{
+						Optional:    true,
+						Description: "K2V API endpoint (e.g., 'http://localhost:3904'), used with the S3 credentials",
+					},
+					"metrics": schema.StringAttribute{
+						Optional:    true,
+						Description: "Endpoint serving the Prometheus metrics on /metrics, used with metrics_token. Defaults to the admin endpoint",
+					},
 				},
 			},
 		},
@@ -103,7 +170,7 @@ func (p *GarageProvider) Configure(ctx context.Context, req provider.ConfigureRe
 	}
 
 	// Handle backwards compatibility
-	var adminEndpoint, s3Endpoint string
+	var adminEndpoint, s3Endpoint, k2vEndpoint, metricsEndpoint string
 
 	if config.Endpoints != nil {
 		// New endpoints block takes precedence
@@ -113,6 +180,12 @@ func (p *GarageProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		if !config.Endpoints.S3.IsNull() {
 			s3Endpoint = config.Endpoints.S3.ValueString()
 		}
+		if !config.Endpoints.K2V.IsNull() {
+			k2vEndpoint = config.Endpoints.K2V.ValueString()
+		}
+		if !config.Endpoints.Metrics.IsNull() {
+			metricsEndpoint = config.Endpoints.Metrics.ValueString()
+		}
 	}
 
 	// Fall back to deprecated 'endpoint' attribute if endpoints block not used
@@ -123,6 +196,18 @@ func (p *GarageProvider) Configure(ctx context.Context, req provider.ConfigureRe
 			// Simple heuristic: replace 3903 with 3900
 			s3Endpoint = replacePort(adminEndpoint, "3903", "3900")
 		}
+		if k2vEndpoint == "" {
+			k2vEndpoint = replacePort(adminEndpoint, "3903", "3904")
+		}
+	}
+
+	// Environment variable fallback for the admin token. A token only known
+	// after apply stays unknown.
+	token := config.Token
+	if !token.IsUnknown() && token.ValueString() == "" {
+		if v := os.Getenv("GARAGE_TOKEN"); v != "" {
+			token = types.StringValue(v)
+		}
 	}
 
 	// Environment variable fallback for S3 credentials
@@ -145,44 +230,117 @@ func (p *GarageProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
+	// Garage serves the metrics on the admin API port
+	if metricsEndpoint == "" {
+		metricsEndpoint = adminEndpoint
+	}
+
 	// Store in provider data with both endpoints
 	providerData := &GarageProviderModel{
 		Endpoint:  types.StringValue(adminEndpoint),
-		Token:     config.Token,
+		Token:     token,
 		AccessKey: types.StringValue(accessKey),
 		SecretKey: types.StringValue(secretKey),
 		Endpoints: &EndpointsModel{
-			Admin: types.StringValue(adminEndpoint),
-			S3:    types.StringValue(s3Endpoint),
+			Admin:   types.StringValue(adminEndpoint),
+			S3:      types.StringValue(s3Endpoint),
+			K2V:     types.StringValue(k2vEndpoint),
+			Metrics: types.StringValue(metricsEndpoint),
 		},
+		SkipChecksumHeaders:  types.BoolValue(config.SkipChecksumHeaders.ValueBool()),
+		ContentTypeOverrides: config.ContentTypeOverrides,
+		MetricsToken:         config.MetricsToken,
+	}
+
+	providerData.AdminClient = client.NewClient(adminEndpoint, token.ValueString())
+	providerData.MetricsClient = client.NewMetricsClient(metricsEndpoint, config.MetricsToken.ValueString())
+
+	// Object resources without an override fail on use when this is nil
+	if s3Endpoint != "" {
+		providerData.S3Client = newS3Client(s3Endpoint, accessKey, secretKey)
+	}
+	if k2vEndpoint != "" {
+		providerData.K2VClient = client.NewK2VClient(k2vEndpoint, accessKey, secretKey)
 	}
 
 	resp.DataSourceData = providerData
 	resp.ResourceData = providerData
+	resp.EphemeralResourceData = providerData
+	resp.ActionData = providerData
 }
 
 func (p *GarageProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewBucketResource,
 		NewBucketPermissionResource,
+		NewKeyGrantsResource,
 		NewKeyResource,
+		NewAdminTokenResource,
+		NewNodeConnectResource,
+		NewClusterLayoutResource,
+		NewNodeRoleResource,
+		NewRepairResource,
 		NewGarageObjectResource,
+		NewGarageObjectDirectoryResource,
+		NewGarageBucketUploadCleanupResource,
+		NewGarageBucketCorsResource,
+		NewGarageBucketLifecycleResource,
+		NewGarageBucketWebsiteResource,
+		NewK2VItemResource,
 	}
 }
 
 func (p *GarageProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
-	return []func() ephemeral.EphemeralResource{}
+	return []func() ephemeral.EphemeralResource{
+		NewGarageObjectEphemeralResource,
+		NewGaragePresignedURLEphemeralResource,
+		NewAdminTokenEphemeralResource,
+		NewKeyEphemeralResource,
+	}
+}
+
+func (p *GarageProvider) Actions(ctx context.Context) []func() action.Action {
+	return []func() action.Action{
+		NewGarageBucketEmptyAction,
+	}
 }
 
 func (p *GarageProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewBucketDataSource,
+		NewAdminTokenDataSource,
+		NewAdminTokensDataSource,
+		NewKeysDataSource,
+		NewNodeDataSource,
+		NewNodesDataSource,
+		NewNodeStatisticsDataSource,
+		NewVersionDataSource,
+		NewClusterHealthDataSource,
+		NewClusterMetricsDataSource,
+		NewK2VIndexDataSource,
 		NewGarageObjectDataSource,
+		NewGarageObjectHeadDataSource,
+		NewGarageBucketUsageDataSource,
+		NewGarageBucketWebsiteDataSource,
 	}
 }
 
 func (p *GarageProvider) Functions(ctx context.Context) []func() function.Function {
-	return []func() function.Function{}
+	return []func() function.Function{
+		NewParseSizeFunction,
+		NewFormatSizeFunction,
+		NewObjectIDFunction,
+		NewParseObjectIDFunction,
+		NewIsValidBucketAliasFunction,
+		NewValidateBucketAliasFunction,
+		NewWebsiteURLFunction,
+		NewFileETagFunction,
+		NewS3URIFunction,
+		NewContentTypeFunction,
+		NewKeyFromPathFunction,
+		NewParseDurationFunction,
+		NewExpiresAtFunction,
+	}
 }
 
 func New(version string) func() provider.Provider {
@@ -193,6 +351,77 @@ func New(version string) func() provider.Provider {
 	}
 }
 
+// adminEndpoint returns endpoints.admin, or the deprecated endpoint when it
+// is not set.
+func (m *GarageProviderModel) adminEndpoint() string {
+	if m.Endpoints != nil && !m.Endpoints.Admin.IsNull() {
+		return m.Endpoints.Admin.ValueString()
+	}
+	return m.Endpoint.ValueString()
+}
+
+// adminClient returns the shared admin API client, or a new client of the
+// admin endpoint when the provider data was not built by Configure.
+func (m *GarageProviderModel) adminClient() *client.Client {
+	if m.AdminClient != nil {
+		return m.AdminClient
+	}
+	return client.NewClient(m.adminEndpoint(), m.Token.ValueString())
+}
+
+// configureAdminClient returns the admin API client of the resources and data
+// sources of the admin API. It reports an error when no admin endpoint is
+// configured, and warns when no token is.
+func (m *GarageProviderModel) configureAdminClient() (*client.Client, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if m.adminEndpoint() == "" {
+		diags.AddError(
+			"Missing Admin Endpoint",
+			"Admin endpoint must be configured via 'endpoints.admin' or deprecated 'endpoint' attribute",
+		)
+		return nil, diags
+	}
+
+	diags.Append(missingAdminTokenDiagnostics(m)...)
+	return m.adminClient(), diags
+}
+
+// contentTypeOverrides returns the content_type_overrides of the provider by
+// lowercase extension, or nil when they are not set.
+func (m *GarageProviderModel) contentTypeOverrides() map[string]string {
+	if m.ContentTypeOverrides.IsNull() || m.ContentTypeOverrides.IsUnknown() {
+		return nil
+	}
+
+	overrides := make(map[string]string, len(m.ContentTypeOverrides.Elements()))
+	for ext, value := range m.ContentTypeOverrides.Elements() {
+		if contentType, ok := value.(types.String); ok && !contentType.IsNull() && !contentType.IsUnknown() {
+			overrides[strings.ToLower(ext)] = contentType.ValueString()
+		}
+	}
+	return overrides
+}
+
+// missingAdminTokenDiagnostics warns that the admin API will reject the
+// requests of a resource or data source when providerData has no token. It is
+// only called when configuring those of the admin API, so configurations
+// that only use the S3 or K2V APIs are not warned.
+func missingAdminTokenDiagnostics(providerData *GarageProviderModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if providerData.Token.IsUnknown() || providerData.Token.ValueString() != "" {
+		return diags
+	}
+
+	diags.AddWarning(
+		"Missing Admin Token",
+		"No admin API token is configured, so Garage will reject the admin API requests of this configuration with 401 Unauthorized. "+
+			"Set the token attribute of the provider, e.g. token = var.garage_admin_token with the variable set in a .tfvars file "+
+			"or the TF_VAR_garage_admin_token environment variable, or set the GARAGE_TOKEN environment variable. "+
+			"The token is the admin_token of the [admin] section of garage.toml, or one created with garage admin-token create.",
+	)
+	return diags
+}
+
 // Helper function to replace port in endpoint URL.
 func replacePort(endpoint, oldPort, newPort string) string {
 	// Simple string replacement - you may want more robust URL parsing
@@ -8,9 +8,16 @@ import (
 	"os"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
 )
 
@@ -20,7 +27,7 @@ var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServe
 }
 
 // testAccProtoV6ProviderFactoriesWithEcho includes the echo provider alongside the garage provider.
-var _ = map[string]func() (tfprotov6.ProviderServer, error){
+var testAccProtoV6ProviderFactoriesWithEcho = map[string]func() (tfprotov6.ProviderServer, error){
 	"garage": providerserver.NewProtocol6WithError(New("test")()),
 	"echo":   echoprovider.NewProviderServer(),
 }
@@ -84,3 +91,249 @@ func TestProviderSchema(t *testing.T) {
 
 	t.Log("SUCCESS: endpoints block exists!")
 }
+
+func TestProviderConfigure_sharedS3Client(t *testing.T) {
+	tests := []struct {
+		name       string
+		s3Endpoint string
+		wantClient bool
+	}{
+		{name: "with S3 endpoint", s3Endpoint: "http://localhost:3900", wantClient: true},
+		{name: "without S3 endpoint"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			p := New("test")()
+
+			var schemaResp provider.SchemaResponse
+			p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+			objType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+			if !ok {
+				t.Fatal("Unexpected provider schema type")
+			}
+			endpointsType, ok := objType.AttributeTypes["endpoints"].(tftypes.Object)
+			if !ok {
+				t.Fatal("Unexpected endpoints type")
+			}
+
+			values := map[string]tftypes.Value{}
+			for name, attrType := range objType.AttributeTypes {
+				values[name] = tftypes.NewValue(attrType, nil)
+			}
+			s3Endpoint := tftypes.NewValue(tftypes.String, nil)
+			if tt.s3Endpoint != "" {
+				s3Endpoint = tftypes.NewValue(tftypes.String, tt.s3Endpoint)
+			}
+			values["endpoints"] = tftypes.NewValue(endpointsType, map[string]tftypes.Value{
+				"admin":   tftypes.NewValue(tftypes.String, "http://localhost:3903"),
+				"s3":      s3Endpoint,
+				"k2v":     tftypes.NewValue(tftypes.String, nil),
+				"metrics": tftypes.NewValue(tftypes.String, nil),
+			})
+
+			resp := &provider.ConfigureResponse{}
+			p.Configure(ctx, provider.ConfigureRequest{
+				Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objType, values)},
+			}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			resourceData, ok := resp.ResourceData.(*GarageProviderModel)
+			if !ok {
+				t.Fatalf("Unexpected resource data %T", resp.ResourceData)
+			}
+			dataSourceData, ok := resp.DataSourceData.(*GarageProviderModel)
+			if !ok {
+				t.Fatalf("Unexpected data source data %T", resp.DataSourceData)
+			}
+
+			if (resourceData.S3Client != nil) != tt.wantClient {
+				t.Errorf("Expected S3 client %v, got %v", tt.wantClient, resourceData.S3Client)
+			}
+			if resourceData.S3Client != dataSourceData.S3Client {
+				t.Error("Expected resources and data sources to share the S3 client")
+			}
+			if resourceData.AdminClient == nil || resourceData.AdminClient != dataSourceData.AdminClient {
+				t.Error("Expected resources and data sources to share the admin client")
+			}
+		})
+	}
+}
+
+func TestProviderConfigure_tokenFromEnvironment(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     tftypes.Value
+		env       string
+		wantToken types.String
+	}{
+		{name: "from environment", token: tftypes.NewValue(tftypes.String, nil), env: "env-token", wantToken: types.StringValue("env-token")},
+		{name: "configured token wins", token: tftypes.NewValue(tftypes.String, "config-token"), env: "env-token", wantToken: types.StringValue("config-token")},
+		{name: "unknown token", token: tftypes.NewValue(tftypes.String, tftypes.UnknownValue), env: "env-token", wantToken: types.StringUnknown()},
+		{name: "neither", token: tftypes.NewValue(tftypes.String, nil), wantToken: types.StringNull()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GARAGE_TOKEN", tt.env)
+
+			ctx := context.Background()
+			p := New("test")()
+
+			var schemaResp provider.SchemaResponse
+			p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+			objType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+			if !ok {
+				t.Fatal("Unexpected provider schema type")
+			}
+
+			values := map[string]tftypes.Value{}
+			for name, attrType := range objType.AttributeTypes {
+				values[name] = tftypes.NewValue(attrType, nil)
+			}
+			values["endpoint"] = tftypes.NewValue(tftypes.String, "http://localhost:3903")
+			values["token"] = tt.token
+
+			resp := &provider.ConfigureResponse{}
+			p.Configure(ctx, provider.ConfigureRequest{
+				Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objType, values)},
+			}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			resourceData, ok := resp.ResourceData.(*GarageProviderModel)
+			if !ok {
+				t.Fatalf("Unexpected resource data %T", resp.ResourceData)
+			}
+			if !resourceData.Token.Equal(tt.wantToken) {
+				t.Errorf("Expected token %s, got %s", tt.wantToken, resourceData.Token)
+			}
+		})
+	}
+}
+
+func TestMissingAdminTokenWarning(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       types.String
+		configure   func(*GarageProviderModel) diag.Diagnostics
+		wantWarning bool
+	}{
+		{name: "admin resource without token", token: types.StringNull(), configure: configureBucketResource, wantWarning: true},
+		{name: "admin resource with empty token", token: types.StringValue(""), configure: configureBucketResource, wantWarning: true},
+		{name: "admin resource with token", token: types.StringValue("secret"), configure: configureBucketResource},
+		{name: "admin resource with unknown token", token: types.StringUnknown(), configure: configureBucketResource},
+		{name: "admin data source without token", token: types.StringNull(), configure: configureNodesDataSource, wantWarning: true},
+		{name: "object resource without token", token: types.StringNull(), configure: configureGarageObjectResource},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := tt.configure(&GarageProviderModel{
+				Token: tt.token,
+				Endpoints: &EndpointsModel{
+					Admin: types.StringValue("http://localhost:3903"),
+					S3:    types.StringValue("http://localhost:3900"),
+				},
+				S3Client: newS3Client("http://localhost:3900", "GKtest", "secret"),
+			})
+
+			if diags.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", diags)
+			}
+			if got := len(diags.Warnings()) == 1 && diags.Warnings()[0].Summary() == "Missing Admin Token"; got != tt.wantWarning {
+				t.Errorf("Expected the missing token warning %v, got %v", tt.wantWarning, diags)
+			}
+		})
+	}
+}
+
+func TestConfigureAdminClient(t *testing.T) {
+	tests := []struct {
+		name         string
+		providerData GarageProviderModel
+		wantEndpoint string
+	}{
+		{
+			name: "admin endpoint",
+			providerData: GarageProviderModel{
+				Endpoint:  types.StringValue("http://deprecated:3903"),
+				Endpoints: &EndpointsModel{Admin: types.StringValue("http://admin:3903")},
+			},
+			wantEndpoint: "http://admin:3903",
+		},
+		{
+			name:         "deprecated endpoint",
+			providerData: GarageProviderModel{Endpoint: types.StringValue("http://deprecated:3903")},
+			wantEndpoint: "http://deprecated:3903",
+		},
+		{
+			name: "deprecated endpoint without admin endpoint",
+			providerData: GarageProviderModel{
+				Endpoint:  types.StringValue("http://deprecated:3903"),
+				Endpoints: &EndpointsModel{Admin: types.StringNull(), S3: types.StringValue("http://s3:3900")},
+			},
+			wantEndpoint: "http://deprecated:3903",
+		},
+		{
+			name:         "no endpoint",
+			providerData: GarageProviderModel{Endpoint: types.StringNull()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.providerData.Token = types.StringValue("secret")
+			c, diags := tt.providerData.configureAdminClient()
+
+			if tt.wantEndpoint == "" {
+				if c != nil || !diags.HasError() || diags.Errors()[0].Summary() != "Missing Admin Endpoint" {
+					t.Errorf("Expected a Missing Admin Endpoint error, got %v", diags)
+				}
+				return
+			}
+			if diags.HasError() || c == nil {
+				t.Fatalf("Unexpected diagnostics: %v", diags)
+			}
+			if got := tt.providerData.adminEndpoint(); got != tt.wantEndpoint {
+				t.Errorf("Expected endpoint %s, got %s", tt.wantEndpoint, got)
+			}
+		})
+	}
+}
+
+func configureBucketResource(providerData *GarageProviderModel) diag.Diagnostics {
+	resp := &resource.ConfigureResponse{}
+	NewBucketResource().(resource.ResourceWithConfigure).Configure(context.Background(), resource.ConfigureRequest{ProviderData: providerData}, resp)
+	return resp.Diagnostics
+}
+
+func configureNodesDataSource(providerData *GarageProviderModel) diag.Diagnostics {
+	resp := &datasource.ConfigureResponse{}
+	NewNodesDataSource().(datasource.DataSourceWithConfigure).Configure(context.Background(), datasource.ConfigureRequest{ProviderData: providerData}, resp)
+	return resp.Diagnostics
+}
+
+func configureGarageObjectResource(providerData *GarageProviderModel) diag.Diagnostics {
+	resp := &resource.ConfigureResponse{}
+	NewGarageObjectResource().(resource.ResourceWithConfigure).Configure(context.Background(), resource.ConfigureRequest{ProviderData: providerData}, resp)
+	return resp.Diagnostics
+}
+
+func TestProviderContentTypeOverrides(t *testing.T) {
+	providerData := &GarageProviderModel{ContentTypeOverrides: types.MapNull(types.StringType)}
+	if overrides := providerData.contentTypeOverrides(); overrides != nil {
+		t.Errorf("Expected no overrides, got %v", overrides)
+	}
+
+	providerData.ContentTypeOverrides = types.MapValueMust(types.StringType, map[string]attr.Value{
+		".GLB": types.StringValue("model/gltf-binary"),
+	})
+	if overrides := providerData.contentTypeOverrides(); len(overrides) != 1 || overrides[".glb"] != "model/gltf-binary" {
+		t.Errorf("Expected overrides by lowercase extension, got %v", overrides)
+	}
+}
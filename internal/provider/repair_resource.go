@@ -0,0 +1,378 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &RepairResource{}
+
+// repairOperations maps the operations of the resource to the repair types of
+// the admin API.
+var repairOperations = map[string]string{
+	"blocks":    client.RepairBlocks,
+	"versions":  client.RepairVersions,
+	"multipart": client.RepairMultipart,
+	"scrub":     client.RepairScrub,
+}
+
+// repairWorkerNames maps the operations to a part of the name of the worker
+// running them, in lowercase.
+var repairWorkerNames = map[string]string{
+	"blocks":    "block repair",
+	"versions":  "version repair",
+	"multipart": "multipart",
+	"scrub":     "scrub",
+}
+
+// repairPollInterval is the delay between two checks of the workers. It is a
+// variable so tests can shorten it.
+var repairPollInterval = 5 * time.Second
+
+func NewRepairResource() resource.Resource {
+	return &RepairResource{}
+}
+
+// RepairResource defines the resource implementation.
+type RepairResource struct {
+	client *client.Client
+}
+
+// RepairResourceModel describes the resource data model.
+type RepairResourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	Operation types.String `tfsdk:"operation"`
+	Node      types.String `tfsdk:"node"`
+	Wait      types.Bool   `tfsdk:"wait"`
+	Timeout   types.String `tfsdk:"timeout"`
+	Triggers  types.Map    `tfsdk:"triggers"`
+	WorkerIDs types.Map    `tfsdk:"worker_ids"`
+	Duration  types.String `tfsdk:"duration"`
+}
+
+func (r *RepairResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_repair"
+}
+
+func (r *RepairResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "**Performs cluster work, it does not manage any state.** Creating this resource launches a repair or a scrub on the cluster, " +
+			"like `garage repair` does, e.g. after replacing a disk. The operation runs once, on creation: reading the resource never checks the cluster again, " +
+			"and destroying it does not stop a running operation. To run the operation again, replace the resource (`terraform apply -replace`) or change `triggers`.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The launch time of the operation, in RFC 3339 format.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"operation": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The operation to run: `blocks` checks that every referenced block is stored and resyncs the missing ones, `versions` and `multipart` repair the object versions and multipart uploads tables, `scrub` verifies the integrity of every stored block.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(sortedKeys(repairOperations)...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"node": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The full ID of the node to run the operation on. Runs on every node when not set.",
+				Validators: []validator.String{
+					validators.NodeID(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"wait": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Wait for the workers running the operation to complete. Defaults to `false`: the operation is only launched.",
+			},
+			"timeout": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("1h"),
+				MarkdownDescription: "Maximum time to wait for the operation to complete with `wait`, as a Go duration (e.g. `30m`). The operation keeps running on the cluster after a timeout. Defaults to `1h`.",
+				Validators: []validator.String{
+					validators.DurationBetween(time.Second, 7*24*time.Hour),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Arbitrary values that run the operation again when they change, e.g. the version of a `garage_cluster_layout`.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"worker_ids": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.Int64Type,
+				MarkdownDescription: "The ID of the worker running the operation, by node ID.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"duration": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "How long the operation took, as a Go duration. Null without `wait`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *RepairResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	var diags diag.Diagnostics
+	r.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *RepairResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data RepairResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.run(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "Created repair resource")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepairResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Nothing to refresh: the operation ran once, on creation
+	var data RepairResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepairResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Only wait and timeout can change in place, and they only apply on
+	// creation
+	var data RepairResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RepairResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Garage cannot cancel repairs, removing from state is enough
+	tflog.Debug(ctx, "Removing repair resource from state, running operations are not stopped")
+}
+
+// run launches the operation of data, identifies the worker running it on
+// every node and, with wait, polls the workers until they complete.
+func (r *RepairResource) run(ctx context.Context, data *RepairResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	operation := data.Operation.ValueString()
+	node := "*"
+	if !data.Node.IsNull() {
+		node = data.Node.ValueString()
+	}
+
+	timeout, err := time.ParseDuration(data.Timeout.ValueString())
+	if err != nil {
+		diags.AddError("Invalid Duration", fmt.Sprintf("Unable to parse timeout %q: %s", data.Timeout.ValueString(), err))
+		return diags
+	}
+
+	// Workers that already exist are told apart from the ones the operation
+	// starts, except for the scrub worker which always runs
+	before, err := r.client.ListWorkers(ctx, node)
+	if err != nil {
+		diags.Append(diagFromClientError("Unable to list workers", err))
+		return diags
+	}
+	existing := make(map[string]map[int64]bool, len(before))
+	for nodeID, workers := range before {
+		existing[nodeID] = make(map[int64]bool, len(workers))
+		for _, worker := range workers {
+			existing[nodeID][worker.ID] = true
+		}
+	}
+
+	tflog.Debug(ctx, "Launching repair operation", map[string]interface{}{
+		"operation": operation,
+		"node":      node,
+	})
+
+	start := time.Now()
+	if err := r.client.LaunchRepairOperation(ctx, node, repairOperations[operation]); err != nil {
+		diags.Append(diagFromClientError(fmt.Sprintf("Unable to launch %s repair", operation), err))
+		return diags
+	}
+
+	tracked := make(map[string]*repairWorker)
+	for {
+		workers, err := r.client.ListWorkers(ctx, node)
+		if err != nil {
+			diags.Append(diagFromClientError("Unable to list workers", err))
+			return diags
+		}
+
+		pending := trackRepairWorkers(tracked, workers, existing, repairWorkerNames[operation])
+		if !data.Wait.ValueBool() {
+			// Without wait, the workers only need to be identified
+			pending = nil
+			for nodeID := range workers {
+				if tracked[nodeID] == nil {
+					pending = append(pending, nodeID)
+				}
+			}
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		if time.Since(start) >= timeout {
+			diags.AddError(
+				"Repair Timed Out",
+				fmt.Sprintf("The %s repair did not complete within %s on nodes %s. The operation keeps running on the cluster.", operation, timeout, strings.Join(pending, ", ")),
+			)
+			return diags
+		}
+
+		tflog.Debug(ctx, "Waiting for repair workers", map[string]interface{}{
+			"operation": operation,
+			"pending":   pending,
+		})
+
+		select {
+		case <-ctx.Done():
+			diags.AddError("Repair Interrupted", fmt.Sprintf("Stopped waiting for the %s repair: %s", operation, ctx.Err()))
+			return diags
+		case <-time.After(repairPollInterval):
+		}
+	}
+
+	workerIDs := make(map[string]attr.Value, len(tracked))
+	for _, nodeID := range sortedKeys(tracked) {
+		worker := tracked[nodeID]
+		workerIDs[nodeID] = types.Int64Value(worker.ID)
+		if data.Wait.ValueBool() && worker.ConsecutiveErrors > 0 && worker.LastError != nil {
+			diags.AddWarning(
+				"Repair Completed With Errors",
+				fmt.Sprintf("The %s repair worker %d of node %s reported: %s", operation, worker.ID, nodeID, worker.LastError.Message),
+			)
+		}
+	}
+
+	data.ID = types.StringValue(start.UTC().Format(time.RFC3339))
+	data.WorkerIDs = types.MapValueMust(types.Int64Type, workerIDs)
+	data.Duration = types.StringNull()
+	if data.Wait.ValueBool() {
+		data.Duration = types.StringValue(time.Since(start).Round(time.Second).String())
+	}
+
+	return diags
+}
+
+// repairWorker is a worker running an operation, with whether it was seen
+// running.
+type repairWorker struct {
+	client.WorkerInfo
+	seenRunning bool
+}
+
+// trackRepairWorkers updates the worker running the operation on every node
+// and returns the nodes whose worker is not identified or has not completed
+// yet, sorted. A worker started by the operation is the newest one matching
+// name; a worker that existed before, like the scrub worker, must be seen
+// running before it is considered complete.
+func trackRepairWorkers(tracked map[string]*repairWorker, workers map[string][]client.WorkerInfo, existing map[string]map[int64]bool, name string) []string {
+	var pending []string
+	for _, nodeID := range sortedKeys(workers) {
+		var found *client.WorkerInfo
+		for i, worker := range workers[nodeID] {
+			if !strings.Contains(strings.ToLower(worker.Name), name) {
+				continue
+			}
+			if found == nil || (existing[nodeID][found.ID] && !existing[nodeID][worker.ID]) ||
+				(existing[nodeID][found.ID] == existing[nodeID][worker.ID] && worker.ID > found.ID) {
+				found = &workers[nodeID][i]
+			}
+		}
+		if found == nil {
+			pending = append(pending, nodeID)
+			continue
+		}
+
+		worker := tracked[nodeID]
+		if worker == nil || worker.ID != found.ID {
+			worker = &repairWorker{}
+			tracked[nodeID] = worker
+		}
+		worker.WorkerInfo = *found
+		worker.seenRunning = worker.seenRunning || found.State.Running()
+
+		if found.State.Running() || (existing[nodeID][found.ID] && !worker.seenRunning) {
+			pending = append(pending, nodeID)
+		}
+	}
+
+	return pending
+}
@@ -0,0 +1,173 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+func TestAccRepairResource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig() + `
+resource "garage_repair" "test" {
+  operation = "versions"
+  wait      = true
+  timeout   = "5m"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("garage_repair.test", "id"),
+					resource.TestCheckResourceAttrSet("garage_repair.test", "duration"),
+					resource.TestCheckResourceAttr("garage_repair.test", "worker_ids.%", "1"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestRepairRun(t *testing.T) {
+	nodeA := strings.Repeat("a", 64)
+
+	oldInterval := repairPollInterval
+	repairPollInterval = time.Millisecond
+	defer func() { repairPollInterval = oldInterval }()
+
+	scrubIdle := `{"id": 1, "name": "Block scrub worker", "state": "idle"}`
+	scrubBusy := `{"id": 1, "name": "Block scrub worker", "state": "busy"}`
+	oldRepair := `{"id": 4, "name": "version repair worker", "state": "done"}`
+
+	tests := []struct {
+		name      string
+		operation string
+		wait      bool
+		timeout   string
+		// workers are the successive worker lists of node A, the last one
+		// being repeated
+		workers   []string
+		wantID    int64
+		wantCalls int
+		wantErr   string
+	}{
+		{
+			name:      "wait",
+			operation: "versions",
+			wait:      true,
+			workers: []string{
+				scrubIdle + "," + oldRepair,
+				scrubIdle + "," + oldRepair,
+				scrubIdle + "," + oldRepair + `, {"id": 9, "name": "version repair worker", "state": "busy"}`,
+				scrubIdle + "," + oldRepair + `, {"id": 9, "name": "version repair worker", "state": "done"}`,
+			},
+			wantID:    9,
+			wantCalls: 4,
+		},
+		{
+			name:      "no wait",
+			operation: "versions",
+			workers: []string{
+				scrubIdle,
+				scrubIdle + `, {"id": 9, "name": "version repair worker", "state": "busy"}`,
+			},
+			wantID:    9,
+			wantCalls: 2,
+		},
+		{
+			name:      "scrub",
+			operation: "scrub",
+			wait:      true,
+			workers:   []string{scrubIdle, scrubIdle, scrubBusy, scrubIdle},
+			wantID:    1,
+			wantCalls: 4,
+		},
+		{
+			name:      "timeout",
+			operation: "versions",
+			wait:      true,
+			timeout:   "1s",
+			workers:   []string{oldRepair, `{"id": 9, "name": "version repair worker", "state": {"throttled": {"durationSecs": 1}}}`},
+			wantErr:   "did not complete within 1s on nodes " + nodeA,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			calls := 0
+			launched := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/v2/LaunchRepairOperation":
+					launched = true
+					_, _ = w.Write([]byte(`{"success": {"` + nodeA + `": null}, "error": {}}`))
+				case "/v2/ListWorkers":
+					workers := tt.workers[min(calls, len(tt.workers)-1)]
+					calls++
+					_, _ = w.Write([]byte(`{"success": {"` + nodeA + `": [` + workers + `]}, "error": {}}`))
+				default:
+					t.Errorf("Unexpected request to %s", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			timeout := tt.timeout
+			if timeout == "" {
+				timeout = "1m"
+			}
+			r := &RepairResource{client: client.NewClient(server.URL, "test-token")}
+			data := RepairResourceModel{
+				Operation: types.StringValue(tt.operation),
+				Node:      types.StringNull(),
+				Wait:      types.BoolValue(tt.wait),
+				Timeout:   types.StringValue(timeout),
+				Triggers:  types.MapNull(types.StringType),
+			}
+
+			diags := r.run(context.Background(), &data)
+			if !launched {
+				t.Errorf("Expected the operation to be launched")
+			}
+			if tt.wantErr != "" {
+				if diags.ErrorsCount() != 1 || !strings.Contains(diags.Errors()[0].Detail(), tt.wantErr) {
+					t.Errorf("Expected an error containing %q, got %v", tt.wantErr, diags)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", diags)
+			}
+
+			if calls != tt.wantCalls {
+				t.Errorf("Expected %d worker lists, got %d", tt.wantCalls, calls)
+			}
+			expected := types.MapValueMust(types.Int64Type, map[string]attr.Value{nodeA: types.Int64Value(tt.wantID)})
+			if !data.WorkerIDs.Equal(expected) {
+				t.Errorf("Expected worker IDs %s, got %s", expected, data.WorkerIDs)
+			}
+			if data.Duration.IsNull() == tt.wait {
+				t.Errorf("Unexpected duration %s", data.Duration)
+			}
+		})
+	}
+}
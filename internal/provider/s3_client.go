@@ -0,0 +1,316 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// S3OverrideModel describes per-resource S3 credentials and endpoint that
+// replace the provider-level ones.
+type S3OverrideModel struct {
+	AccessKey types.String `tfsdk:"access_key"`
+	SecretKey types.String `tfsdk:"secret_key"`
+	Endpoint  types.String `tfsdk:"endpoint"`
+}
+
+// objectAPI is the part of the S3 API used by garage_object. *s3.Client
+// implements it, unit tests use an in-memory fake instead.
+type objectAPI interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+	CopyObject(ctx context.Context, params *s3.CopyObjectInput, optFns ...func(*s3.Options)) (*s3.CopyObjectOutput, error)
+}
+
+var _ objectAPI = (*s3.Client)(nil)
+
+// s3Region is the region the S3 requests are signed for, the default
+// s3_region of Garage.
+const s3Region = "garage"
+
+// s3ClientCache holds the S3 clients built for overrides, keyed by a hash of
+// their endpoint and credentials, so they are not rebuilt on every call.
+var s3ClientCache = struct {
+	sync.Mutex
+	clients map[string]*s3.Client
+}{clients: map[string]*s3.Client{}}
+
+// newS3Client builds an S3 client for Garage.
+func newS3Client(endpoint, accessKey, secretKey string) *s3.Client {
+	return s3.NewFromConfig(aws.Config{
+		Region:      s3Region,
+		Credentials: credentials.NewStaticCredentialsProvider(accessKey, secretKey, ""),
+	}, func(o *s3.Options) {
+		o.BaseEndpoint = aws.String(endpoint)
+		o.UsePathStyle = true // Important for S3-compatible storage like Garage
+	})
+}
+
+// cachedS3Client returns the cached S3 client for the given endpoint and
+// credentials, building it on first use.
+func cachedS3Client(endpoint, accessKey, secretKey string) *s3.Client {
+	sum := sha256.Sum256([]byte(endpoint + "\x00" + accessKey + "\x00" + secretKey))
+	cacheKey := hex.EncodeToString(sum[:])
+
+	s3ClientCache.Lock()
+	defer s3ClientCache.Unlock()
+
+	if c, ok := s3ClientCache.clients[cacheKey]; ok {
+		return c
+	}
+
+	c := newS3Client(endpoint, accessKey, secretKey)
+	s3ClientCache.clients[cacheKey] = c
+	return c
+}
+
+// overrideS3Client returns the client and endpoint to use for a resource:
+// the override ones when set, else the defaults. The endpoint of an override
+// defaults to defaultEndpoint.
+func overrideS3Client(override *S3OverrideModel, defaultClient *s3.Client, defaultEndpoint string) (*s3.Client, string) {
+	if override == nil {
+		return defaultClient, defaultEndpoint
+	}
+
+	endpoint := defaultEndpoint
+	if !override.Endpoint.IsNull() && override.Endpoint.ValueString() != "" {
+		endpoint = override.Endpoint.ValueString()
+	}
+	if endpoint == "" {
+		return nil, ""
+	}
+
+	return cachedS3Client(endpoint, override.AccessKey.ValueString(), override.SecretKey.ValueString()), endpoint
+}
+
+// overrideAccessKey returns the access key ID used with override, for
+// diagnostics.
+func overrideAccessKey(override *S3OverrideModel, defaultAccessKey string) string {
+	if override == nil {
+		return defaultAccessKey
+	}
+	return override.AccessKey.ValueString()
+}
+
+// isAccessDenied reports whether err is an S3 authorization failure. HEAD
+// responses have no body, so a bare 403 also counts.
+func isAccessDenied(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "AccessDenied" {
+		return true
+	}
+
+	var respErr *smithyhttp.ResponseError
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusForbidden
+}
+
+// s3ErrorGuidance returns what to check for the S3 errors that are most
+// often a misconfiguration of the provider rather than of the bucket, or ""
+// for other errors.
+func s3ErrorGuidance(accessKeyID string, err error) string {
+	// The admin API answers in JSON, which the SDK fails to decode as XML
+	var deserializationErr *smithy.DeserializationError
+	if errors.As(err, &deserializationErr) {
+		return "The S3 endpoint answered with a response that is not from an S3 API. " +
+			"Check that endpoints.s3 is the S3 API of Garage (port 3900 by default), not its admin API (port 3903 by default)."
+	}
+
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return ""
+	}
+
+	switch apiErr.ErrorCode() {
+	case "SignatureDoesNotMatch":
+		return fmt.Sprintf("Garage rejected the signature of the request. Check that secret_key is the secret of access key %s, "+
+			"and that s3_region in garage.toml is %q, the region the provider signs requests for.", accessKeyID, s3Region)
+	case "AuthorizationHeaderMalformed":
+		return fmt.Sprintf("Garage rejected the authorization of the request. Check that s3_region in garage.toml is %q, "+
+			"the region the provider signs requests for.", s3Region)
+	case "InvalidAccessKeyId":
+		return fmt.Sprintf("Access key %s does not exist on this cluster. Check access_key, or the garage_key resource it comes from.", accessKeyID)
+	case "RequestTimeTooSkewed":
+		return "The clocks of this machine and of the Garage server differ by more than 15 minutes, so Garage rejects the signatures of the requests. " +
+			"Synchronize both clocks, e.g. with NTP."
+	}
+
+	return ""
+}
+
+// objectErrorDetail returns the diagnostic detail for a failed object
+// operation. Errors that are most often a misconfiguration of the provider
+// say what to check, see s3ErrorGuidance. Access denied errors are most
+// often a missing grant, so they name the access key in use and the
+// garage_bucket_permission flag needed on the bucket: write for uploads, read
+// otherwise. The raw error is always included.
+func objectErrorDetail(accessKeyID, bucket string, write bool, err error) string {
+	if guidance := s3ErrorGuidance(accessKeyID, err); guidance != "" {
+		return guidance + "\n\nOriginal error: " + err.Error()
+	}
+	if !isAccessDenied(err) {
+		return err.Error()
+	}
+
+	flag := "read"
+	if write {
+		flag = "write"
+	}
+
+	return fmt.Sprintf("Access key %s is not allowed to %s objects in bucket %s. "+
+		"Check that a garage_bucket_permission resource grants this key %s = true on the bucket, for example:\n\n"+
+		"resource \"garage_bucket_permission\" \"example\" {\n"+
+		"  bucket_id     = garage_bucket.example.id\n"+
+		"  access_key_id = %q\n"+
+		"  %s = true\n"+
+		"}\n\n"+
+		"Original error: %s",
+		accessKeyID, flag, bucket, flag, accessKeyID, flag, err)
+}
+
+const (
+	// maxDeleteObjectsKeys is the largest number of keys accepted by a
+	// DeleteObjects request.
+	maxDeleteObjectsKeys = 1000
+	// deleteObjectsAttempts bounds the attempts to delete a key that failed
+	// with a transient error.
+	deleteObjectsAttempts = 3
+)
+
+// deleteObjectsBatch deletes keys from bucket with DeleteObjects requests of
+// up to 1000 keys, and returns the errors by key. Keys that failed with a
+// transient error are retried, missing keys count as deleted. A failed
+// request fails all the keys of its batch.
+func deleteObjectsBatch(ctx context.Context, client *s3.Client, bucket string, keys []string) map[string]error {
+	errs := map[string]error{}
+
+	for start := 0; start < len(keys); start += maxDeleteObjectsKeys {
+		batch := keys[start:min(start+maxDeleteObjectsKeys, len(keys))]
+
+		for attempt := 1; len(batch) > 0; attempt++ {
+			objects := make([]s3types.ObjectIdentifier, 0, len(batch))
+			for _, key := range batch {
+				objects = append(objects, s3types.ObjectIdentifier{Key: aws.String(key)})
+			}
+
+			output, err := client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+				Bucket: aws.String(bucket),
+				Delete: &s3types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+			})
+			if err != nil {
+				for _, key := range batch {
+					errs[key] = err
+				}
+				break
+			}
+
+			var retry []string
+			for _, keyErr := range output.Errors {
+				key := aws.ToString(keyErr.Key)
+				switch code := aws.ToString(keyErr.Code); {
+				case code == "NoSuchKey":
+					delete(errs, key)
+				case isTransientDeleteError(code) && attempt < deleteObjectsAttempts:
+					retry = append(retry, key)
+				default:
+					errs[key] = fmt.Errorf("%s: %s", code, aws.ToString(keyErr.Message))
+				}
+			}
+			batch = retry
+		}
+	}
+
+	return errs
+}
+
+// isTransientDeleteError reports whether a per-key DeleteObjects error code
+// is worth retrying.
+func isTransientDeleteError(code string) bool {
+	switch code {
+	case "InternalError", "ServiceUnavailable", "SlowDown":
+		return true
+	}
+	return false
+}
+
+// purgeBucket deletes every object and aborts every multipart upload of
+// bucket so that it can be deleted, and returns how many of each it removed.
+// Garage has no versioning, so there are no delete markers to clean.
+func purgeBucket(ctx context.Context, client *s3.Client, bucket string) (int, int, error) {
+	return emptyBucket(ctx, client, bucket, "", nil)
+}
+
+// emptyBucket deletes the objects and aborts the multipart uploads of bucket
+// whose key starts with prefix, and returns how many of each it removed.
+// Objects are listed and deleted one page at a time; progress, when not nil,
+// is called after each page with the number of objects deleted so far. It
+// stops at the first failed page or when ctx is done.
+func emptyBucket(ctx context.Context, client *s3.Client, bucket, prefix string, progress func(objects int)) (int, int, error) {
+	objects := 0
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+	}
+	if prefix != "" {
+		input.Prefix = aws.String(prefix)
+	}
+	paginator := s3.NewListObjectsV2Paginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return objects, 0, fmt.Errorf("listing objects: %w", err)
+		}
+
+		keys := make([]string, 0, len(page.Contents))
+		for _, object := range page.Contents {
+			keys = append(keys, aws.ToString(object.Key))
+		}
+		errs := deleteObjectsBatch(ctx, client, bucket, keys)
+		objects += len(keys) - len(errs)
+		if len(errs) > 0 {
+			failed := sortedKeys(errs)
+			return objects, 0, fmt.Errorf("deleting %d objects, first %s: %w", len(failed), failed[0], errs[failed[0]])
+		}
+		if progress != nil {
+			progress(objects)
+		}
+	}
+
+	pending, err := listPendingUploads(ctx, client, bucket)
+	if err != nil {
+		return objects, 0, fmt.Errorf("listing multipart uploads: %w", err)
+	}
+	uploads := 0
+	var abortErrs []error
+	for _, upload := range pending {
+		if !strings.HasPrefix(upload.key, prefix) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return objects, uploads, errors.Join(append(abortErrs, err)...)
+		}
+		if err := abortUpload(ctx, client, bucket, upload); err != nil {
+			abortErrs = append(abortErrs, fmt.Errorf("aborting upload %s of %s: %w", upload.uploadID, upload.key, err))
+			continue
+		}
+		uploads++
+	}
+
+	return objects, uploads, errors.Join(abortErrs...)
+}
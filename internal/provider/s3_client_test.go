@@ -0,0 +1,576 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCachedS3Client(t *testing.T) {
+	a := cachedS3Client("http://localhost:3900", "GKaaa", "secret-a")
+	b := cachedS3Client("http://localhost:3900", "GKaaa", "secret-a")
+	c := cachedS3Client("http://localhost:3900", "GKbbb", "secret-b")
+	d := cachedS3Client("http://other:3900", "GKaaa", "secret-a")
+
+	if a != b {
+		t.Error("Expected the same client for identical credentials")
+	}
+	if a == c {
+		t.Error("Expected a different client for different credentials")
+	}
+	if a == d {
+		t.Error("Expected a different client for a different endpoint")
+	}
+}
+
+func TestOverrideS3Client(t *testing.T) {
+	defaultClient := newS3Client("http://localhost:3900", "GKdefault", "secret")
+
+	client, endpoint := overrideS3Client(nil, defaultClient, "http://localhost:3900")
+	if client != defaultClient || endpoint != "http://localhost:3900" {
+		t.Errorf("Expected the default client without override, got %p %s", client, endpoint)
+	}
+
+	override := &S3OverrideModel{
+		AccessKey: types.StringValue("GKother"),
+		SecretKey: types.StringValue("other-secret"),
+		Endpoint:  types.StringNull(),
+	}
+	client, endpoint = overrideS3Client(override, defaultClient, "http://localhost:3900")
+	if client == defaultClient || endpoint != "http://localhost:3900" {
+		t.Errorf("Expected a dedicated client on the default endpoint, got %p %s", client, endpoint)
+	}
+
+	override.Endpoint = types.StringValue("http://other:3900")
+	if _, endpoint = overrideS3Client(override, defaultClient, "http://localhost:3900"); endpoint != "http://other:3900" {
+		t.Errorf("Expected the override endpoint, got %s", endpoint)
+	}
+
+	override.Endpoint = types.StringNull()
+	if client, _ = overrideS3Client(override, nil, ""); client != nil {
+		t.Error("Expected no client without any endpoint")
+	}
+}
+
+func TestObjectErrorDetail(t *testing.T) {
+	forbidden := &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusForbidden}},
+		Err:      errors.New("forbidden"),
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		write    bool
+		wantHint bool
+		contains []string
+	}{
+		{
+			name:     "access denied on upload",
+			err:      &smithy.GenericAPIError{Code: "AccessDenied", Message: "Forbidden"},
+			write:    true,
+			wantHint: true,
+			contains: []string{"GK123", "my-bucket", "write = true", "AccessDenied"},
+		},
+		{
+			name:     "bare 403 on read",
+			err:      forbidden,
+			wantHint: true,
+			contains: []string{"GK123", "read = true", "forbidden"},
+		},
+		{
+			name:     "other error",
+			err:      &smithy.GenericAPIError{Code: "InternalError", Message: "boom"},
+			contains: []string{"InternalError"},
+		},
+		{
+			name: "admin endpoint answering 403",
+			err: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusForbidden}},
+				Err:      &smithy.DeserializationError{Err: errors.New("invalid character '{'")},
+			},
+			contains: []string{"port 3903", "Original error:", "invalid character"},
+		},
+		{
+			name:     "signature mismatch",
+			err:      &smithy.GenericAPIError{Code: "SignatureDoesNotMatch", Message: "Forbidden: Invalid signature"},
+			contains: []string{"secret_key", "GK123", "Original error:", "Invalid signature"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail := objectErrorDetail("GK123", "my-bucket", tt.write, tt.err)
+			for _, want := range tt.contains {
+				if !strings.Contains(detail, want) {
+					t.Errorf("Expected detail to contain %q, got: %s", want, detail)
+				}
+			}
+			if hint := strings.Contains(detail, "garage_bucket_permission"); hint != tt.wantHint {
+				t.Errorf("Expected permission hint %v, got: %s", tt.wantHint, detail)
+			}
+		})
+	}
+}
+
+func TestS3ErrorGuidance(t *testing.T) {
+	operationError := func(err error) error {
+		return &smithy.OperationError{ServiceID: "S3", OperationName: "GetObject", Err: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusForbidden}},
+			Err:      err,
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		contains []string
+	}{
+		{
+			name:     "signature mismatch",
+			err:      operationError(&smithy.GenericAPIError{Code: "SignatureDoesNotMatch", Message: "Forbidden: Invalid signature"}),
+			contains: []string{"secret_key", "access key GK123", `s3_region in garage.toml is "garage"`},
+		},
+		{
+			name:     "region mismatch",
+			err:      operationError(&smithy.GenericAPIError{Code: "AuthorizationHeaderMalformed", Message: "unexpected region"}),
+			contains: []string{`s3_region in garage.toml is "garage"`},
+		},
+		{
+			name:     "unknown access key",
+			err:      operationError(&smithy.GenericAPIError{Code: "InvalidAccessKeyId", Message: "Forbidden: No such key"}),
+			contains: []string{"Access key GK123 does not exist", "garage_key"},
+		},
+		{
+			name:     "clock skew",
+			err:      operationError(&smithy.GenericAPIError{Code: "RequestTimeTooSkewed", Message: "Request time too skewed"}),
+			contains: []string{"clocks", "NTP"},
+		},
+		{
+			name:     "admin endpoint",
+			err:      operationError(&smithy.DeserializationError{Err: errors.New("failed to decode response body, EOF")}),
+			contains: []string{"endpoints.s3", "port 3900", "port 3903"},
+		},
+		{
+			name: "access denied",
+			err:  operationError(&smithy.GenericAPIError{Code: "AccessDenied", Message: "Forbidden"}),
+		},
+		{
+			name: "not an API error",
+			err:  errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guidance := s3ErrorGuidance("GK123", tt.err)
+			if (guidance != "") != (len(tt.contains) > 0) {
+				t.Fatalf("Unexpected guidance %q", guidance)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(guidance, want) {
+					t.Errorf("Expected guidance to contain %q, got: %s", want, guidance)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteObjectsBatch(t *testing.T) {
+	var mu sync.Mutex
+	var batchSizes []int
+	attempts := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method != http.MethodPost || !r.URL.Query().Has("delete") {
+			t.Errorf("Unexpected %s request to %s", r.Method, r.URL)
+			return
+		}
+		var request struct {
+			Objects []struct{ Key string } `xml:"Object"`
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Unexpected delete request body: %s", err)
+			return
+		}
+		batchSizes = append(batchSizes, len(request.Objects))
+
+		// Quiet mode: only the failed keys are reported
+		var result strings.Builder
+		result.WriteString(`<DeleteResult>`)
+		for _, object := range request.Objects {
+			attempts[object.Key]++
+			code := ""
+			switch {
+			case strings.HasPrefix(object.Key, "denied-"):
+				code = "AccessDenied"
+			case strings.HasPrefix(object.Key, "missing-"):
+				code = "NoSuchKey"
+			case strings.HasPrefix(object.Key, "slow-") && attempts[object.Key] == 1:
+				code = "SlowDown"
+			case strings.HasPrefix(object.Key, "busy-"):
+				code = "InternalError"
+			}
+			if code != "" {
+				fmt.Fprintf(&result, `<Error><Key>%s</Key><Code>%s</Code><Message>%s failed</Message></Error>`, object.Key, code, object.Key)
+			}
+		}
+		result.WriteString(`</DeleteResult>`)
+		_, _ = w.Write([]byte(result.String()))
+	}))
+	defer server.Close()
+
+	keys := make([]string, 0, 2500)
+	for i := range 2496 {
+		keys = append(keys, fmt.Sprintf("object-%04d", i))
+	}
+	keys = append(keys, "denied-a", "missing-a", "slow-a", "busy-a")
+
+	errs := deleteObjectsBatch(context.Background(), testS3Client(server.URL), "bucket", keys)
+
+	// 1000, 1000 and 500 keys, then the retries of the transient failures
+	if fmt.Sprint(batchSizes) != "[1000 1000 500 2 1]" {
+		t.Errorf("Unexpected batch sizes %v", batchSizes)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 failed keys, got %v", errs)
+	}
+	if err := errs["denied-a"]; err == nil || err.Error() != "AccessDenied: denied-a failed" {
+		t.Errorf("Expected denied-a to fail with AccessDenied, got %v", err)
+	}
+	if err := errs["busy-a"]; err == nil || !strings.Contains(err.Error(), "InternalError") {
+		t.Errorf("Expected busy-a to fail after %d attempts, got %v", deleteObjectsAttempts, err)
+	}
+	if attempts["busy-a"] != deleteObjectsAttempts || attempts["slow-a"] != 2 {
+		t.Errorf("Unexpected attempts %d for busy-a and %d for slow-a", attempts["busy-a"], attempts["slow-a"])
+	}
+}
+
+func TestPurgeBucket(t *testing.T) {
+	var mu sync.Mutex
+	objects := map[string]bool{"a": true, "b": true, "c": true}
+	uploads := map[string]bool{"x": true, "y": true}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		query := r.URL.Query()
+		switch {
+		case r.Method == http.MethodGet && query.Has("uploads"):
+			var result strings.Builder
+			for _, key := range sortedKeys(uploads) {
+				fmt.Fprintf(&result, `<Upload><Key>%s</Key><UploadId>id-%s</UploadId></Upload>`, key, key)
+			}
+			_, _ = fmt.Fprintf(w, `<ListMultipartUploadsResult>%s</ListMultipartUploadsResult>`, result.String())
+		case r.Method == http.MethodGet:
+			// One object per page
+			var result strings.Builder
+			keys := sortedKeys(objects)
+			start := 0
+			for start < len(keys) && keys[start] <= query.Get("continuation-token") {
+				start++
+			}
+			if start < len(keys) {
+				fmt.Fprintf(&result, `<Contents><Key>%s</Key></Contents>`, keys[start])
+			}
+			if start+1 < len(keys) {
+				fmt.Fprintf(&result, `<IsTruncated>true</IsTruncated><NextContinuationToken>%s</NextContinuationToken>`, keys[start])
+			}
+			_, _ = fmt.Fprintf(w, `<ListBucketResult>%s</ListBucketResult>`, result.String())
+		case r.Method == http.MethodPost && query.Has("delete"):
+			var request struct {
+				Objects []struct{ Key string } `xml:"Object"`
+			}
+			if err := xml.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Errorf("Unexpected delete request body: %s", err)
+				return
+			}
+			for _, object := range request.Objects {
+				delete(objects, object.Key)
+			}
+			_, _ = w.Write([]byte(`<DeleteResult></DeleteResult>`))
+		case r.Method == http.MethodDelete && query.Has("uploadId"):
+			delete(uploads, strings.TrimPrefix(r.URL.Path, "/bucket/"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected %s request to %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	deletedObjects, abortedUploads, err := purgeBucket(context.Background(), testS3Client(server.URL), "bucket")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if deletedObjects != 3 || abortedUploads != 2 {
+		t.Errorf("Expected 3 objects and 2 uploads purged, got %d and %d", deletedObjects, abortedUploads)
+	}
+	if len(objects) != 0 || len(uploads) != 0 {
+		t.Errorf("Expected an empty bucket, got objects %v and uploads %v", objects, uploads)
+	}
+}
+
+// fakeObject is an object stored by fakeObjectAPI.
+type fakeObject struct {
+	body         []byte
+	lastModified time.Time
+
+	contentType        *string
+	contentEncoding    *string
+	contentDisposition *string
+	contentLanguage    *string
+	cacheControl       *string
+	websiteRedirect    *string
+	metadata           map[string]string
+
+	// checksumAlgorithm and checksums are the checksum headers of the
+	// upload, by algorithm.
+	checksumAlgorithm s3types.ChecksumAlgorithm
+	checksums         map[s3types.ChecksumAlgorithm]string
+}
+
+// fakeObjectAPI is an in-memory objectAPI for unit tests of garage_object.
+// Objects are single-part, with the ETag and checksums S3 computes for them.
+type fakeObjectAPI struct {
+	mu sync.Mutex
+
+	// buckets holds the objects of each existing bucket, by key.
+	buckets map[string]map[string]*fakeObject
+	// errs are returned instead of running the operation of the same name,
+	// e.g. "HeadObject".
+	errs map[string]error
+	// calls lists the operations run, in order.
+	calls []string
+}
+
+var _ objectAPI = (*fakeObjectAPI)(nil)
+
+// newFakeObjectAPI returns a fakeObjectAPI with the given empty buckets.
+func newFakeObjectAPI(buckets ...string) *fakeObjectAPI {
+	f := &fakeObjectAPI{buckets: map[string]map[string]*fakeObject{}, errs: map[string]error{}}
+	for _, bucket := range buckets {
+		f.buckets[bucket] = map[string]*fakeObject{}
+	}
+	return f
+}
+
+// put stores an object as if it was uploaded out of Terraform.
+func (f *fakeObjectAPI) put(bucket, key, body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.buckets[bucket][key] = &fakeObject{body: []byte(body), lastModified: time.Now().UTC().Truncate(time.Second), contentType: aws.String("text/plain")}
+}
+
+// object returns the object stored at bucket/key, or nil.
+func (f *fakeObjectAPI) object(bucket, key string) *fakeObject {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.buckets[bucket][key]
+}
+
+// called reports whether the operation op was run.
+func (f *fakeObjectAPI) called(op string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, call := range f.calls {
+		if call == op {
+			return true
+		}
+	}
+	return false
+}
+
+// start records the call of op and returns the error configured for it, or
+// NoSuchBucket when bucket does not exist.
+func (f *fakeObjectAPI) start(op string, bucket *string) error {
+	f.calls = append(f.calls, op)
+	if err := f.errs[op]; err != nil {
+		return err
+	}
+	if _, ok := f.buckets[aws.ToString(bucket)]; !ok {
+		return &smithy.GenericAPIError{Code: "NoSuchBucket", Message: "The specified bucket does not exist"}
+	}
+	return nil
+}
+
+func (f *fakeObjectAPI) PutObject(_ context.Context, params *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.start("PutObject", params.Bucket); err != nil {
+		return nil, err
+	}
+
+	objects := f.buckets[aws.ToString(params.Bucket)]
+	if aws.ToString(params.IfNoneMatch) == "*" && objects[aws.ToString(params.Key)] != nil {
+		return nil, &smithy.GenericAPIError{Code: "PreconditionFailed", Message: "At least one of the pre-conditions you specified did not hold"}
+	}
+
+	body, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	if params.ChecksumSHA256 != nil {
+		if sum := sha256.Sum256(body); *params.ChecksumSHA256 != base64.StdEncoding.EncodeToString(sum[:]) {
+			return nil, &smithy.GenericAPIError{Code: "BadDigest", Message: "The SHA256 you specified did not match the calculated checksum"}
+		}
+	}
+
+	objects[aws.ToString(params.Key)] = &fakeObject{
+		body:               body,
+		lastModified:       time.Now().UTC().Truncate(time.Second),
+		contentType:        params.ContentType,
+		contentEncoding:    params.ContentEncoding,
+		contentDisposition: params.ContentDisposition,
+		contentLanguage:    params.ContentLanguage,
+		cacheControl:       params.CacheControl,
+		websiteRedirect:    params.WebsiteRedirectLocation,
+		metadata:           params.Metadata,
+		checksumAlgorithm:  params.ChecksumAlgorithm,
+		checksums:          map[s3types.ChecksumAlgorithm]string{},
+	}
+	for algorithm, checksum := range map[s3types.ChecksumAlgorithm]*string{
+		s3types.ChecksumAlgorithmCrc32: params.ChecksumCRC32, s3types.ChecksumAlgorithmCrc32c: params.ChecksumCRC32C,
+		s3types.ChecksumAlgorithmSha1: params.ChecksumSHA1, s3types.ChecksumAlgorithmSha256: params.ChecksumSHA256,
+	} {
+		if checksum != nil {
+			objects[aws.ToString(params.Key)].checksums[algorithm] = *checksum
+		}
+	}
+
+	return &s3.PutObjectOutput{ETag: aws.String(contentETag(string(body)))}, nil
+}
+
+func (f *fakeObjectAPI) HeadObject(_ context.Context, params *s3.HeadObjectInput, _ ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.start("HeadObject", params.Bucket); err != nil {
+		return nil, err
+	}
+
+	object := f.buckets[aws.ToString(params.Bucket)][aws.ToString(params.Key)]
+	if object == nil {
+		return nil, &s3types.NotFound{}
+	}
+
+	output := &s3.HeadObjectOutput{
+		ETag:                    aws.String(contentETag(string(object.body))),
+		ContentLength:           aws.Int64(int64(len(object.body))),
+		LastModified:            aws.Time(object.lastModified),
+		ContentType:             object.contentType,
+		ContentEncoding:         object.contentEncoding,
+		ContentDisposition:      object.contentDisposition,
+		ContentLanguage:         object.contentLanguage,
+		CacheControl:            object.cacheControl,
+		WebsiteRedirectLocation: object.websiteRedirect,
+		Metadata:                object.metadata,
+	}
+	if params.ChecksumMode == s3types.ChecksumModeEnabled {
+		sha := sha256.Sum256(object.body)
+		crc := binary.BigEndian.AppendUint32(nil, crc32.ChecksumIEEE(object.body))
+		output.ChecksumSHA256 = aws.String(base64.StdEncoding.EncodeToString(sha[:]))
+		output.ChecksumCRC32 = aws.String(base64.StdEncoding.EncodeToString(crc))
+	}
+
+	return output, nil
+}
+
+func (f *fakeObjectAPI) GetObject(_ context.Context, params *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.start("GetObject", params.Bucket); err != nil {
+		return nil, err
+	}
+
+	object := f.buckets[aws.ToString(params.Bucket)][aws.ToString(params.Key)]
+	if object == nil {
+		return nil, &s3types.NoSuchKey{}
+	}
+
+	return &s3.GetObjectOutput{
+		Body:          io.NopCloser(bytes.NewReader(object.body)),
+		ContentLength: aws.Int64(int64(len(object.body))),
+		ETag:          aws.String(contentETag(string(object.body))),
+		ContentType:   object.contentType,
+	}, nil
+}
+
+// DeleteObject succeeds for missing keys, as S3 does.
+func (f *fakeObjectAPI) DeleteObject(_ context.Context, params *s3.DeleteObjectInput, _ ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.start("DeleteObject", params.Bucket); err != nil {
+		return nil, err
+	}
+
+	delete(f.buckets[aws.ToString(params.Bucket)], aws.ToString(params.Key))
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+// CopyObject only supports the copies of garage_object, within a bucket,
+// which replace the headers of the object or copy them along.
+func (f *fakeObjectAPI) CopyObject(_ context.Context, params *s3.CopyObjectInput, _ ...func(*s3.Options)) (*s3.CopyObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.start("CopyObject", params.Bucket); err != nil {
+		return nil, err
+	}
+
+	source, err := url.PathUnescape(aws.ToString(params.CopySource))
+	if err != nil {
+		return nil, err
+	}
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(source, "/"), "/")
+	object := f.buckets[bucket][key]
+	if object == nil {
+		return nil, &s3types.NoSuchKey{}
+	}
+
+	copied := &fakeObject{
+		body:               object.body,
+		lastModified:       time.Now().UTC().Truncate(time.Second),
+		contentType:        params.ContentType,
+		contentEncoding:    params.ContentEncoding,
+		contentDisposition: params.ContentDisposition,
+		contentLanguage:    params.ContentLanguage,
+		cacheControl:       params.CacheControl,
+		websiteRedirect:    params.WebsiteRedirectLocation,
+		metadata:           params.Metadata,
+	}
+	if params.MetadataDirective != s3types.MetadataDirectiveReplace {
+		headers := *object
+		headers.body, headers.lastModified = copied.body, copied.lastModified
+		copied = &headers
+	}
+	f.buckets[aws.ToString(params.Bucket)][aws.ToString(params.Key)] = copied
+
+	return &s3.CopyObjectOutput{CopyObjectResult: &s3types.CopyObjectResult{
+		ETag:         aws.String(contentETag(string(copied.body))),
+		LastModified: aws.Time(copied.lastModified),
+	}}, nil
+}
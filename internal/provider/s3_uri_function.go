@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &S3URIFunction{}
+
+func NewS3URIFunction() function.Function {
+	return &S3URIFunction{}
+}
+
+// S3URIFunction defines the function implementation.
+type S3URIFunction struct{}
+
+func (f *S3URIFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "s3_uri"
+}
+
+func (f *S3URIFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds the s3://bucket/key URI of an object",
+		MarkdownDescription: "Builds the `s3://bucket/key` URI of an object, as accepted by tools such as the AWS CLI or rclone. " +
+			"The bucket must be a valid global bucket alias or a bucket ID, and the key a valid object key. " +
+			"The key is not escaped: tools expect it as is, spaces and unicode included.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "bucket",
+				MarkdownDescription: "The global alias or the ID of the bucket.",
+			},
+			function.StringParameter{
+				Name:                "key",
+				MarkdownDescription: "The key of the object. It cannot be empty nor start with a slash.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *S3URIFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var bucket, key string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &bucket, &key))
+	if resp.Error != nil {
+		return
+	}
+
+	if !bucketIDPattern.MatchString(bucket) {
+		if err := validators.ValidateBucketAlias(bucket); err != nil {
+			resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid bucket %q: expected a bucket ID or a valid alias, %s", bucket, err))
+			return
+		}
+	}
+	if err := validators.ValidateObjectKey(key); err != nil {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("invalid key %q: %s", key, err))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, "s3://"+bucket+"/"+key))
+}
@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccS3URIFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::garage::s3_uri("backups", "db/2024-01-01.sql.gz")
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.StringExact("s3://backups/db/2024-01-01.sql.gz")),
+				},
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::garage::s3_uri("backups", "/db.sql.gz")
+}
+`,
+				ExpectError: regexp.MustCompile(`starts with /`),
+			},
+		},
+	})
+}
+
+func TestS3URIFunction_run(t *testing.T) {
+	f := NewS3URIFunction()
+	bucketID := strings.Repeat("0123456789abcdef", 4)
+
+	tests := []struct {
+		bucket, key string
+		expected    string
+	}{
+		{"backups", "db.sql.gz", "s3://backups/db.sql.gz"},
+		{"backups", "db/2024/01/db.sql.gz", "s3://backups/db/2024/01/db.sql.gz"},
+		{"backups", "my file.txt", "s3://backups/my file.txt"},
+		{"backups", "données/été.txt", "s3://backups/données/été.txt"},
+		{"backups", "dir/", "s3://backups/dir/"},
+		{bucketID, "db.sql.gz", "s3://" + bucketID + "/db.sql.gz"},
+	}
+
+	for _, test := range tests {
+		resp := testFunctionRun(t, f, types.StringValue(test.bucket), types.StringValue(test.key))
+		if resp.Error != nil || !resp.Result.Value().Equal(types.StringValue(test.expected)) {
+			t.Errorf("s3_uri(%q, %q): expected %q, got %s and %v", test.bucket, test.key, test.expected, resp.Result.Value(), resp.Error)
+		}
+	}
+
+	invalid := []struct {
+		bucket, key string
+		argument    int64
+		error       string
+	}{
+		{"", "db.sql.gz", 0, "alias is empty"},
+		{"Backups", "db.sql.gz", 0, `invalid bucket "Backups"`},
+		{"backups/db", "db.sql.gz", 0, `contains '/'`},
+		{strings.ToUpper(bucketID), "db.sql.gz", 0, "expected a bucket ID or a valid alias"},
+		{"backups", "", 1, "key is empty"},
+		{"backups", "/db.sql.gz", 1, "starts with /"},
+		{"backups", "//db.sql.gz", 1, "starts with /"},
+	}
+
+	for _, test := range invalid {
+		resp := testFunctionRun(t, f, types.StringValue(test.bucket), types.StringValue(test.key))
+		if resp.Error == nil || resp.Error.FunctionArgument == nil || *resp.Error.FunctionArgument != test.argument || !strings.Contains(resp.Error.Text, test.error) {
+			t.Errorf("s3_uri(%q, %q): expected an error on argument %d containing %q, got %v", test.bucket, test.key, test.argument, test.error, resp.Error)
+		}
+	}
+}
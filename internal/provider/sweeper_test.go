@@ -0,0 +1,224 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+// sweepPrefix is the prefix of the names of the buckets and keys created by
+// acceptance tests. Sweepers never touch anything else.
+const sweepPrefix = "test-"
+
+// TestMain runs the sweepers with go test -sweep=<any value>, e.g.
+// go test ./internal/provider -v -sweep=garage -sweep-run=garage_bucket, and
+// the tests otherwise.
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+func init() {
+	resource.AddTestSweepers("garage_object", &resource.Sweeper{
+		Name: "garage_object",
+		F:    sweepObjects,
+	})
+	resource.AddTestSweepers("garage_bucket", &resource.Sweeper{
+		Name:         "garage_bucket",
+		Dependencies: []string{"garage_object"},
+		F:            sweepBuckets,
+	})
+	resource.AddTestSweepers("garage_key", &resource.Sweeper{
+		Name: "garage_key",
+		F:    sweepKeys,
+	})
+}
+
+// sweepClient returns the admin client of the acceptance test cluster.
+func sweepClient() (*client.Client, error) {
+	endpoint := os.Getenv("GARAGE_ADMIN_ENDPOINT")
+	if endpoint == "" {
+		return nil, errors.New("GARAGE_ADMIN_ENDPOINT must be set to sweep")
+	}
+	return client.NewClient(endpoint, os.Getenv("GARAGE_TOKEN")), nil
+}
+
+// sweepableBucket reports whether bucket was created by acceptance tests:
+// it has global aliases and they all start with sweepPrefix. Buckets without
+// global alias cannot be attributed to tests and are kept.
+func sweepableBucket(bucket client.Bucket) bool {
+	if len(bucket.GlobalAliases) == 0 {
+		return false
+	}
+	for _, alias := range bucket.GlobalAliases {
+		if !strings.HasPrefix(alias, sweepPrefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// sweepableKey reports whether key was created by acceptance tests. The key
+// the tests use for S3 is never swept, whatever its name.
+func sweepableKey(key client.KeyListItem) bool {
+	return strings.HasPrefix(key.Name, sweepPrefix) && key.ID != os.Getenv("GARAGE_ACCESS_KEY")
+}
+
+// sweepBucketList returns the buckets created by acceptance tests.
+func sweepBucketList(ctx context.Context, c *client.Client) ([]client.Bucket, error) {
+	buckets, err := c.ListBuckets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing buckets: %w", err)
+	}
+
+	var sweepable []client.Bucket
+	for _, bucket := range buckets {
+		if sweepableBucket(bucket) {
+			sweepable = append(sweepable, bucket)
+		}
+	}
+	return sweepable, nil
+}
+
+// sweepObjects empties the buckets created by acceptance tests, through the
+// S3 API with the acceptance test access key, so that they can be deleted.
+func sweepObjects(_ string) error {
+	ctx := context.Background()
+	c, err := sweepClient()
+	if err != nil {
+		return err
+	}
+
+	accessKey := os.Getenv("GARAGE_ACCESS_KEY")
+	if os.Getenv("GARAGE_S3_ENDPOINT") == "" || accessKey == "" || os.Getenv("GARAGE_SECRET_KEY") == "" {
+		log.Printf("[WARN] Skipping object sweeper: GARAGE_S3_ENDPOINT, GARAGE_ACCESS_KEY and GARAGE_SECRET_KEY must be set to empty buckets")
+		return nil
+	}
+	s3Client := testAccS3Client()
+
+	buckets, err := sweepBucketList(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, bucket := range buckets {
+		alias := bucket.GlobalAliases[0]
+		_, err := c.AllowBucketKey(ctx, client.BucketKeyPermRequest{
+			BucketID:    bucket.ID,
+			AccessKeyID: accessKey,
+			Permissions: client.Permissions{Read: true, Write: true},
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("granting access to bucket %s: %w", alias, err))
+			continue
+		}
+
+		objects, uploads, err := purgeBucket(ctx, s3Client, alias)
+		log.Printf("[INFO] Deleted %d objects and %d multipart uploads of bucket %s", objects, uploads, alias)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("emptying bucket %s: %w", alias, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// sweepBuckets deletes the buckets created by acceptance tests. They are
+// emptied by the garage_object sweeper first.
+func sweepBuckets(_ string) error {
+	ctx := context.Background()
+	c, err := sweepClient()
+	if err != nil {
+		return err
+	}
+
+	buckets, err := sweepBucketList(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, bucket := range buckets {
+		log.Printf("[INFO] Deleting bucket %s (%s)", bucket.GlobalAliases[0], bucket.ID)
+		if err := c.DeleteBucket(ctx, client.DeleteBucketRequest{ID: bucket.ID}); err != nil {
+			errs = append(errs, fmt.Errorf("deleting bucket %s: %w", bucket.GlobalAliases[0], err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// sweepKeys deletes the access keys created by acceptance tests.
+func sweepKeys(_ string) error {
+	ctx := context.Background()
+	c, err := sweepClient()
+	if err != nil {
+		return err
+	}
+
+	keys, err := c.ListKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("listing keys: %w", err)
+	}
+
+	var errs []error
+	for _, key := range keys {
+		if !sweepableKey(key) {
+			continue
+		}
+		log.Printf("[INFO] Deleting key %s (%s)", key.Name, key.ID)
+		if err := c.DeleteKey(ctx, client.DeleteKeyRequest{ID: key.ID}); err != nil {
+			errs = append(errs, fmt.Errorf("deleting key %s: %w", key.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func TestSweepable(t *testing.T) {
+	t.Setenv("GARAGE_ACCESS_KEY", "GKtests")
+
+	buckets := []struct {
+		aliases []string
+		want    bool
+	}{
+		{[]string{"test-bucket-website"}, true},
+		{[]string{"test-a", "test-b"}, true},
+		{[]string{"test-a", "production"}, false},
+		{[]string{"production"}, false},
+		{[]string{"tests"}, false},
+		{[]string{"my-test-bucket"}, false},
+		{nil, false},
+	}
+	for _, tt := range buckets {
+		if got := sweepableBucket(client.Bucket{ID: "b", GlobalAliases: tt.aliases}); got != tt.want {
+			t.Errorf("sweepableBucket(%q) = %v, want %v", tt.aliases, got, tt.want)
+		}
+	}
+
+	keys := []struct {
+		key  client.KeyListItem
+		want bool
+	}{
+		{client.KeyListItem{ID: "GK1", Name: "test-key-basic"}, true},
+		{client.KeyListItem{ID: "GK2", Name: "ci"}, false},
+		{client.KeyListItem{ID: "GK3", Name: ""}, false},
+		{client.KeyListItem{ID: "GKtests", Name: "test-access-key"}, false},
+	}
+	for _, tt := range keys {
+		if got := sweepableKey(tt.key); got != tt.want {
+			t.Errorf("sweepableKey(%+v) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+# __generated__ by Terraform
+# Please review these resources and move them into your main configuration files.
+
+# __generated__ by Terraform from "test-generate-config/docs/index.html"
+resource "garage_object" "imported" {
+  adopt_existing      = false
+  bucket              = "test-generate-config"
+  cache_control       = "max-age=300"
+  content             = null # sensitive
+  content_base64      = null # sensitive
+  content_disposition = null
+  content_encoding    = null
+  content_language    = null
+  content_md5         = null
+  content_type        = "text/html"
+  content_wo_version  = null
+  key                 = "docs/index.html"
+  metadata = {
+    owner = "docs"
+  }
+  override            = null
+  overwrite           = true
+  retain_on_delete    = false
+  source              = null
+  source_url          = null
+  source_url_checksum = null
+  website_redirect    = null
+}
+
+# __generated__ by Terraform from "test-generate-config"
+resource "garage_bucket" "imported" {
+  global_alias           = "test-generate-config"
+  max_objects            = null
+  max_size               = null
+  website_enabled        = true
+  website_error_document = null
+  website_index_document = "index.html"
+}
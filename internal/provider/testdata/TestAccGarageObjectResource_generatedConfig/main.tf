@@ -0,0 +1,41 @@
+variable "admin_endpoint" {
+  type = string
+}
+
+variable "s3_endpoint" {
+  type = string
+}
+
+variable "token" {
+  type      = string
+  sensitive = true
+}
+
+variable "access_key" {
+  type = string
+}
+
+variable "secret_key" {
+  type      = string
+  sensitive = true
+}
+
+provider "garage" {
+  endpoints {
+    admin = var.admin_endpoint
+    s3    = var.s3_endpoint
+  }
+  token      = var.token
+  access_key = var.access_key
+  secret_key = var.secret_key
+}
+
+import {
+  to = garage_bucket.imported
+  id = "test-generate-config"
+}
+
+import {
+  to = garage_object.imported
+  id = "test-generate-config/docs/index.html"
+}
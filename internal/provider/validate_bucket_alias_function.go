@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &ValidateBucketAliasFunction{}
+
+func NewValidateBucketAliasFunction() function.Function {
+	return &ValidateBucketAliasFunction{}
+}
+
+// ValidateBucketAliasFunction defines the function implementation.
+type ValidateBucketAliasFunction struct{}
+
+func (f *ValidateBucketAliasFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "validate_bucket_alias"
+}
+
+func (f *ValidateBucketAliasFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Returns a global bucket alias, or fails with the rule it breaks",
+		MarkdownDescription: "Returns the alias unchanged when it is accepted as `global_alias` of a `garage_bucket`, " +
+			"and fails with the rule it breaks otherwise, e.g. while computing aliases in locals. " +
+			"The rules are those of `is_valid_bucket_alias`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "alias",
+				MarkdownDescription: "The alias to validate.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ValidateBucketAliasFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var alias string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &alias))
+	if resp.Error != nil {
+		return
+	}
+
+	if err := validators.ValidateBucketAlias(alias); err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid bucket alias %q: %s", alias, err))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, alias))
+}
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccValidateBucketAliasFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::garage::validate_bucket_alias("team-assets")
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.StringExact("team-assets")),
+				},
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::garage::validate_bucket_alias("team-assets-")
+}
+`,
+				ExpectError: regexp.MustCompile(`does not end with a letter or digit`),
+			},
+		},
+	})
+}
+
+func TestValidateBucketAliasFunction_run(t *testing.T) {
+	f := NewValidateBucketAliasFunction()
+
+	resp := testFunctionRun(t, f, types.StringValue("team-assets"))
+	if resp.Error != nil || !resp.Result.Value().Equal(types.StringValue("team-assets")) {
+		t.Errorf("Expected team-assets, got %s and %v", resp.Result.Value(), resp.Error)
+	}
+
+	tests := map[string]string{
+		"ab":             "shorter than 3 characters",
+		"team_assets":    `contains '_'`,
+		".team":          "does not start with a letter or digit",
+		"team-":          "does not end with a letter or digit",
+		"192.168.0.1":    "formatted as an IP address",
+		"xn--team":       "reserved prefix xn--",
+		"assets-s3alias": "reserved suffix -s3alias",
+	}
+
+	for alias, rule := range tests {
+		resp := testFunctionRun(t, f, types.StringValue(alias))
+		if resp.Error == nil || resp.Error.FunctionArgument == nil || *resp.Error.FunctionArgument != 0 ||
+			!strings.Contains(resp.Error.Text, rule) || !strings.Contains(resp.Error.Text, alias) {
+			t.Errorf("validate_bucket_alias(%q): expected an error naming the alias and %q, got %v", alias, rule, resp.Error)
+		}
+	}
+}
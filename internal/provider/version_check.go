@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+// garageVersion is a Garage release version, as major, minor and patch.
+type garageVersion [3]int
+
+// garageV2 is the release introducing the v2 admin API, admin tokens and
+// the layout history.
+var garageV2 = garageVersion{2, 0, 0}
+
+// garageVersionPattern matches the release in the versions reported by
+// Garage, e.g. "v2.0.0", "cargo:1.1.0" or "git:v2.1.0-12-gabcdef".
+var garageVersionPattern = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// parseGarageVersion extracts the release from a version reported by Garage.
+// It returns false for development builds reporting no release.
+func parseGarageVersion(s string) (garageVersion, bool) {
+	match := garageVersionPattern.FindStringSubmatch(s)
+	if match == nil {
+		return garageVersion{}, false
+	}
+
+	var v garageVersion
+	for i, part := range match[1:] {
+		if part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return garageVersion{}, false
+		}
+		v[i] = n
+	}
+
+	return v, true
+}
+
+// less reports whether v is an older release than other.
+func (v garageVersion) less(other garageVersion) bool {
+	for i := range v {
+		if v[i] != other[i] {
+			return v[i] < other[i]
+		}
+	}
+	return false
+}
+
+func (v garageVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+// garageCapability is a feature of the provider that needs a more recent
+// Garage release than the rest of the provider.
+type garageCapability struct {
+	// feature names the feature in diagnostics, e.g. "Admin tokens".
+	feature string
+	minimum garageVersion
+}
+
+// The features of the provider gated on the version of the server.
+var (
+	// capabilityAdminTokens covers the admin token resources and data
+	// sources.
+	capabilityAdminTokens = garageCapability{feature: "Admin tokens", minimum: garageV2}
+	// capabilityLayoutParameters covers the zone redundancy set with the
+	// parameters attribute of garage_cluster_layout.
+	capabilityLayoutParameters = garageCapability{feature: "Attribute `parameters`", minimum: garageVersion{1, 0, 0}}
+)
+
+// supportedBy reports whether a server reporting version supports c.
+// Servers whose release cannot be determined, such as development builds,
+// are given the benefit of the doubt.
+func (c garageCapability) supportedBy(version string) bool {
+	release, ok := parseGarageVersion(version)
+	return !ok || !release.less(c.minimum)
+}
+
+// requireGarageCapability fails when the server is too old for capability,
+// so that it is reported as unsupported rather than with the raw error of
+// the missing endpoint. The server version is cached by the client, which
+// is shared by the resources of a single operation, so it is only requested
+// once. Servers whose version cannot be detected are given the benefit of
+// the doubt.
+func requireGarageCapability(ctx context.Context, c *client.Client, capability garageCapability) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	info, err := c.GetNodeInfo(ctx)
+	if err != nil {
+		tflog.Warn(ctx, "Unable to detect the Garage version", map[string]interface{}{
+			"feature": capability.feature,
+			"error":   err.Error(),
+		})
+		return diags
+	}
+
+	if capability.supportedBy(info.GarageVersion) {
+		return diags
+	}
+
+	diags.AddError(
+		"Unsupported Garage Version",
+		fmt.Sprintf("%s requires Garage >= %s, server reports %s.", capability.feature, capability.minimum, info.GarageVersion),
+	)
+	return diags
+}
@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+func TestParseGarageVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		expected garageVersion
+		ok       bool
+	}{
+		{"release", "v2.0.0", garageVersion{2, 0, 0}, true},
+		{"cargo", "cargo:1.1.0", garageVersion{1, 1, 0}, true},
+		{"git describe", "git:v2.1.0-12-gabcdef", garageVersion{2, 1, 0}, true},
+		{"no patch", "v1.0", garageVersion{1, 0, 0}, true},
+		{"development build", "git:abcdef", garageVersion{}, false},
+		{"empty", "", garageVersion{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, ok := parseGarageVersion(tt.version)
+			if ok != tt.ok || version != tt.expected {
+				t.Errorf("parseGarageVersion(%q) = %v, %t, expected %v, %t", tt.version, version, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestGarageCapabilities(t *testing.T) {
+	// Versions as reported by the servers of each release line
+	versions := []string{"v0.9.4", "cargo:1.0.1", "v1.1.0", "v2.0.0", "git:v2.1.0-12-gabcdef", "git:abcdef"}
+
+	tests := []struct {
+		capability garageCapability
+		supported  []bool
+	}{
+		{capabilityAdminTokens, []bool{false, false, false, true, true, true}},
+		{capabilityLayoutParameters, []bool{false, true, true, true, true, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.capability.feature, func(t *testing.T) {
+			for i, version := range versions {
+				if got := tt.capability.supportedBy(version); got != tt.supported[i] {
+					t.Errorf("Expected %s supported by %s to be %t, got %t", tt.capability.feature, version, tt.supported[i], got)
+				}
+			}
+		})
+	}
+}
+
+func TestRequireGarageCapability(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		wantErr bool
+	}{
+		{"older", "v1.1.0", true},
+		{"same", "v2.0.0", false},
+		{"newer", "v2.1.0", false},
+		{"development build", "git:abcdef", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprintf(w, `{"success": {"n1": {"nodeId": "n1", "garageVersion": %q}}, "error": {}}`, tt.version)
+			}))
+			defer server.Close()
+
+			c := client.NewClient(server.URL, "test-token")
+			diags := requireGarageCapability(context.Background(), c, capabilityAdminTokens)
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("Expected error %t, got %v", tt.wantErr, diags)
+			}
+			if tt.wantErr {
+				expected := "Admin tokens requires Garage >= 2.0.0, server reports " + tt.version + "."
+				if detail := diags.Errors()[0].Detail(); !strings.Contains(detail, expected) {
+					t.Errorf("Expected %q, got %q", expected, detail)
+				}
+			}
+
+			// The version is detected once per operation
+			_ = requireGarageCapability(context.Background(), c, capabilityAdminTokens)
+			if calls != 1 {
+				t.Errorf("Expected a single status call, got %d", calls)
+			}
+		})
+	}
+}
+
+func TestRequireGarageCapability_unknown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	// The feature itself reports the error when the version is unknown
+	diags := requireGarageCapability(context.Background(), client.NewClient(server.URL, "test-token"), capabilityAdminTokens)
+	if diags.HasError() {
+		t.Errorf("Expected no error, got %v", diags)
+	}
+}
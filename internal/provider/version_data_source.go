@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &VersionDataSource{}
+
+func NewVersionDataSource() datasource.DataSource {
+	return &VersionDataSource{}
+}
+
+// VersionDataSource defines the data source implementation.
+type VersionDataSource struct {
+	client *client.Client
+}
+
+// VersionDataSourceModel describes the data source data model.
+type VersionDataSourceModel struct {
+	Version     types.String `tfsdk:"version"`
+	RustVersion types.String `tfsdk:"rust_version"`
+	Features    types.List   `tfsdk:"features"`
+}
+
+func (d *VersionDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_version"
+}
+
+func (d *VersionDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Retrieves the version of the Garage server answering the admin API.",
+
+		Attributes: map[string]schema.Attribute{
+			"version": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The Garage version reported by the server, e.g. `v2.0.0`.",
+			},
+			"rust_version": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The version of Rust the server was built with.",
+			},
+			"features": schema.ListAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The build features of the server, e.g. `k2v` or `lmdb`.",
+			},
+		},
+	}
+}
+
+func (d *VersionDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*GarageProviderModel)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GarageProviderModel, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	var diags diag.Diagnostics
+	d.client, diags = providerData.configureAdminClient()
+	resp.Diagnostics.Append(diags...)
+}
+
+func (d *VersionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data VersionDataSourceModel
+
+	tflog.Debug(ctx, "Reading version data source")
+
+	info, err := d.client.GetNodeInfo(ctx)
+	if err != nil {
+		resp.Diagnostics.Append(diagFromClientError("Unable to read node information", err))
+		return
+	}
+
+	features := info.GarageFeatures
+	if features == nil {
+		features = []string{}
+	}
+
+	data.Version = types.StringValue(info.GarageVersion)
+	data.RustVersion = types.StringValue(info.RustVersion)
+	featuresValue, diags := types.ListValueFrom(ctx, types.StringType, features)
+	resp.Diagnostics.Append(diags...)
+	data.Features = featuresValue
+
+	tflog.Trace(ctx, "Read version data source")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
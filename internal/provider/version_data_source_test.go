@@ -0,0 +1,47 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/client"
+)
+
+func TestVersionDataSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/GetNodeInfo" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success": {"n1": {"nodeId": "n1", "garageVersion": "v2.1.0", "garageFeatures": ["k2v", "lmdb"],
+			"rustVersion": "1.86.0", "dbEngine": "LMDB"}}, "error": {}}`))
+	}))
+	defer server.Close()
+
+	d := &VersionDataSource{client: client.NewClient(server.URL, "test-token")}
+	resp := testDataSourceRead(t, d, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data VersionDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+	var features []string
+	resp.Diagnostics.Append(data.Features.ElementsAs(context.Background(), &features, false)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if data.Version.ValueString() != "v2.1.0" || data.RustVersion.ValueString() != "1.86.0" {
+		t.Errorf("Unexpected versions %+v", data)
+	}
+	if len(features) != 2 || features[0] != "k2v" || features[1] != "lmdb" {
+		t.Errorf("Unexpected features %v", features)
+	}
+}
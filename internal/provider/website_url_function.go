@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"github.com/DarkMukke/terraform-provider-garage/internal/validators"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &WebsiteURLFunction{}
+
+func NewWebsiteURLFunction() function.Function {
+	return &WebsiteURLFunction{}
+}
+
+// WebsiteURLFunction defines the function implementation.
+type WebsiteURLFunction struct{}
+
+func (f *WebsiteURLFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "website_url"
+}
+
+func (f *WebsiteURLFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds the website URL of a bucket, or of an object of it",
+		MarkdownDescription: "Builds `https://<alias>.<root_domain>/<key>`, the URL at which the website endpoint of Garage serves a website-enabled bucket. " +
+			"The alias must be a valid global bucket alias, the domain is lowercased and each segment of the key is URL-escaped, " +
+			"so keys with spaces, `?` or `#` are served as is. Only the arguments are used, so the URL is known at plan time " +
+			"as soon as they are, even when the bucket is not created yet.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "alias",
+				MarkdownDescription: "The global alias of the bucket.",
+			},
+			function.StringParameter{
+				Name: "root_domain",
+				MarkdownDescription: "The `root_domain` of the `[s3_web]` section of the Garage configuration, e.g. `web.example.com`. " +
+					"A leading dot, as in the Garage configuration, is ignored.",
+			},
+		},
+		VariadicParameter: function.StringParameter{
+			Name:                "key",
+			MarkdownDescription: "The key of an object, e.g. `docs/getting started.html`. Default: the root of the website. At most one key can be given.",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *WebsiteURLFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var alias, rootDomain string
+	var keys []string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &alias, &rootDomain, &keys))
+	if resp.Error != nil {
+		return
+	}
+
+	if err := validators.ValidateBucketAlias(alias); err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid bucket alias %q: %s", alias, err))
+		return
+	}
+
+	domain, err := websiteRootDomain(rootDomain)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+
+	var key string
+	switch {
+	case len(keys) > 1:
+		resp.Error = function.NewArgumentFuncError(2, fmt.Sprintf("invalid key: expected at most one key, got %d", len(keys)))
+		return
+	case len(keys) == 1 && keys[0] != "":
+		key = keys[0]
+		if err := validators.ValidateObjectKey(key); err != nil {
+			resp.Error = function.NewArgumentFuncError(2, fmt.Sprintf("invalid key %q: %s", key, err))
+			return
+		}
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, websiteURL(alias, domain, key)))
+}
+
+// websiteRootDomain returns rootDomain lowercased and without the leading
+// dot of the Garage configuration, or an error when it is not a bare domain.
+func websiteRootDomain(rootDomain string) (string, error) {
+	domain := strings.ToLower(strings.TrimPrefix(rootDomain, "."))
+	switch {
+	case domain == "":
+		return "", fmt.Errorf("invalid root domain %q: expected a domain such as web.example.com", rootDomain)
+	case strings.Contains(domain, "://"):
+		return "", fmt.Errorf("invalid root domain %q: expected a domain without scheme", rootDomain)
+	case strings.ContainsAny(domain, "/?# \t"):
+		return "", fmt.Errorf("invalid root domain %q: expected a domain without path or spaces", rootDomain)
+	}
+	return domain, nil
+}
+
+// websiteURL returns the URL of key in the website of the bucket alias
+// served under domain, escaping key but not its slashes.
+func websiteURL(alias, domain, key string) string {
+	return "https://" + alias + "." + domain + "/" + escapeObjectKey(key)
+}
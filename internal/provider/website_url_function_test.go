@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccWebsiteURLFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "root" {
+  value = provider::garage::website_url("my-website", ".Web.Example.com")
+}
+
+output "key" {
+  value = provider::garage::website_url("my-website", "web.example.com", "docs/getting started.html")
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("root", knownvalue.StringExact("https://my-website.web.example.com/")),
+					statecheck.ExpectKnownOutputValue("key", knownvalue.StringExact("https://my-website.web.example.com/docs/getting%20started.html")),
+				},
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::garage::website_url("My_Website", "web.example.com")
+}
+`,
+				ExpectError: regexp.MustCompile(`invalid bucket alias "My_Website"`),
+			},
+		},
+	})
+}
+
+func TestWebsiteURL(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"", "https://assets.web.example.com/"},
+		{"index.html", "https://assets.web.example.com/index.html"},
+		{"docs/", "https://assets.web.example.com/docs/"},
+		{"docs/getting started.html", "https://assets.web.example.com/docs/getting%20started.html"},
+		{"a?b#c.html", "https://assets.web.example.com/a%3Fb%23c.html"},
+		{"100%/ünïcode.txt", "https://assets.web.example.com/100%25/%C3%BCn%C3%AFcode.txt"},
+		{"a//b", "https://assets.web.example.com/a//b"},
+	}
+
+	for _, test := range tests {
+		if got := websiteURL("assets", "web.example.com", test.key); got != test.expected {
+			t.Errorf("websiteURL(%q): expected %q, got %q", test.key, test.expected, got)
+		}
+	}
+}
+
+func TestWebsiteURLFunction_run(t *testing.T) {
+	f := NewWebsiteURLFunction()
+
+	run := func(alias, rootDomain string, keys ...string) (string, *int64, string) {
+		keyTypes := make([]attr.Type, 0, len(keys))
+		keyValues := make([]attr.Value, 0, len(keys))
+		for _, key := range keys {
+			keyTypes = append(keyTypes, types.StringType)
+			keyValues = append(keyValues, types.StringValue(key))
+		}
+		resp := testFunctionRun(t, f, types.StringValue(alias), types.StringValue(rootDomain), types.TupleValueMust(keyTypes, keyValues))
+		if resp.Error != nil {
+			return "", resp.Error.FunctionArgument, resp.Error.Text
+		}
+		return resp.Result.Value().(types.String).ValueString(), nil, ""
+	}
+
+	if got, _, text := run("assets", ".WEB.example.com"); got != "https://assets.web.example.com/" {
+		t.Errorf("Expected the root URL with the domain lowercased, got %q and %q", got, text)
+	}
+	if got, _, text := run("assets", "web.example.com", "css/site.css"); got != "https://assets.web.example.com/css/site.css" {
+		t.Errorf("Expected the URL of the key, got %q and %q", got, text)
+	}
+
+	tests := []struct {
+		alias, rootDomain string
+		keys              []string
+		argument          int64
+		error             string
+	}{
+		{"a", "web.example.com", nil, 0, `invalid bucket alias "a"`},
+		{"assets", "", nil, 1, "expected a domain"},
+		{"assets", "https://web.example.com", nil, 1, "without scheme"},
+		{"assets", "web.example.com/site", nil, 1, "without path"},
+		{"assets", "web.example.com", []string{"/index.html"}, 2, `invalid key "/index.html"`},
+		{"assets", "web.example.com", []string{"a", "b"}, 2, "at most one key"},
+	}
+
+	for _, test := range tests {
+		_, argument, text := run(test.alias, test.rootDomain, test.keys...)
+		if argument == nil || *argument != test.argument || !strings.Contains(text, test.error) {
+			t.Errorf("website_url(%q, %q, %q): expected an error on argument %d containing %q, got %v and %q", test.alias, test.rootDomain, test.keys, test.argument, test.error, argument, text)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// accessKeyIDPattern matches Garage access key IDs: "GK" followed by 24
+// lowercase hexadecimal characters.
+var accessKeyIDPattern = regexp.MustCompile(`^GK[0-9a-f]{24}$`)
+
+var _ validator.String = accessKeyIDValidator{}
+
+type accessKeyIDValidator struct{}
+
+// AccessKeyID returns a validator which ensures that a string is a well-formed
+// Garage access key ID. Null and unknown values are skipped.
+func AccessKeyID() validator.String {
+	return accessKeyIDValidator{}
+}
+
+func (v accessKeyIDValidator) Description(_ context.Context) string {
+	return "value must be a Garage access key ID (GK followed by 24 hexadecimal characters)"
+}
+
+func (v accessKeyIDValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v accessKeyIDValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if !IsAccessKeyID(value) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Access Key ID",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value),
+		)
+	}
+}
+
+// IsAccessKeyID reports whether s is a well-formed Garage access key ID.
+func IsAccessKeyID(s string) bool {
+	return accessKeyIDPattern.MatchString(s)
+}
@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestAccessKeyID(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     types.String
+		expectErr bool
+	}{
+		{name: "valid", value: types.StringValue("GK31c2f218a2e44f485b94239e")},
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+		{name: "missing prefix", value: types.StringValue("31c2f218a2e44f485b94239e"), expectErr: true},
+		{name: "lowercase prefix", value: types.StringValue("gk31c2f218a2e44f485b94239e"), expectErr: true},
+		{name: "too short", value: types.StringValue("GK31c2f218"), expectErr: true},
+		{name: "too long", value: types.StringValue("GK31c2f218a2e44f485b94239e00"), expectErr: true},
+		{name: "non hex", value: types.StringValue("GK31c2f218a2e44f485b94239z"), expectErr: true},
+		{name: "uppercase hex", value: types.StringValue("GK31C2F218A2E44F485B94239E"), expectErr: true},
+		{name: "empty", value: types.StringValue(""), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("access_key_id"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.StringResponse{}
+
+			AccessKeyID().ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tt.expectErr {
+				t.Errorf("Expected error %t, got diagnostics: %v", tt.expectErr, resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// adminAPIEndpoints are the names of the endpoints of the Garage v2 admin API
+// that a token scope can list, including the special Metrics scope of the
+// /metrics endpoint.
+var adminAPIEndpoints = []string{
+	// Cluster
+	"GetClusterHealth", "GetClusterStatus", "GetClusterStatistics", "ConnectClusterNodes",
+	// Admin tokens
+	"ListAdminTokens", "GetAdminTokenInfo", "GetCurrentAdminTokenInfo", "CreateAdminToken", "UpdateAdminToken", "DeleteAdminToken",
+	// Layout
+	"GetClusterLayout", "GetClusterLayoutHistory", "UpdateClusterLayout", "PreviewClusterLayoutChanges",
+	"ApplyClusterLayout", "RevertClusterLayout", "ClusterLayoutSkipDeadNodes",
+	// Access keys
+	"ListKeys", "GetKeyInfo", "CreateKey", "ImportKey", "UpdateKey", "DeleteKey",
+	// Buckets
+	"ListBuckets", "GetBucketInfo", "CreateBucket", "UpdateBucket", "DeleteBucket",
+	"CleanupIncompleteUploads", "InspectObject", "AllowBucketKey", "DenyBucketKey",
+	"AddBucketAlias", "RemoveBucketAlias",
+	// Nodes
+	"GetNodeInfo", "GetNodeStatistics", "CreateMetadataSnapshot", "LaunchRepairOperation",
+	"ListWorkers", "GetWorkerInfo", "GetWorkerVariable", "SetWorkerVariable",
+	"ListBlockErrors", "GetBlockInfo", "RetryBlockResync", "PurgeBlocks",
+	// Special endpoints
+	"Metrics", "CheckDomain", "Health",
+}
+
+var _ validator.String = adminTokenScopeValidator{}
+
+type adminTokenScopeValidator struct{}
+
+// AdminTokenScope returns a validator which ensures that a string is an
+// entry of an admin token scope: the name of an admin API endpoint, or "*"
+// for all of them. Names this provider does not know only produce a warning,
+// as newer servers may have more endpoints. Null and unknown values are
+// skipped.
+func AdminTokenScope() validator.String {
+	return adminTokenScopeValidator{}
+}
+
+func (v adminTokenScopeValidator) Description(_ context.Context) string {
+	return `value must be the name of an admin API endpoint, such as "ListBuckets", or "*"`
+}
+
+func (v adminTokenScopeValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v adminTokenScopeValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if value == "*" || slices.Contains(adminAPIEndpoints, value) {
+		return
+	}
+
+	if value == "" {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Admin Token Scope",
+			fmt.Sprintf("Attribute %s %s, got an empty string", req.Path, v.Description(ctx)),
+		)
+		return
+	}
+
+	resp.Diagnostics.AddAttributeWarning(
+		req.Path,
+		"Unknown Admin API Endpoint",
+		fmt.Sprintf("%q is not an admin API endpoint known to this provider. It is sent as is in case the server supports it; check its spelling otherwise.", value),
+	)
+}
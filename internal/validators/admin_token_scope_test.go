@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestAdminTokenScope(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         types.String
+		expectErr     bool
+		expectWarning bool
+	}{
+		{name: "all endpoints", value: types.StringValue("*")},
+		{name: "endpoint", value: types.StringValue("ListBuckets")},
+		{name: "metrics", value: types.StringValue("Metrics")},
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+		{name: "future endpoint", value: types.StringValue("ListBucketsV3"), expectWarning: true},
+		{name: "wrong case", value: types.StringValue("listbuckets"), expectWarning: true},
+		{name: "empty", value: types.StringValue(""), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("scope"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.StringResponse{}
+
+			AdminTokenScope().ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tt.expectErr {
+				t.Errorf("Expected error %t, got diagnostics: %v", tt.expectErr, resp.Diagnostics)
+			}
+			if got := resp.Diagnostics.WarningsCount() > 0; got != tt.expectWarning {
+				t.Errorf("Expected warning %t, got diagnostics: %v", tt.expectWarning, resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ConfigValidator = atLeastOneTrueValidator{}
+var _ validator.Object = atLeastOneTrueAttributeValidator{}
+
+type atLeastOneTrueValidator struct {
+	paths []path.Path
+}
+
+// AtLeastOneTrue returns a resource config validator which ensures that at
+// least one of the given boolean attributes is set to true. Unset attributes
+// count as false. Validation is skipped while any of them is unknown.
+func AtLeastOneTrue(paths ...path.Path) resource.ConfigValidator {
+	return atLeastOneTrueValidator{paths: paths}
+}
+
+func (v atLeastOneTrueValidator) Description(_ context.Context) string {
+	names := make([]string, 0, len(v.paths))
+	for _, p := range v.paths {
+		names = append(names, p.String())
+	}
+	return fmt.Sprintf("at least one of %s must be true", strings.Join(names, ", "))
+}
+
+func (v atLeastOneTrueValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v atLeastOneTrueValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	resp.Diagnostics.Append(v.validate(ctx, req.Config)...)
+}
+
+func (v atLeastOneTrueValidator) validate(ctx context.Context, config tfsdk.Config) (diags diag.Diagnostics) {
+	for _, p := range v.paths {
+		var value types.Bool
+		diags.Append(config.GetAttribute(ctx, p, &value)...)
+		if diags.HasError() {
+			return diags
+		}
+
+		if value.IsUnknown() {
+			return diags
+		}
+
+		if value.ValueBool() {
+			return diags
+		}
+	}
+
+	diags.AddError(
+		"Invalid Attribute Combination",
+		fmt.Sprintf("The configuration is invalid: %s.", v.Description(ctx)),
+	)
+
+	return diags
+}
+
+type atLeastOneTrueAttributeValidator struct {
+	names []string
+}
+
+// AtLeastOneTrueAttribute returns an object validator which ensures that at
+// least one of the named boolean attributes of the object is set to true. It
+// is the nested-object counterpart of AtLeastOneTrue.
+func AtLeastOneTrueAttribute(names ...string) validator.Object {
+	return atLeastOneTrueAttributeValidator{names: names}
+}
+
+func (v atLeastOneTrueAttributeValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("at least one of %s must be true", strings.Join(v.names, ", "))
+}
+
+func (v atLeastOneTrueAttributeValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v atLeastOneTrueAttributeValidator) ValidateObject(ctx context.Context, req validator.ObjectRequest, resp *validator.ObjectResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	attrs := req.ConfigValue.Attributes()
+	for _, name := range v.names {
+		value, ok := attrs[name].(types.Bool)
+		if !ok {
+			continue
+		}
+
+		if value.IsUnknown() || value.ValueBool() {
+			return
+		}
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid Attribute Combination",
+		fmt.Sprintf("Attribute %s is invalid: %s.", req.Path, v.Description(ctx)),
+	)
+}
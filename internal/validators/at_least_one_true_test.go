@@ -0,0 +1,152 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestAtLeastOneTrue(t *testing.T) {
+	testSchema := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"read":  schema.BoolAttribute{Optional: true},
+			"write": schema.BoolAttribute{Optional: true},
+			"owner": schema.BoolAttribute{Optional: true},
+		},
+	}
+	objectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"read":  tftypes.Bool,
+		"write": tftypes.Bool,
+		"owner": tftypes.Bool,
+	}}
+
+	tests := []struct {
+		name      string
+		values    map[string]tftypes.Value
+		expectErr bool
+	}{
+		{
+			name: "one true",
+			values: map[string]tftypes.Value{
+				"read":  tftypes.NewValue(tftypes.Bool, false),
+				"write": tftypes.NewValue(tftypes.Bool, true),
+				"owner": tftypes.NewValue(tftypes.Bool, nil),
+			},
+		},
+		{
+			name: "all false",
+			values: map[string]tftypes.Value{
+				"read":  tftypes.NewValue(tftypes.Bool, false),
+				"write": tftypes.NewValue(tftypes.Bool, false),
+				"owner": tftypes.NewValue(tftypes.Bool, false),
+			},
+			expectErr: true,
+		},
+		{
+			name: "all null",
+			values: map[string]tftypes.Value{
+				"read":  tftypes.NewValue(tftypes.Bool, nil),
+				"write": tftypes.NewValue(tftypes.Bool, nil),
+				"owner": tftypes.NewValue(tftypes.Bool, nil),
+			},
+			expectErr: true,
+		},
+		{
+			name: "unknown skips validation",
+			values: map[string]tftypes.Value{
+				"read":  tftypes.NewValue(tftypes.Bool, false),
+				"write": tftypes.NewValue(tftypes.Bool, tftypes.UnknownValue),
+				"owner": tftypes.NewValue(tftypes.Bool, false),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := resource.ValidateConfigRequest{
+				Config: tfsdk.Config{
+					Schema: testSchema,
+					Raw:    tftypes.NewValue(objectType, tt.values),
+				},
+			}
+			resp := &resource.ValidateConfigResponse{}
+
+			AtLeastOneTrue(path.Root("read"), path.Root("write"), path.Root("owner")).ValidateResource(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tt.expectErr {
+				t.Errorf("Expected error %t, got diagnostics: %v", tt.expectErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestAtLeastOneTrueAttribute(t *testing.T) {
+	attrTypes := map[string]attr.Type{
+		"bucket_id": types.StringType,
+		"read":      types.BoolType,
+		"write":     types.BoolType,
+	}
+
+	tests := []struct {
+		name      string
+		value     types.Object
+		expectErr bool
+	}{
+		{
+			name: "one true",
+			value: types.ObjectValueMust(attrTypes, map[string]attr.Value{
+				"bucket_id": types.StringValue("bucket"),
+				"read":      types.BoolValue(true),
+				"write":     types.BoolNull(),
+			}),
+		},
+		{
+			name: "all false or null",
+			value: types.ObjectValueMust(attrTypes, map[string]attr.Value{
+				"bucket_id": types.StringValue("bucket"),
+				"read":      types.BoolValue(false),
+				"write":     types.BoolNull(),
+			}),
+			expectErr: true,
+		},
+		{
+			name: "unknown skips validation",
+			value: types.ObjectValueMust(attrTypes, map[string]attr.Value{
+				"bucket_id": types.StringValue("bucket"),
+				"read":      types.BoolValue(false),
+				"write":     types.BoolUnknown(),
+			}),
+		},
+		{
+			name:  "null object",
+			value: types.ObjectNull(attrTypes),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.ObjectRequest{
+				Path:        path.Root("grant"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.ObjectResponse{}
+
+			AtLeastOneTrueAttribute("read", "write").ValidateObject(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tt.expectErr {
+				t.Errorf("Expected error %t, got diagnostics: %v", tt.expectErr, resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = base64Validator{}
+
+type base64Validator struct{}
+
+// Base64 returns a validator which ensures that a string uses the standard
+// base64 encoding with padding, as produced by Terraform's base64encode and
+// filebase64 functions. Null and unknown values are skipped.
+func Base64() validator.String {
+	return base64Validator{}
+}
+
+func (v base64Validator) Description(_ context.Context) string {
+	return "value must be standard base64 encoded"
+}
+
+func (v base64Validator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v base64Validator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := base64.StdEncoding.DecodeString(req.ConfigValue.ValueString()); err != nil {
+		// The value may be large and sensitive, only report the error
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Base64 Value",
+			fmt.Sprintf("Attribute %s %s: %s", req.Path, v.Description(ctx), err),
+		)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestBase64(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     types.String
+		expectErr bool
+	}{
+		{name: "valid", value: types.StringValue("iVBORw0KGgo=")},
+		{name: "empty", value: types.StringValue("")},
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+		{name: "missing padding", value: types.StringValue("iVBORw0KGgo"), expectErr: true},
+		{name: "url encoding", value: types.StringValue("_-8="), expectErr: true},
+		{name: "not base64", value: types.StringValue("not base64!"), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("content_base64"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.StringResponse{}
+
+			Base64().ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tt.expectErr {
+				t.Errorf("Expected error %t, got diagnostics: %v", tt.expectErr, resp.Diagnostics)
+			}
+		})
+	}
+}
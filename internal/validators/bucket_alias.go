@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+const (
+	// MinBucketAliasLength is the minimum length of a global bucket alias.
+	MinBucketAliasLength = 3
+	// MaxBucketAliasLength is the maximum length of a global bucket alias.
+	MaxBucketAliasLength = 63
+)
+
+var _ validator.String = bucketAliasValidator{}
+
+type bucketAliasValidator struct{}
+
+// BucketAlias returns a validator which ensures that a string is a global
+// bucket alias accepted by Garage, see ValidateBucketAlias. Null and unknown
+// values are skipped.
+func BucketAlias() validator.String {
+	return bucketAliasValidator{}
+}
+
+func (v bucketAliasValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be a bucket name of %d to %d lowercase letters, digits, dashes and dots, starting and ending with a letter or digit",
+		MinBucketAliasLength, MaxBucketAliasLength)
+}
+
+func (v bucketAliasValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v bucketAliasValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := ValidateBucketAlias(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Bucket Alias",
+			fmt.Sprintf("Attribute %s %s: %s", req.Path, v.Description(ctx), err),
+		)
+	}
+}
+
+// ValidateBucketAlias returns an error describing the first rule of Garage
+// for global bucket aliases that alias breaks, or nil. These are the S3
+// bucket naming rules: 3 to 63 lowercase letters, digits, dashes and dots,
+// starting and ending with a letter or digit, not formatted as an IP
+// address, not starting with "xn--" and not ending with "-s3alias".
+func ValidateBucketAlias(alias string) error {
+	switch {
+	case alias == "":
+		return errors.New("alias is empty")
+	case len(alias) < MinBucketAliasLength:
+		return fmt.Errorf("alias is shorter than %d characters", MinBucketAliasLength)
+	case len(alias) > MaxBucketAliasLength:
+		return fmt.Errorf("alias is longer than %d characters", MaxBucketAliasLength)
+	}
+
+	for _, c := range alias {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '.' {
+			return fmt.Errorf("alias contains %q, only lowercase letters, digits, dashes and dots are allowed", c)
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(alias, "-") || strings.HasPrefix(alias, "."):
+		return errors.New("alias does not start with a letter or digit")
+	case strings.HasSuffix(alias, "-") || strings.HasSuffix(alias, "."):
+		return errors.New("alias does not end with a letter or digit")
+	case isIPAddress(alias):
+		return errors.New("alias is formatted as an IP address")
+	case strings.HasPrefix(alias, "xn--"):
+		return errors.New("alias starts with the reserved prefix xn--")
+	case strings.HasSuffix(alias, "-s3alias"):
+		return errors.New("alias ends with the reserved suffix -s3alias")
+	}
+
+	return nil
+}
+
+// isIPAddress reports whether s is an IPv4 address. IPv6 addresses contain
+// colons, which aliases cannot.
+func isIPAddress(s string) bool {
+	_, err := netip.ParseAddr(s)
+	return err == nil
+}
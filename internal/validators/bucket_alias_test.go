@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestBucketAlias(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     types.String
+		expectErr bool
+	}{
+		{name: "valid", value: types.StringValue("team-project.assets")},
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+		{name: "invalid", value: types.StringValue("Team_Project"), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("global_alias"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.StringResponse{}
+
+			BucketAlias().ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tt.expectErr {
+				t.Errorf("Expected error %v, got %v", tt.expectErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestValidateBucketAlias(t *testing.T) {
+	tests := []struct {
+		alias string
+		error string
+	}{
+		// Length bounds
+		{alias: "abc"},
+		{alias: strings.Repeat("a", MaxBucketAliasLength)},
+		{alias: "", error: "is empty"},
+		{alias: "ab", error: "shorter than 3 characters"},
+		{alias: strings.Repeat("a", MaxBucketAliasLength+1), error: "longer than 63 characters"},
+		// Character classes
+		{alias: "team-project.v2"},
+		{alias: "0123456789"},
+		{alias: "Team", error: `contains 'T'`},
+		{alias: "team_project", error: `contains '_'`},
+		{alias: "team project", error: `contains ' '`},
+		{alias: "team/project", error: `contains '/'`},
+		{alias: "équipe", error: `contains 'é'`},
+		// Edge punctuation
+		{alias: "a-b"},
+		{alias: "a..b"},
+		{alias: "-team", error: "does not start with a letter or digit"},
+		{alias: ".team", error: "does not start with a letter or digit"},
+		{alias: "team-", error: "does not end with a letter or digit"},
+		{alias: "team.", error: "does not end with a letter or digit"},
+		// Reserved formats
+		{alias: "192.168.1.1", error: "formatted as an IP address"},
+		{alias: "192.168.1.256"},
+		{alias: "xn--team", error: "reserved prefix xn--"},
+		{alias: "team-s3alias", error: "reserved suffix -s3alias"},
+		{alias: "s3alias-team"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			err := ValidateBucketAlias(tt.alias)
+			if tt.error == "" {
+				if err != nil {
+					t.Errorf("Expected %q to be valid, got %v", tt.alias, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Errorf("Expected an error containing %q for %q, got %v", tt.error, tt.alias, err)
+			}
+		})
+	}
+}
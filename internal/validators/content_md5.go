@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = contentMD5Validator{}
+
+type contentMD5Validator struct{}
+
+// ContentMD5 returns a validator which ensures that a string is a valid
+// Content-MD5 header value: the standard base64 encoding of a 16-byte MD5
+// digest. Null and unknown values are skipped.
+func ContentMD5() validator.String {
+	return contentMD5Validator{}
+}
+
+func (v contentMD5Validator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be the base64 encoding of a %d-byte MD5 digest", md5.Size)
+}
+
+func (v contentMD5Validator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v contentMD5Validator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	raw, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(raw) != md5.Size {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Content MD5",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value),
+		)
+	}
+}
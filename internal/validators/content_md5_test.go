@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestContentMD5(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     types.String
+		expectErr bool
+	}{
+		{name: "valid", value: types.StringValue("XUFAKrxLKna5cZ2REBfFkg==")},
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+		{name: "hex digest", value: types.StringValue("5d41402abc4b2a76b9719d911017c592"), expectErr: true},
+		{name: "not base64", value: types.StringValue("not base64!"), expectErr: true},
+		{name: "wrong length", value: types.StringValue("aGVsbG8="), expectErr: true},
+		{name: "empty", value: types.StringValue(""), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("content_md5"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.StringResponse{}
+
+			ContentMD5().ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tt.expectErr {
+				t.Errorf("Expected error %t, got diagnostics: %v", tt.expectErr, resp.Diagnostics)
+			}
+		})
+	}
+}
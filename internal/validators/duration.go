@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = durationBetweenValidator{}
+
+type durationBetweenValidator struct {
+	minimum time.Duration
+	maximum time.Duration
+}
+
+// DurationBetween returns a validator which ensures that a string is a Go
+// duration (e.g. "15m") between minimum and maximum inclusive. Null and unknown
+// values are skipped.
+func DurationBetween(minimum, maximum time.Duration) validator.String {
+	return durationBetweenValidator{minimum: minimum, maximum: maximum}
+}
+
+func (v durationBetweenValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be a duration between %s and %s", v.minimum, v.maximum)
+}
+
+func (v durationBetweenValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v durationBetweenValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	d, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration",
+			fmt.Sprintf("Attribute %s %s, got %q: %s", req.Path, v.Description(ctx), req.ConfigValue.ValueString(), err),
+		)
+		return
+	}
+
+	if d < v.minimum || d > v.maximum {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration",
+			fmt.Sprintf("Attribute %s %s, got %s", req.Path, v.Description(ctx), d),
+		)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDurationBetween(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     types.String
+		expectErr bool
+	}{
+		{name: "valid", value: types.StringValue("15m")},
+		{name: "minimum", value: types.StringValue("1s")},
+		{name: "maximum", value: types.StringValue("168h")},
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+		{name: "too short", value: types.StringValue("500ms"), expectErr: true},
+		{name: "too long", value: types.StringValue("169h"), expectErr: true},
+		{name: "negative", value: types.StringValue("-1m"), expectErr: true},
+		{name: "days unit", value: types.StringValue("7d"), expectErr: true},
+		{name: "empty", value: types.StringValue(""), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("expires_in"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.StringResponse{}
+
+			DurationBetween(time.Second, 7*24*time.Hour).ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tt.expectErr {
+				t.Errorf("Expected error %t, got diagnostics: %v", tt.expectErr, resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// nodeIDPattern matches Garage node IDs: the 64 lowercase hexadecimal
+// characters of the public key of the node.
+var nodeIDPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+var _ validator.String = nodeIDValidator{}
+
+type nodeIDValidator struct{}
+
+// NodeID returns a validator which ensures that a string is a full Garage
+// node ID. Null and unknown values are skipped.
+func NodeID() validator.String {
+	return nodeIDValidator{}
+}
+
+func (v nodeIDValidator) Description(_ context.Context) string {
+	return "value must be a full Garage node ID (64 hexadecimal characters)"
+}
+
+func (v nodeIDValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v nodeIDValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if !IsNodeID(value) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Node ID",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value),
+		)
+	}
+}
+
+// IsNodeID reports whether s is a full Garage node ID.
+func IsNodeID(s string) bool {
+	return nodeIDPattern.MatchString(s)
+}
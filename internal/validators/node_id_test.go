@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNodeID(t *testing.T) {
+	nodeID := strings.Repeat("ec79480e", 8)
+
+	tests := []struct {
+		name      string
+		value     types.String
+		expectErr bool
+	}{
+		{name: "valid", value: types.StringValue(nodeID)},
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+		{name: "prefix", value: types.StringValue(nodeID[:16]), expectErr: true},
+		{name: "too long", value: types.StringValue(nodeID + "00"), expectErr: true},
+		{name: "uppercase", value: types.StringValue(strings.ToUpper(nodeID)), expectErr: true},
+		{name: "with address", value: types.StringValue(nodeID + "@10.0.0.1:3901"), expectErr: true},
+		{name: "empty", value: types.StringValue(""), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("id"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.StringResponse{}
+
+			NodeID().ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tt.expectErr {
+				t.Errorf("Expected error %t, got diagnostics: %v", tt.expectErr, resp.Diagnostics)
+			}
+		})
+	}
+}
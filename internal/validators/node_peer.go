@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = nodePeerValidator{}
+
+type nodePeerValidator struct{}
+
+// NodePeer returns a validator which ensures that a string is a Garage peer
+// address, <node_id>@<host>:<port>. Null and unknown values are skipped.
+func NodePeer() validator.String {
+	return nodePeerValidator{}
+}
+
+func (v nodePeerValidator) Description(_ context.Context) string {
+	return "value must be a Garage peer as <node_id>@<host>:<port>, with the 64 hexadecimal characters of the node ID"
+}
+
+func (v nodePeerValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v nodePeerValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if !IsNodePeer(value) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Node Peer",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value),
+		)
+	}
+}
+
+// IsNodePeer reports whether s is a well-formed Garage peer address.
+func IsNodePeer(s string) bool {
+	nodeID, addr, ok := strings.Cut(s, "@")
+	if !ok || !IsNodeID(nodeID) {
+		return false
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return false
+	}
+	number, err := strconv.Atoi(port)
+	return err == nil && number > 0 && number <= 65535
+}
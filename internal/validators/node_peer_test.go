@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNodePeer(t *testing.T) {
+	nodeID := strings.Repeat("ec79480e", 8)
+
+	tests := []struct {
+		name      string
+		value     types.String
+		expectErr bool
+	}{
+		{name: "ipv4", value: types.StringValue(nodeID + "@10.0.0.1:3901")},
+		{name: "ipv6", value: types.StringValue(nodeID + "@[fd00::1]:3901")},
+		{name: "hostname", value: types.StringValue(nodeID + "@garage-1.internal:3901")},
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+		{name: "missing node id", value: types.StringValue("10.0.0.1:3901"), expectErr: true},
+		{name: "short node id", value: types.StringValue("ec79480e@10.0.0.1:3901"), expectErr: true},
+		{name: "uppercase node id", value: types.StringValue(strings.ToUpper(nodeID) + "@10.0.0.1:3901"), expectErr: true},
+		{name: "missing port", value: types.StringValue(nodeID + "@10.0.0.1"), expectErr: true},
+		{name: "invalid port", value: types.StringValue(nodeID + "@10.0.0.1:70000"), expectErr: true},
+		{name: "missing host", value: types.StringValue(nodeID + "@:3901"), expectErr: true},
+		{name: "empty", value: types.StringValue(""), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("peers"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.StringResponse{}
+
+			NodePeer().ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tt.expectErr {
+				t.Errorf("Expected error %t, got diagnostics: %v", tt.expectErr, resp.Diagnostics)
+			}
+		})
+	}
+}
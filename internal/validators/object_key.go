@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// MaxObjectKeyLength is the maximum length of an S3 object key, in bytes.
+const MaxObjectKeyLength = 1024
+
+var _ validator.String = objectKeyValidator{}
+
+type objectKeyValidator struct{}
+
+// ObjectKey returns a validator which ensures that a string is a usable S3
+// object key: non-empty, at most 1024 bytes and without a leading slash. Keys
+// containing characters that break website URLs ("?" and "#") only raise a
+// warning. Null and unknown values are skipped.
+func ObjectKey() validator.String {
+	return objectKeyValidator{}
+}
+
+func (v objectKeyValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be a non-empty object key of at most %d bytes, not starting with /", MaxObjectKeyLength)
+}
+
+func (v objectKeyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v objectKeyValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if err := ValidateObjectKey(value); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Object Key",
+			fmt.Sprintf("Attribute %s %s: %s", req.Path, v.Description(ctx), err),
+		)
+		return
+	}
+
+	if strings.ContainsAny(value, "?#") {
+		resp.Diagnostics.AddAttributeWarning(
+			req.Path,
+			"Problematic Object Key",
+			fmt.Sprintf("Object key %q contains \"?\" or \"#\". These are valid in S3 keys but are interpreted as "+
+				"query string or fragment in URLs, so the object cannot be served by the website endpoint.", value),
+		)
+	}
+}
+
+// ValidateObjectKey returns an error describing why key is not a usable S3
+// object key, or nil.
+func ValidateObjectKey(key string) error {
+	switch {
+	case key == "":
+		return errors.New("key is empty")
+	case len(key) > MaxObjectKeyLength:
+		return fmt.Errorf("key is %d bytes long", len(key))
+	case strings.HasPrefix(key, "/"):
+		return fmt.Errorf("key %q starts with /", key)
+	}
+	return nil
+}
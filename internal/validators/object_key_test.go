@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestObjectKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      types.String
+		expectErr  bool
+		expectWarn bool
+	}{
+		{name: "valid", value: types.StringValue("assets/app.js")},
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+		{name: "max length", value: types.StringValue(strings.Repeat("a", MaxObjectKeyLength))},
+		{name: "empty", value: types.StringValue(""), expectErr: true},
+		{name: "too long", value: types.StringValue(strings.Repeat("a", MaxObjectKeyLength+1)), expectErr: true},
+		{name: "too long multibyte", value: types.StringValue(strings.Repeat("é", MaxObjectKeyLength/2+1)), expectErr: true},
+		{name: "leading slash", value: types.StringValue("/leading/slash"), expectErr: true},
+		{name: "question mark", value: types.StringValue("page?id=1"), expectWarn: true},
+		{name: "hash", value: types.StringValue("notes#1.txt"), expectWarn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("key"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.StringResponse{}
+
+			ObjectKey().ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tt.expectErr {
+				t.Errorf("Expected error %t, got diagnostics: %v", tt.expectErr, resp.Diagnostics)
+			}
+			if hasWarn := resp.Diagnostics.WarningsCount() > 0; hasWarn != tt.expectWarn {
+				t.Errorf("Expected warning %t, got diagnostics: %v", tt.expectWarn, resp.Diagnostics)
+			}
+		})
+	}
+}
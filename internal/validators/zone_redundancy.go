@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// zoneRedundancyPattern matches "maximum" or a positive integer without sign
+// nor leading zeros, the form in which Garage reports it back.
+var zoneRedundancyPattern = regexp.MustCompile(`^(maximum|[1-9][0-9]{0,8})$`)
+
+var _ validator.String = zoneRedundancyValidator{}
+
+type zoneRedundancyValidator struct{}
+
+// ZoneRedundancy returns a validator which ensures that a string is a Garage
+// zone redundancy: "maximum" or a positive integer. Null and unknown values
+// are skipped.
+func ZoneRedundancy() validator.String {
+	return zoneRedundancyValidator{}
+}
+
+func (v zoneRedundancyValidator) Description(_ context.Context) string {
+	return `value must be "maximum" or a positive integer without leading zeros`
+}
+
+func (v zoneRedundancyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v zoneRedundancyValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if !zoneRedundancyPattern.MatchString(value) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Zone Redundancy",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value),
+		)
+	}
+}
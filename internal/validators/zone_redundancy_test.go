@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestZoneRedundancy(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     types.String
+		expectErr bool
+	}{
+		{name: "maximum", value: types.StringValue("maximum")},
+		{name: "integer", value: types.StringValue("2")},
+		{name: "large integer", value: types.StringValue("10")},
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+		{name: "zero", value: types.StringValue("0"), expectErr: true},
+		{name: "negative", value: types.StringValue("-1"), expectErr: true},
+		{name: "leading zero", value: types.StringValue("02"), expectErr: true},
+		{name: "sign", value: types.StringValue("+2"), expectErr: true},
+		{name: "uppercase", value: types.StringValue("Maximum"), expectErr: true},
+		{name: "too large", value: types.StringValue("99999999999"), expectErr: true},
+		{name: "empty", value: types.StringValue(""), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("zone_redundancy"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.StringResponse{}
+
+			ZoneRedundancy().ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tt.expectErr {
+				t.Errorf("Expected error %t, got diagnostics: %v", tt.expectErr, resp.Diagnostics)
+			}
+		})
+	}
+}